@@ -0,0 +1,10 @@
+package algebraintegral
+
+type Config struct {
+	DexID              string
+	SubgraphAPI        string `json:"subgraphAPI"`
+	AllowSubgraphError bool   `json:"allowSubgraphError"`
+	// PluginFactories maps the plugin factories of the dex to the type of the plugins they deploy, a plugin whose
+	// factory is not listed is of type unknown
+	PluginFactories map[string]PluginType `json:"pluginFactories"`
+}
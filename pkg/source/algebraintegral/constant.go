@@ -0,0 +1,45 @@
+package algebraintegral
+
+import (
+	"math/big"
+)
+
+const (
+	DexTypeAlgebraIntegral = "algebra-integral"
+
+	poolMethodGlobalState = "globalState"
+	poolMethodLiquidity   = "liquidity"
+	poolMethodPlugin      = "plugin"
+	poolMethodTickSpacing = "tickSpacing"
+
+	pluginMethodFeeConfig         = "feeConfig"
+	pluginMethodGetTimepoints     = "getTimepoints"
+	pluginMethodPluginFactory     = "pluginFactory"
+	pluginMethodBaseFee           = "s_baseFee"
+	pluginMethodFeeFactors        = "s_feeFactors"
+	pluginMethodPriceChangeFactor = "s_priceChangeFactor"
+	pluginMethodTimepointIndex    = "timepointIndex"
+	pluginMethodTimepoints        = "timepoints"
+
+	erc20MethodBalanceOf = "balanceOf"
+
+	// volatilityWindow is the window of the average volatility of the dynamic fee plugin
+	volatilityWindow = 86400
+
+	// pluginSwapGas is the gas of the calls of the pool to a plugin which hooks swaps
+	pluginSwapGas = 40000
+)
+
+// the flags of the plugin config of a pool, which tell the hooks of the plugin that the pool calls
+const (
+	beforeSwapFlag uint8 = 1
+	afterSwapFlag  uint8 = 2
+	dynamicFeeFlag uint8 = 128
+)
+
+var (
+	// feeFactorShift is the shift of the fee factors of the sliding fee plugin, a factor of 1 << 96 is the base fee
+	feeFactorShift = uint(96)
+	// priceChangeFactorDenominator is the denominator of the price change factor of the sliding fee plugin
+	priceChangeFactorDenominator = big.NewInt(1000)
+)
@@ -0,0 +1,14 @@
+package algebraintegral
+
+import (
+	_ "embed"
+)
+
+//go:embed abis/AlgebraIntegralPool.json
+var poolABIJson []byte
+
+//go:embed abis/AlgebraIntegralPlugin.json
+var pluginABIJson []byte
+
+//go:embed abis/ERC20.json
+var erc20ABIJson []byte
@@ -0,0 +1,7 @@
+package algebraintegral
+
+import "errors"
+
+var (
+	ErrUnknownPlugin = errors.New("pool has an unknown plugin which hooks swaps")
+)
@@ -0,0 +1,89 @@
+package algebraintegral
+
+import (
+	"math/big"
+
+	v3Utils "github.com/daoleno/uniswapv3-sdk/utils"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/algebrav1"
+)
+
+// hooksSwaps returns whether the pool calls its plugin on swaps, a plugin which does not can not change the fee
+func (p Plugin) hooksSwaps() bool {
+	return p.Address != "" && p.Config&(beforeSwapFlag|afterSwapFlag|dynamicFeeFlag) != 0
+}
+
+// quotable returns whether the fee of a swap is known, the fee of an unknown plugin which hooks swaps is not
+func (p Plugin) quotable() bool {
+	return p.Type != PluginTypeUnknown || !p.hooksSwaps()
+}
+
+// getDynamicFee ports the fee of the dynamic fee plugin
+// https://github.com/cryptoalgebra/Algebra/blob/integral-v1.1/src/plugin/contracts/libraries/AdaptiveFee.sol
+func getDynamicFee(volatility *big.Int, config *FeeConfig) uint16 {
+	if config.Alpha1|config.Alpha2 == 0 {
+		return config.BaseFee
+	}
+
+	sumOfSigmoids := uint32(algebrav1.Sigmoid(volatility, config.Gamma1, config.Alpha1, big.NewInt(int64(config.Beta1)))) +
+		uint32(algebrav1.Sigmoid(volatility, config.Gamma2, config.Alpha2, big.NewInt(int64(config.Beta2))))
+
+	// safe since alpha1 + alpha2 + baseFee must be <= type(uint16).max
+	return uint16(uint32(config.BaseFee) + sumOfSigmoids)
+}
+
+// getSlidingFee ports the fee of the sliding fee plugin, the fee factors are updated from the price change since the
+// last timepoint, then the fee is the base fee scaled by the factor of the direction of the swap
+// https://github.com/cryptoalgebra/Algebra/blob/integral-v1.2/src/plugin/contracts/base/SlidingFeeModule.sol
+func getSlidingFee(zeroToOne bool, currentTick, lastTick int32, config *SlidingFee, factors FeeFactors) (uint16, FeeFactors) {
+	if currentTick != lastTick {
+		factors = calculateFeeFactors(currentTick, lastTick, config.PriceChangeFactor, factors)
+	}
+
+	factor := factors.OneToZeroFeeFactor
+	if zeroToOne {
+		factor = factors.ZeroToOneFeeFactor
+	}
+
+	fee := new(big.Int).Mul(big.NewInt(int64(config.BaseFee)), factor)
+	fee.Rsh(fee, feeFactorShift)
+
+	return uint16(fee.Uint64()), factors
+}
+
+// calculateFeeFactors moves the fee factors by the price change between two ticks, the zero to one factor grows when
+// the price moves down, as after swaps from zero to one, and the factors are kept in [0, 2 << 96]
+func calculateFeeFactors(currentTick, lastTick int32, priceChangeFactor uint16, factors FeeFactors) FeeFactors {
+	tickDelta := max(min(lastTick-currentTick, int32(v3Utils.MaxTick)), int32(v3Utils.MinTick))
+
+	sqrtPriceRatio, err := v3Utils.GetSqrtRatioAtTick(int(tickDelta))
+	if err != nil {
+		return factors
+	}
+
+	one := new(big.Int).Lsh(big.NewInt(1), feeFactorShift)
+	maxFactor := new(big.Int).Lsh(big.NewInt(2), feeFactorShift)
+
+	// (lastPrice - currentPrice) / currentPrice, in Q96
+	priceChangeRatio := new(big.Int).Mul(sqrtPriceRatio, sqrtPriceRatio)
+	priceChangeRatio.Rsh(priceChangeRatio, feeFactorShift)
+	priceChangeRatio.Sub(priceChangeRatio, one)
+
+	feeFactorImpact := priceChangeRatio.Mul(priceChangeRatio, big.NewInt(int64(priceChangeFactor)))
+	feeFactorImpact.Quo(feeFactorImpact, priceChangeFactorDenominator)
+
+	newZeroToOneFeeFactor := new(big.Int).Add(factors.ZeroToOneFeeFactor, feeFactorImpact)
+	switch {
+	case newZeroToOneFeeFactor.Sign() <= 0:
+		return FeeFactors{ZeroToOneFeeFactor: new(big.Int), OneToZeroFeeFactor: maxFactor}
+	case newZeroToOneFeeFactor.Cmp(maxFactor) >= 0:
+		return FeeFactors{ZeroToOneFeeFactor: maxFactor, OneToZeroFeeFactor: new(big.Int)}
+	}
+
+	newOneToZeroFeeFactor := new(big.Int).Sub(factors.OneToZeroFeeFactor, feeFactorImpact)
+	if newOneToZeroFeeFactor.Sign() < 0 {
+		newOneToZeroFeeFactor.SetInt64(0)
+	}
+
+	return FeeFactors{ZeroToOneFeeFactor: newZeroToOneFeeFactor, OneToZeroFeeFactor: newOneToZeroFeeFactor}
+}
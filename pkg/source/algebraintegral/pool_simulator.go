@@ -0,0 +1,126 @@
+package algebraintegral
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/KyberNetwork/logger"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/algebrav1"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// PoolSimulator swaps with the math of Algebra V1 and the fee the plugin of the pool returns in beforeSwap
+type PoolSimulator struct {
+	*algebrav1.PoolSimulator
+	plugin  Plugin
+	lastFee uint16
+	tick    int32
+	// timepointWritten tells whether a simulated swap has written the timepoint of the block, after which the last
+	// tick of the sliding fee plugin is the tick before that swap
+	timepointWritten bool
+}
+
+func NewPoolSimulator(entityPool entity.Pool, defaultGas int64) (*PoolSimulator, error) {
+	var extra Extra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	if !extra.Plugin.quotable() {
+		return nil, ErrUnknownPlugin
+	}
+
+	if extra.Plugin.hooksSwaps() {
+		defaultGas += pluginSwapGas
+	}
+
+	simulator, err := algebrav1.NewPoolSimulator(entityPool, defaultGas)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PoolSimulator{
+		PoolSimulator: simulator,
+		plugin:        extra.Plugin,
+		lastFee:       extra.GlobalState.FeeZto,
+		tick:          int32(extra.GlobalState.Tick.Int64()),
+	}, nil
+}
+
+func (p *PoolSimulator) CalcAmountOut(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	zeroForOne := !strings.EqualFold(tokenOut, p.Info.Tokens[0])
+
+	fee, feeFactors := p.beforeSwapFee(zeroForOne)
+
+	result, err := p.PoolSimulator.CalcAmountOutWithFee(tokenAmountIn, tokenOut, fee)
+	if err != nil {
+		return result, err
+	}
+
+	result.SwapInfo = SwapInfo{
+		StateUpdate: result.SwapInfo.(algebrav1.StateUpdate),
+		FeeFactors:  feeFactors,
+	}
+
+	return result, nil
+}
+
+func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	si, ok := params.SwapInfo.(SwapInfo)
+	if !ok {
+		logger.Warnf("failed to UpdateBalance for Algebra Integral %v %v pool, wrong swapInfo type", p.Info.Address, p.Info.Exchange)
+		return
+	}
+
+	params.SwapInfo = si.StateUpdate
+	p.PoolSimulator.UpdateBalance(params)
+
+	if p.plugin.SlidingFee != nil {
+		if !p.timepointWritten {
+			p.plugin.SlidingFee.LastTick = p.tick
+		}
+		if si.FeeFactors != nil {
+			p.plugin.SlidingFee.ZeroToOneFeeFactor = si.FeeFactors.ZeroToOneFeeFactor
+			p.plugin.SlidingFee.OneToZeroFeeFactor = si.FeeFactors.OneToZeroFeeFactor
+		}
+	}
+	p.timepointWritten = true
+	p.tick = int32(si.GlobalState.Tick.Int64())
+}
+
+// beforeSwapFee returns the fee the plugin returns in beforeSwap, and the fee factors of the sliding fee plugin after
+// the swap
+func (p *PoolSimulator) beforeSwapFee(zeroForOne bool) (uint16, *FeeFactors) {
+	if !p.plugin.hooksSwaps() {
+		return p.lastFee, nil
+	}
+
+	switch p.plugin.Type {
+	case PluginTypeDynamicFee:
+		if p.plugin.Config&dynamicFeeFlag == 0 || p.plugin.FeeConfig == nil || p.plugin.AverageVolatility == nil {
+			return p.lastFee, nil
+		}
+		return getDynamicFee(p.plugin.AverageVolatility, p.plugin.FeeConfig), nil
+
+	case PluginTypeSlidingFee:
+		slidingFee := p.plugin.SlidingFee
+		if p.plugin.Config&beforeSwapFlag == 0 || slidingFee == nil ||
+			slidingFee.ZeroToOneFeeFactor == nil || slidingFee.OneToZeroFeeFactor == nil {
+			return p.lastFee, nil
+		}
+		fee, feeFactors := getSlidingFee(zeroForOne, p.tick, slidingFee.LastTick, slidingFee, FeeFactors{
+			ZeroToOneFeeFactor: slidingFee.ZeroToOneFeeFactor,
+			OneToZeroFeeFactor: slidingFee.OneToZeroFeeFactor,
+		})
+		return fee, &feeFactors
+
+	default:
+		// the limit order plugin does not change the fee
+		return p.lastFee, nil
+	}
+}
@@ -0,0 +1,135 @@
+package algebraintegral
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// the state of an Algebra V1 pool with a fee of 2985, see the tests of algebrav1
+const poolState = `"liquidity":2822091172725,"globalState":{"price":93065132232889433968150957834858946,"tick":279543,"feeZto":2985,"feeOtz":2985,"timepoint_index":0,"community_fee_token0":0,"community_fee_token1":0,"unlocked":true},"ticks":[{"Index":-887220,"LiquidityGross":2822091172725,"LiquidityNet":2822091172725},{"Index":273540,"LiquidityGross":116315447200034,"LiquidityNet":116315447200034},{"Index":279120,"LiquidityGross":116315447200034,"LiquidityNet":-116315447200034},{"Index":285480,"LiquidityGross":2822091172725,"LiquidityNet":-2822091172725}],"tickSpacing":60`
+
+func newTestPoolSimulator(plugin string) (*PoolSimulator, error) {
+	return NewPoolSimulator(entity.Pool{
+		Exchange: "",
+		Type:     DexTypeAlgebraIntegral,
+		Reserves: entity.PoolReserves{"723924", "36031866872048609640"},
+		Tokens:   []*entity.PoolToken{{Address: "A"}, {Address: "B"}},
+		Extra:    fmt.Sprintf(`{%s,"plugin":%s}`, poolState, plugin),
+	}, 1001)
+}
+
+func TestPoolSimulator_CalcAmountOut(t *testing.T) {
+	testcases := []struct {
+		name              string
+		plugin            string
+		expectedFee       uint16
+		expectedOutAmount string
+		expectedGas       int64
+	}{
+		{
+			name:              "no plugin uses the last fee",
+			plugin:            `{"address":"","type":"","config":0}`,
+			expectedFee:       2985,
+			expectedOutAmount: "1375085809786534",
+			expectedGas:       1001,
+		},
+		{
+			name:              "dynamic fee without sigmoids is the base fee",
+			plugin:            `{"address":"0x1","type":"dynamic-fee","config":129,"feeConfig":{"alpha1":0,"alpha2":0,"beta1":360,"beta2":60000,"gamma1":59,"gamma2":8500,"baseFee":2985},"averageVolatility":1000}`,
+			expectedFee:       2985,
+			expectedOutAmount: "1375085809786534",
+			expectedGas:       1001 + pluginSwapGas,
+		},
+		{
+			name:              "dynamic fee of a volatile pool is the base fee with both sigmoids",
+			plugin:            `{"address":"0x1","type":"dynamic-fee","config":129,"feeConfig":{"alpha1":2900,"alpha2":12000,"beta1":360,"beta2":60000,"gamma1":59,"gamma2":8500,"baseFee":100},"averageVolatility":1000000}`,
+			expectedFee:       15000,
+			expectedOutAmount: "1358541910846644",
+			expectedGas:       1001 + pluginSwapGas,
+		},
+		{
+			name:              "sliding fee without price change is the base fee",
+			plugin:            `{"address":"0x1","type":"sliding-fee","config":1,"slidingFee":{"baseFee":2985,"priceChangeFactor":1000,"zeroToOneFeeFactor":79228162514264337593543950336,"oneToZeroFeeFactor":79228162514264337593543950336,"lastTick":279543}}`,
+			expectedFee:       2985,
+			expectedOutAmount: "1375085809786534",
+			expectedGas:       1001 + pluginSwapGas,
+		},
+		{
+			name:              "unknown plugin which does not hook swaps uses the last fee",
+			plugin:            `{"address":"0x1","type":"unknown","config":64}`,
+			expectedFee:       2985,
+			expectedOutAmount: "1375085809786534",
+			expectedGas:       1001,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := newTestPoolSimulator(tc.plugin)
+			require.NoError(t, err)
+
+			fee, _ := p.beforeSwapFee(true)
+			assert.Equal(t, tc.expectedFee, fee)
+
+			out, err := p.CalcAmountOut(pool.TokenAmount{Token: "A", Amount: big.NewInt(1000)}, "B")
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOutAmount, out.TokenAmountOut.Amount.String())
+			assert.Equal(t, tc.expectedGas, out.Gas)
+		})
+	}
+}
+
+func TestNewPoolSimulator_UnknownPlugin(t *testing.T) {
+	_, err := newTestPoolSimulator(`{"address":"0x1","type":"unknown","config":1}`)
+	assert.ErrorIs(t, err, ErrUnknownPlugin)
+}
+
+func TestPoolSimulator_UpdateBalance_SlidingFee(t *testing.T) {
+	p, err := newTestPoolSimulator(`{"address":"0x1","type":"sliding-fee","config":1,"slidingFee":{"baseFee":2985,"priceChangeFactor":1000,"zeroToOneFeeFactor":79228162514264337593543950336,"oneToZeroFeeFactor":79228162514264337593543950336,"lastTick":279543}}`)
+	require.NoError(t, err)
+
+	tokenAmountIn := pool.TokenAmount{Token: "A", Amount: big.NewInt(1000)}
+	result, err := p.CalcAmountOut(tokenAmountIn, "B")
+	require.NoError(t, err)
+	p.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  tokenAmountIn,
+		TokenAmountOut: *result.TokenAmountOut,
+		SwapInfo:       result.SwapInfo,
+	})
+
+	// the swap from zero to one moved the price down, so the next swap from zero to one pays more than the base fee
+	// and the next swap from one to zero pays less
+	assert.Less(t, p.tick, int32(279543))
+	assert.Equal(t, int32(279543), p.plugin.SlidingFee.LastTick)
+
+	zeroToOneFee, _ := p.beforeSwapFee(true)
+	oneToZeroFee, _ := p.beforeSwapFee(false)
+	assert.Greater(t, zeroToOneFee, uint16(2985))
+	assert.Less(t, oneToZeroFee, uint16(2985))
+}
+
+func TestCalculateFeeFactors(t *testing.T) {
+	one := new(big.Int).Lsh(big.NewInt(1), 96)
+	maxFactor := new(big.Int).Lsh(big.NewInt(2), 96)
+	factors := FeeFactors{ZeroToOneFeeFactor: one, OneToZeroFeeFactor: one}
+
+	// the price is 1.0001^100 lower, about 1% with a price change factor of 1
+	newFactors := calculateFeeFactors(-100, 0, 1000, factors)
+	assert.Equal(t, 1, newFactors.ZeroToOneFeeFactor.Cmp(one))
+	assert.Equal(t, maxFactor, new(big.Int).Add(newFactors.ZeroToOneFeeFactor, newFactors.OneToZeroFeeFactor))
+
+	fee, _ := getSlidingFee(true, -100, 0, &SlidingFee{BaseFee: 10000, PriceChangeFactor: 1000}, factors)
+	assert.Equal(t, uint16(10100), fee)
+
+	// the factors are kept in [0, 2 << 96]
+	newFactors = calculateFeeFactors(100000, 0, 3000, factors)
+	assert.Equal(t, maxFactor, newFactors.OneToZeroFeeFactor)
+	assert.Equal(t, 0, newFactors.ZeroToOneFeeFactor.Sign())
+}
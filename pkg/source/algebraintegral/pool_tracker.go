@@ -0,0 +1,280 @@
+package algebraintegral
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	v3Entities "github.com/daoleno/uniswapv3-sdk/entities"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sourcegraph/conc/pool"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/algebrav1"
+	sourcePool "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+type PoolTracker struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+	// ticksTracker gets the ticks from the subgraph, which has the schema of the Algebra V1 subgraph
+	ticksTracker *algebrav1.PoolTracker
+}
+
+func NewPoolTracker(
+	cfg *Config,
+	ethrpcClient *ethrpc.Client,
+) (*PoolTracker, error) {
+	ticksTracker, err := algebrav1.NewPoolTracker(newAlgebraV1Config(cfg), ethrpcClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PoolTracker{
+		config:       cfg,
+		ethrpcClient: ethrpcClient,
+		ticksTracker: ticksTracker,
+	}, nil
+}
+
+func (d *PoolTracker) GetNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	_ sourcePool.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	logger.Infof("[%v] Start getting new state of pool: %v", d.config.DexID, p.Address)
+
+	var (
+		liquidity, tickSpacing, reserve0, reserve1 *big.Int
+		globalState                                GlobalState
+		pluginAddress                              common.Address
+		plugin                                     Plugin
+		ticks                                      []v3Entities.Tick
+	)
+
+	g := pool.New().WithContext(ctx)
+	g.Go(func(context.Context) error {
+		calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: p.Address,
+			Method: poolMethodLiquidity,
+		}, []interface{}{&liquidity})
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: p.Address,
+			Method: poolMethodGlobalState,
+		}, []interface{}{&globalState})
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: p.Address,
+			Method: poolMethodTickSpacing,
+		}, []interface{}{&tickSpacing})
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: p.Address,
+			Method: poolMethodPlugin,
+		}, []interface{}{&pluginAddress})
+		calls.AddCall(&ethrpc.Call{
+			ABI:    erc20ABI,
+			Target: p.Tokens[0].Address,
+			Method: erc20MethodBalanceOf,
+			Params: []interface{}{common.HexToAddress(p.Address)},
+		}, []interface{}{&reserve0})
+		calls.AddCall(&ethrpc.Call{
+			ABI:    erc20ABI,
+			Target: p.Tokens[1].Address,
+			Method: erc20MethodBalanceOf,
+			Params: []interface{}{common.HexToAddress(p.Address)},
+		}, []interface{}{&reserve1})
+
+		if _, err := calls.Aggregate(); err != nil {
+			logger.WithFields(logger.Fields{
+				"poolAddress": p.Address,
+				"error":       err,
+			}).Errorf("failed to fetch data from RPC")
+			return err
+		}
+
+		var err error
+		plugin, err = d.getPlugin(ctx, pluginAddress, globalState.PluginConfig)
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"poolAddress":   p.Address,
+				"pluginAddress": pluginAddress.Hex(),
+				"error":         err,
+			}).Errorf("failed to fetch plugin")
+		}
+
+		return err
+	})
+	g.Go(func(context.Context) error {
+		var err error
+		ticks, err = d.ticksTracker.GetPoolTicks(ctx, p.Address)
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"poolAddress": p.Address,
+				"error":       err,
+			}).Errorf("failed to query subgraph for pool ticks")
+		}
+
+		return err
+	})
+
+	if err := g.Wait(); err != nil {
+		return entity.Pool{}, err
+	}
+
+	extraBytes, err := json.Marshal(Extra{
+		Extra: algebrav1.Extra{
+			Liquidity: liquidity,
+			GlobalState: algebrav1.GlobalState{
+				Price:              globalState.Price,
+				Tick:               globalState.Tick,
+				FeeZto:             globalState.LastFee,
+				FeeOtz:             globalState.LastFee,
+				CommunityFeeToken0: globalState.CommunityFee,
+				CommunityFeeToken1: globalState.CommunityFee,
+				Unlocked:           globalState.Unlocked,
+			},
+			Ticks:       ticks,
+			TickSpacing: int32(tickSpacing.Int64()),
+		},
+		Plugin: plugin,
+	})
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to marshal extra data")
+		return entity.Pool{}, err
+	}
+
+	p.Extra = string(extraBytes)
+	p.Timestamp = time.Now().Unix()
+	p.Reserves = entity.PoolReserves{reserve0.String(), reserve1.String()}
+
+	logger.Infof("[%v] Finish updating state of pool: %v, plugin %v", d.config.DexID, p.Address, plugin.Type)
+
+	return p, nil
+}
+
+// getPlugin detects the type of the plugin from its factory and reads the config of the fee of the known types
+func (d *PoolTracker) getPlugin(ctx context.Context, pluginAddress common.Address, pluginConfig uint8) (Plugin, error) {
+	if pluginAddress == (common.Address{}) {
+		return Plugin{Type: PluginTypeNone, Config: pluginConfig}, nil
+	}
+
+	plugin := Plugin{
+		Address: strings.ToLower(pluginAddress.Hex()),
+		Type:    PluginTypeUnknown,
+		Config:  pluginConfig,
+	}
+
+	// a plugin which has no factory is of type unknown
+	var pluginFactory common.Address
+	resp, err := d.ethrpcClient.NewRequest().SetContext(ctx).AddCall(&ethrpc.Call{
+		ABI:    pluginABI,
+		Target: plugin.Address,
+		Method: pluginMethodPluginFactory,
+	}, []interface{}{&pluginFactory}).TryAggregate()
+	if err != nil {
+		return Plugin{}, err
+	}
+	if len(resp.Result) == 0 || !resp.Result[0] {
+		return plugin, nil
+	}
+
+	if pluginType, ok := d.config.PluginFactories[strings.ToLower(pluginFactory.Hex())]; ok {
+		plugin.Type = pluginType
+	}
+
+	switch plugin.Type {
+	case PluginTypeDynamicFee:
+		plugin.FeeConfig, plugin.AverageVolatility, err = d.getDynamicFeeConfig(ctx, plugin.Address)
+	case PluginTypeSlidingFee:
+		plugin.SlidingFee, err = d.getSlidingFeeConfig(ctx, plugin.Address)
+	}
+
+	return plugin, err
+}
+
+// getDynamicFeeConfig returns the fee config and the average volatility of the last day, the volatility is nil when
+// the plugin has less than a day of timepoints, then the simulator keeps the last fee
+func (d *PoolTracker) getDynamicFeeConfig(ctx context.Context, pluginAddress string) (*FeeConfig, *big.Int, error) {
+	var feeConfig FeeConfig
+	if _, err := d.ethrpcClient.NewRequest().SetContext(ctx).AddCall(&ethrpc.Call{
+		ABI:    pluginABI,
+		Target: pluginAddress,
+		Method: pluginMethodFeeConfig,
+	}, []interface{}{&feeConfig}).Call(); err != nil {
+		return nil, nil, err
+	}
+
+	var timepoints GetTimepointsResult
+	resp, err := d.ethrpcClient.NewRequest().SetContext(ctx).AddCall(&ethrpc.Call{
+		ABI:    pluginABI,
+		Target: pluginAddress,
+		Method: pluginMethodGetTimepoints,
+		Params: []interface{}{[]uint32{0, volatilityWindow}},
+	}, []interface{}{&timepoints}).TryAggregate()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(resp.Result) == 0 || !resp.Result[0] || len(timepoints.VolatilityCumulatives) != 2 {
+		return &feeConfig, nil, nil
+	}
+
+	averageVolatility := new(big.Int).Sub(timepoints.VolatilityCumulatives[0], timepoints.VolatilityCumulatives[1])
+	averageVolatility.Quo(averageVolatility, big.NewInt(volatilityWindow))
+
+	return &feeConfig, averageVolatility, nil
+}
+
+func (d *PoolTracker) getSlidingFeeConfig(ctx context.Context, pluginAddress string) (*SlidingFee, error) {
+	var (
+		slidingFee     SlidingFee
+		feeFactors     FeeFactors
+		timepointIndex uint16
+	)
+
+	if _, err := d.ethrpcClient.NewRequest().SetContext(ctx).AddCall(&ethrpc.Call{
+		ABI:    pluginABI,
+		Target: pluginAddress,
+		Method: pluginMethodBaseFee,
+	}, []interface{}{&slidingFee.BaseFee}).AddCall(&ethrpc.Call{
+		ABI:    pluginABI,
+		Target: pluginAddress,
+		Method: pluginMethodPriceChangeFactor,
+	}, []interface{}{&slidingFee.PriceChangeFactor}).AddCall(&ethrpc.Call{
+		ABI:    pluginABI,
+		Target: pluginAddress,
+		Method: pluginMethodFeeFactors,
+	}, []interface{}{&feeFactors}).AddCall(&ethrpc.Call{
+		ABI:    pluginABI,
+		Target: pluginAddress,
+		Method: pluginMethodTimepointIndex,
+	}, []interface{}{&timepointIndex}).Aggregate(); err != nil {
+		return nil, err
+	}
+
+	var timepoint Timepoint
+	if _, err := d.ethrpcClient.NewRequest().SetContext(ctx).AddCall(&ethrpc.Call{
+		ABI:    pluginABI,
+		Target: pluginAddress,
+		Method: pluginMethodTimepoints,
+		Params: []interface{}{big.NewInt(int64(timepointIndex))},
+	}, []interface{}{&timepoint}).Call(); err != nil {
+		return nil, err
+	}
+
+	slidingFee.ZeroToOneFeeFactor = feeFactors.ZeroToOneFeeFactor
+	slidingFee.OneToZeroFeeFactor = feeFactors.OneToZeroFeeFactor
+	slidingFee.LastTick = int32(timepoint.Tick.Int64())
+
+	return &slidingFee, nil
+}
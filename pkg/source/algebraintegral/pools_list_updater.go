@@ -0,0 +1,41 @@
+package algebraintegral
+
+import (
+	"context"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/algebrav1"
+)
+
+// PoolsListUpdater lists the pools from the subgraph of the dex, which has the schema of the Algebra V1 subgraph
+type PoolsListUpdater struct {
+	*algebrav1.PoolsListUpdater
+}
+
+func NewPoolsListUpdater(cfg *Config) *PoolsListUpdater {
+	return &PoolsListUpdater{
+		PoolsListUpdater: algebrav1.NewPoolsListUpdater(newAlgebraV1Config(cfg)),
+	}
+}
+
+func (d *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte) ([]entity.Pool, []byte, error) {
+	pools, newMetadataBytes, err := d.PoolsListUpdater.GetNewPools(ctx, metadataBytes)
+	if err != nil {
+		return nil, metadataBytes, err
+	}
+
+	for i := range pools {
+		pools[i].Type = DexTypeAlgebraIntegral
+	}
+
+	return pools, newMetadataBytes, nil
+}
+
+func newAlgebraV1Config(cfg *Config) *algebrav1.Config {
+	return &algebrav1.Config{
+		DexID:              cfg.DexID,
+		SubgraphAPI:        cfg.SubgraphAPI,
+		AllowSubgraphError: cfg.AllowSubgraphError,
+		SkipFeeCalculating: true,
+	}
+}
@@ -0,0 +1,91 @@
+package algebraintegral
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/algebrav1"
+)
+
+type PluginType string
+
+const (
+	PluginTypeNone       PluginType = ""
+	PluginTypeDynamicFee PluginType = "dynamic-fee"
+	PluginTypeSlidingFee PluginType = "sliding-fee"
+	PluginTypeLimitOrder PluginType = "limit-order"
+	PluginTypeUnknown    PluginType = "unknown"
+)
+
+type Extra struct {
+	algebrav1.Extra
+	Plugin Plugin `json:"plugin"`
+}
+
+type Plugin struct {
+	Address string     `json:"address"`
+	Type    PluginType `json:"type"`
+	// Config is the plugin config of the pool, the flags of the hooks the pool calls
+	Config uint8 `json:"config"`
+
+	// of the dynamic fee plugin
+	FeeConfig         *FeeConfig `json:"feeConfig,omitempty"`
+	AverageVolatility *big.Int   `json:"averageVolatility,omitempty"`
+
+	// of the sliding fee plugin
+	SlidingFee *SlidingFee `json:"slidingFee,omitempty"`
+}
+
+// FeeConfig is the adaptive fee config of the dynamic fee plugin, which has no volume sigmoid unlike Algebra V1
+type FeeConfig struct {
+	Alpha1  uint16 `json:"alpha1"`
+	Alpha2  uint16 `json:"alpha2"`
+	Beta1   uint32 `json:"beta1"`
+	Beta2   uint32 `json:"beta2"`
+	Gamma1  uint16 `json:"gamma1"`
+	Gamma2  uint16 `json:"gamma2"`
+	BaseFee uint16 `json:"baseFee"`
+}
+
+type SlidingFee struct {
+	BaseFee            uint16   `json:"baseFee"`
+	PriceChangeFactor  uint16   `json:"priceChangeFactor"`
+	ZeroToOneFeeFactor *big.Int `json:"zeroToOneFeeFactor"`
+	OneToZeroFeeFactor *big.Int `json:"oneToZeroFeeFactor"`
+	// LastTick is the tick of the last timepoint of the plugin
+	LastTick int32 `json:"lastTick"`
+}
+
+type SwapInfo struct {
+	algebrav1.StateUpdate
+	// FeeFactors are the fee factors of the sliding fee plugin after the swap
+	FeeFactors *FeeFactors
+}
+
+type FeeFactors struct {
+	ZeroToOneFeeFactor *big.Int
+	OneToZeroFeeFactor *big.Int
+}
+
+type GlobalState struct {
+	Price        *big.Int
+	Tick         *big.Int
+	LastFee      uint16
+	PluginConfig uint8
+	CommunityFee uint16
+	Unlocked     bool
+}
+
+type Timepoint struct {
+	Initialized          bool
+	BlockTimestamp       uint32
+	TickCumulative       *big.Int
+	VolatilityCumulative *big.Int
+	Tick                 *big.Int
+	AverageTick          *big.Int
+	WindowStartIndex     uint16
+}
+
+type GetTimepointsResult struct {
+	TickCumulatives       []*big.Int
+	VolatilityCumulatives []*big.Int
+}
@@ -16,19 +16,19 @@ func getFee(
 	volumePerLiquidity *big.Int,
 	config *FeeConfiguration,
 ) uint16 {
-	sumOfSigmoids := sigmoid(volatility, config.Gamma1, config.Alpha1, big.NewInt(int64(config.Beta1))) +
-		sigmoid(volatility, config.Gamma2, config.Alpha2, big.NewInt(int64(config.Beta2)))
+	sumOfSigmoids := Sigmoid(volatility, config.Gamma1, config.Alpha1, big.NewInt(int64(config.Beta1))) +
+		Sigmoid(volatility, config.Gamma2, config.Alpha2, big.NewInt(int64(config.Beta2)))
 
 	// safe since alpha1 + alpha2 + baseFee _must_ be <= type(uint16).max
 	return uint16(config.BaseFee +
-		sigmoid(volumePerLiquidity, config.VolumeGamma, uint16(sumOfSigmoids), big.NewInt(int64(config.VolumeBeta))),
+		Sigmoid(volumePerLiquidity, config.VolumeGamma, uint16(sumOfSigmoids), big.NewInt(int64(config.VolumeBeta))),
 	)
 }
 
-// / @notice calculates α / (1 + e^( (β-x) / γ))
-// / that is a sigmoid with a maximum value of α, x-shifted by β, and stretched by γ
-// / @dev returns uint256 for fuzzy testing. Guaranteed that the result is not greater than alpha
-func sigmoid(
+// Sigmoid calculates α / (1 + e^( (β-x) / γ))
+// that is a sigmoid with a maximum value of α, x-shifted by β, and stretched by γ
+// Guaranteed that the result is not greater than alpha
+func Sigmoid(
 	x *big.Int,
 	g uint16,
 	alpha uint16,
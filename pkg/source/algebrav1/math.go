@@ -41,6 +41,7 @@ func (p *PoolSimulator) _calculateSwapAndLock(
 	zeroToOne bool,
 	amountRequired *big.Int,
 	limitSqrtPrice *big.Int,
+	fee uint16,
 ) (error, *big.Int, *big.Int, *StateUpdate) {
 	var cache SwapCalculationCache
 	var err error
@@ -77,13 +78,7 @@ func (p *PoolSimulator) _calculateSwapAndLock(
 
 	// don't need to care about activeIncentive
 
-	// use pre-calculated fee instead of calculating from timepoints
-	// see tracker code for more details
-	if zeroToOne {
-		cache.fee = p.globalState.FeeZto
-	} else {
-		cache.fee = p.globalState.FeeOtz
-	}
+	cache.fee = fee
 	logger.Debugf("fee %v", cache.fee)
 
 	var step PriceMovementCache
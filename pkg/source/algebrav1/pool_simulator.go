@@ -116,6 +116,23 @@ func (p *PoolSimulator) getSqrtPriceLimit(zeroForOne bool) *big.Int {
 func (p *PoolSimulator) CalcAmountOut(
 	tokenAmountIn pool.TokenAmount,
 	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	// use pre-calculated fee instead of calculating from timepoints
+	// see tracker code for more details
+	fee := p.globalState.FeeZto
+	if strings.EqualFold(tokenOut, p.Info.Tokens[0]) {
+		fee = p.globalState.FeeOtz
+	}
+
+	return p.CalcAmountOutWithFee(tokenAmountIn, tokenOut, fee)
+}
+
+// CalcAmountOutWithFee swaps with a fee given by the caller, for the pools whose fee of a swap is not the fee of the
+// global state, like the pools of Algebra Integral whose fees are given by plugins.
+func (p *PoolSimulator) CalcAmountOutWithFee(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+	fee uint16,
 ) (*pool.CalcAmountOutResult, error) {
 	var tokenInIndex = p.GetTokenIndex(tokenAmountIn.Token)
 	var tokenOutIndex = p.GetTokenIndex(tokenOut)
@@ -129,7 +146,7 @@ func (p *PoolSimulator) CalcAmountOut(
 		}
 
 		priceLimit := p.getSqrtPriceLimit(zeroForOne)
-		err, amount0, amount1, stateUpdate := p._calculateSwapAndLock(zeroForOne, tokenAmountIn.Amount, priceLimit)
+		err, amount0, amount1, stateUpdate := p._calculateSwapAndLock(zeroForOne, tokenAmountIn.Amount, priceLimit, fee)
 		if err != nil {
 			return &pool.CalcAmountOutResult{}, fmt.Errorf("can not GetOutputAmount, err: %+v", err)
 		}
@@ -87,24 +87,7 @@ func (d *PoolTracker) GetNewPoolState(
 		return entity.Pool{}, err
 	}
 
-	ticks := make([]v3Entities.Tick, 0, len(poolTicks))
-	for _, tickResp := range poolTicks {
-		tick, err := transformTickRespToTick(tickResp)
-		if err != nil {
-			logger.WithFields(logger.Fields{
-				"poolAddress": p.Address,
-				"error":       err,
-			}).Errorf("failed to transform tickResp to tick")
-			continue
-		}
-
-		// LiquidityGross = 0 means that the tick is uninitialized
-		if tick.LiquidityGross.Cmp(bignumber.ZeroBI) == 0 {
-			continue
-		}
-
-		ticks = append(ticks, tick)
-	}
+	ticks := transformTickResps(p.Address, poolTicks)
 
 	extraBytes, err := json.Marshal(Extra{
 		Liquidity:   rpcData.liquidity,
@@ -604,3 +587,37 @@ func (d *PoolTracker) getPoolTicks(ctx context.Context, poolAddress string) ([]T
 
 	return ticks, nil
 }
+
+// GetPoolTicks returns the initialized ticks of a pool from the subgraph, for the sources built on the Algebra
+// subgraph schema.
+func (d *PoolTracker) GetPoolTicks(ctx context.Context, poolAddress string) ([]v3Entities.Tick, error) {
+	poolTicks, err := d.getPoolTicks(ctx, poolAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return transformTickResps(poolAddress, poolTicks), nil
+}
+
+func transformTickResps(poolAddress string, poolTicks []TickResp) []v3Entities.Tick {
+	ticks := make([]v3Entities.Tick, 0, len(poolTicks))
+	for _, tickResp := range poolTicks {
+		tick, err := transformTickRespToTick(tickResp)
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"poolAddress": poolAddress,
+				"error":       err,
+			}).Errorf("failed to transform tickResp to tick")
+			continue
+		}
+
+		// LiquidityGross = 0 means that the tick is uninitialized
+		if tick.LiquidityGross.Cmp(bignumber.ZeroBI) == 0 {
+			continue
+		}
+
+		ticks = append(ticks, tick)
+	}
+
+	return ticks
+}
@@ -0,0 +1,32 @@
+package ambient
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+var (
+	crocSwapDexABI abi.ABI
+	crocQueryABI   abi.ABI
+	erc20ABI       abi.ABI
+)
+
+func init() {
+	builder := []struct {
+		ABI  *abi.ABI
+		data []byte
+	}{
+		{&crocSwapDexABI, crocSwapDexJson},
+		{&crocQueryABI, crocQueryJson},
+		{&erc20ABI, erc20Json},
+	}
+
+	for _, b := range builder {
+		var err error
+		*b.ABI, err = abi.JSON(bytes.NewReader(b.data))
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package ambient
+
+import "github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+
+type Config struct {
+	DexID        string              `json:"-"`
+	ChainID      valueobject.ChainID `json:"chainID"`
+	DexAddress   string              `json:"dexAddress"`
+	QueryAddress string              `json:"queryAddress"`
+
+	// StartBlock is the CrocSwapDex deployment block, discovery starts from here on the first run
+	StartBlock uint64 `json:"startBlock"`
+	// BlockBatchSize is the block range of a single eth_getLogs request
+	BlockBatchSize uint64 `json:"blockBatchSize"`
+	// TickRange is the number of tick size steps read on each side of the current tick, swaps can not move the
+	// price outside of this range
+	TickRange int `json:"tickRange"`
+}
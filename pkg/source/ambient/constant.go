@@ -0,0 +1,50 @@
+package ambient
+
+import (
+	"math/big"
+)
+
+const (
+	DexTypeAmbient        = "ambient"
+	defaultTokenDecimals  = 18
+	defaultTokenWeight    = 50
+	defaultBlockBatchSize = 5000
+	defaultTickRange      = 100
+	zeroString            = "0"
+)
+
+const (
+	crocSwapDexEventColdCmd = "CrocColdCmd"
+
+	crocQueryMethodQueryCurve      = "queryCurve"
+	crocQueryMethodQueryLevel      = "queryLevel"
+	crocQueryMethodQueryPoolParams = "queryPoolParams"
+	erc20MethodDecimals            = "decimals"
+)
+
+const (
+	// initPoolCode is the ColdPath user command that initializes a pool, its input is
+	// abi.encode(uint8 code, address base, address quote, uint256 poolIdx, uint128 price)
+	initPoolCode    = 71
+	initPoolCmdSize = 5 * 32
+
+	// minTick and maxTick bound the Q64.64 sqrt price of CrocSwap, ambient liquidity spans this whole range
+	minTick = -665454
+	maxTick = 831818
+
+	// lotSizeBits converts the lots of a tick level into liquidity, the lowest lot bit flags knockout liquidity
+	lotSizeBits     = 10
+	knockoutFlagBit = 1
+
+	// seedDeflatorBits is the fixed point precision of the ambient seed deflator (Q16.48)
+	seedDeflatorBits = 48
+	// priceRootShift converts the Q64.64 CrocSwap sqrt price into Q64.96
+	priceRootShift = 32
+
+	multicallBatchSize = 500
+)
+
+var (
+	zeroBI     = big.NewInt(0)
+	defaultGas = Gas{Swap: 120000}
+)
@@ -0,0 +1,14 @@
+package ambient
+
+import (
+	_ "embed"
+)
+
+//go:embed abis/CrocSwapDex.json
+var crocSwapDexJson []byte
+
+//go:embed abis/CrocQuery.json
+var crocQueryJson []byte
+
+//go:embed abis/ERC20.json
+var erc20Json []byte
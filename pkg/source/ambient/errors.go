@@ -0,0 +1,11 @@
+package ambient
+
+import "errors"
+
+var (
+	ErrInvalidInitPoolCmd   = errors.New("invalid init pool command")
+	ErrZeroLiquidity        = errors.New("pool has no liquidity")
+	ErrZeroAmountOut        = errors.New("amountOut is 0")
+	ErrTickRangeExceeded    = errors.New("swap moves the price outside of the tracked tick range")
+	ErrKnockoutNotSupported = errors.New("pool has knockout liquidity which is not supported")
+)
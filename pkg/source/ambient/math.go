@@ -0,0 +1,144 @@
+package ambient
+
+import (
+	"math/big"
+	"sort"
+
+	v3Entities "github.com/daoleno/uniswapv3-sdk/entities"
+	v3Utils "github.com/daoleno/uniswapv3-sdk/utils"
+)
+
+// ambientLiquidity mirrors CompoundMath.inflateLiqSeed, the seeds grow with the fees reinvested into the curve
+func ambientLiquidity(seeds *big.Int, seedDeflator uint64) *big.Int {
+	growth := new(big.Int).Mul(seeds, new(big.Int).SetUint64(seedDeflator))
+	return growth.Rsh(growth, seedDeflatorBits).Add(growth, seeds)
+}
+
+// lotsToLiquidity mirrors LiquidityMath.lotsToLiquidity, the knockout flag bit is not part of the liquidity
+func lotsToLiquidity(lots *big.Int) *big.Int {
+	liquidity := new(big.Int).SetBit(lots, 0, 0)
+	return liquidity.Lsh(liquidity, lotSizeBits)
+}
+
+func hasKnockoutFlag(lots *big.Int) bool {
+	return lots.Bit(0) == knockoutFlagBit
+}
+
+func priceRootToSqrtPriceX96(priceRoot *big.Int) *big.Int {
+	return new(big.Int).Lsh(priceRoot, priceRootShift)
+}
+
+// floorTick rounds the tick down to a multiple of the tick size
+func floorTick(tick, tickSize int) int {
+	compressed := tick / tickSize
+	if tick < 0 && tick%tickSize != 0 {
+		compressed--
+	}
+
+	return compressed * tickSize
+}
+
+// levelNet is the change of the concentrated liquidity when the price crosses the level upward: the ranges with a
+// bid at the tick start there and the ranges with an ask at the tick end there
+func levelNet(level Level) *big.Int {
+	return new(big.Int).Sub(lotsToLiquidity(level.BidLots), lotsToLiquidity(level.AskLots))
+}
+
+// buildTicks converts the curve into uniswapv3 ticks. The ambient liquidity is a position over the whole price
+// range. The concentrated liquidity is only known inside [TickMin, TickMax]: the ranges crossing TickMin start at
+// it and every range still open ends at TickMax, so the swap has to stay inside the tracked range.
+func buildTicks(extra Extra) ([]v3Entities.Tick, error) {
+	tickSize := extra.TickSize
+	currentTick, err := v3Utils.GetTickAtSqrtRatio(priceRootToSqrtPriceX96(extra.PriceRoot))
+	if err != nil {
+		return nil, err
+	}
+
+	liquidityNets := map[int]*big.Int{}
+	addNet := func(tick int, net *big.Int) {
+		if cur, ok := liquidityNets[tick]; ok {
+			cur.Add(cur, net)
+			return
+		}
+		liquidityNets[tick] = new(big.Int).Set(net)
+	}
+
+	ambientLower, ambientUpper := floorTick(minTick, tickSize)+tickSize, floorTick(maxTick, tickSize)
+	addNet(ambientLower, extra.AmbientLiq)
+	addNet(ambientUpper, new(big.Int).Neg(extra.AmbientLiq))
+
+	// the concentrated liquidity at TickMin is the active one minus every level crossed on the way up to the price
+	lowerLiq := new(big.Int).Set(extra.ConcLiq)
+	openLiq := new(big.Int)
+	for _, level := range extra.Levels {
+		if level.Tick > extra.TickMin && level.Tick <= currentTick {
+			lowerLiq.Sub(lowerLiq, levelNet(level))
+		}
+	}
+	addNet(extra.TickMin, lowerLiq)
+	openLiq.Add(openLiq, lowerLiq)
+
+	for _, level := range extra.Levels {
+		if level.Tick <= extra.TickMin || level.Tick >= extra.TickMax {
+			continue
+		}
+		net := levelNet(level)
+		addNet(level.Tick, net)
+		openLiq.Add(openLiq, net)
+	}
+	addNet(extra.TickMax, openLiq.Neg(openLiq))
+
+	ticks := make([]v3Entities.Tick, 0, len(liquidityNets))
+	for tick, net := range liquidityNets {
+		if net.Sign() == 0 {
+			continue
+		}
+
+		ticks = append(ticks, v3Entities.Tick{
+			Index:          tick,
+			LiquidityGross: new(big.Int).Abs(net),
+			LiquidityNet:   net,
+		})
+	}
+
+	sort.Slice(ticks, func(i, j int) bool {
+		return ticks[i].Index < ticks[j].Index
+	})
+
+	return ticks, nil
+}
+
+// calcReserves sums the token amounts held by every range of the curve, the CrocSwapDex contract holds the
+// balances of all pools
+func calcReserves(ticks []v3Entities.Tick, sqrtPriceX96 *big.Int) (*big.Int, *big.Int, error) {
+	reserve0, reserve1 := big.NewInt(0), big.NewInt(0)
+	liquidity := big.NewInt(0)
+
+	for i := 0; i < len(ticks)-1; i++ {
+		liquidity = new(big.Int).Add(liquidity, ticks[i].LiquidityNet)
+		if liquidity.Sign() <= 0 {
+			continue
+		}
+
+		sqrtLower, err := v3Utils.GetSqrtRatioAtTick(ticks[i].Index)
+		if err != nil {
+			return nil, nil, err
+		}
+		sqrtUpper, err := v3Utils.GetSqrtRatioAtTick(ticks[i+1].Index)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch {
+		case sqrtPriceX96.Cmp(sqrtLower) <= 0:
+			reserve0.Add(reserve0, v3Utils.GetAmount0Delta(sqrtLower, sqrtUpper, liquidity, false))
+		case sqrtPriceX96.Cmp(sqrtUpper) >= 0:
+			reserve1.Add(reserve1, v3Utils.GetAmount1Delta(sqrtLower, sqrtUpper, liquidity, false))
+		default:
+			reserve0.Add(reserve0, v3Utils.GetAmount0Delta(sqrtPriceX96, sqrtUpper, liquidity, false))
+			reserve1.Add(reserve1, v3Utils.GetAmount1Delta(sqrtLower, sqrtPriceX96, liquidity, false))
+		}
+	}
+
+	return reserve0, reserve1, nil
+}
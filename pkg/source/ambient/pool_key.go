@@ -0,0 +1,18 @@
+package ambient
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ID returns keccak256(abi.encode(base, quote, poolIdx)), the same value as PoolSpecs.encodeKey
+func (k PoolKey) ID() common.Hash {
+	var buf [96]byte
+	copy(buf[12:32], common.HexToAddress(k.Base).Bytes())
+	copy(buf[44:64], common.HexToAddress(k.Quote).Bytes())
+	new(big.Int).SetUint64(k.PoolIdx).FillBytes(buf[64:96])
+
+	return crypto.Keccak256Hash(buf[:])
+}
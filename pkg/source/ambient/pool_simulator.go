@@ -0,0 +1,210 @@
+package ambient
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/KyberNetwork/logger"
+	coreEntities "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/daoleno/uniswapv3-sdk/constants"
+	v3Entities "github.com/daoleno/uniswapv3-sdk/entities"
+	v3Utils "github.com/daoleno/uniswapv3-sdk/utils"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+// PoolSimulator swaps on the curve with the uniswapv3 math: inside a tick the ambient and the concentrated
+// liquidity both follow x·y=k, so the curve is the sum of a full range position and the tracked tick levels.
+type PoolSimulator struct {
+	CurvePool *v3Entities.Pool
+	pool.Pool
+	poolKey PoolKey
+	dex     string
+	gas     Gas
+	tickMin int
+	tickMax int
+}
+
+func NewPoolSimulator(entityPool entity.Pool, chainID valueobject.ChainID) (*PoolSimulator, error) {
+	var extra Extra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	var staticExtra StaticExtra
+	if err := json.Unmarshal([]byte(entityPool.StaticExtra), &staticExtra); err != nil {
+		return nil, err
+	}
+
+	if extra.Knockout {
+		return nil, ErrKnockoutNotSupported
+	}
+
+	if extra.PriceRoot == nil || extra.TickSize <= 0 {
+		return nil, ErrZeroLiquidity
+	}
+
+	liquidity := new(big.Int).Add(extra.AmbientLiq, extra.ConcLiq)
+	if liquidity.Sign() <= 0 {
+		return nil, ErrZeroLiquidity
+	}
+
+	// Use the raw tokens so the sdk keeps the base/quote ordering, the native token is address zero
+	token0 := coreEntities.NewToken(uint(chainID), common.HexToAddress(staticExtra.Base), uint(entityPool.Tokens[0].Decimals), entityPool.Tokens[0].Symbol, entityPool.Tokens[0].Name)
+	token1 := coreEntities.NewToken(uint(chainID), common.HexToAddress(staticExtra.Quote), uint(entityPool.Tokens[1].Decimals), entityPool.Tokens[1].Symbol, entityPool.Tokens[1].Name)
+
+	tokens := make([]string, 2)
+	reserves := make([]*big.Int, 2)
+	if len(entityPool.Reserves) == 2 && len(entityPool.Tokens) == 2 {
+		tokens[0] = entityPool.Tokens[0].Address
+		reserves[0], _ = new(big.Int).SetString(entityPool.Reserves[0], 10)
+		tokens[1] = entityPool.Tokens[1].Address
+		reserves[1], _ = new(big.Int).SetString(entityPool.Reserves[1], 10)
+	}
+
+	curveTicks, err := buildTicks(extra)
+	if err != nil {
+		return nil, err
+	}
+
+	ticks, err := v3Entities.NewTickListDataProvider(curveTicks, extra.TickSize)
+	if err != nil {
+		return nil, err
+	}
+
+	sqrtPriceX96 := priceRootToSqrtPriceX96(extra.PriceRoot)
+	currentTick, err := v3Utils.GetTickAtSqrtRatio(sqrtPriceX96)
+	if err != nil {
+		return nil, err
+	}
+
+	curvePool, err := v3Entities.NewPool(
+		token0,
+		token1,
+		constants.FeeAmount(extra.FeeRate),
+		sqrtPriceX96,
+		liquidity,
+		currentTick,
+		ticks,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var info = pool.PoolInfo{
+		Address:    strings.ToLower(entityPool.Address),
+		ReserveUsd: entityPool.ReserveUsd,
+		SwapFee:    big.NewInt(int64(extra.FeeRate)),
+		Exchange:   entityPool.Exchange,
+		Type:       entityPool.Type,
+		Tokens:     tokens,
+		Reserves:   reserves,
+		Checked:    false,
+	}
+
+	return &PoolSimulator{
+		Pool:      pool.Pool{Info: info},
+		CurvePool: curvePool,
+		poolKey:   staticExtra.PoolKey,
+		dex:       staticExtra.Dex,
+		gas:       defaultGas,
+		tickMin:   extra.TickMin,
+		tickMax:   extra.TickMax,
+	}, nil
+}
+
+/**
+ * getSqrtPriceLimit get the price limit of the swap based on the tick range tracked for this pool
+ */
+func (p *PoolSimulator) getSqrtPriceLimit(zeroForOne bool) *big.Int {
+	var tickLimit int
+	if zeroForOne {
+		tickLimit = p.tickMin
+	} else {
+		tickLimit = p.tickMax
+	}
+
+	sqrtPriceX96Limit, err := v3Utils.GetSqrtRatioAtTick(tickLimit)
+
+	if err != nil {
+		return nil
+	}
+
+	return sqrtPriceX96Limit
+}
+
+func (p *PoolSimulator) CalcAmountOut(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	var tokenInIndex = p.GetTokenIndex(tokenAmountIn.Token)
+	var tokenOutIndex = p.GetTokenIndex(tokenOut)
+	if tokenInIndex < 0 || tokenOutIndex < 0 || tokenInIndex == tokenOutIndex {
+		return &pool.CalcAmountOutResult{}, fmt.Errorf("tokenInIndex %v or tokenOutIndex %v is not correct", tokenInIndex, tokenOutIndex)
+	}
+
+	zeroForOne := tokenInIndex == 0
+	tokenIn := p.CurvePool.Token1
+	if zeroForOne {
+		tokenIn = p.CurvePool.Token0
+	}
+
+	sqrtPriceLimit := p.getSqrtPriceLimit(zeroForOne)
+	amountIn := coreEntities.FromRawAmount(tokenIn, tokenAmountIn.Amount)
+	swapAmountOut, newPoolState, err := p.CurvePool.GetOutputAmount(amountIn, sqrtPriceLimit)
+	if err != nil {
+		return &pool.CalcAmountOutResult{}, fmt.Errorf("can not GetOutputAmount, err: %+v", err)
+	}
+
+	// the levels outside of the tracked range are unknown, a swap stopped by the limit is not fully filled
+	if sqrtPriceLimit != nil && newPoolState.SqrtRatioX96.Cmp(sqrtPriceLimit) == 0 {
+		return &pool.CalcAmountOutResult{}, ErrTickRangeExceeded
+	}
+
+	amountOut := swapAmountOut.Quotient()
+	if amountOut.Cmp(zeroBI) <= 0 {
+		return &pool.CalcAmountOutResult{}, ErrZeroAmountOut
+	}
+
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{
+			Token:  tokenOut,
+			Amount: amountOut,
+		},
+		Fee: &pool.TokenAmount{
+			Token:  tokenAmountIn.Token,
+			Amount: nil,
+		},
+		Gas: p.gas.Swap,
+		SwapInfo: SwapInfo{
+			nextStateSqrtRatioX96: new(big.Int).Set(newPoolState.SqrtRatioX96),
+			nextStateLiquidity:    new(big.Int).Set(newPoolState.Liquidity),
+			nextStateTickCurrent:  newPoolState.TickCurrent,
+		},
+	}, nil
+}
+
+func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	si, ok := params.SwapInfo.(SwapInfo)
+	if !ok {
+		logger.Warn("failed to UpdateBalance for Ambient pool, wrong swapInfo type")
+		return
+	}
+	p.CurvePool.SqrtRatioX96 = si.nextStateSqrtRatioX96
+	p.CurvePool.Liquidity = si.nextStateLiquidity
+	p.CurvePool.TickCurrent = si.nextStateTickCurrent
+}
+
+func (p *PoolSimulator) GetMetaInfo(_ string, _ string) interface{} {
+	return MetaInfo{
+		Dex:     p.dex,
+		Base:    p.poolKey.Base,
+		Quote:   p.poolKey.Quote,
+		PoolIdx: p.poolKey.PoolIdx,
+	}
+}
@@ -0,0 +1,173 @@
+package ambient
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/uniswapv3"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+const (
+	testBase  = "0x6b175474e89094c44da98b954eedeac495271d0f"
+	testQuote = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+)
+
+var (
+	// 2^64 is a price of 1 in Q64.64
+	testPriceRoot  = new(big.Int).Lsh(big.NewInt(1), 64)
+	testAmbientLiq = bignumber("1000000000000000000000")
+	// 1e18 lots of 1024 units of liquidity
+	testConcLots = bignumber("1000000000000000000")
+	testConcLiq  = bignumber("1024000000000000000000")
+)
+
+func bignumber(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 10)
+	return n
+}
+
+func newTestEntityPool(t *testing.T, extra Extra) entity.Pool {
+	poolKey := PoolKey{Base: testBase, Quote: testQuote, PoolIdx: 420}
+	staticExtraBytes, err := json.Marshal(StaticExtra{PoolKey: poolKey})
+	require.NoError(t, err)
+	extraBytes, err := json.Marshal(extra)
+	require.NoError(t, err)
+
+	return entity.Pool{
+		Address:  poolKey.ID().Hex(),
+		SwapFee:  float64(extra.FeeRate),
+		Reserves: entity.PoolReserves{"1000000000000000000000", "1000000000000000000000"},
+		Tokens: []*entity.PoolToken{
+			{Address: testBase, Decimals: 18},
+			{Address: testQuote, Decimals: 18},
+		},
+		Extra:       string(extraBytes),
+		StaticExtra: string(staticExtraBytes),
+	}
+}
+
+// newTestExtra has ambient liquidity and a concentrated range over [-160, 160]
+func newTestExtra() Extra {
+	return Extra{
+		PriceRoot:  testPriceRoot,
+		AmbientLiq: testAmbientLiq,
+		ConcLiq:    testConcLiq,
+		FeeRate:    3000,
+		TickSize:   16,
+		TickMin:    -1600,
+		TickMax:    1600,
+		Levels: []Level{
+			{Tick: -160, BidLots: testConcLots, AskLots: big.NewInt(0)},
+			{Tick: 160, BidLots: big.NewInt(0), AskLots: testConcLots},
+		},
+	}
+}
+
+func TestPoolSimulator_CalcAmountOut_MatchesV3(t *testing.T) {
+	ambientPool, err := NewPoolSimulator(newTestEntityPool(t, newTestExtra()), valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+
+	// the same curve as uniswapv3 ranges: the ambient liquidity over the full range plus the concentrated range
+	entityPool := newTestEntityPool(t, newTestExtra())
+	v3ExtraBytes, err := json.Marshal(uniswapv3.Extra{
+		Liquidity:    new(big.Int).Add(testAmbientLiq, testConcLiq),
+		SqrtPriceX96: new(big.Int).Lsh(big.NewInt(1), 96),
+		Tick:         big.NewInt(0),
+		Ticks: []uniswapv3.Tick{
+			{Index: -665440, LiquidityGross: testAmbientLiq, LiquidityNet: testAmbientLiq},
+			{Index: -160, LiquidityGross: testConcLiq, LiquidityNet: testConcLiq},
+			{Index: 160, LiquidityGross: testConcLiq, LiquidityNet: new(big.Int).Neg(testConcLiq)},
+			{Index: 831808, LiquidityGross: testAmbientLiq, LiquidityNet: new(big.Int).Neg(testAmbientLiq)},
+		},
+	})
+	require.NoError(t, err)
+	entityPool.Extra = string(v3ExtraBytes)
+	v3Pool, err := uniswapv3.NewPoolSimulatorWithTickSpacing(entityPool, valueobject.ChainIDEthereum, 3000, 16)
+	require.NoError(t, err)
+
+	for _, tokenIn := range []string{testBase, testQuote} {
+		tokenOut := ambientPool.CanSwapTo(tokenIn)[0]
+		// large enough to cross the concentrated range
+		amountIn := pool.TokenAmount{Token: tokenIn, Amount: bignumber("80000000000000000000")}
+
+		ambientResult, err := ambientPool.CalcAmountOut(amountIn, tokenOut)
+		require.NoError(t, err)
+		v3Result, err := v3Pool.CalcAmountOut(amountIn, tokenOut)
+		require.NoError(t, err)
+
+		assert.Equal(t, v3Result.TokenAmountOut.Amount, ambientResult.TokenAmountOut.Amount)
+	}
+}
+
+func TestPoolSimulator_CalcAmountOut_LevelsBelowPrice(t *testing.T) {
+	// the price is above the concentrated range, only the ambient liquidity is active at the current tick
+	extra := newTestExtra()
+	extra.ConcLiq = big.NewInt(0)
+	extra.Levels = []Level{
+		{Tick: -800, BidLots: testConcLots, AskLots: big.NewInt(0)},
+		{Tick: -400, BidLots: big.NewInt(0), AskLots: testConcLots},
+	}
+	ambientPool, err := NewPoolSimulator(newTestEntityPool(t, extra), valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+
+	extra.Levels = nil
+	ambientOnlyPool, err := NewPoolSimulator(newTestEntityPool(t, extra), valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+
+	small := pool.TokenAmount{Token: testBase, Amount: bignumber("1000000000000000000")}
+	result, err := ambientPool.CalcAmountOut(small, testQuote)
+	require.NoError(t, err)
+	ambientOnlyResult, err := ambientOnlyPool.CalcAmountOut(small, testQuote)
+	require.NoError(t, err)
+	assert.Equal(t, ambientOnlyResult.TokenAmountOut.Amount, result.TokenAmountOut.Amount)
+
+	// a swap reaching the range below gets the extra concentrated liquidity
+	large := pool.TokenAmount{Token: testBase, Amount: bignumber("60000000000000000000")}
+	result, err = ambientPool.CalcAmountOut(large, testQuote)
+	require.NoError(t, err)
+	ambientOnlyResult, err = ambientOnlyPool.CalcAmountOut(large, testQuote)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.TokenAmountOut.Amount.Cmp(ambientOnlyResult.TokenAmountOut.Amount))
+}
+
+func TestPoolSimulator_CalcAmountOut_TickRangeExceeded(t *testing.T) {
+	ambientPool, err := NewPoolSimulator(newTestEntityPool(t, newTestExtra()), valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+
+	_, err = ambientPool.CalcAmountOut(pool.TokenAmount{Token: testBase, Amount: bignumber("1000000000000000000000")}, testQuote)
+	assert.ErrorIs(t, err, ErrTickRangeExceeded)
+}
+
+func TestNewPoolSimulator_Knockout(t *testing.T) {
+	extra := newTestExtra()
+	extra.Knockout = true
+
+	_, err := NewPoolSimulator(newTestEntityPool(t, extra), valueobject.ChainIDEthereum)
+	assert.ErrorIs(t, err, ErrKnockoutNotSupported)
+}
+
+func TestParseInitPoolCmd(t *testing.T) {
+	input := make([]byte, initPoolCmdSize)
+	input[31] = initPoolCode
+	copy(input[76:96], common.HexToAddress(testQuote).Bytes())
+	big.NewInt(420).FillBytes(input[96:128])
+
+	poolKey, ok, err := parseInitPoolCmd(input)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, PoolKey{Base: valueobject.ZeroAddress, Quote: testQuote, PoolIdx: 420}, poolKey)
+
+	// other cold path commands are skipped
+	input[31] = initPoolCode + 1
+	_, ok, err = parseInitPoolCmd(input)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,214 @@
+package ambient
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	v3Utils "github.com/daoleno/uniswapv3-sdk/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	sourcePool "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util"
+)
+
+type PoolTracker struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+}
+
+func NewPoolTracker(
+	cfg *Config,
+	ethrpcClient *ethrpc.Client,
+) *PoolTracker {
+	return &PoolTracker{
+		config:       cfg,
+		ethrpcClient: ethrpcClient,
+	}
+}
+
+func (d *PoolTracker) GetNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	_ sourcePool.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	logger.Infof("[%s] Start getting new state of pool: %v", d.config.DexID, p.Address)
+
+	var staticExtra StaticExtra
+	if err := json.Unmarshal([]byte(p.StaticExtra), &staticExtra); err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to unmarshal static extra")
+		return entity.Pool{}, err
+	}
+
+	var (
+		curve      curveResp
+		poolParams poolParamsResp
+		params     = poolKeyParams(staticExtra.PoolKey)
+	)
+
+	rpcRequest := d.ethrpcClient.NewRequest()
+	rpcRequest.SetContext(ctx)
+	rpcRequest.AddCall(&ethrpc.Call{
+		ABI:    crocQueryABI,
+		Target: d.config.QueryAddress,
+		Method: crocQueryMethodQueryCurve,
+		Params: params,
+	}, []interface{}{&curve})
+	rpcRequest.AddCall(&ethrpc.Call{
+		ABI:    crocQueryABI,
+		Target: d.config.QueryAddress,
+		Method: crocQueryMethodQueryPoolParams,
+		Params: params,
+	}, []interface{}{&poolParams})
+
+	if _, err := rpcRequest.Aggregate(); err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to fetch data from RPC")
+		return entity.Pool{}, err
+	}
+
+	if curve.Curve.PriceRoot == nil || curve.Curve.PriceRoot.Sign() == 0 {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+		}).Errorf("pool is not initialized")
+		return entity.Pool{}, ErrZeroLiquidity
+	}
+
+	sqrtPriceX96 := priceRootToSqrtPriceX96(curve.Curve.PriceRoot)
+	currentTick, err := v3Utils.GetTickAtSqrtRatio(sqrtPriceX96)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to get current tick")
+		return entity.Pool{}, err
+	}
+
+	tickSize := max(int(poolParams.Pool.TickSize), 1)
+	tickRange := d.config.TickRange
+	if tickRange <= 0 {
+		tickRange = defaultTickRange
+	}
+	tickMin := max(floorTick(currentTick, tickSize)-tickRange*tickSize, floorTick(minTick, tickSize)+tickSize)
+	tickMax := min(floorTick(currentTick, tickSize)+tickRange*tickSize, floorTick(maxTick, tickSize))
+
+	levels, err := d.getLevels(ctx, staticExtra.PoolKey, tickMin, tickMax, tickSize)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to fetch tick levels from query contract")
+		return entity.Pool{}, err
+	}
+
+	extra := Extra{
+		PriceRoot:  curve.Curve.PriceRoot,
+		AmbientLiq: ambientLiquidity(curve.Curve.AmbientSeeds, curve.Curve.SeedDeflator),
+		ConcLiq:    curve.Curve.ConcLiq,
+		FeeRate:    poolParams.Pool.FeeRate,
+		TickSize:   tickSize,
+		TickMin:    tickMin,
+		TickMax:    tickMax,
+		Levels:     levels,
+		Knockout: lo.SomeBy(levels, func(level Level) bool {
+			return hasKnockoutFlag(level.BidLots) || hasKnockoutFlag(level.AskLots)
+		}),
+	}
+
+	ticks, err := buildTicks(extra)
+	if err != nil {
+		return entity.Pool{}, err
+	}
+	reserve0, reserve1, err := calcReserves(ticks, sqrtPriceX96)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to calculate reserves")
+		return entity.Pool{}, err
+	}
+
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to marshal extra data")
+		return entity.Pool{}, err
+	}
+
+	p.Extra = string(extraBytes)
+	p.SwapFee = float64(extra.FeeRate)
+	p.Timestamp = time.Now().Unix()
+	p.Reserves = entity.PoolReserves{
+		reserve0.String(),
+		reserve1.String(),
+	}
+
+	logger.Infof("[%s] Finish updating state of pool: %v", d.config.DexID, p.Address)
+
+	return p, nil
+}
+
+// getLevels reads the bid and ask lots of every tick of [tickMin, tickMax], CrocQuery has no tick bitmap so the
+// range is scanned tick size by tick size and the empty levels are dropped
+func (d *PoolTracker) getLevels(ctx context.Context, poolKey PoolKey, tickMin, tickMax, tickSize int) ([]Level, error) {
+	tickIndexes := make([]int, 0, (tickMax-tickMin)/tickSize+1)
+	for tick := tickMin; tick <= tickMax; tick += tickSize {
+		tickIndexes = append(tickIndexes, tick)
+	}
+
+	params := poolKeyParams(poolKey)
+
+	var levels []Level
+	for _, chunk := range lo.Chunk[int](tickIndexes, multicallBatchSize) {
+		rpcRequest := d.ethrpcClient.NewRequest()
+		rpcRequest.SetContext(util.NewContextWithTimestamp(ctx))
+
+		results := make([]level, len(chunk))
+		for i, tick := range chunk {
+			rpcRequest.AddCall(&ethrpc.Call{
+				ABI:    crocQueryABI,
+				Target: d.config.QueryAddress,
+				Method: crocQueryMethodQueryLevel,
+				Params: append(params, big.NewInt(int64(tick))),
+			}, []interface{}{&results[i]})
+		}
+
+		if _, err := rpcRequest.Aggregate(); err != nil {
+			return nil, err
+		}
+
+		for i, tick := range chunk {
+			if results[i].BidLots.Sign() == 0 && results[i].AskLots.Sign() == 0 {
+				continue
+			}
+
+			levels = append(levels, Level{
+				Tick:    tick,
+				BidLots: results[i].BidLots,
+				AskLots: results[i].AskLots,
+			})
+		}
+	}
+
+	return levels, nil
+}
+
+func poolKeyParams(poolKey PoolKey) []interface{} {
+	return []interface{}{
+		common.HexToAddress(poolKey.Base),
+		common.HexToAddress(poolKey.Quote),
+		new(big.Int).SetUint64(poolKey.PoolIdx),
+	}
+}
@@ -0,0 +1,256 @@
+package ambient
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+// LogsClient is the part of ethclient.Client used to scan CrocSwapDex events
+type LogsClient interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+}
+
+type PoolsListUpdater struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+	logsClient   LogsClient
+}
+
+func NewPoolsListUpdater(
+	cfg *Config,
+	ethrpcClient *ethrpc.Client,
+	logsClient LogsClient,
+) *PoolsListUpdater {
+	return &PoolsListUpdater{
+		config:       cfg,
+		ethrpcClient: ethrpcClient,
+		logsClient:   logsClient,
+	}
+}
+
+func (d *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte) ([]entity.Pool, []byte, error) {
+	var metadata Metadata
+	if len(metadataBytes) != 0 {
+		if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
+			return nil, metadataBytes, err
+		}
+	}
+
+	fromBlock := d.config.StartBlock
+	if metadata.LastBlock >= fromBlock {
+		fromBlock = metadata.LastBlock + 1
+	}
+
+	latestBlock, err := d.logsClient.BlockNumber(ctx)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"error": err,
+		}).Errorf("failed to get latest block")
+		return nil, metadataBytes, err
+	}
+	if fromBlock > latestBlock {
+		return nil, metadataBytes, nil
+	}
+
+	batchSize := d.config.BlockBatchSize
+	if batchSize == 0 {
+		batchSize = defaultBlockBatchSize
+	}
+	toBlock := fromBlock + batchSize - 1
+	if toBlock > latestBlock {
+		toBlock = latestBlock
+	}
+
+	logs, err := d.logsClient.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{common.HexToAddress(d.config.DexAddress)},
+		Topics:    [][]common.Hash{{crocSwapDexABI.Events[crocSwapDexEventColdCmd].ID}},
+	})
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"fromBlock": fromBlock,
+			"toBlock":   toBlock,
+			"error":     err,
+		}).Errorf("failed to filter CrocColdCmd logs")
+		return nil, metadataBytes, err
+	}
+
+	pools, err := d.newPools(ctx, logs)
+	if err != nil {
+		return nil, metadataBytes, err
+	}
+
+	newMetadataBytes, err := json.Marshal(Metadata{
+		LastBlock: toBlock,
+	})
+	if err != nil {
+		return nil, metadataBytes, err
+	}
+
+	logger.Infof("got %v %s pools until block %v", len(pools), d.config.DexID, toBlock)
+
+	return pools, newMetadataBytes, nil
+}
+
+func (d *PoolsListUpdater) newPools(ctx context.Context, logs []types.Log) ([]entity.Pool, error) {
+	poolKeys := make([]PoolKey, 0, len(logs))
+	for _, log := range logs {
+		poolKey, ok, err := parseColdCmdLog(log)
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"txHash": log.TxHash.Hex(),
+				"error":  err,
+			}).Errorf("failed to parse CrocColdCmd log")
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		poolKeys = append(poolKeys, poolKey)
+	}
+
+	decimals, err := d.getDecimals(ctx, poolKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	pools := make([]entity.Pool, 0, len(poolKeys))
+	for _, poolKey := range poolKeys {
+		staticExtraBytes, err := json.Marshal(StaticExtra{
+			PoolKey: poolKey,
+			Dex:     strings.ToLower(d.config.DexAddress),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		pools = append(pools, entity.Pool{
+			Address:   strings.ToLower(poolKey.ID().Hex()),
+			Exchange:  d.config.DexID,
+			Type:      DexTypeAmbient,
+			Timestamp: time.Now().Unix(),
+			Reserves:  entity.PoolReserves{zeroString, zeroString},
+			Tokens: []*entity.PoolToken{
+				{
+					Address:   d.tokenAddress(poolKey.Base),
+					Decimals:  decimals[poolKey.Base],
+					Weight:    defaultTokenWeight,
+					Swappable: true,
+				},
+				{
+					Address:   d.tokenAddress(poolKey.Quote),
+					Decimals:  decimals[poolKey.Quote],
+					Weight:    defaultTokenWeight,
+					Swappable: true,
+				},
+			},
+			StaticExtra: string(staticExtraBytes),
+		})
+	}
+
+	return pools, nil
+}
+
+// tokenAddress maps the native token (address zero) to the wrapped native token used in routing
+func (d *PoolsListUpdater) tokenAddress(token string) string {
+	if token == strings.ToLower(valueobject.ZeroAddress) {
+		return strings.ToLower(valueobject.WETHByChainID[d.config.ChainID])
+	}
+
+	return token
+}
+
+func (d *PoolsListUpdater) getDecimals(ctx context.Context, poolKeys []PoolKey) (map[string]uint8, error) {
+	decimals := map[string]uint8{strings.ToLower(valueobject.ZeroAddress): defaultTokenDecimals}
+
+	var tokens []string
+	for _, poolKey := range poolKeys {
+		for _, token := range []string{poolKey.Base, poolKey.Quote} {
+			if _, ok := decimals[token]; !ok {
+				decimals[token] = defaultTokenDecimals
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	if len(tokens) == 0 {
+		return decimals, nil
+	}
+
+	results := make([]uint8, len(tokens))
+	rpcRequest := d.ethrpcClient.NewRequest()
+	rpcRequest.SetContext(ctx)
+	for i, token := range tokens {
+		rpcRequest.AddCall(&ethrpc.Call{
+			ABI:    erc20ABI,
+			Target: token,
+			Method: erc20MethodDecimals,
+			Params: nil,
+		}, []interface{}{&results[i]})
+	}
+
+	resp, err := rpcRequest.TryAggregate()
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"error": err,
+		}).Errorf("failed to fetch token decimals")
+		return nil, err
+	}
+
+	for i, token := range tokens {
+		if resp.Result[i] {
+			decimals[token] = results[i]
+		}
+	}
+
+	return decimals, nil
+}
+
+// parseColdCmdLog returns the pool initialized by a CrocColdCmd log, the other cold path commands are skipped
+func parseColdCmdLog(log types.Log) (PoolKey, bool, error) {
+	values, err := crocSwapDexABI.Unpack(crocSwapDexEventColdCmd, log.Data)
+	if err != nil {
+		return PoolKey{}, false, err
+	}
+
+	input, ok := values[0].([]byte)
+	if !ok {
+		return PoolKey{}, false, ErrInvalidInitPoolCmd
+	}
+
+	return parseInitPoolCmd(input)
+}
+
+func parseInitPoolCmd(input []byte) (PoolKey, bool, error) {
+	if len(input) < 32 || input[31] != initPoolCode {
+		return PoolKey{}, false, nil
+	}
+	if len(input) < initPoolCmdSize {
+		return PoolKey{}, false, ErrInvalidInitPoolCmd
+	}
+
+	poolIdx := new(big.Int).SetBytes(input[96:128])
+	if !poolIdx.IsUint64() {
+		return PoolKey{}, false, ErrInvalidInitPoolCmd
+	}
+
+	return PoolKey{
+		Base:    strings.ToLower(common.BytesToAddress(input[32:64]).Hex()),
+		Quote:   strings.ToLower(common.BytesToAddress(input[64:96]).Hex()),
+		PoolIdx: poolIdx.Uint64(),
+	}, true, nil
+}
@@ -0,0 +1,95 @@
+package ambient
+
+import (
+	"math/big"
+)
+
+type Gas struct {
+	Swap int64
+}
+
+// SwapInfo present the after state of a swap
+type SwapInfo struct {
+	nextStateSqrtRatioX96 *big.Int
+	nextStateLiquidity    *big.Int
+	nextStateTickCurrent  int
+}
+
+type Metadata struct {
+	LastBlock uint64 `json:"lastBlock"`
+}
+
+// PoolKey identifies a pool inside the CrocSwapDex contract, the native token is the address zero base
+type PoolKey struct {
+	Base    string `json:"base"`
+	Quote   string `json:"quote"`
+	PoolIdx uint64 `json:"poolIdx"`
+}
+
+type StaticExtra struct {
+	PoolKey
+	Dex string `json:"dex"`
+}
+
+// Level is the concentrated liquidity of a tick, bid lots start at the tick and ask lots end at it
+type Level struct {
+	Tick    int      `json:"tick"`
+	BidLots *big.Int `json:"bidLots"`
+	AskLots *big.Int `json:"askLots"`
+}
+
+type Extra struct {
+	PriceRoot *big.Int `json:"priceRoot"`
+	// AmbientLiq is the full range liquidity, the ambient seeds inflated by the seed deflator
+	AmbientLiq *big.Int `json:"ambientLiq"`
+	// ConcLiq is the concentrated liquidity active at the current price
+	ConcLiq  *big.Int `json:"concLiq"`
+	FeeRate  uint16   `json:"feeRate"`
+	TickSize int      `json:"tickSize"`
+	// TickMin and TickMax are the bounds of the levels read by the tracker
+	TickMin int     `json:"tickMin"`
+	TickMax int     `json:"tickMax"`
+	Levels  []Level `json:"levels"`
+	// Knockout is set when a level in range holds knockout liquidity, it is removed from the curve once the price
+	// crosses its pivot and is not modeled yet
+	Knockout bool `json:"knockout"`
+}
+
+type MetaInfo struct {
+	Dex     string `json:"dex"`
+	Base    string `json:"base"`
+	Quote   string `json:"quote"`
+	PoolIdx uint64 `json:"poolIdx"`
+}
+
+type CurveState struct {
+	PriceRoot    *big.Int `abi:"priceRoot_"`
+	AmbientSeeds *big.Int `abi:"ambientSeeds_"`
+	ConcLiq      *big.Int `abi:"concLiq_"`
+	SeedDeflator uint64   `abi:"seedDeflator_"`
+	ConcGrowth   uint64   `abi:"concGrowth_"`
+}
+
+type PoolParams struct {
+	Schema       uint8  `abi:"schema_"`
+	FeeRate      uint16 `abi:"feeRate_"`
+	ProtocolTake uint8  `abi:"protocolTake_"`
+	TickSize     uint16 `abi:"tickSize_"`
+	JitThresh    uint8  `abi:"jitThresh_"`
+	KnockoutBits uint8  `abi:"knockoutBits_"`
+	OracleFlags  uint8  `abi:"oracleFlags_"`
+}
+
+type level struct {
+	BidLots  *big.Int
+	AskLots  *big.Int
+	Odometer uint64
+}
+
+type curveResp struct {
+	Curve CurveState
+}
+
+type poolParamsResp struct {
+	Pool PoolParams
+}
@@ -123,6 +123,14 @@ func (c *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
 	}
 }
 
+func (c *PoolSimulator) GetVaultAddress() string {
+	return c.VaultAddress
+}
+
+func (c *PoolSimulator) GetPoolId() string {
+	return c.PoolId
+}
+
 func (c *PoolSimulator) GetMetaInfo(tokenIn string, tokenOut string) interface{} {
 	return Meta{
 		VaultAddress:           c.VaultAddress,
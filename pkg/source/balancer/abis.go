@@ -11,6 +11,9 @@ var (
 	balancerPoolABI   abi.ABI
 	stablePoolABI     abi.ABI
 	metaStablePoolABI abi.ABI
+	linearPoolABI     abi.ABI
+	lbpABI            abi.ABI
+	managedPoolABI    abi.ABI
 )
 
 func init() {
@@ -22,6 +25,9 @@ func init() {
 		{&balancerPoolABI, balancerPoolJson},
 		{&stablePoolABI, balancerStablePoolJson},
 		{&metaStablePoolABI, balancerMetaStablePoolJson},
+		{&linearPoolABI, balancerLinearPoolJson},
+		{&lbpABI, balancerLiquidityBootstrappingPoolJson},
+		{&managedPoolABI, balancerManagedPoolJson},
 	}
 
 	for _, b := range builder {
@@ -0,0 +1,12 @@
+package balancerbatch
+
+const (
+	PoolType = "balancer-vault-batch"
+
+	// gasSavingPerIntermediateHop is the gas saved for each intermediate token of a batchSwap compared to
+	// separate swaps: the vault settles the net deltas at the end, so the intermediate tokens are never
+	// transferred out of and back into the vault, and the vault entry overhead is only paid once.
+	gasSavingPerIntermediateHop int64 = 40000
+
+	addressSeparator = "-"
+)
@@ -0,0 +1,12 @@
+package balancerbatch
+
+import "errors"
+
+var (
+	ErrInvalidHops        = errors.New("batch path must have at least 2 hops")
+	ErrDisconnectedHops   = errors.New("token in of a hop must be the token out of the previous hop")
+	ErrDifferentVaults    = errors.New("all hops of a batch path must use the same vault")
+	ErrDuplicatedPool     = errors.New("a pool can only be used once in a batch path")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrInvalidHopAmountIn = errors.New("invalid hop amount in")
+)
@@ -0,0 +1,202 @@
+package balancerbatch
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// PoolSimulator simulates a path of Balancer pools of the same vault which is executed in one batchSwap,
+// e.g. USDC -> bb-a-USDC (linear) -> bb-a-DAI (composable stable) -> DAI (linear). It can only swap from the
+// first token to the last token of the path.
+type PoolSimulator struct {
+	pool.Pool
+	VaultAddress string
+	Hops         []Hop
+}
+
+func NewPoolSimulator(hops []Hop) (*PoolSimulator, error) {
+	if len(hops) < 2 {
+		return nil, ErrInvalidHops
+	}
+
+	var (
+		vaultAddress = strings.ToLower(hops[0].Pool.GetVaultAddress())
+		addresses    = make([]string, 0, len(hops))
+		usedPools    = make(map[string]struct{}, len(hops))
+	)
+	for i, hop := range hops {
+		if hop.Pool.GetTokenIndex(hop.TokenIn) < 0 || hop.Pool.GetTokenIndex(hop.TokenOut) < 0 || hop.TokenIn == hop.TokenOut {
+			return nil, ErrInvalidToken
+		}
+		if i > 0 && hop.TokenIn != hops[i-1].TokenOut {
+			return nil, ErrDisconnectedHops
+		}
+		if !strings.EqualFold(hop.Pool.GetVaultAddress(), vaultAddress) {
+			return nil, ErrDifferentVaults
+		}
+		if _, ok := usedPools[hop.Pool.GetAddress()]; ok {
+			return nil, ErrDuplicatedPool
+		}
+
+		usedPools[hop.Pool.GetAddress()] = struct{}{}
+		addresses = append(addresses, hop.Pool.GetAddress())
+	}
+
+	var first, last = hops[0], hops[len(hops)-1]
+	return &PoolSimulator{
+		Pool: pool.Pool{
+			Info: pool.PoolInfo{
+				Address:  strings.Join(addresses, addressSeparator),
+				SwapFee:  bignumber.ZeroBI,
+				Exchange: first.Pool.GetExchange(),
+				Type:     PoolType,
+				Tokens:   []string{first.TokenIn, last.TokenOut},
+				Reserves: []*big.Int{
+					first.Pool.GetReserves()[first.Pool.GetTokenIndex(first.TokenIn)],
+					last.Pool.GetReserves()[last.Pool.GetTokenIndex(last.TokenOut)],
+				},
+				Checked: false,
+			},
+		},
+		VaultAddress: vaultAddress,
+		Hops:         hops,
+	}, nil
+}
+
+func (t *PoolSimulator) CalcAmountOut(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	if tokenAmountIn.Token != t.Info.Tokens[0] || tokenOut != t.Info.Tokens[1] {
+		return &pool.CalcAmountOutResult{}, ErrInvalidToken
+	}
+
+	var (
+		amountIn = tokenAmountIn.Amount
+		gas      int64
+		hopInfos = make([]HopSwapInfo, 0, len(t.Hops))
+	)
+	for _, hop := range t.Hops {
+		result, err := hop.Pool.CalcAmountOut(pool.TokenAmount{Token: hop.TokenIn, Amount: amountIn}, hop.TokenOut)
+		if err != nil {
+			return &pool.CalcAmountOutResult{}, err
+		}
+		if result.TokenAmountOut == nil || result.TokenAmountOut.Amount == nil || result.TokenAmountOut.Amount.Sign() <= 0 {
+			return &pool.CalcAmountOutResult{}, ErrInvalidHopAmountIn
+		}
+
+		var hopInfo = HopSwapInfo{
+			AmountIn:  amountIn.String(),
+			AmountOut: result.TokenAmountOut.Amount.String(),
+			SwapInfo:  result.SwapInfo,
+		}
+		if result.Fee != nil && result.Fee.Amount != nil {
+			hopInfo.Fee = result.Fee.Amount.String()
+			hopInfo.FeeToken = result.Fee.Token
+		}
+
+		gas += result.Gas
+		hopInfos = append(hopInfos, hopInfo)
+		amountIn = result.TokenAmountOut.Amount
+	}
+
+	var gasSaving = t.gasSaving()
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{
+			Token:  tokenOut,
+			Amount: amountIn,
+		},
+		Fee: &pool.TokenAmount{
+			Token:  tokenAmountIn.Token,
+			Amount: bignumber.ZeroBI,
+		},
+		Gas: gas - gasSaving,
+		SwapInfo: SwapInfo{
+			Hops:      hopInfos,
+			GasSaving: gasSaving,
+		},
+	}, nil
+}
+
+// UpdateBalance replays the swap of each hop on its pool, the hop pools are shared with the other paths so that
+// they are all updated.
+func (t *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	swapInfo, ok := params.SwapInfo.(SwapInfo)
+	if !ok || len(swapInfo.Hops) != len(t.Hops) {
+		return
+	}
+
+	for i, hop := range t.Hops {
+		var hopInfo = swapInfo.Hops[i]
+		var fee = pool.TokenAmount{Token: hopInfo.FeeToken, Amount: bignumber.ZeroBI}
+		if hopInfo.Fee != "" {
+			fee.Amount = bignumber.NewBig10(hopInfo.Fee)
+		}
+
+		hop.Pool.UpdateBalance(pool.UpdateBalanceParams{
+			TokenAmountIn:  pool.TokenAmount{Token: hop.TokenIn, Amount: bignumber.NewBig10(hopInfo.AmountIn)},
+			TokenAmountOut: pool.TokenAmount{Token: hop.TokenOut, Amount: bignumber.NewBig10(hopInfo.AmountOut)},
+			Fee:            fee,
+			SwapInfo:       hopInfo.SwapInfo,
+			Inventory:      params.Inventory,
+		})
+	}
+
+	var first, last = t.Hops[0], t.Hops[len(t.Hops)-1]
+	t.Info.Reserves[0] = first.Pool.GetReserves()[first.Pool.GetTokenIndex(first.TokenIn)]
+	t.Info.Reserves[1] = last.Pool.GetReserves()[last.Pool.GetTokenIndex(last.TokenOut)]
+}
+
+func (t *PoolSimulator) CanSwapTo(address string) []string {
+	if address == t.Info.Tokens[1] {
+		return []string{t.Info.Tokens[0]}
+	}
+	return []string{}
+}
+
+func (t *PoolSimulator) CanSwapFrom(address string) []string {
+	if address == t.Info.Tokens[0] {
+		return []string{t.Info.Tokens[1]}
+	}
+	return []string{}
+}
+
+func (t *PoolSimulator) GetMetaInfo(_ string, _ string) interface{} {
+	var (
+		assets      = make([]string, 0, len(t.Hops)+1)
+		assetIndex  = make(map[string]int, len(t.Hops)+1)
+		getAssetIdx = func(token string) int {
+			if idx, ok := assetIndex[token]; ok {
+				return idx
+			}
+			assetIndex[token] = len(assets)
+			assets = append(assets, token)
+			return assetIndex[token]
+		}
+		swaps = make([]BatchSwapStep, 0, len(t.Hops))
+	)
+	for _, hop := range t.Hops {
+		swaps = append(swaps, BatchSwapStep{
+			PoolId:        hop.Pool.GetPoolId(),
+			AssetInIndex:  getAssetIdx(hop.TokenIn),
+			AssetOutIndex: getAssetIdx(hop.TokenOut),
+		})
+	}
+
+	return Meta{
+		VaultAddress: t.VaultAddress,
+		Swaps:        swaps,
+		Assets:       assets,
+	}
+}
+
+func (t *PoolSimulator) GetVaultAddress() string {
+	return t.VaultAddress
+}
+
+func (t *PoolSimulator) gasSaving() int64 {
+	return int64(len(t.Hops)-1) * gasSavingPerIntermediateHop
+}
@@ -0,0 +1,155 @@
+package balancerbatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	balancercomposablestable "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/balancer-composable-stable"
+	balancerlinear "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/balancer/linear"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	vault   = "0xba12222222228d8ba445958a75a0704d566bf2c8"
+	bbaUSDC = "0x60d604890feaa0b5460b28a424407c24fe89374a"
+	bbaUSD  = "0x9001cbbd96f54a658ff4e6e65ab564ded76a5431"
+	bbaDAI  = "0xbe9895146f7af43049ca1c1ae358b0541ea49704"
+)
+
+func newLinearPool(t *testing.T, main, wrapped, bpt string) *balancerlinear.PoolSimulator {
+	p, err := balancerlinear.NewPoolSimulator(entity.Pool{
+		Address:  bpt,
+		SwapFee:  0.002,
+		Exchange: "balancer",
+		Type:     "balancer-linear",
+		Reserves: entity.PoolReserves{"2500000000000000000000000", "1000000000000000000000000", "5192296858534827628530496329220095"},
+		Tokens:   []*entity.PoolToken{{Address: main}, {Address: wrapped}, {Address: bpt}},
+		Extra: `{"scalingFactors": [1000000000000000000, 1100000000000000000, 1000000000000000000],
+			"lowerTarget": 2000000000000000000000000, "upperTarget": 3000000000000000000000000,
+			"virtualSupply": 3600000000000000000000000, "mainIndex": 0, "wrappedIndex": 1, "bptIndex": 2}`,
+		StaticExtra: `{"vaultAddress": "` + vault + `", "poolId": "` + bpt + `000000000000000000000001", "tokenDecimals": [18, 18, 18]}`,
+	})
+	require.Nil(t, err)
+	return p
+}
+
+func newComposableStablePool(t *testing.T) *balancercomposablestable.PoolSimulator {
+	p, err := balancercomposablestable.NewPoolSimulator(entity.Pool{
+		Address:     bbaUSD,
+		SwapFee:     0.000001,
+		Exchange:    "balancer",
+		Type:        "balancer-composable-stable",
+		Reserves:    entity.PoolReserves{"2518960237189623226641", "2596148429266323438822175768385755", "3457262534881651304610"},
+		Tokens:      []*entity.PoolToken{{Address: bbaUSDC}, {Address: bbaUSD}, {Address: bbaDAI}},
+		Extra:       "{\"amplificationParameter\":{\"value\":700000,\"isUpdating\":false,\"precision\":1000},\"scalingFactors\":[1003649423771917631,1000000000000000000,1043680240732074966],\"bptIndex\":1,\"actualSupply\":6105781862789255176406,\"lastJoinExit\":{\"LastJoinExitAmplification\":700000,\"LastPostJoinExitInvariant\":6135006746648647084879},\"rateProviders\":[\"0x60d604890feaa0b5460b28a424407c24fe89374a\",\"0x0000000000000000000000000000000000000000\",\"0x7311e4bb8a72e7b300c5b8bde4de6cdaa822a5b1\"],\"tokensExemptFromYieldProtocolFee\":[false,false,false],\"tokenRateCaches\":[{\"Rate\":1003649423771917631,\"OldRate\":1003554274984131981,\"Duration\":21600,\"Expires\":1689845039},{\"Rate\":null,\"OldRate\":null,\"Duration\":null,\"Expires\":null},{\"Rate\":1043680240732074966,\"OldRate\":1043375386816533719,\"Duration\":21600,\"Expires\":1689845039}],\"protocolFeePercentageCacheSwapType\":0,\"protocolFeePercentageCacheYieldType\":0}",
+		StaticExtra: `{"vaultAddress": "` + vault + `", "poolId": "0x9001cbbd96f54a658ff4e6e65ab564ded76a543100000000000000000000050a", "tokenDecimals": [18, 18, 18]}`,
+		TotalSupply: "2596148429272429220684965023562161",
+	})
+	require.Nil(t, err)
+	return p
+}
+
+func newBoostedPath(t *testing.T) (*PoolSimulator, []IVaultPool) {
+	pools := []IVaultPool{
+		newLinearPool(t, "USDC", "aUSDC", bbaUSDC),
+		newComposableStablePool(t),
+		newLinearPool(t, "DAI", "aDAI", bbaDAI),
+	}
+	p, err := NewPoolSimulator([]Hop{
+		{Pool: pools[0], TokenIn: "USDC", TokenOut: bbaUSDC},
+		{Pool: pools[1], TokenIn: bbaUSDC, TokenOut: bbaDAI},
+		{Pool: pools[2], TokenIn: bbaDAI, TokenOut: "DAI"},
+	})
+	require.Nil(t, err)
+	return p, pools
+}
+
+func TestCalcAmountOut(t *testing.T) {
+	p, pools := newBoostedPath(t)
+	assert.Equal(t, []string{"USDC"}, p.CanSwapTo("DAI"))
+	assert.Equal(t, 0, len(p.CanSwapTo("USDC")))
+
+	amountIn := bignumber.NewBig10("10000000000000000000")
+	result, err := p.CalcAmountOut(pool.TokenAmount{Token: "USDC", Amount: amountIn}, "DAI")
+	require.Nil(t, err)
+
+	// the result must be the same as swapping through the hops one by one
+	var (
+		tokens   = []string{"USDC", bbaUSDC, bbaDAI, "DAI"}
+		amount   = amountIn
+		totalGas int64
+	)
+	for i, hopPool := range pools {
+		hopResult, err := hopPool.CalcAmountOut(pool.TokenAmount{Token: tokens[i], Amount: amount}, tokens[i+1])
+		require.Nil(t, err)
+		amount = hopResult.TokenAmountOut.Amount
+		totalGas += hopResult.Gas
+	}
+	assert.Equal(t, amount, result.TokenAmountOut.Amount)
+	assert.Equal(t, 2*gasSavingPerIntermediateHop, result.SwapInfo.(SwapInfo).GasSaving)
+	assert.Equal(t, totalGas-2*gasSavingPerIntermediateHop, result.Gas)
+
+	assert.Equal(t, Meta{
+		VaultAddress: vault,
+		Swaps: []BatchSwapStep{
+			{PoolId: bbaUSDC + "000000000000000000000001", AssetInIndex: 0, AssetOutIndex: 1},
+			{PoolId: "0x9001cbbd96f54a658ff4e6e65ab564ded76a543100000000000000000000050a", AssetInIndex: 1, AssetOutIndex: 2},
+			{PoolId: bbaDAI + "000000000000000000000001", AssetInIndex: 2, AssetOutIndex: 3},
+		},
+		Assets: tokens,
+	}, p.GetMetaInfo("USDC", "DAI"))
+
+	_, err = p.CalcAmountOut(pool.TokenAmount{Token: "DAI", Amount: amountIn}, "USDC")
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestUpdateBalance(t *testing.T) {
+	p, pools := newBoostedPath(t)
+	amountIn := bignumber.NewBig10("10000000000000000000")
+	result, err := p.CalcAmountOut(pool.TokenAmount{Token: "USDC", Amount: amountIn}, "DAI")
+	require.Nil(t, err)
+
+	p.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  pool.TokenAmount{Token: "USDC", Amount: amountIn},
+		TokenAmountOut: *result.TokenAmountOut,
+		Fee:            *result.Fee,
+		SwapInfo:       result.SwapInfo,
+	})
+
+	hops := result.SwapInfo.(SwapInfo).Hops
+	assert.Equal(t, "2500010000000000000000000", p.Info.Reserves[0].String())
+	assert.Equal(t, "2500010000000000000000000", pools[0].GetReserves()[0].String())
+	assert.Equal(t, "3600010000000000000000000", pools[0].(*balancerlinear.PoolSimulator).VirtualSupply.String())
+	assert.Equal(t, hops[2].AmountIn, hops[1].AmountOut)
+	assert.Equal(t, p.Info.Reserves[1], pools[2].GetReserves()[0])
+}
+
+func TestNewPoolSimulator(t *testing.T) {
+	_, pools := newBoostedPath(t)
+
+	_, err := NewPoolSimulator([]Hop{{Pool: pools[0], TokenIn: "USDC", TokenOut: bbaUSDC}})
+	assert.Equal(t, ErrInvalidHops, err)
+
+	_, err = NewPoolSimulator([]Hop{
+		{Pool: pools[0], TokenIn: "USDC", TokenOut: bbaUSDC},
+		{Pool: pools[2], TokenIn: bbaDAI, TokenOut: "DAI"},
+	})
+	assert.Equal(t, ErrDisconnectedHops, err)
+
+	_, err = NewPoolSimulator([]Hop{
+		{Pool: pools[0], TokenIn: "USDC", TokenOut: bbaUSDC},
+		{Pool: pools[0], TokenIn: bbaUSDC, TokenOut: "aUSDC"},
+	})
+	assert.Equal(t, ErrDuplicatedPool, err)
+
+	pools[1].(*balancercomposablestable.PoolSimulator).VaultAddress = "0x0000000000000000000000000000000000000001"
+	_, err = NewPoolSimulator([]Hop{
+		{Pool: pools[0], TokenIn: "USDC", TokenOut: bbaUSDC},
+		{Pool: pools[1], TokenIn: bbaUSDC, TokenOut: bbaDAI},
+	})
+	assert.Equal(t, ErrDifferentVaults, err)
+}
@@ -0,0 +1,46 @@
+package balancerbatch
+
+import (
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// IVaultPool is a pool whose swaps are executed by a Balancer vault
+type IVaultPool interface {
+	pool.IPoolSimulator
+	GetVaultAddress() string
+	GetPoolId() string
+}
+
+type Hop struct {
+	Pool     IVaultPool
+	TokenIn  string
+	TokenOut string
+}
+
+type HopSwapInfo struct {
+	AmountIn  string      `json:"amountIn"`
+	AmountOut string      `json:"amountOut"`
+	Fee       string      `json:"fee"`
+	FeeToken  string      `json:"feeToken"`
+	SwapInfo  interface{} `json:"swapInfo,omitempty"`
+}
+
+type SwapInfo struct {
+	Hops []HopSwapInfo `json:"hops"`
+	// GasSaving is the gas saved by executing the hops in one batchSwap instead of separate swaps
+	GasSaving int64 `json:"gasSaving"`
+}
+
+// BatchSwapStep is the BatchSwapStep struct of the vault, the amount is only set for the first step,
+// the other steps use 0 so that the vault uses the amount out of the previous step.
+type BatchSwapStep struct {
+	PoolId        string `json:"poolId"`
+	AssetInIndex  int    `json:"assetInIndex"`
+	AssetOutIndex int    `json:"assetOutIndex"`
+}
+
+type Meta struct {
+	VaultAddress string          `json:"vault"`
+	Swaps        []BatchSwapStep `json:"swaps"`
+	Assets       []string        `json:"assets"`
+}
@@ -12,12 +12,19 @@ const (
 	// DexTypeBalancer is used to detect all types of balancer pools
 	DexTypeBalancer = "balancer"
 
-	subgraphPoolTypeWeighted   PoolType = "Weighted"
-	subgraphPoolTypeStable     PoolType = "Stable"
-	subgraphPoolTypeMetaStable PoolType = "MetaStable"
-	DexTypeBalancerWeighted    DexType  = "balancer-weighted"
-	DexTypeBalancerStable      DexType  = "balancer-stable"
-	DexTypeBalancerMetaStable  DexType  = "balancer-meta-stable"
+	subgraphPoolTypeWeighted      PoolType = "Weighted"
+	subgraphPoolTypeStable        PoolType = "Stable"
+	subgraphPoolTypeMetaStable    PoolType = "MetaStable"
+	subgraphPoolTypeAaveLinear    PoolType = "AaveLinear"
+	subgraphPoolTypeERC4626Linear PoolType = "ERC4626Linear"
+	subgraphPoolTypeLBP           PoolType = "LiquidityBootstrapping"
+	subgraphPoolTypeManaged       PoolType = "Managed"
+	DexTypeBalancerWeighted       DexType  = "balancer-weighted"
+	DexTypeBalancerStable         DexType  = "balancer-stable"
+	DexTypeBalancerMetaStable     DexType  = "balancer-meta-stable"
+	DexTypeBalancerLinear         DexType  = "balancer-linear"
+	DexTypeBalancerLBP            DexType  = "balancer-liquidity-bootstrapping"
+	DexTypeBalancerManaged        DexType  = "balancer-managed"
 
 	graphQLRequestTimeout = 20 * time.Second
 
@@ -30,18 +37,33 @@ const (
 	vaultMethodGetPoolTokens = "getPoolTokens"
 
 	// poolMethodGetVault to get vault of a pool
-	poolMethodGetVault                    = "getVault"
-	poolMethodGetSwapFeePercentage        = "getSwapFeePercentage"
-	poolMethodGetAmplificationParameter   = "getAmplificationParameter"
-	metaStablePoolMethodGetScalingFactors = "getScalingFactors"
+	poolMethodGetVault                     = "getVault"
+	poolMethodGetSwapFeePercentage         = "getSwapFeePercentage"
+	poolMethodGetAmplificationParameter    = "getAmplificationParameter"
+	metaStablePoolMethodGetScalingFactors  = "getScalingFactors"
+	linearPoolMethodGetTargets             = "getTargets"
+	linearPoolMethodGetVirtualSupply       = "getVirtualSupply"
+	linearPoolMethodGetMainIndex           = "getMainIndex"
+	linearPoolMethodGetWrappedIndex        = "getWrappedIndex"
+	linearPoolMethodGetBptIndex            = "getBptIndex"
+	poolMethodGetGradualWeightUpdateParams = "getGradualWeightUpdateParams"
+	poolMethodGetNormalizedWeights         = "getNormalizedWeights"
+	poolMethodGetSwapEnabled               = "getSwapEnabled"
+
+	// bptDecimals is the decimals of the pool token, which is registered as a token of the linear pools
+	bptDecimals = 18
 )
 
 var (
 	// dexTypeByPoolType Add more types of pool here when we integrate a new type of Balancer
 	dexTypeByPoolType = map[PoolType]DexType{
-		subgraphPoolTypeWeighted:   DexTypeBalancerWeighted,
-		subgraphPoolTypeStable:     DexTypeBalancerStable,
-		subgraphPoolTypeMetaStable: DexTypeBalancerMetaStable,
+		subgraphPoolTypeWeighted:      DexTypeBalancerWeighted,
+		subgraphPoolTypeStable:        DexTypeBalancerStable,
+		subgraphPoolTypeMetaStable:    DexTypeBalancerMetaStable,
+		subgraphPoolTypeAaveLinear:    DexTypeBalancerLinear,
+		subgraphPoolTypeERC4626Linear: DexTypeBalancerLinear,
+		subgraphPoolTypeLBP:           DexTypeBalancerLBP,
+		subgraphPoolTypeManaged:       DexTypeBalancerManaged,
 	}
 
 	zeroBI       = big.NewInt(0)
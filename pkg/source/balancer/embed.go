@@ -13,3 +13,12 @@ var balancerStablePoolJson []byte
 
 //go:embed abis/MetaStablePool.json
 var balancerMetaStablePoolJson []byte
+
+//go:embed abis/LinearPool.json
+var balancerLinearPoolJson []byte
+
+//go:embed abis/LiquidityBootstrappingPool.json
+var balancerLiquidityBootstrappingPoolJson []byte
+
+//go:embed abis/ManagedPool.json
+var balancerManagedPoolJson []byte
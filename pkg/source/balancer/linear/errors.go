@@ -0,0 +1,9 @@
+package balancerlinear
+
+import "errors"
+
+var (
+	ErrInvalidToken            = errors.New("invalid token")
+	ErrInvalidAmountOut        = errors.New("invalid amount out")
+	ErrExchangeMoreThanBalance = errors.New("exchange more than balance")
+)
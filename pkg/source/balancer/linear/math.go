@@ -0,0 +1,193 @@
+package balancerlinear
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// Solidity code:
+// https://github.com/balancer-labs/balancer-v2-monorepo/blob/master/pkg/pool-linear/contracts/LinearMath.sol
+
+// params are the fee and the targets of the pool, the targets are upscaled like the main balance
+type params struct {
+	fee         *big.Int
+	lowerTarget *big.Int
+	upperTarget *big.Int
+}
+
+func mulDown(a *big.Int, b *big.Int) *big.Int {
+	return new(big.Int).Div(new(big.Int).Mul(a, b), bignumber.BONE)
+}
+
+func divDown(a *big.Int, b *big.Int) *big.Int {
+	if a.Sign() == 0 {
+		return bignumber.ZeroBI
+	}
+	return new(big.Int).Div(new(big.Int).Mul(a, bignumber.BONE), b)
+}
+
+// mathDivUp is Math.divUp, it does not scale a by ONE
+func mathDivUp(a *big.Int, b *big.Int) *big.Int {
+	if a.Sign() == 0 {
+		return bignumber.ZeroBI
+	}
+	return new(big.Int).Add(new(big.Int).Div(new(big.Int).Sub(a, bignumber.One), b), bignumber.One)
+}
+
+func _upscale(amount *big.Int, scalingFactor *big.Int) *big.Int {
+	return mulDown(amount, scalingFactor)
+}
+
+func _downscaleDown(amount *big.Int, scalingFactor *big.Int) *big.Int {
+	return divDown(amount, scalingFactor)
+}
+
+func _calcBptOutPerMainIn(mainIn, mainBalance, wrappedBalance, bptSupply *big.Int, p params) *big.Int {
+	// Amount out, so we round down overall.
+	if bptSupply.Sign() == 0 {
+		// Return nominal DAI
+		return _toNominal(mainIn, p)
+	}
+
+	var previousNominalMain = _toNominal(mainBalance, p)
+	var afterNominalMain = _toNominal(new(big.Int).Add(mainBalance, mainIn), p)
+	var deltaNominalMain = new(big.Int).Sub(afterNominalMain, previousNominalMain)
+	var invariant = _calcInvariant(previousNominalMain, wrappedBalance)
+	return new(big.Int).Div(new(big.Int).Mul(bptSupply, deltaNominalMain), invariant)
+}
+
+func _calcBptInPerMainOut(mainOut, mainBalance, wrappedBalance, bptSupply *big.Int, p params) *big.Int {
+	// Amount in, so we round up overall.
+	var previousNominalMain = _toNominal(mainBalance, p)
+	var afterNominalMain = _toNominal(new(big.Int).Sub(mainBalance, mainOut), p)
+	var deltaNominalMain = new(big.Int).Sub(previousNominalMain, afterNominalMain)
+	var invariant = _calcInvariant(previousNominalMain, wrappedBalance)
+	return mathDivUp(new(big.Int).Mul(bptSupply, deltaNominalMain), invariant)
+}
+
+func _calcWrappedOutPerMainIn(mainIn, mainBalance *big.Int, p params) *big.Int {
+	// Amount out, so we round down overall.
+	var previousNominalMain = _toNominal(mainBalance, p)
+	var afterNominalMain = _toNominal(new(big.Int).Add(mainBalance, mainIn), p)
+	return new(big.Int).Sub(afterNominalMain, previousNominalMain)
+}
+
+func _calcWrappedInPerMainOut(mainOut, mainBalance *big.Int, p params) *big.Int {
+	// Amount in, so we round up overall.
+	var previousNominalMain = _toNominal(mainBalance, p)
+	var afterNominalMain = _toNominal(new(big.Int).Sub(mainBalance, mainOut), p)
+	return new(big.Int).Sub(previousNominalMain, afterNominalMain)
+}
+
+func _calcMainInPerBptOut(bptOut, mainBalance, wrappedBalance, bptSupply *big.Int, p params) *big.Int {
+	// Amount in, so we round up overall.
+	if bptSupply.Sign() == 0 {
+		// Return nominal DAI
+		return _fromNominal(bptOut, p)
+	}
+
+	var previousNominalMain = _toNominal(mainBalance, p)
+	var invariant = _calcInvariant(previousNominalMain, wrappedBalance)
+	var deltaNominalMain = mathDivUp(new(big.Int).Mul(invariant, bptOut), bptSupply)
+	var afterNominalMain = new(big.Int).Add(previousNominalMain, deltaNominalMain)
+	var newMainBalance = _fromNominal(afterNominalMain, p)
+	return new(big.Int).Sub(newMainBalance, mainBalance)
+}
+
+func _calcMainOutPerBptIn(bptIn, mainBalance, wrappedBalance, bptSupply *big.Int, p params) *big.Int {
+	// Amount out, so we round down overall.
+	var previousNominalMain = _toNominal(mainBalance, p)
+	var invariant = _calcInvariant(previousNominalMain, wrappedBalance)
+	var deltaNominalMain = new(big.Int).Div(new(big.Int).Mul(invariant, bptIn), bptSupply)
+	var afterNominalMain = new(big.Int).Sub(previousNominalMain, deltaNominalMain)
+	var newMainBalance = _fromNominal(afterNominalMain, p)
+	return new(big.Int).Sub(mainBalance, newMainBalance)
+}
+
+func _calcMainOutPerWrappedIn(wrappedIn, mainBalance *big.Int, p params) *big.Int {
+	// Amount out, so we round down overall.
+	var previousNominalMain = _toNominal(mainBalance, p)
+	var afterNominalMain = new(big.Int).Sub(previousNominalMain, wrappedIn)
+	var newMainBalance = _fromNominal(afterNominalMain, p)
+	return new(big.Int).Sub(mainBalance, newMainBalance)
+}
+
+func _calcMainInPerWrappedOut(wrappedOut, mainBalance *big.Int, p params) *big.Int {
+	// Amount in, so we round up overall.
+	var previousNominalMain = _toNominal(mainBalance, p)
+	var afterNominalMain = new(big.Int).Add(previousNominalMain, wrappedOut)
+	var newMainBalance = _fromNominal(afterNominalMain, p)
+	return new(big.Int).Sub(newMainBalance, mainBalance)
+}
+
+func _calcBptOutPerWrappedIn(wrappedIn, mainBalance, wrappedBalance, bptSupply *big.Int, p params) *big.Int {
+	// Amount out, so we round down overall.
+	if bptSupply.Sign() == 0 {
+		// Return nominal DAI
+		return wrappedIn
+	}
+
+	var nominalMain = _toNominal(mainBalance, p)
+	var previousInvariant = _calcInvariant(nominalMain, wrappedBalance)
+	return new(big.Int).Div(new(big.Int).Mul(bptSupply, wrappedIn), previousInvariant)
+}
+
+func _calcBptInPerWrappedOut(wrappedOut, mainBalance, wrappedBalance, bptSupply *big.Int, p params) *big.Int {
+	// Amount in, so we round up overall.
+	var nominalMain = _toNominal(mainBalance, p)
+	var previousInvariant = _calcInvariant(nominalMain, wrappedBalance)
+	return mathDivUp(new(big.Int).Mul(bptSupply, wrappedOut), previousInvariant)
+}
+
+func _calcWrappedInPerBptOut(bptOut, mainBalance, wrappedBalance, bptSupply *big.Int, p params) *big.Int {
+	// Amount in, so we round up overall.
+	if bptSupply.Sign() == 0 {
+		// Return nominal DAI
+		return bptOut
+	}
+
+	var nominalMain = _toNominal(mainBalance, p)
+	var previousInvariant = _calcInvariant(nominalMain, wrappedBalance)
+	return mathDivUp(new(big.Int).Mul(previousInvariant, bptOut), bptSupply)
+}
+
+func _calcWrappedOutPerBptIn(bptIn, mainBalance, wrappedBalance, bptSupply *big.Int, p params) *big.Int {
+	// Amount out, so we round down overall.
+	var nominalMain = _toNominal(mainBalance, p)
+	var previousInvariant = _calcInvariant(nominalMain, wrappedBalance)
+	return new(big.Int).Div(new(big.Int).Mul(previousInvariant, bptIn), bptSupply)
+}
+
+func _calcInvariant(nominalMainBalance, wrappedBalance *big.Int) *big.Int {
+	return new(big.Int).Add(nominalMainBalance, wrappedBalance)
+}
+
+func _toNominal(real *big.Int, p params) *big.Int {
+	// Fees are always rounded down: either direction would work but we need to be consistent, and rounding down
+	// uses less gas.
+	if real.Cmp(p.lowerTarget) < 0 {
+		var fees = mulDown(new(big.Int).Sub(p.lowerTarget, real), p.fee)
+		return new(big.Int).Sub(real, fees)
+	} else if real.Cmp(p.upperTarget) <= 0 {
+		return real
+	}
+	var fees = mulDown(new(big.Int).Sub(real, p.upperTarget), p.fee)
+	return new(big.Int).Sub(real, fees)
+}
+
+func _fromNominal(nominal *big.Int, p params) *big.Int {
+	// Since real = nominal + fees, rounding down fees is equivalent to rounding down real.
+	if nominal.Cmp(p.lowerTarget) < 0 {
+		return divDown(
+			new(big.Int).Add(nominal, mulDown(p.fee, p.lowerTarget)),
+			new(big.Int).Add(bignumber.BONE, p.fee),
+		)
+	} else if nominal.Cmp(p.upperTarget) <= 0 {
+		return nominal
+	}
+	return divDown(
+		new(big.Int).Sub(nominal, mulDown(p.fee, p.upperTarget)),
+		new(big.Int).Sub(bignumber.BONE, p.fee),
+	)
+}
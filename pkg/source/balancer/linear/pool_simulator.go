@@ -0,0 +1,204 @@
+package balancerlinear
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/balancer"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// PoolSimulator simulates the Aave/ERC4626 linear pools. A linear pool has 3 tokens: the main token, the wrapped
+// token and its own BPT, swapping between them has no explicit fee, the fee is charged when the main balance
+// is moved out of the [lowerTarget, upperTarget] range.
+type PoolSimulator struct {
+	pool.Pool
+	VaultAddress   string
+	PoolId         string
+	ScalingFactors []*big.Int
+	LowerTarget    *big.Int
+	UpperTarget    *big.Int
+	VirtualSupply  *big.Int
+	MainIndex      int
+	WrappedIndex   int
+	BptIndex       int
+	gas            balancer.Gas
+}
+
+func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
+	var staticExtra balancer.StaticExtra
+	if err := json.Unmarshal([]byte(entityPool.StaticExtra), &staticExtra); err != nil {
+		return nil, err
+	}
+
+	var extra balancer.LinearPoolExtra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	numTokens := len(entityPool.Tokens)
+	if len(extra.ScalingFactors) != numTokens || !isValidIndex(extra.MainIndex, numTokens) ||
+		!isValidIndex(extra.WrappedIndex, numTokens) || !isValidIndex(extra.BptIndex, numTokens) ||
+		extra.LowerTarget == nil || extra.UpperTarget == nil || extra.VirtualSupply == nil {
+		return nil, ErrInvalidToken
+	}
+
+	swapFeeFl := new(big.Float).Mul(big.NewFloat(entityPool.SwapFee), bignumber.BoneFloat)
+	swapFee, _ := swapFeeFl.Int(nil)
+	tokens := make([]string, numTokens)
+	reserves := make([]*big.Int, numTokens)
+
+	for i := 0; i < numTokens; i += 1 {
+		tokens[i] = entityPool.Tokens[i].Address
+		reserves[i] = bignumber.NewBig10(entityPool.Reserves[i])
+	}
+
+	return &PoolSimulator{
+		Pool: pool.Pool{
+			Info: pool.PoolInfo{
+				Address:    entityPool.Address,
+				ReserveUsd: entityPool.ReserveUsd,
+				SwapFee:    swapFee,
+				Exchange:   entityPool.Exchange,
+				Type:       entityPool.Type,
+				Tokens:     tokens,
+				Reserves:   reserves,
+				Checked:    false,
+			},
+		},
+		VaultAddress:   strings.ToLower(staticExtra.VaultAddress),
+		PoolId:         strings.ToLower(staticExtra.PoolId),
+		ScalingFactors: extra.ScalingFactors,
+		LowerTarget:    extra.LowerTarget,
+		UpperTarget:    extra.UpperTarget,
+		VirtualSupply:  extra.VirtualSupply,
+		MainIndex:      extra.MainIndex,
+		WrappedIndex:   extra.WrappedIndex,
+		BptIndex:       extra.BptIndex,
+		gas:            balancer.DefaultGas,
+	}, nil
+}
+
+func (t *PoolSimulator) CalcAmountOut(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	var tokenIndexFrom = t.GetTokenIndex(tokenAmountIn.Token)
+	var tokenIndexTo = t.GetTokenIndex(tokenOut)
+	if tokenIndexFrom < 0 || tokenIndexTo < 0 || tokenIndexFrom == tokenIndexTo {
+		return &pool.CalcAmountOutResult{}, ErrInvalidToken
+	}
+
+	var amountIn = _upscale(tokenAmountIn.Amount, t.ScalingFactors[tokenIndexFrom])
+	var amountOut = t._onSwapGivenIn(tokenIndexFrom, tokenIndexTo, amountIn)
+	if amountOut == nil {
+		return &pool.CalcAmountOutResult{}, ErrInvalidToken
+	}
+
+	amountOut = _downscaleDown(amountOut, t.ScalingFactors[tokenIndexTo])
+	if amountOut.Sign() <= 0 {
+		return &pool.CalcAmountOutResult{}, ErrInvalidAmountOut
+	}
+	if amountOut.Cmp(t.Info.Reserves[tokenIndexTo]) > 0 {
+		return &pool.CalcAmountOutResult{}, ErrExchangeMoreThanBalance
+	}
+
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{
+			Token:  tokenOut,
+			Amount: amountOut,
+		},
+		Fee: &pool.TokenAmount{
+			Token:  tokenAmountIn.Token,
+			Amount: bignumber.ZeroBI,
+		},
+		Gas: t.gas.Swap,
+	}, nil
+}
+
+// _onSwapGivenIn returns the upscaled amount out, or nil if the pair of tokens can not be swapped
+func (t *PoolSimulator) _onSwapGivenIn(indexIn, indexOut int, amountIn *big.Int) *big.Int {
+	var mainBalance = _upscale(t.Info.Reserves[t.MainIndex], t.ScalingFactors[t.MainIndex])
+	var wrappedBalance = _upscale(t.Info.Reserves[t.WrappedIndex], t.ScalingFactors[t.WrappedIndex])
+	var p = params{
+		fee:         t.Info.SwapFee,
+		lowerTarget: _upscale(t.LowerTarget, t.ScalingFactors[t.MainIndex]),
+		upperTarget: _upscale(t.UpperTarget, t.ScalingFactors[t.MainIndex]),
+	}
+
+	switch indexIn {
+	case t.MainIndex:
+		switch indexOut {
+		case t.BptIndex:
+			return _calcBptOutPerMainIn(amountIn, mainBalance, wrappedBalance, t.VirtualSupply, p)
+		case t.WrappedIndex:
+			return _calcWrappedOutPerMainIn(amountIn, mainBalance, p)
+		}
+	case t.WrappedIndex:
+		switch indexOut {
+		case t.BptIndex:
+			return _calcBptOutPerWrappedIn(amountIn, mainBalance, wrappedBalance, t.VirtualSupply, p)
+		case t.MainIndex:
+			return _calcMainOutPerWrappedIn(amountIn, mainBalance, p)
+		}
+	case t.BptIndex:
+		if t.VirtualSupply.Sign() == 0 {
+			return nil
+		}
+		switch indexOut {
+		case t.MainIndex:
+			return _calcMainOutPerBptIn(amountIn, mainBalance, wrappedBalance, t.VirtualSupply, p)
+		case t.WrappedIndex:
+			return _calcWrappedOutPerBptIn(amountIn, mainBalance, wrappedBalance, t.VirtualSupply, p)
+		}
+	}
+
+	return nil
+}
+
+func (t *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	input, output := params.TokenAmountIn, params.TokenAmountOut
+	var tokenInIndex = t.GetTokenIndex(input.Token)
+	var tokenOutIndex = t.GetTokenIndex(output.Token)
+	if tokenInIndex >= 0 {
+		t.Info.Reserves[tokenInIndex] = new(big.Int).Add(t.Info.Reserves[tokenInIndex], input.Amount)
+	}
+	if tokenOutIndex >= 0 {
+		t.Info.Reserves[tokenOutIndex] = new(big.Int).Sub(t.Info.Reserves[tokenOutIndex], output.Amount)
+	}
+
+	// The BPT held by the vault is not in circulation, the virtual supply changes when it is swapped
+	if tokenInIndex == t.BptIndex {
+		t.VirtualSupply = new(big.Int).Sub(t.VirtualSupply, input.Amount)
+	}
+	if tokenOutIndex == t.BptIndex {
+		t.VirtualSupply = new(big.Int).Add(t.VirtualSupply, output.Amount)
+	}
+}
+
+func (t *PoolSimulator) GetMetaInfo(tokenIn string, tokenOut string) interface{} {
+	mapTokenAddressToIndex := make(map[string]int)
+	for idx, tokenAddress := range t.Pool.Info.Tokens {
+		mapTokenAddressToIndex[tokenAddress] = idx
+	}
+	return balancer.Meta{
+		VaultAddress:           t.VaultAddress,
+		PoolId:                 t.PoolId,
+		MapTokenAddressToIndex: mapTokenAddressToIndex,
+	}
+}
+
+func (t *PoolSimulator) GetVaultAddress() string {
+	return t.VaultAddress
+}
+
+func (t *PoolSimulator) GetPoolId() string {
+	return t.PoolId
+}
+
+func isValidIndex(index, numTokens int) bool {
+	return index >= 0 && index < numTokens
+}
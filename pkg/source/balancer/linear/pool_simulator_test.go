@@ -0,0 +1,83 @@
+package balancerlinear
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+func newTestPool(t *testing.T) *PoolSimulator {
+	// main: 6 decimals, wrapped: 6 decimals with rate 1.1, targets: [2M, 3M], swap fee: 0.2%
+	p, err := NewPoolSimulator(entity.Pool{
+		Address:  "LP",
+		SwapFee:  0.002,
+		Exchange: "balancer",
+		Type:     "balancer-linear",
+		Reserves: entity.PoolReserves{"2500000000000", "1000000000000", "5192296858534827628530496329220095"},
+		Tokens:   []*entity.PoolToken{{Address: "USDC"}, {Address: "aUSDC"}, {Address: "LP"}},
+		Extra: `{"scalingFactors": [1000000000000000000000000000000, 1100000000000000000000000000000, 1000000000000000000],
+			"lowerTarget": 2000000000000, "upperTarget": 3000000000000, "virtualSupply": 3600000000000000000000000,
+			"mainIndex": 0, "wrappedIndex": 1, "bptIndex": 2}`,
+		StaticExtra: `{"vaultAddress": "0xBA12222222228d8Ba445958a75a0704d566BF2C8", "poolId": "0xPOOL", "tokenDecimals": [6, 6, 18]}`,
+	})
+	require.Nil(t, err)
+	return p
+}
+
+func TestCalcAmountOut(t *testing.T) {
+	testcases := []struct {
+		in                string
+		inAmount          string
+		out               string
+		expectedOutAmount string
+	}{
+		// the main balance is moved above the upper target, 0.2% of the excess (500k) is charged
+		{"USDC", "1000000000000", "LP", "999000000000000000000000"},
+		{"USDC", "1000000000000", "aUSDC", "908181818181"},
+		// the main balance is moved below the lower target
+		{"aUSDC", "500000000000", "USDC", "549900199600"},
+		{"aUSDC", "500000000000", "LP", "550000000000000000000000"},
+		{"LP", "100000000000000000000000", "USDC", "100000000000"},
+		{"LP", "100000000000000000000000", "aUSDC", "90909090909"},
+	}
+	p := newTestPool(t)
+
+	for _, tc := range testcases {
+		t.Run(tc.in+" -> "+tc.out, func(t *testing.T) {
+			out, err := p.CalcAmountOut(pool.TokenAmount{Token: tc.in, Amount: bignumber.NewBig10(tc.inAmount)}, tc.out)
+			require.Nil(t, err)
+			assert.Equal(t, tc.expectedOutAmount, out.TokenAmountOut.Amount.String())
+		})
+	}
+
+	_, err := p.CalcAmountOut(pool.TokenAmount{Token: "LP", Amount: bignumber.NewBig10("3000000000000000000000000")}, "USDC")
+	assert.Equal(t, ErrExchangeMoreThanBalance, err)
+	_, err = p.CalcAmountOut(pool.TokenAmount{Token: "USDC", Amount: bignumber.NewBig10("1")}, "USDC")
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestUpdateBalance(t *testing.T) {
+	p := newTestPool(t)
+	amountIn := bignumber.NewBig10("1000000000000")
+	out, err := p.CalcAmountOut(pool.TokenAmount{Token: "USDC", Amount: amountIn}, "LP")
+	require.Nil(t, err)
+
+	p.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  pool.TokenAmount{Token: "USDC", Amount: amountIn},
+		TokenAmountOut: *out.TokenAmountOut,
+		Fee:            *out.Fee,
+	})
+
+	assert.Equal(t, "3500000000000", p.Info.Reserves[0].String())
+	assert.Equal(t, "4599000000000000000000000", p.VirtualSupply.String())
+
+	// the fee is charged on the nominal balance, so the BPT is still worth 1 nominal main token
+	out, err = p.CalcAmountOut(pool.TokenAmount{Token: "LP", Amount: bignumber.NewBig10("100000000000000000000000")}, "aUSDC")
+	require.Nil(t, err)
+	assert.Equal(t, "90909090909", out.TokenAmountOut.Amount.String())
+}
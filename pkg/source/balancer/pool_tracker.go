@@ -53,6 +53,15 @@ func (d *PoolTracker) GetNewPoolState(
 		amplificationParameter AmplificationParameter
 		scalingFactors         []*big.Int
 		swapFeePercentage      *big.Int
+		linearTargets          LinearTargets
+		virtualSupply          *big.Int
+		mainIndex              *big.Int
+		wrappedIndex           *big.Int
+		bptIndex               *big.Int
+		normalizedWeights      []*big.Int
+		swapEnabled            bool
+		lbpWeightParams        LBPGradualWeightUpdateParams
+		managedWeightParams    ManagedPoolGradualWeightUpdateParams
 	)
 
 	calls := d.ethrpcClient.NewRequest()
@@ -97,6 +106,78 @@ func (d *PoolTracker) GetNewPoolState(
 		}, []interface{}{&scalingFactors})
 	}
 
+	if DexType(p.Type) == DexTypeBalancerLinear {
+		calls.AddCall(&ethrpc.Call{
+			ABI:    linearPoolABI,
+			Target: p.Address,
+			Method: metaStablePoolMethodGetScalingFactors,
+			Params: nil,
+		}, []interface{}{&scalingFactors})
+
+		calls.AddCall(&ethrpc.Call{
+			ABI:    linearPoolABI,
+			Target: p.Address,
+			Method: linearPoolMethodGetTargets,
+			Params: nil,
+		}, []interface{}{&linearTargets})
+
+		calls.AddCall(&ethrpc.Call{
+			ABI:    linearPoolABI,
+			Target: p.Address,
+			Method: linearPoolMethodGetVirtualSupply,
+			Params: nil,
+		}, []interface{}{&virtualSupply})
+
+		calls.AddCall(&ethrpc.Call{
+			ABI:    linearPoolABI,
+			Target: p.Address,
+			Method: linearPoolMethodGetMainIndex,
+			Params: nil,
+		}, []interface{}{&mainIndex})
+
+		calls.AddCall(&ethrpc.Call{
+			ABI:    linearPoolABI,
+			Target: p.Address,
+			Method: linearPoolMethodGetWrappedIndex,
+			Params: nil,
+		}, []interface{}{&wrappedIndex})
+
+		calls.AddCall(&ethrpc.Call{
+			ABI:    linearPoolABI,
+			Target: p.Address,
+			Method: linearPoolMethodGetBptIndex,
+			Params: nil,
+		}, []interface{}{&bptIndex})
+	}
+
+	if DexType(p.Type) == DexTypeBalancerLBP || DexType(p.Type) == DexTypeBalancerManaged {
+		var poolABI, weightParams = lbpABI, interface{}(&lbpWeightParams)
+		if DexType(p.Type) == DexTypeBalancerManaged {
+			poolABI, weightParams = managedPoolABI, &managedWeightParams
+		}
+
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: p.Address,
+			Method: poolMethodGetNormalizedWeights,
+			Params: nil,
+		}, []interface{}{&normalizedWeights})
+
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: p.Address,
+			Method: poolMethodGetSwapEnabled,
+			Params: nil,
+		}, []interface{}{&swapEnabled})
+
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: p.Address,
+			Method: poolMethodGetGradualWeightUpdateParams,
+			Params: nil,
+		}, []interface{}{weightParams})
+	}
+
 	if _, err := calls.Aggregate(); err != nil {
 		logger.WithFields(logger.Fields{
 			"poolAddress": p.Address,
@@ -159,6 +240,63 @@ func (d *PoolTracker) GetNewPoolState(
 		extra = string(extraBytes)
 	}
 
+	if DexType(p.Type) == DexTypeBalancerLinear {
+		if mainIndex == nil || wrappedIndex == nil || bptIndex == nil {
+			logger.WithFields(logger.Fields{
+				"poolAddress": p.Address,
+			}).Errorf("can not get token indexes for linear pool")
+			return entity.Pool{}, fmt.Errorf("can not get token indexes for linear pool %v", p.Address)
+		}
+
+		// The indexes returned by the pool are the indexes of the tokens registered in the vault,
+		// they are mapped to the indexes of p.Tokens, which are used by the simulator.
+		extraBytes, err := json.Marshal(LinearPoolExtra{
+			ScalingFactors: mapScalingFactors(p.Tokens, poolTokens.Tokens, scalingFactors),
+			LowerTarget:    linearTargets.LowerTarget,
+			UpperTarget:    linearTargets.UpperTarget,
+			VirtualSupply:  virtualSupply,
+			MainIndex:      mapTokenIndex(p.Tokens, poolTokens.Tokens, int(mainIndex.Int64())),
+			WrappedIndex:   mapTokenIndex(p.Tokens, poolTokens.Tokens, int(wrappedIndex.Int64())),
+			BptIndex:       mapTokenIndex(p.Tokens, poolTokens.Tokens, int(bptIndex.Int64())),
+		})
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"poolAddress": p.Address,
+				"error":       err,
+			}).Errorf("failed to marshal pool extra")
+			return entity.Pool{}, err
+		}
+
+		extra = string(extraBytes)
+	}
+
+	if DexType(p.Type) == DexTypeBalancerLBP || DexType(p.Type) == DexTypeBalancerManaged {
+		weightParams, err := getGradualWeightUpdateParams(
+			p, poolTokens.Tokens, normalizedWeights, lbpWeightParams, managedWeightParams, time.Now().Unix(),
+		)
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"poolAddress": p.Address,
+				"error":       err,
+			}).Errorf("failed to get gradual weight update params")
+			return entity.Pool{}, err
+		}
+
+		extraBytes, err := json.Marshal(Extra{
+			SwapEnabled:               &swapEnabled,
+			GradualWeightUpdateParams: weightParams,
+		})
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"poolAddress": p.Address,
+				"error":       err,
+			}).Errorf("failed to marshal pool extra")
+			return entity.Pool{}, err
+		}
+
+		extra = string(extraBytes)
+	}
+
 	p.Extra = extra
 	p.Timestamp = time.Now().Unix()
 	p.Reserves = reserves
@@ -169,3 +307,95 @@ func (d *PoolTracker) GetNewPoolState(
 
 	return p, nil
 }
+
+// mapTokenIndex returns the index in tokens of the vault token at vaultIndex, or -1 if it is not found
+func mapTokenIndex(tokens []*entity.PoolToken, vaultTokens []common.Address, vaultIndex int) int {
+	if vaultIndex < 0 || vaultIndex >= len(vaultTokens) {
+		return -1
+	}
+
+	for i, token := range tokens {
+		if strings.EqualFold(vaultTokens[vaultIndex].Hex(), token.Address) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// mapScalingFactors reorders the scaling factors, which follow the vault token order, to the order of tokens
+func mapScalingFactors(tokens []*entity.PoolToken, vaultTokens []common.Address, scalingFactors []*big.Int) []*big.Int {
+	if len(scalingFactors) != len(vaultTokens) {
+		return scalingFactors
+	}
+
+	result := make([]*big.Int, len(tokens))
+	for i := range vaultTokens {
+		if idx := mapTokenIndex(tokens, vaultTokens, i); idx >= 0 {
+			result[idx] = scalingFactors[i]
+		}
+	}
+
+	return result
+}
+
+// getGradualWeightUpdateParams maps the weights, which follow the vault token order without the BPT, to the order
+// of p.Tokens and updates the weights of p.Tokens to the current normalized weights.
+//
+// The liquidity bootstrapping pools do not expose their start weights. During an update, the current weights are
+// used as the start weights from now on, which is the same line since the weights are interpolated linearly.
+func getGradualWeightUpdateParams(
+	p entity.Pool,
+	vaultTokens []common.Address,
+	normalizedWeights []*big.Int,
+	lbpParams LBPGradualWeightUpdateParams,
+	managedParams ManagedPoolGradualWeightUpdateParams,
+	now int64,
+) (*GradualWeightUpdateParams, error) {
+	var weightedTokens = make([]common.Address, 0, len(vaultTokens))
+	for _, token := range vaultTokens {
+		if !strings.EqualFold(token.Hex(), p.Address) {
+			weightedTokens = append(weightedTokens, token)
+		}
+	}
+
+	var startTime, endTime, startWeights, endWeights = lbpParams.StartTime, lbpParams.EndTime,
+		normalizedWeights, lbpParams.EndWeights
+	if DexType(p.Type) == DexTypeBalancerManaged {
+		startTime, endTime, startWeights, endWeights = managedParams.StartTime, managedParams.EndTime,
+			managedParams.StartWeights, managedParams.EndWeights
+	}
+	if startTime == nil || endTime == nil || len(normalizedWeights) != len(weightedTokens) ||
+		len(startWeights) != len(weightedTokens) || len(endWeights) != len(weightedTokens) {
+		return nil, fmt.Errorf("invalid gradual weight update params of pool %v", p.Address)
+	}
+
+	var params = GradualWeightUpdateParams{
+		StartTime:    startTime.Int64(),
+		EndTime:      endTime.Int64(),
+		StartWeights: make([]*big.Int, len(p.Tokens)),
+		EndWeights:   make([]*big.Int, len(p.Tokens)),
+	}
+	if DexType(p.Type) == DexTypeBalancerLBP && params.StartTime < now {
+		params.StartTime = now
+	}
+
+	for i, token := range p.Tokens {
+		var idx = -1
+		for j, t := range weightedTokens {
+			if strings.EqualFold(t.Hex(), token.Address) {
+				idx = j
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("can not get weight of token %v of pool %v", token.Address, p.Address)
+		}
+
+		params.StartWeights[i] = startWeights[idx]
+		params.EndWeights[i] = endWeights[idx]
+		token.Weight = uint(normalizedWeights[idx].Uint64())
+	}
+
+	return &params, nil
+}
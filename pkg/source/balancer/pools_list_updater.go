@@ -44,6 +44,10 @@ func (d *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte
 		subgraphPoolTypeWeighted,
 		subgraphPoolTypeStable,
 		subgraphPoolTypeMetaStable,
+		subgraphPoolTypeAaveLinear,
+		subgraphPoolTypeERC4626Linear,
+		subgraphPoolTypeLBP,
+		subgraphPoolTypeManaged,
 	}
 
 	var metadata Metadata
@@ -140,6 +144,11 @@ func (d *PoolsListUpdater) getNewPoolsByType(ctx context.Context, poolType PoolT
 		}
 
 		for _, item := range p.Tokens {
+			// The managed pools register their own BPT in the vault, but it has no weight and can not be swapped
+			if dexTypeByPoolType[poolType] == DexTypeBalancerManaged && strings.EqualFold(item.Address, p.Address) {
+				continue
+			}
+
 			weight, _ := strconv.ParseFloat(item.Weight, 64)
 			poolToken := entity.PoolToken{
 				Address:   item.Address,
@@ -155,6 +164,17 @@ func (d *PoolsListUpdater) getNewPoolsByType(ctx context.Context, poolType PoolT
 			tokens = append(tokens, &poolToken)
 			reserves = append(reserves, zeroString)
 		}
+
+		// The linear pools register their own BPT in the vault, it can be swapped like the other tokens
+		if dexTypeByPoolType[poolType] == DexTypeBalancerLinear && !hasToken(tokens, p.Address) {
+			tokens = append(tokens, &entity.PoolToken{
+				Address:   strings.ToLower(p.Address),
+				Weight:    uint(1e18 / (len(p.Tokens) + 1)),
+				Swappable: true,
+			})
+			staticField.TokenDecimals = append(staticField.TokenDecimals, bptDecimals)
+			reserves = append(reserves, zeroString)
+		}
 		var swapFee, _ = strconv.ParseFloat(p.SwapFee, 64)
 
 		staticBytes, _ := json.Marshal(staticField)
@@ -241,3 +261,13 @@ func (d *PoolsListUpdater) getPoolsListByType(
 
 	return response.Pairs, nil
 }
+
+func hasToken(tokens []*entity.PoolToken, address string) bool {
+	for _, token := range tokens {
+		if strings.EqualFold(token.Address, address) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -140,6 +140,14 @@ func (t *StablePool) UpdateBalance(params pool.UpdateBalanceParams) {
 	}
 }
 
+func (t *StablePool) GetVaultAddress() string {
+	return t.VaultAddress
+}
+
+func (t *StablePool) GetPoolId() string {
+	return t.PoolId
+}
+
 func (t *StablePool) getScalingFactor(tokenIndex int) *big.Int {
 	if t.GetType() == string(balancer.DexTypeBalancerMetaStable) {
 		return t.ScalingFactors[tokenIndex]
@@ -34,6 +34,47 @@ type StaticExtra struct {
 type Extra struct {
 	AmplificationParameter AmplificationParameter `json:"amplificationParameter"`
 	ScalingFactors         []*big.Int             `json:"scalingFactors,omitempty"`
+	// SwapEnabled and GradualWeightUpdateParams are only set for the liquidity bootstrapping and managed pools
+	SwapEnabled               *bool                      `json:"swapEnabled,omitempty"`
+	GradualWeightUpdateParams *GradualWeightUpdateParams `json:"gradualWeightUpdateParams,omitempty"`
+}
+
+// GradualWeightUpdateParams are the params of a gradual weight update, the weights are interpolated linearly
+// from StartWeights at StartTime to EndWeights at EndTime. The weights are in the order of the pool tokens.
+type GradualWeightUpdateParams struct {
+	StartTime    int64      `json:"startTime"`
+	EndTime      int64      `json:"endTime"`
+	StartWeights []*big.Int `json:"startWeights"`
+	EndWeights   []*big.Int `json:"endWeights"`
+}
+
+type LBPGradualWeightUpdateParams struct {
+	StartTime  *big.Int
+	EndTime    *big.Int
+	EndWeights []*big.Int
+}
+
+type ManagedPoolGradualWeightUpdateParams struct {
+	StartTime    *big.Int
+	EndTime      *big.Int
+	StartWeights []*big.Int
+	EndWeights   []*big.Int
+}
+
+// LinearPoolExtra is the extra of the Aave/ERC4626 linear pools, the targets are in main token decimals.
+type LinearPoolExtra struct {
+	ScalingFactors []*big.Int `json:"scalingFactors"`
+	LowerTarget    *big.Int   `json:"lowerTarget"`
+	UpperTarget    *big.Int   `json:"upperTarget"`
+	VirtualSupply  *big.Int   `json:"virtualSupply"`
+	MainIndex      int        `json:"mainIndex"`
+	WrappedIndex   int        `json:"wrappedIndex"`
+	BptIndex       int        `json:"bptIndex"`
+}
+
+type LinearTargets struct {
+	LowerTarget *big.Int
+	UpperTarget *big.Int
 }
 
 type PoolTokens struct {
@@ -1,8 +1,13 @@
 package balancerweighted
 
-import "math/big"
+import (
+	"errors"
+	"math/big"
+)
 
 var (
-	MaxInRatio   = big.NewInt(30) // 30% = 0.3
-	MaxOutRatio  = big.NewInt(30) // 30% = 0.3
-)
\ No newline at end of file
+	MaxInRatio  = big.NewInt(30) // 30% = 0.3
+	MaxOutRatio = big.NewInt(30) // 30% = 0.3
+)
+
+var ErrSwapDisabled = errors.New("swap is disabled")
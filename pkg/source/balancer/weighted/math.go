@@ -71,3 +71,35 @@ func calcOutGivenIn(
 
 	return mulDown(balanceOut, complement(power))
 }
+
+// Solidity code:
+// https://github.com/balancer-labs/balancer-v2-monorepo/blob/master/pkg/pool-weighted/contracts/lib/GradualValueChange.sol
+func calculateValueChangeProgress(startTime, endTime, now int64) *big.Int {
+	if now >= endTime {
+		return bignumber.BONE
+	} else if now <= startTime {
+		return bignumber.ZeroBI
+	}
+
+	var totalSeconds = big.NewInt(endTime - startTime)
+	var secondsElapsed = big.NewInt(now - startTime)
+
+	// We don't need to consider zero division here as this is covered above.
+	return divDown(secondsElapsed, totalSeconds)
+}
+
+func interpolateValue(startValue, endValue, pctProgress *big.Int) *big.Int {
+	if pctProgress.Cmp(bignumber.BONE) >= 0 || startValue.Cmp(endValue) == 0 {
+		return endValue
+	}
+	if pctProgress.Sign() == 0 {
+		return startValue
+	}
+
+	if startValue.Cmp(endValue) > 0 {
+		var delta = mulDown(pctProgress, new(big.Int).Sub(startValue, endValue))
+		return new(big.Int).Sub(startValue, delta)
+	}
+	var delta = mulDown(pctProgress, new(big.Int).Sub(endValue, startValue))
+	return new(big.Int).Add(startValue, delta)
+}
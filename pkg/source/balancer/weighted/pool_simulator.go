@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"time"
 
 	balancer "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/balancer"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
@@ -20,7 +21,11 @@ type WeightedPool2Tokens struct {
 	PoolId       string
 	Decimals     []uint
 	Weights      []*big.Int
-	gas          balancer.Gas
+	// SwapEnabled and GradualWeightUpdateParams are used by the liquidity bootstrapping and managed pools,
+	// whose weights change over time.
+	SwapEnabled               bool
+	GradualWeightUpdateParams *balancer.GradualWeightUpdateParams
+	gas                       balancer.Gas
 }
 
 func NewPoolSimulator(entityPool entity.Pool) (*WeightedPool2Tokens, error) {
@@ -29,6 +34,13 @@ func NewPoolSimulator(entityPool entity.Pool) (*WeightedPool2Tokens, error) {
 		return nil, err
 	}
 
+	var extra balancer.Extra
+	if len(entityPool.Extra) > 0 {
+		if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+			return nil, err
+		}
+	}
+
 	swapFeeFl := new(big.Float).Mul(big.NewFloat(entityPool.SwapFee), bignumber.BoneFloat)
 	swapFee, _ := swapFeeFl.Int(nil)
 
@@ -45,6 +57,11 @@ func NewPoolSimulator(entityPool entity.Pool) (*WeightedPool2Tokens, error) {
 		decimals[i] = uint(staticExtra.TokenDecimals[i])
 	}
 
+	var weightParams = extra.GradualWeightUpdateParams
+	if weightParams != nil && (len(weightParams.StartWeights) != numTokens || len(weightParams.EndWeights) != numTokens) {
+		return nil, fmt.Errorf("invalid gradual weight update params of pool %v", entityPool.Address)
+	}
+
 	return &WeightedPool2Tokens{
 		Pool: pool.Pool{
 			Info: pool.PoolInfo{
@@ -62,7 +79,10 @@ func NewPoolSimulator(entityPool entity.Pool) (*WeightedPool2Tokens, error) {
 		PoolId:       strings.ToLower(staticExtra.PoolId),
 		Decimals:     decimals,
 		Weights:      weights,
-		gas:          balancer.DefaultGas,
+		// the swap is enabled if the pool does not have the flag
+		SwapEnabled:               extra.SwapEnabled == nil || *extra.SwapEnabled,
+		GradualWeightUpdateParams: weightParams,
+		gas:                       balancer.DefaultGas,
 	}, nil
 }
 
@@ -72,7 +92,11 @@ func (t *WeightedPool2Tokens) CalcAmountOut(
 ) (*pool.CalcAmountOutResult, error) {
 	var tokenIndexFrom = t.GetTokenIndex(tokenAmountIn.Token)
 	var tokenIndexTo = t.GetTokenIndex(tokenOut)
+	if !t.SwapEnabled {
+		return &pool.CalcAmountOutResult{}, ErrSwapDisabled
+	}
 	if tokenIndexFrom >= 0 && tokenIndexTo >= 0 {
+		var weights = t.getNormalizedWeights(time.Now().Unix())
 		var maxAmountIn = new(big.Int).Div(new(big.Int).Mul(t.Info.Reserves[tokenIndexFrom], MaxInRatio), bignumber.TenPowInt(2))
 
 		if tokenAmountIn.Amount.Cmp(bignumber.ZeroBI) < 0 {
@@ -94,9 +118,9 @@ func (t *WeightedPool2Tokens) CalcAmountOut(
 		var amount = _upscale(new(big.Int).Sub(tokenAmountIn.Amount, feeAmount), scalingFactorTokenIn)
 		var amountOut = calcOutGivenIn(
 			balanceTokenIn,
-			t.Weights[tokenIndexFrom],
+			weights[tokenIndexFrom],
 			balanceTokenOut,
-			t.Weights[tokenIndexTo],
+			weights[tokenIndexTo],
 			amount,
 		)
 		amountOut = _downscaleDown(amountOut, scalingFactorTokenOut)
@@ -143,3 +167,28 @@ func (t *WeightedPool2Tokens) UpdateBalance(params pool.UpdateBalanceParams) {
 		t.Info.Reserves[tokenOutIndex] = new(big.Int).Sub(t.Info.Reserves[tokenOutIndex], output.Amount)
 	}
 }
+
+func (t *WeightedPool2Tokens) GetVaultAddress() string {
+	return t.VaultAddress
+}
+
+func (t *WeightedPool2Tokens) GetPoolId() string {
+	return t.PoolId
+}
+
+// getNormalizedWeights returns the weights at the timestamp, the weights are interpolated if the pool
+// has a gradual weight update, otherwise they are the weights of the pool tokens.
+func (t *WeightedPool2Tokens) getNormalizedWeights(timestamp int64) []*big.Int {
+	if t.GradualWeightUpdateParams == nil {
+		return t.Weights
+	}
+
+	var params = t.GradualWeightUpdateParams
+	var pctProgress = calculateValueChangeProgress(params.StartTime, params.EndTime, timestamp)
+	var weights = make([]*big.Int, len(t.Weights))
+	for i := range weights {
+		weights[i] = interpolateValue(params.StartWeights[i], params.EndWeights[i], pctProgress)
+	}
+
+	return weights
+}
@@ -62,3 +62,34 @@ func TestSwap_3token(t *testing.T) {
 	assert.Equal(t, big.NewInt(47), result.TokenAmountOut.Amount)
 	assert.Equal(t, big.NewInt(3), result.Fee.Amount)
 }
+
+func TestSwap_gradualWeightUpdate(t *testing.T) {
+	var poolInfo = entity.Pool{
+		Address:  "adr",
+		SwapFee:  0.0025,
+		Type:     "balancer-liquidity-bootstrapping",
+		Reserves: []string{"5000000", "7000"},
+		Tokens: entity.PoolTokens{
+			&entity.PoolToken{Address: "BAL", Weight: 900000000000000000},
+			&entity.PoolToken{Address: "WETH", Weight: 100000000000000000},
+		},
+		Extra: "{\"swapEnabled\":true,\"gradualWeightUpdateParams\":{\"startTime\":1000,\"endTime\":2000," +
+			"\"startWeights\":[900000000000000000,100000000000000000],\"endWeights\":[800000000000000000,200000000000000000]}}",
+		StaticExtra: "{\"vaultAddress\":\"v1\",\"poolId\":\"p1\",\"tokenDecimals\":[1,19]}",
+	}
+	var p, err = NewPoolSimulator(poolInfo)
+	require.Nil(t, err)
+
+	assert.Equal(t, []*big.Int{big.NewInt(9e17), big.NewInt(1e17)}, p.getNormalizedWeights(500))
+	assert.Equal(t, []*big.Int{big.NewInt(875e15), big.NewInt(125e15)}, p.getNormalizedWeights(1250))
+	assert.Equal(t, []*big.Int{big.NewInt(8e17), big.NewInt(2e17)}, p.getNormalizedWeights(3000))
+
+	// the update has ended, so the result is the same as the 80/20 pool in TestSwap_2token
+	result, err := p.CalcAmountOut(pool.TokenAmount{Token: "BAL", Amount: big.NewInt(1000)}, "WETH")
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(5), result.TokenAmountOut.Amount)
+
+	p.SwapEnabled = false
+	_, err = p.CalcAmountOut(pool.TokenAmount{Token: "BAL", Amount: big.NewInt(1000)}, "WETH")
+	assert.Equal(t, ErrSwapDisabled, err)
+}
@@ -21,6 +21,13 @@ var (
 	tricryptoABI       abi.ABI
 	oracleABI          abi.ABI
 	compoundABI        abi.ABI
+
+	stableSwapNGFactoryABI abi.ABI
+	stableSwapNGABI        abi.ABI
+	tricryptoNGFactoryABI  abi.ABI
+	tricryptoNGABI         abi.ABI
+	twocryptoNGFactoryABI  abi.ABI
+	twocryptoNGABI         abi.ABI
 )
 
 func init() {
@@ -42,6 +49,12 @@ func init() {
 		{&tricryptoABI, tricryptoABIBytes},
 		{&oracleABI, oracleABIBytes},
 		{&compoundABI, compoundABIBytes},
+		{&stableSwapNGFactoryABI, stableSwapNGFactoryABIBytes},
+		{&stableSwapNGABI, stableSwapNGABIBytes},
+		{&tricryptoNGFactoryABI, tricryptoNGFactoryABIBytes},
+		{&tricryptoNGABI, tricryptoNGABIBytes},
+		{&twocryptoNGFactoryABI, twocryptoNGFactoryABIBytes},
+		{&twocryptoNGABI, twocryptoNGABIBytes},
 	}
 
 	for _, b := range build {
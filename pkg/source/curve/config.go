@@ -13,4 +13,9 @@ type Config struct {
 	MetaPoolsFactoryAddress    string `json:"metaPoolsFactoryAddress"`
 	CryptoPoolsRegistryAddress string `json:"cryptoPoolsRegistryAddress"`
 	CryptoPoolsFactoryAddress  string `json:"cryptoPoolsFactoryAddress"`
+
+	// The NG factories are not registered in the address provider, they must be configured explicitly.
+	StableSwapNGFactoryAddress string `json:"stableSwapNGFactoryAddress"`
+	TricryptoNGFactoryAddress  string `json:"tricryptoNGFactoryAddress"`
+	TwocryptoNGFactoryAddress  string `json:"twocryptoNGFactoryAddress"`
 }
@@ -16,6 +16,7 @@ const (
 	registryOrFactoryMethodGetUnderDecimals   = "get_underlying_decimals"
 	registryOrFactoryMethodGetRates           = "get_rates"
 	registryOrFactoryMethodGetLpToken         = "get_lp_token"
+	registryOrFactoryMethodGetPoolAssetTypes  = "get_pool_asset_types"
 
 	poolMethodA                   = "A"
 	poolMethodAPrecise            = "A_precise"
@@ -46,6 +47,10 @@ const (
 	poolMethodPriceOracle         = "price_oracle"
 	poolMethodLastPrices          = "last_prices"
 	poolMethodBasePool            = "base_pool"
+	poolMethodGetBalances         = "get_balances"
+	poolMethodStoredRates         = "stored_rates"
+	poolMethodMaTime              = "ma_time"
+	poolMethodLastTimestamp       = "last_timestamp"
 
 	aaveMethodOffpegFeeMultiplier = "offpeg_fee_multiplier"
 	oracleMethodLatestAnswer      = "latestAnswer"
@@ -70,6 +75,9 @@ const (
 	poolTypeCompound    = "curve-compound"
 	poolTypeTricrypto   = "curve-tricrypto"
 	poolTypeTwo         = "curve-two"
+	poolTypeStableNg    = "curve-stable-ng"
+	poolTypeTricryptoNg = "curve-tricrypto-ng"
+	poolTypeTwocryptoNg = "curve-twocrypto-ng"
 	poolTypeUnsupported = "unsupported"
 )
 
@@ -79,6 +87,9 @@ const (
 	sourceMetaPoolsFactory
 	sourceCryptoPoolsRegistry
 	sourceCryptoPoolsFactory
+	sourceStableSwapNGFactory
+	sourceTricryptoNGFactory
+	sourceTwocryptoNGFactory
 )
 
 // Known weth9 implementation addresses, used in our implementation of Ether#wrapped
@@ -0,0 +1,59 @@
+package cryptong
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const MaxLoopLimit = 255
+
+var (
+	DefaultGas     = map[int]Gas{2: {Exchange: 170000}, 3: {Exchange: 210000}}
+	AMultiplier    = bignumber.NewBig10("10000")
+	Precision      = bignumber.BONE
+	FeeDenominator = bignumber.NewBig10("10000000000")
+	PriceMask      = new(big.Int).Sub(new(big.Int).Lsh(bignumber.One, 128), bignumber.One)
+	MinGamma       = bignumber.NewBig10("10000000000")
+	TimestampMask  = PriceMask
+
+	// the bounds and the coefficients of wadExp, from Solady's FixedPointMathLib.expWad
+	wadExpMin       = bignumber.NewBig10("-41446531673892822313")
+	wadExpMax       = bignumber.NewBig10("135305999368893231589")
+	wadExpFivePow18 = new(big.Int).Exp(big.NewInt(5), big.NewInt(18), nil)
+	wadExpLn2       = bignumber.NewBig10("54916777467707473351141471128")
+	wadExpHalf      = new(big.Int).Lsh(bignumber.One, 95)
+	wadExpScale     = bignumber.NewBig10("3822833074963236453042738258902158003155416615667")
+	wadExpY         = []*big.Int{
+		bignumber.NewBig10("1346386616545796478920950773328"),
+		bignumber.NewBig10("57155421227552351082224309758442"),
+	}
+	wadExpP = []*big.Int{
+		bignumber.NewBig10("94201549194550492254356042504812"),
+		bignumber.NewBig10("28719021644029726153956944680412240"),
+		new(big.Int).Lsh(bignumber.NewBig10("4385272521454847904659076985693276"), 96),
+	}
+	wadExpQ = []*big.Int{
+		bignumber.NewBig10("2855989394907223263936484059900"),
+		bignumber.NewBig10("50020603652535783019961831881945"),
+		bignumber.NewBig10("533845033583426703283633433725380"),
+		bignumber.NewBig10("3604857256930695427073651918091429"),
+		bignumber.NewBig10("14423608567350463180887372962807573"),
+		bignumber.NewBig10("26449188498355588339934803723976023"),
+	}
+
+	// MaxGamma and the A range depend on the number of coins, they come from
+	// CurveCryptoMathOptimized2.vy (twocrypto-ng) and CurveCryptoMathOptimized3.vy (tricrypto-ng)
+	MaxGamma = map[int]*big.Int{
+		2: bignumber.NewBig10("300000000000000000"),
+		3: bignumber.NewBig10("50000000000000000"),
+	}
+	MinA = map[int]*big.Int{
+		2: new(big.Int).Div(new(big.Int).Mul(big.NewInt(4), AMultiplier), big.NewInt(10)),
+		3: new(big.Int).Div(new(big.Int).Mul(big.NewInt(27), AMultiplier), big.NewInt(100)),
+	}
+	MaxA = map[int]*big.Int{
+		2: new(big.Int).Mul(new(big.Int).Mul(big.NewInt(4), AMultiplier), big.NewInt(100000)),
+		3: new(big.Int).Mul(new(big.Int).Mul(big.NewInt(27), AMultiplier), big.NewInt(1000)),
+	}
+)
@@ -0,0 +1,20 @@
+package cryptong
+
+import "errors"
+
+var (
+	ErrInvalidNumberOfCoins    = errors.New("invalid number of coins")
+	ErrTokenFromEqualsTokenTo  = errors.New("can't compare token to itself")
+	ErrTokenIndexesOutOfRange  = errors.New("token index out of range")
+	ErrZero                    = errors.New("zero")
+	ErrDenominatorZero         = errors.New("denominator should not be 0")
+	ErrUnsafeA                 = errors.New("unsafe values A")
+	ErrUnsafeGamma             = errors.New("unsafe values gamma")
+	ErrUnsafeD                 = errors.New("unsafe values D")
+	ErrUnsafeX                 = errors.New("unsafe values x[i]")
+	ErrUnsafeY                 = errors.New("unsafe value for y")
+	ErrDidNotConverge          = errors.New("did not converge")
+	ErrExchangeMoreThanBalance = errors.New("exchange amount is more than balance")
+	ErrWadExpOverflow          = errors.New("wad_exp overflow")
+	ErrLoss                    = errors.New("loss")
+)
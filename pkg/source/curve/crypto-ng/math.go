@@ -0,0 +1,525 @@
+package cryptong
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// Reference:
+// https://github.com/curvefi/tricrypto-ng/blob/main/contracts/main/CurveCryptoMathOptimized3.vy
+// https://github.com/curvefi/twocrypto-ng/blob/main/contracts/main/CurveCryptoMathOptimized2.vy
+// The contracts solve y analytically and only fall back to newton's method on unsafe values,
+// here newton's method is always used so the results can differ from get_dy in the last digits.
+
+func sortArray(a []*big.Int) []*big.Int {
+	var ret = make([]*big.Int, len(a))
+	copy(ret, a)
+	for i := 1; i < len(ret); i += 1 {
+		var x = ret[i]
+		var cur = i
+		for cur > 0 && ret[cur-1].Cmp(x) < 0 {
+			ret[cur] = ret[cur-1]
+			cur -= 1
+		}
+		ret[cur] = x
+	}
+	return ret
+}
+
+func absDiff(a, b *big.Int) *big.Int {
+	if a.Cmp(b) > 0 {
+		return new(big.Int).Sub(a, b)
+	}
+	return new(big.Int).Sub(b, a)
+}
+
+func geometricMean(x []*big.Int) (*big.Int, error) {
+	var nCoins = len(x)
+	var nCoinsBi = big.NewInt(int64(nCoins))
+	var D = x[0]
+	for i := 0; i < MaxLoopLimit; i += 1 {
+		var DPrev = D
+		var tmp = bignumber.BONE
+		for _, _x := range x {
+			tmp = new(big.Int).Div(new(big.Int).Mul(tmp, _x), D)
+		}
+		D = new(big.Int).Div(
+			new(big.Int).Mul(D, new(big.Int).Add(new(big.Int).Mul(big.NewInt(int64(nCoins-1)), bignumber.BONE), tmp)),
+			new(big.Int).Mul(nCoinsBi, bignumber.BONE),
+		)
+		var diff = absDiff(D, DPrev)
+		if diff.Cmp(bignumber.One) <= 0 || new(big.Int).Mul(diff, bignumber.BONE).Cmp(D) < 0 {
+			return D, nil
+		}
+	}
+	return nil, ErrDidNotConverge
+}
+
+func newtonD(ANN *big.Int, gamma *big.Int, xUnsorted []*big.Int) (*big.Int, error) {
+	var nCoins = len(xUnsorted)
+	if ANN.Cmp(MinA[nCoins]) < 0 || ANN.Cmp(MaxA[nCoins]) > 0 {
+		return nil, ErrUnsafeA
+	}
+	if gamma.Cmp(MinGamma) < 0 || gamma.Cmp(MaxGamma[nCoins]) > 0 {
+		return nil, ErrUnsafeGamma
+	}
+	var nCoinsBi = big.NewInt(int64(nCoins))
+	var x = sortArray(xUnsorted)
+	if x[0].Cmp(bignumber.TenPowInt(9)) < 0 || x[0].Cmp(bignumber.TenPowInt(33)) > 0 {
+		return nil, ErrUnsafeX
+	}
+	for i := 1; i < nCoins; i += 1 {
+		var frac = new(big.Int).Div(new(big.Int).Mul(x[i], bignumber.BONE), x[0])
+		if frac.Cmp(bignumber.TenPowInt(11)) < 0 {
+			return nil, ErrUnsafeX
+		}
+	}
+	mean, err := geometricMean(x)
+	if err != nil {
+		return nil, err
+	}
+	var D = new(big.Int).Mul(nCoinsBi, mean)
+	var S = big.NewInt(0)
+	for _, xi := range x {
+		S.Add(S, xi)
+	}
+	for i := 0; i < MaxLoopLimit; i += 1 {
+		var DPrev = D
+		var K0 = bignumber.BONE
+		for _, _x := range x {
+			K0 = new(big.Int).Div(new(big.Int).Mul(new(big.Int).Mul(K0, _x), nCoinsBi), D)
+		}
+		var g1k0 = new(big.Int).Add(gamma, bignumber.BONE)
+		g1k0 = new(big.Int).Add(absDiff(g1k0, K0), bignumber.One)
+
+		var mul1 = new(big.Int).Div(
+			new(big.Int).Mul(
+				new(big.Int).Mul(
+					new(big.Int).Div(new(big.Int).Mul(new(big.Int).Div(new(big.Int).Mul(bignumber.BONE, D), gamma), g1k0), gamma),
+					g1k0,
+				),
+				AMultiplier,
+			),
+			ANN,
+		)
+		var mul2 = new(big.Int).Div(new(big.Int).Mul(new(big.Int).Mul(new(big.Int).Mul(bignumber.Two, bignumber.BONE), nCoinsBi), K0), g1k0)
+		var negFprime = new(big.Int).Sub(
+			new(big.Int).Add(
+				new(big.Int).Add(S, new(big.Int).Div(new(big.Int).Mul(S, mul2), bignumber.BONE)),
+				new(big.Int).Div(new(big.Int).Mul(mul1, nCoinsBi), K0),
+			),
+			new(big.Int).Div(new(big.Int).Mul(mul2, D), bignumber.BONE),
+		)
+		if negFprime.Sign() == 0 {
+			return nil, ErrDenominatorZero
+		}
+		var DPlus = new(big.Int).Div(new(big.Int).Mul(D, new(big.Int).Add(negFprime, S)), negFprime)
+		var DMinus = new(big.Int).Div(new(big.Int).Mul(D, D), negFprime)
+		var term = new(big.Int).Div(new(big.Int).Mul(D, new(big.Int).Div(mul1, negFprime)), bignumber.BONE)
+		if bignumber.BONE.Cmp(K0) > 0 {
+			DMinus = new(big.Int).Add(DMinus, new(big.Int).Div(new(big.Int).Mul(term, new(big.Int).Sub(bignumber.BONE, K0)), K0))
+		} else {
+			DMinus = new(big.Int).Sub(DMinus, new(big.Int).Div(new(big.Int).Mul(term, new(big.Int).Sub(K0, bignumber.BONE)), K0))
+		}
+		if DPlus.Cmp(DMinus) > 0 {
+			D = new(big.Int).Sub(DPlus, DMinus)
+		} else {
+			D = new(big.Int).Div(new(big.Int).Sub(DMinus, DPlus), bignumber.Two)
+		}
+		var limit = bignumber.TenPowInt(16)
+		if D.Cmp(limit) > 0 {
+			limit = D
+		}
+		if new(big.Int).Mul(absDiff(D, DPrev), bignumber.TenPowInt(14)).Cmp(limit) < 0 {
+			for _, _x := range x {
+				var frac = new(big.Int).Div(new(big.Int).Mul(_x, bignumber.BONE), D)
+				if frac.Cmp(bignumber.TenPowInt(16)) < 0 || frac.Cmp(bignumber.TenPowInt(20)) > 0 {
+					return nil, ErrUnsafeX
+				}
+			}
+			return D, nil
+		}
+	}
+	return nil, ErrDidNotConverge
+}
+
+func newtonY(ANN *big.Int, gamma *big.Int, x []*big.Int, D *big.Int, i int) (*big.Int, error) {
+	var nCoins = len(x)
+	if ANN.Cmp(MinA[nCoins]) < 0 || ANN.Cmp(MaxA[nCoins]) > 0 {
+		return nil, ErrUnsafeA
+	}
+	if gamma.Cmp(MinGamma) < 0 || gamma.Cmp(MaxGamma[nCoins]) > 0 {
+		return nil, ErrUnsafeGamma
+	}
+	if D.Cmp(bignumber.TenPowInt(17)) < 0 || D.Cmp(bignumber.TenPowInt(33)) > 0 {
+		return nil, ErrUnsafeD
+	}
+	for k := 0; k < nCoins; k += 1 {
+		if k == i {
+			continue
+		}
+		var frac = new(big.Int).Div(new(big.Int).Mul(x[k], bignumber.BONE), D)
+		if frac.Cmp(bignumber.TenPowInt(16)) < 0 || frac.Cmp(bignumber.TenPowInt(20)) > 0 {
+			return nil, ErrUnsafeX
+		}
+	}
+
+	var nCoinsBi = big.NewInt(int64(nCoins))
+	var y = new(big.Int).Div(D, nCoinsBi)
+	var K0i = bignumber.BONE
+	var Si = big.NewInt(0)
+
+	var xSorted = make([]*big.Int, nCoins)
+	copy(xSorted, x)
+	xSorted[i] = bignumber.ZeroBI
+	xSorted = sortArray(xSorted)
+
+	var tenPow14 = bignumber.TenPowInt(14)
+	var convergenceLimit = new(big.Int).Div(xSorted[0], tenPow14)
+	if temp := new(big.Int).Div(D, tenPow14); temp.Cmp(convergenceLimit) > 0 {
+		convergenceLimit = temp
+	}
+	if convergenceLimit.Cmp(big.NewInt(100)) < 0 {
+		convergenceLimit = big.NewInt(100)
+	}
+
+	for j := 2; j < nCoins+1; j += 1 {
+		var _x = xSorted[nCoins-j]
+		if _x.Sign() == 0 {
+			return nil, ErrDenominatorZero
+		}
+		y = new(big.Int).Div(new(big.Int).Mul(y, D), new(big.Int).Mul(_x, nCoinsBi))
+		Si = new(big.Int).Add(Si, _x)
+	}
+	for j := 0; j < nCoins-1; j += 1 {
+		K0i = new(big.Int).Div(new(big.Int).Mul(new(big.Int).Mul(K0i, xSorted[j]), nCoinsBi), D)
+	}
+
+	for j := 0; j < MaxLoopLimit; j += 1 {
+		var yPrev = y
+		var K0 = new(big.Int).Div(new(big.Int).Mul(new(big.Int).Mul(K0i, y), nCoinsBi), D)
+		var S = new(big.Int).Add(Si, y)
+		var g1k0 = new(big.Int).Add(gamma, bignumber.BONE)
+		g1k0 = new(big.Int).Add(absDiff(g1k0, K0), bignumber.One)
+
+		var mul1 = new(big.Int).Div(
+			new(big.Int).Mul(
+				new(big.Int).Div(new(big.Int).Mul(new(big.Int).Div(new(big.Int).Mul(bignumber.BONE, D), gamma), g1k0), gamma),
+				new(big.Int).Mul(g1k0, AMultiplier),
+			),
+			ANN,
+		)
+		var mul2 = new(big.Int).Add(new(big.Int).Div(new(big.Int).Mul(new(big.Int).Mul(bignumber.Two, bignumber.BONE), K0), g1k0), bignumber.BONE)
+		var yfprime = new(big.Int).Add(new(big.Int).Add(new(big.Int).Mul(bignumber.BONE, y), new(big.Int).Mul(S, mul2)), mul1)
+		var dyfprime = new(big.Int).Mul(D, mul2)
+		if yfprime.Cmp(dyfprime) < 0 {
+			y = new(big.Int).Div(yPrev, bignumber.Two)
+			continue
+		}
+		yfprime = new(big.Int).Sub(yfprime, dyfprime)
+		if y.Sign() == 0 {
+			return nil, ErrDenominatorZero
+		}
+		var fprime = new(big.Int).Div(yfprime, y)
+		if fprime.Sign() == 0 {
+			return nil, ErrDenominatorZero
+		}
+
+		var yMinus = new(big.Int).Div(mul1, fprime)
+		var yPlus = new(big.Int).Add(
+			new(big.Int).Div(new(big.Int).Add(yfprime, new(big.Int).Mul(bignumber.BONE, D)), fprime),
+			new(big.Int).Div(new(big.Int).Mul(yMinus, bignumber.BONE), K0),
+		)
+		yMinus = new(big.Int).Add(yMinus, new(big.Int).Div(new(big.Int).Mul(bignumber.BONE, S), fprime))
+		if yPlus.Cmp(yMinus) < 0 {
+			y = new(big.Int).Div(yPrev, bignumber.Two)
+		} else {
+			y = new(big.Int).Sub(yPlus, yMinus)
+		}
+
+		var limit = new(big.Int).Div(y, tenPow14)
+		if convergenceLimit.Cmp(limit) > 0 {
+			limit = convergenceLimit
+		}
+		if absDiff(y, yPrev).Cmp(limit) < 0 {
+			var frac = new(big.Int).Div(new(big.Int).Mul(y, bignumber.BONE), D)
+			if frac.Cmp(bignumber.TenPowInt(16)) < 0 || frac.Cmp(bignumber.TenPowInt(20)) > 0 {
+				return nil, ErrUnsafeY
+			}
+			return y, nil
+		}
+	}
+	return nil, ErrDidNotConverge
+}
+
+func (t *PoolSimulator) _A_gamma() (*big.Int, *big.Int) {
+	var t1 = t.FutureAGammaTime
+	var A1 = new(big.Int).Rsh(t.FutureAGamma, 128)
+	var gamma1 = new(big.Int).And(t.FutureAGamma, PriceMask)
+	var now = time.Now().Unix()
+	if now < t1 {
+		var A0 = new(big.Int).Rsh(t.InitialAGamma, 128)
+		var gamma0 = new(big.Int).And(t.InitialAGamma, PriceMask)
+		var t0 = t.InitialAGammaTime
+		t1 -= t0
+		t0 = now - t0
+		var t2 = t1 - t0
+		A1 = new(big.Int).Div(new(big.Int).Add(new(big.Int).Mul(A0, big.NewInt(t2)), new(big.Int).Mul(A1, big.NewInt(t0))), big.NewInt(t1))
+		gamma1 = new(big.Int).Div(new(big.Int).Add(new(big.Int).Mul(gamma0, big.NewInt(t2)), new(big.Int).Mul(gamma1, big.NewInt(t0))), big.NewInt(t1))
+	}
+	return A1, gamma1
+}
+
+// _fee is the dynamic fee of the pool, it is mid_fee when the pool is balanced and moves towards out_fee otherwise.
+func (t *PoolSimulator) _fee(xp []*big.Int) *big.Int {
+	var nCoinsBi = big.NewInt(int64(len(xp)))
+	var S = big.NewInt(0)
+	for _, x := range xp {
+		S.Add(S, x)
+	}
+	if S.Sign() == 0 {
+		return t.OutFee
+	}
+	var K = new(big.Int).Mul(bignumber.BONE, new(big.Int).Exp(nCoinsBi, nCoinsBi, nil))
+	for _, x := range xp {
+		K = new(big.Int).Div(new(big.Int).Mul(K, x), S)
+	}
+	var f = new(big.Int).Div(
+		new(big.Int).Mul(t.FeeGamma, bignumber.BONE),
+		new(big.Int).Sub(new(big.Int).Add(t.FeeGamma, bignumber.BONE), K),
+	)
+	return new(big.Int).Div(
+		new(big.Int).Add(new(big.Int).Mul(t.MidFee, f), new(big.Int).Mul(t.OutFee, new(big.Int).Sub(bignumber.BONE, f))),
+		bignumber.BONE,
+	)
+}
+
+// _xp scales the balances by the precisions and the price scales.
+func (t *PoolSimulator) _xp(balances []*big.Int) []*big.Int {
+	var nCoins = len(balances)
+	var xp = make([]*big.Int, nCoins)
+	xp[0] = new(big.Int).Mul(balances[0], t.Precisions[0])
+	for k := 1; k < nCoins; k += 1 {
+		xp[k] = new(big.Int).Div(new(big.Int).Mul(new(big.Int).Mul(balances[k], t.PriceScale[k-1]), t.Precisions[k]), Precision)
+	}
+	return xp
+}
+
+// GetDy follows get_dy of the views contracts of tricrypto-ng and twocrypto-ng,
+// it returns the amount out, the fee and the scaled balances after the swap.
+func (t *PoolSimulator) GetDy(i int, j int, dx *big.Int) (*big.Int, *big.Int, []*big.Int, error) {
+	var nCoins = len(t.Info.Tokens)
+	if i == j {
+		return nil, nil, nil, ErrTokenFromEqualsTokenTo
+	}
+	if i < 0 || j < 0 || i >= nCoins || j >= nCoins {
+		return nil, nil, nil, ErrTokenIndexesOutOfRange
+	}
+	if dx.Sign() <= 0 {
+		return nil, nil, nil, ErrZero
+	}
+
+	A, gamma := t._A_gamma()
+	var D = t.D
+	if t.FutureAGammaTime > time.Now().Unix() {
+		var err error
+		if D, err = newtonD(A, gamma, t._xp(t.Info.Reserves)); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	var balances = make([]*big.Int, nCoins)
+	copy(balances, t.Info.Reserves)
+	balances[i] = new(big.Int).Add(balances[i], dx)
+	var xp = t._xp(balances)
+
+	y, err := newtonY(A, gamma, xp, D, j)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var dy = new(big.Int).Sub(new(big.Int).Sub(xp[j], y), bignumber.One)
+	if dy.Sign() <= 0 {
+		return nil, nil, nil, ErrZero
+	}
+	xp[j] = y
+	if j > 0 {
+		dy = new(big.Int).Div(new(big.Int).Mul(dy, Precision), t.PriceScale[j-1])
+	}
+	dy = new(big.Int).Div(dy, t.Precisions[j])
+
+	var fee = new(big.Int).Div(new(big.Int).Mul(t._fee(xp), dy), FeeDenominator)
+	dy = new(big.Int).Sub(dy, fee)
+
+	return dy, fee, xp, nil
+}
+
+// wadExp is wad_exp of the math contracts (Solady's expWad), e^x with x and the result in 18 decimals.
+func wadExp(x *big.Int) (*big.Int, error) {
+	if x.Cmp(wadExpMin) <= 0 {
+		return big.NewInt(0), nil
+	}
+	if x.Cmp(wadExpMax) >= 0 {
+		return nil, ErrWadExpOverflow
+	}
+
+	var x96 = new(big.Int).Quo(new(big.Int).Lsh(x, 78), wadExpFivePow18)
+	var k = new(big.Int).Rsh(
+		new(big.Int).Add(new(big.Int).Quo(new(big.Int).Lsh(x96, 96), wadExpLn2), wadExpHalf), 96,
+	)
+	x96.Sub(x96, new(big.Int).Mul(k, wadExpLn2))
+
+	var y = new(big.Int).Add(x96, wadExpY[0])
+	y = new(big.Int).Add(new(big.Int).Rsh(new(big.Int).Mul(y, x96), 96), wadExpY[1])
+	var p = new(big.Int).Sub(new(big.Int).Add(y, x96), wadExpP[0])
+	p = new(big.Int).Add(new(big.Int).Rsh(new(big.Int).Mul(p, y), 96), wadExpP[1])
+	p = new(big.Int).Add(new(big.Int).Mul(p, x96), wadExpP[2])
+
+	var q = new(big.Int).Sub(x96, wadExpQ[0])
+	for i, c := range wadExpQ[1:] {
+		q = new(big.Int).Rsh(new(big.Int).Mul(q, x96), 96)
+		if i%2 == 0 {
+			q.Add(q, c)
+		} else {
+			q.Sub(q, c)
+		}
+	}
+
+	var r = new(big.Int).Quo(p, q)
+	return r.Rsh(r.Mul(r, wadExpScale), uint(195-k.Int64())), nil
+}
+
+// getP returns the prices of the coins 1.. in coin 0 at the scaled balances xp, the contracts compute them
+// analytically in get_p, here they come from the amounts of the coins bought by a small amount of coin 0.
+func getP(A, gamma *big.Int, xp []*big.Int, D *big.Int, priceScale []*big.Int) ([]*big.Int, error) {
+	var nCoins = len(xp)
+	var xpDx = make([]*big.Int, nCoins)
+	copy(xpDx, xp)
+	var dx = new(big.Int).Div(xp[0], bignumber.TenPowInt(6))
+	xpDx[0] = new(big.Int).Add(xp[0], dx)
+
+	var prices = make([]*big.Int, nCoins-1)
+	for k := 0; k < nCoins-1; k += 1 {
+		y, err := newtonY(A, gamma, xpDx, D, k+1)
+		if err != nil {
+			return nil, err
+		}
+		var dy = new(big.Int).Sub(xp[k+1], y)
+		if dy.Sign() <= 0 {
+			return nil, ErrDenominatorZero
+		}
+		prices[k] = new(big.Int).Div(new(big.Int).Mul(priceScale[k], dx), dy)
+	}
+	return prices, nil
+}
+
+// tweakPrice follows tweak_price of tricrypto-ng and twocrypto-ng after a swap left the pool at the scaled balances
+// xp: it moves the price oracle, records the last prices, checks the profit of the pool and moves the price scale
+// towards the price oracle when the profit allows it.
+func (t *PoolSimulator) tweakPrice(A, gamma *big.Int, xp []*big.Int) error {
+	var nCoins = len(xp)
+
+	var priceOracle = make([]*big.Int, nCoins-1)
+	copy(priceOracle, t.PriceOracle)
+	var now = time.Now().Unix()
+	if t.LastPricesTimestamp < now {
+		alpha, err := wadExp(new(big.Int).Neg(new(big.Int).Div(
+			new(big.Int).Mul(big.NewInt(now-t.LastPricesTimestamp), bignumber.BONE), t.MaTime,
+		)))
+		if err != nil {
+			return err
+		}
+		for k := 0; k < nCoins-1; k += 1 {
+			var lastPrice = new(big.Int).Mul(t.PriceScale[k], bignumber.Two)
+			if t.LastPrices[k].Cmp(lastPrice) < 0 {
+				lastPrice = t.LastPrices[k]
+			}
+			priceOracle[k] = new(big.Int).Div(new(big.Int).Add(
+				new(big.Int).Mul(lastPrice, new(big.Int).Sub(bignumber.BONE, alpha)),
+				new(big.Int).Mul(priceOracle[k], alpha),
+			), bignumber.BONE)
+		}
+	}
+
+	DUnadjusted, err := newtonD(A, gamma, xp)
+	if err != nil {
+		return err
+	}
+	lastPrices, err := getP(A, gamma, xp, DUnadjusted, t.PriceScale)
+	if err != nil {
+		return err
+	}
+
+	var virtualPrice, xcpProfit = bignumber.BONE, bignumber.BONE
+	if t.VirtualPrice.Sign() > 0 {
+		xcp, err := geometricMean(t.balancedXp(DUnadjusted, t.PriceScale))
+		if err != nil {
+			return err
+		}
+		virtualPrice = new(big.Int).Div(new(big.Int).Mul(bignumber.BONE, xcp), t.LpSupply)
+		xcpProfit = new(big.Int).Div(new(big.Int).Mul(t.XcpProfit, virtualPrice), t.VirtualPrice)
+		if virtualPrice.Cmp(t.VirtualPrice) < 0 {
+			return ErrLoss
+		}
+	}
+
+	t.PriceOracle, t.LastPrices, t.LastPricesTimestamp = priceOracle, lastPrices, now
+	t.XcpProfit = xcpProfit
+	t.D, t.VirtualPrice = DUnadjusted, virtualPrice
+
+	if new(big.Int).Sub(new(big.Int).Mul(virtualPrice, bignumber.Two), bignumber.BONE).Cmp(
+		new(big.Int).Add(xcpProfit, new(big.Int).Mul(bignumber.Two, t.AllowedExtraProfit))) <= 0 {
+		return nil
+	}
+
+	var norm = big.NewInt(0)
+	for k := 0; k < nCoins-1; k += 1 {
+		var ratio = absDiff(new(big.Int).Div(new(big.Int).Mul(priceOracle[k], bignumber.BONE), t.PriceScale[k]), bignumber.BONE)
+		norm.Add(norm, new(big.Int).Mul(ratio, ratio))
+	}
+	norm.Sqrt(norm)
+	var adjustmentStep = new(big.Int).Div(norm, big.NewInt(5))
+	if t.AdjustmentStep.Cmp(adjustmentStep) > 0 {
+		adjustmentStep = t.AdjustmentStep
+	}
+	if norm.Cmp(adjustmentStep) <= 0 {
+		return nil
+	}
+
+	var priceScale = make([]*big.Int, nCoins-1)
+	var xpNew = make([]*big.Int, nCoins)
+	xpNew[0] = xp[0]
+	for k := 0; k < nCoins-1; k += 1 {
+		priceScale[k] = new(big.Int).Div(new(big.Int).Add(
+			new(big.Int).Mul(t.PriceScale[k], new(big.Int).Sub(norm, adjustmentStep)),
+			new(big.Int).Mul(adjustmentStep, priceOracle[k]),
+		), norm)
+		xpNew[k+1] = new(big.Int).Div(new(big.Int).Mul(xp[k+1], priceScale[k]), t.PriceScale[k])
+	}
+	D, err := newtonD(A, gamma, xpNew)
+	if err != nil {
+		return err
+	}
+	xcp, err := geometricMean(t.balancedXp(D, priceScale))
+	if err != nil {
+		return err
+	}
+	var newVirtualPrice = new(big.Int).Div(new(big.Int).Mul(bignumber.BONE, xcp), t.LpSupply)
+	if newVirtualPrice.Cmp(bignumber.BONE) > 0 &&
+		new(big.Int).Sub(new(big.Int).Mul(bignumber.Two, newVirtualPrice), bignumber.BONE).Cmp(xcpProfit) > 0 {
+		t.PriceScale, t.D, t.VirtualPrice = priceScale, D, newVirtualPrice
+	}
+	return nil
+}
+
+// balancedXp returns the scaled balances of a balanced pool with the invariant D at the price scale.
+func (t *PoolSimulator) balancedXp(D *big.Int, priceScale []*big.Int) []*big.Int {
+	var nCoinsBi = big.NewInt(int64(len(priceScale) + 1))
+	var xp = make([]*big.Int, len(priceScale)+1)
+	xp[0] = new(big.Int).Div(D, nCoinsBi)
+	for k := range priceScale {
+		xp[k+1] = new(big.Int).Div(new(big.Int).Mul(D, Precision), new(big.Int).Mul(nCoinsBi, priceScale[k]))
+	}
+	return xp
+}
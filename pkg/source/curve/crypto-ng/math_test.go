@@ -0,0 +1,28 @@
+package cryptong
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+func TestWadExp(t *testing.T) {
+	for _, x := range []float64{-40, -5, -1, -0.1, 0, 1, 3} {
+		r, err := wadExp(new(big.Int).Mul(big.NewInt(int64(x*1e6)), bignumber.TenPowInt(12)))
+		require.NoError(t, err)
+		f, _ := new(big.Float).Quo(new(big.Float).SetInt(r), new(big.Float).SetInt(bignumber.BONE)).Float64()
+		assert.InDelta(t, math.Exp(x), f, 1e-15*math.Max(1, math.Exp(x)))
+	}
+
+	r, err := wadExp(bignumber.NewBig10("-42000000000000000000"))
+	require.NoError(t, err)
+	assert.Zero(t, r.Sign())
+
+	_, err = wadExp(bignumber.NewBig10("136000000000000000000"))
+	assert.ErrorIs(t, err, ErrWadExpOverflow)
+}
@@ -0,0 +1,198 @@
+package cryptong
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/KyberNetwork/logger"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/curve"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// PoolSimulator simulates both tricrypto-ng (3 coins) and twocrypto-ng (2 coins) pools,
+// the math of the 2 pools only differs by the number of coins.
+type PoolSimulator struct {
+	pool.Pool
+	Precisions        []*big.Int
+	A                 *big.Int
+	Gamma             *big.Int
+	D                 *big.Int
+	FeeGamma          *big.Int
+	MidFee            *big.Int
+	OutFee            *big.Int
+	FutureAGammaTime  int64
+	FutureAGamma      *big.Int
+	InitialAGammaTime int64
+	InitialAGamma     *big.Int
+
+	PriceScale          []*big.Int
+	PriceOracle         []*big.Int
+	LastPrices          []*big.Int
+	LastPricesTimestamp int64
+
+	LpToken            string
+	LpSupply           *big.Int
+	XcpProfit          *big.Int
+	VirtualPrice       *big.Int
+	AllowedExtraProfit *big.Int
+	AdjustmentStep     *big.Int
+	MaTime             *big.Int
+	gas                Gas
+}
+
+type Gas struct {
+	Exchange int64
+}
+
+func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
+	var staticExtra curve.PoolCryptoNgStaticExtra
+	if err := json.Unmarshal([]byte(entityPool.StaticExtra), &staticExtra); err != nil {
+		return nil, err
+	}
+
+	var extra curve.PoolCryptoNgExtra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	numTokens := len(entityPool.Tokens)
+	gas, ok := DefaultGas[numTokens]
+	if !ok || len(entityPool.Reserves) < numTokens || len(staticExtra.PrecisionMultipliers) != numTokens ||
+		len(extra.PriceScale) < numTokens-1 {
+		return nil, ErrInvalidNumberOfCoins
+	}
+
+	tokens := make([]string, numTokens)
+	reserves := make([]*big.Int, numTokens)
+	precisions := make([]*big.Int, numTokens)
+	for i := 0; i < numTokens; i += 1 {
+		tokens[i] = entityPool.Tokens[i].Address
+		reserves[i] = bignumber.NewBig10(entityPool.Reserves[i])
+		precisions[i] = bignumber.NewBig10(staticExtra.PrecisionMultipliers[i])
+	}
+
+	toBigInts := func(values []string) []*big.Int {
+		var ret = make([]*big.Int, 0, numTokens-1)
+		for i := 0; i < numTokens-1 && i < len(values); i += 1 {
+			ret = append(ret, bignumber.NewBig10(values[i]))
+		}
+		return ret
+	}
+
+	return &PoolSimulator{
+		Pool: pool.Pool{
+			Info: pool.PoolInfo{
+				Address:    strings.ToLower(entityPool.Address),
+				ReserveUsd: entityPool.ReserveUsd,
+				SwapFee:    bignumber.ZeroBI,
+				Exchange:   entityPool.Exchange,
+				Type:       entityPool.Type,
+				Tokens:     tokens,
+				Reserves:   reserves,
+				Checked:    false,
+			},
+		},
+		Precisions:        precisions,
+		A:                 bignumber.NewBig10(extra.A),
+		D:                 bignumber.NewBig10(extra.D),
+		Gamma:             bignumber.NewBig10(extra.Gamma),
+		FeeGamma:          bignumber.NewBig10(extra.FeeGamma),
+		MidFee:            bignumber.NewBig10(extra.MidFee),
+		OutFee:            bignumber.NewBig10(extra.OutFee),
+		FutureAGammaTime:  extra.FutureAGammaTime,
+		FutureAGamma:      bignumber.NewBig10(extra.FutureAGamma),
+		InitialAGammaTime: extra.InitialAGammaTime,
+		InitialAGamma:     bignumber.NewBig10(extra.InitialAGamma),
+
+		PriceScale:  toBigInts(extra.PriceScale),
+		PriceOracle: toBigInts(extra.PriceOracle),
+		LastPrices:  toBigInts(extra.LastPrices),
+		// twocrypto-ng packs the timestamp of the last xcp update above the one of the last prices
+		LastPricesTimestamp: new(big.Int).And(bignumber.NewBig10(extra.LastTimestamp), TimestampMask).Int64(),
+
+		LpToken:            staticExtra.LpToken,
+		LpSupply:           bignumber.NewBig10(extra.LpSupply),
+		XcpProfit:          bignumber.NewBig10(extra.XcpProfit),
+		VirtualPrice:       bignumber.NewBig10(extra.VirtualPrice),
+		AllowedExtraProfit: bignumber.NewBig10(extra.AllowedExtraProfit),
+		AdjustmentStep:     bignumber.NewBig10(extra.AdjustmentStep),
+		MaTime:             bignumber.NewBig10(extra.MaTime),
+		gas:                gas,
+	}, nil
+}
+
+func (t *PoolSimulator) CalcAmountOut(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	var tokenIndexFrom = t.Info.GetTokenIndex(tokenAmountIn.Token)
+	var tokenIndexTo = t.Info.GetTokenIndex(tokenOut)
+	if tokenIndexFrom >= 0 && tokenIndexTo >= 0 {
+		amountOut, fee, _, err := t.GetDy(tokenIndexFrom, tokenIndexTo, tokenAmountIn.Amount)
+		if err != nil {
+			return &pool.CalcAmountOutResult{}, err
+		}
+		if amountOut.Cmp(t.Info.Reserves[tokenIndexTo]) >= 0 {
+			return &pool.CalcAmountOutResult{}, ErrExchangeMoreThanBalance
+		}
+		if amountOut.Cmp(bignumber.ZeroBI) > 0 {
+			return &pool.CalcAmountOutResult{
+				TokenAmountOut: &pool.TokenAmount{
+					Token:  tokenOut,
+					Amount: amountOut,
+				},
+				Fee: &pool.TokenAmount{
+					Token:  tokenOut,
+					Amount: fee,
+				},
+				Gas: t.gas.Exchange,
+			}, nil
+		}
+	}
+	return &pool.CalcAmountOutResult{}, fmt.Errorf("tokenIndexFrom %v or tokenIndexTo %v is not correct", tokenIndexFrom, tokenIndexTo)
+}
+
+// UpdateBalance updates the balances of the pool, then its D and price scale with tweak_price like exchange does.
+// When tweak_price fails the price scale is kept and only D is updated.
+func (t *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	input, output := params.TokenAmountIn, params.TokenAmountOut
+	var inputIndex = t.GetTokenIndex(input.Token)
+	var outputIndex = t.GetTokenIndex(output.Token)
+	if inputIndex < 0 || outputIndex < 0 {
+		return
+	}
+
+	t.Info.Reserves[inputIndex] = new(big.Int).Add(t.Info.Reserves[inputIndex], input.Amount)
+	t.Info.Reserves[outputIndex] = new(big.Int).Sub(t.Info.Reserves[outputIndex], output.Amount)
+
+	A, gamma := t._A_gamma()
+	var xp = t._xp(t.Info.Reserves)
+	if err := t.tweakPrice(A, gamma, xp); err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": t.Info.Address,
+			"error":       err,
+		}).Warnf("failed to tweak price")
+		if D, err := newtonD(A, gamma, xp); err == nil {
+			t.D = D
+		}
+	}
+}
+
+func (t *PoolSimulator) GetLpToken() string {
+	return t.LpToken
+}
+
+func (t *PoolSimulator) GetMetaInfo(tokenIn string, tokenOut string) interface{} {
+	var fromId = t.GetTokenIndex(tokenIn)
+	var toId = t.GetTokenIndex(tokenOut)
+	return curve.Meta{
+		TokenInIndex:  fromId,
+		TokenOutIndex: toId,
+		Underlying:    false,
+	}
+}
@@ -0,0 +1,102 @@
+package cryptong
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+func TestCalcAmountOut(t *testing.T) {
+	// the states are the same as in the curve two and tricrypto tests, the NG math should give the same results
+	testcases := []struct {
+		pool              entity.Pool
+		in                string
+		inAmount          int64
+		out               string
+		expectedOutAmount int64
+	}{
+		{twocryptoPool, "A", 10, "B", 304},
+		{twocryptoPool, "B", 1000, "A", 172},
+		{tricryptoPool, "A", 1, "C", 600295188},
+		{tricryptoPool, "B", 1, "C", 153286543005},
+		{tricryptoPool, "B", 1, "A", 255},
+		{tricryptoPool, "A", 1000, "B", 3},
+	}
+
+	for idx, tc := range testcases {
+		t.Run(fmt.Sprintf("test %d", idx), func(t *testing.T) {
+			p, err := NewPoolSimulator(tc.pool)
+			require.Nil(t, err)
+
+			out, err := p.CalcAmountOut(pool.TokenAmount{Token: tc.in, Amount: big.NewInt(tc.inAmount)}, tc.out)
+			require.Nil(t, err)
+			assert.Equal(t, big.NewInt(tc.expectedOutAmount), out.TokenAmountOut.Amount)
+			assert.Equal(t, tc.out, out.TokenAmountOut.Token)
+			assert.Equal(t, DefaultGas[len(tc.pool.Tokens)].Exchange, out.Gas)
+		})
+	}
+}
+
+func TestUpdateBalance(t *testing.T) {
+	p, err := NewPoolSimulator(tricryptoPool)
+	require.Nil(t, err)
+
+	amountIn := big.NewInt(100000000) // 1 WBTC
+	before, err := p.CalcAmountOut(pool.TokenAmount{Token: "B", Amount: amountIn}, "C")
+	require.Nil(t, err)
+
+	p.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  pool.TokenAmount{Token: "B", Amount: amountIn},
+		TokenAmountOut: *before.TokenAmountOut,
+		Fee:            *before.Fee,
+	})
+	assert.Equal(t, "212971488312", p.Info.Reserves[1].String())
+
+	// the same swap gets a worse price after the balances were updated
+	after, err := p.CalcAmountOut(pool.TokenAmount{Token: "B", Amount: amountIn}, "C")
+	require.Nil(t, err)
+	assert.Equal(t, -1, after.TokenAmountOut.Amount.Cmp(before.TokenAmountOut.Amount))
+}
+
+func TestUpdateBalance_TweakPrice(t *testing.T) {
+	p, err := NewPoolSimulator(twocryptoPool)
+	require.Nil(t, err)
+
+	amountIn := bignumber.BONE
+	out, err := p.CalcAmountOut(pool.TokenAmount{Token: "A", Amount: amountIn}, "B")
+	require.Nil(t, err)
+	p.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  pool.TokenAmount{Token: "A", Amount: amountIn},
+		TokenAmountOut: *out.TokenAmountOut,
+		Fee:            *out.Fee,
+	})
+
+	// the last prices move to the oracle, which then pulls the price scale down
+	assert.Equal(t, "1241874208010789089", p.PriceOracle[0].String())
+	assert.Equal(t, "1248401934431433858", p.PriceScale[0].String())
+	assert.Equal(t, "1671864259860480256", p.LastPrices[0].String())
+	assert.Equal(t, "1034190144222703397", p.XcpProfit.String())
+}
+
+var twocryptoPool = entity.Pool{
+	Type:        "curve-twocrypto-ng",
+	Reserves:    entity.PoolReserves{"2575977394749099472751", "1447320191806527553931"},
+	Tokens:      []*entity.PoolToken{{Address: "A"}, {Address: "B"}},
+	Extra:       `{"A":"200000000","D":"4344269418800893049364","gamma":"100000000000000","priceScale":["1250033866036595049"],"lastPrices":["1241874208010789089"],"priceOracle":["1199834141509881054"],"feeGamma":"5000000000000000","midFee":"10000000","outFee":"90000000","futureAGammaTime":0,"futureAGamma":"68056473384187692692674921486353742291200000000","initialAGammaTime":0,"initialAGamma":"68056473384187692692674921486353742291200000000","lastTimestamp":"1686876995","lpSupply":"1894549993474267797965","xcpProfit":"1034188512253919548","virtualPrice":"1025462529694819838","allowedExtraProfit":"10000000000","adjustmentStep":"5500000000000","maTime":"866"}`,
+	StaticExtra: `{"lpToken":"LP","precisionMultipliers":["1","1"]}`,
+}
+
+var tricryptoPool = entity.Pool{
+	Type:        "curve-tricrypto-ng",
+	Reserves:    entity.PoolReserves{"54743954382801", "212871488312", "32759437840549558629494"},
+	Tokens:      []*entity.PoolToken{{Address: "A"}, {Address: "B"}, {Address: "C"}},
+	Extra:       `{"A":"1707629","D":"162458225493710120387117207","gamma":"11809167828997","priceScale":["25182439404844022315525","1651754874918630176109"],"lastPrices":["25550848343816062635020","1663587698754935470890"],"priceOracle":["25509537194730788716548","1663683592023356857621"],"feeGamma":"500000000000000","midFee":"3000000","outFee":"30000000","futureAGammaTime":0,"futureAGamma":"581076037942835227425498917514114728328226821","initialAGammaTime":1633548703,"initialAGamma":"183752478137306770270222288013175834186240000","lastTimestamp":"1686880115","lpSupply":"151463393077555004737648","xcpProfit":"1063768763992698993","virtualPrice":"1031885802695565056","allowedExtraProfit":"2000000000000","adjustmentStep":"490000000000000","maTime":"866"}`,
+	StaticExtra: `{"lpToken":"LP","precisionMultipliers":["1000000000000","10000000000","1"]}`,
+}
@@ -41,6 +41,24 @@ var oracleABIBytes []byte
 //go:embed abi/Compound.json
 var compoundABIBytes []byte
 
+//go:embed abi/StableSwapNGFactory.json
+var stableSwapNGFactoryABIBytes []byte
+
+//go:embed abi/StableSwapNG.json
+var stableSwapNGABIBytes []byte
+
+//go:embed abi/TricryptoNGFactory.json
+var tricryptoNGFactoryABIBytes []byte
+
+//go:embed abi/TricryptoNG.json
+var tricryptoNGABIBytes []byte
+
+//go:embed abi/TwocryptoNGFactory.json
+var twocryptoNGFactoryABIBytes []byte
+
+//go:embed abi/TwocryptoNG.json
+var twocryptoNGABIBytes []byte
+
 //go:embed abi/ERC20.json
 var erc20ABIBytes []byte
 
@@ -0,0 +1,265 @@
+package curve
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+)
+
+// getNewPoolsTypeCryptoNg gets new pools of both tricrypto-ng and twocrypto-ng factories.
+// The factories return fixed size arrays, so the number of coins is decided by the pool type.
+func (d *PoolsListUpdater) getNewPoolsTypeCryptoNg(
+	ctx context.Context,
+	poolType string,
+	poolAndRegistries []PoolAndRegistries,
+) ([]entity.Pool, error) {
+	var (
+		coins    = make([][]common.Address, len(poolAndRegistries))
+		decimals = make([][]*big.Int, len(poolAndRegistries))
+
+		coins2    = make([][2]common.Address, len(poolAndRegistries))
+		decimals2 = make([][2]*big.Int, len(poolAndRegistries))
+		coins3    = make([][3]common.Address, len(poolAndRegistries))
+		decimals3 = make([][3]*big.Int, len(poolAndRegistries))
+	)
+
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+
+	for i, poolAndRegistry := range poolAndRegistries {
+		var coinsOut, decimalsOut interface{} = &coins3[i], &decimals3[i]
+		if poolType == poolTypeTwocryptoNg {
+			coinsOut, decimalsOut = &coins2[i], &decimals2[i]
+		}
+
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolAndRegistry.RegistryOrFactoryABI,
+			Target: poolAndRegistry.RegistryOrFactoryAddress,
+			Method: registryOrFactoryMethodGetCoins,
+			Params: []interface{}{poolAndRegistry.PoolAddress},
+		}, []interface{}{coinsOut})
+
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolAndRegistry.RegistryOrFactoryABI,
+			Target: poolAndRegistry.RegistryOrFactoryAddress,
+			Method: registryOrFactoryMethodGetDecimals,
+			Params: []interface{}{poolAndRegistry.PoolAddress},
+		}, []interface{}{decimalsOut})
+	}
+
+	if _, err := calls.Aggregate(); err != nil {
+		logger.WithFields(logger.Fields{
+			"error": err,
+		}).Errorf("failed to aggregate call to get pool data")
+		return nil, err
+	}
+
+	for i := range poolAndRegistries {
+		if poolType == poolTypeTwocryptoNg {
+			coins[i], decimals[i] = coins2[i][:], decimals2[i][:]
+		} else {
+			coins[i], decimals[i] = coins3[i][:], decimals3[i][:]
+		}
+	}
+
+	var pools = make([]entity.Pool, len(poolAndRegistries))
+	for i := range poolAndRegistries {
+		var reserves entity.PoolReserves
+		var tokens []*entity.PoolToken
+		var staticExtra = PoolCryptoNgStaticExtra{
+			LpToken: strings.ToLower(poolAndRegistries[i].PoolAddress.Hex()),
+		}
+		for j := range coins[i] {
+			coinAddress := convertToEtherAddress(coins[i][j].Hex(), d.config.ChainID)
+			precision := new(big.Int).Exp(big.NewInt(10), new(big.Int).Sub(big.NewInt(18), decimals[i][j]), nil)
+			staticExtra.PrecisionMultipliers = append(staticExtra.PrecisionMultipliers, precision.String())
+			reserves = append(reserves, zeroString)
+			tokens = append(tokens, &entity.PoolToken{
+				Address:   strings.ToLower(coinAddress),
+				Weight:    defaultWeight,
+				Swappable: true,
+			})
+		}
+		staticExtraBytes, err := json.Marshal(staticExtra)
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"error": err,
+			}).Errorf("failed to marshal static extra data")
+			return nil, err
+		}
+
+		pools[i] = entity.Pool{
+			Address:     strings.ToLower(poolAndRegistries[i].PoolAddress.Hex()),
+			Exchange:    DexTypeCurve,
+			Type:        poolType,
+			Timestamp:   time.Now().Unix(),
+			Reserves:    reserves,
+			Tokens:      tokens,
+			StaticExtra: string(staticExtraBytes),
+		}
+	}
+
+	return pools, nil
+}
+
+// Smart contract code:
+// tricrypto-ng: https://etherscan.io/address/0x7f86bf177dd4f3494b841a37e810a34dd56c829b#code
+// twocrypto-ng: https://etherscan.io/address/0x04d1a3ad2ef8e4ab8b7c87f9fbe2dfef3bc0e5c4#code
+// The twocrypto-ng pool only has 1 price, so price_scale, price_oracle and last_prices do not take an index.
+func (d *PoolTracker) getNewPoolStateTypeCryptoNg(
+	ctx context.Context,
+	p entity.Pool,
+) (entity.Pool, error) {
+	logger.Infof("[Curve] Start getting new state of pool %v with type %v", p.Address, p.Type)
+
+	var (
+		a, dExtra, gamma, feeGamma, midFee, outFee, futureAGammaTime, futureAGamma, initialAGammaTime, initialAGamma *big.Int
+
+		lastTimestamp, xcpProfit, virtualPrice, allowedExtraProfit, adjustmentStep, maTime, lpSupply *big.Int
+
+		balances = make([]*big.Int, len(p.Tokens))
+
+		// These 3 slices only has length = number of tokens - 1 (check in the contract)
+		priceScales  = make([]*big.Int, len(p.Tokens)-1)
+		priceOracles = make([]*big.Int, len(p.Tokens)-1)
+		lastPrices   = make([]*big.Int, len(p.Tokens)-1)
+	)
+
+	var poolABI abi.ABI
+	if p.Type == poolTypeTwocryptoNg {
+		poolABI = twocryptoNGABI
+	} else {
+		poolABI = tricryptoNGABI
+	}
+
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+
+	for method, out := range map[string]**big.Int{
+		poolMethodA:                  &a,
+		poolMethodD:                  &dExtra,
+		poolMethodGamma:              &gamma,
+		poolMethodFeeGamma:           &feeGamma,
+		poolMethodMidFee:             &midFee,
+		poolMethodOutFee:             &outFee,
+		poolMethodFutureAGammaTime:   &futureAGammaTime,
+		poolMethodFutureAGamma:       &futureAGamma,
+		poolMethodInitialAGammaTime:  &initialAGammaTime,
+		poolMethodInitialAGamma:      &initialAGamma,
+		poolMethodLastTimestamp:      &lastTimestamp,
+		poolMethodXcpProfit:          &xcpProfit,
+		poolMethodVirtualPrice:       &virtualPrice,
+		poolMethodAllowedExtraProfit: &allowedExtraProfit,
+		poolMethodAdjustmentStep:     &adjustmentStep,
+		poolMethodMaTime:             &maTime,
+		erc20MethodTotalSupply:       &lpSupply,
+	} {
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: p.Address,
+			Method: method,
+			Params: nil,
+		}, []interface{}{out})
+	}
+
+	for i := range p.Tokens {
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: p.Address,
+			Method: poolMethodBalances,
+			Params: []interface{}{big.NewInt(int64(i))},
+		}, []interface{}{&balances[i]})
+	}
+
+	for i := 0; i < len(p.Tokens)-1; i++ {
+		var params []interface{}
+		if p.Type != poolTypeTwocryptoNg {
+			params = []interface{}{big.NewInt(int64(i))}
+		}
+
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: p.Address,
+			Method: poolMethodPriceScale,
+			Params: params,
+		}, []interface{}{&priceScales[i]})
+
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: p.Address,
+			Method: poolMethodPriceOracle,
+			Params: params,
+		}, []interface{}{&priceOracles[i]})
+
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: p.Address,
+			Method: poolMethodLastPrices,
+			Params: params,
+		}, []interface{}{&lastPrices[i]})
+	}
+
+	if _, err := calls.Aggregate(); err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"poolType":    p.Type,
+			"error":       err,
+		}).Errorf("failed to aggregate call pool data")
+		return entity.Pool{}, err
+	}
+
+	toStrings := func(values []*big.Int) []string {
+		return lo.Map(values, func(value *big.Int, _ int) string {
+			return value.String()
+		})
+	}
+
+	var extra = PoolCryptoNgExtra{
+		A:                  a.String(),
+		D:                  dExtra.String(),
+		Gamma:              gamma.String(),
+		FeeGamma:           feeGamma.String(),
+		MidFee:             midFee.String(),
+		OutFee:             outFee.String(),
+		FutureAGammaTime:   futureAGammaTime.Int64(),
+		FutureAGamma:       futureAGamma.String(),
+		InitialAGammaTime:  initialAGammaTime.Int64(),
+		InitialAGamma:      initialAGamma.String(),
+		LastTimestamp:      lastTimestamp.String(),
+		XcpProfit:          xcpProfit.String(),
+		VirtualPrice:       virtualPrice.String(),
+		AllowedExtraProfit: allowedExtraProfit.String(),
+		AdjustmentStep:     adjustmentStep.String(),
+		MaTime:             maTime.String(),
+
+		PriceScale:  toStrings(priceScales),
+		LastPrices:  toStrings(lastPrices),
+		PriceOracle: toStrings(priceOracles),
+		LpSupply:    lpSupply.String(),
+	}
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"poolType":    p.Type,
+			"error":       err,
+		}).Errorf("failed to marshal extra data")
+		return entity.Pool{}, err
+	}
+
+	p.Extra = string(extraBytes)
+	p.Timestamp = time.Now().Unix()
+	p.Reserves = toStrings(balances)
+
+	logger.Infof("[Curve] Finish getting new state of pool %v with type %v", p.Address, p.Type)
+
+	return p, nil
+}
@@ -27,6 +27,12 @@ func (d *PoolsListUpdater) classifyPoolTypes(
 		return d.classifyCurveV2PoolTypes(ctx, registryOrFactoryABI, registryOrFactoryAddress, poolAddresses)
 	case sourceCryptoPoolsFactory:
 		return d.classifyCurveV2PoolTypes(ctx, registryOrFactoryABI, registryOrFactoryAddress, poolAddresses)
+	case sourceStableSwapNGFactory:
+		return d.classifyPoolsFromStableSwapNGFactory(ctx, registryOrFactoryABI, registryOrFactoryAddress, poolAddresses)
+	case sourceTricryptoNGFactory:
+		return classifyPoolsAs(poolTypeTricryptoNg, poolAddresses), nil
+	case sourceTwocryptoNGFactory:
+		return classifyPoolsAs(poolTypeTwocryptoNg, poolAddresses), nil
 	default:
 		// Index can be found here https://github.com/KyberNetwork/kyberswap-dex-lib/blob/0e4796ffde08481ef8b456e354cf2cb7b3aa8268/pkg/source/curve/pools_list_updater.go#L69-L79
 		logger.Errorf("unknown pools source index %v", poolsSourceIndex)
@@ -228,6 +234,56 @@ func (d *PoolsListUpdater) classifyCurveV2PoolTypes(
 	return poolTypes, nil
 }
 
+// classifyPoolsFromStableSwapNGFactory includes stable-ng only, the ng metapools are not supported yet
+func (d *PoolsListUpdater) classifyPoolsFromStableSwapNGFactory(
+	ctx context.Context,
+	registryOrFactoryABI abi.ABI,
+	registryOrFactoryAddress string,
+	poolAddresses []common.Address,
+) ([]string, error) {
+	var isMetaList = make([]bool, len(poolAddresses))
+
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+
+	for i, poolAddress := range poolAddresses {
+		calls.AddCall(&ethrpc.Call{
+			ABI:    registryOrFactoryABI,
+			Target: registryOrFactoryAddress,
+			Method: registryOrFactoryMethodIsMeta,
+			Params: []interface{}{poolAddress},
+		}, []interface{}{&isMetaList[i]})
+	}
+
+	if _, err := calls.Aggregate(); err != nil {
+		logger.WithFields(logger.Fields{
+			"error": err,
+		}).Errorf("failed to aggregate to get pool data")
+		return nil, err
+	}
+
+	var poolTypes = make([]string, len(poolAddresses))
+	for i := range poolAddresses {
+		if isMetaList[i] {
+			logger.Infof("unsupported curve stable-ng metapool: %s", poolAddresses[i].Hex())
+			poolTypes[i] = poolTypeUnsupported
+			continue
+		}
+		poolTypes[i] = poolTypeStableNg
+	}
+
+	return poolTypes, nil
+}
+
+// classifyPoolsAs is used for factories which only deploy a single pool type (tricrypto-ng, twocrypto-ng)
+func classifyPoolsAs(poolType string, poolAddresses []common.Address) []string {
+	var poolTypes = make([]string, len(poolAddresses))
+	for i := range poolTypes {
+		poolTypes[i] = poolType
+	}
+
+	return poolTypes
+}
+
 // isBasePool PlainOraclePool should
 // be a BasePool but having method "oracle" in its contract
 func (d *PoolsListUpdater) isPlainOraclePool(oracleAddress common.Address) bool {
@@ -0,0 +1,241 @@
+package curve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+)
+
+func (d *PoolsListUpdater) getNewPoolsTypeStableNg(
+	ctx context.Context,
+	poolAndRegistries []PoolAndRegistries,
+) ([]entity.Pool, error) {
+	var (
+		coins      = make([][]common.Address, len(poolAndRegistries))
+		assetTypes = make([][]uint8, len(poolAndRegistries))
+	)
+
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+
+	for i, poolAndRegistry := range poolAndRegistries {
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolAndRegistry.RegistryOrFactoryABI,
+			Target: poolAndRegistry.RegistryOrFactoryAddress,
+			Method: registryOrFactoryMethodGetCoins,
+			Params: []interface{}{poolAndRegistry.PoolAddress},
+		}, []interface{}{&coins[i]})
+
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolAndRegistry.RegistryOrFactoryABI,
+			Target: poolAndRegistry.RegistryOrFactoryAddress,
+			Method: registryOrFactoryMethodGetPoolAssetTypes,
+			Params: []interface{}{poolAndRegistry.PoolAddress},
+		}, []interface{}{&assetTypes[i]})
+	}
+
+	if _, err := calls.Aggregate(); err != nil {
+		logger.WithFields(logger.Fields{
+			"error": err,
+		}).Errorf("failed to aggregate call to get pool data")
+		return nil, err
+	}
+
+	var pools = make([]entity.Pool, 0, len(poolAndRegistries))
+	for i := range poolAndRegistries {
+		if len(coins[i]) == 0 || len(coins[i]) != len(assetTypes[i]) {
+			logger.WithFields(logger.Fields{
+				"poolAddress": poolAndRegistries[i].PoolAddress,
+			}).Errorf("pool with mismatched coins and asset types is not valid")
+			continue
+		}
+
+		var reserves entity.PoolReserves
+		var tokens []*entity.PoolToken
+		var staticExtra = PoolStableNgStaticExtra{
+			LpToken:    strings.ToLower(poolAndRegistries[i].PoolAddress.Hex()),
+			AssetTypes: assetTypes[i],
+		}
+		for j := range coins[i] {
+			coinAddress := convertToEtherAddress(coins[i][j].Hex(), d.config.ChainID)
+			reserves = append(reserves, zeroString)
+			tokens = append(tokens, &entity.PoolToken{
+				Address:   strings.ToLower(coinAddress),
+				Weight:    defaultWeight,
+				Swappable: true,
+			})
+		}
+		staticExtraBytes, err := json.Marshal(staticExtra)
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"error": err,
+			}).Errorf("failed to marshal static extra data")
+			return nil, err
+		}
+
+		// initial totalSupply
+		reserves = append(reserves, zeroString)
+
+		pools = append(pools, entity.Pool{
+			Address:     strings.ToLower(poolAndRegistries[i].PoolAddress.Hex()),
+			Exchange:    DexTypeCurve,
+			Type:        poolTypeStableNg,
+			Timestamp:   time.Now().Unix(),
+			Reserves:    reserves,
+			Tokens:      tokens,
+			StaticExtra: string(staticExtraBytes),
+		})
+	}
+
+	return pools, nil
+}
+
+// Smart contract code: https://etherscan.io/address/0x6a8cbed756804b16e05e741edabd5cb544ae21bf#code
+// The stored rates already take the asset type of each coin into account (oracle rate, ERC4626 convertToAssets),
+// and get_balances excludes the admin balances, which matters for rebasing coins.
+func (d *PoolTracker) getNewPoolStateTypeStableNg(
+	ctx context.Context,
+	p entity.Pool,
+) (entity.Pool, error) {
+	logger.Infof("[%s] Start getting new state of pool %v with type %v", d.config.DexID, p.Address, p.Type)
+
+	var (
+		initialA, futureA, initialATime, futureATime, swapFee, adminFee, offpegFeeMultiplier, lpSupply *big.Int
+
+		balances, storedRates []*big.Int
+	)
+
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+
+	calls.AddCall(&ethrpc.Call{
+		ABI:    stableSwapNGABI,
+		Target: p.Address,
+		Method: poolMethodInitialA,
+		Params: nil,
+	}, []interface{}{&initialA})
+
+	calls.AddCall(&ethrpc.Call{
+		ABI:    stableSwapNGABI,
+		Target: p.Address,
+		Method: poolMethodFutureA,
+		Params: nil,
+	}, []interface{}{&futureA})
+
+	calls.AddCall(&ethrpc.Call{
+		ABI:    stableSwapNGABI,
+		Target: p.Address,
+		Method: poolMethodInitialATime,
+		Params: nil,
+	}, []interface{}{&initialATime})
+
+	calls.AddCall(&ethrpc.Call{
+		ABI:    stableSwapNGABI,
+		Target: p.Address,
+		Method: poolMethodFutureATime,
+		Params: nil,
+	}, []interface{}{&futureATime})
+
+	calls.AddCall(&ethrpc.Call{
+		ABI:    stableSwapNGABI,
+		Target: p.Address,
+		Method: poolMethodFee,
+		Params: nil,
+	}, []interface{}{&swapFee})
+
+	calls.AddCall(&ethrpc.Call{
+		ABI:    stableSwapNGABI,
+		Target: p.Address,
+		Method: poolMethodAdminFee,
+		Params: nil,
+	}, []interface{}{&adminFee})
+
+	calls.AddCall(&ethrpc.Call{
+		ABI:    stableSwapNGABI,
+		Target: p.Address,
+		Method: aaveMethodOffpegFeeMultiplier,
+		Params: nil,
+	}, []interface{}{&offpegFeeMultiplier})
+
+	calls.AddCall(&ethrpc.Call{
+		ABI:    stableSwapNGABI,
+		Target: p.Address,
+		Method: poolMethodGetBalances,
+		Params: nil,
+	}, []interface{}{&balances})
+
+	calls.AddCall(&ethrpc.Call{
+		ABI:    stableSwapNGABI,
+		Target: p.Address,
+		Method: poolMethodStoredRates,
+		Params: nil,
+	}, []interface{}{&storedRates})
+
+	calls.AddCall(&ethrpc.Call{
+		ABI:    erc20ABI,
+		Target: p.GetLpToken(),
+		Method: erc20MethodTotalSupply,
+		Params: nil,
+	}, []interface{}{&lpSupply})
+
+	if _, err := calls.Aggregate(); err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"poolType":    p.Type,
+			"error":       err,
+		}).Errorf("failed to aggregate call pool data")
+		return entity.Pool{}, err
+	}
+
+	if len(balances) != len(p.Tokens) || len(storedRates) != len(p.Tokens) {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"poolType":    p.Type,
+		}).Errorf("number of balances or stored rates does not match number of tokens")
+		return entity.Pool{}, errors.New("invalid number of coins")
+	}
+
+	var extra = PoolStableNgExtra{
+		InitialA:            safeCastBigIntToString(initialA),
+		FutureA:             safeCastBigIntToString(futureA),
+		InitialATime:        safeCastBigIntToInt64(initialATime),
+		FutureATime:         safeCastBigIntToInt64(futureATime),
+		SwapFee:             safeCastBigIntToString(swapFee),
+		AdminFee:            safeCastBigIntToString(adminFee),
+		OffpegFeeMultiplier: safeCastBigIntToString(offpegFeeMultiplier),
+		StoredRates: lo.Map(storedRates, func(value *big.Int, _ int) string {
+			return value.String()
+		}),
+	}
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"poolType":    p.Type,
+			"error":       err,
+		}).Errorf("failed to marshal extra data")
+		return entity.Pool{}, err
+	}
+
+	var reserves = make(entity.PoolReserves, 0, len(balances)+1)
+	for i := range balances {
+		reserves = append(reserves, safeCastBigIntToReserve(balances[i]))
+	}
+	reserves = append(reserves, safeCastBigIntToReserve(lpSupply))
+
+	p.Extra = string(extraBytes)
+	p.Timestamp = time.Now().Unix()
+	p.Reserves = reserves
+
+	logger.Infof("[Curve] Finish getting new state of pool %v with type %v", p.Address, p.Type)
+
+	return p, nil
+}
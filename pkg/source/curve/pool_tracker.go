@@ -52,6 +52,10 @@ func (d *PoolTracker) GetNewPoolState(
 		return d.getNewPoolStateTypeTwo(ctx, p)
 	case poolTypeTricrypto:
 		return d.getNewPoolStateTypeTricrypto(ctx, p)
+	case poolTypeStableNg:
+		return d.getNewPoolStateTypeStableNg(ctx, p)
+	case poolTypeTricryptoNg, poolTypeTwocryptoNg:
+		return d.getNewPoolStateTypeCryptoNg(ctx, p)
 	default:
 		logger.WithFields(logger.Fields{
 			"poolAddress": p.Address,
@@ -20,6 +20,9 @@ import (
 // 2. meta pool factory
 // 3. crypto pools registry
 // 4. crypto pools factory
+// 5. stableswap-ng factory
+// 6. tricrypto-ng factory
+// 7. twocrypto-ng factory
 type PoolsSource struct {
 	ABI     abi.ABI
 	Address string
@@ -73,18 +76,24 @@ func (d *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte
 		// 2. meta pool factory
 		// 3. crypto pools registry
 		// 4. crypto pools factory
+		// 5. stableswap-ng factory
+		// 6. tricrypto-ng factory
+		// 7. twocrypto-ng factory
 		// At the moment, we MUST keep the order of the sources like this
 		registryOrFactoryList = []PoolsSource{
 			{mainRegistryABI, d.config.MainRegistryAddress, metadata.MainRegistryOffset},
 			{metaPoolFactoryABI, d.config.MetaPoolsFactoryAddress, metadata.MetaFactoryOffset},
 			{cryptoRegistryABI, d.config.CryptoPoolsRegistryAddress, metadata.CryptoRegistryOffset},
 			{cryptoFactoryABI, d.config.CryptoPoolsFactoryAddress, metadata.CryptoFactoryOffset},
+			{stableSwapNGFactoryABI, d.config.StableSwapNGFactoryAddress, metadata.StableSwapNGFactoryOffset},
+			{tricryptoNGFactoryABI, d.config.TricryptoNGFactoryAddress, metadata.TricryptoNGFactoryOffset},
+			{twocryptoNGFactoryABI, d.config.TwocryptoNGFactoryAddress, metadata.TwocryptoNGFactoryOffset},
 		}
 	)
 
 	if !d.config.SkipInitFactory {
 		for i := 0; i < len(registryOrFactoryList); i++ {
-			if strings.EqualFold(registryOrFactoryList[i].Address, addressZero) {
+			if len(registryOrFactoryList[i].Address) == 0 || strings.EqualFold(registryOrFactoryList[i].Address, addressZero) {
 				logger.Debugf("skip zero factory %v", i)
 				continue
 			}
@@ -138,6 +147,10 @@ func (d *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte
 			newPools, err = d.getNewPoolsTypeTwo(ctx, poolAndRegistries)
 		case poolTypeTricrypto:
 			newPools, err = d.getNewPoolsTypeTricrypto(ctx, poolAndRegistries)
+		case poolTypeStableNg:
+			newPools, err = d.getNewPoolsTypeStableNg(ctx, poolAndRegistries)
+		case poolTypeTricryptoNg, poolTypeTwocryptoNg:
+			newPools, err = d.getNewPoolsTypeCryptoNg(ctx, poolType, poolAndRegistries)
 		default:
 			logger.Infof("skip pool type %v", poolType)
 			continue
@@ -168,6 +181,10 @@ func (d *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte
 		MetaFactoryOffset:    registryOrFactoryList[1].Offset,
 		CryptoRegistryOffset: registryOrFactoryList[2].Offset,
 		CryptoFactoryOffset:  registryOrFactoryList[3].Offset,
+
+		StableSwapNGFactoryOffset: registryOrFactoryList[4].Offset,
+		TricryptoNGFactoryOffset:  registryOrFactoryList[5].Offset,
+		TwocryptoNGFactoryOffset:  registryOrFactoryList[6].Offset,
 	})
 	if err != nil {
 		logger.WithFields(logger.Fields{
@@ -327,6 +344,9 @@ func (d *PoolsListUpdater) initPool() ([]entity.Pool, error) {
 // 2. meta pool factory
 // 3. crypto pools registry
 // 4. crypto pools factory
+// 5. stableswap-ng factory
+// 6. tricrypto-ng factory
+// 7. twocrypto-ng factory
 func (d *PoolsListUpdater) getNewPoolAddressesFromSource(
 	ctx context.Context,
 	poolsSourceIndex int,
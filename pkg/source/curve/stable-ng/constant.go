@@ -0,0 +1,34 @@
+package stableng
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	MaxLoopLimit = 256
+	MaxCoins     = 8
+)
+
+// Asset types of the stableswap-ng coins, see get_pool_asset_types in the factory
+const (
+	AssetTypeStandard uint8 = iota
+	AssetTypeOracle
+	AssetTypeRebasing
+	AssetTypeERC4626
+)
+
+var (
+	DefaultGas     = Gas{Exchange: 145000}
+	Precision      = bignumber.BONE
+	FeeDenominator = bignumber.NewBig10("10000000000")
+	APrecision     = big.NewInt(100)
+
+	// assetTypeGas is the extra gas used to fetch the rate (or the balance) of the coin in the exchange
+	assetTypeGas = map[uint8]int64{
+		AssetTypeOracle:   15000,
+		AssetTypeRebasing: 10000,
+		AssetTypeERC4626:  20000,
+	}
+)
@@ -0,0 +1,14 @@
+package stableng
+
+import "errors"
+
+var (
+	ErrZero                    = errors.New("zero")
+	ErrDDoesNotConverge        = errors.New("d does not converge")
+	ErrYDoesNotConverge        = errors.New("y does not converge")
+	ErrTokenFromEqualsTokenTo  = errors.New("can't compare token to itself")
+	ErrTokenIndexesOutOfRange  = errors.New("token index out of range")
+	ErrTooManyCoins            = errors.New("too many coins")
+	ErrInvalidStoredRates      = errors.New("invalid stored rates")
+	ErrExchangeMoreThanBalance = errors.New("exchange amount is more than balance")
+)
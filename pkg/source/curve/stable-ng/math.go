@@ -0,0 +1,193 @@
+package stableng
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// Reference: https://github.com/curvefi/stableswap-ng/blob/main/contracts/main/CurveStableSwapNG.vy
+
+func (t *PoolSimulator) _A() *big.Int {
+	var t1 = t.FutureATime
+	var a1 = t.FutureA
+	var now = time.Now().Unix()
+	if t1 > now {
+		var t0 = t.InitialATime
+		var a0 = t.InitialA
+		if a1.Cmp(a0) > 0 {
+			return new(big.Int).Add(
+				a0,
+				new(big.Int).Div(
+					new(big.Int).Mul(new(big.Int).Sub(a1, a0), big.NewInt(now-t0)),
+					big.NewInt(t1-t0),
+				),
+			)
+		}
+		return new(big.Int).Sub(
+			a0,
+			new(big.Int).Div(
+				new(big.Int).Mul(new(big.Int).Sub(a0, a1), big.NewInt(now-t0)),
+				big.NewInt(t1-t0),
+			),
+		)
+	}
+	return a1
+}
+
+// _xp returns the balances scaled by the stored rates: rates[i] * balances[i] / PRECISION
+func (t *PoolSimulator) _xp() []*big.Int {
+	var nCoins = len(t.Info.Tokens)
+	var xp = make([]*big.Int, nCoins)
+	for i := 0; i < nCoins; i += 1 {
+		xp[i] = new(big.Int).Div(new(big.Int).Mul(t.StoredRates[i], t.Info.Reserves[i]), Precision)
+	}
+	return xp
+}
+
+func (t *PoolSimulator) _dynamicFee(xpi, xpj, fee *big.Int) *big.Int {
+	if t.OffpegFeeMultiplier.Cmp(FeeDenominator) <= 0 {
+		return fee
+	}
+	var sum = new(big.Int).Add(xpi, xpj)
+	var xps2 = new(big.Int).Mul(sum, sum)
+	if xps2.Sign() == 0 {
+		return fee
+	}
+	return new(big.Int).Div(
+		new(big.Int).Mul(t.OffpegFeeMultiplier, fee),
+		new(big.Int).Add(
+			new(big.Int).Div(
+				new(big.Int).Mul(
+					new(big.Int).Mul(new(big.Int).Mul(new(big.Int).Sub(t.OffpegFeeMultiplier, FeeDenominator), bignumber.Four), xpi),
+					xpj,
+				),
+				xps2,
+			),
+			FeeDenominator,
+		),
+	)
+}
+
+func getD(xp []*big.Int, amp *big.Int) (*big.Int, error) {
+	var nCoins = len(xp)
+	var nCoinsBi = big.NewInt(int64(nCoins))
+	var S = big.NewInt(0)
+	for _, x := range xp {
+		S.Add(S, x)
+	}
+	if S.Sign() == 0 {
+		return S, nil
+	}
+	var nPowN = new(big.Int).Exp(nCoinsBi, nCoinsBi, nil)
+	var D = new(big.Int).Set(S)
+	var Ann = new(big.Int).Mul(amp, nCoinsBi)
+	for i := 0; i < MaxLoopLimit; i += 1 {
+		var DP = new(big.Int).Set(D)
+		for _, x := range xp {
+			if x.Sign() == 0 {
+				return nil, ErrZero
+			}
+			DP = new(big.Int).Div(new(big.Int).Mul(DP, D), x)
+		}
+		DP.Div(DP, nPowN)
+		var Dprev = D
+		D = new(big.Int).Div(
+			new(big.Int).Mul(
+				new(big.Int).Add(
+					new(big.Int).Div(new(big.Int).Mul(Ann, S), APrecision),
+					new(big.Int).Mul(DP, nCoinsBi),
+				),
+				D,
+			),
+			new(big.Int).Add(
+				new(big.Int).Div(new(big.Int).Mul(new(big.Int).Sub(Ann, APrecision), D), APrecision),
+				new(big.Int).Mul(big.NewInt(int64(nCoins+1)), DP),
+			),
+		)
+		if new(big.Int).Sub(D, Dprev).CmpAbs(bignumber.One) <= 0 {
+			return D, nil
+		}
+	}
+	return nil, ErrDDoesNotConverge
+}
+
+func getY(i int, j int, x *big.Int, xp []*big.Int, amp *big.Int, D *big.Int) (*big.Int, error) {
+	var nCoins = len(xp)
+	if i == j {
+		return nil, ErrTokenFromEqualsTokenTo
+	}
+	if i < 0 || j < 0 || i >= nCoins || j >= nCoins {
+		return nil, ErrTokenIndexesOutOfRange
+	}
+	var nCoinsBi = big.NewInt(int64(nCoins))
+	var S = big.NewInt(0)
+	var c = new(big.Int).Set(D)
+	var Ann = new(big.Int).Mul(amp, nCoinsBi)
+	for k := 0; k < nCoins; k += 1 {
+		var _x *big.Int
+		if k == i {
+			_x = x
+		} else if k != j {
+			_x = xp[k]
+		} else {
+			continue
+		}
+		if _x.Sign() == 0 {
+			return nil, ErrZero
+		}
+		S.Add(S, _x)
+		c = new(big.Int).Div(new(big.Int).Mul(c, D), new(big.Int).Mul(_x, nCoinsBi))
+	}
+	if Ann.Sign() == 0 {
+		return nil, ErrZero
+	}
+	c = new(big.Int).Div(new(big.Int).Mul(new(big.Int).Mul(c, D), APrecision), new(big.Int).Mul(Ann, nCoinsBi))
+	var b = new(big.Int).Add(S, new(big.Int).Div(new(big.Int).Mul(D, APrecision), Ann))
+	var y = new(big.Int).Set(D)
+	for k := 0; k < MaxLoopLimit; k += 1 {
+		var yPrev = y
+		var denominator = new(big.Int).Sub(new(big.Int).Add(new(big.Int).Mul(y, bignumber.Two), b), D)
+		if denominator.Sign() == 0 {
+			return nil, ErrZero
+		}
+		y = new(big.Int).Div(new(big.Int).Add(new(big.Int).Mul(y, y), c), denominator)
+		if new(big.Int).Sub(y, yPrev).CmpAbs(bignumber.One) <= 0 {
+			return y, nil
+		}
+	}
+	return nil, ErrYDoesNotConverge
+}
+
+// GetDy returns the amount out, the fee and the admin fee (which is removed from the pool balance), all in coin j.
+func (t *PoolSimulator) GetDy(i int, j int, dx *big.Int) (*big.Int, *big.Int, *big.Int, error) {
+	var xp = t._xp()
+	var amp = t._A()
+	D, err := getD(xp, amp)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var x = new(big.Int).Add(xp[i], new(big.Int).Div(new(big.Int).Mul(dx, t.StoredRates[i]), Precision))
+	y, err := getY(i, j, x, xp, amp, D)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var dy = new(big.Int).Sub(new(big.Int).Sub(xp[j], y), bignumber.One)
+	if dy.Sign() <= 0 {
+		return nil, nil, nil, ErrZero
+	}
+	var dynamicFee = t._dynamicFee(
+		new(big.Int).Div(new(big.Int).Add(xp[i], x), bignumber.Two),
+		new(big.Int).Div(new(big.Int).Add(xp[j], y), bignumber.Two),
+		t.Info.SwapFee,
+	)
+	var dyFee = new(big.Int).Div(new(big.Int).Mul(dy, dynamicFee), FeeDenominator)
+	var amountOut = new(big.Int).Div(new(big.Int).Mul(new(big.Int).Sub(dy, dyFee), Precision), t.StoredRates[j])
+	var fee = new(big.Int).Div(new(big.Int).Mul(dyFee, Precision), t.StoredRates[j])
+	var adminFee = new(big.Int).Div(
+		new(big.Int).Mul(new(big.Int).Div(new(big.Int).Mul(dyFee, t.AdminFee), FeeDenominator), Precision),
+		t.StoredRates[j],
+	)
+	return amountOut, fee, adminFee, nil
+}
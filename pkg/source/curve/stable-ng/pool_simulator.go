@@ -0,0 +1,175 @@
+package stableng
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/curve"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+type PoolSimulator struct {
+	pool.Pool
+	// StoredRates are the rates returned by stored_rates(), they include the precision multipliers
+	// and the rate of oracle/ERC4626 coins.
+	StoredRates []*big.Int
+	AssetTypes  []uint8
+	// extra fields
+	InitialA            *big.Int
+	FutureA             *big.Int
+	InitialATime        int64
+	FutureATime         int64
+	AdminFee            *big.Int
+	OffpegFeeMultiplier *big.Int
+	LpToken             string
+	LpSupply            *big.Int
+	gas                 Gas
+}
+
+type Gas struct {
+	Exchange int64
+}
+
+type SwapInfo struct {
+	AdminFee string `json:"adminFee"`
+}
+
+func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
+	var staticExtra curve.PoolStableNgStaticExtra
+	if err := json.Unmarshal([]byte(entityPool.StaticExtra), &staticExtra); err != nil {
+		return nil, err
+	}
+
+	var extra curve.PoolStableNgExtra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	var numTokens = len(entityPool.Tokens)
+	if numTokens > MaxCoins {
+		return nil, ErrTooManyCoins
+	}
+	if entityPool.Reserves == nil || len(entityPool.Reserves) < numTokens+1 {
+		return nil, errors.New("empty reserve")
+	}
+	if len(extra.StoredRates) != numTokens {
+		return nil, ErrInvalidStoredRates
+	}
+
+	var tokens = make([]string, numTokens)
+	var reserves = make([]*big.Int, numTokens)
+	var storedRates = make([]*big.Int, numTokens)
+	var gas = DefaultGas
+	for i := 0; i < numTokens; i += 1 {
+		tokens[i] = entityPool.Tokens[i].Address
+		reserves[i] = bignumber.NewBig10(entityPool.Reserves[i])
+		storedRates[i] = bignumber.NewBig10(extra.StoredRates[i])
+		if storedRates[i].Sign() <= 0 {
+			return nil, ErrInvalidStoredRates
+		}
+		if i < len(staticExtra.AssetTypes) {
+			gas.Exchange += assetTypeGas[staticExtra.AssetTypes[i]]
+		}
+	}
+
+	return &PoolSimulator{
+		Pool: pool.Pool{
+			Info: pool.PoolInfo{
+				Address:    strings.ToLower(entityPool.Address),
+				ReserveUsd: entityPool.ReserveUsd,
+				SwapFee:    bignumber.NewBig10(extra.SwapFee),
+				Exchange:   entityPool.Exchange,
+				Type:       entityPool.Type,
+				Tokens:     tokens,
+				Reserves:   reserves,
+				Checked:    false,
+			},
+		},
+		StoredRates:         storedRates,
+		AssetTypes:          staticExtra.AssetTypes,
+		InitialA:            bignumber.NewBig10(extra.InitialA),
+		FutureA:             bignumber.NewBig10(extra.FutureA),
+		InitialATime:        extra.InitialATime,
+		FutureATime:         extra.FutureATime,
+		AdminFee:            bignumber.NewBig10(extra.AdminFee),
+		OffpegFeeMultiplier: bignumber.NewBig10(extra.OffpegFeeMultiplier),
+		LpToken:             staticExtra.LpToken,
+		LpSupply:            bignumber.NewBig10(entityPool.Reserves[numTokens]),
+		gas:                 gas,
+	}, nil
+}
+
+func (t *PoolSimulator) CalcAmountOut(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	var tokenIndexFrom = t.Info.GetTokenIndex(tokenAmountIn.Token)
+	var tokenIndexTo = t.Info.GetTokenIndex(tokenOut)
+	if tokenIndexFrom >= 0 && tokenIndexTo >= 0 {
+		amountOut, fee, adminFee, err := t.GetDy(
+			tokenIndexFrom,
+			tokenIndexTo,
+			tokenAmountIn.Amount,
+		)
+		if err != nil {
+			return &pool.CalcAmountOutResult{}, err
+		}
+		if new(big.Int).Add(amountOut, adminFee).Cmp(t.Info.Reserves[tokenIndexTo]) > 0 {
+			return &pool.CalcAmountOutResult{}, ErrExchangeMoreThanBalance
+		}
+		if amountOut.Cmp(bignumber.ZeroBI) > 0 {
+			return &pool.CalcAmountOutResult{
+				TokenAmountOut: &pool.TokenAmount{
+					Token:  tokenOut,
+					Amount: amountOut,
+				},
+				Fee: &pool.TokenAmount{
+					Token:  tokenOut,
+					Amount: fee,
+				},
+				Gas: t.gas.Exchange,
+				SwapInfo: SwapInfo{
+					AdminFee: adminFee.String(),
+				},
+			}, nil
+		}
+	}
+	return &pool.CalcAmountOutResult{}, fmt.Errorf("tokenIndexFrom %v or tokenIndexTo %v is not correct", tokenIndexFrom, tokenIndexTo)
+}
+
+func (t *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	input, output := params.TokenAmountIn, params.TokenAmountOut
+	var inputAmount = input.Amount
+	// the admin fee is moved out of the pool balances, see exchange in the contract
+	var outputAmount = output.Amount
+	if swapInfo, ok := params.SwapInfo.(SwapInfo); ok {
+		outputAmount = new(big.Int).Add(outputAmount, bignumber.NewBig10(swapInfo.AdminFee))
+	}
+	for i := range t.Info.Tokens {
+		if t.Info.Tokens[i] == input.Token {
+			t.Info.Reserves[i] = new(big.Int).Add(t.Info.Reserves[i], inputAmount)
+		}
+		if t.Info.Tokens[i] == output.Token {
+			t.Info.Reserves[i] = new(big.Int).Sub(t.Info.Reserves[i], outputAmount)
+		}
+	}
+}
+
+func (t *PoolSimulator) GetLpToken() string {
+	return t.LpToken
+}
+
+func (t *PoolSimulator) GetMetaInfo(tokenIn string, tokenOut string) interface{} {
+	var fromId = t.GetTokenIndex(tokenIn)
+	var toId = t.GetTokenIndex(tokenOut)
+	return curve.Meta{
+		TokenInIndex:  fromId,
+		TokenOutIndex: toId,
+		Underlying:    false,
+	}
+}
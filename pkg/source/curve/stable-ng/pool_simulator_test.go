@@ -0,0 +1,92 @@
+package stableng
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+func newTestPool(t *testing.T) *PoolSimulator {
+	// 3 coins: a standard 18 decimals coin, a standard 6 decimals coin and an ERC4626 coin (rate = 1.05)
+	p, err := NewPoolSimulator(entity.Pool{
+		Exchange: "curve",
+		Type:     "curve-stable-ng",
+		Reserves: entity.PoolReserves{"12345678901234567890123", "23456789012", "4567890123456789012345", "40000000000000000000000"},
+		Tokens:   []*entity.PoolToken{{Address: "A"}, {Address: "B"}, {Address: "C"}},
+		Extra: `{"initialA": "150000", "futureA": "150000", "initialATime": 0, "futureATime": 0, "swapFee": "1000000",
+			"adminFee": "5000000000", "offpegFeeMultiplier": "20000000000",
+			"storedRates": ["1000000000000000000", "1000000000000000000000000000000", "1050000000000000000"]}`,
+		StaticExtra: `{"lpToken": "LP", "assetTypes": [0, 0, 3]}`,
+	})
+	require.Nil(t, err)
+	return p
+}
+
+func TestCalcAmountOut(t *testing.T) {
+	// expected amounts are calculated with the get_dy of CurveStableSwapNG.vy
+	testcases := []struct {
+		in                string
+		inAmount          string
+		out               string
+		expectedOutAmount string
+		expectedFee       string
+	}{
+		{"A", "1000000000000000000000", "B", "1000438157", "104209"},
+		{"B", "1000000000", "C", "949027913794362790496", "125225950000288696"},
+		{"C", "500000000000000000000", "A", "525884555093302422628", "57414244705995953"},
+		{"B", "1", "A", "999278563856", "104994537"},
+	}
+	p := newTestPool(t)
+	assert.Equal(t, DefaultGas.Exchange+assetTypeGas[AssetTypeERC4626], p.gas.Exchange)
+
+	for idx, tc := range testcases {
+		t.Run(fmt.Sprintf("test %d", idx), func(t *testing.T) {
+			out, err := p.CalcAmountOut(pool.TokenAmount{Token: tc.in, Amount: bignumber.NewBig10(tc.inAmount)}, tc.out)
+			require.Nil(t, err)
+			assert.Equal(t, tc.expectedOutAmount, out.TokenAmountOut.Amount.String())
+			assert.Equal(t, tc.expectedFee, out.Fee.Amount.String())
+		})
+	}
+}
+
+func TestDynamicFee(t *testing.T) {
+	p := newTestPool(t)
+	fee := big.NewInt(1000000)
+
+	// balanced pool pays the base fee
+	assert.Equal(t, fee, p._dynamicFee(big.NewInt(1000), big.NewInt(1000), fee))
+	// the more imbalanced the pool, the higher the fee (up to offpegFeeMultiplier * fee)
+	assert.Equal(t, big.NewInt(1142857), p._dynamicFee(big.NewInt(3000), big.NewInt(1000), fee))
+
+	p.OffpegFeeMultiplier = FeeDenominator
+	assert.Equal(t, fee, p._dynamicFee(big.NewInt(3000), big.NewInt(1000), fee))
+}
+
+func TestUpdateBalance(t *testing.T) {
+	p := newTestPool(t)
+	amountIn := bignumber.NewBig10("1000000000000000000000")
+	out, err := p.CalcAmountOut(pool.TokenAmount{Token: "A", Amount: amountIn}, "B")
+	require.Nil(t, err)
+
+	p.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  pool.TokenAmount{Token: "A", Amount: amountIn},
+		TokenAmountOut: *out.TokenAmountOut,
+		Fee:            *out.Fee,
+		SwapInfo:       out.SwapInfo,
+	})
+
+	adminFee := bignumber.NewBig10(out.SwapInfo.(SwapInfo).AdminFee)
+	assert.Equal(t, "52104", adminFee.String())
+	assert.Equal(t, "13345678901234567890123", p.Info.Reserves[0].String())
+	assert.Equal(t, new(big.Int).Sub(new(big.Int).Sub(bignumber.NewBig10("23456789012"), out.TokenAmountOut.Amount), adminFee), p.Info.Reserves[1])
+
+	_, err = p.CalcAmountOut(pool.TokenAmount{Token: "A", Amount: amountIn}, "LP")
+	assert.NotNil(t, err)
+}
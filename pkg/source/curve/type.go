@@ -18,6 +18,10 @@ type Metadata struct {
 	MetaFactoryOffset    int `json:"metaFactoryOffset"`
 	CryptoRegistryOffset int `json:"cryptoRegistryOffset"`
 	CryptoFactoryOffset  int `json:"cryptoFactoryOffset"`
+
+	StableSwapNGFactoryOffset int `json:"stableSwapNGFactoryOffset"`
+	TricryptoNGFactoryOffset  int `json:"tricryptoNGFactoryOffset"`
+	TwocryptoNGFactoryOffset  int `json:"twocryptoNGFactoryOffset"`
 }
 
 type PoolToken struct {
@@ -84,6 +88,17 @@ type PoolTricryptoStaticExtra struct {
 	PrecisionMultipliers []string `json:"precisionMultipliers"`
 }
 
+type PoolStableNgStaticExtra struct {
+	LpToken string `json:"lpToken"`
+	// AssetTypes of the coins: 0 standard, 1 oracle, 2 rebasing, 3 ERC4626
+	AssetTypes []uint8 `json:"assetTypes"`
+}
+
+type PoolCryptoNgStaticExtra struct {
+	LpToken              string   `json:"lpToken"`
+	PrecisionMultipliers []string `json:"precisionMultipliers"`
+}
+
 type PoolBaseExtra struct {
 	InitialA     string `json:"initialA"`
 	FutureA      string `json:"futureA"`
@@ -175,6 +190,40 @@ type PoolTricryptoExtra struct {
 	MaHalfTime          string   `json:"maHalfTime"`
 }
 
+type PoolStableNgExtra struct {
+	InitialA            string   `json:"initialA"`
+	FutureA             string   `json:"futureA"`
+	InitialATime        int64    `json:"initialATime"`
+	FutureATime         int64    `json:"futureATime"`
+	SwapFee             string   `json:"swapFee"`
+	AdminFee            string   `json:"adminFee"`
+	OffpegFeeMultiplier string   `json:"offpegFeeMultiplier"`
+	StoredRates         []string `json:"storedRates"`
+}
+
+type PoolCryptoNgExtra struct {
+	A                  string   `json:"A"`
+	D                  string   `json:"D"`
+	Gamma              string   `json:"gamma"`
+	PriceScale         []string `json:"priceScale"`
+	LastPrices         []string `json:"lastPrices"`
+	PriceOracle        []string `json:"priceOracle"`
+	FeeGamma           string   `json:"feeGamma"`
+	MidFee             string   `json:"midFee"`
+	OutFee             string   `json:"outFee"`
+	FutureAGammaTime   int64    `json:"futureAGammaTime"`
+	FutureAGamma       string   `json:"futureAGamma"`
+	InitialAGammaTime  int64    `json:"initialAGammaTime"`
+	InitialAGamma      string   `json:"initialAGamma"`
+	LastTimestamp      string   `json:"lastTimestamp"`
+	LpSupply           string   `json:"lpSupply"`
+	XcpProfit          string   `json:"xcpProfit"`
+	VirtualPrice       string   `json:"virtualPrice"`
+	AllowedExtraProfit string   `json:"allowedExtraProfit"`
+	AdjustmentStep     string   `json:"adjustmentStep"`
+	MaTime             string   `json:"maTime"`
+}
+
 type Meta struct {
 	TokenInIndex  int  `json:"tokenInIndex"`
 	TokenOutIndex int  `json:"tokenOutIndex"`
@@ -7,8 +7,10 @@ import (
 )
 
 var (
-	v1PoolABI abi.ABI
-	v2PoolABI abi.ABI
+	v1PoolABI        abi.ABI
+	v2PoolABI        abi.ABI
+	dppOraclePoolABI abi.ABI
+	oracleABI        abi.ABI
 )
 
 func init() {
@@ -22,6 +24,12 @@ func init() {
 		{
 			&v2PoolABI, v2PoolData,
 		},
+		{
+			&dppOraclePoolABI, dppOraclePoolData,
+		},
+		{
+			&oracleABI, oracleData,
+		},
 	}
 
 	for _, b := range builder {
@@ -12,6 +12,7 @@ const (
 	poolTypeDodoVendingMachine = "dodo-dvm"
 	poolTypeDodoStable         = "dodo-dsp"
 	poolTypeDodoPrivate        = "dodo-dpp"
+	poolTypeDodoPrivateOracle  = "dodo-dpp-oracle"
 
 	// SubgraphPoolType DodoV1
 	subgraphPoolTypeDodoClassical = "CLASSICAL"
@@ -19,6 +20,8 @@ const (
 	subgraphPoolTypeDodoVendingMachine = "DVM"
 	subgraphPoolTypeDodoStable         = "DSP"
 	subgraphPoolTypeDodoPrivate        = "DPP"
+	// private pools whose i is the price of an oracle
+	subgraphPoolTypeDodoPrivateOracle = "DPPOracle"
 
 	// Contract methods
 	poolMethodGetExpectedTarget = "getExpectedTarget"
@@ -33,6 +36,10 @@ const (
 
 	poolMethodGetPMMStateForCall = "getPMMStateForCall"
 	poolMethodGetUserFeeRate     = "getUserFeeRate"
+	poolMethodOracle             = "_O_"
+
+	oracleMethodPrices     = "prices"
+	oracleMethodIsFeasible = "isFeasible"
 
 	defaultTokenWeight           = 50
 	defaultGraphQLRequestTimeout = 20 * time.Second
@@ -57,6 +64,7 @@ var (
 		subgraphPoolTypeDodoStable:         poolTypeDodoStable,
 		subgraphPoolTypeDodoVendingMachine: poolTypeDodoVendingMachine,
 		subgraphPoolTypeDodoPrivate:        poolTypeDodoPrivate,
+		subgraphPoolTypeDodoPrivateOracle:  poolTypeDodoPrivateOracle,
 	}
 
 	DefaultGas = Gas{
@@ -8,6 +8,12 @@ var v1PoolData []byte
 //go:embed abi/DodoV2Pool.json
 var v2PoolData []byte
 
+//go:embed abi/DodoPrivatePoolOracle.json
+var dppOraclePoolData []byte
+
+//go:embed abi/DodoOracle.json
+var oracleData []byte
+
 //go:embed blacklist/bsc.txt
 var bscBlacklistFilePath []byte
 
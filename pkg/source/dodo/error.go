@@ -7,4 +7,5 @@ var (
 	ErrInitializeBlacklistFailed = errors.New("initialize DODO black list failed")
 	ErrStaticExtraEmpty          = errors.New("staticExtra is empty")
 	ErrExtraEmpty                = errors.New("extra is empty")
+	ErrOracleNotFeasible         = errors.New("oracle price is not feasible")
 )
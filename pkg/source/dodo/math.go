@@ -94,13 +94,13 @@ func QuerySellQuote(amount *big.Float, state *PoolSimulatorState) (result *big.F
 }
 
 func ROneSellBase(amount *big.Float, state *PoolSimulatorState) (result *big.Float, err error) {
-	result, err = solveQuadraticFunctionForTrade(state.Q0, state.Q0, amount, state.OraclePrice, state.k)
+	result, err = SolveQuadraticFunctionForTrade(state.Q0, state.Q0, amount, state.OraclePrice, state.k)
 
 	return
 }
 
 func ROneSellQuote(amount *big.Float, state *PoolSimulatorState) (result *big.Float, err error) {
-	result, err = solveQuadraticFunctionForTrade(
+	result, err = SolveQuadraticFunctionForTrade(
 		state.B0, state.B0, amount, new(big.Float).Quo(big.NewFloat(1), state.OraclePrice), state.k,
 	)
 	return
@@ -112,7 +112,7 @@ func RAboveSellBase(amount *big.Float, state *PoolSimulatorState) (result *big.F
 }
 
 func RAboveSellQuote(amount *big.Float, state *PoolSimulatorState) (result *big.Float, err error) {
-	result, err = solveQuadraticFunctionForTrade(
+	result, err = SolveQuadraticFunctionForTrade(
 		state.B0, state.B, amount, new(big.Float).Quo(big.NewFloat(1), state.OraclePrice), state.k,
 	)
 	return
@@ -127,7 +127,7 @@ func RBelowSellQuote(amount *big.Float, state *PoolSimulatorState) (result *big.
 }
 
 func RBelowSellBase(amount *big.Float, state *PoolSimulatorState) (result *big.Float, err error) {
-	result, err = solveQuadraticFunctionForTrade(state.Q0, state.Q, amount, state.OraclePrice, state.k)
+	result, err = SolveQuadraticFunctionForTrade(state.Q0, state.Q, amount, state.OraclePrice, state.k)
 	return
 }
 
@@ -146,7 +146,7 @@ func integrate(V0, V1, V2, i, k *big.Float) (*big.Float, error) {
 	return new(big.Float).Mul(fairAmount, new(big.Float).Add(new(big.Float).Sub(big.NewFloat(1), k), penalty)), nil
 }
 
-func solveQuadraticFunctionForTrade(V0, V1, delta, i, k *big.Float) (*big.Float, error) {
+func SolveQuadraticFunctionForTrade(V0, V1, delta, i, k *big.Float) (*big.Float, error) {
 	if V0.Cmp(big.NewFloat(0)) <= 0 {
 		return big.NewFloat(0), errors.New("TARGET_IS_ZERO")
 	}
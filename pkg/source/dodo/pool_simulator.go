@@ -50,6 +50,11 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 		return nil, err
 	}
 
+	// the oracle of a private pool does not give a price when it is not feasible, swaps revert
+	if staticExtra.Type == subgraphPoolTypeDodoPrivateOracle && !extra.Swappable {
+		return nil, ErrOracleNotFeasible
+	}
+
 	// swapFee isn't used to calculate the amountOut, poolState.mtFeeRate and poolState.lpFeeRate are used instead
 	swapFee, _ := new(big.Float).Mul(new(big.Float).SetFloat64(entityPool.SwapFee), bignumber.BoneFloat).Int(nil)
 
@@ -100,6 +100,38 @@ func TestCanSwapTo(t *testing.T) {
 	assert.Equal(t, 0, len(p.CanSwapTo("XX")))
 }
 
+func TestNewPoolSimulator_PrivatePoolOracle(t *testing.T) {
+	newPool := func(swappable bool) (*PoolSimulator, error) {
+		return NewPoolSimulator(entity.Pool{
+			SwapFee: 0.001 + 0.002,
+			Tokens:  []*entity.PoolToken{{Address: "BASE", Decimals: 18}, {Address: "QUOTE", Decimals: 18}},
+			Extra: fmt.Sprintf("{\"reserves\": [%v, %v], \"targetReserves\": [%v, %v],\"i\": %v,\"k\": %v,\"rStatus\": %v,\"mtFeeRate\": \"%v\",\"lpFeeRate\": \"%v\",\"swappable\": %v }",
+				decStr(10), decStr(1000),
+				decStr(10), decStr(1000),
+				decStr(100),          // i=100 from the oracle
+				"100000000000000000", // k=0.1
+				0,
+				"0.001",
+				"0.002",
+				swappable,
+			),
+			StaticExtra: fmt.Sprintf("{\"tokens\": [\"%v\",\"%v\"], \"type\": \"%v\", \"dodoV1SellHelper\": \"%v\"}",
+				"BASE", "QUOTE", "DPPOracle", ""),
+		})
+	}
+
+	_, err := newPool(false)
+	assert.ErrorIs(t, err, ErrOracleNotFeasible)
+
+	// a feasible oracle price swaps like a private pool with the same i
+	p, err := newPool(true)
+	require.Nil(t, err)
+
+	out, err := p.CalcAmountOut(pool.TokenAmount{Token: "BASE", Amount: bignumber.NewBig10(decStr(1))}, "QUOTE")
+	require.Nil(t, err)
+	assert.Equal(t, bignumber.NewBig10("98617454226610630667"), out.TokenAmountOut.Amount)
+}
+
 func decStr(amt int64) string {
 	return new(big.Int).Mul(big.NewInt(amt), bignumber.TenPowInt(18)).String()
 }
@@ -59,7 +59,7 @@ func (d *PoolTracker) GetNewPoolState(
 		return d.getNewPoolStateDodoV1(ctx, p)
 	}
 
-	return d.getNewPoolStateDodoV2(ctx, p)
+	return d.getNewPoolStateDodoV2(ctx, p, staticExtraData.Type)
 }
 
 func (d *PoolTracker) getNewPoolStateDodoV1(ctx context.Context, p entity.Pool) (entity.Pool, error) {
@@ -174,7 +174,7 @@ func (d *PoolTracker) getNewPoolStateDodoV1(ctx context.Context, p entity.Pool)
 	return p, nil
 }
 
-func (d *PoolTracker) getNewPoolStateDodoV2(ctx context.Context, p entity.Pool) (entity.Pool, error) {
+func (d *PoolTracker) getNewPoolStateDodoV2(ctx context.Context, p entity.Pool, subgraphPoolType string) (entity.Pool, error) {
 	logger.Infof("[Dodo] Start getting new state of dodoV2 pool: %v", p.Address)
 
 	_, ok := d.blackList.Get(p.Address)
@@ -263,13 +263,30 @@ func (d *PoolTracker) getNewPoolStateDodoV2(ctx context.Context, p entity.Pool)
 		return entity.Pool{}, fmt.Errorf("get pool lpFeeRate failed")
 	}
 
+	// the i of the private pools with an oracle is the latest price of the oracle, which swaps can not use when it is
+	// not feasible
+	swappable := true
+	if subgraphPoolType == subgraphPoolTypeDodoPrivateOracle {
+		oraclePrice, feasible, err := d.getOraclePrice(ctx, p)
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"poolAddress": p.Address,
+				"error":       err,
+			}).Errorf("[DodoV2] failed to get oracle price")
+			return entity.Pool{}, err
+		}
+
+		state.I = oraclePrice
+		swappable = feasible
+	}
+
 	extra := Extra{
 		I:              state.I,
 		K:              state.K,
 		RStatus:        int(state.R.Int64()),
 		MtFeeRate:      new(big.Float).Quo(new(big.Float).SetInt64(feeRate.MtFeeRate.Int64()), oneBF),
 		LpFeeRate:      new(big.Float).Quo(new(big.Float).SetInt64(lpFeeRate.Int64()), oneBF),
-		Swappable:      true,
+		Swappable:      swappable,
 		Reserves:       []*big.Int{state.B, state.Q},
 		TargetReserves: []*big.Int{state.B0, state.Q0},
 	}
@@ -292,6 +309,46 @@ func (d *PoolTracker) getNewPoolStateDodoV2(ctx context.Context, p entity.Pool)
 	return p, nil
 }
 
+// getOraclePrice returns the price of the base token of a private pool from the oracle of the pool, and whether the
+// oracle allows swaps at that price
+func (d *PoolTracker) getOraclePrice(ctx context.Context, p entity.Pool) (*big.Int, bool, error) {
+	var oracle common.Address
+	if _, err := d.ethrpcClient.NewRequest().SetContext(ctx).AddCall(&ethrpc.Call{
+		ABI:    dppOraclePoolABI,
+		Target: p.Address,
+		Method: poolMethodOracle,
+		Params: nil,
+	}, []interface{}{&oracle}).Call(); err != nil {
+		return nil, false, err
+	}
+
+	var (
+		price    *big.Int
+		feasible bool
+	)
+
+	baseToken := common.HexToAddress(p.Tokens[0].Address)
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+	calls.AddCall(&ethrpc.Call{
+		ABI:    oracleABI,
+		Target: oracle.Hex(),
+		Method: oracleMethodPrices,
+		Params: []interface{}{baseToken},
+	}, []interface{}{&price})
+	calls.AddCall(&ethrpc.Call{
+		ABI:    oracleABI,
+		Target: oracle.Hex(),
+		Method: oracleMethodIsFeasible,
+		Params: []interface{}{baseToken},
+	}, []interface{}{&feasible})
+
+	if _, err := calls.Aggregate(); err != nil {
+		return nil, false, err
+	}
+
+	return price, feasible, nil
+}
+
 func initBlackList(blackListPath string) (cmap.ConcurrentMap, error) {
 	blackListMap := cmap.New()
 
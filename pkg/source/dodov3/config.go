@@ -0,0 +1,7 @@
+package dodov3
+
+type Config struct {
+	DexID        string `json:"dexID"`
+	VaultAddress string `json:"vaultAddress"`
+	NewPoolLimit int    `json:"newPoolLimit"`
+}
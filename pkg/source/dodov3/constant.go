@@ -0,0 +1,28 @@
+package dodov3
+
+import (
+	"math/big"
+)
+
+const (
+	DexTypeDodoV3 = "dodo-v3"
+
+	vaultMethodGetAllPoolAddrList = "getAllPoolAddrList"
+
+	poolMethodGetPoolTokenlist           = "getPoolTokenlist"
+	poolMethodGetTokenMMPriceInfoForRead = "getTokenMMPriceInfoForRead"
+	poolMethodGetTokenMMOtherInfoForRead = "getTokenMMOtherInfoForRead"
+
+	erc20MethodBalanceOf = "balanceOf"
+	erc20MethodDecimals  = "decimals"
+
+	defaultTokenWeight = 50
+	zeroString         = "0"
+)
+
+var (
+	// minFromAmount is the smallest amount a swap can sell, D3MM reverts with AMOUNT_TOO_SMALL below it
+	minFromAmount = big.NewInt(1000)
+
+	DefaultGas = Gas{Swap: 200000}
+)
@@ -0,0 +1,14 @@
+package dodov3
+
+import (
+	_ "embed"
+)
+
+//go:embed abis/D3MM.json
+var poolABIJson []byte
+
+//go:embed abis/D3Vault.json
+var vaultABIJson []byte
+
+//go:embed abis/ERC20.json
+var erc20ABIJson []byte
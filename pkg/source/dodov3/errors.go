@@ -0,0 +1,12 @@
+package dodov3
+
+import "errors"
+
+var (
+	ErrAmountTooSmall   = errors.New("amount too small")
+	ErrRangeOrderEmpty  = errors.New("range order amount is zero")
+	ErrRangeOrderFilled = errors.New("range order can not fill the amount")
+	ErrNumeratorIsZero  = errors.New("numerator is zero")
+	ErrBalanceNotEnough = errors.New("pool balance is not enough")
+	ErrZeroAmountOut    = errors.New("amountOut is 0")
+)
@@ -0,0 +1,152 @@
+package dodov3
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// querySellTokens sells an amount of the from token for vUSD on its bid order, then vUSD for the to token on its ask
+// order, each order is a PMM curve which starts at its down price and whose target is its amount, as the range orders of
+// https://github.com/DODOEX/new-dodo-v3/blob/main/contracts/DODOV3MM/lib/PMMRangeOrder.sol
+// The amounts are in 18 decimals.
+func querySellTokens(fromTokenMMInfo, toTokenMMInfo TokenMMInfo, fromAmount *big.Int) (*big.Int, *big.Int, error) {
+	// the bid order is a pool whose base is vUSD and whose quote is the from token
+	vusdAmount, err := sellToRangeOrder(
+		fromTokenMMInfo.BidAmount, fromTokenMMInfo.CumulativeBid, fromTokenMMInfo.BidDownPrice, fromTokenMMInfo.KBid,
+		fromAmount,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the ask order is a pool whose base is the to token and whose quote is vUSD
+	receiveAmount, err := sellToRangeOrder(
+		toTokenMMInfo.AskAmount, toTokenMMInfo.CumulativeAsk, toTokenMMInfo.AskDownPrice, toTokenMMInfo.KAsk,
+		vusdAmount,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return vusdAmount, receiveAmount, nil
+}
+
+// sellToRangeOrder sells the quote of a range order for its base, the base left in the order is its amount less its
+// cumulative. The contract caps the base out to what is left, which would pay the whole quote for a part of it, so a
+// sell which the order can not fill fails instead.
+func sellToRangeOrder(amount, cumulative, price, k *big.Int, quoteAmount *big.Int) (*big.Int, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, ErrRangeOrderEmpty
+	}
+
+	b := new(big.Int).Sub(amount, cumulative)
+	if b.Sign() <= 0 {
+		return nil, ErrRangeOrderFilled
+	}
+
+	// price is the price of the base in the quote, the curve sells the base at 1 / price
+	baseAmount, err := solveQuadraticFunctionForTrade(amount, b, quoteAmount, reciprocalFloor(price), k)
+	if err != nil {
+		return nil, err
+	}
+
+	if baseAmount.Cmp(b) > 0 {
+		return nil, ErrRangeOrderFilled
+	}
+
+	return baseAmount, nil
+}
+
+// solveQuadraticFunctionForTrade is DODOMath._SolveQuadraticFunctionForTrade, except that the base out of a curve
+// without slippage is not capped to v1 so that the caller sees when the curve can not fill the trade
+func solveQuadraticFunctionForTrade(v0, v1, delta, i, k *big.Int) (*big.Int, error) {
+	if v0.Sign() <= 0 {
+		return nil, ErrRangeOrderEmpty
+	}
+
+	if delta.Sign() == 0 {
+		return new(big.Int), nil
+	}
+
+	if k.Sign() == 0 {
+		return mulFloor(i, delta), nil
+	}
+
+	if k.Cmp(bignumber.BONE) == 0 {
+		// v1 * temp / (temp + 1), temp = i * delta * v1 / v0^2, in the order of the contract when i * delta * v1
+		// overflows uint256
+		var temp *big.Int
+		iDelta := new(big.Int).Mul(i, delta)
+		if iDeltaV1 := new(big.Int).Mul(iDelta, v1); iDeltaV1.BitLen() <= 256 {
+			temp = iDeltaV1.Div(iDeltaV1, new(big.Int).Mul(v0, v0))
+		} else {
+			temp = new(big.Int).Mul(delta, v1)
+			temp.Div(temp, v0).Mul(temp, i).Div(temp, v0)
+		}
+		result := new(big.Int).Mul(v1, temp)
+		return result.Div(result, temp.Add(temp, bignumber.BONE)), nil
+	}
+
+	// b = k * v0^2 / v1 - i * delta - (1 - k) * v1, bSig is whether b is positive
+	part2 := new(big.Int).Mul(k, v0)
+	part2.Div(part2, v1).Mul(part2, v0).Add(part2, new(big.Int).Mul(i, delta))
+	oneMinusK := new(big.Int).Sub(bignumber.BONE, k)
+	bAbs := new(big.Int).Mul(oneMinusK, v1)
+
+	bSig := false
+	if bAbs.Cmp(part2) >= 0 {
+		bAbs.Sub(bAbs, part2)
+	} else {
+		bAbs.Sub(part2, bAbs)
+		bSig = true
+	}
+	bAbs.Div(bAbs, bignumber.BONE)
+
+	// sqrt(b^2 + 4 * (1 - k) * k * v0^2)
+	squareRoot := mulFloor(new(big.Int).Mul(oneMinusK, bignumber.Four), new(big.Int).Mul(mulFloor(k, v0), v0))
+	squareRoot.Add(squareRoot, new(big.Int).Mul(bAbs, bAbs)).Sqrt(squareRoot)
+
+	denominator := new(big.Int).Mul(oneMinusK, bignumber.Two)
+	var numerator *big.Int
+	if bSig {
+		numerator = squareRoot.Sub(squareRoot, bAbs)
+		if numerator.Sign() <= 0 {
+			return nil, ErrNumeratorIsZero
+		}
+	} else {
+		numerator = squareRoot.Add(squareRoot, bAbs)
+	}
+
+	v2 := divCeil(numerator, denominator)
+	if v2.Cmp(v1) > 0 {
+		return new(big.Int), nil
+	}
+
+	return v2.Sub(v1, v2), nil
+}
+
+func mulFloor(target, d *big.Int) *big.Int {
+	result := new(big.Int).Mul(target, d)
+	return result.Div(result, bignumber.BONE)
+}
+
+func divCeil(target, d *big.Int) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(new(big.Int).Mul(target, bignumber.BONE), d, new(big.Int))
+	if remainder.Sign() > 0 {
+		quotient.Add(quotient, bignumber.One)
+	}
+	return quotient
+}
+
+func reciprocalFloor(target *big.Int) *big.Int {
+	return new(big.Int).Div(new(big.Int).Mul(bignumber.BONE, bignumber.BONE), target)
+}
+
+func scaleToDec18(amount *big.Int, decimals uint8) *big.Int {
+	return new(big.Int).Div(new(big.Int).Mul(amount, bignumber.BONE), bignumber.TenPowInt(decimals))
+}
+
+func scaleFromDec18(amount *big.Int, decimals uint8) *big.Int {
+	return new(big.Int).Div(new(big.Int).Mul(amount, bignumber.TenPowInt(decimals)), bignumber.BONE)
+}
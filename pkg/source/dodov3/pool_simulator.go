@@ -0,0 +1,144 @@
+package dodov3
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/KyberNetwork/logger"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+type PoolSimulator struct {
+	pool.Pool
+	decimals     []uint8
+	tokenMMInfos []TokenMMInfo
+	gas          Gas
+}
+
+func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
+	var extra Extra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	if len(extra.TokenMMInfos) != len(entityPool.Tokens) {
+		return nil, fmt.Errorf("the pool has %v tokens but %v token mm infos", len(entityPool.Tokens), len(extra.TokenMMInfos))
+	}
+
+	tokens := make([]string, len(entityPool.Tokens))
+	decimals := make([]uint8, len(entityPool.Tokens))
+	reserves := make([]*big.Int, len(entityPool.Tokens))
+	for i, token := range entityPool.Tokens {
+		tokens[i] = token.Address
+		decimals[i] = token.Decimals
+		reserves[i] = bignumber.NewBig10(entityPool.Reserves[i])
+	}
+
+	return &PoolSimulator{
+		Pool: pool.Pool{
+			Info: pool.PoolInfo{
+				Address:    entityPool.Address,
+				ReserveUsd: entityPool.ReserveUsd,
+				Exchange:   entityPool.Exchange,
+				Type:       entityPool.Type,
+				Tokens:     tokens,
+				Reserves:   reserves,
+				Checked:    false,
+			},
+		},
+		decimals:     decimals,
+		tokenMMInfos: extra.TokenMMInfos,
+		gas:          DefaultGas,
+	}, nil
+}
+
+func (p *PoolSimulator) CalcAmountOut(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	tokenInIndex := p.GetTokenIndex(tokenAmountIn.Token)
+	tokenOutIndex := p.GetTokenIndex(tokenOut)
+	if tokenInIndex < 0 || tokenOutIndex < 0 || tokenInIndex == tokenOutIndex {
+		return &pool.CalcAmountOutResult{}, fmt.Errorf("tokenInIndex %v or tokenOutIndex %v is not correct", tokenInIndex, tokenOutIndex)
+	}
+
+	if tokenAmountIn.Amount.Cmp(minFromAmount) <= 0 {
+		return &pool.CalcAmountOutResult{}, ErrAmountTooSmall
+	}
+
+	fromTokenMMInfo, toTokenMMInfo := p.tokenMMInfos[tokenInIndex], p.tokenMMInfos[tokenOutIndex]
+
+	vusdAmount, receiveAmount, err := querySellTokens(
+		fromTokenMMInfo, toTokenMMInfo, scaleToDec18(tokenAmountIn.Amount, p.decimals[tokenInIndex]),
+	)
+	if err != nil {
+		return &pool.CalcAmountOutResult{}, err
+	}
+
+	// the contract caps the amount out to its balance of the to token, which would take the whole amount in for less
+	amountOut := scaleFromDec18(receiveAmount, p.decimals[tokenOutIndex])
+	if amountOut.Cmp(p.Info.Reserves[tokenOutIndex]) > 0 {
+		return &pool.CalcAmountOutResult{}, ErrBalanceNotEnough
+	}
+
+	swapFeeRate := new(big.Int).Add(fromTokenMMInfo.SwapFeeRate, toTokenMMInfo.SwapFeeRate)
+	swapFee := new(big.Int).Div(new(big.Int).Mul(amountOut, swapFeeRate), bignumber.BONE)
+	amountOut.Sub(amountOut, swapFee)
+
+	if amountOut.Sign() <= 0 {
+		return &pool.CalcAmountOutResult{}, ErrZeroAmountOut
+	}
+
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{
+			Token:  tokenOut,
+			Amount: amountOut,
+		},
+		Fee: &pool.TokenAmount{
+			Token:  tokenOut,
+			Amount: swapFee,
+		},
+		Gas: p.gas.Swap,
+		SwapInfo: SwapInfo{
+			VUSDAmount:    vusdAmount,
+			ReceiveAmount: receiveAmount,
+		},
+	}, nil
+}
+
+func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	swapInfo, ok := params.SwapInfo.(SwapInfo)
+	if !ok {
+		logger.Warn("failed to UpdateBalance for DODO V3 pool, wrong swapInfo type")
+		return
+	}
+
+	tokenInIndex := p.GetTokenIndex(params.TokenAmountIn.Token)
+	tokenOutIndex := p.GetTokenIndex(params.TokenAmountOut.Token)
+	if tokenInIndex < 0 || tokenOutIndex < 0 {
+		return
+	}
+
+	// the swap fills the bid order of the from token and the ask order of the to token
+	fromTokenMMInfo := p.tokenMMInfos[tokenInIndex]
+	fromTokenMMInfo.CumulativeBid = new(big.Int).Add(fromTokenMMInfo.CumulativeBid, swapInfo.VUSDAmount)
+	p.tokenMMInfos[tokenInIndex] = fromTokenMMInfo
+
+	toTokenMMInfo := p.tokenMMInfos[tokenOutIndex]
+	toTokenMMInfo.CumulativeAsk = new(big.Int).Add(toTokenMMInfo.CumulativeAsk, swapInfo.ReceiveAmount)
+	p.tokenMMInfos[tokenOutIndex] = toTokenMMInfo
+
+	p.Info.Reserves[tokenInIndex] = new(big.Int).Add(p.Info.Reserves[tokenInIndex], params.TokenAmountIn.Amount)
+	p.Info.Reserves[tokenOutIndex] = new(big.Int).Sub(p.Info.Reserves[tokenOutIndex], params.TokenAmountOut.Amount)
+}
+
+func (p *PoolSimulator) GetMetaInfo(tokenIn string, tokenOut string) interface{} {
+	return Meta{
+		FromToken: tokenIn,
+		ToToken:   tokenOut,
+	}
+}
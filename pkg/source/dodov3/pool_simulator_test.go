@@ -0,0 +1,187 @@
+package dodov3
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	tokenA = "0x7ceb23fd6bc0add59e62ac25578270cff1b9f619"
+	tokenB = "0x2791bca1f2de4661ed88a30c99a7a9449aa84174"
+)
+
+// newTestTokenMMInfos returns the orders of tokenA at 2000 vUSD and of tokenB at 1 vUSD, with 100 tokenA and 100000
+// tokenB on the ask orders and 100000 vUSD on each bid order
+func newTestTokenMMInfos(k string) []TokenMMInfo {
+	return []TokenMMInfo{
+		{
+			AskDownPrice:  bignumber.NewBig10("2000000000000000000000"),
+			BidDownPrice:  bignumber.NewBig10("500000000000000"),
+			AskAmount:     bignumber.NewBig10("100000000000000000000"),
+			BidAmount:     bignumber.NewBig10("100000000000000000000000"),
+			KAsk:          bignumber.NewBig10(k),
+			KBid:          bignumber.NewBig10(k),
+			CumulativeAsk: new(big.Int),
+			CumulativeBid: new(big.Int),
+			SwapFeeRate:   bignumber.NewBig10("1000000000000000"),
+		},
+		{
+			AskDownPrice:  bignumber.NewBig10("1000000000000000000"),
+			BidDownPrice:  bignumber.NewBig10("1000000000000000000"),
+			AskAmount:     bignumber.NewBig10("100000000000000000000000"),
+			BidAmount:     bignumber.NewBig10("100000000000000000000000"),
+			KAsk:          bignumber.NewBig10(k),
+			KBid:          bignumber.NewBig10(k),
+			CumulativeAsk: new(big.Int),
+			CumulativeBid: new(big.Int),
+			SwapFeeRate:   bignumber.NewBig10("500000000000000"),
+		},
+	}
+}
+
+func newTestPoolSimulator(t *testing.T, tokenMMInfos []TokenMMInfo, reserves entity.PoolReserves) *PoolSimulator {
+	extraBytes, err := json.Marshal(Extra{TokenMMInfos: tokenMMInfos})
+	require.NoError(t, err)
+
+	simulator, err := NewPoolSimulator(entity.Pool{
+		Address:  "0x3a2e6a7e3f0e5e1b1f2b7c0c5d2a0e8f5d4c3b2a",
+		Exchange: DexTypeDodoV3,
+		Type:     DexTypeDodoV3,
+		Reserves: reserves,
+		Tokens: []*entity.PoolToken{
+			{Address: tokenA, Decimals: 18},
+			{Address: tokenB, Decimals: 6},
+		},
+		Extra: string(extraBytes),
+	})
+	require.NoError(t, err)
+
+	return simulator
+}
+
+func TestPoolSimulator_CalcAmountOut(t *testing.T) {
+	testcases := []struct {
+		name              string
+		k                 string
+		tokenIn           string
+		amountIn          string
+		tokenOut          string
+		expectedAmountOut string
+		expectedFee       string
+	}{
+		{
+			name:              "without slippage sells at the down prices less the fees of both tokens",
+			k:                 "0",
+			tokenIn:           tokenA,
+			amountIn:          "1000000000000000000",
+			tokenOut:          tokenB,
+			expectedAmountOut: "1997000000",
+			expectedFee:       "3000000",
+		},
+		{
+			name:              "without slippage buys at the down prices",
+			k:                 "0",
+			tokenIn:           tokenB,
+			amountIn:          "2000000000",
+			tokenOut:          tokenA,
+			expectedAmountOut: "998500000000000000",
+			expectedFee:       "1500000000000000",
+		},
+		{
+			name:              "slippage grows with the filled part of the orders",
+			k:                 "100000000000000000",
+			tokenIn:           tokenA,
+			amountIn:          "1000000000000000000",
+			tokenOut:          tokenB,
+			expectedAmountOut: "1988899030",
+			expectedFee:       "2987830",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			simulator := newTestPoolSimulator(t, newTestTokenMMInfos(tc.k),
+				entity.PoolReserves{"100000000000000000000", "100000000000"})
+
+			result, err := simulator.CalcAmountOut(pool.TokenAmount{
+				Token:  tc.tokenIn,
+				Amount: bignumber.NewBig10(tc.amountIn),
+			}, tc.tokenOut)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedAmountOut, result.TokenAmountOut.Amount.String())
+			assert.Equal(t, tc.expectedFee, result.Fee.Amount.String())
+		})
+	}
+}
+
+func TestPoolSimulator_CalcAmountOut_InventoryLimits(t *testing.T) {
+	// only 1000 tokenB are left on the ask order of tokenB
+	tokenMMInfos := newTestTokenMMInfos("0")
+	tokenMMInfos[1].CumulativeAsk = bignumber.NewBig10("99000000000000000000000")
+	simulator := newTestPoolSimulator(t, tokenMMInfos, entity.PoolReserves{"100000000000000000000", "100000000000"})
+
+	result, err := simulator.CalcAmountOut(pool.TokenAmount{
+		Token:  tokenA,
+		Amount: bignumber.NewBig10("500000000000000000"),
+	}, tokenB)
+	require.NoError(t, err)
+	assert.Equal(t, "998500000", result.TokenAmountOut.Amount.String())
+
+	_, err = simulator.CalcAmountOut(pool.TokenAmount{
+		Token:  tokenA,
+		Amount: bignumber.NewBig10("1000000000000000000"),
+	}, tokenB)
+	assert.ErrorIs(t, err, ErrRangeOrderFilled)
+
+	// the pool only holds 500 tokenB
+	simulator = newTestPoolSimulator(t, newTestTokenMMInfos("0"), entity.PoolReserves{"100000000000000000000", "500000000"})
+
+	_, err = simulator.CalcAmountOut(pool.TokenAmount{
+		Token:  tokenA,
+		Amount: bignumber.NewBig10("1000000000000000000"),
+	}, tokenB)
+	assert.ErrorIs(t, err, ErrBalanceNotEnough)
+
+	_, err = simulator.CalcAmountOut(pool.TokenAmount{Token: tokenA, Amount: big.NewInt(1000)}, tokenB)
+	assert.ErrorIs(t, err, ErrAmountTooSmall)
+}
+
+func TestPoolSimulator_GetMetaInfo(t *testing.T) {
+	simulator := newTestPoolSimulator(t, newTestTokenMMInfos("0"), entity.PoolReserves{"100000000000000000000", "100000000000"})
+
+	assert.Equal(t, Meta{FromToken: tokenA, ToToken: tokenB}, simulator.GetMetaInfo(tokenA, tokenB))
+}
+
+func TestPoolSimulator_UpdateBalance(t *testing.T) {
+	simulator := newTestPoolSimulator(t, newTestTokenMMInfos("100000000000000000"),
+		entity.PoolReserves{"100000000000000000000", "100000000000"})
+
+	tokenAmountIn := pool.TokenAmount{Token: tokenA, Amount: bignumber.NewBig10("1000000000000000000")}
+	first, err := simulator.CalcAmountOut(tokenAmountIn, tokenB)
+	require.NoError(t, err)
+
+	simulator.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  tokenAmountIn,
+		TokenAmountOut: *first.TokenAmountOut,
+		Fee:            *first.Fee,
+		SwapInfo:       first.SwapInfo,
+	})
+
+	swapInfo := first.SwapInfo.(SwapInfo)
+	assert.Equal(t, swapInfo.VUSDAmount, simulator.tokenMMInfos[0].CumulativeBid)
+	assert.Equal(t, swapInfo.ReceiveAmount, simulator.tokenMMInfos[1].CumulativeAsk)
+	assert.Equal(t, "101000000000000000000", simulator.Info.Reserves[0].String())
+
+	// the orders are filled further, so the same swap gets less
+	second, err := simulator.CalcAmountOut(tokenAmountIn, tokenB)
+	require.NoError(t, err)
+	assert.Equal(t, -1, second.TokenAmountOut.Amount.Cmp(first.TokenAmountOut.Amount))
+}
@@ -0,0 +1,129 @@
+package dodov3
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+type PoolTracker struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+}
+
+func NewPoolTracker(
+	cfg *Config,
+	ethrpcClient *ethrpc.Client,
+) *PoolTracker {
+	return &PoolTracker{
+		config:       cfg,
+		ethrpcClient: ethrpcClient,
+	}
+}
+
+func (d *PoolTracker) GetNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	_ pool.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	logger.WithFields(logger.Fields{
+		"address": p.Address,
+	}).Infof("[%s] Start getting new state of pool", p.Type)
+
+	tokens, err := getPoolTokens(ctx, d.ethrpcClient, p.Address)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to get tokens of pool")
+
+		return entity.Pool{}, err
+	}
+
+	var (
+		priceInfos = make([]TokenMMPriceInfo, len(tokens))
+		otherInfos = make([]TokenMMOtherInfo, len(tokens))
+		balances   = make([]*big.Int, len(tokens))
+	)
+
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+	for i, token := range tokens {
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: p.Address,
+			Method: poolMethodGetTokenMMPriceInfoForRead,
+			Params: []interface{}{common.HexToAddress(token.Address)},
+		}, []interface{}{&priceInfos[i]})
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: p.Address,
+			Method: poolMethodGetTokenMMOtherInfoForRead,
+			Params: []interface{}{common.HexToAddress(token.Address)},
+		}, []interface{}{&otherInfos[i]})
+		calls.AddCall(&ethrpc.Call{
+			ABI:    erc20ABI,
+			Target: token.Address,
+			Method: erc20MethodBalanceOf,
+			Params: []interface{}{common.HexToAddress(p.Address)},
+		}, []interface{}{&balances[i]})
+	}
+
+	if len(tokens) > 0 {
+		if _, err := calls.Aggregate(); err != nil {
+			logger.WithFields(logger.Fields{
+				"poolAddress": p.Address,
+				"error":       err,
+			}).Errorf("failed to aggregate to get pool data")
+
+			return entity.Pool{}, err
+		}
+	}
+
+	extra := Extra{
+		TokenMMInfos: make([]TokenMMInfo, len(tokens)),
+	}
+	reserves := make(entity.PoolReserves, len(tokens))
+	for i := range tokens {
+		extra.TokenMMInfos[i] = TokenMMInfo{
+			AskDownPrice:  priceInfos[i].AskDownPrice,
+			BidDownPrice:  priceInfos[i].BidDownPrice,
+			AskAmount:     otherInfos[i].AskAmount,
+			BidAmount:     otherInfos[i].BidAmount,
+			KAsk:          otherInfos[i].KAsk,
+			KBid:          otherInfos[i].KBid,
+			CumulativeAsk: otherInfos[i].CumulativeAsk,
+			CumulativeBid: otherInfos[i].CumulativeBid,
+			SwapFeeRate:   priceInfos[i].SwapFee,
+		}
+		reserves[i] = balances[i].String()
+	}
+
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to marshal extra")
+
+		return entity.Pool{}, err
+	}
+
+	p.Tokens = tokens
+	p.Reserves = reserves
+	p.Extra = string(extraBytes)
+	p.Timestamp = time.Now().Unix()
+
+	logger.WithFields(logger.Fields{
+		"address": p.Address,
+	}).Infof("[%s] Finish getting new state of pool", p.Type)
+
+	return p, nil
+}
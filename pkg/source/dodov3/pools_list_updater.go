@@ -0,0 +1,139 @@
+package dodov3
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util"
+)
+
+// PoolsListUpdater lists the pools of the vault, each pool trades all of its tokens against each other
+type PoolsListUpdater struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+}
+
+func NewPoolsListUpdater(
+	cfg *Config,
+	ethrpcClient *ethrpc.Client,
+) *PoolsListUpdater {
+	return &PoolsListUpdater{
+		config:       cfg,
+		ethrpcClient: ethrpcClient,
+	}
+}
+
+func (d *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte) ([]entity.Pool, []byte, error) {
+	var metadata Metadata
+	if len(metadataBytes) != 0 {
+		if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
+			return nil, metadataBytes, err
+		}
+	}
+
+	// Add timestamp to the context so that each run iteration will have something different
+	ctx = util.NewContextWithTimestamp(ctx)
+
+	var poolAddresses []common.Address
+	if _, err := d.ethrpcClient.NewRequest().SetContext(ctx).AddCall(&ethrpc.Call{
+		ABI:    vaultABI,
+		Target: d.config.VaultAddress,
+		Method: vaultMethodGetAllPoolAddrList,
+	}, []interface{}{&poolAddresses}).Call(); err != nil {
+		logger.WithFields(logger.Fields{
+			"dexID": d.config.DexID,
+			"error": err,
+		}).Errorf("failed to get pools from vault")
+		return nil, metadataBytes, err
+	}
+
+	batchSize := min(d.config.NewPoolLimit, len(poolAddresses)-metadata.Offset)
+	if batchSize <= 0 {
+		return nil, metadataBytes, nil
+	}
+
+	pools := make([]entity.Pool, 0, batchSize)
+	for _, poolAddress := range poolAddresses[metadata.Offset : metadata.Offset+batchSize] {
+		address := strings.ToLower(poolAddress.Hex())
+		tokens, err := getPoolTokens(ctx, d.ethrpcClient, address)
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"dexID":       d.config.DexID,
+				"poolAddress": address,
+				"error":       err,
+			}).Errorf("failed to get tokens of pool")
+			return nil, metadataBytes, err
+		}
+
+		reserves := make(entity.PoolReserves, len(tokens))
+		for i := range reserves {
+			reserves[i] = zeroString
+		}
+
+		pools = append(pools, entity.Pool{
+			Address:   address,
+			Exchange:  d.config.DexID,
+			Type:      DexTypeDodoV3,
+			Timestamp: time.Now().Unix(),
+			Reserves:  reserves,
+			Tokens:    tokens,
+		})
+	}
+
+	newMetadataBytes, err := json.Marshal(Metadata{Offset: metadata.Offset + batchSize})
+	if err != nil {
+		return nil, metadataBytes, err
+	}
+
+	logger.Infof("got %v %s pools, progress: %d/%d", len(pools), d.config.DexID, metadata.Offset+batchSize, len(poolAddresses))
+
+	return pools, newMetadataBytes, nil
+}
+
+// getPoolTokens returns the tokens of a pool with their decimals, the maker can add tokens to the pool at any time
+func getPoolTokens(ctx context.Context, ethrpcClient *ethrpc.Client, poolAddress string) ([]*entity.PoolToken, error) {
+	var tokenAddresses []common.Address
+	if _, err := ethrpcClient.NewRequest().SetContext(ctx).AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: poolAddress,
+		Method: poolMethodGetPoolTokenlist,
+	}, []interface{}{&tokenAddresses}).Call(); err != nil {
+		return nil, err
+	}
+
+	if len(tokenAddresses) == 0 {
+		return nil, nil
+	}
+
+	decimals := make([]uint8, len(tokenAddresses))
+	calls := ethrpcClient.NewRequest().SetContext(ctx)
+	for i, tokenAddress := range tokenAddresses {
+		calls.AddCall(&ethrpc.Call{
+			ABI:    erc20ABI,
+			Target: tokenAddress.Hex(),
+			Method: erc20MethodDecimals,
+		}, []interface{}{&decimals[i]})
+	}
+	if _, err := calls.Aggregate(); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*entity.PoolToken, len(tokenAddresses))
+	for i, tokenAddress := range tokenAddresses {
+		tokens[i] = &entity.PoolToken{
+			Address:   strings.ToLower(tokenAddress.Hex()),
+			Decimals:  decimals[i],
+			Weight:    defaultTokenWeight,
+			Swappable: true,
+		}
+	}
+
+	return tokens, nil
+}
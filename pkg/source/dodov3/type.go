@@ -0,0 +1,65 @@
+package dodov3
+
+import "math/big"
+
+type Metadata struct {
+	Offset int `json:"offset"`
+}
+
+// Extra keeps the market making info of each token of the pool, in the order of the tokens of the pool. The prices,
+// amounts and cumulatives are in 18 decimals.
+type Extra struct {
+	TokenMMInfos []TokenMMInfo `json:"tokenMMInfos"`
+}
+
+// TokenMMInfo is the range orders of a token against vUSD, the virtual USD every token of a pool is priced in. The pool
+// sells the token on the ask order and buys it on the bid order, and the cumulatives are the amounts filled since the
+// maker last set the orders.
+type TokenMMInfo struct {
+	// AskDownPrice is the price of the token in vUSD at which the ask order starts
+	AskDownPrice *big.Int `json:"askDownPrice"`
+	// BidDownPrice is the price of vUSD in the token at which the bid order starts
+	BidDownPrice *big.Int `json:"bidDownPrice"`
+	// AskAmount is the amount of the token of the ask order
+	AskAmount *big.Int `json:"askAmount"`
+	// BidAmount is the amount of vUSD of the bid order
+	BidAmount     *big.Int `json:"bidAmount"`
+	KAsk          *big.Int `json:"kAsk"`
+	KBid          *big.Int `json:"kBid"`
+	CumulativeAsk *big.Int `json:"cumulativeAsk"`
+	CumulativeBid *big.Int `json:"cumulativeBid"`
+	SwapFeeRate   *big.Int `json:"swapFeeRate"`
+}
+
+type TokenMMPriceInfo struct {
+	AskDownPrice *big.Int
+	AskUpPrice   *big.Int
+	BidDownPrice *big.Int
+	BidUpPrice   *big.Int
+	SwapFee      *big.Int
+}
+
+type TokenMMOtherInfo struct {
+	AskAmount     *big.Int
+	BidAmount     *big.Int
+	KAsk          *big.Int
+	KBid          *big.Int
+	CumulativeAsk *big.Int
+	CumulativeBid *big.Int
+}
+
+// SwapInfo keeps the amounts which fill the range orders, in 18 decimals
+type SwapInfo struct {
+	VUSDAmount    *big.Int `json:"vusdAmount"`
+	ReceiveAmount *big.Int `json:"receiveAmount"`
+}
+
+// Meta is what D3MM.sellToken takes besides the amounts
+type Meta struct {
+	FromToken string `json:"fromToken"`
+	ToToken   string `json:"toToken"`
+}
+
+type Gas struct {
+	Swap int64
+}
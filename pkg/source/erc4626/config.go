@@ -0,0 +1,11 @@
+package erc4626
+
+type Config struct {
+	DexID      string    `json:"-"`
+	ConfigPath string    `json:"configPath"`
+	DexConfig  DexConfig `json:"-"`
+
+	// Owner is the address passed to maxDeposit/maxRedeem, usually the executor holding shares during a swap.
+	// Leave it empty for vaults whose limits do not depend on the caller.
+	Owner string `json:"owner"`
+}
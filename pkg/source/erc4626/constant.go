@@ -0,0 +1,20 @@
+package erc4626
+
+const DexTypeERC4626 = "erc4626"
+
+const (
+	vaultMethodTotalAssets    = "totalAssets"
+	vaultMethodTotalSupply    = "totalSupply"
+	vaultMethodMaxDeposit     = "maxDeposit"
+	vaultMethodMaxRedeem      = "maxRedeem"
+	vaultMethodPreviewDeposit = "previewDeposit"
+	vaultMethodPreviewRedeem  = "previewRedeem"
+)
+
+const (
+	reserveZero = "0"
+)
+
+var (
+	DefaultGas = Gas{Deposit: 65000, Redeem: 55000}
+)
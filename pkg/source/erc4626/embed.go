@@ -0,0 +1,13 @@
+package erc4626
+
+import _ "embed"
+
+//go:embed dexconfig/ethereum.json
+var ethereumDexConfigBytes []byte
+
+var bytesByPath = map[string][]byte{
+	"dexconfig/ethereum.json": ethereumDexConfigBytes,
+}
+
+//go:embed abis/ERC4626.json
+var erc4626ABIBytes []byte
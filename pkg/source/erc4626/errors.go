@@ -0,0 +1,11 @@
+package erc4626
+
+import "errors"
+
+var (
+	ErrInvalidToken              = errors.New("invalid token")
+	ErrZeroAmount                = errors.New("zero amount")
+	ErrERC4626DepositMoreThanMax = errors.New("ERC4626: deposit more than max")
+	ErrERC4626RedeemMoreThanMax  = errors.New("ERC4626: redeem more than max")
+	ErrInsufficientAssets        = errors.New("insufficient assets")
+)
@@ -0,0 +1,117 @@
+package erc4626
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// PoolSimulator quotes an ERC-4626 vault as a 2-token pool: Tokens[0] is the underlying asset, Tokens[1] is the
+// vault share (the pool address).
+type PoolSimulator struct {
+	pool.Pool
+
+	Vault Vault
+
+	gas Gas
+}
+
+func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
+	var extra Extra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	if len(entityPool.Tokens) != 2 || extra.TotalAssets == nil || extra.TotalSupply == nil {
+		return nil, ErrInvalidToken
+	}
+
+	tokens := make([]string, 0, len(entityPool.Tokens))
+	reserves := make([]*big.Int, 0, len(entityPool.Tokens))
+	for i, poolToken := range entityPool.Tokens {
+		tokens = append(tokens, poolToken.Address)
+		reserves = append(reserves, bignumber.NewBig10(entityPool.Reserves[i]))
+	}
+
+	return &PoolSimulator{
+		Pool: pool.Pool{
+			Info: pool.PoolInfo{
+				Address:    strings.ToLower(entityPool.Address),
+				ReserveUsd: entityPool.ReserveUsd,
+				SwapFee:    bignumber.ZeroBI,
+				Exchange:   entityPool.Exchange,
+				Type:       entityPool.Type,
+				Tokens:     tokens,
+				Reserves:   reserves,
+			},
+		},
+		Vault: Vault{
+			TotalAssets: extra.TotalAssets,
+			TotalSupply: extra.TotalSupply,
+			MaxDeposit:  extra.MaxDeposit,
+			MaxRedeem:   extra.MaxRedeem,
+			DepositRate: extra.DepositRate,
+			RedeemRate:  extra.RedeemRate,
+			assetUnit:   bignumber.TenPowInt(entityPool.Tokens[0].Decimals),
+			shareUnit:   bignumber.TenPowInt(entityPool.Tokens[1].Decimals),
+		},
+		gas: DefaultGas,
+	}, nil
+}
+
+func (p *PoolSimulator) CalcAmountOut(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	tokenInIndex, tokenOutIndex := p.GetTokenIndex(tokenAmountIn.Token), p.GetTokenIndex(tokenOut)
+	if tokenInIndex < 0 || tokenOutIndex < 0 || tokenInIndex == tokenOutIndex {
+		return nil, ErrInvalidToken
+	}
+
+	var (
+		amountOut *big.Int
+		gas       int64
+		err       error
+	)
+	if tokenInIndex == 0 {
+		amountOut, err = p.Vault.deposit(tokenAmountIn.Amount)
+		gas = p.gas.Deposit
+	} else {
+		amountOut, err = p.Vault.redeem(tokenAmountIn.Amount)
+		gas = p.gas.Redeem
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{
+			Token:  tokenOut,
+			Amount: amountOut,
+		},
+		Fee: &pool.TokenAmount{
+			Token:  tokenAmountIn.Token,
+			Amount: bignumber.ZeroBI,
+		},
+		Gas: gas,
+	}, nil
+}
+
+func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	input, output := params.TokenAmountIn, params.TokenAmountOut
+	if p.GetTokenIndex(input.Token) == 0 {
+		p.Vault.updateBalanceDeposit(input.Amount, output.Amount)
+	} else {
+		p.Vault.updateBalanceRedeem(input.Amount, output.Amount)
+	}
+
+	p.Info.Reserves = []*big.Int{p.Vault.TotalAssets, p.Vault.TotalSupply}
+}
+
+func (p *PoolSimulator) GetMetaInfo(_ string, _ string) interface{} {
+	return nil
+}
@@ -0,0 +1,121 @@
+package erc4626
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPool(t *testing.T, extra string) *PoolSimulator {
+	p, err := NewPoolSimulator(entity.Pool{
+		Address:  "sDAI",
+		Reserves: entity.PoolReserves{"0", "0"},
+		Tokens:   []*entity.PoolToken{{Address: "DAI", Decimals: 18}, {Address: "sDAI", Decimals: 18}},
+		Extra:    extra,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"sDAI"}, p.CanSwapTo("DAI"))
+	assert.Equal(t, []string{"DAI"}, p.CanSwapTo("sDAI"))
+	return p
+}
+
+func TestPoolSimulator_CalcAmountOut(t *testing.T) {
+	// totalAssets/totalSupply = 1.5
+	p := newPool(t, `{"totalAssets":1500,"totalSupply":1000,"maxDeposit":1000,"maxRedeem":null}`)
+
+	testcases := []struct {
+		in                string
+		inAmount          int64
+		out               string
+		expectedOutAmount int64
+		expectedErr       error
+	}{
+		// shares = 100 * 1000 / 1500 = 66.67, rounded down
+		{"DAI", 100, "sDAI", 66, nil},
+		// assets = 67 * 1500 / 1000 = 100.5, rounded down
+		{"sDAI", 67, "DAI", 100, nil},
+		{"DAI", 1, "sDAI", 0, ErrZeroAmount},
+		{"DAI", 1001, "sDAI", 0, ErrERC4626DepositMoreThanMax},
+		{"sDAI", 1001, "DAI", 0, ErrERC4626RedeemMoreThanMax},
+	}
+
+	for idx, tc := range testcases {
+		t.Run(fmt.Sprintf("test %d", idx), func(t *testing.T) {
+			out, err := p.CalcAmountOut(pool.TokenAmount{Token: tc.in, Amount: big.NewInt(tc.inAmount)}, tc.out)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, big.NewInt(tc.expectedOutAmount), out.TokenAmountOut.Amount)
+			assert.Equal(t, tc.out, out.TokenAmountOut.Token)
+		})
+	}
+}
+
+func TestPoolSimulator_PreviewRates(t *testing.T) {
+	// 1% entry and exit fee on top of a 1:1 vault
+	p := newPool(t, fmt.Sprintf(`{"totalAssets":%v,"totalSupply":%v,"depositRate":%v,"redeemRate":%v}`,
+		bignumber.BONE, bignumber.BONE, "990000000000000000", "990000000000000000"))
+
+	out, err := p.CalcAmountOut(pool.TokenAmount{Token: "DAI", Amount: big.NewInt(1000)}, "sDAI")
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(990), out.TokenAmountOut.Amount)
+
+	out, err = p.CalcAmountOut(pool.TokenAmount{Token: "sDAI", Amount: big.NewInt(1000)}, "DAI")
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(990), out.TokenAmountOut.Amount)
+}
+
+func TestPoolSimulator_UpdateBalance(t *testing.T) {
+	p := newPool(t, `{"totalAssets":1500,"totalSupply":1000,"maxDeposit":300}`)
+
+	inAmount := pool.TokenAmount{Token: "DAI", Amount: big.NewInt(300)}
+	out, err := p.CalcAmountOut(inAmount, "sDAI")
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(200), out.TokenAmountOut.Amount)
+
+	p.UpdateBalance(pool.UpdateBalanceParams{TokenAmountIn: inAmount, TokenAmountOut: *out.TokenAmountOut, Fee: *out.Fee})
+	assert.Equal(t, big.NewInt(1800), p.Vault.TotalAssets)
+	assert.Equal(t, big.NewInt(1200), p.Vault.TotalSupply)
+
+	// deposit cap is used up
+	_, err = p.CalcAmountOut(pool.TokenAmount{Token: "DAI", Amount: big.NewInt(1)}, "sDAI")
+	assert.ErrorIs(t, err, ErrERC4626DepositMoreThanMax)
+
+	// the virtual share and asset take the last unit: 1200 * 1801 / 1201
+	inAmount = pool.TokenAmount{Token: "sDAI", Amount: big.NewInt(1200)}
+	out, err = p.CalcAmountOut(inAmount, "DAI")
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(1799), out.TokenAmountOut.Amount)
+}
+
+func TestPoolSimulator_EmptyVaultDecimalsOffset(t *testing.T) {
+	// a USDC vault with 18-decimal shares mints 10^12 shares per asset while it is empty
+	p, err := NewPoolSimulator(entity.Pool{
+		Address:  "vUSDC",
+		Reserves: entity.PoolReserves{"0", "0"},
+		Tokens:   []*entity.PoolToken{{Address: "USDC", Decimals: 6}, {Address: "vUSDC", Decimals: 18}},
+		Extra:    `{"totalAssets":0,"totalSupply":0}`,
+	})
+	require.Nil(t, err)
+
+	out, err := p.CalcAmountOut(pool.TokenAmount{Token: "USDC", Amount: big.NewInt(1_000_000)}, "vUSDC")
+	require.Nil(t, err)
+	assert.Equal(t, bignumber.BONE, out.TokenAmountOut.Amount)
+}
+
+func TestPoolSimulator_EmptyAssetsWithSupply(t *testing.T) {
+	// OpenZeppelin does not special-case a vault whose assets are gone while it still has shares: 100 * 1001 / 1
+	p := newPool(t, `{"totalAssets":0,"totalSupply":1000}`)
+
+	out, err := p.CalcAmountOut(pool.TokenAmount{Token: "DAI", Amount: big.NewInt(100)}, "sDAI")
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(100100), out.TokenAmountOut.Amount)
+}
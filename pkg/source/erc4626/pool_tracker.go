@@ -0,0 +1,137 @@
+package erc4626
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	sourcePool "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+type PoolTracker struct {
+	cfg          *Config
+	ethrpcClient *ethrpc.Client
+}
+
+func NewPoolTracker(cfg *Config, ethrpcClient *ethrpc.Client) *PoolTracker {
+	return &PoolTracker{
+		cfg:          cfg,
+		ethrpcClient: ethrpcClient,
+	}
+}
+
+func (d *PoolTracker) GetNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	_ sourcePool.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	defer func(startTime time.Time) {
+		logger.
+			WithFields(logger.Fields{
+				"dexID":             d.cfg.DexID,
+				"poolsUpdatedCount": "1",
+				"duration":          time.Since(startTime).Milliseconds(),
+			}).
+			Info("finished GetNewPoolState")
+	}(time.Now())
+
+	extra, err := d.getExtra(ctx, p)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"dexID":       d.cfg.DexID,
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Error("get vault state error")
+		return entity.Pool{}, err
+	}
+
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"dexID": d.cfg.DexID,
+			"error": err,
+		}).Error("can not marshal extra")
+		return entity.Pool{}, err
+	}
+
+	p.Reserves = entity.PoolReserves{extra.TotalAssets.String(), extra.TotalSupply.String()}
+	p.Extra = string(extraBytes)
+	p.Timestamp = time.Now().Unix()
+
+	return p, nil
+}
+
+func (d *PoolTracker) getExtra(ctx context.Context, p entity.Pool) (Extra, error) {
+	var (
+		extra Extra
+		owner = common.HexToAddress(d.cfg.Owner)
+	)
+
+	req := d.ethrpcClient.
+		NewRequest().
+		SetContext(ctx).
+		AddCall(&ethrpc.Call{
+			ABI:    erc4626ABI,
+			Target: p.Address,
+			Method: vaultMethodTotalAssets,
+			Params: nil,
+		}, []interface{}{&extra.TotalAssets}).
+		AddCall(&ethrpc.Call{
+			ABI:    erc4626ABI,
+			Target: p.Address,
+			Method: vaultMethodTotalSupply,
+			Params: nil,
+		}, []interface{}{&extra.TotalSupply}).
+		AddCall(&ethrpc.Call{
+			ABI:    erc4626ABI,
+			Target: p.Address,
+			Method: vaultMethodMaxDeposit,
+			Params: []interface{}{owner},
+		}, []interface{}{&extra.MaxDeposit}).
+		AddCall(&ethrpc.Call{
+			ABI:    erc4626ABI,
+			Target: p.Address,
+			Method: vaultMethodPreviewDeposit,
+			Params: []interface{}{bignumber.TenPowInt(p.Tokens[0].Decimals)},
+		}, []interface{}{&extra.DepositRate}).
+		AddCall(&ethrpc.Call{
+			ABI:    erc4626ABI,
+			Target: p.Address,
+			Method: vaultMethodPreviewRedeem,
+			Params: []interface{}{bignumber.TenPowInt(p.Tokens[1].Decimals)},
+		}, []interface{}{&extra.RedeemRate})
+
+	// maxRedeem is bounded by the owner's share balance, it is only meaningful for a configured owner
+	if len(d.cfg.Owner) > 0 {
+		req.AddCall(&ethrpc.Call{
+			ABI:    erc4626ABI,
+			Target: p.Address,
+			Method: vaultMethodMaxRedeem,
+			Params: []interface{}{owner},
+		}, []interface{}{&extra.MaxRedeem})
+	}
+
+	if _, err := req.Aggregate(); err != nil {
+		logger.WithFields(logger.Fields{
+			"dexID": d.cfg.DexID,
+			"error": err,
+		}).Error("eth rpc call error")
+		return Extra{}, err
+	}
+
+	if extra.TotalAssets == nil {
+		extra.TotalAssets = big.NewInt(0)
+	}
+	if extra.TotalSupply == nil {
+		extra.TotalSupply = big.NewInt(0)
+	}
+
+	return extra, nil
+}
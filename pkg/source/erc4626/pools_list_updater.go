@@ -0,0 +1,100 @@
+package erc4626
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/logger"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+)
+
+type PoolsListUpdater struct {
+	cfg            *Config
+	hasInitialized bool
+}
+
+func NewPoolsListUpdater(cfg *Config) *PoolsListUpdater {
+	return &PoolsListUpdater{
+		cfg: cfg,
+	}
+}
+
+func (d *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte) ([]entity.Pool, []byte, error) {
+	logger.WithFields(logger.Fields{"dexID": d.cfg.DexID}).Info("get new pools")
+
+	if d.hasInitialized {
+		return nil, nil, nil
+	}
+
+	err := d.initializeDexConfig()
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"dexID": d.cfg.DexID,
+			"error": err,
+		}).Error("can not initialize dex config")
+		return nil, nil, err
+	}
+
+	pools := make([]entity.Pool, 0, len(d.cfg.DexConfig.Vaults))
+	for _, vaultCfg := range d.cfg.DexConfig.Vaults {
+		pools = append(pools, d.newPool(vaultCfg))
+	}
+
+	logger.WithFields(logger.Fields{"dexID": d.cfg.DexID}).Info("get new pools successfully")
+
+	d.hasInitialized = true
+
+	return pools, nil, nil
+}
+
+func (d *PoolsListUpdater) newPool(vaultCfg VaultConfig) entity.Pool {
+	vaultAddress := strings.ToLower(vaultCfg.Address)
+
+	asset := &entity.PoolToken{
+		Address:   strings.ToLower(vaultCfg.Asset.Address),
+		Decimals:  vaultCfg.Asset.Decimals,
+		Swappable: true,
+	}
+
+	share := &entity.PoolToken{
+		Address:   vaultAddress,
+		Decimals:  vaultCfg.Decimals,
+		Swappable: true,
+	}
+
+	return entity.Pool{
+		Address:   vaultAddress,
+		Exchange:  d.cfg.DexID,
+		Type:      DexTypeERC4626,
+		Tokens:    []*entity.PoolToken{asset, share},
+		Reserves:  entity.PoolReserves{reserveZero, reserveZero},
+		Timestamp: time.Now().Unix(),
+	}
+}
+
+func (d *PoolsListUpdater) initializeDexConfig() error {
+	dexConfigBytes, ok := bytesByPath[d.cfg.ConfigPath]
+	if !ok {
+		err := fmt.Errorf("key %s not found", d.cfg.ConfigPath)
+		logger.WithFields(logger.Fields{
+			"dexID": d.cfg.DexID,
+			"error": err,
+		}).Error("can not find dex config")
+		return err
+	}
+
+	err := json.Unmarshal(dexConfigBytes, &d.cfg.DexConfig)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"dexID": d.cfg.DexID,
+			"error": err,
+		}).Error("can not unmarshal dex config")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package erc4626
+
+import (
+	"math/big"
+)
+
+type DexConfig struct {
+	Vaults []VaultConfig `json:"vaults"`
+}
+
+type VaultConfig struct {
+	Address  string `json:"address"`
+	Asset    Token  `json:"asset"`
+	Decimals uint8  `json:"decimals"`
+}
+
+type Token struct {
+	Address  string `json:"address"`
+	Decimals uint8  `json:"decimals"`
+}
+
+type Extra struct {
+	TotalAssets *big.Int `json:"totalAssets"`
+	TotalSupply *big.Int `json:"totalSupply"`
+	MaxDeposit  *big.Int `json:"maxDeposit"`
+	MaxRedeem   *big.Int `json:"maxRedeem,omitempty"`
+
+	// DepositRate is previewDeposit(10^assetDecimals), RedeemRate is previewRedeem(10^shareDecimals).
+	// They capture entry/exit fees that are not visible from totalAssets/totalSupply.
+	DepositRate *big.Int `json:"depositRate"`
+	RedeemRate  *big.Int `json:"redeemRate"`
+}
+
+type Gas struct {
+	Deposit int64
+	Redeem  int64
+}
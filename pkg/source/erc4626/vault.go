@@ -0,0 +1,121 @@
+package erc4626
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// Vault implements the share/asset conversions of EIP-4626
+// https://eips.ethereum.org/EIPS/eip-4626
+// previewDeposit and previewRedeem both round down, in favour of the vault.
+type Vault struct {
+	TotalAssets *big.Int
+	TotalSupply *big.Int
+	MaxDeposit  *big.Int
+	MaxRedeem   *big.Int
+	DepositRate *big.Int
+	RedeemRate  *big.Int
+
+	assetUnit *big.Int
+	shareUnit *big.Int
+}
+
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(bignumber.One, 256), bignumber.One)
+
+func (v *Vault) deposit(assets *big.Int) (*big.Int, error) {
+	if assets.Sign() <= 0 {
+		return nil, ErrZeroAmount
+	}
+
+	if v.MaxDeposit != nil && assets.Cmp(v.MaxDeposit) > 0 {
+		return nil, ErrERC4626DepositMoreThanMax
+	}
+
+	shares := v.convertToShares(assets)
+
+	// previewDeposit may charge an entry fee, never quote more than it implies
+	if v.DepositRate != nil && v.DepositRate.Sign() > 0 {
+		byRate := new(big.Int).Div(new(big.Int).Mul(assets, v.DepositRate), v.assetUnit)
+		if byRate.Cmp(shares) < 0 {
+			shares = byRate
+		}
+	}
+
+	if shares.Sign() <= 0 {
+		return nil, ErrZeroAmount
+	}
+
+	return shares, nil
+}
+
+func (v *Vault) redeem(shares *big.Int) (*big.Int, error) {
+	if shares.Sign() <= 0 {
+		return nil, ErrZeroAmount
+	}
+
+	if shares.Cmp(v.TotalSupply) > 0 || (v.MaxRedeem != nil && shares.Cmp(v.MaxRedeem) > 0) {
+		return nil, ErrERC4626RedeemMoreThanMax
+	}
+
+	assets := v.convertToAssets(shares)
+
+	// previewRedeem may charge an exit fee, never quote more than it implies
+	if v.RedeemRate != nil && v.RedeemRate.Sign() > 0 {
+		byRate := new(big.Int).Div(new(big.Int).Mul(shares, v.RedeemRate), v.shareUnit)
+		if byRate.Cmp(assets) < 0 {
+			assets = byRate
+		}
+	}
+
+	if assets.Sign() <= 0 {
+		return nil, ErrZeroAmount
+	}
+
+	if assets.Cmp(v.TotalAssets) > 0 {
+		return nil, ErrInsufficientAssets
+	}
+
+	return assets, nil
+}
+
+// convertToShares = assets * (totalSupply + 10^offset) / (totalAssets + 1), rounded down, like OpenZeppelin's ERC4626
+// with the decimals offset between the share and the asset. The virtual share and asset make an empty vault mint
+// 10^offset shares per asset.
+func (v *Vault) convertToShares(assets *big.Int) *big.Int {
+	numerator := new(big.Int).Mul(assets, new(big.Int).Add(v.TotalSupply, v.decimalsOffsetUnit()))
+	return numerator.Div(numerator, new(big.Int).Add(v.TotalAssets, bignumber.One))
+}
+
+// convertToAssets = shares * (totalAssets + 1) / (totalSupply + 10^offset), rounded down, like OpenZeppelin's ERC4626
+func (v *Vault) convertToAssets(shares *big.Int) *big.Int {
+	numerator := new(big.Int).Mul(shares, new(big.Int).Add(v.TotalAssets, bignumber.One))
+	return numerator.Div(numerator, new(big.Int).Add(v.TotalSupply, v.decimalsOffsetUnit()))
+}
+
+// decimalsOffsetUnit is 10^(shareDecimals - assetDecimals), 1 when the shares do not have more decimals than the asset
+func (v *Vault) decimalsOffsetUnit() *big.Int {
+	if v.shareUnit.Cmp(v.assetUnit) <= 0 {
+		return bignumber.One
+	}
+
+	return new(big.Int).Div(v.shareUnit, v.assetUnit)
+}
+
+func (v *Vault) updateBalanceDeposit(assets, shares *big.Int) {
+	v.TotalAssets = new(big.Int).Add(v.TotalAssets, assets)
+	v.TotalSupply = new(big.Int).Add(v.TotalSupply, shares)
+
+	if v.MaxDeposit != nil && v.MaxDeposit.Cmp(maxUint256) != 0 {
+		v.MaxDeposit = new(big.Int).Sub(v.MaxDeposit, assets)
+	}
+}
+
+func (v *Vault) updateBalanceRedeem(shares, assets *big.Int) {
+	v.TotalAssets = new(big.Int).Sub(v.TotalAssets, assets)
+	v.TotalSupply = new(big.Int).Sub(v.TotalSupply, shares)
+
+	if v.MaxRedeem != nil && v.MaxRedeem.Cmp(maxUint256) != 0 {
+		v.MaxRedeem = new(big.Int).Sub(v.MaxRedeem, shares)
+	}
+}
@@ -1,5 +1,7 @@
 package fraxswap
 
+import "math/big"
+
 const (
 	DexTypeFraxswap    = "fraxswap"
 	defaultTokenWeight = 50
@@ -7,14 +9,26 @@ const (
 	poolFactoryMethodAllPairsLength = "allPairsLength"
 	poolFactoryMethodAllPairs       = "allPairs"
 
-	poolMethodToken0               = "token0"
-	poolMethodToken1               = "token1"
-	poolMethodGetReserveAfterTwamm = "getReserveAfterTwamm"
-	poolMethodFee                  = "fee"
+	poolMethodToken0                  = "token0"
+	poolMethodToken1                  = "token1"
+	poolMethodGetReserveAfterTwamm    = "getReserveAfterTwamm"
+	poolMethodFee                     = "fee"
+	poolMethodGetTwammReserves        = "getTwammReserves"
+	poolMethodGetTwammState           = "getTwammState"
+	poolMethodGetTwammSalesRateEnding = "getTwammSalesRateEnding"
+
+	// twammExpiryLookahead is the number of order time intervals after the fetch time whose order expirations
+	// are fetched, the virtual orders are not projected further than that.
+	twammExpiryLookahead = 24
+	// twammMaxIntervals is the max number of order time intervals whose order expirations are fetched
+	twammMaxIntervals = 24*7 + twammExpiryLookahead
 
 	reserveZero = "0"
 )
 
 var (
 	DefaultGas = Gas{Swap: 113276}
+
+	// SellRateAdditionalPrecision is SELL_RATE_ADDITIONAL_PRECISION of LongTermOrders, the sales rates are scaled by it
+	SellRateAdditionalPrecision = big.NewInt(1000000)
 )
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"math/big"
 	"strings"
+	"time"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
@@ -27,6 +28,13 @@ type (
 		Reserve0 *big.Int
 		Reserve1 *big.Int
 
+		// The TWAMM state, Reserve0 and Reserve1 are the reserves at LastVirtualOrderTimestamp
+		LastVirtualOrderTimestamp int64
+		Token0Rate                *big.Int
+		Token1Rate                *big.Int
+		OrderExpiries             []OrderExpiry
+		OrderExpiriesUntil        int64
+
 		gas Gas
 	}
 )
@@ -69,10 +77,15 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 				Checked:    false,
 			},
 		},
-		Fee:      extra.Fee,
-		Reserve0: extra.Reserve0,
-		Reserve1: extra.Reserve1,
-		gas:      DefaultGas,
+		Fee:                       extra.Fee,
+		Reserve0:                  extra.Reserve0,
+		Reserve1:                  extra.Reserve1,
+		LastVirtualOrderTimestamp: extra.LastVirtualOrderTimestamp,
+		Token0Rate:                orZero(extra.Token0Rate),
+		Token1Rate:                orZero(extra.Token1Rate),
+		OrderExpiries:             extra.OrderExpiries,
+		OrderExpiriesUntil:        extra.OrderExpiriesUntil,
+		gas:                       DefaultGas,
 	}, nil
 }
 
@@ -81,16 +94,17 @@ func (p *PoolSimulator) CalcAmountOut(
 	tokenOut string,
 ) (*pool.CalcAmountOutResult, error) {
 	var (
+		state      = p.executeVirtualOrders(time.Now().Unix())
 		reserveOut *big.Int
 	)
 
 	if strings.EqualFold(tokenAmountIn.Token, p.Info.Tokens[0]) {
-		reserveOut = p.Reserve1
+		reserveOut = state.reserve1
 	} else {
-		reserveOut = p.Reserve0
+		reserveOut = state.reserve0
 	}
 
-	amountOut, err := p.getAmountOut(tokenAmountIn.Amount, tokenAmountIn.Token)
+	amountOut, err := p.getAmountOut(tokenAmountIn.Amount, tokenAmountIn.Token, state.reserve0, state.reserve1)
 	if err != nil {
 		return &pool.CalcAmountOutResult{}, err
 	}
@@ -122,12 +136,19 @@ func (p *PoolSimulator) CalcAmountOut(
 	}, nil
 }
 
+// UpdateBalance executes the virtual orders before the swap, like the swap function of the pair does
 func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
-	amountOut, err := p.getAmountOut(params.TokenAmountIn.Amount, params.TokenAmountIn.Token)
+	state := p.executeVirtualOrders(time.Now().Unix())
+	amountOut, err := p.getAmountOut(params.TokenAmountIn.Amount, params.TokenAmountIn.Token, state.reserve0, state.reserve1)
 	if err != nil {
 		return
 	}
 
+	p.Reserve0, p.Reserve1 = state.reserve0, state.reserve1
+	p.Token0Rate, p.Token1Rate = state.token0Rate, state.token1Rate
+	p.LastVirtualOrderTimestamp = state.lastVirtualOrderTimestamp
+	p.OrderExpiries = state.orderExpiries
+
 	amountIn := new(big.Int).Div(
 		new(big.Int).Mul(params.TokenAmountIn.Amount, p.Fee),
 		FeePrecision,
@@ -163,7 +184,8 @@ func (p *PoolSimulator) CanSwapTo(address string) []string {
 }
 
 func (p *PoolSimulator) GetMidPrice(tokenIn string, _ string, base *big.Int) *big.Int {
-	exactQuote, err := p.getAmountOut(base, tokenIn)
+	state := p.executeVirtualOrders(time.Now().Unix())
+	exactQuote, err := p.getAmountOut(base, tokenIn, state.reserve0, state.reserve1)
 	if err != nil {
 		return bignumber.ZeroBI
 	}
@@ -172,7 +194,8 @@ func (p *PoolSimulator) GetMidPrice(tokenIn string, _ string, base *big.Int) *bi
 }
 
 func (p *PoolSimulator) CalcExactQuote(tokenIn string, _ string, base *big.Int) *big.Int {
-	exactQuote, err := p.getAmountOut(base, tokenIn)
+	state := p.executeVirtualOrders(time.Now().Unix())
+	exactQuote, err := p.getAmountOut(base, tokenIn, state.reserve0, state.reserve1)
 	if err != nil {
 		return bignumber.ZeroBI
 	}
@@ -192,16 +215,16 @@ func (p *PoolSimulator) GetMetaInfo(_ string, _ string) interface{} {
 // getAmountOut given an input amount of an asset and pair reserves, returns the maximum output amount of the other asset
 // amountOut = (amountIn * fee * reserveOut) / ((reserveIn * 10000) + (amountIn * fee))
 // https://github.com/FraxFinance/frax-solidity/blob/012909d168ec0eb549aa9689c0d5cd0cafee400b/src/echidna/FraxswapPairV2.sol#L868
-func (p *PoolSimulator) getAmountOut(amountIn *big.Int, tokenIn string) (*big.Int, error) {
+func (p *PoolSimulator) getAmountOut(amountIn *big.Int, tokenIn string, reserve0, reserve1 *big.Int) (*big.Int, error) {
 	var (
 		reserveIn  *big.Int
 		reserveOut *big.Int
 	)
 
 	if strings.EqualFold(tokenIn, p.Info.Tokens[0]) {
-		reserveIn, reserveOut = p.Reserve0, p.Reserve1
+		reserveIn, reserveOut = reserve0, reserve1
 	} else {
-		reserveIn, reserveOut = p.Reserve1, p.Reserve0
+		reserveIn, reserveOut = reserve1, reserve0
 	}
 
 	if amountIn.Cmp(bignumber.ZeroBI) <= 0 {
@@ -218,3 +241,26 @@ func (p *PoolSimulator) getAmountOut(amountIn *big.Int, tokenIn string) (*big.In
 
 	return new(big.Int).Div(numerator, denominator), nil
 }
+
+// executeVirtualOrders returns the state of the pool after the virtual orders are executed until blockTimestamp,
+// the pool itself is not changed.
+func (p *PoolSimulator) executeVirtualOrders(blockTimestamp int64) twammState {
+	state := twammState{
+		reserve0:                  p.Reserve0,
+		reserve1:                  p.Reserve1,
+		token0Rate:                p.Token0Rate,
+		token1Rate:                p.Token1Rate,
+		lastVirtualOrderTimestamp: p.LastVirtualOrderTimestamp,
+		orderExpiries:             p.OrderExpiries,
+	}
+	state.executeVirtualOrders(blockTimestamp, p.OrderExpiriesUntil, p.Fee)
+
+	return state
+}
+
+func orZero(value *big.Int) *big.Int {
+	if value == nil {
+		return bignumber.ZeroBI
+	}
+	return value
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -90,3 +91,42 @@ func TestPoolSimulator_CalcAmountOut(t *testing.T) {
 		})
 	}
 }
+
+func TestPoolSimulator_executeVirtualOrders(t *testing.T) {
+	// token0 is sold at 100/s until 1100, token1 is sold at 20/s
+	p, err := NewPoolSimulator(entity.Pool{
+		Reserves: []string{"1000000000000000000000000", "1000000000000000000000000"},
+		Tokens:   []*entity.PoolToken{{Address: "a"}, {Address: "b"}},
+		Extra: `{"reserve0": 1000000000000000000000000, "reserve1": 1000000000000000000000000, "fee": 9970,
+			"lastVirtualOrderTimestamp": 1000, "orderTimeInterval": 100,
+			"token0Rate": 100000000000000000000000000, "token1Rate": 20000000000000000000000000,
+			"orderExpiries": [{"timestamp": 1100, "token0RateEnding": 100000000000000000000000000, "token1RateEnding": 0}],
+			"orderExpiriesUntil": 1150}`,
+	})
+	require.Nil(t, err)
+
+	// both order pools sell until 1100, then only the token1 order pool sells
+	state := p.executeVirtualOrders(1150)
+	assert.Equal(t, "1006978184747825786402932", state.reserve0.String())
+	assert.Equal(t, "993108735724823509609196", state.reserve1.String())
+	assert.Equal(t, "0", state.token0Rate.String())
+	assert.Equal(t, int64(1150), state.lastVirtualOrderTimestamp)
+	assert.Len(t, state.orderExpiries, 0)
+
+	// the virtual orders are not executed after orderExpiriesUntil
+	assert.Equal(t, state, p.executeVirtualOrders(5000))
+	// the pool itself is not changed
+	assert.Equal(t, "1000000000000000000000000", p.Reserve0.String())
+
+	// the simulation time is after orderExpiriesUntil, so the quote uses the state at 1150
+	result, err := p.CalcAmountOut(pool.TokenAmount{Token: "a", Amount: bignumber.NewBig10("1000000000000000000000")}, "b")
+	require.Nil(t, err)
+	assert.Equal(t, "982295422049857918531", result.TokenAmountOut.Amount.String())
+
+	p.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  pool.TokenAmount{Token: "a", Amount: bignumber.NewBig10("1000000000000000000000")},
+		TokenAmountOut: *result.TokenAmountOut,
+	})
+	assert.Equal(t, int64(1150), p.LastVirtualOrderTimestamp)
+	assert.Equal(t, "1007975184747825786402932", p.Reserve0.String())
+}
@@ -35,16 +35,33 @@ func (d *PoolTracker) GetNewPoolState(
 
 	var reserveAfterTwammOutput ReserveAfterTwammOutput
 	var feeOutput FeeOutput
+	var twammReservesOutput TwammReservesOutput
+	var twammStateOutput TwammStateOutput
 
+	now := time.Now().Unix()
 	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
 
 	calls.AddCall(&ethrpc.Call{
 		ABI:    pairABI,
 		Target: p.Address,
 		Method: poolMethodGetReserveAfterTwamm,
-		Params: []interface{}{big.NewInt(time.Now().Unix())},
+		Params: []interface{}{big.NewInt(now)},
 	}, []interface{}{&reserveAfterTwammOutput})
 
+	calls.AddCall(&ethrpc.Call{
+		ABI:    pairABI,
+		Target: p.Address,
+		Method: poolMethodGetTwammReserves,
+		Params: nil,
+	}, []interface{}{&twammReservesOutput})
+
+	calls.AddCall(&ethrpc.Call{
+		ABI:    pairABI,
+		Target: p.Address,
+		Method: poolMethodGetTwammState,
+		Params: nil,
+	}, []interface{}{&twammStateOutput})
+
 	calls.AddCall(&ethrpc.Call{
 		ABI:    pairABI,
 		Target: p.Address,
@@ -65,6 +82,28 @@ func (d *PoolTracker) GetNewPoolState(
 		Reserve1: reserveAfterTwammOutput.Reserve1,
 		Fee:      feeOutput.Fee,
 	}
+
+	if hasLongTermOrders(twammStateOutput) && twammReservesOutput.Reserve0 != nil && twammReservesOutput.Reserve1 != nil {
+		orderExpiries, orderExpiriesUntil, err := d.getOrderExpiries(ctx, p.Address, twammStateOutput, now)
+		if err != nil {
+			log.WithFields(logger.Fields{
+				"error": err,
+			}).Errorf("[Fraxswap] failed to get order expiries")
+
+			return entity.Pool{}, err
+		}
+
+		// the virtual orders are executed by the simulator from the last virtual order timestamp
+		extra.Reserve0 = twammReservesOutput.Reserve0
+		extra.Reserve1 = twammReservesOutput.Reserve1
+		extra.LastVirtualOrderTimestamp = twammStateOutput.LastVirtualOrderTimestamp.Int64()
+		extra.OrderTimeInterval = twammStateOutput.OrderTimeIntervalRtn.Int64()
+		extra.Token0Rate = twammStateOutput.Token0Rate
+		extra.Token1Rate = twammStateOutput.Token1Rate
+		extra.OrderExpiries = orderExpiries
+		extra.OrderExpiriesUntil = orderExpiriesUntil
+	}
+
 	extraBytes, err := json.Marshal(extra)
 	if err != nil {
 		log.WithFields(logger.Fields{
@@ -82,3 +121,67 @@ func (d *PoolTracker) GetNewPoolState(
 
 	return p, nil
 }
+
+func hasLongTermOrders(state TwammStateOutput) bool {
+	return state.LastVirtualOrderTimestamp != nil && state.LastVirtualOrderTimestamp.Sign() > 0 &&
+		state.OrderTimeIntervalRtn != nil && state.OrderTimeIntervalRtn.Sign() > 0 &&
+		((state.Token0Rate != nil && state.Token0Rate.Sign() > 0) || (state.Token1Rate != nil && state.Token1Rate.Sign() > 0))
+}
+
+// getOrderExpiries fetches the sales rates of the orders expiring at each order time interval, from the last virtual
+// order timestamp until twammExpiryLookahead intervals after now. It returns the expiries which have orders and the
+// last timestamp fetched.
+func (d *PoolTracker) getOrderExpiries(
+	ctx context.Context,
+	address string,
+	state TwammStateOutput,
+	now int64,
+) ([]OrderExpiry, int64, error) {
+	var (
+		interval                  = state.OrderTimeIntervalRtn.Int64()
+		lastVirtualOrderTimestamp = state.LastVirtualOrderTimestamp.Int64()
+		nextExpiry                = lastVirtualOrderTimestamp - lastVirtualOrderTimestamp%interval + interval
+		until                     = now - now%interval + interval*twammExpiryLookahead
+	)
+	if maxUntil := nextExpiry + interval*(twammMaxIntervals-1); until > maxUntil {
+		until = maxUntil
+	}
+
+	var timestamps []int64
+	for timestamp := nextExpiry; timestamp <= until; timestamp += interval {
+		timestamps = append(timestamps, timestamp)
+	}
+
+	salesRateEndings := make([]TwammSalesRateEndingOutput, len(timestamps))
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+	for i, timestamp := range timestamps {
+		calls.AddCall(&ethrpc.Call{
+			ABI:    pairABI,
+			Target: address,
+			Method: poolMethodGetTwammSalesRateEnding,
+			Params: []interface{}{big.NewInt(timestamp)},
+		}, []interface{}{&salesRateEndings[i]})
+	}
+
+	if _, err := calls.Aggregate(); err != nil {
+		return nil, 0, err
+	}
+
+	var orderExpiries []OrderExpiry
+	for i, salesRateEnding := range salesRateEndings {
+		if salesRateEnding.OrderPool0SalesRateEnding == nil || salesRateEnding.OrderPool1SalesRateEnding == nil {
+			continue
+		}
+		if salesRateEnding.OrderPool0SalesRateEnding.Sign() == 0 && salesRateEnding.OrderPool1SalesRateEnding.Sign() == 0 {
+			continue
+		}
+
+		orderExpiries = append(orderExpiries, OrderExpiry{
+			Timestamp:        timestamps[i],
+			Token0RateEnding: salesRateEnding.OrderPool0SalesRateEnding,
+			Token1RateEnding: salesRateEnding.OrderPool1SalesRateEnding,
+		})
+	}
+
+	return orderExpiries, until, nil
+}
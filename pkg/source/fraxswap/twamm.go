@@ -0,0 +1,101 @@
+package fraxswap
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// twammState is the state of the long-term orders which is needed to execute the virtual orders
+type twammState struct {
+	reserve0                  *big.Int
+	reserve1                  *big.Int
+	token0Rate                *big.Int
+	token1Rate                *big.Int
+	lastVirtualOrderTimestamp int64
+	// orderExpiries are sorted by timestamp, the executed ones are removed
+	orderExpiries []OrderExpiry
+}
+
+// executeVirtualOrders executes the virtual orders until blockTimestamp like executeVirtualOrdersUntilTimestamp.
+// The virtual orders are not executed after orderExpiriesUntil, since the orders expiring after it are unknown.
+// https://github.com/FraxFinance/frax-solidity/blob/master/src/hardhat/contracts/Fraxswap/core/libraries/LongTermOrders.sol
+func (s *twammState) executeVirtualOrders(blockTimestamp int64, orderExpiriesUntil int64, fee *big.Int) {
+	if blockTimestamp > orderExpiriesUntil {
+		blockTimestamp = orderExpiriesUntil
+	}
+	if s.lastVirtualOrderTimestamp == 0 || blockTimestamp <= s.lastVirtualOrderTimestamp {
+		return
+	}
+
+	var executed int
+	for _, expiry := range s.orderExpiries {
+		if expiry.Timestamp > blockTimestamp {
+			break
+		}
+		executed += 1
+		if expiry.Timestamp <= s.lastVirtualOrderTimestamp {
+			continue
+		}
+
+		s.executeVirtualTradesAndOrderExpiries(expiry.Timestamp, fee)
+		s.token0Rate = new(big.Int).Sub(s.token0Rate, expiry.Token0RateEnding)
+		s.token1Rate = new(big.Int).Sub(s.token1Rate, expiry.Token1RateEnding)
+	}
+	s.orderExpiries = s.orderExpiries[executed:]
+
+	if s.lastVirtualOrderTimestamp != blockTimestamp {
+		s.executeVirtualTradesAndOrderExpiries(blockTimestamp, fee)
+	}
+}
+
+func (s *twammState) executeVirtualTradesAndOrderExpiries(blockTimestamp int64, fee *big.Int) {
+	var blockTimestampElapsed = big.NewInt(blockTimestamp - s.lastVirtualOrderTimestamp)
+	var token0SellAmount = new(big.Int).Div(new(big.Int).Mul(s.token0Rate, blockTimestampElapsed), SellRateAdditionalPrecision)
+	var token1SellAmount = new(big.Int).Div(new(big.Int).Mul(s.token1Rate, blockTimestampElapsed), SellRateAdditionalPrecision)
+
+	// the sold tokens are moved into the AMM and the bought tokens are moved out of it
+	token0Out, token1Out := computeVirtualBalances(s.reserve0, s.reserve1, token0SellAmount, token1SellAmount, fee)
+	s.reserve0 = new(big.Int).Sub(new(big.Int).Add(s.reserve0, token0SellAmount), token0Out)
+	s.reserve1 = new(big.Int).Sub(new(big.Int).Add(s.reserve1, token1SellAmount), token1Out)
+	s.lastVirtualOrderTimestamp = blockTimestamp
+}
+
+// computeVirtualBalances returns the amounts of token0 and token1 bought by the virtual trades
+func computeVirtualBalances(token0Start, token1Start, token0In, token1In, fee *big.Int) (*big.Int, *big.Int) {
+	// if no tokens are sold to the pool, we don't need to execute any orders
+	if token0In.Cmp(bignumber.Two) < 0 && token1In.Cmp(bignumber.Two) < 0 {
+		return bignumber.ZeroBI, bignumber.ZeroBI
+	}
+
+	// in the case where only one pool is selling, we just perform a normal swap
+	if token0In.Cmp(bignumber.Two) < 0 {
+		var token1InWithFee = new(big.Int).Mul(token1In, fee)
+		var token0Out = new(big.Int).Div(
+			new(big.Int).Mul(token0Start, token1InWithFee),
+			new(big.Int).Add(new(big.Int).Mul(token1Start, FeePrecision), token1InWithFee),
+		)
+		return token0Out, bignumber.ZeroBI
+	}
+	if token1In.Cmp(bignumber.Two) < 0 {
+		var token0InWithFee = new(big.Int).Mul(token0In, fee)
+		var token1Out = new(big.Int).Div(
+			new(big.Int).Mul(token1Start, token0InWithFee),
+			new(big.Int).Add(new(big.Int).Mul(token0Start, FeePrecision), token0InWithFee),
+		)
+		return bignumber.ZeroBI, token1Out
+	}
+
+	// when both pools sell, we use the TWAMM formula
+	var aIn = new(big.Int).Div(new(big.Int).Mul(token0In, fee), FeePrecision)
+	var bIn = new(big.Int).Div(new(big.Int).Mul(token1In, fee), FeePrecision)
+	var k = new(big.Int).Mul(token0Start, token1Start)
+	var ammEndToken1 = new(big.Int).Div(
+		new(big.Int).Mul(token0Start, new(big.Int).Add(token1Start, bIn)),
+		new(big.Int).Add(token0Start, aIn),
+	)
+	var ammEndToken0 = new(big.Int).Div(k, ammEndToken1)
+	var token0Out = new(big.Int).Sub(new(big.Int).Add(token0Start, aIn), ammEndToken0)
+	var token1Out = new(big.Int).Sub(new(big.Int).Add(token1Start, bIn), ammEndToken1)
+	return token0Out, token1Out
+}
@@ -10,6 +10,23 @@ type Extra struct {
 	Reserve0 *big.Int `json:"reserve0"`
 	Reserve1 *big.Int `json:"reserve1"`
 	Fee      *big.Int `json:"fee"`
+
+	// The TWAMM state is only set if the pool has long-term orders, Reserve0 and Reserve1 are then the reserves
+	// at LastVirtualOrderTimestamp, before the virtual orders are executed.
+	LastVirtualOrderTimestamp int64         `json:"lastVirtualOrderTimestamp,omitempty"`
+	OrderTimeInterval         int64         `json:"orderTimeInterval,omitempty"`
+	Token0Rate                *big.Int      `json:"token0Rate,omitempty"`
+	Token1Rate                *big.Int      `json:"token1Rate,omitempty"`
+	OrderExpiries             []OrderExpiry `json:"orderExpiries,omitempty"`
+	// OrderExpiriesUntil is the last timestamp whose order expirations are fetched
+	OrderExpiriesUntil int64 `json:"orderExpiriesUntil,omitempty"`
+}
+
+// OrderExpiry is the sales rates of the orders of each order pool which expire at Timestamp
+type OrderExpiry struct {
+	Timestamp        int64    `json:"timestamp"`
+	Token0RateEnding *big.Int `json:"token0RateEnding"`
+	Token1RateEnding *big.Int `json:"token1RateEnding"`
 }
 
 type ReserveAfterTwammOutput struct {
@@ -17,6 +34,29 @@ type ReserveAfterTwammOutput struct {
 	Reserve1 *big.Int
 }
 
+type TwammReservesOutput struct {
+	Reserve0           *big.Int
+	Reserve1           *big.Int
+	BlockTimestampLast uint32
+	TwammReserve0      *big.Int
+	TwammReserve1      *big.Int
+	Fee                *big.Int
+}
+
+type TwammStateOutput struct {
+	Token0Rate                *big.Int
+	Token1Rate                *big.Int
+	LastVirtualOrderTimestamp *big.Int
+	OrderTimeIntervalRtn      *big.Int
+	RewardFactorPool0         *big.Int
+	RewardFactorPool1         *big.Int
+}
+
+type TwammSalesRateEndingOutput struct {
+	OrderPool0SalesRateEnding *big.Int
+	OrderPool1SalesRateEnding *big.Int
+}
+
 type FeeOutput struct {
 	Fee *big.Int
 }
@@ -3,7 +3,7 @@ package fxdx
 import (
 	"math/big"
 
-	"github.com/KyberNetwork/blockchain-toolkit/integer"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx/vaultengine"
 )
 
 type FeeUtilsV2 struct {
@@ -67,36 +67,12 @@ func (f *FeeUtilsV2) getFeeBasisPoints(
 		return feeBps
 	}
 
-	initialAmount := f.Vault.USDFAmounts[token]
-	nextAmount := new(big.Int).Add(initialAmount, usdfDelta)
-	if !increment {
-		if usdfDelta.Cmp(initialAmount) > 0 {
-			nextAmount = integer.Zero()
-		} else {
-			nextAmount = new(big.Int).Sub(initialAmount, usdfDelta)
-		}
-	}
-
-	targetAmount := f.Vault.GetTargetUSDFAmount(token)
-	if targetAmount.Cmp(integer.Zero()) == 0 {
-		return feeBps
-	}
-
-	initialDiff := new(big.Int).Abs(new(big.Int).Sub(initialAmount, targetAmount))
-	nextDiff := new(big.Int).Abs(new(big.Int).Sub(nextAmount, targetAmount))
-
-	if nextDiff.Cmp(initialDiff) < 0 {
-		rebateBps := new(big.Int).Div(new(big.Int).Mul(taxBasisPoints, initialDiff), targetAmount)
-		if rebateBps.Cmp(feeBps) > 0 {
-			return integer.Zero()
-		}
-		return new(big.Int).Sub(feeBps, rebateBps)
-	}
-
-	averageDiff := new(big.Int).Div(new(big.Int).Add(initialDiff, nextDiff), integer.Two())
-	if averageDiff.Cmp(targetAmount) > 0 {
-		averageDiff = targetAmount
-	}
-	taxBps := new(big.Int).Div(new(big.Int).Mul(taxBasisPoints, averageDiff), targetAmount)
-	return new(big.Int).Add(feeBps, taxBps)
+	return vaultengine.GetFeeBasisPoints(
+		f.Vault.USDFAmounts[token],
+		f.Vault.GetTargetUSDFAmount(token),
+		usdfDelta,
+		feeBps,
+		taxBasisPoints,
+		increment,
+	)
 }
@@ -5,9 +5,10 @@ import (
 	"math/big"
 	"strings"
 
-	"github.com/KyberNetwork/blockchain-toolkit/integer"
+	"github.com/KyberNetwork/logger"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx/vaultengine"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 )
 
@@ -18,9 +19,9 @@ type Gas struct {
 type PoolSimulator struct {
 	pool.Pool
 
-	vault    *Vault
-	feeUtils *FeeUtilsV2
-	gas      Gas
+	vault  *Vault
+	engine *vaultengine.Engine
+	gas    Gas
 }
 
 func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
@@ -49,9 +50,21 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 		Pool: pool.Pool{
 			Info: info,
 		},
-		vault:    vault,
-		feeUtils: feeUtils,
-		gas:      DefaultGas,
+		vault: vault,
+		engine: vaultengine.NewEngine(
+			&vaultengine.Vault{
+				IsSwapEnabled:   vault.IsSwapEnabled,
+				UsdgAddress:     vault.USDF.Address,
+				PoolAmounts:     vault.PoolAmounts,
+				BufferAmounts:   vault.BufferAmounts,
+				ReservedAmounts: vault.ReservedAmounts,
+				UsdgAmounts:     vault.USDFAmounts,
+				MaxUsdgAmounts:  vault.MaxUSDFAmounts,
+			},
+			vault,
+			feeUtils,
+		),
+		gas: DefaultGas,
 	}, nil
 }
 
@@ -80,20 +93,18 @@ func (p *PoolSimulator) CalcAmountOut(
 	}, nil
 }
 
+// UpdateBalance replays the swap on the vault, updating its usdf amounts and pool amounts.
 func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
-	input, output, fee := params.TokenAmountIn, params.TokenAmountOut, params.Fee
-	priceIn, err := p.vault.GetMinPrice(input.Token)
-	if err != nil {
+	input, output := params.TokenAmountIn, params.TokenAmountOut
+	if _, err := p.engine.Swap(input.Token, output.Token, input.Amount); err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Info.Address,
+			"tokenIn":     input.Token,
+			"tokenOut":    output.Token,
+			"error":       err,
+		}).Errorf("failed to update balance")
 		return
 	}
-
-	usdfAmount := new(big.Int).Div(new(big.Int).Mul(input.Amount, priceIn), PricePrecision)
-	usdfAmount = p.vault.AdjustForDecimals(usdfAmount, input.Token, p.vault.USDF.Address)
-
-	p.vault.IncreaseUSDFAmount(input.Token, usdfAmount)
-	p.vault.DecreaseUSDFAmount(output.Token, usdfAmount)
-	p.vault.IncreasePoolAmount(input.Token, input.Amount)
-	p.vault.DecreasePoolAmount(output.Token, new(big.Int).Add(output.Amount, fee.Amount))
 }
 
 func (p *PoolSimulator) CanSwapFrom(address string) []string { return p.CanSwapTo(address) }
@@ -129,101 +140,10 @@ func (p *PoolSimulator) CanSwapTo(address string) []string {
 func (p *PoolSimulator) GetMetaInfo(_ string, _ string) interface{} { return nil }
 
 func (p *PoolSimulator) getAmountOut(tokenIn string, tokenOut string, amountIn *big.Int) (*big.Int, *big.Int, error) {
-	if !p.vault.IsSwapEnabled {
-		return nil, nil, ErrVaultSwapsNotEnabled
-	}
-
-	priceIn, err := p.vault.GetMinPrice(tokenIn)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	priceOut, err := p.vault.GetMaxPrice(tokenOut)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	amountOut := new(big.Int).Div(new(big.Int).Mul(amountIn, priceIn), priceOut)
-	amountOut = p.vault.AdjustForDecimals(amountOut, tokenIn, tokenOut)
-
-	usdfAmount := new(big.Int).Div(new(big.Int).Mul(amountIn, priceIn), PricePrecision)
-	usdfAmount = p.vault.AdjustForDecimals(usdfAmount, tokenIn, p.vault.USDF.Address)
-
-	// in smart contract, this validation is implemented inside _increaseUsdfAmount method
-	if err = p.validateMaxUsdfExceed(tokenIn, usdfAmount); err != nil {
-		return nil, nil, err
-	}
-
-	// in smart contract, this validation is implemented inside _decreasePoolAmount method
-	if err = p.validateMinPoolAmount(tokenOut, amountOut); err != nil {
-		return nil, nil, err
-	}
-
-	// in smart contract, this validation is implemented inside _validateBufferAmount method
-	if err = p.validateBufferAmount(tokenOut, amountOut); err != nil {
-		return nil, nil, err
-	}
-
-	feeBasisPoints, err := p.feeUtils.GetSwapFeeBasisPoints(tokenIn, tokenOut, usdfAmount)
+	result, err := p.engine.GetAmountOut(tokenIn, tokenOut, amountIn)
 	if err != nil {
 		return nil, nil, err
 	}
-	amountOutAfterFees := new(big.Int).Div(
-		new(big.Int).Mul(
-			amountOut,
-			new(big.Int).Sub(BasisPointsDivisor, feeBasisPoints),
-		),
-		BasisPointsDivisor,
-	)
-
-	feeAmount := new(big.Int).Sub(amountOut, amountOutAfterFees)
-
-	return amountOutAfterFees, feeAmount, nil
-}
-
-func (p *PoolSimulator) validateMaxUsdfExceed(token string, amount *big.Int) error {
-	currentUsdfAmount := p.vault.USDFAmounts[token]
-	newUsdfAmount := new(big.Int).Add(currentUsdfAmount, amount)
-
-	maxUsdfAmount := p.vault.MaxUSDFAmounts[token]
-
-	if maxUsdfAmount.Cmp(integer.Zero()) == 0 {
-		return nil
-	}
-
-	if newUsdfAmount.Cmp(maxUsdfAmount) < 0 {
-		return nil
-	}
-
-	return ErrVaultMaxUsdfExceeded
-}
-
-func (p *PoolSimulator) validateMinPoolAmount(token string, amount *big.Int) error {
-	currentPoolAmount := p.vault.PoolAmounts[token]
-
-	if currentPoolAmount.Cmp(amount) < 0 {
-		return ErrVaultPoolAmountExceeded
-	}
-
-	newPoolAmount := new(big.Int).Sub(currentPoolAmount, amount)
-	reservedAmount := p.vault.ReservedAmounts[token]
-
-	if reservedAmount.Cmp(newPoolAmount) > 0 {
-		return ErrVaultReserveExceedsPool
-	}
-
-	return nil
-}
-
-func (p *PoolSimulator) validateBufferAmount(token string, amount *big.Int) error {
-	currentPoolAmount := p.vault.PoolAmounts[token]
-	newPoolAmount := new(big.Int).Sub(currentPoolAmount, amount)
-
-	bufferAmount := p.vault.BufferAmounts[token]
-
-	if newPoolAmount.Cmp(bufferAmount) < 0 {
-		return ErrVaultPoolAmountLessThanBufferAmount
-	}
 
-	return nil
+	return result.AmountOutAfterFees, result.FeeAmount, nil
 }
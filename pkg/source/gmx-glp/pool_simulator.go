@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx/vaultengine"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 )
 
@@ -19,6 +20,7 @@ type PoolSimulator struct {
 	pool.Pool
 	vault           *Vault
 	vaultUtils      *VaultUtils
+	engine          *vaultengine.Engine
 	glpManager      *GlpManager
 	yearnTokenVault *YearnTokenVault
 	gas             Gas
@@ -49,12 +51,35 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 		},
 		vault:           extra.Vault,
 		vaultUtils:      NewVaultUtils(extra.Vault),
+		engine:          newVaultEngine(extra.Vault),
 		glpManager:      extra.GlpManager,
 		yearnTokenVault: extra.YearnTokenVault,
 		gas:             DefaultGas,
 	}, nil
 }
 
+// newVaultEngine binds the vault engine to the state maps of the vault, so the dynamic fees of VaultUtils follow the
+// amounts updated by the engine. Only its validations and amount updates are used, as the pool never swaps.
+func newVaultEngine(vault *Vault) *vaultengine.Engine {
+	vaultUtils := NewVaultUtils(vault)
+
+	return vaultengine.NewEngine(
+		&vaultengine.Vault{
+			IsSwapEnabled:   vault.IsSwapEnabled,
+			UsdgAddress:     vault.USDG.Address,
+			PoolAmounts:     vault.PoolAmounts,
+			BufferAmounts:   vault.BufferAmounts,
+			ReservedAmounts: vault.ReservedAmounts,
+			UsdgAmounts:     vault.USDGAmounts,
+			MaxUsdgAmounts:  vault.MaxUSDGAmounts,
+		},
+		vault,
+		vaultengine.FeeCalculatorFunc(func(tokenIn string, tokenOut string, usdgAmount *big.Int) (*big.Int, error) {
+			return vaultUtils.GetSwapFeeBasisPoints(tokenIn, tokenOut, usdgAmount), nil
+		}),
+	)
+}
+
 func (p *PoolSimulator) CalcAmountOut(
 	tokenAmountIn pool.TokenAmount,
 	tokenOut string,
@@ -110,8 +135,9 @@ func (p *PoolSimulator) CalcAmountOut(
 	}, nil
 }
 
-// UpdateBalance update UsdgAmount only
-// https://github.com/gmx-io/gmx-contracts/blob/787d767e033c411f6d083f2725fb54b7fa956f7e/contracts/core/Vault.sol#L547-L548
+// UpdateBalance applies buyUSDG or sellUSDG to the vault: usdg amounts, pool amounts and the USDG supply, which
+// drives the target amounts of the dynamic fees.
+// https://github.com/gmx-io/gmx-contracts/blob/787d767e033c411f6d083f2725fb54b7fa956f7e/contracts/core/Vault.sol#L462-L519
 func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
 	swapInfo, ok := params.SwapInfo.(gmxGlpSwapInfo)
 	if !ok {
@@ -121,11 +147,16 @@ func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
 
 	switch swapInfo.calcAmountOutType {
 	case calcAmountOutTypeStake:
-		p.vault.IncreaseUSDGAmount(params.TokenAmountIn.Token, swapInfo.mintAmount)
-		p.vault.IncreasePoolAmount(params.TokenAmountIn.Token, swapInfo.amountAfterFees)
+		p.engine.IncreaseUsdgAmount(params.TokenAmountIn.Token, swapInfo.mintAmount)
+		p.engine.IncreasePoolAmount(params.TokenAmountIn.Token, swapInfo.amountAfterFees)
+		p.vault.USDG.TotalSupply = new(big.Int).Add(p.vault.USDG.TotalSupply, swapInfo.mintAmount)
 	case calcAmountOutTypeUnStake:
-		p.vault.DecreaseUSDGAmount(params.TokenAmountOut.Token, swapInfo.usdgAmount)
-		p.vault.DecreasePoolAmount(params.TokenAmountOut.Token, swapInfo.redemptionAmount)
+		p.engine.DecreaseUsdgAmount(params.TokenAmountOut.Token, swapInfo.usdgAmount)
+		p.engine.DecreasePoolAmount(params.TokenAmountOut.Token, swapInfo.redemptionAmount)
+		p.vault.USDG.TotalSupply = new(big.Int).Sub(p.vault.USDG.TotalSupply, swapInfo.usdgAmount)
+		if p.vault.USDG.TotalSupply.Sign() < 0 {
+			p.vault.USDG.TotalSupply = new(big.Int)
+		}
 	}
 }
 
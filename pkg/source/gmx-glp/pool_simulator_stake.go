@@ -103,7 +103,7 @@ func (p *PoolSimulator) BuyUSDG(token string, tokenAmount *big.Int) (*big.Int, e
 	p.swapInfo.mintAmount = new(big.Int).Set(mintAmount)
 	p.swapInfo.amountAfterFees = new(big.Int).Set(amountAfterFees)
 	//p.vault.IncreaseUSDGAmount(tokenIn, mintAmount)
-	if err = p.validateMaxUsdgExceed(token, mintAmount); err != nil {
+	if err = p.engine.ValidateMaxUsdgAmount(token, mintAmount); err != nil {
 		return nil, err
 	}
 	//p.vault.IncreasePoolAmount(tokenIn, amountAfterFees)
@@ -0,0 +1,32 @@
+package gmxv2
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+var (
+	dataStoreABI abi.ABI
+	priceFeedABI abi.ABI
+	readerABI    abi.ABI
+)
+
+func init() {
+	builder := []struct {
+		ABI  *abi.ABI
+		data []byte
+	}{
+		{&dataStoreABI, dataStoreJson},
+		{&priceFeedABI, priceFeedJson},
+		{&readerABI, readerJson},
+	}
+
+	for _, b := range builder {
+		var err error
+		*b.ABI, err = abi.JSON(bytes.NewReader(b.data))
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package gmxv2
+
+type Config struct {
+	DexID            string `json:"-"`
+	DataStoreAddress string `json:"dataStoreAddress"`
+	ReaderAddress    string `json:"readerAddress"`
+}
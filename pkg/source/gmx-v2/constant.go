@@ -0,0 +1,33 @@
+package gmxv2
+
+import (
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const DexTypeGmxV2 = "gmx-v2"
+
+const (
+	dataStoreMethodGetAddressCount = "getAddressCount"
+	dataStoreMethodGetAddress      = "getAddress"
+	dataStoreMethodGetBool         = "getBool"
+	dataStoreMethodGetBytes32      = "getBytes32"
+	dataStoreMethodGetUint         = "getUint"
+
+	readerMethodGetMarkets = "getMarkets"
+
+	priceFeedMethodLatestRoundData = "latestRoundData"
+)
+
+// maxSwapPathLength is the number of markets a simulated swap path may go through. The contracts accept longer paths,
+// but every extra market adds a full swap of gas for a rarely better rate.
+const maxSwapPathLength = 2
+
+var (
+	DefaultGas = Gas{Swap: 400000, SwapPathMarket: 150000}
+
+	// FloatPrecision is the precision of usd values and factors in the contracts
+	FloatPrecision = bignumber.TenPowInt(30)
+	// floatToWeiDivisor converts a FloatPrecision value to the 18 decimals of PRBMath
+	floatToWeiDivisor = bignumber.TenPowInt(12)
+	weiPrecision      = bignumber.TenPowInt(18)
+)
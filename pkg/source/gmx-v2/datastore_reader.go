@@ -0,0 +1,211 @@
+package gmxv2
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DataStoreReader reads the markets of a GMX v2 DataStore and the state their swaps need.
+type DataStoreReader struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+	log          logger.Logger
+}
+
+func NewDataStoreReader(config *Config, ethrpcClient *ethrpc.Client) *DataStoreReader {
+	return &DataStoreReader{
+		config:       config,
+		ethrpcClient: ethrpcClient,
+		log: logger.WithFields(logger.Fields{
+			"liquiditySource": DexTypeGmxV2,
+			"reader":          "DataStoreReader",
+		}),
+	}
+}
+
+func (r *DataStoreReader) Read(ctx context.Context) (*Extra, error) {
+	markets, err := r.readMarkets(ctx)
+	if err != nil {
+		r.log.Errorf("error when read markets: %s", err)
+		return nil, err
+	}
+
+	swapFeeReceiverFactor, err := r.readMarketStates(ctx, markets)
+	if err != nil {
+		r.log.Errorf("error when read market states: %s", err)
+		return nil, err
+	}
+
+	virtualInventories, err := r.readVirtualInventories(ctx, markets)
+	if err != nil {
+		r.log.Errorf("error when read virtual inventories: %s", err)
+		return nil, err
+	}
+
+	prices, err := NewPriceReader(r.config, r.ethrpcClient).Read(ctx, marketTokens(markets))
+	if err != nil {
+		r.log.Errorf("error when read prices: %s", err)
+		return nil, err
+	}
+
+	return &Extra{
+		Markets:               markets,
+		VirtualInventories:    virtualInventories,
+		Prices:                prices,
+		SwapFeeReceiverFactor: swapFeeReceiverFactor,
+	}, nil
+}
+
+func (r *DataStoreReader) readMarkets(ctx context.Context) ([]*Market, error) {
+	var marketCount *big.Int
+	if _, err := r.ethrpcClient.NewRequest().SetContext(ctx).AddCall(&ethrpc.Call{
+		ABI:    dataStoreABI,
+		Target: r.config.DataStoreAddress,
+		Method: dataStoreMethodGetAddressCount,
+		Params: []interface{}{keyMarketList},
+	}, []interface{}{&marketCount}).Call(); err != nil {
+		return nil, err
+	}
+
+	var marketProps []MarketProps
+	if _, err := r.ethrpcClient.NewRequest().SetContext(ctx).AddCall(&ethrpc.Call{
+		ABI:    readerABI,
+		Target: r.config.ReaderAddress,
+		Method: readerMethodGetMarkets,
+		Params: []interface{}{common.HexToAddress(r.config.DataStoreAddress), big.NewInt(0), marketCount},
+	}, []interface{}{&marketProps}).Call(); err != nil {
+		return nil, err
+	}
+
+	markets := make([]*Market, 0, len(marketProps))
+	for _, props := range marketProps {
+		markets = append(markets, &Market{
+			MarketToken: strings.ToLower(props.MarketToken.Hex()),
+			IndexToken:  strings.ToLower(props.IndexToken.Hex()),
+			LongToken:   strings.ToLower(props.LongToken.Hex()),
+			ShortToken:  strings.ToLower(props.ShortToken.Hex()),
+		})
+	}
+
+	return markets, nil
+}
+
+// readMarketStates fills the pool amounts, impact and fee factors of the markets and returns the swap fee receiver
+// factor, which is global.
+func (r *DataStoreReader) readMarketStates(ctx context.Context, markets []*Market) (*big.Int, error) {
+	var swapFeeReceiverFactor *big.Int
+
+	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
+	rpcRequest.AddCall(r.getUintCall(keySwapFeeReceiverFactor), []interface{}{&swapFeeReceiverFactor})
+
+	virtualMarketIDs := make([]common.Hash, len(markets))
+	for i, m := range markets {
+		m.LongPoolAmount, m.ShortPoolAmount = new(big.Int), new(big.Int)
+		m.MaxLongPoolAmount, m.MaxShortPoolAmount = new(big.Int), new(big.Int)
+		m.LongSwapImpactPoolAmount, m.ShortSwapImpactPoolAmount = new(big.Int), new(big.Int)
+		m.PositiveSwapImpactFactor, m.NegativeSwapImpactFactor = new(big.Int), new(big.Int)
+		m.SwapImpactExponentFactor = new(big.Int)
+		m.PositiveSwapFeeFactor, m.NegativeSwapFeeFactor = new(big.Int), new(big.Int)
+
+		rpcRequest.AddCall(&ethrpc.Call{
+			ABI:    dataStoreABI,
+			Target: r.config.DataStoreAddress,
+			Method: dataStoreMethodGetBool,
+			Params: []interface{}{isMarketDisabledKey(m.MarketToken)},
+		}, []interface{}{&m.IsDisabled})
+		rpcRequest.AddCall(&ethrpc.Call{
+			ABI:    dataStoreABI,
+			Target: r.config.DataStoreAddress,
+			Method: dataStoreMethodGetBytes32,
+			Params: []interface{}{virtualMarketIDKey(m.MarketToken)},
+		}, []interface{}{&virtualMarketIDs[i]})
+
+		rpcRequest.
+			AddCall(r.getUintCall(poolAmountKey(m.MarketToken, m.LongToken)), []interface{}{&m.LongPoolAmount}).
+			AddCall(r.getUintCall(poolAmountKey(m.MarketToken, m.ShortToken)), []interface{}{&m.ShortPoolAmount}).
+			AddCall(r.getUintCall(maxPoolAmountKey(m.MarketToken, m.LongToken)), []interface{}{&m.MaxLongPoolAmount}).
+			AddCall(r.getUintCall(maxPoolAmountKey(m.MarketToken, m.ShortToken)), []interface{}{&m.MaxShortPoolAmount}).
+			AddCall(r.getUintCall(swapImpactPoolAmountKey(m.MarketToken, m.LongToken)), []interface{}{&m.LongSwapImpactPoolAmount}).
+			AddCall(r.getUintCall(swapImpactPoolAmountKey(m.MarketToken, m.ShortToken)), []interface{}{&m.ShortSwapImpactPoolAmount}).
+			AddCall(r.getUintCall(swapImpactFactorKey(m.MarketToken, true)), []interface{}{&m.PositiveSwapImpactFactor}).
+			AddCall(r.getUintCall(swapImpactFactorKey(m.MarketToken, false)), []interface{}{&m.NegativeSwapImpactFactor}).
+			AddCall(r.getUintCall(swapImpactExponentFactorKey(m.MarketToken)), []interface{}{&m.SwapImpactExponentFactor}).
+			AddCall(r.getUintCall(swapFeeFactorKey(m.MarketToken, true)), []interface{}{&m.PositiveSwapFeeFactor}).
+			AddCall(r.getUintCall(swapFeeFactorKey(m.MarketToken, false)), []interface{}{&m.NegativeSwapFeeFactor})
+	}
+
+	if _, err := rpcRequest.Aggregate(); err != nil {
+		return nil, err
+	}
+
+	for i, market := range markets {
+		if virtualMarketIDs[i] != (common.Hash{}) {
+			market.VirtualMarketID = virtualMarketIDs[i].Hex()
+		}
+	}
+
+	return swapFeeReceiverFactor, nil
+}
+
+func (r *DataStoreReader) readVirtualInventories(ctx context.Context, markets []*Market) (map[string]*VirtualInventory, error) {
+	virtualInventories := make(map[string]*VirtualInventory)
+
+	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
+	for _, market := range markets {
+		if market.VirtualMarketID == "" {
+			continue
+		}
+		if _, ok := virtualInventories[market.VirtualMarketID]; ok {
+			continue
+		}
+
+		inventory := &VirtualInventory{LongAmount: new(big.Int), ShortAmount: new(big.Int)}
+		virtualInventories[market.VirtualMarketID] = inventory
+
+		virtualMarketID := common.HexToHash(market.VirtualMarketID)
+		rpcRequest.
+			AddCall(r.getUintCall(virtualInventoryForSwapsKey(virtualMarketID, true)), []interface{}{&inventory.LongAmount}).
+			AddCall(r.getUintCall(virtualInventoryForSwapsKey(virtualMarketID, false)), []interface{}{&inventory.ShortAmount})
+	}
+
+	if len(virtualInventories) == 0 {
+		return virtualInventories, nil
+	}
+
+	if _, err := rpcRequest.Aggregate(); err != nil {
+		return nil, err
+	}
+
+	return virtualInventories, nil
+}
+
+func (r *DataStoreReader) getUintCall(key common.Hash) *ethrpc.Call {
+	return &ethrpc.Call{
+		ABI:    dataStoreABI,
+		Target: r.config.DataStoreAddress,
+		Method: dataStoreMethodGetUint,
+		Params: []interface{}{key},
+	}
+}
+
+// marketTokens returns the distinct long and short tokens of the markets, in the order they first appear
+func marketTokens(markets []*Market) []string {
+	seen := make(map[string]struct{})
+	tokens := make([]string, 0, len(markets))
+	for _, market := range markets {
+		for _, token := range []string{market.LongToken, market.ShortToken} {
+			if _, ok := seen[token]; ok {
+				continue
+			}
+			seen[token] = struct{}{}
+			tokens = append(tokens, token)
+		}
+	}
+
+	return tokens
+}
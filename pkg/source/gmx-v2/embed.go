@@ -0,0 +1,12 @@
+package gmxv2
+
+import _ "embed"
+
+//go:embed abis/DataStore.json
+var dataStoreJson []byte
+
+//go:embed abis/PriceFeed.json
+var priceFeedJson []byte
+
+//go:embed abis/Reader.json
+var readerJson []byte
@@ -0,0 +1,17 @@
+package gmxv2
+
+import "errors"
+
+var (
+	ErrMarketDisabled           = errors.New("market is disabled")
+	ErrInvalidTokenIn           = errors.New("invalid tokenIn for market")
+	ErrPriceNotFound            = errors.New("price not found")
+	ErrNoSwapPath               = errors.New("no swap path")
+	ErrUsdDeltaExceedsPoolValue = errors.New("usd delta exceeds pool value")
+	ErrInsufficientPoolAmount   = errors.New("insufficient pool amount")
+	ErrMaxPoolAmountExceeded    = errors.New("max pool amount exceeded")
+	ErrPriceImpactExceedsAmount = errors.New("swap price impact exceeds amount in")
+	ErrInvalidSwapPath          = errors.New("invalid swap path")
+	ErrLog2InputTooSmall        = errors.New("log2 input is less than 1")
+	ErrExp2InputTooBig          = errors.New("exp2 input is too big")
+)
@@ -0,0 +1,110 @@
+package gmxv2
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DataStore keys, see https://github.com/gmx-io/gmx-synthetics/blob/main/contracts/data/Keys.sol
+var (
+	keyMarketList               = hashString("MARKET_LIST")
+	keyIsMarketDisabled         = hashString("IS_MARKET_DISABLED")
+	keyPoolAmount               = hashString("POOL_AMOUNT")
+	keyMaxPoolAmount            = hashString("MAX_POOL_AMOUNT")
+	keySwapImpactPoolAmount     = hashString("SWAP_IMPACT_POOL_AMOUNT")
+	keySwapImpactFactor         = hashString("SWAP_IMPACT_FACTOR")
+	keySwapImpactExponentFactor = hashString("SWAP_IMPACT_EXPONENT_FACTOR")
+	keySwapFeeFactor            = hashString("SWAP_FEE_FACTOR")
+	keySwapFeeReceiverFactor    = hashString("SWAP_FEE_RECEIVER_FACTOR")
+	keyVirtualMarketID          = hashString("VIRTUAL_MARKET_ID")
+	keyVirtualInventoryForSwaps = hashString("VIRTUAL_INVENTORY_FOR_SWAPS")
+	keyPriceFeed                = hashString("PRICE_FEED")
+	keyPriceFeedMultiplier      = hashString("PRICE_FEED_MULTIPLIER")
+	keyStablePrice              = hashString("STABLE_PRICE")
+)
+
+var (
+	abiTypeString, _  = abi.NewType("string", "", nil)
+	abiTypeBytes32, _ = abi.NewType("bytes32", "", nil)
+	abiTypeAddress, _ = abi.NewType("address", "", nil)
+	abiTypeBool, _    = abi.NewType("bool", "", nil)
+)
+
+func isMarketDisabledKey(market string) common.Hash {
+	return hashData(abi.Arguments{{Type: abiTypeBytes32}, {Type: abiTypeAddress}},
+		keyIsMarketDisabled, common.HexToAddress(market))
+}
+
+func poolAmountKey(market, token string) common.Hash {
+	return marketTokenKey(keyPoolAmount, market, token)
+}
+
+func maxPoolAmountKey(market, token string) common.Hash {
+	return marketTokenKey(keyMaxPoolAmount, market, token)
+}
+
+func swapImpactPoolAmountKey(market, token string) common.Hash {
+	return marketTokenKey(keySwapImpactPoolAmount, market, token)
+}
+
+func swapImpactFactorKey(market string, isPositive bool) common.Hash {
+	return hashData(abi.Arguments{{Type: abiTypeBytes32}, {Type: abiTypeAddress}, {Type: abiTypeBool}},
+		keySwapImpactFactor, common.HexToAddress(market), isPositive)
+}
+
+func swapImpactExponentFactorKey(market string) common.Hash {
+	return hashData(abi.Arguments{{Type: abiTypeBytes32}, {Type: abiTypeAddress}},
+		keySwapImpactExponentFactor, common.HexToAddress(market))
+}
+
+func swapFeeFactorKey(market string, forPositiveImpact bool) common.Hash {
+	return hashData(abi.Arguments{{Type: abiTypeBytes32}, {Type: abiTypeAddress}, {Type: abiTypeBool}},
+		keySwapFeeFactor, common.HexToAddress(market), forPositiveImpact)
+}
+
+func virtualMarketIDKey(market string) common.Hash {
+	return hashData(abi.Arguments{{Type: abiTypeBytes32}, {Type: abiTypeAddress}},
+		keyVirtualMarketID, common.HexToAddress(market))
+}
+
+func virtualInventoryForSwapsKey(virtualMarketID common.Hash, isLongToken bool) common.Hash {
+	return hashData(abi.Arguments{{Type: abiTypeBytes32}, {Type: abiTypeBytes32}, {Type: abiTypeBool}},
+		keyVirtualInventoryForSwaps, virtualMarketID, isLongToken)
+}
+
+func priceFeedKey(token string) common.Hash {
+	return tokenKey(keyPriceFeed, token)
+}
+
+func priceFeedMultiplierKey(token string) common.Hash {
+	return tokenKey(keyPriceFeedMultiplier, token)
+}
+
+func stablePriceKey(token string) common.Hash {
+	return tokenKey(keyStablePrice, token)
+}
+
+func marketTokenKey(key common.Hash, market, token string) common.Hash {
+	return hashData(abi.Arguments{{Type: abiTypeBytes32}, {Type: abiTypeAddress}, {Type: abiTypeAddress}},
+		key, common.HexToAddress(market), common.HexToAddress(token))
+}
+
+func tokenKey(key common.Hash, token string) common.Hash {
+	return hashData(abi.Arguments{{Type: abiTypeBytes32}, {Type: abiTypeAddress}}, key, common.HexToAddress(token))
+}
+
+// hashString is keccak256(abi.encode(value))
+func hashString(value string) common.Hash {
+	return hashData(abi.Arguments{{Type: abiTypeString}}, value)
+}
+
+// hashData is keccak256(abi.encode(values...))
+func hashData(arguments abi.Arguments, values ...interface{}) common.Hash {
+	data, err := arguments.Pack(values...)
+	if err != nil {
+		panic(err)
+	}
+
+	return crypto.Keccak256Hash(data)
+}
@@ -0,0 +1,449 @@
+package gmxv2
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/KyberNetwork/logger"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// PoolSimulator simulates swaps of all markets of a GMX v2 DataStore, along swap paths of up to maxSwapPathLength
+// markets.
+type PoolSimulator struct {
+	pool.Pool
+
+	markets               map[string]*Market
+	marketsByToken        map[string][]*Market
+	virtualInventories    map[string]*VirtualInventory
+	prices                map[string]*Price
+	swapFeeReceiverFactor *big.Int
+	gas                   Gas
+}
+
+// hopResult is the result of a swap through a single market
+type hopResult struct {
+	market            *Market
+	tokenIn           string
+	tokenOut          string
+	amountOut         *big.Int
+	feeAmount         *big.Int
+	poolAmountIn      *big.Int
+	poolAmountOut     *big.Int
+	impactPoolAmount  *big.Int
+	hasPositiveImpact bool
+}
+
+func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
+	var extra Extra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]string, 0, len(entityPool.Tokens))
+	for _, poolToken := range entityPool.Tokens {
+		tokens = append(tokens, poolToken.Address)
+	}
+
+	reserves := make([]*big.Int, 0, len(entityPool.Reserves))
+	for _, reserve := range entityPool.Reserves {
+		reserves = append(reserves, bignumber.NewBig10(reserve))
+	}
+
+	markets := make(map[string]*Market, len(extra.Markets))
+	marketsByToken := make(map[string][]*Market)
+	for _, market := range extra.Markets {
+		if market.IsDisabled || market.LongToken == market.ShortToken {
+			continue
+		}
+		markets[market.MarketToken] = market
+		marketsByToken[market.LongToken] = append(marketsByToken[market.LongToken], market)
+		marketsByToken[market.ShortToken] = append(marketsByToken[market.ShortToken], market)
+	}
+
+	swapFeeReceiverFactor := extra.SwapFeeReceiverFactor
+	if swapFeeReceiverFactor == nil {
+		swapFeeReceiverFactor = new(big.Int)
+	}
+
+	return &PoolSimulator{
+		Pool: pool.Pool{
+			Info: pool.PoolInfo{
+				Address:  entityPool.Address,
+				Exchange: entityPool.Exchange,
+				Type:     entityPool.Type,
+				Tokens:   tokens,
+				Reserves: reserves,
+			},
+		},
+		markets:               markets,
+		marketsByToken:        marketsByToken,
+		virtualInventories:    extra.VirtualInventories,
+		prices:                extra.Prices,
+		swapFeeReceiverFactor: swapFeeReceiverFactor,
+		gas:                   DefaultGas,
+	}, nil
+}
+
+func (p *PoolSimulator) CalcAmountOut(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	markets := p.getSwapPath(tokenAmountIn.Token, tokenOut)
+	if markets == nil {
+		return &pool.CalcAmountOutResult{}, ErrNoSwapPath
+	}
+
+	hops, err := p.swapAlongPath(markets, tokenAmountIn.Token, tokenAmountIn.Amount, false)
+	if err != nil {
+		return &pool.CalcAmountOutResult{}, err
+	}
+
+	swapPath := make([]string, 0, len(hops))
+	for _, hop := range hops {
+		swapPath = append(swapPath, hop.market.MarketToken)
+	}
+
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{
+			Token:  tokenOut,
+			Amount: hops[len(hops)-1].amountOut,
+		},
+		Fee: &pool.TokenAmount{
+			Token:  tokenAmountIn.Token,
+			Amount: hops[0].feeAmount,
+		},
+		Gas:      p.gas.Swap + p.gas.SwapPathMarket*int64(len(hops)-1),
+		SwapInfo: SwapInfo{SwapPath: swapPath},
+	}, nil
+}
+
+// UpdateBalance replays the swap path on the markets: pool amounts, swap impact pools and virtual inventories.
+func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	swapInfo, ok := params.SwapInfo.(SwapInfo)
+	if !ok {
+		logger.Warnf("failed to UpdateBalance for gmx-v2 pool %v, wrong swapInfo type", p.Info.Address)
+		return
+	}
+
+	markets := make([]*Market, 0, len(swapInfo.SwapPath))
+	for _, marketToken := range swapInfo.SwapPath {
+		market, ok := p.markets[marketToken]
+		if !ok {
+			logger.Warnf("failed to UpdateBalance for gmx-v2 pool %v, unknown market %v", p.Info.Address, marketToken)
+			return
+		}
+		markets = append(markets, market)
+	}
+
+	if _, err := p.swapAlongPath(markets, params.TokenAmountIn.Token, params.TokenAmountIn.Amount, true); err != nil {
+		logger.Warnf("failed to UpdateBalance for gmx-v2 pool %v: %v", p.Info.Address, err)
+	}
+}
+
+func (p *PoolSimulator) CanSwapFrom(address string) []string { return p.CanSwapTo(address) }
+
+// CanSwapTo returns the tokens connected to address by a swap path, swap paths are symmetric so it is also the tokens
+// address can be swapped to.
+func (p *PoolSimulator) CanSwapTo(address string) []string {
+	reachable := make(map[string]struct{})
+	frontier := []string{address}
+	for i := 0; i < maxSwapPathLength; i++ {
+		var next []string
+		for _, token := range frontier {
+			for _, market := range p.marketsByToken[token] {
+				otherToken := market.LongToken
+				if otherToken == token {
+					otherToken = market.ShortToken
+				}
+				if _, ok := reachable[otherToken]; ok || otherToken == address {
+					continue
+				}
+				reachable[otherToken] = struct{}{}
+				next = append(next, otherToken)
+			}
+		}
+		frontier = next
+	}
+
+	tokens := make([]string, 0, len(reachable))
+	for _, token := range p.Info.Tokens {
+		if _, ok := reachable[token]; ok {
+			tokens = append(tokens, token)
+		}
+	}
+
+	return tokens
+}
+
+// GetMetaInfo returns the swap path which CalcAmountOut quotes from tokenIn to tokenOut.
+func (p *PoolSimulator) GetMetaInfo(tokenIn string, tokenOut string) interface{} {
+	markets := p.getSwapPath(tokenIn, tokenOut)
+	swapPath := make([]string, 0, len(markets))
+	for _, market := range markets {
+		swapPath = append(swapPath, market.MarketToken)
+	}
+
+	return Meta{SwapPath: swapPath}
+}
+
+// getSwapPath picks the swap path from tokenIn to tokenOut. It does not depend on the amount, so that the path
+// returned by GetMetaInfo is the one quoted: the fewest markets, then the most tokenOut in the pool of the last one.
+func (p *PoolSimulator) getSwapPath(tokenIn, tokenOut string) []*Market {
+	var best []*Market
+	for _, swapPath := range p.findSwapPaths(tokenIn, tokenOut) {
+		if best == nil || len(swapPath) < len(best) ||
+			len(swapPath) == len(best) && outPoolAmount(swapPath, tokenOut).Cmp(outPoolAmount(best, tokenOut)) > 0 {
+			best = swapPath
+		}
+	}
+
+	return best
+}
+
+// outPoolAmount is the pool amount of tokenOut in the last market of the swap path
+func outPoolAmount(swapPath []*Market, tokenOut string) *big.Int {
+	market := swapPath[len(swapPath)-1]
+	return market.poolAmount(market.LongToken == tokenOut)
+}
+
+// findSwapPaths lists the paths of distinct markets from tokenIn to tokenOut, up to maxSwapPathLength markets.
+func (p *PoolSimulator) findSwapPaths(tokenIn, tokenOut string) [][]*Market {
+	var swapPaths [][]*Market
+
+	var walk func(token string, path []*Market)
+	walk = func(token string, path []*Market) {
+		if token == tokenOut && len(path) > 0 {
+			swapPaths = append(swapPaths, append([]*Market(nil), path...))
+			return
+		}
+		if len(path) == maxSwapPathLength {
+			return
+		}
+
+		for _, market := range p.marketsByToken[token] {
+			if containsMarket(path, market) {
+				continue
+			}
+			nextToken := market.LongToken
+			if nextToken == token {
+				nextToken = market.ShortToken
+			}
+			walk(nextToken, append(path, market))
+		}
+	}
+	walk(tokenIn, nil)
+
+	return swapPaths
+}
+
+// swapAlongPath swaps through the markets in order. The state is only written when apply is set, otherwise the
+// virtual inventories are copied, since markets of the same path may share one.
+func (p *PoolSimulator) swapAlongPath(markets []*Market, tokenIn string, amountIn *big.Int, apply bool) ([]*hopResult, error) {
+	virtualInventories := p.virtualInventories
+	if !apply {
+		virtualInventories = make(map[string]*VirtualInventory, len(p.virtualInventories))
+		for id, inventory := range p.virtualInventories {
+			virtualInventories[id] = &VirtualInventory{LongAmount: inventory.LongAmount, ShortAmount: inventory.ShortAmount}
+		}
+	}
+
+	hops := make([]*hopResult, 0, len(markets))
+	for _, market := range markets {
+		hop, err := p.swap(market, virtualInventories[market.VirtualMarketID], tokenIn, amountIn)
+		if err != nil {
+			return nil, err
+		}
+
+		applyHop(hop, virtualInventories[market.VirtualMarketID], apply)
+
+		hops = append(hops, hop)
+		tokenIn, amountIn = hop.tokenOut, hop.amountOut
+	}
+
+	return hops, nil
+}
+
+// swap ports SwapUtils._swap without changing any state.
+// https://github.com/gmx-io/gmx-synthetics/blob/main/contracts/swap/SwapUtils.sol
+func (p *PoolSimulator) swap(market *Market, inventory *VirtualInventory, tokenIn string, amountIn *big.Int) (*hopResult, error) {
+	if market.IsDisabled {
+		return nil, ErrMarketDisabled
+	}
+
+	var isLongIn bool
+	var tokenOut string
+	switch tokenIn {
+	case market.LongToken:
+		isLongIn, tokenOut = true, market.ShortToken
+	case market.ShortToken:
+		isLongIn, tokenOut = false, market.LongToken
+	default:
+		return nil, ErrInvalidTokenIn
+	}
+
+	priceIn, ok := p.prices[tokenIn]
+	if !ok {
+		return nil, ErrPriceNotFound
+	}
+	priceOut, ok := p.prices[tokenOut]
+	if !ok {
+		return nil, ErrPriceNotFound
+	}
+
+	midPriceIn := midPrice(priceIn)
+	priceImpactUsd, err := getPriceImpactUsd(
+		market, inventory, isLongIn, midPriceIn, midPrice(priceOut), new(big.Int).Mul(amountIn, midPriceIn),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// SwapPricingUtils.getSwapFees
+	hasPositiveImpact := priceImpactUsd.Sign() > 0
+	feeFactor := market.NegativeSwapFeeFactor
+	if hasPositiveImpact {
+		feeFactor = market.PositiveSwapFeeFactor
+	}
+	feeAmount := applyFactor(amountIn, feeFactor)
+	feeAmountForPool := new(big.Int).Sub(feeAmount, applyFactor(feeAmount, p.swapFeeReceiverFactor))
+	amountAfterFees := new(big.Int).Sub(amountIn, feeAmount)
+
+	var poolAmountIn, poolAmountOut, amountOut, impactPoolAmount *big.Int
+	if hasPositiveImpact {
+		// the positive impact is paid out of the swap impact pool of tokenOut, capped by its balance
+		impactPoolAmount = new(big.Int).Div(priceImpactUsd, priceOut.Max)
+		if maxImpactAmount := market.swapImpactPoolAmount(!isLongIn); impactPoolAmount.Cmp(maxImpactAmount) > 0 {
+			impactPoolAmount = new(big.Int).Set(maxImpactAmount)
+		}
+
+		poolAmountIn = amountAfterFees
+		poolAmountOut = new(big.Int).Div(new(big.Int).Mul(poolAmountIn, priceIn.Min), priceOut.Max)
+		amountOut = new(big.Int).Add(poolAmountOut, impactPoolAmount)
+	} else {
+		// the negative impact is kept from tokenIn into its swap impact pool, rounded up
+		impactPoolAmount = roundUpDivision(new(big.Int).Neg(priceImpactUsd), priceIn.Min)
+
+		poolAmountIn = new(big.Int).Sub(amountAfterFees, impactPoolAmount)
+		if poolAmountIn.Sign() < 0 {
+			return nil, ErrPriceImpactExceedsAmount
+		}
+		poolAmountOut = new(big.Int).Div(new(big.Int).Mul(poolAmountIn, priceIn.Min), priceOut.Max)
+		amountOut = poolAmountOut
+	}
+	poolAmountIn = new(big.Int).Add(poolAmountIn, feeAmountForPool)
+
+	if market.poolAmount(!isLongIn).Cmp(poolAmountOut) < 0 {
+		return nil, ErrInsufficientPoolAmount
+	}
+
+	// MarketUtils.validatePoolAmount
+	nextPoolAmountIn := new(big.Int).Add(market.poolAmount(isLongIn), poolAmountIn)
+	if nextPoolAmountIn.Cmp(market.maxPoolAmount(isLongIn)) > 0 {
+		return nil, ErrMaxPoolAmountExceeded
+	}
+
+	return &hopResult{
+		market:            market,
+		tokenIn:           tokenIn,
+		tokenOut:          tokenOut,
+		amountOut:         amountOut,
+		feeAmount:         feeAmount,
+		poolAmountIn:      poolAmountIn,
+		poolAmountOut:     poolAmountOut,
+		impactPoolAmount:  impactPoolAmount,
+		hasPositiveImpact: hasPositiveImpact,
+	}, nil
+}
+
+// applyHop applies the pool amount deltas of a hop to the virtual inventory, and also to the market when apply is set.
+func applyHop(hop *hopResult, inventory *VirtualInventory, apply bool) {
+	isLongIn := hop.tokenIn == hop.market.LongToken
+
+	if inventory != nil {
+		if isLongIn {
+			inventory.LongAmount = new(big.Int).Add(inventory.LongAmount, hop.poolAmountIn)
+			inventory.ShortAmount = subBounded(inventory.ShortAmount, hop.poolAmountOut)
+		} else {
+			inventory.ShortAmount = new(big.Int).Add(inventory.ShortAmount, hop.poolAmountIn)
+			inventory.LongAmount = subBounded(inventory.LongAmount, hop.poolAmountOut)
+		}
+	}
+
+	if !apply {
+		return
+	}
+
+	market := hop.market
+	if isLongIn {
+		market.LongPoolAmount = new(big.Int).Add(market.LongPoolAmount, hop.poolAmountIn)
+		market.ShortPoolAmount = new(big.Int).Sub(market.ShortPoolAmount, hop.poolAmountOut)
+	} else {
+		market.ShortPoolAmount = new(big.Int).Add(market.ShortPoolAmount, hop.poolAmountIn)
+		market.LongPoolAmount = new(big.Int).Sub(market.LongPoolAmount, hop.poolAmountOut)
+	}
+
+	switch {
+	case hop.hasPositiveImpact && isLongIn:
+		market.ShortSwapImpactPoolAmount = subBounded(market.ShortSwapImpactPoolAmount, hop.impactPoolAmount)
+	case hop.hasPositiveImpact:
+		market.LongSwapImpactPoolAmount = subBounded(market.LongSwapImpactPoolAmount, hop.impactPoolAmount)
+	case isLongIn:
+		market.LongSwapImpactPoolAmount = new(big.Int).Add(market.LongSwapImpactPoolAmount, hop.impactPoolAmount)
+	default:
+		market.ShortSwapImpactPoolAmount = new(big.Int).Add(market.ShortSwapImpactPoolAmount, hop.impactPoolAmount)
+	}
+}
+
+func (m *Market) poolAmount(isLong bool) *big.Int {
+	if isLong {
+		return m.LongPoolAmount
+	}
+	return m.ShortPoolAmount
+}
+
+func (m *Market) maxPoolAmount(isLong bool) *big.Int {
+	if isLong {
+		return m.MaxLongPoolAmount
+	}
+	return m.MaxShortPoolAmount
+}
+
+func (m *Market) swapImpactPoolAmount(isLong bool) *big.Int {
+	if isLong {
+		return m.LongSwapImpactPoolAmount
+	}
+	return m.ShortSwapImpactPoolAmount
+}
+
+func containsMarket(markets []*Market, market *Market) bool {
+	for _, m := range markets {
+		if m == market {
+			return true
+		}
+	}
+	return false
+}
+
+func midPrice(price *Price) *big.Int {
+	return new(big.Int).Div(new(big.Int).Add(price.Min, price.Max), bignumber.Two)
+}
+
+func roundUpDivision(a, b *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	if r.Sign() != 0 {
+		q.Add(q, bignumber.One)
+	}
+	return q
+}
+
+// subBounded is a - b floored at zero, as DataStore.applyBoundedDeltaToUint
+func subBounded(a, b *big.Int) *big.Int {
+	if a.Cmp(b) <= 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).Sub(a, b)
+}
@@ -0,0 +1,261 @@
+package gmxv2
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	weth = "0x82af49447d8a07e3bd95bd0d56f35241523fbab1"
+	wbtc = "0x2f2a2543b76a4166549f7aab2e75bef0aefc5b0f"
+	usdc = "0xaf88d065e77c8cc2239327c5edb3a432268e5831"
+
+	ethUsdcMarket = "0x70d95587d40a2caf56bd97485ab3eec10bee6336"
+	btcUsdcMarket = "0x47c031236e19d024b42f8ae6780e44a573170703"
+)
+
+// newTestMarket returns a market of 1000 ETH and 2,000,000 USDC with a 1e-9 quadratic swap impact, 0.05% and 0.07%
+// swap fees, and prices of 2000 and 1 usd.
+func newTestMarket(marketToken, longToken string, longPoolAmount *big.Int) *Market {
+	return &Market{
+		MarketToken:               marketToken,
+		IndexToken:                longToken,
+		LongToken:                 longToken,
+		ShortToken:                usdc,
+		LongPoolAmount:            longPoolAmount,
+		ShortPoolAmount:           bignumber.NewBig10("2000000000000"),
+		MaxLongPoolAmount:         bignumber.NewBig10("1000000000000000000000000"),
+		MaxShortPoolAmount:        bignumber.NewBig10("1000000000000000"),
+		LongSwapImpactPoolAmount:  bignumber.NewBig10("1000000000000000000"),
+		ShortSwapImpactPoolAmount: bignumber.NewBig10("1000000000"),
+		PositiveSwapImpactFactor:  bignumber.NewBig10("500000000000000000000"),
+		NegativeSwapImpactFactor:  bignumber.NewBig10("1000000000000000000000"),
+		SwapImpactExponentFactor:  bignumber.NewBig10("2000000000000000000000000000000"),
+		PositiveSwapFeeFactor:     bignumber.NewBig10("500000000000000000000000000"),
+		NegativeSwapFeeFactor:     bignumber.NewBig10("700000000000000000000000000"),
+	}
+}
+
+func newTestPrices() map[string]*Price {
+	return map[string]*Price{
+		weth: {Min: bignumber.NewBig10("2000000000000000"), Max: bignumber.NewBig10("2000000000000000")},
+		wbtc: {Min: bignumber.NewBig10("200000000000000000000000000"), Max: bignumber.NewBig10("200000000000000000000000000")},
+		usdc: {Min: bignumber.NewBig10("1000000000000000000000000"), Max: bignumber.NewBig10("1000000000000000000000000")},
+	}
+}
+
+func newTestPoolSimulator(t *testing.T, extra Extra) *PoolSimulator {
+	extraBytes, err := json.Marshal(extra)
+	require.NoError(t, err)
+
+	poolTokens, reserves := getTokensAndReserves(extra.Markets)
+	simulator, err := NewPoolSimulator(entity.Pool{
+		Address:  "0xfd70de6b91282d8017aa4e741e9ae325cab992d8",
+		Exchange: DexTypeGmxV2,
+		Type:     DexTypeGmxV2,
+		Tokens:   poolTokens,
+		Reserves: reserves,
+		Extra:    string(extraBytes),
+	})
+	require.NoError(t, err)
+
+	return simulator
+}
+
+func TestPoolSimulator_CalcAmountOut(t *testing.T) {
+	simulator := newTestPoolSimulator(t, Extra{
+		Markets: []*Market{
+			newTestMarket(ethUsdcMarket, weth, bignumber.NewBig10("1000000000000000000000")),
+		},
+		Prices:                newTestPrices(),
+		SwapFeeReceiverFactor: bignumber.NewBig10("700000000000000000000000000000"),
+	})
+
+	// 10 ETH moves the pools from balanced to a 40,000 usd difference, so the impact is -1e-9 * 40000^2 = -1.6 usd,
+	// which is 0.0008 ETH kept in the impact pool. The fee is 0.07% of the amount in.
+	result, err := simulator.CalcAmountOut(pool.TokenAmount{
+		Token:  weth,
+		Amount: bignumber.NewBig10("10000000000000000000"),
+	}, usdc)
+	require.NoError(t, err)
+	assert.Equal(t, "19984400000", result.TokenAmountOut.Amount.String())
+	assert.Equal(t, "7000000000000000", result.Fee.Amount.String())
+	assert.Equal(t, SwapInfo{SwapPath: []string{ethUsdcMarket}}, result.SwapInfo)
+	assert.Equal(t, DefaultGas.Swap, result.Gas)
+
+	simulator.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  pool.TokenAmount{Token: weth, Amount: bignumber.NewBig10("10000000000000000000")},
+		TokenAmountOut: *result.TokenAmountOut,
+		Fee:            *result.Fee,
+		SwapInfo:       result.SwapInfo,
+	})
+
+	market := simulator.markets[ethUsdcMarket]
+	// 9.993 ETH after fees - 0.0008 ETH impact + 0.0021 ETH of fees kept by the pool
+	assert.Equal(t, "1009994300000000000000", market.LongPoolAmount.String())
+	assert.Equal(t, "1980015600000", market.ShortPoolAmount.String())
+	assert.Equal(t, "1000800000000000000", market.LongSwapImpactPoolAmount.String())
+
+	// the same swap again is further from balance, so it pays a larger impact
+	secondResult, err := simulator.CalcAmountOut(pool.TokenAmount{
+		Token:  weth,
+		Amount: bignumber.NewBig10("10000000000000000000"),
+	}, usdc)
+	require.NoError(t, err)
+	assert.Equal(t, -1, secondResult.TokenAmountOut.Amount.Cmp(result.TokenAmountOut.Amount))
+}
+
+func TestPoolSimulator_CalcAmountOut_PositiveImpact(t *testing.T) {
+	// 990 ETH against 2,000,000 USDC, so selling ETH brings the pools back to balance
+	simulator := newTestPoolSimulator(t, Extra{
+		Markets: []*Market{
+			newTestMarket(ethUsdcMarket, weth, bignumber.NewBig10("990000000000000000000")),
+		},
+		Prices:                newTestPrices(),
+		SwapFeeReceiverFactor: new(big.Int),
+	})
+
+	result, err := simulator.CalcAmountOut(pool.TokenAmount{
+		Token:  weth,
+		Amount: bignumber.NewBig10("5000000000000000000"),
+	}, usdc)
+	require.NoError(t, err)
+
+	// the diff goes from 20,000 to 0 usd, the positive impact is 5e-10 * 20000^2 = 0.2 usd paid from the impact pool
+	// and the fee is 0.05% of the amount in. PRBMath computes 20000^2 a bit short, which costs the last unit.
+	assert.Equal(t, "2500000000000000", result.Fee.Amount.String())
+	assert.Equal(t, "9995199999", result.TokenAmountOut.Amount.String())
+}
+
+func TestPoolSimulator_CalcAmountOut_SwapPath(t *testing.T) {
+	simulator := newTestPoolSimulator(t, Extra{
+		Markets: []*Market{
+			newTestMarket(ethUsdcMarket, weth, bignumber.NewBig10("1000000000000000000000")),
+			newTestMarket(btcUsdcMarket, wbtc, bignumber.NewBig10("10000000000")),
+		},
+		Prices:                newTestPrices(),
+		SwapFeeReceiverFactor: new(big.Int),
+	})
+
+	assert.ElementsMatch(t, []string{usdc, wbtc}, simulator.CanSwapTo(weth))
+
+	result, err := simulator.CalcAmountOut(pool.TokenAmount{
+		Token:  weth,
+		Amount: bignumber.NewBig10("1000000000000000000"),
+	}, wbtc)
+	require.NoError(t, err)
+	assert.Equal(t, SwapInfo{SwapPath: []string{ethUsdcMarket, btcUsdcMarket}}, result.SwapInfo)
+	assert.Equal(t, Meta{SwapPath: []string{ethUsdcMarket, btcUsdcMarket}}, simulator.GetMetaInfo(weth, wbtc))
+	assert.Equal(t, DefaultGas.Swap+DefaultGas.SwapPathMarket, result.Gas)
+	// 2000 usd less two fees of 0.07% and two impacts of 0.016 usd, in BTC of 20,000 usd with 8 decimals
+	assert.Equal(t, "9985845", result.TokenAmountOut.Amount.String())
+}
+
+func TestPoolSimulator_CalcAmountOut_VirtualInventory(t *testing.T) {
+	newExtra := func(virtualInventories map[string]*VirtualInventory) Extra {
+		market := newTestMarket(ethUsdcMarket, weth, bignumber.NewBig10("1000000000000000000000"))
+		market.VirtualMarketID = "0x01"
+		return Extra{
+			Markets:               []*Market{market},
+			VirtualInventories:    virtualInventories,
+			Prices:                newTestPrices(),
+			SwapFeeReceiverFactor: new(big.Int),
+		}
+	}
+	tokenAmountIn := pool.TokenAmount{Token: weth, Amount: bignumber.NewBig10("10000000000000000000")}
+
+	withoutInventory, err := newTestPoolSimulator(t, newExtra(nil)).CalcAmountOut(tokenAmountIn, usdc)
+	require.NoError(t, err)
+
+	// the virtual inventory already holds 3000 ETH against 2,000,000 USDC, so adding ETH has a larger impact there
+	withInventory, err := newTestPoolSimulator(t, newExtra(map[string]*VirtualInventory{
+		"0x01": {
+			LongAmount:  bignumber.NewBig10("3000000000000000000000"),
+			ShortAmount: bignumber.NewBig10("2000000000000"),
+		},
+	})).CalcAmountOut(tokenAmountIn, usdc)
+	require.NoError(t, err)
+
+	assert.Equal(t, -1, withInventory.TokenAmountOut.Amount.Cmp(withoutInventory.TokenAmountOut.Amount))
+}
+
+func TestPoolSimulator_CalcAmountOut_VirtualInventoryPositiveImpact(t *testing.T) {
+	testCases := []struct {
+		name      string
+		inventory *VirtualInventory
+	}{
+		{
+			// adding ETH to 3000 ETH against 2,000,000 USDC has a negative impact on the virtual inventory
+			name: "negative virtual inventory impact",
+			inventory: &VirtualInventory{
+				LongAmount:  bignumber.NewBig10("3000000000000000000000"),
+				ShortAmount: bignumber.NewBig10("2000000000000"),
+			},
+		},
+		{
+			// the virtual inventory holds less USDC than the swap takes out
+			name: "virtual inventory smaller than the swap",
+			inventory: &VirtualInventory{
+				LongAmount:  bignumber.NewBig10("3000000000000000000000"),
+				ShortAmount: bignumber.NewBig10("1000000000"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// 990 ETH against 2,000,000 USDC, so selling ETH rebalances the market and its impact is kept
+			market := newTestMarket(ethUsdcMarket, weth, bignumber.NewBig10("990000000000000000000"))
+			market.VirtualMarketID = "0x01"
+			simulator := newTestPoolSimulator(t, Extra{
+				Markets:               []*Market{market},
+				VirtualInventories:    map[string]*VirtualInventory{"0x01": tc.inventory},
+				Prices:                newTestPrices(),
+				SwapFeeReceiverFactor: new(big.Int),
+			})
+
+			result, err := simulator.CalcAmountOut(pool.TokenAmount{
+				Token:  weth,
+				Amount: bignumber.NewBig10("5000000000000000000"),
+			}, usdc)
+			require.NoError(t, err)
+			assert.Equal(t, "9995199999", result.TokenAmountOut.Amount.String())
+		})
+	}
+}
+
+func TestPoolSimulator_CalcAmountOut_Errors(t *testing.T) {
+	market := newTestMarket(ethUsdcMarket, weth, bignumber.NewBig10("1000000000000000000000"))
+	market.MaxLongPoolAmount = bignumber.NewBig10("1005000000000000000000")
+	simulator := newTestPoolSimulator(t, Extra{
+		Markets:               []*Market{market},
+		Prices:                newTestPrices(),
+		SwapFeeReceiverFactor: new(big.Int),
+	})
+
+	_, err := simulator.CalcAmountOut(pool.TokenAmount{
+		Token:  weth,
+		Amount: bignumber.NewBig10("10000000000000000000"),
+	}, usdc)
+	assert.ErrorIs(t, err, ErrMaxPoolAmountExceeded)
+
+	_, err = simulator.CalcAmountOut(pool.TokenAmount{
+		Token:  usdc,
+		Amount: bignumber.NewBig10("3000000000000"),
+	}, weth)
+	assert.ErrorIs(t, err, ErrUsdDeltaExceedsPoolValue)
+
+	_, err = simulator.CalcAmountOut(pool.TokenAmount{
+		Token:  weth,
+		Amount: bignumber.NewBig10("1000000000000000000"),
+	}, wbtc)
+	assert.ErrorIs(t, err, ErrNoSwapPath)
+}
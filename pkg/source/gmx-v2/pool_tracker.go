@@ -0,0 +1,61 @@
+package gmxv2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+type PoolTracker struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+}
+
+func NewPoolTracker(
+	cfg *Config,
+	ethrpcClient *ethrpc.Client,
+) (*PoolTracker, error) {
+	return &PoolTracker{
+		config:       cfg,
+		ethrpcClient: ethrpcClient,
+	}, nil
+}
+
+func (d *PoolTracker) GetNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	_ pool.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	log := logger.WithFields(logger.Fields{
+		"liquiditySource": DexTypeGmxV2,
+		"poolAddress":     p.Address,
+	})
+	log.Info("Start getting new state of pool")
+
+	extra, err := NewDataStoreReader(d.config, d.ethrpcClient).Read(ctx)
+	if err != nil {
+		log.Errorf("read data store failed: %v", err)
+		return entity.Pool{}, fmt.Errorf("read data store failed, pool: %s, err: %v", p.Address, err)
+	}
+
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		log.Errorf("marshal extra failed: %v", err)
+		return entity.Pool{}, err
+	}
+
+	p.Tokens, p.Reserves = getTokensAndReserves(extra.Markets)
+	p.Extra = string(extraBytes)
+	p.Timestamp = time.Now().Unix()
+
+	log.Info("Finish getting new state")
+
+	return p, nil
+}
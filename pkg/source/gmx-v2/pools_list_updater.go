@@ -0,0 +1,103 @@
+package gmxv2
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+)
+
+type PoolsListUpdater struct {
+	config         *Config
+	ethrpcClient   *ethrpc.Client
+	hasInitialized bool
+}
+
+func NewPoolsListUpdater(
+	cfg *Config,
+	ethrpcClient *ethrpc.Client,
+) *PoolsListUpdater {
+	return &PoolsListUpdater{
+		config:         cfg,
+		ethrpcClient:   ethrpcClient,
+		hasInitialized: false,
+	}
+}
+
+// GetNewPools returns a single pool for the DataStore, new markets are picked up by the pool tracker.
+func (d *PoolsListUpdater) GetNewPools(ctx context.Context, _ []byte) ([]entity.Pool, []byte, error) {
+	log := logger.WithFields(logger.Fields{
+		"liquiditySource": DexTypeGmxV2,
+		"kind":            "getNewPools",
+	})
+	if d.hasInitialized {
+		log.Infof("initialized. Ignore making new pools")
+		return nil, nil, nil
+	}
+
+	extra, err := NewDataStoreReader(d.config, d.ethrpcClient).Read(ctx)
+	if err != nil {
+		log.Errorf("read data store failed: %v", err)
+		return nil, nil, err
+	}
+
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		log.Errorf("error when marshal extra: %v", err)
+		return nil, nil, err
+	}
+
+	poolTokens, reserves := getTokensAndReserves(extra.Markets)
+	pool := entity.Pool{
+		Address:   d.config.DataStoreAddress,
+		Exchange:  d.config.DexID,
+		Type:      DexTypeGmxV2,
+		Tokens:    poolTokens,
+		Reserves:  reserves,
+		Extra:     string(extraBytes),
+		Timestamp: time.Now().Unix(),
+	}
+
+	d.hasInitialized = true
+	log.Infof("got data store %v with %v markets", d.config.DataStoreAddress, len(extra.Markets))
+
+	return []entity.Pool{pool}, nil, nil
+}
+
+// getTokensAndReserves returns the tokens of the markets, each with its pool amount summed over all markets.
+func getTokensAndReserves(markets []*Market) ([]*entity.PoolToken, entity.PoolReserves) {
+	tokens := marketTokens(markets)
+
+	amounts := make(map[string]*big.Int, len(tokens))
+	for _, market := range markets {
+		amounts[market.LongToken] = new(big.Int).Add(orZero(amounts[market.LongToken]), market.LongPoolAmount)
+		// both pool amounts of a single token market are the same DataStore value
+		if market.ShortToken != market.LongToken {
+			amounts[market.ShortToken] = new(big.Int).Add(orZero(amounts[market.ShortToken]), market.ShortPoolAmount)
+		}
+	}
+
+	poolTokens := make([]*entity.PoolToken, 0, len(tokens))
+	reserves := make(entity.PoolReserves, 0, len(tokens))
+	for _, token := range tokens {
+		poolTokens = append(poolTokens, &entity.PoolToken{
+			Address:   token,
+			Swappable: true,
+		})
+		reserves = append(reserves, amounts[token].String())
+	}
+
+	return poolTokens, reserves
+}
+
+func orZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
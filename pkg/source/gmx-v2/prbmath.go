@@ -0,0 +1,112 @@
+package gmxv2
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// The functions of this file port the unsigned 60.18-decimal fixed-point math of PRBMath v2, which the contracts use
+// in Precision.applyExponentFactor.
+// https://github.com/PaulRBerg/prb-math/blob/v2.5.0/contracts/PRBMathUD60x18.sol
+
+var (
+	prbScale     = weiPrecision
+	prbHalfScale = new(big.Int).Div(weiPrecision, bignumber.Two)
+	// prbMaxExp2Input is the bound of the input of exp2, the result would overflow 192 bits above it
+	prbMaxExp2Input = new(big.Int).Mul(big.NewInt(192), weiPrecision)
+	prbExp2Start    = new(big.Int).Lsh(bignumber.One, 191)
+
+	// prbExp2Factors are the factors of PRBMath.exp2 in 64.64 fixed point, prbExp2Factors[i] is 2^(2^(i-64)) rounded
+	// to the nearest, i.e. the factor of the bit i of the fractional part
+	prbExp2Factors = getExp2Factors()
+)
+
+func getExp2Factors() [64]*big.Int {
+	var factors [64]*big.Int
+
+	// 2^(2^-1) is the square root of 2, and every next factor is the square root of the previous one
+	factor := new(big.Float).SetPrec(512).SetInt64(2)
+	one := new(big.Float).SetPrec(512).SetInt(new(big.Int).Lsh(bignumber.One, 64))
+	half := new(big.Float).SetPrec(512).SetFloat64(0.5)
+	for i := 63; i >= 0; i-- {
+		factor.Sqrt(factor)
+		scaled := new(big.Float).SetPrec(512).Mul(factor, one)
+		factors[i], _ = scaled.Add(scaled, half).Int(nil)
+	}
+
+	return factors
+}
+
+// prbPow is PRBMathUD60x18.pow, x^y = 2^(log2(x) * y), x must not be less than 1
+func prbPow(x, y *big.Int) (*big.Int, error) {
+	if x.Sign() == 0 {
+		if y.Sign() == 0 {
+			return new(big.Int).Set(prbScale), nil
+		}
+		return new(big.Int), nil
+	}
+
+	log2X, err := prbLog2(x)
+	if err != nil {
+		return nil, err
+	}
+
+	return prbExp2(prbMul(log2X, y))
+}
+
+// prbLog2 is PRBMathUD60x18.log2, the binary logarithm by iterative approximation
+func prbLog2(x *big.Int) (*big.Int, error) {
+	if x.Cmp(prbScale) < 0 {
+		return nil, ErrLog2InputTooSmall
+	}
+
+	n := new(big.Int).Div(x, prbScale).BitLen() - 1
+	result := new(big.Int).Mul(big.NewInt(int64(n)), prbScale)
+
+	y := new(big.Int).Rsh(x, uint(n))
+	if y.Cmp(prbScale) == 0 {
+		return result, nil
+	}
+
+	doubleScale := new(big.Int).Mul(prbScale, bignumber.Two)
+	for delta := new(big.Int).Set(prbHalfScale); delta.Sign() > 0; delta.Rsh(delta, 1) {
+		y.Div(y.Mul(y, y), prbScale)
+		if y.Cmp(doubleScale) >= 0 {
+			result.Add(result, delta)
+			y.Rsh(y, 1)
+		}
+	}
+
+	return result, nil
+}
+
+// prbExp2 is PRBMathUD60x18.exp2, x is converted to 192.64 fixed point, then 2^x is the product of the factors of
+// the bits of the fractional part shifted by the integer part
+func prbExp2(x *big.Int) (*big.Int, error) {
+	if x.Cmp(prbMaxExp2Input) >= 0 {
+		return nil, ErrExp2InputTooBig
+	}
+
+	x192x64 := new(big.Int).Div(new(big.Int).Lsh(x, 64), prbScale)
+
+	result := new(big.Int).Set(prbExp2Start)
+	for i := 63; i >= 0; i-- {
+		if x192x64.Bit(i) == 1 {
+			result.Rsh(result.Mul(result, prbExp2Factors[i]), 64)
+		}
+	}
+
+	result.Mul(result, prbScale)
+	return result.Rsh(result, uint(191-new(big.Int).Rsh(x192x64, 64).Int64())), nil
+}
+
+// prbMul is PRBMath.mulDivFixedPoint, x * y / 1e18 rounded half up
+func prbMul(x, y *big.Int) *big.Int {
+	result, remainder := new(big.Int).QuoRem(new(big.Int).Mul(x, y), prbScale, new(big.Int))
+	if remainder.Cmp(prbHalfScale) >= 0 {
+		result.Add(result, bignumber.One)
+	}
+
+	return result
+}
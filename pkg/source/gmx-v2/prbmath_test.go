@@ -0,0 +1,38 @@
+package gmxv2
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+func TestPrbPow(t *testing.T) {
+	log2, err := prbLog2(bignumber.NewBig10("8000000000000000000"))
+	require.NoError(t, err)
+	assert.Equal(t, "3000000000000000000", log2.String())
+
+	exp2, err := prbExp2(bignumber.NewBig10("3000000000000000000"))
+	require.NoError(t, err)
+	assert.Equal(t, "8000000000000000000", exp2.String())
+
+	// 4^0.5 and 20000^2 are a few units off the exact results, as in the contracts
+	sqrt, err := prbPow(bignumber.NewBig10("4000000000000000000"), bignumber.NewBig10("500000000000000000"))
+	require.NoError(t, err)
+	assert.InDelta(t, 2e18, float64(sqrt.Int64()), 1e3)
+
+	square, err := prbPow(bignumber.NewBig10("20000000000000000000000"), bignumber.NewBig10("2000000000000000000"))
+	require.NoError(t, err)
+	exact := bignumber.NewBig10("400000000000000000000000000")
+	assert.Equal(t, -1, square.Cmp(exact))
+	assert.Equal(t, -1, new(big.Int).Sub(exact, square).Cmp(bignumber.TenPowInt(12)))
+
+	_, err = prbLog2(bignumber.NewBig10("999999999999999999"))
+	assert.ErrorIs(t, err, ErrLog2InputTooSmall)
+
+	_, err = prbExp2(bignumber.NewBig10("192000000000000000000"))
+	assert.ErrorIs(t, err, ErrExp2InputTooBig)
+}
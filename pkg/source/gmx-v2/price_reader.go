@@ -0,0 +1,119 @@
+package gmxv2
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PriceReader reads token prices from the chainlink feeds configured in the DataStore. Swaps of GMX v2 are executed
+// by keepers with signed oracle prices, which are not available on-chain, so the feed prices stand in for them, the
+// same way the Oracle of the contracts falls back to them.
+// https://github.com/gmx-io/gmx-synthetics/blob/main/contracts/oracle/Oracle.sol
+type PriceReader struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+	log          logger.Logger
+}
+
+func NewPriceReader(config *Config, ethrpcClient *ethrpc.Client) *PriceReader {
+	return &PriceReader{
+		config:       config,
+		ethrpcClient: ethrpcClient,
+		log: logger.WithFields(logger.Fields{
+			"liquiditySource": DexTypeGmxV2,
+			"reader":          "PriceReader",
+		}),
+	}
+}
+
+// Read returns the prices of the tokens which have a price feed, the others are left out.
+func (r *PriceReader) Read(ctx context.Context, tokens []string) (map[string]*Price, error) {
+	priceFeeds := make([]common.Address, len(tokens))
+	multipliers := make([]*big.Int, len(tokens))
+	stablePrices := make([]*big.Int, len(tokens))
+
+	rpcRequest := r.ethrpcClient.NewRequest().SetContext(ctx)
+	for i, token := range tokens {
+		rpcRequest.AddCall(&ethrpc.Call{
+			ABI:    dataStoreABI,
+			Target: r.config.DataStoreAddress,
+			Method: dataStoreMethodGetAddress,
+			Params: []interface{}{priceFeedKey(token)},
+		}, []interface{}{&priceFeeds[i]})
+		rpcRequest.AddCall(&ethrpc.Call{
+			ABI:    dataStoreABI,
+			Target: r.config.DataStoreAddress,
+			Method: dataStoreMethodGetUint,
+			Params: []interface{}{priceFeedMultiplierKey(token)},
+		}, []interface{}{&multipliers[i]})
+		rpcRequest.AddCall(&ethrpc.Call{
+			ABI:    dataStoreABI,
+			Target: r.config.DataStoreAddress,
+			Method: dataStoreMethodGetUint,
+			Params: []interface{}{stablePriceKey(token)},
+		}, []interface{}{&stablePrices[i]})
+	}
+
+	if _, err := rpcRequest.Aggregate(); err != nil {
+		r.log.Errorf("error when read price feeds: %s", err)
+		return nil, err
+	}
+
+	roundData := make([]RoundData, len(tokens))
+	rpcRequest = r.ethrpcClient.NewRequest().SetContext(ctx)
+	for i := range tokens {
+		if priceFeeds[i] == (common.Address{}) {
+			continue
+		}
+
+		rpcRequest.AddCall(&ethrpc.Call{
+			ABI:    priceFeedABI,
+			Target: priceFeeds[i].Hex(),
+			Method: priceFeedMethodLatestRoundData,
+			Params: nil,
+		}, []interface{}{&roundData[i]})
+	}
+
+	if len(rpcRequest.Calls) == 0 {
+		return map[string]*Price{}, nil
+	}
+
+	resp, err := rpcRequest.TryAggregate()
+	if err != nil {
+		r.log.Errorf("error when read latest round data: %s", err)
+		return nil, err
+	}
+
+	prices := make(map[string]*Price, len(tokens))
+	callIndex := 0
+	for i, token := range tokens {
+		if priceFeeds[i] == (common.Address{}) {
+			continue
+		}
+		success := resp.Result[callIndex]
+		callIndex++
+
+		if !success || roundData[i].Answer == nil || roundData[i].Answer.Sign() <= 0 {
+			continue
+		}
+
+		// ChainlinkPriceFeedUtils.getPriceFeedPrice
+		price := new(big.Int).Div(new(big.Int).Mul(roundData[i].Answer, multipliers[i]), FloatPrecision)
+		if stablePrices[i].Sign() > 0 {
+			if price.Cmp(stablePrices[i]) < 0 {
+				prices[token] = &Price{Min: price, Max: stablePrices[i]}
+			} else {
+				prices[token] = &Price{Min: stablePrices[i], Max: price}
+			}
+			continue
+		}
+
+		prices[token] = &Price{Min: price, Max: price}
+	}
+
+	return prices, nil
+}
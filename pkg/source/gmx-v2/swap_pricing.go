@@ -0,0 +1,166 @@
+package gmxv2
+
+import (
+	"math/big"
+)
+
+// getPriceImpactUsd returns the price impact of moving usdDelta from the pool of tokenOut to the pool of tokenIn. A
+// positive impact on the market pools is kept, a negative one is the lower of the impacts on the market pools and on
+// the virtual inventory.
+// https://github.com/gmx-io/gmx-synthetics/blob/main/contracts/pricing/SwapPricingUtils.sol
+func getPriceImpactUsd(
+	market *Market,
+	inventory *VirtualInventory,
+	isLongIn bool,
+	priceIn *big.Int,
+	priceOut *big.Int,
+	usdDelta *big.Int,
+) (*big.Int, error) {
+	poolAmountIn, poolAmountOut := market.LongPoolAmount, market.ShortPoolAmount
+	if !isLongIn {
+		poolAmountIn, poolAmountOut = poolAmountOut, poolAmountIn
+	}
+
+	priceImpactUsd, err := getPriceImpactUsdForPools(
+		market,
+		new(big.Int).Mul(poolAmountIn, priceIn),
+		new(big.Int).Mul(poolAmountOut, priceOut),
+		usdDelta,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if priceImpactUsd.Sign() >= 0 || inventory == nil {
+		return priceImpactUsd, nil
+	}
+
+	virtualAmountIn, virtualAmountOut := inventory.LongAmount, inventory.ShortAmount
+	if !isLongIn {
+		virtualAmountIn, virtualAmountOut = virtualAmountOut, virtualAmountIn
+	}
+
+	priceImpactUsdForVirtualInventory, err := getPriceImpactUsdForPools(
+		market,
+		new(big.Int).Mul(virtualAmountIn, priceIn),
+		new(big.Int).Mul(virtualAmountOut, priceOut),
+		usdDelta,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if priceImpactUsdForVirtualInventory.Cmp(priceImpactUsd) < 0 {
+		return priceImpactUsdForVirtualInventory, nil
+	}
+
+	return priceImpactUsd, nil
+}
+
+func getPriceImpactUsdForPools(market *Market, poolUsdIn, poolUsdOut, usdDelta *big.Int) (*big.Int, error) {
+	nextPoolUsdIn := new(big.Int).Add(poolUsdIn, usdDelta)
+	nextPoolUsdOut := new(big.Int).Sub(poolUsdOut, usdDelta)
+	if nextPoolUsdOut.Sign() < 0 {
+		return nil, ErrUsdDeltaExceedsPoolValue
+	}
+
+	initialDiffUsd := new(big.Int).Abs(new(big.Int).Sub(poolUsdIn, poolUsdOut))
+	nextDiffUsd := new(big.Int).Abs(new(big.Int).Sub(nextPoolUsdIn, nextPoolUsdOut))
+
+	positiveImpactFactor, negativeImpactFactor := market.PositiveSwapImpactFactor, market.NegativeSwapImpactFactor
+	// the positive impact factor must not be larger than the negative one, to prevent gaming by splitting the swap
+	if positiveImpactFactor.Cmp(negativeImpactFactor) > 0 {
+		positiveImpactFactor = negativeImpactFactor
+	}
+
+	isSameSideRebalance := (poolUsdIn.Cmp(poolUsdOut) <= 0) == (nextPoolUsdIn.Cmp(nextPoolUsdOut) <= 0)
+	if isSameSideRebalance {
+		hasPositiveImpact := nextDiffUsd.Cmp(initialDiffUsd) < 0
+		impactFactor := negativeImpactFactor
+		if hasPositiveImpact {
+			impactFactor = positiveImpactFactor
+		}
+
+		return getPriceImpactUsdForSameSideRebalance(
+			initialDiffUsd, nextDiffUsd, impactFactor, market.SwapImpactExponentFactor,
+		)
+	}
+
+	return getPriceImpactUsdForCrossoverRebalance(
+		initialDiffUsd, nextDiffUsd, positiveImpactFactor, negativeImpactFactor, market.SwapImpactExponentFactor,
+	)
+}
+
+// https://github.com/gmx-io/gmx-synthetics/blob/main/contracts/pricing/PricingUtils.sol
+func getPriceImpactUsdForSameSideRebalance(
+	initialDiffUsd, nextDiffUsd, impactFactor, impactExponentFactor *big.Int,
+) (*big.Int, error) {
+	hasPositiveImpact := nextDiffUsd.Cmp(initialDiffUsd) < 0
+
+	initialImpactUsd, err := applyImpactFactor(initialDiffUsd, impactFactor, impactExponentFactor)
+	if err != nil {
+		return nil, err
+	}
+	nextImpactUsd, err := applyImpactFactor(nextDiffUsd, impactFactor, impactExponentFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	deltaDiffUsd := new(big.Int).Abs(new(big.Int).Sub(initialImpactUsd, nextImpactUsd))
+	if hasPositiveImpact {
+		return deltaDiffUsd, nil
+	}
+
+	return deltaDiffUsd.Neg(deltaDiffUsd), nil
+}
+
+func getPriceImpactUsdForCrossoverRebalance(
+	initialDiffUsd, nextDiffUsd, positiveImpactFactor, negativeImpactFactor, impactExponentFactor *big.Int,
+) (*big.Int, error) {
+	positiveImpactUsd, err := applyImpactFactor(initialDiffUsd, positiveImpactFactor, impactExponentFactor)
+	if err != nil {
+		return nil, err
+	}
+	negativeImpactUsd, err := applyImpactFactor(nextDiffUsd, negativeImpactFactor, impactExponentFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).Sub(positiveImpactUsd, negativeImpactUsd), nil
+}
+
+func applyImpactFactor(diffUsd, impactFactor, impactExponentFactor *big.Int) (*big.Int, error) {
+	exponentValue, err := applyExponentFactor(diffUsd, impactExponentFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyFactor(exponentValue, impactFactor), nil
+}
+
+// applyFactor is Precision.applyFactor: value * factor / FloatPrecision
+func applyFactor(value, factor *big.Int) *big.Int {
+	return new(big.Int).Div(new(big.Int).Mul(value, factor), FloatPrecision)
+}
+
+// applyExponentFactor is Precision.applyExponentFactor, the power is computed in 18 decimals with PRBMath
+func applyExponentFactor(floatValue, exponentFactor *big.Int) (*big.Int, error) {
+	// `PRBMathUD60x18.pow` doesn't work for `x` less than one
+	if floatValue.Cmp(FloatPrecision) < 0 {
+		return new(big.Int), nil
+	}
+
+	if exponentFactor.Cmp(FloatPrecision) == 0 {
+		return floatValue, nil
+	}
+
+	weiResult, err := prbPow(
+		new(big.Int).Div(floatValue, floatToWeiDivisor),
+		new(big.Int).Div(exponentFactor, floatToWeiDivisor),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return weiResult.Mul(weiResult, floatToWeiDivisor), nil
+}
@@ -0,0 +1,83 @@
+package gmxv2
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Gas struct {
+	Swap int64
+	// SwapPathMarket is the gas of every market of the swap path after the first one
+	SwapPathMarket int64
+}
+
+// Market is a GMX v2 market and the state which its swaps read
+type Market struct {
+	MarketToken string `json:"marketToken"`
+	IndexToken  string `json:"indexToken"`
+	LongToken   string `json:"longToken"`
+	ShortToken  string `json:"shortToken"`
+
+	IsDisabled bool `json:"isDisabled,omitempty"`
+
+	LongPoolAmount            *big.Int `json:"longPoolAmount"`
+	ShortPoolAmount           *big.Int `json:"shortPoolAmount"`
+	MaxLongPoolAmount         *big.Int `json:"maxLongPoolAmount"`
+	MaxShortPoolAmount        *big.Int `json:"maxShortPoolAmount"`
+	LongSwapImpactPoolAmount  *big.Int `json:"longSwapImpactPoolAmount"`
+	ShortSwapImpactPoolAmount *big.Int `json:"shortSwapImpactPoolAmount"`
+
+	PositiveSwapImpactFactor *big.Int `json:"positiveSwapImpactFactor"`
+	NegativeSwapImpactFactor *big.Int `json:"negativeSwapImpactFactor"`
+	SwapImpactExponentFactor *big.Int `json:"swapImpactExponentFactor"`
+	PositiveSwapFeeFactor    *big.Int `json:"positiveSwapFeeFactor"`
+	NegativeSwapFeeFactor    *big.Int `json:"negativeSwapFeeFactor"`
+
+	// VirtualMarketID links the markets sharing a virtual inventory, it is empty when the market has none
+	VirtualMarketID string `json:"virtualMarketId,omitempty"`
+}
+
+// VirtualInventory is the virtual inventory for swaps of a virtual market id, it is shared by all its markets
+type VirtualInventory struct {
+	LongAmount  *big.Int `json:"longAmount"`
+	ShortAmount *big.Int `json:"shortAmount"`
+}
+
+// Price is the price of 1 unit of a token in usd with FloatPrecision
+type Price struct {
+	Min *big.Int `json:"min"`
+	Max *big.Int `json:"max"`
+}
+
+type Extra struct {
+	Markets               []*Market                    `json:"markets"`
+	VirtualInventories    map[string]*VirtualInventory `json:"virtualInventories"`
+	Prices                map[string]*Price            `json:"prices"`
+	SwapFeeReceiverFactor *big.Int                     `json:"swapFeeReceiverFactor"`
+}
+
+type SwapInfo struct {
+	// SwapPath is the market tokens of the swap, to be passed as swapPath of the order
+	SwapPath []string `json:"swapPath"`
+}
+
+// Meta is the meta of the encoder, SwapPath is the same as the one of SwapInfo
+type Meta struct {
+	SwapPath []string `json:"swapPath"`
+}
+
+type MarketProps struct {
+	MarketToken common.Address
+	IndexToken  common.Address
+	LongToken   common.Address
+	ShortToken  common.Address
+}
+
+type RoundData struct {
+	RoundId         *big.Int
+	Answer          *big.Int
+	StartedAt       *big.Int
+	UpdatedAt       *big.Int
+	AnsweredInRound *big.Int
+}
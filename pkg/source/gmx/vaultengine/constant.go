@@ -0,0 +1,8 @@
+package vaultengine
+
+import "math/big"
+
+var (
+	BasisPointsDivisor = big.NewInt(10000)
+	PricePrecision     = new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil)
+)
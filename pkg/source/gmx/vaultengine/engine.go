@@ -0,0 +1,203 @@
+package vaultengine
+
+import (
+	"math/big"
+)
+
+// Vault is the part of a GMX-like vault state which is read and written by swaps. The maps are shared with the vault
+// of the source, so the fee utils of the source compute dynamic fees from the amounts updated by the Engine.
+type Vault struct {
+	IsSwapEnabled bool
+
+	// UsdgAddress is the address of the vault's usd token (USDG, USDM, USDB, USDF, ...)
+	UsdgAddress string
+
+	PoolAmounts     map[string]*big.Int
+	BufferAmounts   map[string]*big.Int
+	ReservedAmounts map[string]*big.Int
+	UsdgAmounts     map[string]*big.Int
+	MaxUsdgAmounts  map[string]*big.Int
+}
+
+type IPriceFeed interface {
+	GetMinPrice(token string) (*big.Int, error)
+	GetMaxPrice(token string) (*big.Int, error)
+	AdjustForDecimals(amount *big.Int, tokenDiv string, tokenMul string) *big.Int
+}
+
+type IFeeCalculator interface {
+	GetSwapFeeBasisPoints(tokenIn string, tokenOut string, usdgAmount *big.Int) (*big.Int, error)
+}
+
+// FeeCalculatorFunc adapts a plain function to IFeeCalculator.
+type FeeCalculatorFunc func(tokenIn string, tokenOut string, usdgAmount *big.Int) (*big.Int, error)
+
+func (f FeeCalculatorFunc) GetSwapFeeBasisPoints(tokenIn string, tokenOut string, usdgAmount *big.Int) (*big.Int, error) {
+	return f(tokenIn, tokenOut, usdgAmount)
+}
+
+// Engine simulates Vault.swap of GMX and its forks.
+// https://github.com/gmx-io/gmx-contracts/blob/787d767e033c411f6d083f2725fb54b7fa956f7e/contracts/core/Vault.sol#L521
+type Engine struct {
+	vault         *Vault
+	priceFeed     IPriceFeed
+	feeCalculator IFeeCalculator
+}
+
+type SwapResult struct {
+	AmountOut          *big.Int
+	AmountOutAfterFees *big.Int
+	FeeAmount          *big.Int
+	UsdgAmount         *big.Int
+}
+
+func NewEngine(vault *Vault, priceFeed IPriceFeed, feeCalculator IFeeCalculator) *Engine {
+	return &Engine{
+		vault:         vault,
+		priceFeed:     priceFeed,
+		feeCalculator: feeCalculator,
+	}
+}
+
+// GetAmountOut quotes a swap against the current vault state without changing it
+func (e *Engine) GetAmountOut(tokenIn string, tokenOut string, amountIn *big.Int) (*SwapResult, error) {
+	if !e.vault.IsSwapEnabled {
+		return nil, ErrVaultSwapsNotEnabled
+	}
+
+	if tokenIn == tokenOut {
+		return nil, ErrVaultInvalidTokens
+	}
+
+	priceIn, err := e.priceFeed.GetMinPrice(tokenIn)
+	if err != nil {
+		return nil, err
+	}
+
+	priceOut, err := e.priceFeed.GetMaxPrice(tokenOut)
+	if err != nil {
+		return nil, err
+	}
+
+	amountOut := new(big.Int).Div(new(big.Int).Mul(amountIn, priceIn), priceOut)
+	amountOut = e.priceFeed.AdjustForDecimals(amountOut, tokenIn, tokenOut)
+
+	usdgAmount := new(big.Int).Div(new(big.Int).Mul(amountIn, priceIn), PricePrecision)
+	usdgAmount = e.priceFeed.AdjustForDecimals(usdgAmount, tokenIn, e.vault.UsdgAddress)
+
+	// in smart contract, this validation is implemented inside _increaseUsdgAmount method
+	if err = e.ValidateMaxUsdgAmount(tokenIn, usdgAmount); err != nil {
+		return nil, err
+	}
+
+	// in smart contract, this validation is implemented inside _decreasePoolAmount method
+	if err = e.ValidateMinPoolAmount(tokenOut, amountOut); err != nil {
+		return nil, err
+	}
+
+	// in smart contract, this validation is implemented inside _validateBufferAmount method
+	if err = e.ValidateBufferAmount(tokenOut, amountOut); err != nil {
+		return nil, err
+	}
+
+	feeBasisPoints, err := e.feeCalculator.GetSwapFeeBasisPoints(tokenIn, tokenOut, usdgAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	amountOutAfterFees := new(big.Int).Div(
+		new(big.Int).Mul(amountOut, new(big.Int).Sub(BasisPointsDivisor, feeBasisPoints)),
+		BasisPointsDivisor,
+	)
+
+	return &SwapResult{
+		AmountOut:          amountOut,
+		AmountOutAfterFees: amountOutAfterFees,
+		FeeAmount:          new(big.Int).Sub(amountOut, amountOutAfterFees),
+		UsdgAmount:         usdgAmount,
+	}, nil
+}
+
+// Swap quotes a swap and applies it to the vault state, so that the next quote sees the new pool amounts, usdg
+// amounts and dynamic fees. The state is left untouched when the swap fails.
+func (e *Engine) Swap(tokenIn string, tokenOut string, amountIn *big.Int) (*SwapResult, error) {
+	result, err := e.GetAmountOut(tokenIn, tokenOut, amountIn)
+	if err != nil {
+		return nil, err
+	}
+
+	e.IncreaseUsdgAmount(tokenIn, result.UsdgAmount)
+	e.DecreaseUsdgAmount(tokenOut, result.UsdgAmount)
+	e.IncreasePoolAmount(tokenIn, amountIn)
+	// the fee is kept in feeReserves, which is not part of poolAmounts
+	e.DecreasePoolAmount(tokenOut, result.AmountOut)
+
+	return result, nil
+}
+
+func (e *Engine) ValidateMaxUsdgAmount(token string, amount *big.Int) error {
+	maxUsdgAmount := e.vault.MaxUsdgAmounts[token]
+	if maxUsdgAmount == nil || maxUsdgAmount.Sign() == 0 {
+		return nil
+	}
+
+	newUsdgAmount := new(big.Int).Add(orZero(e.vault.UsdgAmounts[token]), amount)
+	if newUsdgAmount.Cmp(maxUsdgAmount) <= 0 {
+		return nil
+	}
+
+	return ErrVaultMaxUsdgExceeded
+}
+
+func (e *Engine) ValidateMinPoolAmount(token string, amount *big.Int) error {
+	currentPoolAmount := orZero(e.vault.PoolAmounts[token])
+	if currentPoolAmount.Cmp(amount) < 0 {
+		return ErrVaultPoolAmountExceeded
+	}
+
+	newPoolAmount := new(big.Int).Sub(currentPoolAmount, amount)
+	if orZero(e.vault.ReservedAmounts[token]).Cmp(newPoolAmount) > 0 {
+		return ErrVaultReserveExceedsPool
+	}
+
+	return nil
+}
+
+func (e *Engine) ValidateBufferAmount(token string, amount *big.Int) error {
+	newPoolAmount := new(big.Int).Sub(orZero(e.vault.PoolAmounts[token]), amount)
+	if newPoolAmount.Cmp(orZero(e.vault.BufferAmounts[token])) < 0 {
+		return ErrVaultPoolAmountLessThanBufferAmount
+	}
+
+	return nil
+}
+
+func (e *Engine) IncreaseUsdgAmount(token string, amount *big.Int) {
+	e.vault.UsdgAmounts[token] = new(big.Int).Add(orZero(e.vault.UsdgAmounts[token]), amount)
+}
+
+func (e *Engine) DecreaseUsdgAmount(token string, amount *big.Int) {
+	currentUsdgAmount := orZero(e.vault.UsdgAmounts[token])
+	if currentUsdgAmount.Cmp(amount) < 0 {
+		e.vault.UsdgAmounts[token] = new(big.Int)
+		return
+	}
+
+	e.vault.UsdgAmounts[token] = new(big.Int).Sub(currentUsdgAmount, amount)
+}
+
+func (e *Engine) IncreasePoolAmount(token string, amount *big.Int) {
+	e.vault.PoolAmounts[token] = new(big.Int).Add(orZero(e.vault.PoolAmounts[token]), amount)
+}
+
+func (e *Engine) DecreasePoolAmount(token string, amount *big.Int) {
+	e.vault.PoolAmounts[token] = new(big.Int).Sub(orZero(e.vault.PoolAmounts[token]), amount)
+}
+
+func orZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+
+	return v
+}
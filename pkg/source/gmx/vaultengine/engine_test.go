@@ -0,0 +1,133 @@
+package vaultengine
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	tokenA = "0xa"
+	tokenB = "0xb"
+	usdg   = "0xusdg"
+)
+
+type mockPriceFeed struct{}
+
+func (mockPriceFeed) GetMinPrice(_ string) (*big.Int, error) { return PricePrecision, nil }
+func (mockPriceFeed) GetMaxPrice(_ string) (*big.Int, error) { return PricePrecision, nil }
+func (mockPriceFeed) AdjustForDecimals(amount *big.Int, _ string, _ string) *big.Int {
+	return new(big.Int).Set(amount)
+}
+
+// newTestEngine returns an engine with two tokens of equal weight, priced 1 usd, with 1000 usdg of each token and
+// dynamic fees of 30 bps base and 50 bps tax.
+func newTestEngine() (*Engine, *Vault) {
+	vault := &Vault{
+		IsSwapEnabled:   true,
+		UsdgAddress:     usdg,
+		PoolAmounts:     map[string]*big.Int{tokenA: big.NewInt(1000), tokenB: big.NewInt(1000)},
+		BufferAmounts:   map[string]*big.Int{tokenA: big.NewInt(0), tokenB: big.NewInt(0)},
+		ReservedAmounts: map[string]*big.Int{tokenA: big.NewInt(0), tokenB: big.NewInt(0)},
+		UsdgAmounts:     map[string]*big.Int{tokenA: big.NewInt(1000), tokenB: big.NewInt(1000)},
+		MaxUsdgAmounts:  map[string]*big.Int{tokenA: big.NewInt(0), tokenB: big.NewInt(0)},
+	}
+
+	feeCalculator := FeeCalculatorFunc(func(tokenIn string, tokenOut string, usdgAmount *big.Int) (*big.Int, error) {
+		target := big.NewInt(1000)
+		fee0 := GetFeeBasisPoints(vault.UsdgAmounts[tokenIn], target, usdgAmount, big.NewInt(30), big.NewInt(50), true)
+		fee1 := GetFeeBasisPoints(vault.UsdgAmounts[tokenOut], target, usdgAmount, big.NewInt(30), big.NewInt(50), false)
+		if fee0.Cmp(fee1) > 0 {
+			return fee0, nil
+		}
+		return fee1, nil
+	})
+
+	return NewEngine(vault, mockPriceFeed{}, feeCalculator), vault
+}
+
+func TestEngine_Swap(t *testing.T) {
+	engine, vault := newTestEngine()
+
+	feeBefore, _ := engine.feeCalculator.GetSwapFeeBasisPoints(tokenA, tokenB, big.NewInt(500))
+	assert.Equal(t, big.NewInt(42), feeBefore)
+
+	result, err := engine.Swap(tokenA, tokenB, big.NewInt(100))
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(100), result.AmountOut)
+	// average diff is 50 of 1000, so the tax is 50 * 50 / 1000 = 2 bps
+	assert.Equal(t, big.NewInt(99), result.AmountOutAfterFees)
+
+	assert.Equal(t, big.NewInt(1100), vault.PoolAmounts[tokenA])
+	assert.Equal(t, big.NewInt(900), vault.PoolAmounts[tokenB])
+	assert.Equal(t, big.NewInt(1100), vault.UsdgAmounts[tokenA])
+	assert.Equal(t, big.NewInt(900), vault.UsdgAmounts[tokenB])
+
+	// the vault is now further from its targets, so the same swap pays a higher dynamic fee
+	feeAfter, _ := engine.feeCalculator.GetSwapFeeBasisPoints(tokenA, tokenB, big.NewInt(500))
+	assert.Equal(t, big.NewInt(47), feeAfter)
+}
+
+func TestEngine_Swap_Validations(t *testing.T) {
+	t.Run("it should return ErrVaultPoolAmountExceeded after the pool is drained by previous swaps", func(t *testing.T) {
+		engine, _ := newTestEngine()
+
+		_, err := engine.Swap(tokenA, tokenB, big.NewInt(600))
+		assert.Nil(t, err)
+
+		_, err = engine.GetAmountOut(tokenA, tokenB, big.NewInt(600))
+		assert.Equal(t, ErrVaultPoolAmountExceeded, err)
+	})
+
+	t.Run("it should return ErrVaultPoolAmountLessThanBufferAmount when the swap eats into the buffer", func(t *testing.T) {
+		engine, vault := newTestEngine()
+		vault.BufferAmounts[tokenB] = big.NewInt(850)
+
+		_, err := engine.Swap(tokenA, tokenB, big.NewInt(100))
+		assert.Nil(t, err)
+
+		_, err = engine.Swap(tokenA, tokenB, big.NewInt(100))
+		assert.Equal(t, ErrVaultPoolAmountLessThanBufferAmount, err)
+		assert.Equal(t, big.NewInt(900), vault.PoolAmounts[tokenB])
+	})
+
+	t.Run("it should return ErrVaultMaxUsdgExceeded when previous swaps fill the max usdg amount", func(t *testing.T) {
+		engine, vault := newTestEngine()
+		vault.MaxUsdgAmounts[tokenA] = big.NewInt(1150)
+
+		_, err := engine.Swap(tokenA, tokenB, big.NewInt(100))
+		assert.Nil(t, err)
+
+		_, err = engine.Swap(tokenA, tokenB, big.NewInt(100))
+		assert.Equal(t, ErrVaultMaxUsdgExceeded, err)
+	})
+
+	t.Run("it should allow a swap filling the max usdg amount exactly", func(t *testing.T) {
+		engine, vault := newTestEngine()
+		vault.MaxUsdgAmounts[tokenA] = big.NewInt(1100)
+
+		_, err := engine.Swap(tokenA, tokenB, big.NewInt(100))
+		assert.Nil(t, err)
+
+		_, err = engine.GetAmountOut(tokenA, tokenB, big.NewInt(1))
+		assert.Equal(t, ErrVaultMaxUsdgExceeded, err)
+	})
+
+	t.Run("it should return ErrVaultReserveExceedsPool when the reserved amount is not available", func(t *testing.T) {
+		engine, vault := newTestEngine()
+		vault.ReservedAmounts[tokenB] = big.NewInt(950)
+
+		_, err := engine.GetAmountOut(tokenA, tokenB, big.NewInt(100))
+		assert.Equal(t, ErrVaultReserveExceedsPool, err)
+	})
+}
+
+func TestGetFeeBasisPoints(t *testing.T) {
+	// moving towards the target gets a rebate
+	assert.Equal(t, big.NewInt(25), GetFeeBasisPoints(big.NewInt(1100), big.NewInt(1000), big.NewInt(100), big.NewInt(30), big.NewInt(50), false))
+	// no target means no dynamic fee
+	assert.Equal(t, big.NewInt(30), GetFeeBasisPoints(big.NewInt(1100), bignumber.ZeroBI, big.NewInt(100), big.NewInt(30), big.NewInt(50), true))
+}
@@ -0,0 +1,12 @@
+package vaultengine
+
+import "errors"
+
+var (
+	ErrVaultSwapsNotEnabled                = errors.New("vault: swaps not enabled")
+	ErrVaultMaxUsdgExceeded                = errors.New("vault: max USDG exceeded") // code: 51
+	ErrVaultPoolAmountExceeded             = errors.New("vault: poolAmount exceeded")
+	ErrVaultReserveExceedsPool             = errors.New("vault: reserve exceeds pool") // code: 50
+	ErrVaultPoolAmountLessThanBufferAmount = errors.New("vault: poolAmount < buffer")
+	ErrVaultInvalidTokens                  = errors.New("vault: invalid tokens")
+)
@@ -0,0 +1,55 @@
+package vaultengine
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// GetFeeBasisPoints returns the dynamic fee of changing the usdg amount of a token from initialAmount by usdgDelta,
+// given the target usdg amount of that token. It is the body of VaultUtils.getFeeBasisPoints after the
+// hasDynamicFees check, which is left to the callers since its source differs between the forks.
+// https://github.com/gmx-io/gmx-contracts/blob/master/contracts/core/VaultUtils.sol
+func GetFeeBasisPoints(
+	initialAmount *big.Int,
+	targetAmount *big.Int,
+	usdgDelta *big.Int,
+	feeBasisPoints *big.Int,
+	taxBasisPoints *big.Int,
+	increment bool,
+) *big.Int {
+	if targetAmount.Sign() == 0 {
+		return feeBasisPoints
+	}
+
+	nextAmount := new(big.Int).Add(initialAmount, usdgDelta)
+	if !increment {
+		if usdgDelta.Cmp(initialAmount) > 0 {
+			nextAmount = bignumber.ZeroBI
+		} else {
+			nextAmount = new(big.Int).Sub(initialAmount, usdgDelta)
+		}
+	}
+
+	initialDiff := new(big.Int).Abs(new(big.Int).Sub(initialAmount, targetAmount))
+	nextDiff := new(big.Int).Abs(new(big.Int).Sub(nextAmount, targetAmount))
+
+	if nextDiff.Cmp(initialDiff) < 0 {
+		rebateBps := new(big.Int).Div(new(big.Int).Mul(taxBasisPoints, initialDiff), targetAmount)
+
+		if rebateBps.Cmp(feeBasisPoints) > 0 {
+			return bignumber.ZeroBI
+		}
+
+		return new(big.Int).Sub(feeBasisPoints, rebateBps)
+	}
+
+	averageDiff := new(big.Int).Div(new(big.Int).Add(initialDiff, nextDiff), bignumber.Two)
+	if averageDiff.Cmp(targetAmount) > 0 {
+		averageDiff = targetAmount
+	}
+
+	taxBps := new(big.Int).Div(new(big.Int).Mul(taxBasisPoints, averageDiff), targetAmount)
+
+	return new(big.Int).Add(feeBasisPoints, taxBps)
+}
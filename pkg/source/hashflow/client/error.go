@@ -0,0 +1,10 @@
+package client
+
+import "errors"
+
+var (
+	ErrListMarketMakersFailed = errors.New("listMarketMakers failed")
+	ErrListPriceLevelsFailed  = errors.New("listPriceLevels failed")
+	ErrFirmQuoteFailed        = errors.New("firm quote failed")
+	ErrNoFirmQuote            = errors.New("no firm quote")
+)
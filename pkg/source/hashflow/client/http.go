@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/pkg/errors"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/hashflow"
+)
+
+const (
+	listMarketMakersEndpoint = "/taker/v3/market-makers"
+	listPriceLevelsEndpoint  = "/taker/v3/price-levels"
+	rfqEndpoint              = "/taker/v3/rfq"
+
+	statusSuccess = "success"
+)
+
+type rfqRequest struct {
+	BaseChain  hashflow.Chain   `json:"baseChain"`
+	QuoteChain hashflow.Chain   `json:"quoteChain"`
+	Source     string           `json:"source"`
+	RFQs       []rfqRequestItem `json:"rfqs"`
+}
+
+type rfqRequestItem struct {
+	BaseToken       string   `json:"baseToken"`
+	QuoteToken      string   `json:"quoteToken"`
+	BaseTokenAmount string   `json:"baseTokenAmount"`
+	Trader          string   `json:"trader"`
+	EffectiveTrader string   `json:"effectiveTrader"`
+	MarketMakers    []string `json:"marketMakers"`
+}
+
+type rfqResult struct {
+	Status string                `json:"status"`
+	RFQID  string                `json:"rfqId"`
+	Quotes []hashflow.FirmResult `json:"quotes"`
+	Error  *hashflow.APIError    `json:"error"`
+}
+
+type httpClient struct {
+	client *resty.Client
+	config *hashflow.Config
+}
+
+func NewHTTPClient(config *hashflow.Config) *httpClient {
+	client := resty.New().
+		SetBaseURL(config.HTTP.BaseURL).
+		SetTimeout(config.HTTP.Timeout.Duration).
+		SetRetryCount(config.HTTP.RetryCount).
+		SetHeader("Authorization", config.HTTP.APIKey)
+
+	return &httpClient{
+		client: client,
+		config: config,
+	}
+}
+
+func (c *httpClient) chainQueryParams() map[string]string {
+	return map[string]string{
+		"source":        c.config.Source,
+		"baseChainType": hashflow.ChainTypeEVM,
+		"baseChainId":   strconv.FormatUint(uint64(c.config.ChainID), 10),
+	}
+}
+
+func (c *httpClient) ListMarketMakers(ctx context.Context) ([]string, error) {
+	req := c.client.R().
+		SetContext(ctx).
+		SetQueryParams(c.chainQueryParams())
+
+	var result hashflow.ListMarketMakersResult
+	resp, err := req.SetResult(&result).Get(listMarketMakersEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, errors.Wrapf(ErrListMarketMakersFailed, "response status: %v, response error: %v", resp.Status(), resp.Error())
+	}
+
+	return result.MarketMakers, nil
+}
+
+func (c *httpClient) ListPriceLevels(ctx context.Context, marketMakers []string) (map[string][]hashflow.PairPriceLevels, error) {
+	req := c.client.R().
+		SetContext(ctx).
+		SetQueryParams(c.chainQueryParams()).
+		SetQueryParamsFromValues(map[string][]string{"marketMakers[]": marketMakers})
+
+	var result hashflow.ListPriceLevelsResult
+	resp, err := req.SetResult(&result).Get(listPriceLevelsEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsSuccess() {
+		return nil, errors.Wrapf(ErrListPriceLevelsFailed, "response status: %v, response error: %v", resp.Status(), resp.Error())
+	}
+	if result.Status != statusSuccess {
+		return nil, errors.Wrapf(ErrListPriceLevelsFailed, "response error: %+v", result.Error)
+	}
+
+	return result.Levels, nil
+}
+
+func (c *httpClient) Firm(ctx context.Context, params hashflow.FirmRequestParams) (hashflow.FirmResult, error) {
+	chain := hashflow.Chain{ChainType: hashflow.ChainTypeEVM, ChainID: c.config.ChainID}
+	req := c.client.R().
+		SetContext(ctx).
+		SetBody(rfqRequest{
+			BaseChain:  chain,
+			QuoteChain: chain,
+			Source:     c.config.Source,
+			RFQs: []rfqRequestItem{{
+				BaseToken:       params.BaseToken,
+				QuoteToken:      params.QuoteToken,
+				BaseTokenAmount: params.BaseTokenAmount,
+				Trader:          params.Trader,
+				EffectiveTrader: params.Trader,
+				MarketMakers:    []string{params.MarketMaker},
+			}},
+		})
+
+	var result rfqResult
+	resp, err := req.SetResult(&result).Post(rfqEndpoint)
+	if err != nil {
+		return hashflow.FirmResult{}, err
+	}
+
+	if !resp.IsSuccess() {
+		return hashflow.FirmResult{}, errors.Wrapf(ErrFirmQuoteFailed, "response status: %v, response error: %v", resp.Status(), resp.Error())
+	}
+	if result.Status != statusSuccess {
+		return hashflow.FirmResult{}, errors.Wrapf(ErrFirmQuoteFailed, "rfq id: %v, response error: %+v", result.RFQID, result.Error)
+	}
+	if len(result.Quotes) == 0 {
+		return hashflow.FirmResult{}, errors.Wrapf(ErrNoFirmQuote, "rfq id: %v", result.RFQID)
+	}
+
+	return result.Quotes[0], nil
+}
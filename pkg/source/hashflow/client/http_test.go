@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/hashflow"
+)
+
+const testAPIKey = "test-key"
+
+// newTestServer is a local stand-in of the taker API serving the given handlers after checking the common params
+func newTestServer(t *testing.T, handlers map[string]http.HandlerFunc) *httpClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, testAPIKey, r.Header.Get("Authorization"))
+
+		handler, ok := handlers[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodGet {
+			assert.Equal(t, "kyberswap", r.URL.Query().Get("source"))
+			assert.Equal(t, hashflow.ChainTypeEVM, r.URL.Query().Get("baseChainType"))
+			assert.Equal(t, "1", r.URL.Query().Get("baseChainId"))
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return NewHTTPClient(&hashflow.Config{
+		ChainID: 1,
+		Source:  "kyberswap",
+		HTTP:    hashflow.HTTPConfig{BaseURL: server.URL, APIKey: testAPIKey},
+	})
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	require.NoError(t, json.NewEncoder(w).Encode(v))
+}
+
+func TestHTTPClient_ListMarketMakers(t *testing.T) {
+	c := newTestServer(t, map[string]http.HandlerFunc{
+		listMarketMakersEndpoint: func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, hashflow.ListMarketMakersResult{MarketMakers: []string{"mm1", "mm2"}})
+		},
+	})
+
+	marketMakers, err := c.ListMarketMakers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mm1", "mm2"}, marketMakers)
+}
+
+func TestHTTPClient_ListPriceLevels(t *testing.T) {
+	levels := map[string][]hashflow.PairPriceLevels{
+		"mm1": {{
+			Pair:   hashflow.Pair{BaseToken: "0x1", QuoteToken: "0x2", BaseTokenDecimals: 18, QuoteTokenDecimals: 6},
+			Levels: []hashflow.Level{{Q: "0.1", P: "1800"}, {Q: "1", P: "1800"}},
+		}},
+	}
+	c := newTestServer(t, map[string]http.HandlerFunc{
+		listPriceLevelsEndpoint: func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("marketMakers[]") == "unknown" {
+				writeJSON(t, w, hashflow.ListPriceLevelsResult{
+					Status: "fail",
+					Error:  &hashflow.APIError{Code: "InvalidMarketMaker", Message: "unknown market maker"},
+				})
+				return
+			}
+
+			assert.Equal(t, []string{"mm1", "mm2"}, r.URL.Query()["marketMakers[]"])
+			writeJSON(t, w, hashflow.ListPriceLevelsResult{Status: statusSuccess, Levels: levels})
+		},
+	})
+
+	result, err := c.ListPriceLevels(context.Background(), []string{"mm1", "mm2"})
+	require.NoError(t, err)
+	assert.Equal(t, levels, result)
+
+	_, err = c.ListPriceLevels(context.Background(), []string{"unknown"})
+	assert.ErrorIs(t, err, ErrListPriceLevelsFailed)
+}
+
+func TestHTTPClient_Firm(t *testing.T) {
+	quote := hashflow.FirmResult{
+		QuoteData: hashflow.QuoteData{
+			Pool:             "0x0000000000000000000000000000000000000002",
+			Trader:           "0x0000000000000000000000000000000000000001",
+			BaseToken:        "0x1",
+			QuoteToken:       "0x2",
+			BaseTokenAmount:  "1000",
+			QuoteTokenAmount: "1800",
+			QuoteExpiry:      1700000000,
+		},
+		Signature: "0x1234",
+	}
+	var quotes []hashflow.FirmResult
+	c := newTestServer(t, map[string]http.HandlerFunc{
+		rfqEndpoint: func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+
+			var body rfqRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			chain := hashflow.Chain{ChainType: hashflow.ChainTypeEVM, ChainID: 1}
+			assert.Equal(t, rfqRequest{
+				BaseChain:  chain,
+				QuoteChain: chain,
+				Source:     "kyberswap",
+				RFQs: []rfqRequestItem{{
+					BaseToken:       "0x1",
+					QuoteToken:      "0x2",
+					BaseTokenAmount: "1000",
+					Trader:          "0x0000000000000000000000000000000000000001",
+					EffectiveTrader: "0x0000000000000000000000000000000000000001",
+					MarketMakers:    []string{"mm1"},
+				}},
+			}, body)
+
+			writeJSON(t, w, rfqResult{Status: statusSuccess, RFQID: "rfq-1", Quotes: quotes})
+		},
+	})
+	params := hashflow.FirmRequestParams{
+		MarketMaker:     "mm1",
+		BaseToken:       "0x1",
+		QuoteToken:      "0x2",
+		BaseTokenAmount: "1000",
+		Trader:          "0x0000000000000000000000000000000000000001",
+	}
+
+	quotes = []hashflow.FirmResult{quote}
+	result, err := c.Firm(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, quote, result)
+
+	// The market maker declined
+	quotes = nil
+	_, err = c.Firm(context.Background(), params)
+	assert.ErrorIs(t, err, ErrNoFirmQuote)
+}
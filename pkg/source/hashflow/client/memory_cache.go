@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/dgraph-io/ristretto"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/hashflow"
+)
+
+const (
+	defaultNumCounts   = 5000
+	defaultMaxCost     = 500
+	defaultBufferItems = 64
+
+	defaultSingleItemCost = 1
+
+	cacheKeyMarketMakers      = "market-makers"
+	cacheKeyPriceLevelsPrefix = "price-levels:"
+)
+
+type memoryCacheClient struct {
+	config         *hashflow.MemoryCacheConfig
+	cache          *ristretto.Cache
+	fallbackClient hashflow.IClient
+}
+
+func NewMemoryCacheClient(
+	config *hashflow.MemoryCacheConfig,
+	fallbackClient hashflow.IClient,
+) *memoryCacheClient {
+	cache, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: defaultNumCounts,
+		MaxCost:     defaultMaxCost,
+		BufferItems: defaultBufferItems,
+	})
+	if err != nil {
+		logger.Errorf("failed to init memory cache, err %v", err.Error())
+	}
+
+	return &memoryCacheClient{
+		config:         config,
+		cache:          cache,
+		fallbackClient: fallbackClient,
+	}
+}
+
+func (c *memoryCacheClient) ListMarketMakers(ctx context.Context) ([]string, error) {
+	cachedMarketMakers, err := c.listMarketMakersFromCache()
+	if err == nil {
+		return cachedMarketMakers, nil
+	}
+
+	// Cache missed. Using fallbackClient
+	marketMakers, err := c.fallbackClient.ListMarketMakers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache.SetWithTTL(cacheKeyMarketMakers, marketMakers, defaultSingleItemCost, c.config.TTL.MarketMakers.Duration)
+	c.cache.Wait()
+
+	return marketMakers, nil
+}
+
+// listMarketMakersFromCache only returns if market makers are able to fetch from cache
+func (c *memoryCacheClient) listMarketMakersFromCache() ([]string, error) {
+	cachedMarketMakers, found := c.cache.Get(cacheKeyMarketMakers)
+	if !found {
+		return nil, errors.New("no market makers data in cache")
+	}
+
+	return cachedMarketMakers.([]string), nil
+}
+
+// ListPriceLevels caches the levels of every market maker on its own, so the pools of a market maker share them
+// whatever market makers they were requested with
+func (c *memoryCacheClient) ListPriceLevels(
+	ctx context.Context,
+	marketMakers []string,
+) (map[string][]hashflow.PairPriceLevels, error) {
+	result := make(map[string][]hashflow.PairPriceLevels, len(marketMakers))
+	var missedMarketMakers []string
+	for _, marketMaker := range marketMakers {
+		cachedPriceLevels, found := c.cache.Get(cacheKeyPriceLevelsPrefix + marketMaker)
+		if !found {
+			missedMarketMakers = append(missedMarketMakers, marketMaker)
+			continue
+		}
+		result[marketMaker] = cachedPriceLevels.([]hashflow.PairPriceLevels)
+	}
+	if len(missedMarketMakers) == 0 {
+		return result, nil
+	}
+
+	// Cache missed. Using fallbackClient
+	priceLevels, err := c.fallbackClient.ListPriceLevels(ctx, missedMarketMakers)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, marketMaker := range missedMarketMakers {
+		// Market makers without levels are cached too, they are not quoting any pair at the moment
+		pairs := priceLevels[marketMaker]
+		c.cache.SetWithTTL(cacheKeyPriceLevelsPrefix+marketMaker, pairs, defaultSingleItemCost,
+			c.config.TTL.PriceLevels.Duration)
+		result[marketMaker] = pairs
+	}
+	c.cache.Wait()
+
+	return result, nil
+}
+
+func (c *memoryCacheClient) Firm(ctx context.Context, params hashflow.FirmRequestParams) (hashflow.FirmResult, error) {
+	return c.fallbackClient.Firm(ctx, params)
+}
@@ -0,0 +1,36 @@
+package hashflow
+
+import (
+	"github.com/KyberNetwork/blockchain-toolkit/time/durationjson"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+)
+
+type Config struct {
+	DexID   string `json:"dexID,omitempty"`
+	ChainID uint   `mapstructure:"chain_id" json:"chain_id,omitempty"`
+	// Source identifies the aggregator to the API
+	Source string `mapstructure:"source" json:"source,omitempty"`
+	// MarketMakers limits the makers that are routed through, every maker of the chain is used when empty
+	MarketMakers []string          `mapstructure:"market_makers" json:"market_makers,omitempty"`
+	HTTP         HTTPConfig        `mapstructure:"http" json:"http,omitempty"`
+	MemoryCache  MemoryCacheConfig `mapstructure:"memory_cache" json:"memory_cache,omitempty"`
+	RFQ          rfq.Config        `mapstructure:"rfq" json:"rfq,omitempty"`
+	// Signers are the quote signers by market maker, a firm quote signed by another key or of a market maker without
+	// a signer is rejected
+	Signers map[string]string `mapstructure:"signers" json:"signers,omitempty"`
+}
+
+type HTTPConfig struct {
+	BaseURL    string                `mapstructure:"base_url" json:"base_url,omitempty"`
+	APIKey     string                `mapstructure:"api_key" json:"api_key,omitempty"`
+	Timeout    durationjson.Duration `mapstructure:"timeout" json:"timeout,omitempty"`
+	RetryCount int                   `mapstructure:"retry_count" json:"retry_count,omitempty"`
+}
+
+type MemoryCacheConfig struct {
+	TTL struct {
+		MarketMakers durationjson.Duration `mapstructure:"market_makers" json:"market_makers,omitempty"`
+		PriceLevels  durationjson.Duration `mapstructure:"price_levels" json:"price_levels,omitempty"`
+	} `mapstructure:"ttl"`
+}
@@ -0,0 +1,14 @@
+package hashflow
+
+const (
+	DexTypeHashflow = "hashflow"
+
+	PoolIDPrefix    = "hashflow"
+	PoolIDSeparator = "_"
+
+	ChainTypeEVM = "evm"
+)
+
+var (
+	DefaultGas = Gas{Swap: 130000}
+)
@@ -0,0 +1,16 @@
+package hashflow
+
+import (
+	"errors"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+)
+
+var (
+	ErrNoPriceLevelsForPool   = errors.New("no price levels for pool")
+	ErrEmptyPriceLevels       = rfq.ErrEmptyPriceLevels
+	ErrInsufficientLiquidity  = rfq.ErrInsufficientLiquidity
+	ErrAmountInTooSmall       = errors.New("amount in is below the minimum of the market maker")
+	ErrInvalidFirmQuoteParams = errors.New("invalid firm quote params")
+	ErrSignerNotConfigured    = errors.New("no quote signer configured for the market maker")
+)
@@ -0,0 +1,10 @@
+package hashflow
+
+import "context"
+
+type IClient interface {
+	ListMarketMakers(ctx context.Context) ([]string, error)
+	// ListPriceLevels returns the indicative levels of every pair of the market makers, keyed by market maker
+	ListPriceLevels(ctx context.Context, marketMakers []string) (map[string][]PairPriceLevels, error)
+	Firm(ctx context.Context, params FirmRequestParams) (FirmResult, error)
+}
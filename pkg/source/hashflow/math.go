@@ -0,0 +1,58 @@
+package hashflow
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+)
+
+// toPriceLevels converts the cumulative levels of the API into the amount sold at each price
+func toPriceLevels(levels []Level) (PriceLevels, bool) {
+	if len(levels) == 0 {
+		return PriceLevels{}, false
+	}
+
+	priceLevels := PriceLevels{Levels: make([]PriceLevel, 0, len(levels))}
+	prevQuantity := new(big.Rat)
+	for i, level := range levels {
+		quantity, ok := new(big.Rat).SetString(level.Q)
+		if !ok || quantity.Sign() < 0 {
+			return PriceLevels{}, false
+		}
+		price, ok := new(big.Rat).SetString(level.P)
+		if !ok || price.Sign() < 0 {
+			return PriceLevels{}, false
+		}
+		if i == 0 {
+			priceLevels.MinAmountIn = quantity
+		}
+		if quantity.Cmp(prevQuantity) <= 0 {
+			continue
+		}
+
+		priceLevels.Levels = append(priceLevels.Levels, PriceLevel{
+			Price:  price,
+			Amount: new(big.Rat).Sub(quantity, prevQuantity),
+		})
+		prevQuantity = quantity
+	}
+
+	return priceLevels, len(priceLevels.Levels) > 0
+}
+
+// getAmountOut fills amountIn from the front of the order book, the market maker does not quote below the first level
+func getAmountOut(amountIn *big.Rat, priceLevels PriceLevels) (*big.Rat, error) {
+	if len(priceLevels.Levels) > 0 && priceLevels.MinAmountIn != nil && amountIn.Cmp(priceLevels.MinAmountIn) < 0 {
+		return nil, ErrAmountInTooSmall
+	}
+
+	return rfq.GetAmountOut(amountIn, priceLevels.Levels)
+}
+
+// getNewPriceLevelsState removes amountIn from the front of the order book, see rfq.FillPriceLevels
+func getNewPriceLevelsState(amountIn *big.Rat, priceLevels PriceLevels) PriceLevels {
+	return PriceLevels{
+		MinAmountIn: priceLevels.MinAmountIn,
+		Levels:      rfq.FillPriceLevels(amountIn, priceLevels.Levels),
+	}
+}
@@ -0,0 +1,120 @@
+package hashflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/KyberNetwork/blockchain-toolkit/integer"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+type PoolSimulator struct {
+	pool.Pool
+	token0               entity.PoolToken
+	token1               entity.PoolToken
+	marketMaker          string
+	zeroToOnePriceLevels PriceLevels
+	oneToZeroPriceLevels PriceLevels
+	gas                  Gas
+	timestamp            int64
+}
+
+func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
+	if len(entityPool.Tokens) != 2 || len(entityPool.Reserves) != 2 {
+		return nil, fmt.Errorf("pool's number of tokens should equal 2")
+	}
+
+	var staticExtra StaticExtra
+	if err := json.Unmarshal([]byte(entityPool.StaticExtra), &staticExtra); err != nil {
+		return nil, err
+	}
+
+	var extra Extra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	return &PoolSimulator{
+		Pool: pool.Pool{
+			Info: pool.PoolInfo{
+				Address:    strings.ToLower(entityPool.Address),
+				ReserveUsd: entityPool.ReserveUsd,
+				SwapFee:    integer.Zero(), // fee is added in the price levels already
+				Exchange:   entityPool.Exchange,
+				Type:       entityPool.Type,
+				Tokens:     []string{entityPool.Tokens[0].Address, entityPool.Tokens[1].Address},
+				Reserves: []*big.Int{
+					bignumber.NewBig10(entityPool.Reserves[0]),
+					bignumber.NewBig10(entityPool.Reserves[1]),
+				},
+				Checked: false,
+			},
+		},
+		token0:               *entityPool.Tokens[0],
+		token1:               *entityPool.Tokens[1],
+		marketMaker:          staticExtra.MarketMaker,
+		zeroToOnePriceLevels: extra.ZeroToOnePriceLevels,
+		oneToZeroPriceLevels: extra.OneToZeroPriceLevels,
+		gas:                  DefaultGas,
+		timestamp:            entityPool.Timestamp,
+	}, nil
+}
+
+func (p *PoolSimulator) CalcAmountOut(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	tokenIn, tokenOutInfo, priceLevels := p.getDirection(tokenAmountIn.Token)
+
+	amountOutAfterDecimals, err := getAmountOut(rfq.ToDecimalAmount(tokenAmountIn.Amount, tokenIn.Decimals), priceLevels)
+	if err != nil {
+		return nil, err
+	}
+
+	amountOut := rfq.ToWeiAmount(amountOutAfterDecimals, tokenOutInfo.Decimals)
+
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{Token: tokenOut, Amount: amountOut},
+		Fee:            &pool.TokenAmount{Token: tokenAmountIn.Token, Amount: integer.Zero()},
+		Gas:            p.gas.Swap,
+		SwapInfo: SwapExtra{
+			MarketMaker:      p.marketMaker,
+			BaseToken:        tokenAmountIn.Token,
+			BaseTokenAmount:  tokenAmountIn.Amount.String(),
+			QuoteToken:       tokenOut,
+			QuoteTokenAmount: amountOut.String(),
+		},
+	}, nil
+}
+
+func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	tokenIn, _, priceLevels := p.getDirection(params.TokenAmountIn.Token)
+	newPriceLevels := getNewPriceLevelsState(rfq.ToDecimalAmount(params.TokenAmountIn.Amount, tokenIn.Decimals), priceLevels)
+
+	if strings.EqualFold(params.TokenAmountIn.Token, p.token0.Address) {
+		p.zeroToOnePriceLevels = newPriceLevels
+	} else {
+		p.oneToZeroPriceLevels = newPriceLevels
+	}
+}
+
+func (p *PoolSimulator) GetMetaInfo(_ string, _ string) interface{} {
+	return RFQMeta{
+		Timestamp: p.timestamp,
+	}
+}
+
+// getDirection returns the token in, the token out and the order book of a swap from tokenIn
+func (p *PoolSimulator) getDirection(tokenIn string) (entity.PoolToken, entity.PoolToken, PriceLevels) {
+	if strings.EqualFold(tokenIn, p.token0.Address) {
+		return p.token0, p.token1, p.zeroToOnePriceLevels
+	}
+
+	return p.token1, p.token0, p.oneToZeroPriceLevels
+}
@@ -0,0 +1,157 @@
+package hashflow
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	testUSDC        = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	testWETH        = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+	testMarketMaker = "mm1"
+)
+
+// testPairs are the levels of the pool: 0.1 WETH minimum, 1 WETH at 1800 then 1 WETH at 1790, and 1000 USDC
+// minimum, 3000 USDC at 0.0005
+func testPairs() []PairPriceLevels {
+	weth := Pair{BaseToken: testWETH, QuoteToken: testUSDC, BaseTokenDecimals: 18, QuoteTokenDecimals: 6}
+	usdc := Pair{BaseToken: testUSDC, QuoteToken: testWETH, BaseTokenDecimals: 6, QuoteTokenDecimals: 18}
+
+	return []PairPriceLevels{
+		{Pair: weth, Levels: []Level{{Q: "0.1", P: "1800"}, {Q: "1", P: "1800"}, {Q: "2", P: "1790"}}},
+		{Pair: usdc, Levels: []Level{{Q: "1000", P: "0.0005"}, {Q: "3000", P: "0.0005"}}},
+	}
+}
+
+func newTestPoolSimulator(t *testing.T) *PoolSimulator {
+	tokens := []*entity.PoolToken{
+		{Address: testUSDC, Decimals: 6, Swappable: true},
+		{Address: testWETH, Decimals: 18, Swappable: true},
+	}
+	extra, err := getExtra(tokens, testPairs())
+	require.NoError(t, err)
+	extraBytes, err := json.Marshal(extra)
+	require.NoError(t, err)
+	staticExtraBytes, err := json.Marshal(StaticExtra{MarketMaker: testMarketMaker})
+	require.NoError(t, err)
+
+	p, err := NewPoolSimulator(entity.Pool{
+		Address:     getPoolID(testMarketMaker, testUSDC, testWETH),
+		Exchange:    DexTypeHashflow,
+		Type:        DexTypeHashflow,
+		Reserves:    entity.PoolReserves{"0", "0"},
+		Tokens:      tokens,
+		StaticExtra: string(staticExtraBytes),
+		Extra:       string(extraBytes),
+	})
+	require.NoError(t, err)
+
+	return p
+}
+
+func TestPoolSimulator_CalcAmountOut(t *testing.T) {
+	p := newTestPoolSimulator(t)
+
+	testCases := []struct {
+		name      string
+		tokenIn   string
+		tokenOut  string
+		amountIn  *big.Int
+		amountOut string
+		err       error
+	}{
+		{
+			name:      "within first level",
+			tokenIn:   testWETH,
+			tokenOut:  testUSDC,
+			amountIn:  bignumber.TenPowInt(17),
+			amountOut: "180000000",
+		},
+		{
+			name:      "across levels",
+			tokenIn:   testWETH,
+			tokenOut:  testUSDC,
+			amountIn:  big.NewInt(1.5e18),
+			amountOut: "2695000000",
+		},
+		{
+			name:     "below minimum",
+			tokenIn:  testWETH,
+			tokenOut: testUSDC,
+			amountIn: big.NewInt(1e16),
+			err:      ErrAmountInTooSmall,
+		},
+		{
+			name:     "above liquidity",
+			tokenIn:  testWETH,
+			tokenOut: testUSDC,
+			amountIn: big.NewInt(2.5e18),
+			err:      ErrInsufficientLiquidity,
+		},
+		{
+			name:      "other direction",
+			tokenIn:   testUSDC,
+			tokenOut:  testWETH,
+			amountIn:  big.NewInt(2000e6),
+			amountOut: "1000000000000000000",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := p.CalcAmountOut(pool.TokenAmount{Token: tc.tokenIn, Amount: tc.amountIn}, tc.tokenOut)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.amountOut, result.TokenAmountOut.Amount.String())
+			assert.Equal(t, SwapExtra{
+				MarketMaker:      testMarketMaker,
+				BaseToken:        tc.tokenIn,
+				BaseTokenAmount:  tc.amountIn.String(),
+				QuoteToken:       tc.tokenOut,
+				QuoteTokenAmount: tc.amountOut,
+			}, result.SwapInfo)
+		})
+	}
+}
+
+func TestPoolSimulator_UpdateBalance(t *testing.T) {
+	p := newTestPoolSimulator(t)
+	other := newTestPoolSimulator(t)
+
+	tokenAmountIn := pool.TokenAmount{Token: testWETH, Amount: big.NewInt(1.5e18)}
+	result, err := p.CalcAmountOut(tokenAmountIn, testUSDC)
+	require.NoError(t, err)
+	p.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  tokenAmountIn,
+		TokenAmountOut: *result.TokenAmountOut,
+	})
+
+	// Only 0.5 WETH at 1790 is left
+	result, err = p.CalcAmountOut(pool.TokenAmount{Token: testWETH, Amount: big.NewInt(0.5e18)}, testUSDC)
+	require.NoError(t, err)
+	assert.Equal(t, "895000000", result.TokenAmountOut.Amount.String())
+
+	_, err = p.CalcAmountOut(pool.TokenAmount{Token: testWETH, Amount: big.NewInt(0.6e18)}, testUSDC)
+	assert.ErrorIs(t, err, ErrInsufficientLiquidity)
+
+	// The other direction and other simulators are untouched
+	result, err = p.CalcAmountOut(pool.TokenAmount{Token: testUSDC, Amount: big.NewInt(2000e6)}, testWETH)
+	require.NoError(t, err)
+	assert.Equal(t, "1000000000000000000", result.TokenAmountOut.Amount.String())
+
+	result, err = other.CalcAmountOut(tokenAmountIn, testUSDC)
+	require.NoError(t, err)
+	assert.Equal(t, "2695000000", result.TokenAmountOut.Amount.String())
+}
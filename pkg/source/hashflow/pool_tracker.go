@@ -0,0 +1,117 @@
+package hashflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/logger"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+)
+
+type PoolTracker struct {
+	config *Config
+	client IClient
+}
+
+func NewPoolTracker(cfg *Config, client IClient) *PoolTracker {
+	return &PoolTracker{
+		config: cfg,
+		client: client,
+	}
+}
+
+func (t *PoolTracker) GetNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	_ pool.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	logger.Infof("[Hashflow] Start getting new states for pool %v", p.Address)
+
+	if len(p.Tokens) != 2 {
+		err := errors.New("number of tokens should be 2")
+		logger.Errorf(err.Error())
+
+		return entity.Pool{}, err
+	}
+
+	var staticExtra StaticExtra
+	if err := json.Unmarshal([]byte(p.StaticExtra), &staticExtra); err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to unmarshal static extra data")
+		return entity.Pool{}, err
+	}
+
+	priceLevels, err := t.client.ListPriceLevels(ctx, []string{staticExtra.MarketMaker})
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to get price levels")
+		return entity.Pool{}, err
+	}
+
+	extra, err := getExtra(p.Tokens, priceLevels[staticExtra.MarketMaker])
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to get price levels for pool")
+		return entity.Pool{}, err
+	}
+
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to marshal extra data")
+		return entity.Pool{}, err
+	}
+
+	// The reserve of a token is what the market maker pays out of it for the whole order book
+	p.Reserves = entity.PoolReserves{
+		rfq.ToWeiAmount(rfq.GetTotalAmountOut(extra.OneToZeroPriceLevels.Levels), p.Tokens[0].Decimals).String(),
+		rfq.ToWeiAmount(rfq.GetTotalAmountOut(extra.ZeroToOnePriceLevels.Levels), p.Tokens[1].Decimals).String(),
+	}
+	p.Extra = string(extraBytes)
+	p.Timestamp = time.Now().Unix()
+	logger.Infof("[Hashflow] Finish getting new states for pool %v", p.Address)
+
+	return p, nil
+}
+
+// getExtra finds the two directions of the pool in the pairs of its market maker, a pool may only be quoted in one
+// of them
+func getExtra(tokens []*entity.PoolToken, pairs []PairPriceLevels) (Extra, error) {
+	var (
+		extra Extra
+		found bool
+	)
+	for _, pairLevels := range pairs {
+		baseToken, quoteToken := pairLevels.Pair.BaseToken, pairLevels.Pair.QuoteToken
+		priceLevels, ok := toPriceLevels(pairLevels.Levels)
+		if !ok {
+			continue
+		}
+
+		if strings.EqualFold(baseToken, tokens[0].Address) && strings.EqualFold(quoteToken, tokens[1].Address) {
+			extra.ZeroToOnePriceLevels, found = priceLevels, true
+		} else if strings.EqualFold(baseToken, tokens[1].Address) && strings.EqualFold(quoteToken, tokens[0].Address) {
+			extra.OneToZeroPriceLevels, found = priceLevels, true
+		}
+	}
+
+	if !found {
+		return Extra{}, ErrNoPriceLevelsForPool
+	}
+
+	return extra, nil
+}
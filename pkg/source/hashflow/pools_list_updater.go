@@ -0,0 +1,145 @@
+package hashflow
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/logger"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+)
+
+type PoolsListUpdater struct {
+	config Config
+	client IClient
+}
+
+func NewPoolsListUpdater(cfg Config, client IClient) *PoolsListUpdater {
+	return &PoolsListUpdater{
+		config: cfg,
+		client: client,
+	}
+}
+
+func (u *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte) ([]entity.Pool, []byte, error) {
+	marketMakers, err := u.getMarketMakers(ctx)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"error": err,
+		}).Errorf("can not list all market makers")
+		return nil, metadataBytes, err
+	}
+
+	if len(marketMakers) == 0 {
+		return nil, metadataBytes, nil
+	}
+
+	priceLevels, err := u.client.ListPriceLevels(ctx, marketMakers)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"error": err,
+		}).Errorf("can not list price levels")
+		return nil, metadataBytes, err
+	}
+
+	var pools []entity.Pool
+	for _, marketMaker := range marketMakers {
+		pools = append(pools, u.extractPools(marketMaker, priceLevels[marketMaker])...)
+	}
+
+	if len(pools) > 0 {
+		logger.Infof("[Hashflow] got total %v pools", len(pools))
+	}
+
+	return pools, metadataBytes, nil
+}
+
+// extractPools returns a pool for every pair of tokens of the market maker, the two directions of a pair are listed
+// separately by the API and share the pool
+func (u *PoolsListUpdater) extractPools(marketMaker string, pairs []PairPriceLevels) []entity.Pool {
+	seen := make(map[string]struct{}, len(pairs))
+	result := make([]entity.Pool, 0, len(pairs))
+	for _, pairLevels := range pairs {
+		newPool, err := u.transformToPool(marketMaker, pairLevels.Pair)
+		if err != nil {
+			logger.Errorf("failed to convert %v to pool, err: %v", pairLevels.Pair, err)
+			continue
+		}
+		if _, ok := seen[newPool.Address]; ok {
+			continue
+		}
+		seen[newPool.Address] = struct{}{}
+
+		result = append(result, newPool)
+	}
+
+	return result
+}
+
+func (u *PoolsListUpdater) transformToPool(marketMaker string, pair Pair) (entity.Pool, error) {
+	staticExtraBytes, err := json.Marshal(StaticExtra{MarketMaker: marketMaker})
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"error": err,
+		}).Errorf("failed to marshal static extra data")
+		return entity.Pool{}, err
+	}
+
+	tokens := []*entity.PoolToken{
+		{
+			Address:   strings.ToLower(pair.BaseToken),
+			Decimals:  pair.BaseTokenDecimals,
+			Swappable: true,
+			Symbol:    pair.BaseTokenName,
+		},
+		{
+			Address:   strings.ToLower(pair.QuoteToken),
+			Decimals:  pair.QuoteTokenDecimals,
+			Swappable: true,
+			Symbol:    pair.QuoteTokenName,
+		},
+	}
+	if tokens[0].Address > tokens[1].Address {
+		tokens[0], tokens[1] = tokens[1], tokens[0]
+	}
+
+	return entity.Pool{
+		Address:     getPoolID(marketMaker, tokens[0].Address, tokens[1].Address),
+		Exchange:    u.config.DexID,
+		Type:        DexTypeHashflow,
+		Timestamp:   time.Now().Unix(),
+		Reserves:    entity.PoolReserves{"0", "0"},
+		Tokens:      tokens,
+		StaticExtra: string(staticExtraBytes),
+	}, nil
+}
+
+func getPoolID(marketMaker, token0, token1 string) string {
+	return strings.Join([]string{PoolIDPrefix, strings.ToLower(marketMaker), token0, token1}, PoolIDSeparator)
+}
+
+// getMarketMakers returns the market makers of the chain, filtered by the configured ones
+func (u *PoolsListUpdater) getMarketMakers(ctx context.Context) ([]string, error) {
+	marketMakers, err := u.client.ListMarketMakers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(u.config.MarketMakers) == 0 {
+		return marketMakers, nil
+	}
+
+	included := make(map[string]struct{}, len(u.config.MarketMakers))
+	for _, marketMaker := range u.config.MarketMakers {
+		included[marketMaker] = struct{}{}
+	}
+	result := make([]string, 0, len(marketMakers))
+	for _, marketMaker := range marketMakers {
+		if _, ok := included[marketMaker]; ok {
+			result = append(result, marketMaker)
+		}
+	}
+
+	return result, nil
+}
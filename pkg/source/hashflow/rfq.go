@@ -0,0 +1,184 @@
+package hashflow
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/blockchain-toolkit/account"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+)
+
+// RFQHandler is the rfq maker of the Hashflow market makers, the firm quote is signed by the market maker the swap
+// was simulated with
+type RFQHandler struct {
+	config  *Config
+	client  IClient
+	handler *rfq.Handler
+}
+
+func NewRFQHandler(config *Config, client IClient) *RFQHandler {
+	h := &RFQHandler{
+		config: config,
+		client: client,
+	}
+	h.handler = rfq.NewHandler(config.RFQ, parseRequest, h)
+
+	return h
+}
+
+func (h *RFQHandler) RFQ(ctx context.Context, recipient string, params any) (pool.RFQResult, error) {
+	return h.handler.RFQ(ctx, recipient, params)
+}
+
+func (h *RFQHandler) Name() string {
+	return h.config.DexID
+}
+
+func (h *RFQHandler) Firm(ctx context.Context, req rfq.Request) (rfq.Quote, error) {
+	swapExtra, ok := req.Params.(SwapExtra)
+	if !ok {
+		return rfq.Quote{}, ErrInvalidFirmQuoteParams
+	}
+
+	signer, ok := h.config.Signers[swapExtra.MarketMaker]
+	if !ok || signer == "" {
+		logger.WithFields(logger.Fields{
+			"marketMaker": swapExtra.MarketMaker,
+		}).Warn("no quote signer configured for the market maker")
+		return rfq.Quote{}, ErrSignerNotConfigured
+	}
+
+	result, err := h.client.Firm(ctx, FirmRequestParams{
+		MarketMaker:     swapExtra.MarketMaker,
+		BaseToken:       req.TokenIn,
+		QuoteToken:      req.TokenOut,
+		BaseTokenAmount: req.AmountIn.String(),
+		Trader:          req.Taker,
+	})
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"params": req.Params,
+			"error":  err,
+		}).Errorf("failed to get firm quote")
+		return rfq.Quote{}, err
+	}
+
+	amountOut, err := validateQuote(h.config.ChainID, signer, req, result, time.Now())
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"marketMaker": swapExtra.MarketMaker,
+			"txid":        result.QuoteData.TxID,
+			"error":       err,
+		}).Errorf("invalid firm quote")
+		return rfq.Quote{}, err
+	}
+
+	return rfq.Quote{
+		Maker:     swapExtra.MarketMaker,
+		AmountOut: amountOut,
+		Expiry:    time.Unix(result.QuoteData.QuoteExpiry, 0),
+		Extra: RFQExtra{
+			MarketMaker: swapExtra.MarketMaker,
+			QuoteData:   result.QuoteData,
+			Signature:   result.Signature,
+			GasEstimate: result.GasEstimate,
+		},
+	}, nil
+}
+
+// validateQuote checks the signed quote is for the requested swap and returns its amount out. The signature is
+// recovered from the RFQ-T quote hash of the pool and must be the one of the signer of the market maker.
+func validateQuote(chainID uint, signer string, req rfq.Request, result FirmResult, now time.Time) (*big.Int, error) {
+	quote := result.QuoteData
+	if !strings.EqualFold(quote.BaseToken, req.TokenIn) || !strings.EqualFold(quote.QuoteToken, req.TokenOut) ||
+		!strings.EqualFold(quote.Trader, req.Taker) || !common.IsHexAddress(quote.Pool) {
+		return nil, rfq.ErrQuoteMismatch
+	}
+
+	baseTokenAmount, ok := new(big.Int).SetString(quote.BaseTokenAmount, 10)
+	if !ok || baseTokenAmount.Cmp(req.AmountIn) != 0 {
+		return nil, rfq.ErrQuoteMismatch
+	}
+	quoteTokenAmount, ok := new(big.Int).SetString(quote.QuoteTokenAmount, 10)
+	if !ok || quoteTokenAmount.Sign() <= 0 {
+		return nil, rfq.ErrQuoteMismatch
+	}
+
+	if quote.QuoteExpiry <= now.Unix() {
+		return nil, rfq.ErrQuoteExpired
+	}
+
+	recovered, err := rfq.RecoverSigner(hashQuote(chainID, quote, baseTokenAmount, quoteTokenAmount), result.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(recovered.Hex(), signer) {
+		return nil, rfq.ErrUnexpectedSigner
+	}
+
+	return quoteTokenAmount, nil
+}
+
+// hashQuote returns the message the market maker signs for an RFQ-T quote, the pool recovers the signer from the
+// EIP-191 hash of the packed quote and chain id
+func hashQuote(chainID uint, quote QuoteData, baseTokenAmount, quoteTokenAmount *big.Int) common.Hash {
+	effectiveTrader := quote.EffectiveTrader
+	if effectiveTrader == "" {
+		effectiveTrader = quote.Trader
+	}
+
+	hash := crypto.Keccak256(
+		common.HexToAddress(quote.Pool).Bytes(),
+		common.HexToAddress(quote.Trader).Bytes(),
+		common.HexToAddress(effectiveTrader).Bytes(),
+		common.HexToAddress(quote.ExternalAccount).Bytes(),
+		common.HexToAddress(quote.BaseToken).Bytes(),
+		common.HexToAddress(quote.QuoteToken).Bytes(),
+		math.U256Bytes(new(big.Int).Set(baseTokenAmount)),
+		math.U256Bytes(new(big.Int).Set(quoteTokenAmount)),
+		math.U256Bytes(big.NewInt(quote.QuoteExpiry)),
+		math.U256Bytes(big.NewInt(quote.Nonce)),
+		common.HexToHash(quote.TxID).Bytes(),
+		math.U256Bytes(new(big.Int).SetUint64(uint64(chainID))),
+	)
+
+	return common.BytesToHash(accounts.TextHash(hash))
+}
+
+func parseRequest(recipient string, params any) (rfq.Request, error) {
+	swapExtra, err := rfq.DecodeParams[SwapExtra](params)
+	if err != nil || swapExtra.MarketMaker == "" {
+		return rfq.Request{}, ErrInvalidFirmQuoteParams
+	}
+
+	if !account.IsValidAddress(swapExtra.BaseToken) || !account.IsValidAddress(swapExtra.QuoteToken) {
+		return rfq.Request{}, ErrInvalidFirmQuoteParams
+	}
+
+	baseTokenAmount, ok := new(big.Int).SetString(swapExtra.BaseTokenAmount, 10)
+	if !ok {
+		return rfq.Request{}, ErrInvalidFirmQuoteParams
+	}
+	quoteTokenAmount, ok := new(big.Int).SetString(swapExtra.QuoteTokenAmount, 10)
+	if !ok {
+		return rfq.Request{}, ErrInvalidFirmQuoteParams
+	}
+
+	return rfq.Request{
+		Taker:     recipient,
+		TokenIn:   swapExtra.BaseToken,
+		TokenOut:  swapExtra.QuoteToken,
+		AmountIn:  baseTokenAmount,
+		AmountOut: quoteTokenAmount,
+		Params:    swapExtra,
+	}, nil
+}
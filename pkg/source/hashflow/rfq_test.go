@@ -0,0 +1,203 @@
+package hashflow
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+)
+
+const (
+	testChainID   = 1
+	testRecipient = "0x0000000000000000000000000000000000000001"
+	testPool      = "0x0000000000000000000000000000000000000002"
+)
+
+type mockClient struct {
+	IClient
+	params FirmRequestParams
+	result FirmResult
+}
+
+func (c *mockClient) Firm(_ context.Context, params FirmRequestParams) (FirmResult, error) {
+	c.params = params
+	return c.result, nil
+}
+
+// signQuote signs the quote the way the market maker does, over the EIP-191 hash of the packed quote
+func signQuote(t *testing.T, key *ecdsa.PrivateKey, chainID uint, quote QuoteData) string {
+	baseTokenAmount, _ := new(big.Int).SetString(quote.BaseTokenAmount, 10)
+	quoteTokenAmount, _ := new(big.Int).SetString(quote.QuoteTokenAmount, 10)
+	sig, err := crypto.Sign(hashQuote(chainID, quote, baseTokenAmount, quoteTokenAmount).Bytes(), key)
+	require.NoError(t, err)
+	sig[64] += 27
+
+	return hexutil.Encode(sig)
+}
+
+// newTestFirmResult returns a quote signed by key, modify is applied after signing
+func newTestFirmResult(t *testing.T, key *ecdsa.PrivateKey, modify func(*FirmResult)) FirmResult {
+	result := FirmResult{
+		QuoteData: QuoteData{
+			Pool:             testPool,
+			Trader:           testRecipient,
+			EffectiveTrader:  testRecipient,
+			BaseToken:        testWETH,
+			QuoteToken:       testUSDC,
+			BaseTokenAmount:  "1000000000000000000",
+			QuoteTokenAmount: "1799000000",
+			QuoteExpiry:      time.Now().Add(time.Minute).Unix(),
+			TxID:             "0x01",
+		},
+		GasEstimate: 120000,
+	}
+	result.Signature = signQuote(t, key, testChainID, result.QuoteData)
+	if modify != nil {
+		modify(&result)
+	}
+
+	return result
+}
+
+func TestRFQHandler_RFQ_ValidatesQuote(t *testing.T) {
+	signerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := crypto.PubkeyToAddress(signerKey.PublicKey).Hex()
+
+	signedResult := func(modify func(*FirmResult)) FirmResult {
+		return newTestFirmResult(t, signerKey, modify)
+	}
+
+	testCases := []struct {
+		name    string
+		signers map[string]string
+		result  FirmResult
+		err     error
+	}{
+		{
+			name:   "valid quote",
+			result: signedResult(nil),
+		},
+		{
+			name:    "no signer configured for the market maker",
+			signers: map[string]string{"mm2": signer},
+			result:  signedResult(nil),
+			err:     ErrSignerNotConfigured,
+		},
+		{
+			name:   "signed by another key",
+			result: newTestFirmResult(t, otherKey, nil),
+			err:    rfq.ErrUnexpectedSigner,
+		},
+		{
+			name: "tampered quote",
+			result: signedResult(func(result *FirmResult) {
+				result.QuoteData.Nonce++
+			}),
+			err: rfq.ErrUnexpectedSigner,
+		},
+		{
+			name: "malformed signature",
+			result: signedResult(func(result *FirmResult) {
+				result.Signature = "0x1234"
+			}),
+			err: rfq.ErrInvalidSignature,
+		},
+		{
+			name: "expired",
+			result: signedResult(func(result *FirmResult) {
+				result.QuoteData.QuoteExpiry = time.Now().Add(-time.Minute).Unix()
+			}),
+			err: rfq.ErrQuoteExpired,
+		},
+		{
+			name: "wrong trader",
+			result: signedResult(func(result *FirmResult) {
+				result.QuoteData.Trader = testPool
+			}),
+			err: rfq.ErrQuoteMismatch,
+		},
+		{
+			name: "wrong base token amount",
+			result: signedResult(func(result *FirmResult) {
+				result.QuoteData.BaseTokenAmount = "1"
+			}),
+			err: rfq.ErrQuoteMismatch,
+		},
+		{
+			name: "empty quote token amount",
+			result: signedResult(func(result *FirmResult) {
+				result.QuoteData.QuoteTokenAmount = "0"
+			}),
+			err: rfq.ErrQuoteMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &mockClient{result: tc.result}
+			signers := tc.signers
+			if signers == nil {
+				signers = map[string]string{testMarketMaker: signer}
+			}
+			handler := NewRFQHandler(&Config{
+				DexID:   DexTypeHashflow,
+				ChainID: testChainID,
+				RFQ:     rfq.Config{SlippageTolerance: 50},
+				Signers: signers,
+			}, client)
+
+			result, err := handler.RFQ(context.Background(), testRecipient, SwapExtra{
+				MarketMaker:      testMarketMaker,
+				BaseToken:        testWETH,
+				BaseTokenAmount:  "1000000000000000000",
+				QuoteToken:       testUSDC,
+				QuoteTokenAmount: "1800000000",
+			})
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, FirmRequestParams{
+				MarketMaker:     testMarketMaker,
+				BaseToken:       testWETH,
+				QuoteToken:      testUSDC,
+				BaseTokenAmount: "1000000000000000000",
+				Trader:          testRecipient,
+			}, client.params)
+			assert.Equal(t, "1799000000", result.NewAmountOut.String())
+			assert.Equal(t, RFQExtra{
+				MarketMaker: testMarketMaker,
+				QuoteData:   tc.result.QuoteData,
+				Signature:   tc.result.Signature,
+				GasEstimate: tc.result.GasEstimate,
+			}, result.Extra)
+		})
+	}
+}
+
+func TestRFQHandler_RFQ_RequiresMarketMaker(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	handler := NewRFQHandler(&Config{DexID: DexTypeHashflow}, &mockClient{result: newTestFirmResult(t, key, nil)})
+
+	_, err = handler.RFQ(context.Background(), testRecipient, SwapExtra{
+		BaseToken:        testWETH,
+		BaseTokenAmount:  "1000000000000000000",
+		QuoteToken:       testUSDC,
+		QuoteTokenAmount: "1800000000",
+	})
+	assert.ErrorIs(t, err, ErrInvalidFirmQuoteParams)
+}
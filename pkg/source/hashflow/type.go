@@ -0,0 +1,125 @@
+package hashflow
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+)
+
+type Chain struct {
+	ChainType string `json:"chainType"`
+	ChainID   uint   `json:"chainId"`
+}
+
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ListMarketMakersResult is the result of list market makers
+type ListMarketMakersResult struct {
+	MarketMakers []string `json:"marketMakers"`
+}
+
+type Pair struct {
+	BaseToken          string `json:"baseToken"`
+	QuoteToken         string `json:"quoteToken"`
+	BaseTokenName      string `json:"baseTokenName"`
+	QuoteTokenName     string `json:"quoteTokenName"`
+	BaseTokenDecimals  uint8  `json:"baseTokenDecimals"`
+	QuoteTokenDecimals uint8  `json:"quoteTokenDecimals"`
+}
+
+// Level is an indicative level of a pair, Q is the cumulative amount of base token sold to the maker up to this
+// level and P the price in quote token of the base token between the previous level and this one. The Q of the first
+// level is the minimum amount the maker trades.
+type Level struct {
+	Q string `json:"q"`
+	P string `json:"p"`
+}
+
+// PairPriceLevels are the levels of a trader selling the base token for the quote token, the other direction is
+// another pair
+type PairPriceLevels struct {
+	Pair   Pair    `json:"pair"`
+	Levels []Level `json:"levels"`
+}
+
+// ListPriceLevelsResult is the result of list price levels
+type ListPriceLevelsResult struct {
+	Status string                       `json:"status"`
+	Levels map[string][]PairPriceLevels `json:"levels"`
+	Error  *APIError                    `json:"error"`
+}
+
+type StaticExtra struct {
+	MarketMaker string `json:"marketMaker"`
+}
+
+type Extra struct {
+	ZeroToOnePriceLevels PriceLevels `json:"zeroToOnePriceLevels"`
+	OneToZeroPriceLevels PriceLevels `json:"oneToZeroPriceLevels"`
+}
+
+// PriceLevels is the order book of one direction, the amounts are in token in
+type PriceLevels struct {
+	MinAmountIn *big.Rat     `json:"minAmountIn"`
+	Levels      []PriceLevel `json:"levels"`
+}
+
+// PriceLevel is an exact level of the order book, Amount of token in is sold at Price
+type PriceLevel = rfq.PriceLevel
+
+type SwapExtra struct {
+	MarketMaker      string `json:"marketMaker"`
+	BaseToken        string `json:"baseToken"`
+	BaseTokenAmount  string `json:"baseTokenAmount"`
+	QuoteToken       string `json:"quoteToken"`
+	QuoteTokenAmount string `json:"quoteTokenAmount"`
+}
+
+type Gas struct {
+	Swap int64
+}
+
+type FirmRequestParams struct {
+	MarketMaker     string
+	BaseToken       string
+	QuoteToken      string
+	BaseTokenAmount string
+	Trader          string
+}
+
+// QuoteData is the quote signed by the market maker, it is passed as is to the pool contract
+type QuoteData struct {
+	BaseChain        Chain  `json:"baseChain"`
+	QuoteChain       Chain  `json:"quoteChain"`
+	Pool             string `json:"pool"`
+	ExternalAccount  string `json:"externalAccount,omitempty"`
+	Trader           string `json:"trader"`
+	EffectiveTrader  string `json:"effectiveTrader"`
+	BaseToken        string `json:"baseToken"`
+	QuoteToken       string `json:"quoteToken"`
+	BaseTokenAmount  string `json:"baseTokenAmount"`
+	QuoteTokenAmount string `json:"quoteTokenAmount"`
+	QuoteExpiry      int64  `json:"quoteExpiry"`
+	Nonce            int64  `json:"nonce"`
+	TxID             string `json:"txid"`
+}
+
+type FirmResult struct {
+	QuoteData   QuoteData `json:"quoteData"`
+	Signature   string    `json:"signature"`
+	GasEstimate int64     `json:"gasEstimate"`
+}
+
+type RFQExtra struct {
+	MarketMaker string    `json:"marketMaker"`
+	QuoteData   QuoteData `json:"quoteData"`
+	Signature   string    `json:"signature"`
+	GasEstimate int64     `json:"gasEstimate"`
+}
+
+type RFQMeta struct {
+	Timestamp int64 `json:"timestamp"`
+}
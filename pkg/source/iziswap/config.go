@@ -20,6 +20,15 @@ type Config struct {
 	// is [currentPrice/1.2, currentPrice * 1.2)
 	PointRange int `mapstructure:"point_range" json:"point_range,omitempty"`
 
+	// DepthUSD makes the snapshot range adaptive: a side of the range whose liquidity and limit orders are worth less
+	// than DepthUSD of the token a swap towards it buys is doubled, up to MaxPointRange from the current point.
+	// The price of the tokens comes from the reserveUsd of the pool, so the range stays at PointRange when
+	// DepthUSD is not positive or the pool has no reserveUsd yet.
+	DepthUSD float64 `mapstructure:"depth_usd" json:"depth_usd,omitempty"`
+	// a non-positive value will be set to 20000 by default, the price moves by about 7.4 times over 20000 points,
+	// and a value smaller than PointRange is raised to PointRange
+	MaxPointRange int `mapstructure:"max_point_range" json:"max_point_range,omitempty"`
+
 	HTTP HTTPConfig `mapstructure:"http" json:"http,omitempty"`
 
 	// //todo: we may use it in the future for speed up
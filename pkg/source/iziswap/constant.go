@@ -19,12 +19,16 @@ const (
 
 	LEFT_MOST_PT int = -800000
 
-	DEFAULT_PT_RANGE   = 2000
-	SIMULATOR_PT_RANGE = 2000
+	DEFAULT_PT_RANGE     = 2000
+	DEFAULT_MAX_PT_RANGE = 20000
+	SIMULATOR_PT_RANGE   = 2000
 )
 
 var zeroBI = big.NewInt(0)
 
+// feeBase is the denominator of the fee of the pool
+var feeBase = big.NewInt(1e6)
+
 var pointDeltas = map[int]int{
 	100:   1,
 	400:   8,
@@ -35,5 +39,4 @@ var pointDeltas = map[int]int{
 }
 
 // // Fee can be ignored for now
-// var feeBase = big.NewInt(1e6)
 // var boneFloat, _ = new(big.Float).SetString("1000000000000000000")
@@ -8,4 +8,6 @@ var (
 	ErrInvalidReservesLength = errors.New("invalid reverses length")
 	ErrInvalidTokensLength   = errors.New("invalid tokens length")
 	ErrInvalidReserve        = errors.New("invalid reserve")
+	ErrInvalidAmountIn       = errors.New("amount in must be positive")
+	ErrWindowExhausted       = errors.New("swap reaches the end of the known points")
 )
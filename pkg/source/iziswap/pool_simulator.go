@@ -16,6 +16,7 @@ import (
 type PoolSimulator struct {
 	pool.Pool
 	PoolInfo swap.PoolInfo
+	window   *PointWindow
 }
 
 func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
@@ -64,7 +65,8 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 				Reserves: []*big.Int{reserves0, reserves1},
 			},
 		},
-		PoolInfo: swap.PoolInfo(extra),
+		PoolInfo: extra.PoolInfo,
+		window:   extra.Window,
 	}, nil
 }
 
@@ -81,18 +83,18 @@ func (p *PoolSimulator) CalcAmountOut(
 		return &pool.CalcAmountOutResult{}, fmt.Errorf("tokenInIndex %v or tokenOutIndex %v is not correct", tokenInIndex, tokenOutIndex)
 	}
 
-	// Clone tokenAmountIn.Amount, since the SDK will mutate it
+	// Clone tokenAmountIn.Amount, since the swap will mutate it
 	tokenAmountInAmount := new(big.Int).Set(tokenAmountIn.Amount)
 
 	x2y := tokenInAddr < tokenOutAddr
 	if x2y {
-		// todo, not limit swap-range in the future
-		//    or give a way to modify it
-		lowPt := p.PoolInfo.CurrentPoint - SIMULATOR_PT_RANGE
-		ret, err := swap.SwapX2Y(tokenAmountInAmount, lowPt, p.PoolInfo)
+		ret, err := swapX2Y(tokenAmountInAmount, p.getLimitPoint(x2y), p.PoolInfo)
 		if err != nil {
 			return &pool.CalcAmountOutResult{}, err
 		}
+		if p.isWindowExhausted(tokenAmountIn.Amount, ret.AmountX) {
+			return &pool.CalcAmountOutResult{}, ErrWindowExhausted
+		}
 		amountY := ret.AmountY
 		// // Fee can be ignored for now
 		// amountX := ret.AmountX
@@ -108,19 +110,20 @@ func (p *PoolSimulator) CalcAmountOut(
 				Amount: nil,
 			},
 			SwapInfo: iZiSwapInfo{
-				nextPoint:      ret.CurrentPoint,
-				nextLiquidity:  new(big.Int).Set(ret.Liquidity),
-				nextLiquidityX: new(big.Int).Set(ret.LiquidityX),
+				nextPoint:       ret.CurrentPoint,
+				nextLiquidity:   new(big.Int).Set(ret.Liquidity),
+				nextLiquidityX:  new(big.Int).Set(ret.LiquidityX),
+				nextLimitOrders: ret.limitOrders,
 			},
 		}, nil
 	} else {
-		// todo, not limit swap-range in the future
-		//    or give a way to modify it
-		highPt := p.PoolInfo.CurrentPoint + SIMULATOR_PT_RANGE
-		ret, err := swap.SwapY2X(tokenAmountInAmount, highPt, p.PoolInfo)
+		ret, err := swapY2X(tokenAmountInAmount, p.getLimitPoint(x2y), p.PoolInfo)
 		if err != nil {
 			return &pool.CalcAmountOutResult{}, err
 		}
+		if p.isWindowExhausted(tokenAmountIn.Amount, ret.AmountY) {
+			return &pool.CalcAmountOutResult{}, ErrWindowExhausted
+		}
 		amountX := ret.AmountX
 		// // Fee can be ignored for now
 		// fee := new(big.Int).Mul(amountX, big.NewInt(int64(p.PoolInfo.Fee)))
@@ -135,43 +138,61 @@ func (p *PoolSimulator) CalcAmountOut(
 				Amount: nil,
 			},
 			SwapInfo: iZiSwapInfo{
-				nextPoint:      ret.CurrentPoint,
-				nextLiquidity:  new(big.Int).Set(ret.Liquidity),
-				nextLiquidityX: new(big.Int).Set(ret.LiquidityX),
+				nextPoint:       ret.CurrentPoint,
+				nextLiquidity:   new(big.Int).Set(ret.Liquidity),
+				nextLiquidityX:  new(big.Int).Set(ret.LiquidityX),
+				nextLimitOrders: ret.limitOrders,
 			},
 		}, nil
 	}
 }
 
-// we should notice that,
-// unlike liquidity distribution,
-// limit orders may change more frequently, and often changed after each exchange
-// (if any limit order is dealed during that exchange)
-//
-// the function `UpdateBalance` which only change
-// `currentPoint`, `liquidity` and `liquidityX` on currentPoint
-// is not enough to trace actual limit order distribution on the pool in time,
-// since then we may get inaccurate value of `amountOut` in `CalcAmountOut` if there
-// exists too many limit orders (especially around current point) on that pool
+// UpdateBalance moves the pool to the state after the swap, the limit orders filled by the swap are removed or
+// reduced so the next swaps do not fill them again.
 //
-// that means, if there exists some limit orders around the current point of a pool
-// and we still want to get the value of `amountOut` as accurate as possible, we need
-// to call pool_tracker of that pool more frequently to update limit order distribution in time
+// we should notice that,
+// limit orders may still change more frequently than the liquidity distribution because of swaps and orders
+// outside of the simulation, and only the limit orders in the point range of the tracker are known, so the
+// pool_tracker of pools with many limit orders around the current point should run more frequently
 func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
 	si, ok := params.SwapInfo.(iZiSwapInfo)
 	if !ok {
-		logger.Warn("failed to UpdateBalance for UniV3 pool, wrong swapInfo type")
+		logger.Warn("failed to UpdateBalance for iZiSwap pool, wrong swapInfo type")
 		return
 	}
 	p.PoolInfo.CurrentPoint = si.nextPoint
 	p.PoolInfo.Liquidity = si.nextLiquidity
 	p.PoolInfo.LiquidityX = si.nextLiquidityX
+	p.PoolInfo.LimitOrders = si.nextLimitOrders
 }
 
 func (p *PoolSimulator) GetMetaInfo(tokenIn string, tokenOut string) interface{} {
-	limitPoint := p.PoolInfo.CurrentPoint - SIMULATOR_PT_RANGE
-	if tokenIn > tokenOut {
-		limitPoint = p.PoolInfo.CurrentPoint + SIMULATOR_PT_RANGE
+	return Meta{LimitPoint: p.getLimitPoint(tokenIn < tokenOut)}
+}
+
+// getLimitPoint returns the point a swap can not go past, the end of the window of the tracker when it is known
+func (p *PoolSimulator) getLimitPoint(x2y bool) int {
+	if p.window != nil {
+		if x2y {
+			return p.window.LeftPoint
+		}
+		return p.window.RightPoint
+	}
+
+	// todo, not limit swap-range in the future
+	//    or give a way to modify it
+	if x2y {
+		return p.PoolInfo.CurrentPoint - SIMULATOR_PT_RANGE
+	}
+	return p.PoolInfo.CurrentPoint + SIMULATOR_PT_RANGE
+}
+
+// isWindowExhausted tells if a swap stopped at the end of the window of the tracker before spending amountIn, the
+// points past the window may hold liquidity so the amount out would be under-quoted. The pools tracked before the
+// window was recorded keep returning the amount out of the spent amount.
+func (p *PoolSimulator) isWindowExhausted(amountIn, spent *big.Int) bool {
+	if p.window == nil {
+		return false
 	}
-	return Meta{LimitPoint: limitPoint}
+	return getAmountNoFee(new(big.Int).Sub(amountIn, spent), p.PoolInfo.Fee).Sign() > 0
 }
@@ -2,8 +2,10 @@ package iziswap
 
 import (
 	"fmt"
+	"math/big"
 	"testing"
 
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -48,3 +50,133 @@ func TestCalcAmountOut(t *testing.T) {
 		})
 	}
 }
+
+func TestCalcAmountOut_LimitOrders(t *testing.T) {
+	// the pool of TestCalcAmountOut with 1 B and 0.1 A sold by limit orders at the current point
+	newPoolSimulator := func(t *testing.T) *PoolSimulator {
+		p, err := NewPoolSimulator(entity.Pool{
+			Address:  "0xee45cffbfafe97691b8ef068c8d55163086a3431",
+			Exchange: "iziswap",
+			Type:     "iziswap",
+			SwapFee:  400,
+			Reserves: entity.PoolReserves{"1167087113545385273", "18037620383221447465"},
+			Tokens:   []*entity.PoolToken{{Address: "A", Decimals: 18}, {Address: "B", Decimals: 18}},
+			Extra:    "{\"CurrentPoint\":28912,\"PointDelta\":8,\"LeftMostPt\":-800000,\"RightMostPt\":800000,\"Fee\":400,\"Liquidity\":23123688144702854,\"LiquidityX\":8210612878032008,\"Liquidities\":[{\"LiqudityDelta\":23123688144702854,\"Point\":28728},{\"LiqudityDelta\":-23123688144702854,\"Point\":29128}],\"LimitOrders\":[{\"SellingX\":100000000000000000,\"SellingY\":1000000000000000000,\"Point\":28912}]}",
+		})
+		require.NoError(t, err)
+		return p
+	}
+
+	testcases := []struct {
+		name     string
+		in       string
+		inAmount string
+		out      string
+		x2y      bool
+	}{
+		{"partially fills selling B", "A", "10000000000000000", "B", true},
+		{"fills selling B then range", "A", "200000000000000000", "B", true},
+		{"partially fills selling A", "B", "100000000000000000", "A", false},
+		{"fills selling A then range", "B", "5000000000000000000", "A", false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newPoolSimulator(t)
+			limitOrdersBefore := p.PoolInfo.LimitOrders
+
+			for i := 0; i < 2; i++ {
+				amountIn := pool.TokenAmount{Token: tc.in, Amount: bignumber.NewBig10(tc.inAmount)}
+				result, err := p.CalcAmountOut(amountIn, tc.out)
+				require.NoError(t, err)
+
+				// same amount as the SDK, which fills the limit orders the same way
+				var sdkResult swap.SwapResult
+				if tc.x2y {
+					sdkResult, err = swap.SwapX2Y(bignumber.NewBig10(tc.inAmount), p.PoolInfo.CurrentPoint-SIMULATOR_PT_RANGE, p.PoolInfo)
+					require.NoError(t, err)
+					assert.Equal(t, sdkResult.AmountY, result.TokenAmountOut.Amount)
+				} else {
+					sdkResult, err = swap.SwapY2X(bignumber.NewBig10(tc.inAmount), p.PoolInfo.CurrentPoint+SIMULATOR_PT_RANGE, p.PoolInfo)
+					require.NoError(t, err)
+					assert.Equal(t, sdkResult.AmountX, result.TokenAmountOut.Amount)
+				}
+
+				sellingBefore := getSelling(p.PoolInfo.LimitOrders, tc.x2y)
+				p.UpdateBalance(pool.UpdateBalanceParams{
+					TokenAmountIn:  amountIn,
+					TokenAmountOut: *result.TokenAmountOut,
+					SwapInfo:       result.SwapInfo,
+				})
+				sellingAfter := getSelling(p.PoolInfo.LimitOrders, tc.x2y)
+
+				// the limit order gave at most the amount out, and the next swap does not fill it again
+				filled := new(big.Int).Sub(sellingBefore, sellingAfter)
+				if sellingBefore.Sign() == 0 {
+					assert.Zero(t, filled.Sign())
+					break
+				}
+				assert.Equal(t, 1, filled.Sign())
+				assert.LessOrEqual(t, filled.Cmp(result.TokenAmountOut.Amount), 0)
+				if sellingAfter.Sign() > 0 {
+					assert.Equal(t, filled, result.TokenAmountOut.Amount)
+				}
+			}
+
+			// the limit orders of the pool entity are not modified
+			assert.Equal(t, bignumber.NewBig10("1000000000000000000"), limitOrdersBefore[0].SellingY)
+			assert.Equal(t, bignumber.NewBig10("100000000000000000"), limitOrdersBefore[0].SellingX)
+		})
+	}
+}
+
+// getSelling returns the amount sold by the limit order swapped with in the direction
+func getSelling(limitOrders []swap.LimitOrderPoint, x2y bool) *big.Int {
+	if x2y {
+		return limitOrders[0].SellingY
+	}
+	return limitOrders[0].SellingX
+}
+
+func TestCalcAmountOut_WindowExhausted(t *testing.T) {
+	// the pool of TestCalcAmountOut of which the tracker fetched the points [28800, 29000)
+	p, err := NewPoolSimulator(entity.Pool{
+		Address:  "0xee45cffbfafe97691b8ef068c8d55163086a3431",
+		Exchange: "iziswap",
+		Type:     "iziswap",
+		SwapFee:  400,
+		Reserves: entity.PoolReserves{"1167087113545385273", "18037620383221447465"},
+		Tokens:   []*entity.PoolToken{{Address: "A", Decimals: 18}, {Address: "B", Decimals: 18}},
+		Extra:    "{\"CurrentPoint\":28912,\"PointDelta\":8,\"LeftMostPt\":-800000,\"RightMostPt\":800000,\"Fee\":400,\"Liquidity\":23123688144702854,\"LiquidityX\":8210612878032008,\"Liquidities\":[{\"LiqudityDelta\":23123688144702854,\"Point\":28728},{\"LiqudityDelta\":-23123688144702854,\"Point\":29128}],\"LimitOrders\":[],\"window\":{\"leftPoint\":28800,\"rightPoint\":29000}}",
+	})
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name        string
+		in          string
+		inAmount    string
+		out         string
+		expectedErr error
+	}{
+		{"A to B within the window", "A", "10000000000000000", "B", nil},
+		{"A to B past the window", "A", "2000000000000000000", "B", ErrWindowExhausted},
+		{"B to A within the window", "B", "10000000000000000", "A", nil},
+		{"B to A past the window", "B", "20000000000000000000", "A", ErrWindowExhausted},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			amountIn := pool.TokenAmount{Token: tc.in, Amount: bignumber.NewBig10(tc.inAmount)}
+			result, err := p.CalcAmountOut(amountIn, tc.out)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 1, result.TokenAmountOut.Amount.Sign())
+		})
+	}
+
+	assert.Equal(t, Meta{LimitPoint: 28800}, p.GetMetaInfo("A", "B"))
+	assert.Equal(t, Meta{LimitPoint: 29000}, p.GetMetaInfo("B", "A"))
+}
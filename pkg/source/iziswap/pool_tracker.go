@@ -11,7 +11,6 @@ import (
 	"github.com/KyberNetwork/logger"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
-	"github.com/sourcegraph/conc/pool"
 )
 
 type PoolTracker struct {
@@ -26,6 +25,12 @@ func NewPoolTracker(
 	if cfg.PointRange <= 0 {
 		cfg.PointRange = DEFAULT_PT_RANGE
 	}
+	if cfg.MaxPointRange <= 0 {
+		cfg.MaxPointRange = DEFAULT_MAX_PT_RANGE
+	}
+	if cfg.MaxPointRange < cfg.PointRange {
+		cfg.MaxPointRange = cfg.PointRange
+	}
 	return &PoolTracker{
 		config:       cfg,
 		ethrpcClient: ethrpcClient,
@@ -39,8 +44,6 @@ func (d *PoolTracker) GetNewPoolState(
 ) (entity.Pool, error) {
 	logger.Infof("[iZiSwap] Start getting new state of pool: %v", p.Address)
 
-	g := pool.New().WithContext(ctx)
-
 	rpcData, err := d.fetchPoolState(ctx, p)
 
 	if err != nil {
@@ -66,33 +69,10 @@ func (d *PoolTracker) GetNewPoolState(
 		LiquidityX:   rpcData.state.LiquidityX,
 	}
 
-	var (
-		liquidityPointData  []swap.LiquidityPoint
-		limitOrderPointData []swap.LimitOrderPoint
+	liquidityPointData, limitOrderPointData, window, err := d.getAdaptiveSnapshot(
+		ctx, p, poolInfo, rpcData.reserve0, rpcData.reserve1,
 	)
-
-	g.Go(func(context.Context) error {
-		var err error
-		liquidityPointData, err = d.getLiquiditySnapshot(ctx, p, poolInfo)
-		if err != nil {
-			logger.WithFields(logger.Fields{
-				"error": err,
-			}).Errorf("failed to call SC for pool liquidity snapshot")
-		}
-		return err
-	})
-	g.Go(func(context.Context) error {
-		var err error
-		limitOrderPointData, err = d.getLimitOrderSnapshot(ctx, p, poolInfo)
-		if err != nil {
-			logger.WithFields(logger.Fields{
-				"error": err,
-			}).Errorf("failed to call SC for pool limitOrder snapshot")
-		}
-		return err
-	})
-
-	if err := g.Wait(); err != nil {
+	if err != nil {
 		logger.WithFields(logger.Fields{
 			"poolAddress": p.Address,
 			"error":       err,
@@ -102,7 +82,7 @@ func (d *PoolTracker) GetNewPoolState(
 	poolInfo.Liquidities = liquidityPointData
 	poolInfo.LimitOrders = limitOrderPointData
 
-	extraBytes, err := json.Marshal(poolInfo)
+	extraBytes, err := json.Marshal(Extra{PoolInfo: poolInfo, Window: &window})
 	if err != nil {
 		logger.WithFields(logger.Fields{
 			"poolAddress": p.Address,
@@ -9,6 +9,7 @@ import (
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util"
 	"github.com/KyberNetwork/logger"
 	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+	"github.com/sourcegraph/conc/pool"
 )
 
 func getPointDelta(fee int) int {
@@ -22,11 +23,10 @@ func minInt(a int, b int) int {
 	return b
 }
 
-func (d *PoolTracker) getLiquiditySnapshot(ctx context.Context, pool entity.Pool, poolInfo swap.PoolInfo) ([]swap.LiquidityPoint, error) {
-	ptRange := d.config.PointRange
-	if ptRange <= 0 {
-		ptRange = DEFAULT_PT_RANGE
-	}
+// getSnapshotRange returns the points within ptRange of the current point, aligned on the point delta, of which the
+// liquidity and the limit orders are fetched. Both snapshots use the same range so the simulator knows every limit
+// order the range liquidity it knows can reach.
+func getSnapshotRange(poolInfo swap.PoolInfo, ptRange int) (int, int) {
 	pointDelta := poolInfo.PointDelta
 	leftPoint := poolInfo.CurrentPoint - ptRange
 	modl := (leftPoint%pointDelta + pointDelta) % pointDelta
@@ -44,7 +44,59 @@ func (d *PoolTracker) getLiquiditySnapshot(ctx context.Context, pool entity.Pool
 	if rightPoint > poolInfo.RightMostPt {
 		rightPoint = poolInfo.RightMostPt
 	}
-	batchLen := SNAPSHOT_BATCH * poolInfo.PointDelta
+	return leftPoint, rightPoint
+}
+
+// getSnapshot fetches the liquidity and the limit orders of the points in [leftPoint, rightPoint)
+func (d *PoolTracker) getSnapshot(
+	ctx context.Context,
+	p entity.Pool,
+	pointDelta int,
+	leftPoint int,
+	rightPoint int,
+) ([]swap.LiquidityPoint, []swap.LimitOrderPoint, error) {
+	var (
+		liquidityPointData  []swap.LiquidityPoint
+		limitOrderPointData []swap.LimitOrderPoint
+	)
+
+	g := pool.New().WithContext(ctx)
+	g.Go(func(context.Context) error {
+		var err error
+		liquidityPointData, err = d.getLiquiditySnapshot(ctx, p, pointDelta, leftPoint, rightPoint)
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"error": err,
+			}).Errorf("failed to call SC for pool liquidity snapshot")
+		}
+		return err
+	})
+	g.Go(func(context.Context) error {
+		var err error
+		limitOrderPointData, err = d.getLimitOrderSnapshot(ctx, p, pointDelta, leftPoint, rightPoint)
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"error": err,
+			}).Errorf("failed to call SC for pool limitOrder snapshot")
+		}
+		return err
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	return liquidityPointData, limitOrderPointData, nil
+}
+
+func (d *PoolTracker) getLiquiditySnapshot(
+	ctx context.Context,
+	pool entity.Pool,
+	pointDelta int,
+	leftPoint int,
+	rightPoint int,
+) ([]swap.LiquidityPoint, error) {
+	batchLen := SNAPSHOT_BATCH * pointDelta
 	deltaLiquidities := make([]*big.Int, SNAPSHOT_BATCH)
 	liqudityPointLen := (rightPoint - leftPoint) / pointDelta
 	liquidityPointData := make([]swap.LiquidityPoint, 0, liqudityPointLen)
@@ -90,29 +142,14 @@ func (d *PoolTracker) getLiquiditySnapshot(ctx context.Context, pool entity.Pool
 	return liquidityPointData, nil
 }
 
-func (d *PoolTracker) getLimitOrderSnapshot(ctx context.Context, pool entity.Pool, poolInfo swap.PoolInfo) ([]swap.LimitOrderPoint, error) {
-	ptRange := d.config.PointRange
-	if ptRange <= 0 {
-		ptRange = DEFAULT_PT_RANGE
-	}
-	pointDelta := poolInfo.PointDelta
-	leftPoint := poolInfo.CurrentPoint - ptRange
-	modl := (leftPoint%pointDelta + pointDelta) % pointDelta
-	if modl != 0 {
-		leftPoint = leftPoint - modl
-	}
-	if leftPoint < poolInfo.LeftMostPt {
-		leftPoint = poolInfo.LeftMostPt
-	}
-	rightPoint := poolInfo.CurrentPoint + ptRange
-	modr := (rightPoint%pointDelta + pointDelta) % pointDelta
-	if modr != 0 {
-		rightPoint = rightPoint + pointDelta - modr
-	}
-	if rightPoint > poolInfo.RightMostPt {
-		rightPoint = poolInfo.RightMostPt
-	}
-	batchLen := SNAPSHOT_BATCH * poolInfo.PointDelta
+func (d *PoolTracker) getLimitOrderSnapshot(
+	ctx context.Context,
+	pool entity.Pool,
+	pointDelta int,
+	leftPoint int,
+	rightPoint int,
+) ([]swap.LimitOrderPoint, error) {
+	batchLen := SNAPSHOT_BATCH * pointDelta
 	limitOrderDataRaw := make([]LimitOrder, SNAPSHOT_BATCH)
 	limitOrderPointLen := (rightPoint - leftPoint) / pointDelta
 	limitOrderPointData := make([]swap.LimitOrderPoint, 0, limitOrderPointLen)
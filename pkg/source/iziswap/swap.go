@@ -0,0 +1,299 @@
+package iziswap
+
+import (
+	"math/big"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/swapmath"
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/utils"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// swapResult is the result of a swap, limitOrders are the limit order points of the pool left after the swap
+type swapResult struct {
+	swap.SwapResult
+	limitOrders []swap.LimitOrderPoint
+}
+
+// swapX2Y follows swap.SwapX2Y of the SDK: at every point the limit orders selling y are filled before the range
+// liquidity. The SDK does not tell how much of the limit orders was filled, so the loop is kept here to return the
+// limit orders left after the swap.
+func swapX2Y(amount *big.Int, lowPt int, pool swap.PoolInfo) (swapResult, error) {
+	if amount.Sign() <= 0 {
+		return swapResult{}, ErrInvalidAmountIn
+	}
+	amount = new(big.Int).Set(amount)
+
+	lowPt = calc.Max(lowPt, pool.LeftMostPt)
+	amountX := big.NewInt(0)
+	amountY := big.NewInt(0)
+
+	sqrtPrice_96, _ := calc.GetSqrtPrice(pool.CurrentPoint)
+
+	liquidityX := new(big.Int).Set(pool.LiquidityX)
+	liquidity := new(big.Int).Set(pool.Liquidity)
+
+	finished := false
+	sqrtRate_96, _ := calc.GetSqrtPrice(1)
+	pointDelta := pool.PointDelta
+	currentPoint := pool.CurrentPoint
+	fee := pool.Fee
+
+	limitOrders := append([]swap.LimitOrderPoint(nil), pool.LimitOrders...)
+	orderData := swap.InitX2Y(pool.Liquidities, limitOrders, pool.CurrentPoint)
+
+	for lowPt <= currentPoint && !finished {
+		if orderData.IsLimitOrder(currentPoint) {
+			amountNoFee := getAmountNoFee(amount, fee)
+			if amountNoFee.Sign() > 0 {
+				currY := orderData.UnsafeGetLimitSellingY()
+				costX, acquireY := swapmath.X2YAtPrice(amountNoFee, sqrtPrice_96, currY)
+				if acquireY.Cmp(currY) < 0 || costX.Cmp(amountNoFee) >= 0 {
+					finished = true
+				}
+
+				feeAmount := getFeeAmount(amount, amountNoFee, costX, fee)
+				amount.Sub(amount, costX)
+				amount.Sub(amount, feeAmount)
+				amountX.Add(amountX, costX)
+				amountX.Add(amountX, feeAmount)
+				amountY.Add(amountY, acquireY)
+
+				limitOrders[orderData.LimitOrderIdx].SellingY = new(big.Int).Sub(currY, acquireY)
+				orderData.ConsumeLimitOrder(false)
+			} else {
+				finished = true
+			}
+		}
+
+		if finished {
+			break
+		}
+
+		searchStart := currentPoint - 1
+
+		// clear the liquidity if the currentPoint is an endpoint
+		if orderData.IsLiquidity(currentPoint) {
+			amountNoFee := getAmountNoFee(amount, fee)
+			if amountNoFee.Sign() > 0 {
+				if liquidity.Sign() > 0 {
+					st := utils.State{
+						LiquidityX:   new(big.Int).Set(liquidityX),
+						Liquidity:    new(big.Int).Set(liquidity),
+						CurrentPoint: currentPoint,
+						SqrtPrice_96: sqrtPrice_96,
+					}
+					retState := swapmath.X2YRange(st, currentPoint, sqrtRate_96, new(big.Int).Set(amountNoFee))
+					finished = retState.Finished
+
+					feeAmount := getFeeAmount(amount, amountNoFee, retState.CostX, fee)
+					amountX.Add(amountX, retState.CostX)
+					amountX.Add(amountX, feeAmount)
+					amountY.Add(amountY, retState.AcquireY)
+					amount.Sub(amount, retState.CostX)
+					amount.Sub(amount, feeAmount)
+					currentPoint = retState.FinalPt
+					sqrtPrice_96 = retState.SqrtFinalPrice_96
+					liquidityX = retState.LiquidityX
+				}
+				if !finished {
+					delta := orderData.UnsafeGetDeltaLiquidity()
+					liquidity.Sub(liquidity, delta)
+					currentPoint -= 1
+					sqrtPrice_96, _ = calc.GetSqrtPrice(currentPoint)
+					liquidityX.SetInt64(0)
+				}
+			} else {
+				finished = true
+			}
+		}
+
+		if finished || currentPoint < lowPt {
+			break
+		}
+
+		nextPt := orderData.MoveX2Y(searchStart, pointDelta)
+		if nextPt < lowPt {
+			nextPt = lowPt
+		}
+
+		if liquidity.Sign() == 0 {
+			currentPoint = nextPt
+			sqrtPrice_96, _ = calc.GetSqrtPrice(currentPoint)
+		} else {
+			amountNoFee := getAmountNoFee(amount, fee)
+			if amountNoFee.Sign() > 0 {
+				st := utils.State{
+					LiquidityX:   new(big.Int).Set(liquidityX),
+					Liquidity:    new(big.Int).Set(liquidity),
+					CurrentPoint: currentPoint,
+					SqrtPrice_96: sqrtPrice_96,
+				}
+				retState := swapmath.X2YRange(st, nextPt, sqrtRate_96, new(big.Int).Set(amountNoFee))
+				finished = retState.Finished
+
+				feeAmount := getFeeAmount(amount, amountNoFee, retState.CostX, fee)
+				amountY.Add(amountY, retState.AcquireY)
+				amountX.Add(amountX, retState.CostX)
+				amountX.Add(amountX, feeAmount)
+				amount.Sub(amount, retState.CostX)
+				amount.Sub(amount, feeAmount)
+
+				currentPoint = retState.FinalPt
+				sqrtPrice_96 = retState.SqrtFinalPrice_96
+				liquidityX = retState.LiquidityX
+			} else {
+				finished = true
+			}
+		}
+
+		if currentPoint <= lowPt {
+			break
+		}
+	}
+
+	return swapResult{
+		SwapResult: swap.SwapResult{
+			CurrentPoint: currentPoint,
+			Liquidity:    liquidity,
+			LiquidityX:   liquidityX,
+			AmountX:      amountX,
+			AmountY:      amountY,
+		},
+		limitOrders: limitOrders,
+	}, nil
+}
+
+// swapY2X follows swap.SwapY2X of the SDK: at every point the limit orders selling x are filled before the range
+// liquidity, and the limit orders left after the swap are returned.
+func swapY2X(amount *big.Int, highPt int, pool swap.PoolInfo) (swapResult, error) {
+	if amount.Sign() <= 0 {
+		return swapResult{}, ErrInvalidAmountIn
+	}
+	amount = new(big.Int).Set(amount)
+
+	highPt = calc.Min(highPt, pool.RightMostPt)
+
+	amountX := big.NewInt(0)
+	amountY := big.NewInt(0)
+
+	sqrtPrice_96, _ := calc.GetSqrtPrice(pool.CurrentPoint)
+
+	liquidityX := new(big.Int).Set(pool.LiquidityX)
+	liquidity := new(big.Int).Set(pool.Liquidity)
+
+	finished := false
+	sqrtRate_96, _ := calc.GetSqrtPrice(1)
+	pointDelta := pool.PointDelta
+	currentPoint := pool.CurrentPoint
+	fee := pool.Fee
+
+	limitOrders := append([]swap.LimitOrderPoint(nil), pool.LimitOrders...)
+	orderData := swap.InitY2X(pool.Liquidities, limitOrders, pool.CurrentPoint)
+
+	for currentPoint < highPt && !finished {
+		if orderData.IsLimitOrder(currentPoint) {
+			amountNoFee := getAmountNoFee(amount, fee)
+			if amountNoFee.Sign() > 0 {
+				currX := orderData.UnsafeGetLimitSellingX()
+				costY, acquireX := swapmath.Y2XAtPrice(amountNoFee, sqrtPrice_96, currX)
+				if acquireX.Cmp(currX) < 0 || costY.Cmp(amountNoFee) >= 0 {
+					finished = true
+				}
+
+				feeAmount := getFeeAmount(amount, amountNoFee, costY, fee)
+				amount.Sub(amount, new(big.Int).Add(costY, feeAmount))
+				amountY.Add(amountY, new(big.Int).Add(costY, feeAmount))
+				amountX.Add(amountX, acquireX)
+
+				limitOrders[orderData.LimitOrderIdx].SellingX = new(big.Int).Sub(currX, acquireX)
+				orderData.ConsumeLimitOrder(true)
+			} else {
+				finished = true
+			}
+		}
+
+		if finished {
+			break
+		}
+
+		nextPoint := orderData.MoveY2X(currentPoint, pointDelta)
+		if nextPoint > highPt {
+			nextPoint = highPt
+		}
+
+		// in [currentPoint, nextPoint)
+		if liquidity.Sign() == 0 {
+			currentPoint = nextPoint
+			sqrtPrice_96, _ = calc.GetSqrtPrice(currentPoint)
+			if orderData.IsLiquidity(currentPoint) {
+				delta := orderData.UnsafeGetDeltaLiquidity()
+				liquidity.Add(liquidity, delta)
+				liquidityX = liquidity
+			}
+		} else {
+			amountNoFee := getAmountNoFee(amount, fee)
+			if amountNoFee.Sign() > 0 {
+				st := utils.State{
+					LiquidityX:   new(big.Int).Set(liquidityX),
+					Liquidity:    new(big.Int).Set(liquidity),
+					CurrentPoint: currentPoint,
+					SqrtPrice_96: sqrtPrice_96,
+				}
+				retState := swapmath.Y2XRange(st, nextPoint, sqrtRate_96, new(big.Int).Set(amountNoFee))
+				finished = retState.Finished
+
+				feeAmount := getFeeAmount(amount, amountNoFee, retState.CostY, fee)
+				amountX.Add(amountX, retState.AcquireX)
+				amountY.Add(amountY, new(big.Int).Add(retState.CostY, feeAmount))
+				amount.Sub(amount, new(big.Int).Add(retState.CostY, feeAmount))
+
+				currentPoint = retState.FinalPt
+				sqrtPrice_96 = retState.SqrtFinalPrice_96
+				liquidityX = retState.LiquidityX
+			} else {
+				finished = true
+			}
+
+			if currentPoint == nextPoint {
+				if orderData.IsLiquidity(nextPoint) {
+					delta := orderData.UnsafeGetDeltaLiquidity()
+					liquidity.Add(liquidity, delta)
+				}
+				liquidityX = liquidity
+			}
+		}
+	}
+
+	return swapResult{
+		SwapResult: swap.SwapResult{
+			CurrentPoint: currentPoint,
+			Liquidity:    liquidity,
+			LiquidityX:   liquidityX,
+			AmountX:      amountX,
+			AmountY:      amountY,
+		},
+		limitOrders: limitOrders,
+	}, nil
+}
+
+// getAmountNoFee returns the part of amount left after the fee
+func getAmountNoFee(amount *big.Int, fee int) *big.Int {
+	amountNoFee := new(big.Int).Mul(amount, big.NewInt(int64(1e6-fee)))
+	return amountNoFee.Div(amountNoFee, feeBase)
+}
+
+// getFeeAmount returns the fee paid for cost, the whole amount left is spent when cost uses all of amountNoFee
+func getFeeAmount(amount, amountNoFee, cost *big.Int, fee int) *big.Int {
+	if cost.Cmp(amountNoFee) >= 0 {
+		return new(big.Int).Sub(amount, cost)
+	}
+
+	// fee is rounded up
+	numerator := new(big.Int).Mul(cost, big.NewInt(int64(fee)))
+	feeAmount, mod := new(big.Int).DivMod(numerator, big.NewInt(int64(1e6-fee)), new(big.Int))
+	if mod.Sign() > 0 {
+		feeAmount.Add(feeAmount, big.NewInt(1))
+	}
+	return feeAmount
+}
@@ -45,7 +45,18 @@ type State struct {
 	LiquidityX              *big.Int `abi:"liquidityX"`
 }
 
-type Extra swap.PoolInfo
+type Extra struct {
+	swap.PoolInfo
+	// Window is the range of points of which the tracker fetched the liquidity and the limit orders, it is nil in the
+	// extra of pools tracked before it was recorded
+	Window *PointWindow `json:"window,omitempty"`
+}
+
+// PointWindow is the range [LeftPoint, RightPoint) of points known to the simulator
+type PointWindow struct {
+	LeftPoint  int `json:"leftPoint"`
+	RightPoint int `json:"rightPoint"`
+}
 
 type FetchRPCResult struct {
 	state    State
@@ -64,9 +75,10 @@ type LimitOrder struct {
 }
 
 type iZiSwapInfo struct {
-	nextPoint      int
-	nextLiquidity  *big.Int
-	nextLiquidityX *big.Int
+	nextPoint       int
+	nextLiquidity   *big.Int
+	nextLiquidityX  *big.Int
+	nextLimitOrders []swap.LimitOrderPoint
 }
 
 type Metadata struct {
@@ -0,0 +1,169 @@
+package iziswap
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+)
+
+var (
+	// sqrtRate is the square root of the price ratio between two adjacent points
+	sqrtRate = math.Sqrt(1.0001)
+)
+
+// getAdaptiveSnapshot fetches the liquidity and the limit orders within PointRange of the current point, then doubles
+// every side of the range whose depth is below DepthUSD until it is covered or the side is MaxPointRange away from
+// the current point. reserveX and reserveY are the balances of the pool used to price the tokens.
+func (d *PoolTracker) getAdaptiveSnapshot(
+	ctx context.Context,
+	p entity.Pool,
+	poolInfo swap.PoolInfo,
+	reserveX *big.Int,
+	reserveY *big.Int,
+) ([]swap.LiquidityPoint, []swap.LimitOrderPoint, PointWindow, error) {
+	leftPoint, rightPoint := getSnapshotRange(poolInfo, d.config.PointRange)
+	liquidities, limitOrders, err := d.getSnapshot(ctx, p, poolInfo.PointDelta, leftPoint, rightPoint)
+	if err != nil {
+		return nil, nil, PointWindow{}, err
+	}
+	if d.config.DepthUSD <= 0 {
+		return liquidities, limitOrders, PointWindow{LeftPoint: leftPoint, RightPoint: rightPoint}, nil
+	}
+
+	maxLeftPoint, maxRightPoint := getSnapshotRange(poolInfo, d.config.MaxPointRange)
+	for {
+		poolInfo.Liquidities, poolInfo.LimitOrders = liquidities, limitOrders
+		leftDepth, rightDepth, ok := getDepthUSD(p.ReserveUsd, reserveX, reserveY, poolInfo, leftPoint, rightPoint)
+		if !ok {
+			break
+		}
+
+		newLeftPoint, newRightPoint := leftPoint, rightPoint
+		if leftDepth < d.config.DepthUSD && leftPoint > maxLeftPoint {
+			newLeftPoint, _ = getSnapshotRange(poolInfo, 2*(poolInfo.CurrentPoint-leftPoint))
+			newLeftPoint = max(newLeftPoint, maxLeftPoint)
+		}
+		if rightDepth < d.config.DepthUSD && rightPoint < maxRightPoint {
+			_, newRightPoint = getSnapshotRange(poolInfo, 2*(rightPoint-poolInfo.CurrentPoint))
+			newRightPoint = min(newRightPoint, maxRightPoint)
+		}
+		if newLeftPoint == leftPoint && newRightPoint == rightPoint {
+			break
+		}
+
+		if newLeftPoint < leftPoint {
+			leftLiquidities, leftLimitOrders, err := d.getSnapshot(ctx, p, poolInfo.PointDelta, newLeftPoint, leftPoint)
+			if err != nil {
+				return nil, nil, PointWindow{}, err
+			}
+			liquidities = append(leftLiquidities, liquidities...)
+			limitOrders = append(leftLimitOrders, limitOrders...)
+		}
+		if newRightPoint > rightPoint {
+			rightLiquidities, rightLimitOrders, err := d.getSnapshot(ctx, p, poolInfo.PointDelta, rightPoint, newRightPoint)
+			if err != nil {
+				return nil, nil, PointWindow{}, err
+			}
+			liquidities = append(liquidities, rightLiquidities...)
+			limitOrders = append(limitOrders, rightLimitOrders...)
+		}
+		leftPoint, rightPoint = newLeftPoint, newRightPoint
+	}
+
+	return liquidities, limitOrders, PointWindow{LeftPoint: leftPoint, RightPoint: rightPoint}, nil
+}
+
+// getDepthUSD estimates the value in USD of the token y sold by [leftPoint, currentPoint], to swaps from x to y, and
+// of the token x sold by [currentPoint, rightPoint), to swaps from y to x, counting the range liquidity and the limit
+// orders. The tokens are priced from reserveUsd split between the reserves at the current price, ok is false when
+// they can not be priced.
+func getDepthUSD(
+	reserveUsd float64,
+	reserveX *big.Int,
+	reserveY *big.Int,
+	poolInfo swap.PoolInfo,
+	leftPoint int,
+	rightPoint int,
+) (leftDepth float64, rightDepth float64, ok bool) {
+	currentPoint := poolInfo.CurrentPoint
+	price := math.Pow(1.0001, float64(currentPoint))
+	valueInX := toFloat(reserveX) + toFloat(reserveY)/price
+	if reserveUsd <= 0 || valueInX <= 0 {
+		return 0, 0, false
+	}
+	usdPerX := reserveUsd / valueInX
+	usdPerY := usdPerX / price
+
+	liquidities := poolInfo.Liquidities
+	sqrtPrice := math.Sqrt(price)
+	liquidity := toFloat(poolInfo.Liquidity)
+	liquidityX := toFloat(poolInfo.LiquidityX)
+
+	// y to x, the x of the current point is held by liquidityX
+	amountX := liquidityX / sqrtPrice
+	firstRight := sort.Search(len(liquidities), func(i int) bool { return liquidities[i].Point > currentPoint })
+	point, l := currentPoint+1, liquidity
+	for _, lp := range liquidities[firstRight:] {
+		if lp.Point >= rightPoint {
+			break
+		}
+		amountX += l * sumX(point, lp.Point)
+		l += toFloat(lp.LiqudityDelta)
+		point = lp.Point
+	}
+	amountX += l * sumX(point, rightPoint)
+
+	// x to y, the y of the current point is held by the rest of the liquidity
+	amountY := (liquidity - liquidityX) * sqrtPrice
+	point, l = currentPoint, liquidity
+	for i := firstRight - 1; i >= 0; i-- {
+		lp := liquidities[i]
+		if lp.Point < leftPoint {
+			break
+		}
+		amountY += l * sumY(lp.Point, point)
+		l -= toFloat(lp.LiqudityDelta)
+		point = lp.Point
+	}
+	amountY += l * sumY(leftPoint, point)
+
+	for _, order := range poolInfo.LimitOrders {
+		if order.Point >= currentPoint && order.Point < rightPoint {
+			amountX += toFloat(order.SellingX)
+		}
+		if order.Point <= currentPoint && order.Point >= leftPoint {
+			amountY += toFloat(order.SellingY)
+		}
+	}
+
+	return amountY * usdPerY, amountX * usdPerX, true
+}
+
+// sumX returns the x held by a unit of liquidity over the points [fromPoint, toPoint)
+func sumX(fromPoint, toPoint int) float64 {
+	if fromPoint >= toPoint {
+		return 0
+	}
+	return (math.Pow(sqrtRate, -float64(fromPoint)) - math.Pow(sqrtRate, -float64(toPoint))) / (1 - 1/sqrtRate)
+}
+
+// sumY returns the y held by a unit of liquidity over the points [fromPoint, toPoint)
+func sumY(fromPoint, toPoint int) float64 {
+	if fromPoint >= toPoint {
+		return 0
+	}
+	return (math.Pow(sqrtRate, float64(toPoint)) - math.Pow(sqrtRate, float64(fromPoint))) / (sqrtRate - 1)
+}
+
+func toFloat(v *big.Int) float64 {
+	if v == nil {
+		return 0
+	}
+	f, _ := new(big.Float).SetInt(v).Float64()
+	return f
+}
@@ -0,0 +1,56 @@
+package iziswap
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+func TestGetDepthUSD(t *testing.T) {
+	poolInfo := swap.PoolInfo{
+		CurrentPoint: 28912,
+		PointDelta:   8,
+		LeftMostPt:   -800000,
+		RightMostPt:  800000,
+		Fee:          400,
+		Liquidity:    bignumber.NewBig10("23123688144702854"),
+		LiquidityX:   bignumber.NewBig10("8210612878032008"),
+		Liquidities: []swap.LiquidityPoint{
+			{LiqudityDelta: bignumber.NewBig10("23123688144702854"), Point: 28728},
+			{LiqudityDelta: bignumber.NewBig10("10000000000000000"), Point: 28800},
+			{LiqudityDelta: bignumber.NewBig10("-10000000000000000"), Point: 29000},
+			{LiqudityDelta: bignumber.NewBig10("-23123688144702854"), Point: 29128},
+		},
+		LimitOrders: []swap.LimitOrderPoint{
+			{SellingX: bignumber.NewBig10("100000000000000000"), SellingY: big.NewInt(0), Point: 28960},
+			{SellingX: big.NewInt(0), SellingY: bignumber.NewBig10("1000000000000000000"), Point: 28840},
+		},
+	}
+	leftPoint, rightPoint := 28760, 29080
+
+	// reserveUsd worth the reserves in x makes 1 x worth 1 USD
+	reserveX, reserveY := bignumber.NewBig10("1167087113545385273"), bignumber.NewBig10("18037620383221447465")
+	price := math.Pow(1.0001, float64(poolInfo.CurrentPoint))
+	reserveUsd := toFloat(reserveX) + toFloat(reserveY)/price
+
+	leftDepth, rightDepth, ok := getDepthUSD(reserveUsd, reserveX, reserveY, poolInfo, leftPoint, rightPoint)
+	require.True(t, ok)
+
+	// the depth is what a swap big enough to reach the end of the window gets
+	x2y, err := swapX2Y(bignumber.NewBig10("100000000000000000000"), leftPoint, poolInfo)
+	require.NoError(t, err)
+	assert.InEpsilon(t, toFloat(x2y.AmountY)/price, leftDepth, 1e-4)
+
+	y2x, err := swapY2X(bignumber.NewBig10("100000000000000000000"), rightPoint, poolInfo)
+	require.NoError(t, err)
+	assert.InEpsilon(t, toFloat(y2x.AmountX), rightDepth, 1e-4)
+
+	_, _, ok = getDepthUSD(0, reserveX, reserveY, poolInfo, leftPoint, rightPoint)
+	assert.False(t, ok)
+}
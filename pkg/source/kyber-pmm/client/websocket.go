@@ -0,0 +1,215 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/gorilla/websocket"
+
+	kyberpmm "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/kyber-pmm"
+)
+
+const (
+	wsMethodSubscribe = "subscribe"
+
+	wsChannelPriceLevels = "price_levels"
+	wsChannelBalances    = "balances"
+
+	defaultReconnectInterval = 5 * time.Second
+)
+
+type wsSubscribeMessage struct {
+	Method   string   `json:"method"`
+	Channels []string `json:"channels"`
+}
+
+// wsPushMessage is a push on the stream. A price_levels push replaces the book of every pair it contains, a pair
+// pushed without bids and asks is removed. A balances push replaces the balance of every token it contains.
+type wsPushMessage struct {
+	Channel  string                        `json:"channel"`
+	Prices   map[string]kyberpmm.PriceItem `json:"prices"`
+	Balances map[string]float64            `json:"balances"`
+}
+
+// websocketClient keeps an in-memory order book fed by the price level and balance pushes of the stream, and falls
+// back to fallbackClient (HTTP polling) while the stream is down, stale or has not sent a full snapshot yet.
+type websocketClient struct {
+	config         *kyberpmm.WebSocketConfig
+	fallbackClient kyberpmm.IClient
+	dialer         *websocket.Dialer
+
+	mu          sync.RWMutex
+	connected   bool
+	hasPrices   bool
+	hasBalances bool
+	lastUpdate  time.Time
+	prices      map[string]kyberpmm.PriceItem
+	balances    map[string]float64
+}
+
+func NewWebSocketClient(
+	config *kyberpmm.WebSocketConfig,
+	fallbackClient kyberpmm.IClient,
+) *websocketClient {
+	return &websocketClient{
+		config:         config,
+		fallbackClient: fallbackClient,
+		dialer:         websocket.DefaultDialer,
+		prices:         make(map[string]kyberpmm.PriceItem),
+		balances:       make(map[string]float64),
+	}
+}
+
+// Run keeps the stream connected until ctx is done, dialing again after ReconnectInterval whenever it is closed
+func (c *websocketClient) Run(ctx context.Context) {
+	reconnectInterval := c.config.ReconnectInterval.Duration
+	if reconnectInterval <= 0 {
+		reconnectInterval = defaultReconnectInterval
+	}
+
+	for {
+		if err := c.stream(ctx); err != nil && ctx.Err() == nil {
+			logger.
+				WithFields(logger.Fields{"url": c.config.URL, "error": err}).
+				Warn("websocket stream closed, falling back to http")
+		}
+		c.setDisconnected()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectInterval):
+		}
+	}
+}
+
+func (c *websocketClient) stream(ctx context.Context) error {
+	conn, _, err := c.dialer.DialContext(ctx, c.config.URL, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Unblock ReadJSON when ctx is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err = conn.WriteJSON(wsSubscribeMessage{
+		Method:   wsMethodSubscribe,
+		Channels: []string{wsChannelPriceLevels, wsChannelBalances},
+	}); err != nil {
+		return err
+	}
+	c.setConnected()
+
+	for {
+		var msg wsPushMessage
+		if err = conn.ReadJSON(&msg); err != nil {
+			return err
+		}
+		c.apply(msg)
+	}
+}
+
+func (c *websocketClient) setConnected() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The book of a previous connection may have missed pushes, wait for a new snapshot
+	c.connected = true
+	c.hasPrices = false
+	c.hasBalances = false
+	c.prices = make(map[string]kyberpmm.PriceItem)
+	c.balances = make(map[string]float64)
+}
+
+func (c *websocketClient) setDisconnected() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.connected = false
+}
+
+func (c *websocketClient) apply(msg wsPushMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch msg.Channel {
+	case wsChannelPriceLevels:
+		for pairID, priceItem := range msg.Prices {
+			if len(priceItem.Bids) == 0 && len(priceItem.Asks) == 0 {
+				delete(c.prices, pairID)
+				continue
+			}
+			c.prices[pairID] = priceItem
+		}
+		c.hasPrices = true
+	case wsChannelBalances:
+		for token, balance := range msg.Balances {
+			c.balances[token] = balance
+		}
+		c.hasBalances = true
+	default:
+		return
+	}
+
+	c.lastUpdate = time.Now()
+}
+
+func (c *websocketClient) ListTokens(ctx context.Context) (map[string]kyberpmm.TokenItem, error) {
+	return c.fallbackClient.ListTokens(ctx)
+}
+
+func (c *websocketClient) ListPairs(ctx context.Context) (map[string]kyberpmm.PairItem, error) {
+	return c.fallbackClient.ListPairs(ctx)
+}
+
+func (c *websocketClient) ListPriceLevels(ctx context.Context) (kyberpmm.ListPriceLevelsResult, error) {
+	if priceLevels, ok := c.listPriceLevelsFromStream(); ok {
+		return priceLevels, nil
+	}
+
+	// Stream is not usable. Using fallbackClient
+	return c.fallbackClient.ListPriceLevels(ctx)
+}
+
+// listPriceLevelsFromStream only returns if the stream is connected, has a full snapshot and is not stale
+func (c *websocketClient) listPriceLevelsFromStream() (kyberpmm.ListPriceLevelsResult, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.connected || !c.hasPrices || !c.hasBalances {
+		return kyberpmm.ListPriceLevelsResult{}, false
+	}
+	if maxStaleness := c.config.MaxStaleness.Duration; maxStaleness > 0 && time.Since(c.lastUpdate) > maxStaleness {
+		return kyberpmm.ListPriceLevelsResult{}, false
+	}
+
+	// Pushes replace whole entries, so copying the maps is enough
+	prices := make(map[string]kyberpmm.PriceItem, len(c.prices))
+	for pairID, priceItem := range c.prices {
+		prices[pairID] = priceItem
+	}
+	balances := make(map[string]float64, len(c.balances))
+	for token, balance := range c.balances {
+		balances[token] = balance
+	}
+
+	return kyberpmm.ListPriceLevelsResult{
+		Prices:   prices,
+		Balances: balances,
+	}, true
+}
+
+func (c *websocketClient) Firm(ctx context.Context, params kyberpmm.FirmRequestParams) (kyberpmm.FirmResult, error) {
+	return c.fallbackClient.Firm(ctx, params)
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KyberNetwork/blockchain-toolkit/time/durationjson"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	kyberpmm "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/kyber-pmm"
+)
+
+type fallbackClient struct {
+	kyberpmm.IClient
+	listPriceLevelsCalls atomic.Int32
+}
+
+func (c *fallbackClient) ListPriceLevels(_ context.Context) (kyberpmm.ListPriceLevelsResult, error) {
+	c.listPriceLevelsCalls.Add(1)
+
+	return kyberpmm.ListPriceLevelsResult{
+		Prices:   map[string]kyberpmm.PriceItem{"http": {Bids: [][]string{{"1", "1"}}}},
+		Balances: map[string]float64{"USDC": 1},
+	}, nil
+}
+
+// wsServer is a local stand-in of the price stream, every connection is handed to the test through conns
+type wsServer struct {
+	*httptest.Server
+	conns chan *websocket.Conn
+}
+
+func newWSServer(t *testing.T) *wsServer {
+	s := &wsServer{conns: make(chan *websocket.Conn, 4)}
+	upgrader := websocket.Upgrader{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		var msg wsSubscribeMessage
+		if err = conn.ReadJSON(&msg); err != nil {
+			_ = conn.Close()
+			return
+		}
+		assert.Equal(t, wsMethodSubscribe, msg.Method)
+		assert.ElementsMatch(t, []string{wsChannelPriceLevels, wsChannelBalances}, msg.Channels)
+
+		s.conns <- conn
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *wsServer) url() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func (s *wsServer) accept(t *testing.T) *websocket.Conn {
+	select {
+	case conn := <-s.conns:
+		t.Cleanup(func() { _ = conn.Close() })
+		return conn
+	case <-time.After(time.Second):
+		require.FailNow(t, "no websocket connection")
+		return nil
+	}
+}
+
+func pushSnapshot(t *testing.T, conn *websocket.Conn, prices map[string]kyberpmm.PriceItem) {
+	require.NoError(t, conn.WriteJSON(wsPushMessage{Channel: wsChannelPriceLevels, Prices: prices}))
+	require.NoError(t, conn.WriteJSON(wsPushMessage{
+		Channel:  wsChannelBalances,
+		Balances: map[string]float64{"USDC": 100, "WETH": 2},
+	}))
+}
+
+func runWebSocketClient(t *testing.T, config *kyberpmm.WebSocketConfig) (*websocketClient, *fallbackClient) {
+	fallback := &fallbackClient{}
+	c := NewWebSocketClient(config, fallback)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	return c, fallback
+}
+
+func fromStream(c *websocketClient) func() bool {
+	return func() bool {
+		_, ok := c.listPriceLevelsFromStream()
+		return ok
+	}
+}
+
+func TestWebSocketClient_ServesPushedPriceLevels(t *testing.T) {
+	server := newWSServer(t)
+	c, fallback := runWebSocketClient(t, &kyberpmm.WebSocketConfig{URL: server.url()})
+
+	conn := server.accept(t)
+
+	// No snapshot yet, polls http
+	result, err := c.ListPriceLevels(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, result.Prices, "http")
+	assert.EqualValues(t, 1, fallback.listPriceLevelsCalls.Load())
+
+	pushSnapshot(t, conn, map[string]kyberpmm.PriceItem{
+		"USDC/WETH": {Bids: [][]string{{"1800", "1"}}, Asks: [][]string{{"1810", "1"}}},
+		"WBTC/WETH": {Bids: [][]string{{"16", "1"}}},
+	})
+	require.Eventually(t, fromStream(c), time.Second, 10*time.Millisecond)
+
+	result, err = c.ListPriceLevels(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"USDC": 100, "WETH": 2}, result.Balances)
+	assert.Equal(t, [][]string{{"1800", "1"}}, result.Prices["USDC/WETH"].Bids)
+	assert.Contains(t, result.Prices, "WBTC/WETH")
+
+	// Incremental push replaces one pair and removes another
+	require.NoError(t, conn.WriteJSON(wsPushMessage{
+		Channel: wsChannelPriceLevels,
+		Prices: map[string]kyberpmm.PriceItem{
+			"USDC/WETH": {Bids: [][]string{{"1790", "2"}}},
+			"WBTC/WETH": {},
+		},
+	}))
+	require.Eventually(t, func() bool {
+		result, err := c.ListPriceLevels(context.Background())
+		return err == nil && len(result.Prices) == 1 &&
+			assert.ObjectsAreEqual([][]string{{"1790", "2"}}, result.Prices["USDC/WETH"].Bids)
+	}, time.Second, 10*time.Millisecond)
+
+	assert.EqualValues(t, 1, fallback.listPriceLevelsCalls.Load())
+}
+
+func TestWebSocketClient_FallsBackOnDisconnect(t *testing.T) {
+	server := newWSServer(t)
+	c, fallback := runWebSocketClient(t, &kyberpmm.WebSocketConfig{
+		URL:               server.url(),
+		ReconnectInterval: durationjson.Duration{Duration: 50 * time.Millisecond},
+	})
+
+	conn := server.accept(t)
+	pushSnapshot(t, conn, map[string]kyberpmm.PriceItem{"USDC/WETH": {Bids: [][]string{{"1800", "1"}}}})
+	require.Eventually(t, fromStream(c), time.Second, 10*time.Millisecond)
+
+	require.NoError(t, conn.Close())
+	require.Eventually(t, func() bool { return !fromStream(c)() }, time.Second, 10*time.Millisecond)
+
+	result, err := c.ListPriceLevels(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, result.Prices, "http")
+	assert.EqualValues(t, 1, fallback.listPriceLevelsCalls.Load())
+
+	// Reconnects and waits for a fresh snapshot before serving the stream again
+	conn = server.accept(t)
+	assert.False(t, fromStream(c)())
+	pushSnapshot(t, conn, map[string]kyberpmm.PriceItem{"USDC/WETH": {Bids: [][]string{{"1795", "1"}}}})
+	require.Eventually(t, fromStream(c), time.Second, 10*time.Millisecond)
+
+	result, err = c.ListPriceLevels(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"1795", "1"}}, result.Prices["USDC/WETH"].Bids)
+}
+
+func TestWebSocketClient_FallsBackWhenStale(t *testing.T) {
+	server := newWSServer(t)
+	c, fallback := runWebSocketClient(t, &kyberpmm.WebSocketConfig{
+		URL:          server.url(),
+		MaxStaleness: durationjson.Duration{Duration: 100 * time.Millisecond},
+	})
+
+	conn := server.accept(t)
+	pushSnapshot(t, conn, map[string]kyberpmm.PriceItem{"USDC/WETH": {Bids: [][]string{{"1800", "1"}}}})
+	require.Eventually(t, fromStream(c), time.Second, 10*time.Millisecond)
+
+	require.Eventually(t, func() bool { return !fromStream(c)() }, time.Second, 10*time.Millisecond)
+
+	result, err := c.ListPriceLevels(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, result.Prices, "http")
+	assert.EqualValues(t, 1, fallback.listPriceLevelsCalls.Load())
+}
@@ -2,13 +2,22 @@ package kyberpmm
 
 import (
 	"github.com/KyberNetwork/blockchain-toolkit/time/durationjson"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
 )
 
 type Config struct {
 	DexID              string            `json:"dexID,omitempty"`
+	ChainID            uint              `mapstructure:"chain_id" json:"chain_id,omitempty"`
 	RFQContractAddress string            `mapstructure:"rfq_contract_address" json:"rfq_contract_address,omitempty"`
 	HTTP               HTTPConfig        `mapstructure:"http" json:"http,omitempty"`
 	MemoryCache        MemoryCacheConfig `mapstructure:"memory_cache" json:"memory_cache,omitempty"`
+	WebSocket          WebSocketConfig   `mapstructure:"websocket" json:"websocket,omitempty"`
+	RFQ                rfq.Config        `mapstructure:"rfq" json:"rfq,omitempty"`
+	// RFQDomain overrides the EIP-712 domain of the rfq contract, "KyberSwap RFQ" version "1"
+	RFQDomain rfq.Domain `mapstructure:"rfq_domain" json:"rfq_domain,omitempty"`
+	// Makers are the addresses allowed to sign firm orders, the orders of any other maker are rejected
+	Makers []string `mapstructure:"makers" json:"makers,omitempty"`
 }
 
 type HTTPConfig struct {
@@ -24,3 +33,12 @@ type MemoryCacheConfig struct {
 		PriceLevels durationjson.Duration `mapstructure:"price_levels" json:"price_levels,omitempty"`
 	} `mapstructure:"ttl"`
 }
+
+type WebSocketConfig struct {
+	URL string `mapstructure:"url" json:"url,omitempty"`
+	// ReconnectInterval is the wait before dialing again after the stream is closed, price levels are polled over
+	// HTTP in the meantime
+	ReconnectInterval durationjson.Duration `mapstructure:"reconnect_interval" json:"reconnect_interval,omitempty"`
+	// MaxStaleness is how long the order book is served without any push, zero disables the check
+	MaxStaleness durationjson.Duration `mapstructure:"max_staleness" json:"max_staleness,omitempty"`
+}
@@ -1,11 +1,15 @@
 package kyberpmm
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+)
 
 var (
 	ErrTokenNotFound          = errors.New("token not found")
 	ErrNoPriceLevelsForPool   = errors.New("no price levels for pool")
-	ErrEmptyPriceLevels       = errors.New("empty price levels")
-	ErrInsufficientLiquidity  = errors.New("insufficient liquidity")
+	ErrEmptyPriceLevels       = rfq.ErrEmptyPriceLevels
+	ErrInsufficientLiquidity  = rfq.ErrInsufficientLiquidity
 	ErrInvalidFirmQuoteParams = errors.New("invalid firm quote params")
 )
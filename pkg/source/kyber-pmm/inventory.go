@@ -0,0 +1,32 @@
+package kyberpmm
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// NewInventory returns the inventory of the maker built from the balances of the price levels result. The balances
+// are keyed by token symbol, tokens maps the symbols to the token addresses and decimals, and the balances of unknown
+// tokens are skipped. The maker is the DexID of the pools.
+func NewInventory(maker string, result ListPriceLevelsResult, tokens map[string]TokenItem) *pool.Inventory {
+	balances := make(map[string]*big.Int, len(result.Balances))
+	for symbol, balance := range result.Balances {
+		token, ok := tokens[symbol]
+		if !ok {
+			continue
+		}
+		balances[token.Address] = toWeiBalance(balance, token.Decimals)
+	}
+
+	return pool.NewInventoryWithBalances(maker, balances)
+}
+
+// toWeiBalance converts a balance of the API into wei
+func toWeiBalance(balance float64, decimals uint8) *big.Int {
+	wei, _ := new(big.Float).
+		Mul(new(big.Float).SetFloat64(balance), new(big.Float).Set(bignumber.TenPowDecimals(decimals))).
+		Int(big.NewInt(0))
+	return wei
+}
@@ -0,0 +1,99 @@
+package kyberpmm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+func TestNewInventory(t *testing.T) {
+	inventory := NewInventory(DexTypeKyberPMM, ListPriceLevelsResult{
+		Balances: map[string]float64{"WETH": 1.5, "USDC": 2000, "DAI": 5},
+	}, map[string]TokenItem{
+		"WETH": {Symbol: "WETH", Address: testBaseToken, Decimals: 18},
+		"USDC": {Symbol: "USDC", Address: testQuoteToken, Decimals: 6},
+	})
+
+	assert.Equal(t, big.NewInt(1.5e18), inventory.GetMakerBalance(DexTypeKyberPMM, testBaseToken))
+	assert.Equal(t, big.NewInt(2000e6), inventory.GetMakerBalance(DexTypeKyberPMM, testQuoteToken))
+	assert.False(t, inventory.HasMakerBalance("other-pmm", testQuoteToken))
+}
+
+func TestPoolSimulator_UpdateInventory(t *testing.T) {
+	inventory := NewInventory(DexTypeKyberPMM, ListPriceLevelsResult{
+		Balances: map[string]float64{"WETH": 1, "USDC": 2000},
+	}, map[string]TokenItem{
+		"WETH": {Address: testBaseToken, Decimals: 18},
+		"USDC": {Address: testQuoteToken, Decimals: 6},
+	})
+	priceItem := PriceItem{Bids: [][]string{{"1800", "1"}}}
+	p1 := newTestPoolSimulator(t, priceItem, 18, 6)
+	p2 := newTestPoolSimulator(t, priceItem, 18, 6)
+
+	swap := func(p *PoolSimulator) error {
+		tokenAmountIn := pool.TokenAmount{Token: testBaseToken, Amount: big.NewInt(0.5e18)}
+		result, err := p.CalcAmountOut(tokenAmountIn, testQuoteToken)
+		require.NoError(t, err)
+		return p.UpdateInventory(pool.UpdateBalanceParams{
+			TokenAmountIn:  tokenAmountIn,
+			TokenAmountOut: *result.TokenAmountOut,
+			Inventory:      inventory,
+		})
+	}
+
+	require.NoError(t, swap(p1))
+	assert.Equal(t, big.NewInt(1100e6), p1.QuoteBalance)
+	assert.Equal(t, big.NewInt(1.5e18), p1.BaseBalance)
+	snapshot := inventory.Snapshot()
+
+	// The pools of the maker share its balances
+	require.NoError(t, swap(p2))
+	assert.Equal(t, big.NewInt(200e6), p2.QuoteBalance)
+
+	err := swap(p1)
+	assert.ErrorIs(t, err, pool.ErrNotEnoughInventory)
+	assert.Equal(t, big.NewInt(1100e6), p1.QuoteBalance)
+	assert.Equal(t, big.NewInt(200e6), inventory.GetMakerBalance(DexTypeKyberPMM, testQuoteToken))
+
+	// p2 knows the balance left, so it does not quote more
+	_, err = p2.CalcAmountOut(pool.TokenAmount{Token: testBaseToken, Amount: big.NewInt(0.5e18)}, testQuoteToken)
+	assert.ErrorIs(t, err, pool.ErrNotEnoughInventory)
+
+	inventory.Restore(snapshot)
+	assert.Equal(t, big.NewInt(1100e6), inventory.GetMakerBalance(DexTypeKyberPMM, testQuoteToken))
+}
+
+func TestPoolSimulator_CalcAmountOutWithInventory(t *testing.T) {
+	inventory := NewInventory(DexTypeKyberPMM, ListPriceLevelsResult{
+		Balances: map[string]float64{"WETH": 1, "USDC": 1000},
+	}, map[string]TokenItem{
+		"WETH": {Address: testBaseToken, Decimals: 18},
+		"USDC": {Address: testQuoteToken, Decimals: 6},
+	})
+	priceItem := PriceItem{Bids: [][]string{{"1800", "1"}}}
+	p1 := newTestPoolSimulator(t, priceItem, 18, 6)
+	p2 := newTestPoolSimulator(t, priceItem, 18, 6)
+
+	tokenAmountIn := pool.TokenAmount{Token: testBaseToken, Amount: big.NewInt(0.5e18)}
+	result, err := p1.CalcAmountOutWithInventory(tokenAmountIn, testQuoteToken, inventory)
+	require.NoError(t, err)
+	require.NoError(t, p1.UpdateInventory(pool.UpdateBalanceParams{
+		TokenAmountIn:  tokenAmountIn,
+		TokenAmountOut: *result.TokenAmountOut,
+		Inventory:      inventory,
+	}))
+
+	// p2 never swapped, so only the inventory knows the maker has 100 USDC left
+	_, err = p2.CalcAmountOut(tokenAmountIn, testQuoteToken)
+	require.NoError(t, err)
+	_, err = p2.CalcAmountOutWithInventory(tokenAmountIn, testQuoteToken, inventory)
+	assert.ErrorIs(t, err, pool.ErrNotEnoughInventory)
+
+	// the balance of the pool is used when the inventory does not track the maker
+	_, err = p2.CalcAmountOutWithInventory(tokenAmountIn, testQuoteToken, pool.NewInventory(nil))
+	require.NoError(t, err)
+}
@@ -0,0 +1,76 @@
+package kyberpmm
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+)
+
+const (
+	rfqDomainName    = "KyberSwap RFQ"
+	rfqDomainVersion = "1"
+	rfqOrderType     = "OrderRFQ"
+)
+
+var rfqOrderFields = []apitypes.Type{
+	{Name: "info", Type: "uint256"},
+	{Name: "expiry", Type: "uint256"},
+	{Name: "makerAsset", Type: "address"},
+	{Name: "takerAsset", Type: "address"},
+	{Name: "maker", Type: "address"},
+	{Name: "allowedSender", Type: "address"},
+	{Name: "makingAmount", Type: "uint256"},
+	{Name: "takingAmount", Type: "uint256"},
+}
+
+// TypedData returns the EIP-712 typed data of the order signed by the maker, the taker is the allowed sender
+func (e RFQExtra) TypedData(chainID uint) apitypes.TypedData {
+	return rfq.NewTypedData(rfqDomainName, rfqDomainVersion, chainID, e.RFQContractAddress, rfqOrderType, rfqOrderFields,
+		apitypes.TypedDataMessage{
+			"info":          e.Info,
+			"expiry":        strconv.FormatInt(e.Expiry, 10),
+			"makerAsset":    e.MakerAsset,
+			"takerAsset":    e.TakerAsset,
+			"maker":         e.Maker,
+			"allowedSender": e.Taker,
+			"makingAmount":  e.MakerAmount,
+			"takingAmount":  e.TakerAmount,
+		})
+}
+
+// validateOrder checks the firm order offline before it is encoded: the order must be signed by one of the configured
+// makers, not be expired, swap the requested assets for the requested amount in and give at least the indicative amount
+// out less the slippage tolerance. Every order is rejected when no maker is configured.
+func validateOrder(config *Config, req rfq.Request, order RFQExtra, now time.Time) error {
+	if order.Expiry <= now.Unix() {
+		return rfq.ErrQuoteExpired
+	}
+
+	if !strings.EqualFold(order.MakerAsset, req.TokenOut) || !strings.EqualFold(order.TakerAsset, req.TokenIn) {
+		return rfq.ErrQuoteMismatch
+	}
+
+	takerAmount, ok := new(big.Int).SetString(order.TakerAmount, 10)
+	if !ok || takerAmount.Cmp(req.AmountIn) != 0 {
+		return rfq.ErrQuoteMismatch
+	}
+	makerAmount, ok := new(big.Int).SetString(order.MakerAmount, 10)
+	if !ok {
+		return rfq.ErrQuoteMismatch
+	}
+	if makerAmount.Cmp(config.RFQ.MinAmountOut(req)) < 0 {
+		return rfq.ErrQuoteBelowSlippage
+	}
+
+	if !lo.ContainsBy(config.Makers, func(maker string) bool { return strings.EqualFold(maker, order.Maker) }) {
+		return rfq.ErrUnexpectedSigner
+	}
+
+	return rfq.VerifySigner(config.RFQDomain.Apply(order.TypedData(config.ChainID)), order.Signature, order.Maker)
+}
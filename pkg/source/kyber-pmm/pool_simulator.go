@@ -7,13 +7,12 @@ import (
 	"math/big"
 	"strings"
 
-	"github.com/KyberNetwork/blockchain-toolkit/float"
 	"github.com/KyberNetwork/blockchain-toolkit/integer"
-	"github.com/samber/lo"
+	"github.com/KyberNetwork/logger"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
-	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
 )
 
 type PoolSimulator struct {
@@ -98,6 +97,24 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 func (p *PoolSimulator) CalcAmountOut(
 	tokenAmountIn pool.TokenAmount,
 	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	return p.calcAmountOut(tokenAmountIn, tokenOut, nil)
+}
+
+// CalcAmountOutWithInventory caps the swap by what the maker has left in the inventory, the other pairs of the maker
+// in the request may have spent it
+func (p *PoolSimulator) CalcAmountOutWithInventory(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+	inventory *pool.Inventory,
+) (*pool.CalcAmountOutResult, error) {
+	return p.calcAmountOut(tokenAmountIn, tokenOut, inventory)
+}
+
+func (p *PoolSimulator) calcAmountOut(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+	inventory *pool.Inventory,
 ) (result *pool.CalcAmountOutResult, err error) {
 	swapDirection := p.getSwapDirection(tokenAmountIn.Token)
 
@@ -110,44 +127,63 @@ func (p *PoolSimulator) CalcAmountOut(
 		return nil, err
 	}
 
-	if (swapDirection == SwapDirectionQuoteToBase && result.TokenAmountOut.Amount.Cmp(p.BaseBalance) < 0) || (swapDirection == SwapDirectionBaseToQuote && result.TokenAmountOut.Amount.Cmp(p.QuoteBalance) < 0) {
-		return nil, errors.New("not enough inventory")
+	if result.TokenAmountOut.Amount.Cmp(p.getBalanceOut(swapDirection, inventory)) > 0 {
+		return nil, pool.ErrNotEnoughInventory
 	}
 	return result, nil
 }
 
 func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	if err := p.UpdateInventory(params); err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Info.Address,
+			"error":       err,
+		}).Warn("unable to update PMM inventory")
+	}
+}
+
+// UpdateInventory consumes the price levels of the swap and moves the balances of the maker. With an inventory the
+// balances are shared with the other pairs of the maker, which is identified by the exchange of the pool, and the
+// balances of this pool are used when the inventory does not track the maker yet.
+func (p *PoolSimulator) UpdateInventory(params pool.UpdateBalanceParams) error {
 	swapDirection := p.getSwapDirection(params.TokenAmountIn.Token)
+	tokenIn, tokenOut := p.baseToken, p.quoteToken
+	balanceIn, balanceOut := p.BaseBalance, p.QuoteBalance
+	if swapDirection == SwapDirectionQuoteToBase {
+		tokenIn, tokenOut = p.quoteToken, p.baseToken
+		balanceIn, balanceOut = p.QuoteBalance, p.BaseBalance
+	}
 
-	if swapDirection == SwapDirectionBaseToQuote {
-		amountInAfterDecimals := new(big.Float).Quo(
-			new(big.Float).SetInt(params.TokenAmountIn.Amount),
-			bignumber.TenPowDecimals(p.baseToken.Decimals),
-		)
+	var newBalanceIn, newBalanceOut *big.Int
+	if inventory := params.Inventory; inventory != nil {
+		maker := p.Info.Exchange
+		inventory.InitMakerBalance(maker, tokenIn.Address, balanceIn)
+		inventory.InitMakerBalance(maker, tokenOut.Address, balanceOut)
 
-		p.baseToQuotePriceLevels = getNewPriceLevelsState(amountInAfterDecimals, p.baseToQuotePriceLevels)
-		newQuoteInventory, newBaseInventory, err := params.Inventory.UpdateBalance(p.quoteToken.Address, p.baseToken.Address, params.TokenAmountOut.Amount, params.TokenAmountIn.Amount)
+		var err error
+		newBalanceOut, newBalanceIn, err = inventory.UpdateMakerBalance(maker, tokenOut.Address, tokenIn.Address,
+			params.TokenAmountOut.Amount, params.TokenAmountIn.Amount)
 		if err != nil {
-			fmt.Println("unable to update PMM info, error:", err)
+			return err
 		}
-		p.QuoteBalance = newQuoteInventory
-		p.BaseBalance = newBaseInventory
 	} else {
-		amountInAfterDecimals := new(big.Float).Quo(
-			new(big.Float).SetInt(params.TokenAmountIn.Amount),
-			bignumber.TenPowDecimals(p.quoteToken.Decimals),
-		)
-
-		p.quoteToBasePriceLevels = getNewPriceLevelsState(amountInAfterDecimals, p.quoteToBasePriceLevels)
-
-		newBaseInventory, newQuoteInventory, err := params.Inventory.UpdateBalance(p.baseToken.Address, p.quoteToken.Address, params.TokenAmountOut.Amount, params.TokenAmountIn.Amount)
-		if err != nil {
-			fmt.Println("unable to update PMM info, error:", err)
+		if balanceOut.Cmp(params.TokenAmountOut.Amount) < 0 {
+			return pool.ErrNotEnoughInventory
 		}
-		p.QuoteBalance = newQuoteInventory
-		p.BaseBalance = newBaseInventory
+		newBalanceOut = new(big.Int).Sub(balanceOut, params.TokenAmountOut.Amount)
+		newBalanceIn = new(big.Int).Add(balanceIn, params.TokenAmountIn.Amount)
+	}
 
+	amountInAfterDecimals := rfq.ToDecimalAmount(params.TokenAmountIn.Amount, tokenIn.Decimals)
+	if swapDirection == SwapDirectionBaseToQuote {
+		p.baseToQuotePriceLevels = rfq.FillPriceLevels(amountInAfterDecimals, p.baseToQuotePriceLevels)
+		p.BaseBalance, p.QuoteBalance = newBalanceIn, newBalanceOut
+	} else {
+		p.quoteToBasePriceLevels = rfq.FillPriceLevels(amountInAfterDecimals, p.quoteToBasePriceLevels)
+		p.QuoteBalance, p.BaseBalance = newBalanceIn, newBalanceOut
 	}
+
+	return nil
 }
 
 func (p *PoolSimulator) GetMetaInfo(_ string, _ string) interface{} {
@@ -156,6 +192,21 @@ func (p *PoolSimulator) GetMetaInfo(_ string, _ string) interface{} {
 	}
 }
 
+// getBalanceOut returns the balance of the maker in the token out of the swap direction, the one left in the
+// inventory when it tracks the maker
+func (p *PoolSimulator) getBalanceOut(swapDirection SwapDirection, inventory *pool.Inventory) *big.Int {
+	tokenOut, balanceOut := p.baseToken.Address, p.BaseBalance
+	if swapDirection == SwapDirectionBaseToQuote {
+		tokenOut, balanceOut = p.quoteToken.Address, p.QuoteBalance
+	}
+
+	if inventory != nil && inventory.HasMakerBalance(p.Info.Exchange, tokenOut) {
+		return inventory.GetMakerBalance(p.Info.Exchange, tokenOut)
+	}
+
+	return balanceOut
+}
+
 func (p *PoolSimulator) getSwapDirection(tokenIn string) SwapDirection {
 	if strings.EqualFold(tokenIn, p.baseToken.Address) {
 		return SwapDirectionBaseToQuote
@@ -165,20 +216,14 @@ func (p *PoolSimulator) getSwapDirection(tokenIn string) SwapDirection {
 }
 
 func (p *PoolSimulator) swapBaseToQuote(tokenAmountIn pool.TokenAmount, tokenOut string) (*pool.CalcAmountOutResult, error) {
-	amountInAfterDecimals := new(big.Float).Quo(
-		new(big.Float).SetInt(tokenAmountIn.Amount),
-		bignumber.TenPowDecimals(p.baseToken.Decimals),
-	)
+	amountInAfterDecimals := rfq.ToDecimalAmount(tokenAmountIn.Amount, p.baseToken.Decimals)
 
-	amountOutAfterDecimals, err := getAmountOut(amountInAfterDecimals, p.baseToQuotePriceLevels)
+	amountOutAfterDecimals, err := rfq.GetAmountOut(amountInAfterDecimals, p.baseToQuotePriceLevels)
 	if err != nil {
 		return nil, err
 	}
 
-	amountOut, _ := new(big.Float).Mul(
-		amountOutAfterDecimals,
-		bignumber.TenPowDecimals(p.quoteToken.Decimals),
-	).Int(nil)
+	amountOut := rfq.ToWeiAmount(amountOutAfterDecimals, p.quoteToken.Decimals)
 
 	return &pool.CalcAmountOutResult{
 		TokenAmountOut: &pool.TokenAmount{Token: tokenOut, Amount: amountOut},
@@ -194,20 +239,14 @@ func (p *PoolSimulator) swapBaseToQuote(tokenAmountIn pool.TokenAmount, tokenOut
 }
 
 func (p *PoolSimulator) swapQuoteToBase(tokenAmountIn pool.TokenAmount, tokenOut string) (*pool.CalcAmountOutResult, error) {
-	amountInAfterDecimals := new(big.Float).Quo(
-		new(big.Float).SetInt(tokenAmountIn.Amount),
-		bignumber.TenPowDecimals(p.quoteToken.Decimals),
-	)
+	amountInAfterDecimals := rfq.ToDecimalAmount(tokenAmountIn.Amount, p.quoteToken.Decimals)
 
-	amountOutAfterDecimals, err := getAmountOut(amountInAfterDecimals, p.quoteToBasePriceLevels)
+	amountOutAfterDecimals, err := rfq.GetAmountOut(amountInAfterDecimals, p.quoteToBasePriceLevels)
 	if err != nil {
 		return nil, err
 	}
 
-	amountOut, _ := new(big.Float).Mul(
-		amountOutAfterDecimals,
-		bignumber.TenPowDecimals(p.baseToken.Decimals),
-	).Int(nil)
+	amountOut := rfq.ToWeiAmount(amountOutAfterDecimals, p.baseToken.Decimals)
 
 	return &pool.CalcAmountOutResult{
 		TokenAmountOut: &pool.TokenAmount{Token: tokenOut, Amount: amountOut},
@@ -221,88 +260,3 @@ func (p *PoolSimulator) swapQuoteToBase(tokenAmountIn pool.TokenAmount, tokenOut
 		},
 	}, nil
 }
-
-func getAmountOut(amountIn *big.Float, priceLevels []PriceLevel) (*big.Float, error) {
-	if len(priceLevels) == 0 {
-		return nil, ErrEmptyPriceLevels
-	}
-
-	// Calculate the total available amount in the price levels
-	availableAmount := lo.Reduce(priceLevels, func(acc float64, priceLevel PriceLevel, _ int) float64 {
-		return acc + priceLevel.Amount
-	}, 0.0)
-
-	availableAmountBF := new(big.Float).SetFloat64(availableAmount)
-
-	// If the amount in is greater than the available amount that price levels can provide, return error insufficient liquidity
-	if amountIn.Cmp(availableAmountBF) > 0 {
-		return nil, ErrInsufficientLiquidity
-	}
-
-	amountOut := float.Zero()
-	amountInLeft := amountIn
-	currentLevelIdx := 0
-
-	for {
-		currentLevelAvailableAmount := new(big.Float).SetFloat64(priceLevels[currentLevelIdx].Amount)
-		swappableAmount := currentLevelAvailableAmount
-
-		if currentLevelAvailableAmount.Cmp(amountInLeft) > 0 {
-			swappableAmount = amountInLeft
-		}
-
-		amountOut = new(big.Float).Add(
-			amountOut,
-			new(big.Float).Mul(
-				swappableAmount, new(big.Float).SetFloat64(priceLevels[currentLevelIdx].Price),
-			),
-		)
-
-		amountInLeft = new(big.Float).Sub(amountInLeft, swappableAmount)
-		currentLevelIdx += 1
-
-		if amountInLeft.Cmp(float.Zero()) == 0 || currentLevelIdx > len(priceLevels)-1 {
-			break
-		}
-	}
-
-	return amountOut, nil
-}
-
-func getNewPriceLevelsState(
-	amountIn *big.Float,
-	priceLevels []PriceLevel,
-) []PriceLevel {
-	if len(priceLevels) == 0 {
-		return priceLevels
-	}
-
-	amountInLeft := amountIn
-	currentLevelIdx := 0
-
-	for {
-		currentLevelAvailableAmount := new(big.Float).SetFloat64(priceLevels[currentLevelIdx].Amount)
-		swappableAmount := currentLevelAvailableAmount
-
-		if currentLevelAvailableAmount.Cmp(amountInLeft) > 0 {
-			// Update the price level at the current step because it's partially filled
-			priceLevels[currentLevelIdx].Amount, _ = new(big.Float).Sub(currentLevelAvailableAmount, amountInLeft).Float64()
-
-			swappableAmount = amountInLeft
-		} else {
-			// Only increase the step if the current level is fully filled
-			currentLevelIdx += 1
-		}
-
-		amountInLeft = new(big.Float).Sub(amountInLeft, swappableAmount)
-
-		if amountInLeft.Cmp(float.Zero()) == 0 || currentLevelIdx > len(priceLevels)-1 {
-			// Get the remaining price levels
-			priceLevels = priceLevels[currentLevelIdx:]
-
-			break
-		}
-	}
-
-	return priceLevels
-}
@@ -1,186 +1,184 @@
 package kyberpmm
 
 import (
+	"encoding/json"
+	"fmt"
 	"math/big"
+	"math/rand"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	testBaseToken  = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+	testQuoteToken = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
 )
 
-func TestPoolSimulator_getAmountOut(t *testing.T) {
-	type args struct {
-		amountIn    *big.Float
-		priceLevels []PriceLevel
+// randomDecimal returns a decimal string with up to 18 fractional digits, like the prices and amounts of the API
+func randomDecimal(r *rand.Rand, maxInteger int64) string {
+	fraction := make([]byte, r.Intn(19))
+	for i := range fraction {
+		fraction[i] = byte('0' + r.Intn(10))
 	}
-	tests := []struct {
-		name              string
-		args              args
-		expectedAmountOut *big.Float
-		expectedErr       error
-	}{
-		{
-			name: "it should return error when price levels is empty",
-			args: args{
-				amountIn:    new(big.Float).SetFloat64(1),
-				priceLevels: []PriceLevel{},
-			},
-			expectedAmountOut: nil,
-			expectedErr:       ErrEmptyPriceLevels,
-		},
-		{
-			name: "it should return insufficient liquidity error when the requested amount is greater than available amount in price levels",
-			args: args{
-				amountIn: new(big.Float).SetFloat64(4),
-				priceLevels: []PriceLevel{
-					{
-						Price:  100,
-						Amount: 1,
-					},
-					{
-						Price:  99,
-						Amount: 2,
-					},
-				},
-			},
-			expectedAmountOut: nil,
-			expectedErr:       ErrInsufficientLiquidity,
-		},
-		{
-			name: "it should return correct amount out when fully filled",
-			args: args{
-				amountIn: new(big.Float).SetFloat64(1),
-				priceLevels: []PriceLevel{
-					{
-						Price:  100,
-						Amount: 1,
-					},
-				},
-			},
-			expectedAmountOut: new(big.Float).SetFloat64(100),
-			expectedErr:       nil,
-		},
-		{
-			name: "it should return correct amount out when partially filled",
-			args: args{
-				amountIn: new(big.Float).SetFloat64(2),
-				priceLevels: []PriceLevel{
-					{
-						Price:  100,
-						Amount: 1,
-					},
-					{
-						Price:  99,
-						Amount: 2,
-					},
-				},
-			},
-			expectedAmountOut: new(big.Float).SetFloat64(199),
-			expectedErr:       nil,
-		},
+	if len(fraction) == 0 {
+		return fmt.Sprint(r.Int63n(maxInteger) + 1)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			amountOut, err := getAmountOut(tt.args.amountIn, tt.args.priceLevels)
-			assert.Equal(t, tt.expectedErr, err)
+	return fmt.Sprintf("%d.%s", r.Int63n(maxInteger), fraction)
+}
 
-			if amountOut != nil {
-				assert.Equal(t, tt.expectedAmountOut.Cmp(amountOut), 0)
-			}
-		})
+func randomPriceItem(r *rand.Rand) PriceItem {
+	var priceItem PriceItem
+	for i := 0; i < 1+r.Intn(5); i++ {
+		priceItem.Bids = append(priceItem.Bids, []string{randomDecimal(r, 3000), randomDecimal(r, 10)})
+		priceItem.Asks = append(priceItem.Asks, []string{randomDecimal(r, 3000), randomDecimal(r, 10)})
 	}
+
+	return priceItem
 }
 
-func TestPoolSimulator_getNewPriceLevelsState(t *testing.T) {
-	type args struct {
-		amountIn    *big.Float
-		priceLevels []PriceLevel
-	}
-	tests := []struct {
-		name                string
-		args                args
-		expectedPriceLevels []PriceLevel
-	}{
-		{
-			name: "it should do nothing when price levels is empty",
-			args: args{
-				amountIn:    new(big.Float).SetFloat64(1),
-				priceLevels: []PriceLevel{},
-			},
-			expectedPriceLevels: []PriceLevel{},
-		},
-		{
-			name: "it should return correct new price levels when fully filled",
-			args: args{
-				amountIn: new(big.Float).SetFloat64(1),
-				priceLevels: []PriceLevel{
-					{
-						Price:  100,
-						Amount: 1,
-					},
-				},
-			},
-			expectedPriceLevels: []PriceLevel{},
-		},
-		{
-			name: "it should return correct new price levels when the amountIn is greater than the amount available in the single price level",
-			args: args{
-				amountIn: new(big.Float).SetFloat64(2),
-				priceLevels: []PriceLevel{
-					{
-						Price:  100,
-						Amount: 1,
-					},
-				},
-			},
-			expectedPriceLevels: []PriceLevel{},
-		},
-		{
-			name: "it should return correct new price levels when the amountIn is greater than the amount available in the all price levels",
-			args: args{
-				amountIn: new(big.Float).SetFloat64(5),
-				priceLevels: []PriceLevel{
-					{
-						Price:  100,
-						Amount: 1,
-					},
-					{
-						Price:  99,
-						Amount: 2,
-					},
-				},
-			},
-			expectedPriceLevels: []PriceLevel{},
-		},
-		{
-			name: "it should return correct new price levels when partially filled",
-			args: args{
-				amountIn: new(big.Float).SetFloat64(2),
-				priceLevels: []PriceLevel{
-					{
-						Price:  100,
-						Amount: 1,
-					},
-					{
-						Price:  99,
-						Amount: 2,
-					},
-				},
-			},
-			expectedPriceLevels: []PriceLevel{
-				{
-					Price:  99,
-					Amount: 1,
-				},
-			},
+func newTestPoolSimulator(t *testing.T, priceItem PriceItem, baseDecimals, quoteDecimals uint8) *PoolSimulator {
+	var extra Extra
+	extra.BaseToQuotePriceLevels, extra.QuoteToBasePriceLevels = transformPriceLevels(priceItem)
+	extraBytes, err := json.Marshal(extra)
+	require.NoError(t, err)
+	staticExtraBytes, err := json.Marshal(StaticExtra{BaseTokenAddress: testBaseToken, QuoteTokenAddress: testQuoteToken})
+	require.NoError(t, err)
+
+	poolSimulator, err := NewPoolSimulator(entity.Pool{
+		Address:  "kyber_pmm_test",
+		Exchange: DexTypeKyberPMM,
+		Type:     DexTypeKyberPMM,
+		Reserves: entity.PoolReserves{poolReserve, poolReserve},
+		Tokens: []*entity.PoolToken{
+			{Address: testBaseToken, Decimals: baseDecimals},
+			{Address: testQuoteToken, Decimals: quoteDecimals},
 		},
+		Extra:       string(extraBytes),
+		StaticExtra: string(staticExtraBytes),
+	})
+	require.NoError(t, err)
+
+	return poolSimulator
+}
+
+// scaledDecimal parses a decimal string with up to 18 fractional digits as an integer of 1e-18 units
+func scaledDecimal(s string) *big.Int {
+	integerPart, fractionPart, _ := strings.Cut(s, ".")
+	scaled, _ := new(big.Int).SetString(integerPart+fractionPart+strings.Repeat("0", 18-len(fractionPart)), 10)
+	return scaled
+}
+
+// firmBidAmountOut is the amount the maker signs when filling its bids: every level is filled exactly and the total
+// is rounded down once, computed with integers only
+func firmBidAmountOut(bids [][]string, amountIn *big.Int, baseDecimals, quoteDecimals uint8) *big.Int {
+	// the amount in and the fills are in 1e-18 units of base, the amount out in 1e-36 units of quote
+	amountInLeft := new(big.Int).Mul(amountIn, bignumber.TenPowInt(18-baseDecimals))
+	amountOut := new(big.Int)
+	for _, bid := range bids {
+		fill := scaledDecimal(bid[1])
+		if fill.Cmp(amountInLeft) > 0 {
+			fill = amountInLeft
+		}
+		amountOut.Add(amountOut, new(big.Int).Mul(fill, scaledDecimal(bid[0])))
+		amountInLeft = new(big.Int).Sub(amountInLeft, fill)
+	}
+
+	amountOut.Mul(amountOut, bignumber.TenPowInt(quoteDecimals))
+	return amountOut.Quo(amountOut, bignumber.TenPowInt(36))
+}
+
+// firmAskAmountOut is the amount of base the maker signs when a taker sells quote into its asks
+func firmAskAmountOut(asks [][]string, amountIn *big.Int, quoteDecimals, baseDecimals uint8) *big.Int {
+	amountInLeft := new(big.Rat).SetFrac(amountIn, bignumber.TenPowInt(quoteDecimals))
+	amountOut := new(big.Rat)
+	for _, ask := range asks {
+		price, _ := new(big.Rat).SetString(ask[0])
+		amount, _ := new(big.Rat).SetString(ask[1])
+		if price.Sign() == 0 {
+			continue
+		}
+
+		// the level sells amount of base for price * amount of quote
+		fill := new(big.Rat).Mul(price, amount)
+		if fill.Cmp(amountInLeft) > 0 {
+			fill = amountInLeft
+		}
+		amountOut.Add(amountOut, new(big.Rat).Quo(fill, price))
+		amountInLeft = new(big.Rat).Sub(amountInLeft, fill)
+	}
+
+	amountOut.Mul(amountOut, new(big.Rat).SetInt(bignumber.TenPowInt(baseDecimals)))
+	return new(big.Int).Quo(amountOut.Num(), amountOut.Denom())
+}
+
+func TestPoolSimulator_AmountOutNeverExceedsFirmAmount(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 500; i++ {
+		priceItem := randomPriceItem(r)
+		baseDecimals, quoteDecimals := uint8(18), []uint8{6, 8, 18}[r.Intn(3)]
+		poolSimulator := newTestPoolSimulator(t, priceItem, baseDecimals, quoteDecimals)
+
+		// up to 1 token of base, so most swaps cross several levels
+		amountIn := new(big.Int).Rand(r, bignumber.TenPowInt(baseDecimals))
+		amountIn.Add(amountIn, big.NewInt(1))
+		result, err := poolSimulator.swapBaseToQuote(pool.TokenAmount{Token: testBaseToken, Amount: amountIn}, testQuoteToken)
+		if err == nil {
+			firmAmountOut := firmBidAmountOut(priceItem.Bids, amountIn, baseDecimals, quoteDecimals)
+			assert.LessOrEqual(t, result.TokenAmountOut.Amount.Cmp(firmAmountOut), 0, "bids %v, amountIn %v", priceItem.Bids, amountIn)
+			assert.Equal(t, firmAmountOut, result.TokenAmountOut.Amount)
+		} else {
+			assert.ErrorIs(t, err, ErrInsufficientLiquidity)
+		}
+
+		amountIn = new(big.Int).Rand(r, new(big.Int).Mul(big.NewInt(1000), bignumber.TenPowInt(quoteDecimals)))
+		amountIn.Add(amountIn, big.NewInt(1))
+		result, err = poolSimulator.swapQuoteToBase(pool.TokenAmount{Token: testQuoteToken, Amount: amountIn}, testBaseToken)
+		if err == nil {
+			firmAmountOut := firmAskAmountOut(priceItem.Asks, amountIn, quoteDecimals, baseDecimals)
+			assert.LessOrEqual(t, result.TokenAmountOut.Amount.Cmp(firmAmountOut), 0, "asks %v, amountIn %v", priceItem.Asks, amountIn)
+		} else {
+			assert.ErrorIs(t, err, ErrInsufficientLiquidity)
+		}
 	}
+}
+
+func TestPoolSimulator_SplitSwapsNeverExceedSingleSwap(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+
+	for i := 0; i < 500; i++ {
+		priceItem := randomPriceItem(r)
+		baseToQuotePriceLevels, _ := transformPriceLevels(priceItem)
+
+		amountIn := rfq.ToDecimalAmount(new(big.Int).Rand(r, bignumber.TenPowInt(18)), 18)
+		firstAmountIn := new(big.Rat).Mul(amountIn, big.NewRat(r.Int63n(100), 100))
+		secondAmountIn := new(big.Rat).Sub(amountIn, firstAmountIn)
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			newPriceLevels := getNewPriceLevelsState(tt.args.amountIn, tt.args.priceLevels)
+		total, err := rfq.GetAmountOut(amountIn, baseToQuotePriceLevels)
+		if err != nil {
+			continue
+		}
+		first, err := rfq.GetAmountOut(firstAmountIn, baseToQuotePriceLevels)
+		require.NoError(t, err)
+		remainingPriceLevels := rfq.FillPriceLevels(firstAmountIn, append([]PriceLevel(nil), baseToQuotePriceLevels...))
+		second := new(big.Rat)
+		if secondAmountIn.Sign() > 0 {
+			second, err = rfq.GetAmountOut(secondAmountIn, remainingPriceLevels)
+			require.NoError(t, err)
+		}
 
-			assert.ElementsMatch(t, tt.expectedPriceLevels, newPriceLevels)
-		})
+		// the exact amounts add up, the rounded ones never exceed the single swap
+		assert.Equal(t, 0, new(big.Rat).Add(first, second).Cmp(total))
+		splitAmountOut := new(big.Int).Add(rfq.ToWeiAmount(first, 6), rfq.ToWeiAmount(second, 6))
+		assert.LessOrEqual(t, splitAmountOut.Cmp(rfq.ToWeiAmount(total, 6)), 0)
 	}
 }
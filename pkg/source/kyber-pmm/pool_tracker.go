@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"math/big"
-	"strconv"
 	"strings"
 	"time"
 
@@ -13,7 +12,6 @@ import (
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
-	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
 
 type PoolTracker struct {
@@ -55,10 +53,7 @@ func (t *PoolTracker) GetNewPoolState(
 	//this is supposed to be big
 	p.Reserves = make([]string, len(p.Tokens))
 	for i, token := range p.Tokens {
-		inventoryWithoutDecimal, _ := new(big.Float).
-			Mul(new(big.Float).SetFloat64(inventory[strings.ToLower(token.Address)]),
-				new(big.Float).Set(bignumber.TenPowDecimals(token.Decimals))).Int(big.NewInt(0))
-		p.Reserves[i] = inventoryWithoutDecimal.String()
+		p.Reserves[i] = toWeiBalance(inventory[strings.ToLower(token.Address)], token.Decimals).String()
 	}
 
 	extra.BaseToQuotePriceLevels, extra.QuoteToBasePriceLevels = transformPriceLevels(priceLevels)
@@ -129,18 +124,14 @@ func (t *PoolTracker) getPriceLevelsForPool(ctx context.Context, p entity.Pool)
 // we invert the order book (bids become asks and vice versa)
 // new price = 1 / price
 // new amount = price * amount
+// The API strings are parsed as exact rationals, so the inverted levels do not lose precision either
 func transformPriceLevels(priceLevels PriceItem) ([]PriceLevel, []PriceLevel) {
 	baseToQuotePriceLevels := make([]PriceLevel, 0, len(priceLevels.Bids))
 	quoteToBasePriceLevels := make([]PriceLevel, 0, len(priceLevels.Asks))
 
 	for _, bid := range priceLevels.Bids {
-		baseToQuoteBidPrice, err := strconv.ParseFloat(bid[0], 64)
-		if err != nil {
-			continue
-		}
-
-		baseToQuoteBidAmountFloat64, err := strconv.ParseFloat(bid[1], 64)
-		if err != nil {
+		baseToQuoteBidPrice, baseToQuoteBidAmount, ok := parsePriceLevel(bid)
+		if !ok {
 			continue
 		}
 
@@ -148,39 +139,50 @@ func transformPriceLevels(priceLevels PriceItem) ([]PriceLevel, []PriceLevel) {
 			baseToQuotePriceLevels,
 			PriceLevel{
 				Price:  baseToQuoteBidPrice,
-				Amount: baseToQuoteBidAmountFloat64,
+				Amount: baseToQuoteBidAmount,
 			},
 		)
 	}
 
 	for _, ask := range priceLevels.Asks {
-		baseToQuoteAskPrice, err := strconv.ParseFloat(ask[0], 64)
-		if err != nil {
+		baseToQuoteAskPrice, baseToQuoteAskAmount, ok := parsePriceLevel(ask)
+		if !ok {
 			continue
 		}
 
 		// Check to prevent division by 0 panic
-		if baseToQuoteAskPrice == 0 {
+		if baseToQuoteAskPrice.Sign() == 0 {
 			logger.Debugf("base to quote ask price is 0, skip it")
 			continue
 		}
 
-		quoteToBaseBidPrice := 1 / baseToQuoteAskPrice
-
-		baseToQuoteAskAmount, err := strconv.ParseFloat(ask[1], 64)
-		if err != nil {
-			continue
-		}
-		quoteToBaseBidAmount := baseToQuoteAskPrice * baseToQuoteAskAmount
-
 		quoteToBasePriceLevels = append(
 			quoteToBasePriceLevels,
 			PriceLevel{
-				Price:  quoteToBaseBidPrice,
-				Amount: quoteToBaseBidAmount,
+				Price:  new(big.Rat).Inv(baseToQuoteAskPrice),
+				Amount: new(big.Rat).Mul(baseToQuoteAskPrice, baseToQuoteAskAmount),
 			},
 		)
 	}
 
 	return baseToQuotePriceLevels, quoteToBasePriceLevels
 }
+
+// parsePriceLevel parses a [price, amount] pair of decimal strings, negative values are invalid
+func parsePriceLevel(level []string) (*big.Rat, *big.Rat, bool) {
+	if len(level) < 2 {
+		return nil, nil, false
+	}
+
+	price, ok := new(big.Rat).SetString(level[0])
+	if !ok || price.Sign() < 0 {
+		return nil, nil, false
+	}
+
+	amount, ok := new(big.Rat).SetString(level[1])
+	if !ok || amount.Sign() < 0 {
+		return nil, nil, false
+	}
+
+	return price, amount, true
+}
@@ -2,78 +2,116 @@ package kyberpmm
 
 import (
 	"context"
-	"encoding/json"
 	"math/big"
+	"time"
 
 	"github.com/KyberNetwork/blockchain-toolkit/account"
 	"github.com/KyberNetwork/logger"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
 )
 
+// RFQHandler is the rfq maker of the KyberSwap market makers, the firm quote is signed by the maker
 type RFQHandler struct {
-	config *Config
-	client IClient
+	config  *Config
+	client  IClient
+	handler *rfq.Handler
 }
 
 func NewRFQHandler(config *Config, client IClient) *RFQHandler {
-	return &RFQHandler{
+	h := &RFQHandler{
 		config: config,
 		client: client,
 	}
+	h.handler = rfq.NewHandler(config.RFQ, h.parseRequest, h)
+
+	return h
 }
 
 func (h *RFQHandler) RFQ(ctx context.Context, recipient string, params any) (pool.RFQResult, error) {
-	paramsByteData, err := json.Marshal(params)
+	return h.handler.RFQ(ctx, recipient, params)
+}
+
+func (h *RFQHandler) Name() string {
+	return h.config.DexID
+}
+
+func (h *RFQHandler) Firm(ctx context.Context, req rfq.Request) (rfq.Quote, error) {
+	result, err := h.client.Firm(ctx,
+		FirmRequestParams{
+			MakerAsset:  req.TokenOut,
+			TakerAsset:  req.TokenIn,
+			MakerAmount: req.AmountOut.String(),
+			TakerAmount: req.AmountIn.String(),
+			UserAddress: req.Taker,
+		})
 	if err != nil {
-		return pool.RFQResult{}, err
+		logger.WithFields(logger.Fields{
+			"params": req.Params,
+			"error":  err,
+		}).Errorf("failed to get firm quote")
+		return rfq.Quote{}, err
 	}
 
-	var swapExtra SwapExtra
-	if err = json.Unmarshal(paramsByteData, &swapExtra); err != nil {
-		return pool.RFQResult{}, ErrInvalidFirmQuoteParams
+	newAmountOut, ok := new(big.Int).SetString(result.Order.MakerAmount, 10)
+	if !ok {
+		return rfq.Quote{}, ErrInvalidFirmQuoteParams
 	}
 
-	if swapExtra.MakingAmount == "" || swapExtra.TakingAmount == "" {
-		return pool.RFQResult{}, ErrInvalidFirmQuoteParams
+	order := RFQExtra{
+		RFQContractAddress: h.config.RFQContractAddress,
+		Info:               result.Order.Info,
+		Expiry:             result.Order.Expiry,
+		MakerAsset:         result.Order.MakerAsset,
+		TakerAsset:         result.Order.TakerAsset,
+		Maker:              result.Order.Maker,
+		Taker:              result.Order.Taker,
+		MakerAmount:        result.Order.MakerAmount,
+		TakerAmount:        result.Order.TakerAmount,
+		Signature:          result.Order.Signature,
+		Recipient:          req.Taker,
+	}
+	if err = validateOrder(h.config, req, order, time.Now()); err != nil {
+		logger.WithFields(logger.Fields{
+			"maker": result.Order.Maker,
+			"error": err,
+		}).Errorf("invalid firm quote order")
+		return rfq.Quote{}, err
+	}
+
+	return rfq.Quote{
+		AmountOut: newAmountOut,
+		Expiry:    time.Unix(result.Order.Expiry, 0),
+		Extra:     order,
+	}, nil
+}
+
+func (h *RFQHandler) parseRequest(recipient string, params any) (rfq.Request, error) {
+	swapExtra, err := rfq.DecodeParams[SwapExtra](params)
+	if err != nil {
+		return rfq.Request{}, ErrInvalidFirmQuoteParams
 	}
 
 	if !account.IsValidAddress(swapExtra.MakerAsset) || !account.IsValidAddress(swapExtra.TakerAsset) {
-		return pool.RFQResult{}, ErrInvalidFirmQuoteParams
+		return rfq.Request{}, ErrInvalidFirmQuoteParams
 	}
 
-	result, err := h.client.Firm(ctx,
-		FirmRequestParams{
-			MakerAsset:  swapExtra.MakerAsset,
-			TakerAsset:  swapExtra.TakerAsset,
-			MakerAmount: swapExtra.MakingAmount,
-			TakerAmount: swapExtra.TakingAmount,
-			UserAddress: recipient,
-		})
-	if err != nil {
-		logger.WithFields(logger.Fields{
-			"params": params,
-			"error":  err,
-		}).Errorf("failed to get firm quote")
-		return pool.RFQResult{}, err
+	takingAmount, ok := new(big.Int).SetString(swapExtra.TakingAmount, 10)
+	if !ok {
+		return rfq.Request{}, ErrInvalidFirmQuoteParams
+	}
+	makingAmount, ok := new(big.Int).SetString(swapExtra.MakingAmount, 10)
+	if !ok {
+		return rfq.Request{}, ErrInvalidFirmQuoteParams
 	}
 
-	newAmountOut, _ := new(big.Int).SetString(result.Order.MakerAmount, 10)
-
-	return pool.RFQResult{
-		NewAmountOut: newAmountOut,
-		Extra: RFQExtra{
-			RFQContractAddress: h.config.RFQContractAddress,
-			Info:               result.Order.Info,
-			Expiry:             result.Order.Expiry,
-			MakerAsset:         result.Order.MakerAsset,
-			TakerAsset:         result.Order.TakerAsset,
-			Maker:              result.Order.Maker,
-			Taker:              result.Order.Taker,
-			MakerAmount:        result.Order.MakerAmount,
-			TakerAmount:        result.Order.TakerAmount,
-			Signature:          result.Order.Signature,
-			Recipient:          recipient,
-		},
+	return rfq.Request{
+		Taker:     recipient,
+		TokenIn:   swapExtra.TakerAsset,
+		TokenOut:  swapExtra.MakerAsset,
+		AmountIn:  takingAmount,
+		AmountOut: makingAmount,
+		Params:    swapExtra,
 	}, nil
 }
@@ -0,0 +1,190 @@
+package kyberpmm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+)
+
+const (
+	testRFQContract = "0x7a819fa46734a49d0112796f9377e024c350fb26"
+	testMakerAsset  = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	testTakerAsset  = "0x6b175474e89094c44da98b954eedeac495271d0f"
+	testRecipient   = "0x0000000000000000000000000000000000000001"
+)
+
+type mockClient struct {
+	IClient
+	result FirmResult
+}
+
+func (c *mockClient) Firm(_ context.Context, _ FirmRequestParams) (FirmResult, error) {
+	return c.result, nil
+}
+
+func signOrder(t *testing.T, key *ecdsa.PrivateKey, order RFQExtra) string {
+	digest, err := rfq.HashTypedData(order.TypedData(1))
+	require.NoError(t, err)
+	sig, err := crypto.Sign(digest.Bytes(), key)
+	require.NoError(t, err)
+	sig[64] += 27
+
+	return hexutil.Encode(sig)
+}
+
+func newTestFirmResult(t *testing.T, key *ecdsa.PrivateKey, maker string, modify func(*RFQExtra)) FirmResult {
+	order := RFQExtra{
+		RFQContractAddress: testRFQContract,
+		Info:               "42",
+		Expiry:             time.Now().Add(time.Minute).Unix(),
+		MakerAsset:         testMakerAsset,
+		TakerAsset:         testTakerAsset,
+		Maker:              maker,
+		Taker:              testRecipient,
+		MakerAmount:        "999000",
+		TakerAmount:        "1000000000000000000",
+	}
+	order.Signature = signOrder(t, key, order)
+	if modify != nil {
+		modify(&order)
+	}
+
+	var result FirmResult
+	result.Order.Info = order.Info
+	result.Order.Expiry = order.Expiry
+	result.Order.MakerAsset = order.MakerAsset
+	result.Order.TakerAsset = order.TakerAsset
+	result.Order.Maker = order.Maker
+	result.Order.Taker = order.Taker
+	result.Order.MakerAmount = order.MakerAmount
+	result.Order.TakerAmount = order.TakerAmount
+	result.Order.Signature = order.Signature
+
+	return result
+}
+
+func TestRFQHandler_RFQ_ValidatesOrder(t *testing.T) {
+	makerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	maker := strings.ToLower(crypto.PubkeyToAddress(makerKey.PublicKey).Hex())
+
+	testCases := []struct {
+		name   string
+		makers []string
+		result FirmResult
+		err    error
+	}{
+		{
+			name:   "valid order",
+			result: newTestFirmResult(t, makerKey, maker, nil),
+		},
+		{
+			name:   "maker not configured",
+			makers: []string{testRecipient},
+			result: newTestFirmResult(t, makerKey, maker, nil),
+			err:    rfq.ErrUnexpectedSigner,
+		},
+		{
+			name:   "no maker configured",
+			makers: []string{},
+			result: newTestFirmResult(t, makerKey, maker, nil),
+			err:    rfq.ErrUnexpectedSigner,
+		},
+		{
+			name: "self-consistent order of another maker",
+			result: newTestFirmResult(t, otherKey, strings.ToLower(crypto.PubkeyToAddress(otherKey.PublicKey).Hex()),
+				nil),
+			err: rfq.ErrUnexpectedSigner,
+		},
+		{
+			// the maker amount is checked before the signature
+			name: "maker amount below the slippage tolerance",
+			result: newTestFirmResult(t, makerKey, maker, func(order *RFQExtra) {
+				order.MakerAmount = "994999"
+			}),
+			err: rfq.ErrQuoteBelowSlippage,
+		},
+		{
+			name:   "signed by another key",
+			result: newTestFirmResult(t, otherKey, maker, nil),
+			err:    rfq.ErrUnexpectedSigner,
+		},
+		{
+			name: "amount changed after signing",
+			result: newTestFirmResult(t, makerKey, maker, func(order *RFQExtra) {
+				order.MakerAmount = "999500"
+			}),
+			err: rfq.ErrUnexpectedSigner,
+		},
+		{
+			name: "malformed signature",
+			result: newTestFirmResult(t, makerKey, maker, func(order *RFQExtra) {
+				order.Signature = "0x1234"
+			}),
+			err: rfq.ErrInvalidSignature,
+		},
+		{
+			name: "expired",
+			result: newTestFirmResult(t, makerKey, maker, func(order *RFQExtra) {
+				order.Expiry = time.Now().Add(-time.Minute).Unix()
+			}),
+			err: rfq.ErrQuoteExpired,
+		},
+		{
+			name: "wrong maker asset",
+			result: newTestFirmResult(t, makerKey, maker, func(order *RFQExtra) {
+				order.MakerAsset = testTakerAsset
+			}),
+			err: rfq.ErrQuoteMismatch,
+		},
+		{
+			name: "wrong taker amount",
+			result: newTestFirmResult(t, makerKey, maker, func(order *RFQExtra) {
+				order.TakerAmount = "1"
+			}),
+			err: rfq.ErrQuoteMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			makers := tc.makers
+			if makers == nil {
+				makers = []string{crypto.PubkeyToAddress(makerKey.PublicKey).Hex()}
+			}
+			handler := NewRFQHandler(&Config{
+				DexID:              DexTypeKyberPMM,
+				ChainID:            1,
+				RFQContractAddress: testRFQContract,
+				RFQ:                rfq.Config{SlippageTolerance: 50},
+				Makers:             makers,
+			}, &mockClient{result: tc.result})
+
+			result, err := handler.RFQ(context.Background(), testRecipient, SwapExtra{
+				TakerAsset:   testTakerAsset,
+				TakingAmount: "1000000000000000000",
+				MakerAsset:   testMakerAsset,
+				MakingAmount: "1000000",
+			})
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "999000", result.NewAmountOut.String())
+			assert.Equal(t, maker, result.Extra.(RFQExtra).Maker)
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package kyberpmm
 
+import "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+
 type TokenItem struct {
 	Symbol      string `json:"symbol"`
 	Name        string `json:"name"`
@@ -49,10 +51,8 @@ type Extra struct {
 	QuoteToBasePriceLevels []PriceLevel `json:"quoteToBasePriceLevels"`
 }
 
-type PriceLevel struct {
-	Price  float64 `json:"price"`
-	Amount float64 `json:"amount"`
-}
+// PriceLevel is an exact level of the order book, the price and the amount keep every digit of the API strings
+type PriceLevel = rfq.PriceLevel
 
 type SwapExtra struct {
 	TakerAsset   string `json:"takerAsset"`
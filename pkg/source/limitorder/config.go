@@ -1,5 +1,7 @@
 package limitorder
 
+import "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+
 type Config struct {
 	DexID             string `json:"dexID"`
 	LimitOrderHTTPUrl string `json:"limitOrderHTTPUrl"`
@@ -7,4 +9,13 @@ type Config struct {
 	SupportMultiSCs   bool   `json:"supportMultiSCs"`
 
 	ContractAddresses []string `json:"contractAddresses"`
+	// OperatorAddress signs the fill permission of every order, no order is filled when it is empty
+	OperatorAddress string `json:"operatorAddress"`
+	// ExecutorAddress fills the orders on behalf of the router, orders restricted to another sender are not used
+	ExecutorAddress string `json:"executorAddress"`
+
+	// Domain overrides the EIP-712 domain of the contracts, "Kyber Limit Order" version "1"
+	Domain rfq.Domain `json:"domain"`
+
+	RFQ rfq.Config `json:"rfq"`
 }
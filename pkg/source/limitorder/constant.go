@@ -1,6 +1,9 @@
 package limitorder
 
-import "math/big"
+import (
+	"math"
+	"math/big"
+)
 
 const (
 	DexTypeLimitOrder = "limit-order"
@@ -13,8 +16,34 @@ const (
 	// TODO: when we has correct formula that pool's reserve can be eligible pools.
 	limitOrderPoolReserve    = "10000000000000000000"
 	LimitOrderPoolReserveUSD = 1000000000
+
+	erc20MethodBalanceOf = "balanceOf"
+	erc20MethodAllowance = "allowance"
+
+	erc1271MethodIsValidSignature = "isValidSignature"
+
+	// makerBalancesBatchSize is the number of makers whose balance and allowance are read in one multicall
+	makerBalancesBatchSize = 250
+
+	// feeConfigRecipientBits is the size of the fee recipient in the low bits of the fee config
+	feeConfigRecipientBits = 160
 )
 
+type orderFillability int
+
+const (
+	fillable orderFillability = iota
+	unfillable
+	// fallbackOnly orders are only sent as fallback orders, the executor skips them if they revert
+	fallbackOnly
+)
+
+// feeConfigFeeMask is the size of the fee in the fee config, above the fee recipient
+var feeConfigFeeMask = big.NewInt(math.MaxUint32)
+
+// erc1271MagicValue is what isValidSignature returns for a signature the contract accepts
+var erc1271MagicValue = [4]byte{0x16, 0x26, 0xba, 0x7e}
+
 var (
 	Buy  SwapSide = "BUY"
 	Sell SwapSide = "SELL"
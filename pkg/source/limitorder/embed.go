@@ -0,0 +1,11 @@
+package limitorder
+
+import (
+	_ "embed"
+)
+
+//go:embed abis/ERC20.json
+var erc20ABIJson []byte
+
+//go:embed abis/ERC1271.json
+var erc1271ABIJson []byte
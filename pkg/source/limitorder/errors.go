@@ -4,3 +4,4 @@ import "errors"
 
 var ErrCannotFulfillAmountIn = errors.New("cannot fulfill amountIn")
 var InvalidSwapInfo = errors.New("invalid swap info")
+var ErrOperatorNotConfigured = errors.New("limit order operator is not configured")
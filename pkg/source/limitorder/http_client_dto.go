@@ -72,6 +72,7 @@ type (
 		Permit               string `json:"permit"`
 		Interaction          string `json:"interaction"`
 		ExpiredAt            int64  `json:"expiredAt"`
+		MinFillTakingAmount  string `json:"minFillTakingAmount"`
 	}
 
 	listOrdersFilter struct {
@@ -107,6 +108,8 @@ type (
 		Permit               string   `json:"permit"`
 		Interaction          string   `json:"interaction"`
 		ExpiredAt            int64    `json:"expiredAt"`
+		// MinFillTakingAmount is the smallest partial fill the maker accepts, the last fill of the order can be smaller
+		MinFillTakingAmount *big.Int `json:"minFillTakingAmount,omitempty"`
 	}
 
 	operatorSignatures struct {
@@ -174,6 +177,13 @@ func toOrder(ordersData []*orderData) ([]*order, error) {
 			}
 			result[i].FilledMakingAmount = filledMakingAmount
 		}
+		if len(o.MinFillTakingAmount) > 0 {
+			minFillTakingAmount, ok := new(big.Int).SetString(o.MinFillTakingAmount, 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid minFillTakingAmount %v", o.MinFillTakingAmount)
+			}
+			result[i].MinFillTakingAmount = minFillTakingAmount
+		}
 		result[i].TakingAmount = takingAmount
 		result[i].MakingAmount = makingAmount
 	}
@@ -0,0 +1,242 @@
+package limitorder
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+const (
+	limitOrderDomainName    = "Kyber Limit Order"
+	limitOrderDomainVersion = "1"
+	limitOrderType          = "Order"
+	operatorSignatureType   = "OperatorSignature"
+)
+
+// orderFields is the order of the contracts charging the fee with a packed fee config
+var orderFields = []apitypes.Type{
+	{Name: "salt", Type: "uint256"},
+	{Name: "makerAsset", Type: "address"},
+	{Name: "takerAsset", Type: "address"},
+	{Name: "maker", Type: "address"},
+	{Name: "receiver", Type: "address"},
+	{Name: "allowedSender", Type: "address"},
+	{Name: "makingAmount", Type: "uint256"},
+	{Name: "takingAmount", Type: "uint256"},
+	{Name: "feeConfig", Type: "uint256"},
+	{Name: "makerAssetData", Type: "bytes"},
+	{Name: "takerAssetData", Type: "bytes"},
+	{Name: "getMakerAmount", Type: "bytes"},
+	{Name: "getTakerAmount", Type: "bytes"},
+	{Name: "predicate", Type: "bytes"},
+	{Name: "interaction", Type: "bytes"},
+}
+
+// legacyOrderFields is the order of the first contract, the fee is a recipient and a maker token percent
+var legacyOrderFields = []apitypes.Type{
+	{Name: "salt", Type: "uint256"},
+	{Name: "makerAsset", Type: "address"},
+	{Name: "takerAsset", Type: "address"},
+	{Name: "maker", Type: "address"},
+	{Name: "receiver", Type: "address"},
+	{Name: "allowedSender", Type: "address"},
+	{Name: "makingAmount", Type: "uint256"},
+	{Name: "takingAmount", Type: "uint256"},
+	{Name: "feeRecipient", Type: "address"},
+	{Name: "makerTokenFeePercent", Type: "uint32"},
+	{Name: "makerAssetData", Type: "bytes"},
+	{Name: "takerAssetData", Type: "bytes"},
+	{Name: "getMakerAmount", Type: "bytes"},
+	{Name: "getTakerAmount", Type: "bytes"},
+	{Name: "predicate", Type: "bytes"},
+	{Name: "permit", Type: "bytes"},
+	{Name: "interaction", Type: "bytes"},
+}
+
+var operatorSignatureFields = []apitypes.Type{
+	{Name: "orderHash", Type: "bytes32"},
+	{Name: "expiredAt", Type: "uint256"},
+}
+
+// TypedData returns the EIP-712 typed data of the order signed by the maker
+func (o *FilledOrderInfo) TypedData(chainID uint, contractAddress string) apitypes.TypedData {
+	message := apitypes.TypedDataMessage{
+		"salt":           o.Salt,
+		"makerAsset":     o.MakerAsset,
+		"takerAsset":     o.TakerAsset,
+		"maker":          o.Maker,
+		"receiver":       addressOrZero(o.Receiver),
+		"allowedSender":  addressOrZero(o.AllowedSenders),
+		"makingAmount":   o.MakingAmount,
+		"takingAmount":   o.TakingAmount,
+		"makerAssetData": rfq.HexBytes(o.MakerAssetData),
+		"takerAssetData": rfq.HexBytes(o.TakerAssetData),
+		"getMakerAmount": rfq.HexBytes(o.GetMakerAmount),
+		"getTakerAmount": rfq.HexBytes(o.GetTakerAmount),
+		"predicate":      rfq.HexBytes(o.Predicate),
+		"interaction":    rfq.HexBytes(o.Interaction),
+	}
+
+	fields := orderFields
+	if o.FeeConfig != "" {
+		message["feeConfig"] = o.FeeConfig
+	} else {
+		fields = legacyOrderFields
+		message["feeRecipient"] = addressOrZero(o.FeeRecipient)
+		message["makerTokenFeePercent"] = strconv.FormatUint(uint64(o.MakerTokenFeePercent), 10)
+		message["permit"] = rfq.HexBytes(o.Permit)
+	}
+
+	return rfq.NewTypedData(limitOrderDomainName, limitOrderDomainVersion, chainID, contractAddress, limitOrderType,
+		fields, message)
+}
+
+func operatorTypedData(chainID uint, contractAddress string, orderHash common.Hash, expiredAt int64) apitypes.TypedData {
+	return rfq.NewTypedData(limitOrderDomainName, limitOrderDomainVersion, chainID, contractAddress,
+		operatorSignatureType, operatorSignatureFields, apitypes.TypedDataMessage{
+			"orderHash": orderHash.Hex(),
+			"expiredAt": strconv.FormatInt(expiredAt, 10),
+		})
+}
+
+// validateFilledOrders checks every filled order before it is encoded: the order must be signed by its maker, swap the
+// requested assets within its amounts and come with an unexpired operator signature
+func (h *RFQHandler) validateFilledOrders(
+	ctx context.Context,
+	req rfq.Request,
+	swapInfo SwapInfo,
+	signatures map[int64]*operatorSignatures,
+	now time.Time,
+) error {
+	// the operator signatures can not be checked without the operator, no order is filled then
+	if h.config.OperatorAddress == "" {
+		return ErrOperatorNotConfigured
+	}
+
+	contractAddress := swapInfo.ContractAddress
+	if contractAddress == "" && len(h.config.ContractAddresses) > 0 {
+		contractAddress = h.config.ContractAddresses[0]
+	}
+	if !common.IsHexAddress(contractAddress) {
+		return rfq.ErrQuoteMismatch
+	}
+
+	for _, filledOrder := range swapInfo.FilledOrders {
+		if !strings.EqualFold(filledOrder.MakerAsset, req.TokenOut) ||
+			!strings.EqualFold(filledOrder.TakerAsset, req.TokenIn) {
+			return rfq.ErrQuoteMismatch
+		}
+
+		if !isFilledWithin(filledOrder.FilledMakingAmount, filledOrder.MakingAmount) ||
+			!isFilledWithin(filledOrder.FilledTakingAmount, filledOrder.TakingAmount) {
+			return rfq.ErrQuoteMismatch
+		}
+
+		typedData := h.config.Domain.Apply(filledOrder.TypedData(h.config.ChainID, contractAddress))
+		if err := h.verifyMakerSignature(ctx, typedData, filledOrder.Signature, filledOrder.Maker); err != nil {
+			return err
+		}
+
+		signature, ok := signatures[filledOrder.OrderID]
+		if !ok || signature.OperatorSignature == "" {
+			return rfq.ErrInvalidSignature
+		}
+		if signature.OperatorSignatureExpiredAt <= now.Unix() {
+			return rfq.ErrQuoteExpired
+		}
+		orderHash, err := rfq.HashTypedData(typedData)
+		if err != nil {
+			return err
+		}
+		err = rfq.VerifySigner(
+			h.config.Domain.Apply(
+				operatorTypedData(h.config.ChainID, contractAddress, orderHash, signature.OperatorSignatureExpiredAt),
+			),
+			signature.OperatorSignature,
+			h.config.OperatorAddress,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyMakerSignature checks the signature as the contract does with SignatureChecker.isValidSignatureNow: an ECDSA
+// signature of the maker, or else a signature the maker contract accepts with ERC-1271 isValidSignature. Contract
+// makers are rejected when the handler has no ethrpc client.
+func (h *RFQHandler) verifyMakerSignature(
+	ctx context.Context,
+	typedData apitypes.TypedData,
+	signature string,
+	maker string,
+) error {
+	err := rfq.VerifySigner(typedData, signature, maker)
+	if err == nil || h.ethrpcClient == nil ||
+		!(errors.Is(err, rfq.ErrUnexpectedSigner) || errors.Is(err, rfq.ErrInvalidSignature)) {
+		return err
+	}
+
+	digest, hashErr := rfq.HashTypedData(typedData)
+	if hashErr != nil {
+		return hashErr
+	}
+	signatureBytes, decodeErr := hexutil.Decode(signature)
+	if decodeErr != nil {
+		return rfq.ErrInvalidSignature
+	}
+
+	var magicValue [4]byte
+	if _, callErr := h.ethrpcClient.NewRequest().SetContext(ctx).AddCall(&ethrpc.Call{
+		ABI:    erc1271ABI,
+		Target: maker,
+		Method: erc1271MethodIsValidSignature,
+		Params: []interface{}{digest, signatureBytes},
+	}, []interface{}{&magicValue}).Call(); callErr != nil {
+		// an account without code or a contract without ERC-1271 reverts, the signature is still the wrong one
+		logger.WithFields(logger.Fields{
+			"maker": maker,
+			"error": callErr,
+		}).Debugf("failed to call isValidSignature")
+		return err
+	}
+	if magicValue != erc1271MagicValue {
+		return err
+	}
+
+	return nil
+}
+
+func isFilledWithin(filled, total string) bool {
+	filledAmount, ok := new(big.Int).SetString(filled, 10)
+	if !ok {
+		return false
+	}
+	totalAmount, ok := new(big.Int).SetString(total, 10)
+	if !ok {
+		return false
+	}
+
+	return filledAmount.Sign() >= 0 && filledAmount.Cmp(totalAmount) <= 0
+}
+
+func addressOrZero(address string) string {
+	if address == "" {
+		return valueobject.ZeroAddress
+	}
+
+	return address
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"time"
 
 	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
@@ -25,6 +27,8 @@ type (
 		buyOrderIDs  []int64
 
 		contractAddress string
+		executorAddress string
+		makerBalances   MakerBalances
 	}
 )
 
@@ -40,13 +44,14 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 		reserves[i] = utils.NewBig10(entityPool.Reserves[i])
 	}
 
-	var contractAddress string
+	var contractAddress, executorAddress string
 	var staticExtra StaticExtra
 	if err := json.Unmarshal([]byte(entityPool.StaticExtra), &staticExtra); err != nil {
 		// this is optional for now, will changed to required later
 		contractAddress = ""
 	} else {
 		contractAddress = staticExtra.ContractAddress
+		executorAddress = staticExtra.ExecutorAddress
 	}
 
 	var extra Extra
@@ -83,6 +88,8 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 		tokens:        entity.ClonePoolTokens(entityPool.Tokens),
 
 		contractAddress: contractAddress,
+		executorAddress: executorAddress,
+		makerBalances:   extra.MakerBalances,
 	}, nil
 }
 
@@ -90,7 +97,17 @@ func (p *PoolSimulator) CalcAmountOut(
 	tokenAmountIn pool.TokenAmount,
 	tokenOut string,
 ) (*pool.CalcAmountOutResult, error) {
-	return p.calcAmountOut(tokenAmountIn, tokenOut)
+	return p.calcAmountOut(tokenAmountIn, tokenOut, nil)
+}
+
+// CalcAmountOutWithInventory caps the orders by what their makers have left in the inventory, the pools of the
+// request holding orders of the same maker and maker asset may have spent it
+func (p *PoolSimulator) CalcAmountOutWithInventory(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+	inventory *pool.Inventory,
+) (*pool.CalcAmountOutResult, error) {
+	return p.calcAmountOut(tokenAmountIn, tokenOut, inventory)
 }
 
 func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
@@ -111,15 +128,39 @@ func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
 
 		order.FilledTakingAmount = new(big.Int).Add(order.FilledTakingAmount, filledTakingAmount)
 		order.FilledMakingAmount = new(big.Int).Add(order.FilledMakingAmount, filledMakingAmount)
+		if p.makerBalances != nil {
+			p.updateMakerBalance(order, filledMakingAmount, params.Inventory)
+		}
+	}
+}
+
+// updateMakerBalance charges the maker of the order for a fill. When there is an inventory, the balance is shared
+// with the other pools holding orders of the same maker and maker asset.
+func (p *PoolSimulator) updateMakerBalance(order *order, filledMakingAmount *big.Int, inventory *pool.Inventory) {
+	balance := p.makerBalances.get(order.Maker, order.MakerAsset)
+	if inventory != nil {
+		balance = inventory.InitMakerBalance(order.Maker, order.MakerAsset, balance)
+	}
+
+	spentAmount := filledMakingAmount
+	if spentAmount.Cmp(balance) > 0 {
+		spentAmount = balance
 	}
+	if inventory != nil {
+		balance, _ = inventory.DecreaseMakerBalance(order.Maker, order.MakerAsset, spentAmount)
+	} else {
+		balance = new(big.Int).Sub(balance, spentAmount)
+	}
+	p.makerBalances.set(order.Maker, order.MakerAsset, balance)
 }
 
 func (p *PoolSimulator) calcAmountOut(
 	tokenAmountIn pool.TokenAmount,
 	tokenOut string,
+	inventory *pool.Inventory,
 ) (*pool.CalcAmountOutResult, error) {
 	swapSide := p.getSwapSide(tokenAmountIn.Token, tokenOut)
-	amountOut, swapInfo, feeAmount, err := p.calcAmountWithSwapInfo(swapSide, tokenAmountIn)
+	amountOut, swapInfo, feeAmount, err := p.calcAmountWithSwapInfo(swapSide, tokenAmountIn, inventory)
 	if err != nil {
 		return nil, err
 	}
@@ -138,7 +179,11 @@ func (p *PoolSimulator) calcAmountOut(
 	}, nil
 }
 
-func (p *PoolSimulator) calcAmountWithSwapInfo(swapSide SwapSide, tokenAmountIn pool.TokenAmount) (*big.Int, SwapInfo, *big.Int, error) {
+func (p *PoolSimulator) calcAmountWithSwapInfo(
+	swapSide SwapSide,
+	tokenAmountIn pool.TokenAmount,
+	inventory *pool.Inventory,
+) (*big.Int, SwapInfo, *big.Int, error) {
 
 	orderIDs := p.getOrderIDsBySwapSide(swapSide)
 	if len(orderIDs) == 0 {
@@ -149,14 +194,20 @@ func (p *PoolSimulator) calcAmountWithSwapInfo(swapSide SwapSide, tokenAmountIn
 	totalAmountIn := tokenAmountIn.Amount
 
 	swapInfo := SwapInfo{
-		FilledOrders: []*FilledOrderInfo{},
-		SwapSide:     swapSide,
-		AmountIn:     tokenAmountIn.Amount.String(),
+		FilledOrders:    []*FilledOrderInfo{},
+		SwapSide:        swapSide,
+		AmountIn:        tokenAmountIn.Amount.String(),
+		ContractAddress: p.contractAddress,
 	}
 	isFulfillAmountIn := false
 	totalFeeAmountWei := new(big.Int)
 
+	now := time.Now().Unix()
 	totalMakingAmountWei := new(big.Int)
+	// fallbackOnlyIDs are the orders passed over because their predicates can only be checked on-chain
+	var fallbackOnlyIDs []int64
+	// spentMakingAmounts is what the orders filled so far take from their makers' balances
+	spentMakingAmounts := make(MakerBalances)
 	for i, orderID := range orderIDs {
 		order, ok := p.ordersMapping[orderID]
 		if !ok {
@@ -165,17 +216,29 @@ func (p *PoolSimulator) calcAmountWithSwapInfo(swapSide SwapSide, tokenAmountIn
 		// rate should be the result of making amount/taking amount when dividing decimals per token.
 		// However, we can also use rate with making amount/taking amount (wei) to calculate the amount out instead of converting to measure per token. Because we will return amount out(wei) (we have to multip amountOut(taken out) with decimals)
 		rate := new(big.Float).Quo(new(big.Float).SetInt(order.MakingAmount), new(big.Float).SetInt(order.TakingAmount))
-		remainingMakingAmountWei := new(big.Int).Sub(order.MakingAmount, order.FilledMakingAmount)
-		remainingTakingAmountWei := new(big.Int).Sub(order.TakingAmount, order.FilledTakingAmount)
-		totalMakingAmountWei = new(big.Int).Add(totalMakingAmountWei, remainingMakingAmountWei)
-		// Order was filled out.
+		remainingMakingAmountWei, remainingTakingAmountWei, isCapped := p.getRemainingAmounts(order, spentMakingAmounts, inventory)
+		// Order was filled out or its maker has no funds left.
 		if remainingMakingAmountWei.Cmp(constant.ZeroBI) <= 0 {
 			continue
 		}
+		switch p.getOrderFillability(order, now) {
+		case unfillable:
+			continue
+		case fallbackOnly:
+			fallbackOnlyIDs = append(fallbackOnlyIDs, orderID)
+			continue
+		}
 		if remainingTakingAmountWei.Cmp(totalAmountIn) >= 0 {
+			// The order is partially filled unless the amount in takes all of it
+			isPartialFill := isCapped || remainingTakingAmountWei.Cmp(totalAmountIn) > 0
+			if isPartialFill && !p.canPartiallyFill(order, totalAmountIn) {
+				continue
+			}
+			totalMakingAmountWei = new(big.Int).Add(totalMakingAmountWei, remainingMakingAmountWei)
 			amountOutWei := new(big.Float).Mul(new(big.Float).SetInt(totalAmountIn), rate)
 			filledTakingAmountWei := totalAmountIn
 			filledMakingAmountWei, _ := amountOutWei.Int(nil)
+			spendMakingAmount(spentMakingAmounts, order, filledMakingAmountWei)
 			feeAmountWeiByOrder := p.calcFeeAmountPerOrder(order, filledMakingAmountWei)
 			totalFeeAmountWei = new(big.Int).Add(totalFeeAmountWei, feeAmountWeiByOrder)
 			actualAmountOut := new(big.Int).Sub(filledMakingAmountWei, feeAmountWeiByOrder)
@@ -189,16 +252,20 @@ func (p *PoolSimulator) calcAmountWithSwapInfo(swapSide SwapSide, tokenAmountIn
 			// From that, the estimated amount out and filled orders are not correct. So we need to add more orders when sending to SC to the executor.
 			// In this case, we will some orders util total MakingAmount(remainMakingAmount)/estimated amountOut >= 1.3 (130%)
 			totalAmountOutWeiBigFloat := new(big.Float).SetInt64(totalAmountOutWei.Int64())
-			for j := i + 1; j < len(orderIDs); j++ {
+			fallbackOrderIDs := append(fallbackOnlyIDs, orderIDs[i+1:]...)
+			for _, fallbackOrderID := range fallbackOrderIDs {
 				if new(big.Float).SetInt(totalMakingAmountWei).Cmp(new(big.Float).Mul(totalAmountOutWeiBigFloat, FallbackPercentageOfTotalMakingAmount)) >= 0 {
 					break
 				}
-				order, ok := p.ordersMapping[orderIDs[j]]
+				order, ok := p.ordersMapping[fallbackOrderID]
 				if !ok {
 					continue
 				}
-				remainingMakingAmountWei := new(big.Int).Sub(order.MakingAmount, order.FilledMakingAmount)
-				if remainingMakingAmountWei.Cmp(constant.ZeroBI) == 0 {
+				remainingMakingAmountWei, _, _ := p.getRemainingAmounts(order, spentMakingAmounts, inventory)
+				if remainingMakingAmountWei.Cmp(constant.ZeroBI) <= 0 {
+					continue
+				}
+				if p.getOrderFillability(order, now) == unfillable {
 					continue
 				}
 				totalMakingAmountWei = new(big.Int).Add(totalMakingAmountWei, remainingMakingAmountWei)
@@ -208,6 +275,11 @@ func (p *PoolSimulator) calcAmountWithSwapInfo(swapSide SwapSide, tokenAmountIn
 			}
 			break
 		}
+		if isCapped && !p.canPartiallyFill(order, remainingTakingAmountWei) {
+			continue
+		}
+		spendMakingAmount(spentMakingAmounts, order, remainingMakingAmountWei)
+		totalMakingAmountWei = new(big.Int).Add(totalMakingAmountWei, remainingMakingAmountWei)
 		totalAmountIn = new(big.Int).Sub(totalAmountIn, remainingTakingAmountWei)
 		feeAmountWeiByOrder := p.calcFeeAmountPerOrder(order, remainingMakingAmountWei)
 		actualAmountOut := new(big.Int).Sub(remainingMakingAmountWei, feeAmountWeiByOrder)
@@ -224,13 +296,107 @@ func (p *PoolSimulator) calcAmountWithSwapInfo(swapSide SwapSide, tokenAmountIn
 
 // feeAmount = (params.makingAmount * params.order.makerTokenFeePercent + BPS - 1) / BPS
 func (p *PoolSimulator) calcFeeAmountPerOrder(order *order, filledMakingAmount *big.Int) *big.Int {
-	if order.MakerTokenFeePercent == 0 {
+	makerTokenFeePercent := getMakerTokenFeePercent(order)
+	if makerTokenFeePercent == 0 {
 		return constant.ZeroBI
 	}
-	amount := new(big.Int).Mul(filledMakingAmount, big.NewInt(int64(order.MakerTokenFeePercent)))
+	amount := new(big.Int).Mul(filledMakingAmount, big.NewInt(int64(makerTokenFeePercent)))
 	return new(big.Int).Div(new(big.Int).Sub(new(big.Int).Add(amount, valueobject.BasisPoint), constant.One), valueobject.BasisPoint)
 }
 
+// getRemainingAmounts returns the amounts of the order that can still be filled. The making amount is capped by what
+// its maker has left once spentMakingAmounts is taken, isCapped is true when that is less than the whole order.
+func (p *PoolSimulator) getRemainingAmounts(
+	order *order,
+	spentMakingAmounts MakerBalances,
+	inventory *pool.Inventory,
+) (remainingMakingAmount, remainingTakingAmount *big.Int, isCapped bool) {
+	remainingMakingAmount = new(big.Int).Sub(order.MakingAmount, order.FilledMakingAmount)
+	remainingTakingAmount = new(big.Int).Sub(order.TakingAmount, order.FilledTakingAmount)
+	if p.makerBalances == nil {
+		return remainingMakingAmount, remainingTakingAmount, false
+	}
+
+	availableMakingAmount := new(big.Int).Sub(
+		p.getMakerBalance(order, inventory),
+		spentMakingAmounts.get(order.Maker, order.MakerAsset),
+	)
+	if availableMakingAmount.Cmp(remainingMakingAmount) >= 0 {
+		return remainingMakingAmount, remainingTakingAmount, false
+	}
+	if availableMakingAmount.Sign() <= 0 {
+		return big.NewInt(0), big.NewInt(0), true
+	}
+
+	// Round the taking amount down so the making amount it gets does not exceed the funds
+	remainingTakingAmount = new(big.Int).Div(new(big.Int).Mul(availableMakingAmount, order.TakingAmount), order.MakingAmount)
+	remainingMakingAmount = new(big.Int).Div(new(big.Int).Mul(remainingTakingAmount, order.MakingAmount), order.TakingAmount)
+	return remainingMakingAmount, remainingTakingAmount, true
+}
+
+// getMakerBalance returns what the maker of the order can be charged, its balance in the inventory once the inventory
+// tracks it
+func (p *PoolSimulator) getMakerBalance(order *order, inventory *pool.Inventory) *big.Int {
+	if inventory != nil && inventory.HasMakerBalance(order.Maker, order.MakerAsset) {
+		return inventory.GetMakerBalance(order.Maker, order.MakerAsset)
+	}
+
+	return p.makerBalances.get(order.Maker, order.MakerAsset)
+}
+
+func spendMakingAmount(spentMakingAmounts MakerBalances, order *order, makingAmount *big.Int) {
+	spent := spentMakingAmounts.get(order.Maker, order.MakerAsset)
+	spentMakingAmounts.set(order.Maker, order.MakerAsset, new(big.Int).Add(spent, makingAmount))
+}
+
+// getMakerTokenFeePercent returns the fee in bps of the making amount as the contract of the order charges it. The
+// contracts with a fee config pack the fee in the 32 bits above the 160 bits of the fee recipient, the first contract
+// reads the maker token fee percent of the order.
+func getMakerTokenFeePercent(order *order) uint32 {
+	if order.FeeConfig == nil {
+		return order.MakerTokenFeePercent
+	}
+
+	fee := new(big.Int).Rsh(order.FeeConfig, feeConfigRecipientBits)
+	return uint32(fee.And(fee, feeConfigFeeMask).Uint64())
+}
+
+// getOrderFillability checks the rules of the contract that do not depend on the filled amount: the expiry, the
+// allowed sender and the predicate
+func (p *PoolSimulator) getOrderFillability(order *order, now int64) orderFillability {
+	if order.ExpiredAt > 0 && now > order.ExpiredAt {
+		return unfillable
+	}
+
+	allowedSender := order.AllowedSenders
+	if allowedSender != "" && !strings.EqualFold(allowedSender, valueobject.ZeroAddress) &&
+		!strings.EqualFold(allowedSender, p.executorAddress) {
+		return unfillable
+	}
+
+	switch evaluatePredicate(order.Predicate, p.contractAddress, now) {
+	case predicateFalse:
+		return unfillable
+	case predicateUnknown:
+		return fallbackOnly
+	}
+
+	return fillable
+}
+
+// canPartiallyFill checks if the order accepts a fill of takingAmount that leaves some of it unfilled. Orders
+// without amount getters can only be filled at once, and a partial fill must not be under the maker's minimum.
+func (p *PoolSimulator) canPartiallyFill(order *order, takingAmount *big.Int) bool {
+	if len(common.FromHex(order.GetMakerAmount)) == 0 || len(common.FromHex(order.GetTakerAmount)) == 0 {
+		return false
+	}
+	if order.MinFillTakingAmount != nil && takingAmount.Cmp(order.MinFillTakingAmount) < 0 {
+		return false
+	}
+
+	return true
+}
+
 func (p *PoolSimulator) estimateGas(numberOfFilledOrders int) int64 {
 	return p.estimateGasForExecutor(numberOfFilledOrders) + p.estimateGasForRouter(numberOfFilledOrders)
 }
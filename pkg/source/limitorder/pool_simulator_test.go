@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"math/big"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
@@ -74,7 +77,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:       "",
 							GetMakerAmount:       "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:       "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							ExpiredAt:            0,
@@ -100,7 +103,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:       "",
 							GetMakerAmount:       "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:       "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							ExpiredAt:            0,
@@ -153,7 +156,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							MakerTokenFeePercent: 0,
 							MakerAssetData:       "",
 							TakerAssetData:       "",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							Signature:            "signature1",
@@ -178,7 +181,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:     "",
 							GetMakerAmount:     "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:     "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:          "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:          "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:             "",
 							Interaction:        "",
 							FeeAmount:          "0",
@@ -237,7 +240,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:       "",
 							GetMakerAmount:       "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:       "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							ExpiredAt:            0,
@@ -262,7 +265,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:       "",
 							GetMakerAmount:       "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:       "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							ExpiredAt:            0,
@@ -314,7 +317,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							MakerTokenFeePercent: 0,
 							MakerAssetData:       "",
 							TakerAssetData:       "",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							Signature:            "signature1",
@@ -338,7 +341,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:     "",
 							GetMakerAmount:     "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:     "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:          "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:          "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:             "",
 							Interaction:        "",
 							FeeAmount:          "0",
@@ -397,7 +400,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:       "",
 							GetMakerAmount:       "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:       "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							ExpiredAt:            0,
@@ -422,7 +425,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:       "",
 							GetMakerAmount:       "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:       "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							ExpiredAt:            0,
@@ -474,7 +477,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							MakerTokenFeePercent: 100,
 							MakerAssetData:       "",
 							TakerAssetData:       "",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							Signature:            "signature1",
@@ -498,7 +501,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:     "",
 							GetMakerAmount:     "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:     "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:          "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:          "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:             "",
 							Interaction:        "",
 							FeeAmount:          "0",
@@ -557,7 +560,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:       "",
 							GetMakerAmount:       "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:       "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							ExpiredAt:            0,
@@ -582,7 +585,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:       "",
 							GetMakerAmount:       "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:       "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							ExpiredAt:            0,
@@ -607,7 +610,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:       "",
 							GetMakerAmount:       "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:       "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							ExpiredAt:            0,
@@ -632,7 +635,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:       "",
 							GetMakerAmount:       "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:       "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							ExpiredAt:            0,
@@ -684,7 +687,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							MakerTokenFeePercent: 0,
 							MakerAssetData:       "",
 							TakerAssetData:       "",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							Signature:            "signature1",
@@ -708,7 +711,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:     "",
 							GetMakerAmount:     "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:     "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:          "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:          "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:             "",
 							Interaction:        "",
 							FeeAmount:          "0",
@@ -733,7 +736,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:       "",
 							GetMakerAmount:       "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:       "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							IsFallBack:           true,
@@ -793,7 +796,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:       "",
 							GetMakerAmount:       "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:       "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							ExpiredAt:            0,
@@ -818,7 +821,7 @@ func TestPool_CalcAmountOut(t *testing.T) {
 							TakerAssetData:       "",
 							GetMakerAmount:       "f4a215c3000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
 							GetTakerAmount:       "296637bf000000000000000000000000000000000000000000000001d7d843dc3b4800000000000000000000000000000000000000000000000000000de0b6b3a7640000",
-							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b0000000000000000000000000000000000000000000000000000000063c1169800000000000000000000000000000000000000000000000000000000",
+							Predicate:            "961d5b1e000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a000000000000000000000000000000000000000000000000000000000000000020000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000002892e28b58ab329741f27fd1ea56dca0192a38840000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000c00000000000000000000000000000000000000000000000000000000000000044cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590000000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002463592c2b00000000000000000000000000000000000000000000000000000000ffffffff00000000000000000000000000000000000000000000000000000000",
 							Permit:               "",
 							Interaction:          "",
 							ExpiredAt:            0,
@@ -860,3 +863,419 @@ func marshalPoolExtra(extra *Extra) string {
 	bytesData, _ := json.Marshal(extra)
 	return string(bytesData)
 }
+
+func encodeTimestampBelow(t *testing.T, timestamp int64) string {
+	args, err := timestampArguments.Pack(big.NewInt(timestamp))
+	require.NoError(t, err)
+	return common.Bytes2Hex(append(common.CopyBytes(timestampBelowSelector), args...))
+}
+
+func encodePredicates(t *testing.T, selector []byte, target string, calls ...string) string {
+	targets := make([]common.Address, len(calls))
+	data := make([][]byte, len(calls))
+	for i, call := range calls {
+		targets[i] = common.HexToAddress(target)
+		data[i] = common.FromHex(call)
+	}
+	args, err := predicateListArguments.Pack(targets, data)
+	require.NoError(t, err)
+	return common.Bytes2Hex(append(common.CopyBytes(selector), args...))
+}
+
+func TestEvaluatePredicate(t *testing.T) {
+	const contractAddress = "0x2892e28b58ab329741f27fd1ea56dca0192a3884"
+	now := time.Now().Unix()
+	nonceEquals := "cf6fc6e3000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a5900000000000000000000000000000000000000000000000000000000000000006"
+	arbitraryCall := "0x70a08231000000000000000000000000a246ec8bf7f2e54cc2f7bfdd869302ae4a08a590"
+
+	tests := []struct {
+		name      string
+		predicate string
+		want      predicateResult
+	}{
+		{"empty", "", predicateTrue},
+		{"empty hex", "0x", predicateTrue},
+		{"timestamp below", encodeTimestampBelow(t, now+60), predicateTrue},
+		{"timestamp passed", encodeTimestampBelow(t, now), predicateFalse},
+		{"nonce equals", nonceEquals, predicateTrue},
+		{"unknown call", arbitraryCall, predicateUnknown},
+		{"malformed", "0x63592c2b00", predicateUnknown},
+		{"and of known calls",
+			encodePredicates(t, andSelector, contractAddress, nonceEquals, encodeTimestampBelow(t, now+60)), predicateTrue},
+		{"and with a false call",
+			encodePredicates(t, andSelector, contractAddress, arbitraryCall, encodeTimestampBelow(t, now-60)), predicateFalse},
+		{"and with an unknown call",
+			encodePredicates(t, andSelector, contractAddress, nonceEquals, arbitraryCall), predicateUnknown},
+		{"or with a true call",
+			encodePredicates(t, orSelector, contractAddress, arbitraryCall, encodeTimestampBelow(t, now+60)), predicateTrue},
+		{"or of false calls",
+			encodePredicates(t, orSelector, contractAddress, encodeTimestampBelow(t, now-60)), predicateFalse},
+		{"call on another contract",
+			encodePredicates(t, andSelector, "0xa246ec8bf7f2e54cc2f7bfdd869302ae4a08a590", nonceEquals), predicateUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, evaluatePredicate(tt.predicate, contractAddress, now))
+		})
+	}
+}
+
+const (
+	testTokenIn         = "0xc2132d05d31c914a87c6611c10748aeb04b58e8f"
+	testTokenOut        = "0x2791bca1f2de4661ed88a30c99a7a9449aa84174"
+	testContractAddress = "0x227b0c196ea8db17a665ea6824d972a64202e936"
+	testExecutorAddress = "0x1ebd3b6ad4e4f9e8e9d6d6ac4a5b8e1e1b8a1f0b"
+	testMaker           = "0xa246ec8bf7f2e54cc2f7bfdd869302ae4a08a590"
+)
+
+// newTestOrder sells 100 testTokenOut for 100 testTokenIn, partial fills are allowed
+func newTestOrder(id int64, update func(o *order)) *order {
+	o := &order{
+		ID:                 id,
+		MakerAsset:         testTokenOut,
+		TakerAsset:         testTokenIn,
+		Maker:              testMaker,
+		AllowedSenders:     "0x0000000000000000000000000000000000000000",
+		MakingAmount:       big.NewInt(100),
+		TakingAmount:       big.NewInt(100),
+		FilledMakingAmount: big.NewInt(0),
+		FilledTakingAmount: big.NewInt(0),
+		GetMakerAmount:     "f4a215c3",
+		GetTakerAmount:     "296637bf",
+	}
+	if update != nil {
+		update(o)
+	}
+	return o
+}
+
+func newTestPoolSimulator(t *testing.T, extra Extra) *PoolSimulator {
+	staticExtra, err := json.Marshal(StaticExtra{ContractAddress: testContractAddress, ExecutorAddress: testExecutorAddress})
+	require.NoError(t, err)
+	p, err := NewPoolSimulator(entity.Pool{
+		Address:     "limit_order_pool",
+		Exchange:    "kyberswap_limit-order",
+		Type:        DexTypeLimitOrder,
+		Reserves:    entity.PoolReserves{limitOrderPoolReserve, limitOrderPoolReserve},
+		Tokens:      []*entity.PoolToken{{Address: testTokenIn}, {Address: testTokenOut}},
+		StaticExtra: string(staticExtra),
+		Extra:       marshalPoolExtra(&extra),
+	})
+	require.NoError(t, err)
+	return p
+}
+
+// filledOrderIDs returns the ids of the filled orders and of the fallback orders of a swap
+func filledOrderIDs(swapInfo SwapInfo) (filledIDs, fallbackIDs []int64) {
+	for _, filledOrder := range swapInfo.FilledOrders {
+		if filledOrder.IsFallBack {
+			fallbackIDs = append(fallbackIDs, filledOrder.OrderID)
+		} else {
+			filledIDs = append(filledIDs, filledOrder.OrderID)
+		}
+	}
+	return filledIDs, fallbackIDs
+}
+
+func TestPoolSimulator_OrderRules(t *testing.T) {
+	now := time.Now().Unix()
+
+	tests := []struct {
+		name            string
+		orders          []*order
+		amountIn        int64
+		wantErr         error
+		wantAmountOut   int64
+		wantFilledIDs   []int64
+		wantFallbackIDs []int64
+	}{
+		{
+			name:          "expired orders are skipped",
+			orders:        []*order{newTestOrder(1, func(o *order) { o.ExpiredAt = now - 60 }), newTestOrder(2, nil)},
+			amountIn:      50,
+			wantAmountOut: 50,
+			wantFilledIDs: []int64{2},
+		},
+		{
+			name: "predicate expiry is honoured",
+			orders: []*order{
+				newTestOrder(1, func(o *order) { o.Predicate = encodeTimestampBelow(t, now-60) }),
+				newTestOrder(2, func(o *order) { o.Predicate = encodeTimestampBelow(t, now+60) }),
+			},
+			amountIn:      50,
+			wantAmountOut: 50,
+			wantFilledIDs: []int64{2},
+		},
+		{
+			name: "orders for another sender are skipped",
+			orders: []*order{
+				newTestOrder(1, func(o *order) { o.AllowedSenders = "0xa246ec8bf7f2e54cc2f7bfdd869302ae4a08a590" }),
+				newTestOrder(2, func(o *order) { o.AllowedSenders = testExecutorAddress }),
+			},
+			amountIn:      50,
+			wantAmountOut: 50,
+			wantFilledIDs: []int64{2},
+		},
+		{
+			name: "all-or-nothing order is skipped on a partial fill",
+			orders: []*order{
+				newTestOrder(1, func(o *order) { o.GetMakerAmount, o.GetTakerAmount = "", "" }),
+				newTestOrder(2, nil),
+			},
+			amountIn:      50,
+			wantAmountOut: 50,
+			wantFilledIDs: []int64{2},
+		},
+		{
+			name: "all-or-nothing order is filled at once",
+			orders: []*order{
+				newTestOrder(1, func(o *order) { o.GetMakerAmount, o.GetTakerAmount = "", "" }),
+				newTestOrder(2, nil),
+			},
+			amountIn:      150,
+			wantAmountOut: 150,
+			wantFilledIDs: []int64{1, 2},
+		},
+		{
+			name: "partial fill under the minimum is skipped",
+			orders: []*order{
+				newTestOrder(1, func(o *order) { o.MinFillTakingAmount = big.NewInt(60) }),
+				newTestOrder(2, nil),
+			},
+			amountIn:      50,
+			wantAmountOut: 50,
+			wantFilledIDs: []int64{2},
+		},
+		{
+			name: "last fill of an order can be under the minimum",
+			orders: []*order{
+				newTestOrder(1, func(o *order) {
+					o.MinFillTakingAmount = big.NewInt(60)
+					o.FilledMakingAmount, o.FilledTakingAmount = big.NewInt(50), big.NewInt(50)
+				}),
+			},
+			amountIn:      50,
+			wantAmountOut: 50,
+			wantFilledIDs: []int64{1},
+		},
+		{
+			name:          "fee config is charged in maker token",
+			orders:        []*order{newTestOrder(1, func(o *order) { o.FeeConfig = new(big.Int).Lsh(big.NewInt(1000), 160) })},
+			amountIn:      50,
+			wantAmountOut: 45,
+			wantFilledIDs: []int64{1},
+		},
+		{
+			name: "fee config contracts do not read the legacy fee",
+			orders: []*order{newTestOrder(1, func(o *order) {
+				o.FeeConfig = big.NewInt(1)
+				o.MakerTokenFeePercent = 1000
+			})},
+			amountIn:      50,
+			wantAmountOut: 50,
+			wantFilledIDs: []int64{1},
+		},
+		{
+			name: "legacy contracts read the maker token fee percent",
+			orders: []*order{newTestOrder(1, func(o *order) {
+				o.FeeConfig = nil
+				o.MakerTokenFeePercent = 1000
+			})},
+			amountIn:      50,
+			wantAmountOut: 45,
+			wantFilledIDs: []int64{1},
+		},
+		{
+			name: "predicates that need a call are fallback only",
+			orders: []*order{
+				newTestOrder(1, func(o *order) { o.Predicate = "0x70a08231" }),
+				newTestOrder(2, nil),
+			},
+			amountIn:        100,
+			wantAmountOut:   100,
+			wantFilledIDs:   []int64{2},
+			wantFallbackIDs: []int64{1},
+		},
+		{
+			name:     "fallback only orders do not fulfill the amount in",
+			orders:   []*order{newTestOrder(1, func(o *order) { o.Predicate = "0x70a08231" })},
+			amountIn: 50,
+			wantErr:  ErrCannotFulfillAmountIn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPoolSimulator(t, Extra{SellOrders: tt.orders})
+
+			result, err := p.CalcAmountOut(pool.TokenAmount{Token: testTokenIn, Amount: big.NewInt(tt.amountIn)}, testTokenOut)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, big.NewInt(tt.wantAmountOut), result.TokenAmountOut.Amount)
+
+			filledIDs, fallbackIDs := filledOrderIDs(result.SwapInfo.(SwapInfo))
+			assert.Equal(t, tt.wantFilledIDs, filledIDs)
+			assert.Equal(t, tt.wantFallbackIDs, fallbackIDs)
+		})
+	}
+}
+
+func TestPoolSimulator_MakerBalances(t *testing.T) {
+	const otherMaker = "0x5b38da6a701c568545dcfcb03fcb875f56beddc4"
+
+	makerBalances := func(balance, otherBalance int64) MakerBalances {
+		return MakerBalances{
+			testMaker:  {testTokenOut: big.NewInt(balance)},
+			otherMaker: {testTokenOut: big.NewInt(otherBalance)},
+		}
+	}
+	ofOtherMaker := func(o *order) { o.Maker = otherMaker }
+
+	tests := []struct {
+		name          string
+		orders        []*order
+		makerBalances MakerBalances
+		amountIn      int64
+		wantErr       error
+		wantAmountOut int64
+		wantFilled    map[int64]string
+	}{
+		{
+			name:          "orders are not capped without maker balances",
+			orders:        []*order{newTestOrder(1, nil), newTestOrder(2, nil)},
+			amountIn:      150,
+			wantAmountOut: 150,
+			wantFilled:    map[int64]string{1: "100", 2: "50"},
+		},
+		{
+			name:          "orders of a maker share its balance",
+			orders:        []*order{newTestOrder(1, nil), newTestOrder(2, nil), newTestOrder(3, ofOtherMaker)},
+			makerBalances: makerBalances(120, 100),
+			amountIn:      200,
+			wantAmountOut: 200,
+			wantFilled:    map[int64]string{1: "100", 2: "20", 3: "80"},
+		},
+		{
+			name:          "unfunded makers are skipped",
+			orders:        []*order{newTestOrder(1, nil), newTestOrder(2, ofOtherMaker)},
+			makerBalances: MakerBalances{otherMaker: {testTokenOut: big.NewInt(100)}},
+			amountIn:      50,
+			wantAmountOut: 50,
+			wantFilled:    map[int64]string{2: "50"},
+		},
+		{
+			name: "all-or-nothing order is skipped when its maker cannot fund it",
+			orders: []*order{
+				newTestOrder(1, func(o *order) { o.GetMakerAmount, o.GetTakerAmount = "", "" }),
+				newTestOrder(2, ofOtherMaker),
+			},
+			makerBalances: makerBalances(99, 100),
+			amountIn:      100,
+			wantAmountOut: 100,
+			wantFilled:    map[int64]string{2: "100"},
+		},
+		{
+			name:          "amount in over the funded orders cannot be fulfilled",
+			orders:        []*order{newTestOrder(1, nil), newTestOrder(2, ofOtherMaker)},
+			makerBalances: makerBalances(30, 30),
+			amountIn:      100,
+			wantErr:       ErrCannotFulfillAmountIn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPoolSimulator(t, Extra{SellOrders: tt.orders, MakerBalances: tt.makerBalances})
+
+			result, err := p.CalcAmountOut(pool.TokenAmount{Token: testTokenIn, Amount: big.NewInt(tt.amountIn)}, testTokenOut)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, big.NewInt(tt.wantAmountOut), result.TokenAmountOut.Amount)
+
+			filled := make(map[int64]string)
+			for _, filledOrder := range result.SwapInfo.(SwapInfo).FilledOrders {
+				if !filledOrder.IsFallBack {
+					filled[filledOrder.OrderID] = filledOrder.FilledMakingAmount
+				}
+			}
+			assert.Equal(t, tt.wantFilled, filled)
+		})
+	}
+}
+
+func TestPoolSimulator_UpdateBalance_SharesMakerBalances(t *testing.T) {
+	// Two pools holding orders of the same maker for the same maker asset
+	p1 := newTestPoolSimulator(t, Extra{
+		SellOrders:    []*order{newTestOrder(1, nil)},
+		MakerBalances: MakerBalances{testMaker: {testTokenOut: big.NewInt(100)}},
+	})
+	p2 := newTestPoolSimulator(t, Extra{
+		SellOrders:    []*order{newTestOrder(2, nil)},
+		MakerBalances: MakerBalances{testMaker: {testTokenOut: big.NewInt(100)}},
+	})
+	inventory := pool.NewInventory(nil)
+
+	swap := func(p *PoolSimulator, amountIn int64) {
+		tokenAmountIn := pool.TokenAmount{Token: testTokenIn, Amount: big.NewInt(amountIn)}
+		result, err := p.CalcAmountOut(tokenAmountIn, testTokenOut)
+		require.NoError(t, err)
+		p.UpdateBalance(pool.UpdateBalanceParams{
+			TokenAmountIn:  tokenAmountIn,
+			TokenAmountOut: *result.TokenAmountOut,
+			SwapInfo:       result.SwapInfo,
+			Inventory:      inventory,
+		})
+	}
+
+	swap(p1, 80)
+	assert.Equal(t, big.NewInt(20), p1.makerBalances.get(testMaker, testTokenOut))
+
+	// p2 has not seen the fill of p1 yet, its next update takes the balance from the inventory
+	swap(p2, 10)
+	assert.Equal(t, big.NewInt(10), p2.makerBalances.get(testMaker, testTokenOut))
+
+	_, err := p2.CalcAmountOut(pool.TokenAmount{Token: testTokenIn, Amount: big.NewInt(20)}, testTokenOut)
+	assert.ErrorIs(t, err, ErrCannotFulfillAmountIn)
+	result, err := p2.CalcAmountOut(pool.TokenAmount{Token: testTokenIn, Amount: big.NewInt(10)}, testTokenOut)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(10), result.TokenAmountOut.Amount)
+}
+
+func TestPoolSimulator_CalcAmountOutWithInventory(t *testing.T) {
+	p1 := newTestPoolSimulator(t, Extra{
+		SellOrders:    []*order{newTestOrder(1, nil)},
+		MakerBalances: MakerBalances{testMaker: {testTokenOut: big.NewInt(100)}},
+	})
+	p2 := newTestPoolSimulator(t, Extra{
+		SellOrders:    []*order{newTestOrder(2, nil)},
+		MakerBalances: MakerBalances{testMaker: {testTokenOut: big.NewInt(100)}},
+	})
+	inventory := pool.NewInventory(nil)
+
+	tokenAmountIn := pool.TokenAmount{Token: testTokenIn, Amount: big.NewInt(80)}
+	result, err := p1.CalcAmountOutWithInventory(tokenAmountIn, testTokenOut, inventory)
+	require.NoError(t, err)
+	p1.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  tokenAmountIn,
+		TokenAmountOut: *result.TokenAmountOut,
+		SwapInfo:       result.SwapInfo,
+		Inventory:      inventory,
+	})
+
+	// p2 has not been updated, but its maker only has 20 left in the inventory
+	_, err = p2.CalcAmountOutWithInventory(pool.TokenAmount{Token: testTokenIn, Amount: big.NewInt(30)}, testTokenOut, inventory)
+	assert.ErrorIs(t, err, ErrCannotFulfillAmountIn)
+	result, err = p2.CalcAmountOutWithInventory(pool.TokenAmount{Token: testTokenIn, Amount: big.NewInt(20)}, testTokenOut, inventory)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(20), result.TokenAmountOut.Amount)
+
+	// without the inventory only its own balance caps it
+	_, err = p2.CalcAmountOut(pool.TokenAmount{Token: testTokenIn, Amount: big.NewInt(30)}, testTokenOut)
+	assert.NoError(t, err)
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math/big"
 	"strings"
 	"time"
 
+	"github.com/KyberNetwork/ethrpc"
 	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
@@ -17,14 +20,23 @@ import (
 type PoolTracker struct {
 	config           *Config
 	limitOrderClient *httpClient
+	ethrpcClient     *ethrpc.Client
 }
 
+// NewPoolTracker returns a tracker which does not read the maker balances, the orders of its pools are not capped by
+// the funds of their makers. See NewPoolTrackerWithClient.
 func NewPoolTracker(cfg *Config) *PoolTracker {
+	return NewPoolTrackerWithClient(cfg, nil)
+}
+
+// NewPoolTrackerWithClient returns a tracker which reads the balance and allowance of every maker with ethrpcClient
+func NewPoolTrackerWithClient(cfg *Config, ethrpcClient *ethrpc.Client) *PoolTracker {
 	limitOrderClient := NewHTTPClient(cfg.LimitOrderHTTPUrl)
 
 	return &PoolTracker{
 		config:           cfg,
 		limitOrderClient: limitOrderClient,
+		ethrpcClient:     ethrpcClient,
 	}
 }
 
@@ -100,6 +112,20 @@ func (d *PoolTracker) GetNewPoolState(
 		return entity.Pool{}, err
 	}
 
+	if d.ethrpcClient != nil {
+		if contractAddress == "" && len(d.config.ContractAddresses) > 0 {
+			contractAddress = d.config.ContractAddresses[0]
+		}
+		extra.MakerBalances, err = d.getMakerBalances(ctx, contractAddress, append(extra.BuyOrders, extra.SellOrders...))
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"poolAddress": p.Address,
+				"error":       err,
+			}).Errorf("failed to get maker balances")
+			return entity.Pool{}, err
+		}
+	}
+
 	extraBytes, err := json.Marshal(extra)
 	if err != nil {
 		logger.WithFields(logger.Fields{
@@ -113,3 +139,59 @@ func (d *PoolTracker) GetNewPoolState(
 	p.Timestamp = time.Now().Unix()
 	return p, nil
 }
+
+// getMakerBalances reads the balance of every maker for its maker assets, and its allowance to the limit order
+// contract when the contract is known
+func (d *PoolTracker) getMakerBalances(ctx context.Context, contractAddress string, orders []*order) (MakerBalances, error) {
+	type makerAsset struct {
+		maker, asset string
+	}
+	var makerAssets []makerAsset
+	seen := make(map[makerAsset]struct{}, len(orders))
+	for _, o := range orders {
+		key := makerAsset{maker: strings.ToLower(o.Maker), asset: strings.ToLower(o.MakerAsset)}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		makerAssets = append(makerAssets, key)
+	}
+
+	balances := make([]*big.Int, len(makerAssets))
+	allowances := make([]*big.Int, len(makerAssets))
+	for start := 0; start < len(makerAssets); start += makerBalancesBatchSize {
+		end := min(start+makerBalancesBatchSize, len(makerAssets))
+		req := d.ethrpcClient.NewRequest().SetContext(ctx)
+		for i := start; i < end; i++ {
+			req.AddCall(&ethrpc.Call{
+				ABI:    erc20ABI,
+				Target: makerAssets[i].asset,
+				Method: erc20MethodBalanceOf,
+				Params: []interface{}{common.HexToAddress(makerAssets[i].maker)},
+			}, []interface{}{&balances[i]})
+			if contractAddress == "" {
+				continue
+			}
+			req.AddCall(&ethrpc.Call{
+				ABI:    erc20ABI,
+				Target: makerAssets[i].asset,
+				Method: erc20MethodAllowance,
+				Params: []interface{}{common.HexToAddress(makerAssets[i].maker), common.HexToAddress(contractAddress)},
+			}, []interface{}{&allowances[i]})
+		}
+		if _, err := req.Aggregate(); err != nil {
+			return nil, err
+		}
+	}
+
+	makerBalances := make(MakerBalances, len(makerAssets))
+	for i, key := range makerAssets {
+		available := balances[i]
+		if allowances[i] != nil && allowances[i].Cmp(available) < 0 {
+			available = allowances[i]
+		}
+		makerBalances.set(key.maker, key.asset, available)
+	}
+
+	return makerBalances, nil
+}
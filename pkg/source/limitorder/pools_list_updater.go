@@ -113,7 +113,10 @@ func (d *PoolsListUpdater) getPoolID(token0, token1, contractAddress string) str
 }
 
 func (d *PoolsListUpdater) initPool(pair *tokenPair) (entity.Pool, error) {
-	staticExtra := StaticExtra{ContractAddress: pair.ContractAddress}
+	staticExtra := StaticExtra{
+		ContractAddress: pair.ContractAddress,
+		ExecutorAddress: strings.ToLower(d.config.ExecutorAddress),
+	}
 	staticExtraBytes, err := json.Marshal(staticExtra)
 	if err != nil {
 		logger.WithFields(logger.Fields{
@@ -0,0 +1,105 @@
+package limitorder
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type predicateResult int
+
+const (
+	predicateTrue predicateResult = iota
+	predicateFalse
+	// predicateUnknown is a predicate that needs an on-chain call to be evaluated
+	predicateUnknown
+)
+
+var (
+	// selectors of the predicate helpers of the limit order contract
+	andSelector            = common.Hex2Bytes("961d5b1e") // and(address[],bytes[])
+	orSelector             = common.Hex2Bytes("e6133301") // or(address[],bytes[])
+	timestampBelowSelector = common.Hex2Bytes("63592c2b") // timestampBelow(uint256)
+	nonceEqualsSelector    = common.Hex2Bytes("cf6fc6e3") // nonceEquals(address,uint256)
+
+	addressArrayType, _ = abi.NewType("address[]", "", nil)
+	bytesArrayType, _   = abi.NewType("bytes[]", "", nil)
+	uint256Type, _      = abi.NewType("uint256", "", nil)
+
+	predicateListArguments = abi.Arguments{{Type: addressArrayType}, {Type: bytesArrayType}}
+	timestampArguments     = abi.Arguments{{Type: uint256Type}}
+)
+
+// evaluatePredicate evaluates the predicate of an order at the given unix time. Only the helpers of the limit order
+// contract are understood, and the predicate must call them on contractAddress when it is known. nonceEquals is
+// treated as true because the order service drops the orders of makers who increased their nonce.
+func evaluatePredicate(predicate string, contractAddress string, now int64) predicateResult {
+	data := common.FromHex(predicate)
+	if len(data) == 0 {
+		return predicateTrue
+	}
+
+	return evaluatePredicateCall(data, contractAddress, now)
+}
+
+func evaluatePredicateCall(data []byte, contractAddress string, now int64) predicateResult {
+	if len(data) < 4 {
+		return predicateUnknown
+	}
+	selector, args := data[:4], data[4:]
+
+	switch {
+	case bytes.Equal(selector, timestampBelowSelector):
+		values, err := timestampArguments.Unpack(args)
+		if err != nil {
+			return predicateUnknown
+		}
+		if big.NewInt(now).Cmp(values[0].(*big.Int)) < 0 {
+			return predicateTrue
+		}
+		return predicateFalse
+
+	case bytes.Equal(selector, nonceEqualsSelector):
+		return predicateTrue
+
+	case bytes.Equal(selector, andSelector), bytes.Equal(selector, orSelector):
+		values, err := predicateListArguments.Unpack(args)
+		if err != nil {
+			return predicateUnknown
+		}
+		targets, calls := values[0].([]common.Address), values[1].([][]byte)
+		if len(targets) != len(calls) {
+			return predicateUnknown
+		}
+
+		isAnd := bytes.Equal(selector, andSelector)
+		result := predicateFalse
+		if isAnd {
+			result = predicateTrue
+		}
+		for i, call := range calls {
+			if contractAddress != "" && !strings.EqualFold(targets[i].Hex(), contractAddress) {
+				return predicateUnknown
+			}
+
+			switch evaluatePredicateCall(call, contractAddress, now) {
+			case predicateUnknown:
+				result = predicateUnknown
+			case predicateFalse:
+				if isAnd {
+					return predicateFalse
+				}
+			case predicateTrue:
+				if !isAnd {
+					return predicateTrue
+				}
+			}
+		}
+		return result
+	}
+
+	return predicateUnknown
+}
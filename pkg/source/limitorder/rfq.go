@@ -2,53 +2,123 @@ package limitorder
 
 import (
 	"context"
-	"encoding/json"
+	"math/big"
+	"time"
 
+	"github.com/KyberNetwork/ethrpc"
 	"github.com/KyberNetwork/logger"
 	"github.com/samber/lo"
 
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
 )
 
+// RFQHandler is the rfq maker of the limit order book, the firm quote is the operator signature of every filled
+// order
 type RFQHandler struct {
-	config *Config
-	client *httpClient
+	config       *Config
+	client       *httpClient
+	ethrpcClient *ethrpc.Client
+	handler      *rfq.Handler
 }
 
+// NewRFQHandler returns a handler which only accepts the orders of EOA makers, see NewRFQHandlerWithClient
 func NewRFQHandler(config *Config) *RFQHandler {
+	return NewRFQHandlerWithClient(config, nil)
+}
+
+// NewRFQHandlerWithClient returns a handler which also accepts the orders of contract makers, their signatures are
+// checked with ERC-1271 isValidSignature
+func NewRFQHandlerWithClient(config *Config, ethrpcClient *ethrpc.Client) *RFQHandler {
 	client := NewHTTPClient(config.LimitOrderHTTPUrl)
-	return &RFQHandler{
-		config: config,
-		client: client,
+	h := &RFQHandler{
+		config:       config,
+		client:       client,
+		ethrpcClient: ethrpcClient,
 	}
+	h.handler = rfq.NewHandler(config.RFQ, parseRequest, h)
+
+	return h
 }
 
 func (h *RFQHandler) RFQ(ctx context.Context, recipient string, params any) (pool.RFQResult, error) {
-	paramsByteData, err := json.Marshal(params)
-	if err != nil {
-		return pool.RFQResult{}, err
-	}
+	return h.handler.RFQ(ctx, recipient, params)
+}
 
-	var swapInfo SwapInfo
-	if err = json.Unmarshal(paramsByteData, &swapInfo); err != nil {
-		return pool.RFQResult{}, InvalidSwapInfo
+func (h *RFQHandler) Name() string {
+	return h.config.DexID
+}
+
+func (h *RFQHandler) Firm(ctx context.Context, req rfq.Request) (rfq.Quote, error) {
+	swapInfo, ok := req.Params.(SwapInfo)
+	if !ok {
+		return rfq.Quote{}, InvalidSwapInfo
 	}
 
 	orderIds := lo.Map(swapInfo.FilledOrders, func(o *FilledOrderInfo, _ int) int64 { return o.OrderID })
 	result, err := h.client.GetOpSignatures(ctx, ChainID(h.config.ChainID), orderIds)
 	if err != nil {
 		logger.WithFields(logger.Fields{
-			"params": params,
+			"params": req.Params,
 			"error":  err,
 		}).Errorf("failed to get operator signatures")
-		return pool.RFQResult{}, err
+		return rfq.Quote{}, err
+	}
+
+	signatures := lo.SliceToMap(result, func(sig *operatorSignatures) (int64, *operatorSignatures) { return sig.ID, sig })
+	if err = h.validateFilledOrders(ctx, req, swapInfo, signatures, time.Now()); err != nil {
+		logger.WithFields(logger.Fields{
+			"params": req.Params,
+			"error":  err,
+		}).Errorf("invalid filled orders")
+		return rfq.Quote{}, err
 	}
 
-	return pool.RFQResult{
-		NewAmountOut: nil, // at the moment we don't use the new amount out of Limit Order, nil will ignore it
+	// the quote is fillable until the first operator signature expires
+	var expiry time.Time
+	for _, sig := range result {
+		if expiredAt := time.Unix(sig.OperatorSignatureExpiredAt, 0); expiry.IsZero() || expiredAt.Before(expiry) {
+			expiry = expiredAt
+		}
+	}
+
+	return rfq.Quote{
+		AmountOut: nil, // at the moment we don't use the new amount out of Limit Order, nil will ignore it
+		Expiry:    expiry,
 		Extra: OpSignatureExtra{
 			SwapInfo:               swapInfo,
-			OperatorSignaturesById: lo.SliceToMap(result, func(sig *operatorSignatures) (int64, *operatorSignatures) { return sig.ID, sig }),
+			OperatorSignaturesById: signatures,
 		},
 	}, nil
 }
+
+// parseRequest reads the assets from the filled orders, every order of a swap has the same pair
+func parseRequest(recipient string, params any) (rfq.Request, error) {
+	swapInfo, err := rfq.DecodeParams[SwapInfo](params)
+	if err != nil || len(swapInfo.FilledOrders) == 0 {
+		return rfq.Request{}, InvalidSwapInfo
+	}
+
+	amountIn, ok := new(big.Int).SetString(swapInfo.AmountIn, 10)
+	if !ok {
+		return rfq.Request{}, InvalidSwapInfo
+	}
+
+	amountOut := new(big.Int)
+	for _, filledOrder := range swapInfo.FilledOrders {
+		filledMakingAmount, ok := new(big.Int).SetString(filledOrder.FilledMakingAmount, 10)
+		if !ok {
+			return rfq.Request{}, InvalidSwapInfo
+		}
+		amountOut.Add(amountOut, filledMakingAmount)
+	}
+
+	return rfq.Request{
+		Taker:     recipient,
+		TokenIn:   swapInfo.FilledOrders[0].TakerAsset,
+		TokenOut:  swapInfo.FilledOrders[0].MakerAsset,
+		AmountIn:  amountIn,
+		AmountOut: amountOut,
+		Params:    swapInfo,
+	}, nil
+}
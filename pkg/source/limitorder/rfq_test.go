@@ -0,0 +1,178 @@
+package limitorder
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/rfq"
+)
+
+const (
+	testContract   = "0x227b0c196ea8db17a665ea6824d972a64202e936"
+	testMakerAsset = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	testTakerAsset = "0x6b175474e89094c44da98b954eedeac495271d0f"
+)
+
+func signTypedData(t *testing.T, key *ecdsa.PrivateKey, typedData apitypes.TypedData) string {
+	digest, err := rfq.HashTypedData(typedData)
+	require.NoError(t, err)
+	sig, err := crypto.Sign(digest.Bytes(), key)
+	require.NoError(t, err)
+	sig[64] += 27
+
+	return hexutil.Encode(sig)
+}
+
+// erc1271Eth is the eth namespace of a node whose every contract returns magicValue from isValidSignature
+type erc1271Eth struct {
+	magicValue [4]byte
+}
+
+func (e *erc1271Eth) Call(_ map[string]interface{}, _ string) hexutil.Bytes {
+	return common.RightPadBytes(e.magicValue[:], 32)
+}
+
+func newERC1271Client(t *testing.T, magicValue [4]byte) *ethrpc.Client {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &erc1271Eth{magicValue: magicValue}))
+	node := httptest.NewServer(server)
+	t.Cleanup(node.Close)
+
+	client, err := ethclient.Dial(node.URL)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	return ethrpc.NewWithClient(client)
+}
+
+func TestRFQHandler_ValidateFilledOrders(t *testing.T) {
+	makerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	operatorKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	maker := strings.ToLower(crypto.PubkeyToAddress(makerKey.PublicKey).Hex())
+	operator := strings.ToLower(crypto.PubkeyToAddress(operatorKey.PublicKey).Hex())
+
+	ctx := context.Background()
+	handler := &RFQHandler{config: &Config{ChainID: 1, OperatorAddress: operator}}
+	req := rfq.Request{
+		TokenIn:   testTakerAsset,
+		TokenOut:  testMakerAsset,
+		AmountIn:  big.NewInt(1000),
+		AmountOut: big.NewInt(500),
+	}
+	expiredAt := time.Now().Add(time.Minute).Unix()
+
+	newSignedSwap := func(feeConfig string, signer *ecdsa.PrivateKey, opSigner *ecdsa.PrivateKey) (SwapInfo, map[int64]*operatorSignatures) {
+		filledOrder := &FilledOrderInfo{
+			OrderID:            7,
+			FilledTakingAmount: "1000",
+			FilledMakingAmount: "500",
+			TakingAmount:       "2000",
+			MakingAmount:       "1000",
+			Salt:               "123456789",
+			MakerAsset:         testMakerAsset,
+			TakerAsset:         testTakerAsset,
+			Maker:              maker,
+			FeeConfig:          feeConfig,
+		}
+		typedData := filledOrder.TypedData(1, testContract)
+		filledOrder.Signature = signTypedData(t, signer, typedData)
+
+		orderHash, err := rfq.HashTypedData(typedData)
+		require.NoError(t, err)
+
+		return SwapInfo{
+			AmountIn:        "1000",
+			FilledOrders:    []*FilledOrderInfo{filledOrder},
+			ContractAddress: testContract,
+		}, map[int64]*operatorSignatures{
+			7: {
+				ID:                         7,
+				OperatorSignature:          signTypedData(t, opSigner, operatorTypedData(1, testContract, orderHash, expiredAt)),
+				OperatorSignatureExpiredAt: expiredAt,
+			},
+		}
+	}
+
+	t.Run("valid orders", func(t *testing.T) {
+		for _, feeConfig := range []string{"", "1000"} {
+			swapInfo, signatures := newSignedSwap(feeConfig, makerKey, operatorKey)
+			assert.NoError(t, handler.validateFilledOrders(ctx, req, swapInfo, signatures, time.Now()))
+		}
+	})
+
+	t.Run("configured domain", func(t *testing.T) {
+		swapInfo, signatures := newSignedSwap("1000", makerKey, operatorKey)
+
+		config := *handler.config
+		config.Domain = rfq.Domain{Version: "2"}
+		otherDomain := &RFQHandler{config: &config}
+		assert.ErrorIs(t, otherDomain.validateFilledOrders(ctx, req, swapInfo, signatures, time.Now()), rfq.ErrUnexpectedSigner)
+	})
+
+	t.Run("operator not configured", func(t *testing.T) {
+		swapInfo, signatures := newSignedSwap("1000", makerKey, operatorKey)
+
+		config := *handler.config
+		config.OperatorAddress = ""
+		withoutOperator := &RFQHandler{config: &config}
+		assert.ErrorIs(t, withoutOperator.validateFilledOrders(ctx, req, swapInfo, signatures, time.Now()),
+			ErrOperatorNotConfigured)
+	})
+
+	t.Run("maker signature", func(t *testing.T) {
+		swapInfo, signatures := newSignedSwap("1000", operatorKey, operatorKey)
+		assert.ErrorIs(t, handler.validateFilledOrders(ctx, req, swapInfo, signatures, time.Now()), rfq.ErrUnexpectedSigner)
+	})
+
+	t.Run("contract maker signature", func(t *testing.T) {
+		swapInfo, signatures := newSignedSwap("1000", operatorKey, operatorKey)
+
+		accepting := &RFQHandler{config: handler.config, ethrpcClient: newERC1271Client(t, erc1271MagicValue)}
+		assert.NoError(t, accepting.validateFilledOrders(ctx, req, swapInfo, signatures, time.Now()))
+
+		rejecting := &RFQHandler{config: handler.config, ethrpcClient: newERC1271Client(t, [4]byte{})}
+		assert.ErrorIs(t, rejecting.validateFilledOrders(ctx, req, swapInfo, signatures, time.Now()), rfq.ErrUnexpectedSigner)
+	})
+
+	t.Run("operator signature", func(t *testing.T) {
+		swapInfo, signatures := newSignedSwap("1000", makerKey, makerKey)
+		assert.ErrorIs(t, handler.validateFilledOrders(ctx, req, swapInfo, signatures, time.Now()), rfq.ErrUnexpectedSigner)
+
+		delete(signatures, 7)
+		assert.ErrorIs(t, handler.validateFilledOrders(ctx, req, swapInfo, signatures, time.Now()), rfq.ErrInvalidSignature)
+	})
+
+	t.Run("expired operator signature", func(t *testing.T) {
+		swapInfo, signatures := newSignedSwap("1000", makerKey, operatorKey)
+		err := handler.validateFilledOrders(ctx, req, swapInfo, signatures, time.Now().Add(2*time.Minute))
+		assert.ErrorIs(t, err, rfq.ErrQuoteExpired)
+	})
+
+	t.Run("order does not match the request", func(t *testing.T) {
+		swapInfo, signatures := newSignedSwap("1000", makerKey, operatorKey)
+		swapInfo.FilledOrders[0].FilledMakingAmount = "1001"
+		assert.ErrorIs(t, handler.validateFilledOrders(ctx, req, swapInfo, signatures, time.Now()), rfq.ErrQuoteMismatch)
+
+		swapInfo, signatures = newSignedSwap("1000", makerKey, operatorKey)
+		reversed := req
+		reversed.TokenIn, reversed.TokenOut = req.TokenOut, req.TokenIn
+		assert.ErrorIs(t, handler.validateFilledOrders(ctx, reversed, swapInfo, signatures, time.Now()), rfq.ErrQuoteMismatch)
+	})
+}
@@ -1,5 +1,10 @@
 package limitorder
 
+import (
+	"math/big"
+	"strings"
+)
+
 type ChainID uint
 
 type tokenPair struct {
@@ -11,10 +16,33 @@ type tokenPair struct {
 type Extra struct {
 	SellOrders []*order
 	BuyOrders  []*order
+	// MakerBalances is nil for pools tracked before the maker funds were read, their orders are not capped
+	MakerBalances MakerBalances
+}
+
+// MakerBalances is the amount a maker can be charged for each of its maker assets, the lower of its balance and its
+// allowance to the limit order contract. It is keyed by maker then maker asset, both lowercase.
+type MakerBalances map[string]map[string]*big.Int
+
+func (b MakerBalances) get(maker, makerAsset string) *big.Int {
+	if balance, ok := b[strings.ToLower(maker)][strings.ToLower(makerAsset)]; ok {
+		return balance
+	}
+
+	return big.NewInt(0)
+}
+
+func (b MakerBalances) set(maker, makerAsset string, balance *big.Int) {
+	maker, makerAsset = strings.ToLower(maker), strings.ToLower(makerAsset)
+	if b[maker] == nil {
+		b[maker] = make(map[string]*big.Int)
+	}
+	b[maker][makerAsset] = balance
 }
 
 type StaticExtra struct {
 	ContractAddress string
+	ExecutorAddress string
 }
 
 type SwapSide string
@@ -23,6 +51,8 @@ type SwapInfo struct {
 	AmountIn     string             `json:"amountIn"`
 	SwapSide     SwapSide           `json:"swapSide"`
 	FilledOrders []*FilledOrderInfo `json:"filledOrders"`
+	// ContractAddress is the limit order contract the orders are signed for
+	ContractAddress string `json:"contractAddress,omitempty"`
 }
 
 type FilledOrderInfo struct {
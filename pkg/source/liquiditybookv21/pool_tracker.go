@@ -36,7 +36,24 @@ func NewPoolTracker(cfg *Config, ethrpcClient *ethrpc.Client) (*PoolTracker, err
 	}, nil
 }
 
-func (d *PoolTracker) GetNewPoolState(ctx context.Context, p entity.Pool, _ pool.GetNewPoolStateParams) (entity.Pool, error) {
+// RpcCall is a call added to the multicall of the pair state, its results are unpacked into Outputs
+type RpcCall struct {
+	Call    *ethrpc.Call
+	Outputs []interface{}
+}
+
+func (d *PoolTracker) GetNewPoolState(ctx context.Context, p entity.Pool, params pool.GetNewPoolStateParams) (entity.Pool, error) {
+	return d.GetNewPoolStateWithCalls(ctx, p, params)
+}
+
+// GetNewPoolStateWithCalls is GetNewPoolState with calls added to the multicall of the pair state, for the pairs of
+// later versions which have more state to track
+func (d *PoolTracker) GetNewPoolStateWithCalls(
+	ctx context.Context,
+	p entity.Pool,
+	_ pool.GetNewPoolStateParams,
+	calls ...RpcCall,
+) (entity.Pool, error) {
 	logger.WithFields(logger.Fields{
 		"address": p.Address,
 	}).Infof("[%s] Start getting new state of pool", p.Type)
@@ -49,7 +66,7 @@ func (d *PoolTracker) GetNewPoolState(ctx context.Context, p entity.Pool, _ pool
 
 	g := new(errgroup.Group)
 	g.Go(func() error {
-		rpcResult, err = d.queryRpc(ctx, p)
+		rpcResult, err = d.queryRpc(ctx, p, calls)
 		if err != nil {
 			return err
 		}
@@ -94,7 +111,7 @@ func (d *PoolTracker) GetNewPoolState(ctx context.Context, p entity.Pool, _ pool
 	return p, nil
 }
 
-func (d *PoolTracker) queryRpc(ctx context.Context, p entity.Pool) (*queryRpcPoolStateResult, error) {
+func (d *PoolTracker) queryRpc(ctx context.Context, p entity.Pool, calls []RpcCall) (*queryRpcPoolStateResult, error) {
 	var (
 		blockTimestamp uint64
 		binStep        uint16
@@ -140,6 +157,10 @@ func (d *PoolTracker) queryRpc(ctx context.Context, p entity.Pool) (*queryRpcPoo
 		Method: pairMethodGetBinStep,
 	}, []interface{}{&binStep})
 
+	for _, call := range calls {
+		req.AddCall(call.Call, call.Outputs)
+	}
+
 	if _, err := req.Aggregate(); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,11 @@
+package liquiditybookv22
+
+import "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/liquiditybookv21"
+
+type Config struct {
+	liquiditybookv21.Config
+
+	// AllowedHooks are the hooks contracts which run on swaps but are known not to change the swap amounts, like the
+	// rewarders of Trader Joe, pairs with other swap hooks are not quoted.
+	AllowedHooks []string `json:"allowedHooks"`
+}
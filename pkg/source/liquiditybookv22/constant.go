@@ -0,0 +1,29 @@
+package liquiditybookv22
+
+const (
+	DexTypeLiquidityBookV22 = "liquiditybook-v22"
+)
+
+const (
+	factoryMethodGetNumberOfLBPairs = "getNumberOfLBPairs"
+	factoryMethodGetLBPairAtIndex   = "getLBPairAtIndex"
+
+	pairMethodGetTokenX            = "getTokenX"
+	pairMethodGetTokenY            = "getTokenY"
+	pairMethodGetLBHooksParameters = "getLBHooksParameters"
+)
+
+const (
+	defaultTokenWeight = 50
+
+	// swapHooksGas is the extra gas of a swap on a pair whose allowed hooks run on swaps
+	swapHooksGas = 60000
+)
+
+// https://github.com/traderjoe-xyz/joe-v2/blob/v2.2.0/src/libraries/Hooks.sol#L17
+const (
+	hooksAddressBits = 160
+
+	beforeSwapFlag = 0
+	afterSwapFlag  = 1
+)
@@ -0,0 +1,9 @@
+package liquiditybookv22
+
+import _ "embed"
+
+//go:embed abis/LBPair.json
+var pairABIJson []byte
+
+//go:embed abis/LBFactory.json
+var factoryABIJson []byte
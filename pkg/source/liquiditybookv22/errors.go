@@ -0,0 +1,7 @@
+package liquiditybookv22
+
+import "errors"
+
+var (
+	ErrSwapHooks = errors.New("pair has hooks which run on swaps and are not allowed")
+)
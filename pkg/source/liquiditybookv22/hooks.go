@@ -0,0 +1,32 @@
+package liquiditybookv22
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// decodeHooksParameters decodes the hooks parameters of a pair, a pair without hooks has zero parameters
+func decodeHooksParameters(parameters [32]byte, allowedHooks []string) Hooks {
+	hooksAddress := common.BytesToAddress(parameters[12:])
+	if hooksAddress == (common.Address{}) {
+		return Hooks{}
+	}
+
+	flags := new(big.Int).Rsh(new(big.Int).SetBytes(parameters[:]), hooksAddressBits)
+	hooks := Hooks{
+		Address:    strings.ToLower(hooksAddress.Hex()),
+		BeforeSwap: flags.Bit(beforeSwapFlag) == 1,
+		AfterSwap:  flags.Bit(afterSwapFlag) == 1,
+	}
+
+	for _, allowed := range allowedHooks {
+		if strings.EqualFold(allowed, hooks.Address) {
+			hooks.Allowed = true
+			break
+		}
+	}
+
+	return hooks
+}
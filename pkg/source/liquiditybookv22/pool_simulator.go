@@ -0,0 +1,54 @@
+package liquiditybookv22
+
+import (
+	"encoding/json"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/liquiditybookv21"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// PoolSimulator swaps through the bins of a pair like v2.1, pairs whose hooks run on swaps are only quoted when the
+// hooks are allowed.
+type PoolSimulator struct {
+	*liquiditybookv21.PoolSimulator
+
+	hooks Hooks
+}
+
+func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
+	var extra Extra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	poolSimulator, err := liquiditybookv21.NewPoolSimulator(entityPool)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PoolSimulator{
+		PoolSimulator: poolSimulator,
+		hooks:         extra.Hooks,
+	}, nil
+}
+
+func (p *PoolSimulator) CalcAmountOut(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	if p.hooks.runOnSwap() && !p.hooks.Allowed {
+		return nil, ErrSwapHooks
+	}
+
+	result, err := p.PoolSimulator.CalcAmountOut(tokenAmountIn, tokenOut)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.hooks.runOnSwap() {
+		result.Gas += swapHooksGas
+	}
+
+	return result, nil
+}
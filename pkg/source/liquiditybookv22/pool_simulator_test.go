@@ -0,0 +1,108 @@
+package liquiditybookv22
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/liquiditybookv21"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+const (
+	tokenX = "0xb31f66aa3c1e785363f0875a1b74e27b85fd66c7"
+	tokenY = "0xb97ef9ef8734c71904d8002f8b6bc66dd9c48a6e"
+
+	hooksAddress = "0x1ad5a6a2a3fa1ab22ea5ddfd9f7a6fb4ad42ec1c"
+
+	// a pair with 1000 Y in the bin below the active bin, 1000 X and 1000 Y in the active bin and 1000 X above it
+	extraV21 = `{"rpcBlockTimestamp":1710000000,"subgraphBlockTimestamp":1710000000,"staticFeeParams":{"baseFactor":5000,"filterPeriod":30,"decayPeriod":600,"reductionFactor":5000,"variableFeeControl":40000,"protocolShare":1000,"maxVolatilityAccumulator":350000},"variableFeeParams":{"volatilityAccumulator":0,"volatilityReference":0,"idReference":8388608,"timeOfLastUpdate":1710000000},"activeBinId":8388608,"binStep":10,"bins":[{"id":8388607,"reserveX":0,"reserveY":1000000000000000000000,"totalSupply":1000000000000000000000},{"id":8388608,"reserveX":1000000000000000000000,"reserveY":1000000000000000000000,"totalSupply":2000000000000000000000},{"id":8388609,"reserveX":1000000000000000000000,"reserveY":0,"totalSupply":1000000000000000000000}]}`
+)
+
+func newTestEntityPool(t *testing.T, hooks Hooks) entity.Pool {
+	var extra Extra
+	require.NoError(t, json.Unmarshal([]byte(extraV21), &extra.Extra))
+	extra.Hooks = hooks
+
+	extraBytes, err := json.Marshal(extra)
+	require.NoError(t, err)
+
+	return entity.Pool{
+		Address:  "0xd446eb1660f766d533beceef890df7a69d26f7d1",
+		Exchange: "traderjoe-v22",
+		Type:     DexTypeLiquidityBookV22,
+		Reserves: entity.PoolReserves{"2000000000000000000000", "2000000000000000000000"},
+		Tokens: []*entity.PoolToken{
+			{Address: tokenX, Weight: 50, Swappable: true},
+			{Address: tokenY, Weight: 50, Swappable: true},
+		},
+		Extra: string(extraBytes),
+	}
+}
+
+func TestCalcAmountOut(t *testing.T) {
+	tokenAmountIn := pool.TokenAmount{Token: tokenX, Amount: big.NewInt(1500000000000000000)}
+
+	v21Simulator, err := liquiditybookv21.NewPoolSimulator(newTestEntityPool(t, Hooks{}))
+	require.NoError(t, err)
+	expected, err := v21Simulator.CalcAmountOut(tokenAmountIn, tokenY)
+	require.NoError(t, err)
+
+	t.Run("pair without hooks is quoted like v2.1", func(t *testing.T) {
+		simulator, err := NewPoolSimulator(newTestEntityPool(t, Hooks{}))
+		require.NoError(t, err)
+
+		result, err := simulator.CalcAmountOut(tokenAmountIn, tokenY)
+		require.NoError(t, err)
+		assert.Equal(t, expected.TokenAmountOut.Amount, result.TokenAmountOut.Amount)
+		assert.Equal(t, expected.Fee.Amount, result.Fee.Amount)
+		assert.Equal(t, expected.Gas, result.Gas)
+	})
+
+	t.Run("pair with hooks which do not run on swaps", func(t *testing.T) {
+		simulator, err := NewPoolSimulator(newTestEntityPool(t, Hooks{Address: hooksAddress}))
+		require.NoError(t, err)
+
+		result, err := simulator.CalcAmountOut(tokenAmountIn, tokenY)
+		require.NoError(t, err)
+		assert.Equal(t, expected.TokenAmountOut.Amount, result.TokenAmountOut.Amount)
+	})
+
+	t.Run("pair with swap hooks is refused", func(t *testing.T) {
+		simulator, err := NewPoolSimulator(newTestEntityPool(t, Hooks{Address: hooksAddress, BeforeSwap: true}))
+		require.NoError(t, err)
+
+		_, err = simulator.CalcAmountOut(tokenAmountIn, tokenY)
+		assert.ErrorIs(t, err, ErrSwapHooks)
+	})
+
+	t.Run("pair with allowed swap hooks costs more gas", func(t *testing.T) {
+		simulator, err := NewPoolSimulator(newTestEntityPool(t, Hooks{Address: hooksAddress, AfterSwap: true, Allowed: true}))
+		require.NoError(t, err)
+
+		result, err := simulator.CalcAmountOut(tokenAmountIn, tokenY)
+		require.NoError(t, err)
+		assert.Equal(t, expected.TokenAmountOut.Amount, result.TokenAmountOut.Amount)
+		assert.Equal(t, expected.Gas+swapHooksGas, result.Gas)
+	})
+}
+
+func TestDecodeHooksParameters(t *testing.T) {
+	var parameters [32]byte
+	assert.Equal(t, Hooks{}, decodeHooksParameters(parameters, nil))
+
+	// before swap, before mint and before burn flags
+	copy(parameters[12:], common.HexToAddress(hooksAddress).Bytes())
+	parameters[11] = 1<<beforeSwapFlag | 1<<4 | 1<<6
+
+	assert.Equal(t, Hooks{Address: hooksAddress, BeforeSwap: true}, decodeHooksParameters(parameters, nil))
+	assert.Equal(t,
+		Hooks{Address: hooksAddress, BeforeSwap: true, Allowed: true},
+		decodeHooksParameters(parameters, []string{common.HexToAddress(hooksAddress).Hex()}),
+	)
+}
@@ -0,0 +1,67 @@
+package liquiditybookv22
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/liquiditybookv21"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// PoolTracker tracks the bins and the fee parameters of a pair like v2.1, and the hooks of the pair
+type PoolTracker struct {
+	*liquiditybookv21.PoolTracker
+
+	cfg          *Config
+	ethrpcClient *ethrpc.Client
+}
+
+func NewPoolTracker(cfg *Config, ethrpcClient *ethrpc.Client) (*PoolTracker, error) {
+	poolTracker, err := liquiditybookv21.NewPoolTracker(&cfg.Config, ethrpcClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PoolTracker{
+		PoolTracker:  poolTracker,
+		cfg:          cfg,
+		ethrpcClient: ethrpcClient,
+	}, nil
+}
+
+func (d *PoolTracker) GetNewPoolState(ctx context.Context, p entity.Pool, params pool.GetNewPoolStateParams) (entity.Pool, error) {
+	var hooksParameters [32]byte
+	p, err := d.PoolTracker.GetNewPoolStateWithCalls(ctx, p, params, liquiditybookv21.RpcCall{
+		Call: &ethrpc.Call{
+			ABI:    pairABI,
+			Target: p.Address,
+			Method: pairMethodGetLBHooksParameters,
+		},
+		Outputs: []interface{}{&hooksParameters},
+	})
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to get pool state")
+		return entity.Pool{}, err
+	}
+
+	var extra Extra
+	if err := json.Unmarshal([]byte(p.Extra), &extra.Extra); err != nil {
+		return entity.Pool{}, err
+	}
+	extra.Hooks = decodeHooksParameters(hooksParameters, d.cfg.AllowedHooks)
+
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		return entity.Pool{}, err
+	}
+	p.Extra = string(extraBytes)
+
+	return p, nil
+}
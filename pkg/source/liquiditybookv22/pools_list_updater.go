@@ -0,0 +1,48 @@
+package liquiditybookv22
+
+import (
+	"context"
+
+	"github.com/KyberNetwork/ethrpc"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/traderjoecommon"
+)
+
+// PoolsListUpdater lists the pairs of the v2.2 LBFactory, which keeps the append-only list of pairs of the previous
+// factories.
+type PoolsListUpdater struct {
+	*traderjoecommon.PoolsListUpdater
+}
+
+func NewPoolsListUpdater(
+	cfg *Config,
+	ethrpcClient *ethrpc.Client,
+) *PoolsListUpdater {
+	return &PoolsListUpdater{
+		PoolsListUpdater: &traderjoecommon.PoolsListUpdater{
+			Config: &traderjoecommon.Config{
+				DexID:          cfg.DexID,
+				FactoryAddress: cfg.FactoryAddress,
+				NewPoolLimit:   cfg.NewPoolLimit,
+			},
+			EthrpcClient:               ethrpcClient,
+			FactoryABI:                 factoryABI,
+			FactoryNumberOfPairsMethod: factoryMethodGetNumberOfLBPairs,
+			FactoryGetPairMethod:       factoryMethodGetLBPairAtIndex,
+			PairABI:                    pairABI,
+			PairTokenXMethod:           pairMethodGetTokenX,
+			PairTokenYMethod:           pairMethodGetTokenY,
+			DexType:                    DexTypeLiquidityBookV22,
+			DefaultTokenWeight:         defaultTokenWeight,
+		},
+	}
+}
+
+func (d *PoolsListUpdater) InitPool(_ context.Context) error {
+	return nil
+}
+
+func (d *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte) ([]entity.Pool, []byte, error) {
+	return d.PoolsListUpdater.GetNewPools(ctx, metadataBytes)
+}
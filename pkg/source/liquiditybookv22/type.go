@@ -0,0 +1,26 @@
+package liquiditybookv22
+
+import "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/liquiditybookv21"
+
+// Extra is the extra of a v2.1 pair with the hooks of the pair, the bins and the fee parameters of v2.2 pairs are
+// encoded the same way as v2.1.
+type Extra struct {
+	liquiditybookv21.Extra
+
+	Hooks Hooks `json:"hooks"`
+}
+
+// Hooks are the hooks parameters of a pair, an address in the low 160 bits and the flags of the hooks to call above
+type Hooks struct {
+	Address    string `json:"address,omitempty"`
+	BeforeSwap bool   `json:"beforeSwap,omitempty"`
+	AfterSwap  bool   `json:"afterSwap,omitempty"`
+
+	// Allowed is set by the tracker when the hooks are in the AllowedHooks of its config, the simulator only sees the
+	// entity pool so a change of AllowedHooks applies to a pair from its next update
+	Allowed bool `json:"allowed,omitempty"`
+}
+
+func (h Hooks) runOnSwap() bool {
+	return h.BeforeSwap || h.AfterSwap
+}
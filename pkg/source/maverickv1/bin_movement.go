@@ -0,0 +1,108 @@
+package maverickv1
+
+import (
+	"math/big"
+)
+
+// moveBins moves the bins of the movement kinds after a swap, as the pool does in `_moveBins`. Bins of kind right
+// follow the twa when it moves right, bins of kind left when it moves left and bins of kind both in either direction.
+// A bin that moves to a tick which already has a bin of its kind is merged into that bin.
+//
+// The twa only changes with the time since the last swap, so only the first swap simulated on a state moves bins and
+// the twa of the next swaps is the one stored by it.
+func moveBins(state *MaverickPoolState, startingTick, activeTick int32) {
+	if state.LastTwaD8 == nil || state.TwaD8 == nil {
+		return
+	}
+
+	lastTwaTick := twaTick(state.LastTwaD8)
+	newTwaTick := twaTick(state.TwaD8)
+	state.LastTwaD8 = new(big.Int).Set(state.TwaD8)
+
+	if lastTwaTick == newTwaTick {
+		return
+	}
+
+	moveRight := newTwaTick > lastTwaTick
+	for kind := KindRight; kind <= KindBoth; kind++ {
+		if (moveRight && kind == KindLeft) || (!moveRight && kind == KindRight) {
+			continue
+		}
+
+		// the pool searches the ticks between the last twa and the ticks the swap went through
+		var fromTick, toTick int32
+		if moveRight {
+			fromTick, toTick = minInt32(lastTwaTick, startingTick, activeTick), newTwaTick-1
+		} else {
+			fromTick, toTick = newTwaTick+1, maxInt32(lastTwaTick, startingTick, activeTick)
+		}
+
+		for tick := fromTick; tick <= toTick; tick++ {
+			moveBinToTick(state, kind, tick, newTwaTick)
+		}
+	}
+}
+
+// moveBinToTick moves the bin of a kind at a tick to another tick, merging it into the bin of the same kind there
+func moveBinToTick(state *MaverickPoolState, kind uint8, fromTick, toTick int32) {
+	binID := state.BinPositions[fromTick][kind]
+	if binID == 0 {
+		return
+	}
+
+	bin, ok := state.Bins[binID]
+	if !ok {
+		return
+	}
+
+	delete(state.BinPositions[fromTick], kind)
+	removeTypeAtTick(state.BinMap, kind, fromTick)
+
+	targetID := state.BinPositions[toTick][kind]
+	if targetID == 0 {
+		bin.LowerTick = big.NewInt(int64(toTick))
+		state.Bins[binID] = bin
+
+		if state.BinPositions[toTick] == nil {
+			state.BinPositions[toTick] = make(map[uint8]uint32)
+		}
+		state.BinPositions[toTick][kind] = binID
+		putTypeAtTick(state.BinMap, kind, toTick)
+
+		return
+	}
+
+	target := state.Bins[targetID]
+	target.ReserveA = new(big.Int).Add(target.ReserveA, bin.ReserveA)
+	target.ReserveB = new(big.Int).Add(target.ReserveB, bin.ReserveB)
+	state.Bins[targetID] = target
+
+	// a merged bin holds no reserves of its own, the pool state does not keep it
+	delete(state.Bins, binID)
+}
+
+// twaTick is the tick of a twa in 8 decimals, rounding half a tick down as the pool does
+func twaTick(twaD8 *big.Int) int32 {
+	tick := new(big.Int).Sub(twaD8, HalfTickD8)
+
+	// Div is the euclidean division, which floors for a positive divisor
+	return int32(tick.Div(tick, TickD8).Int64())
+}
+
+func minInt32(a int32, values ...int32) int32 {
+	for _, v := range values {
+		if v < a {
+			a = v
+		}
+	}
+	return a
+}
+
+func maxInt32(a int32, values ...int32) int32 {
+	for _, v := range values {
+		if v > a {
+			a = v
+		}
+	}
+	return a
+}
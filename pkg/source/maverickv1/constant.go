@@ -17,7 +17,17 @@ const (
 	poolMethodTokenAScale = "tokenAScale"
 	poolMethodTokenBScale = "tokenBScale"
 
-	poolMethodGetBin = "getBin"
+	poolMethodGetBin        = "getBin"
+	poolMethodGetTwa        = "getTwa"
+	poolMethodGetCurrentTwa = "getCurrentTwa"
+)
+
+// Kinds of bin, a bin of a movement kind follows the twa of the pool after a swap
+const (
+	KindStatic uint8 = iota
+	KindRight
+	KindLeft
+	KindBoth
 )
 
 var (
@@ -37,4 +47,6 @@ var (
 	WordSize                    = big.NewInt(256)
 	One                         = bignumber.TenPowInt(18)
 	Unit                        = bignumber.TenPowInt(18)
+	TickD8                      = bignumber.TenPowInt(8)
+	HalfTickD8                  = big.NewInt(5e7)
 )
@@ -429,17 +429,10 @@ func currentTickLiquidity(activeTick *big.Int, state *MaverickPoolState) (*big.I
 	var bins = make([]Bin, 0)
 
 	for i := 0; i < 4; i++ {
-		bigI := big.NewInt(int64(i))
 		if new(big.Int).And(active.Word, new(big.Int).Lsh(big.NewInt(1), uint(i))).Cmp(zeroBI) > 0 {
-			if state.BinPositions[activeTick.String()] == nil {
-				state.BinPositions[activeTick.String()] = make(map[string]*big.Int)
-			}
-			var binID = state.BinPositions[activeTick.String()][bigI.String()]
-			if binID == nil {
-				binID = big.NewInt(0)
-			}
-			if binID.Cmp(zeroBI) > 0 {
-				var bin = state.Bins[binID.String()]
+			var binID = state.BinPositions[int32(activeTick.Int64())][uint8(i)]
+			if binID > 0 {
+				var bin = state.Bins[binID]
 				reserveA = new(big.Int).Add(reserveA, bin.ReserveA)
 				reserveB = new(big.Int).Add(reserveB, bin.ReserveB)
 				bins = append(bins, bin)
@@ -633,17 +626,10 @@ func adjustAB(bin *Bin, delta *Delta, thisBinAmount, totalAmount, activeTick *bi
 
 	// Custom code to update state.Bin
 	var active = getKindsAtTick(state.BinMap, activeTick)
-	bigI := big.NewInt(bin.Kind.Int64())
 	if new(big.Int).And(active.Word, new(big.Int).Lsh(big.NewInt(1), uint(bin.Kind.Int64()))).Cmp(zeroBI) > 0 {
-		if state.BinPositions[activeTick.String()] == nil {
-			state.BinPositions[activeTick.String()] = make(map[string]*big.Int)
-		}
-		var binID = state.BinPositions[activeTick.String()][bigI.String()]
-		if binID == nil {
-			binID = big.NewInt(0)
-		}
-		if binID.Cmp(zeroBI) > 0 {
-			state.Bins[binID.String()] = *bin
+		var binID = state.BinPositions[int32(activeTick.Int64())][uint8(bin.Kind.Int64())]
+		if binID > 0 {
+			state.Bins[binID] = *bin
 		}
 	}
 
@@ -753,7 +739,7 @@ func getTickL(
 
 // ------------- maverick bin map -----------------------
 
-func nextActive(binMap map[string]*big.Int, tick *big.Int, isRight bool) *big.Int {
+func nextActive(binMap map[int32]*big.Int, tick *big.Int, isRight bool) *big.Int {
 	var refTick, shift, tack, nextWord, subIndex, nextTick *big.Int
 
 	refTick = new(big.Int).Set(tick)
@@ -772,7 +758,7 @@ func nextActive(binMap map[string]*big.Int, tick *big.Int, isRight bool) *big.In
 	}
 
 	for i := 0; i < 4000; i++ {
-		nextWord = binMap[mapIndex.String()]
+		nextWord = binMap[int32(mapIndex.Int64())]
 		if nextWord == nil {
 			nextWord = big.NewInt(0)
 		}
@@ -809,9 +795,9 @@ func nextActive(binMap map[string]*big.Int, tick *big.Int, isRight bool) *big.In
 	return nextTick
 }
 
-func getKindsAtTick(binMap map[string]*big.Int, tick *big.Int) Active {
+func getKindsAtTick(binMap map[int32]*big.Int, tick *big.Int) Active {
 	offset, mapIndex := getMapPointer(new(big.Int).Mul(tick, Kinds))
-	subMap := binMap[mapIndex.String()]
+	subMap := binMap[int32(mapIndex.Int64())]
 	if subMap == nil {
 		subMap = big.NewInt(0)
 	}
@@ -830,6 +816,26 @@ func getMapPointer(tick *big.Int) (*big.Int, *big.Int) {
 	return offset, mapIndex
 }
 
+func putTypeAtTick(binMap map[int32]*big.Int, kind uint8, tick int32) {
+	offset, mapIndex := getMapPointer(big.NewInt(int64(tick)*Kinds.Int64() + int64(kind)))
+	subMap := binMap[int32(mapIndex.Int64())]
+	if subMap == nil {
+		subMap = big.NewInt(0)
+	}
+
+	binMap[int32(mapIndex.Int64())] = new(big.Int).Or(subMap, new(big.Int).Lsh(big.NewInt(1), uint(offset.Int64())))
+}
+
+func removeTypeAtTick(binMap map[int32]*big.Int, kind uint8, tick int32) {
+	offset, mapIndex := getMapPointer(big.NewInt(int64(tick)*Kinds.Int64() + int64(kind)))
+	subMap := binMap[int32(mapIndex.Int64())]
+	if subMap == nil {
+		return
+	}
+
+	binMap[int32(mapIndex.Int64())] = new(big.Int).AndNot(subMap, new(big.Int).Lsh(big.NewInt(1), uint(offset.Int64())))
+}
+
 func lsb(x *big.Int) *big.Int {
 	r := big.NewInt(255)
 	// bigint in typescript is pass by value. So I do not want this function change the input X
@@ -58,6 +58,8 @@ func NewPoolSimulator(entityPool entity.Pool) (*Pool, error) {
 			Bins:             extra.Bins,
 			BinPositions:     extra.BinPositions,
 			BinMap:           extra.BinMap,
+			LastTwaD8:        extra.LastTwaD8,
+			TwaD8:            extra.TwaD8,
 		},
 		gas: DefaultGas,
 	}, nil
@@ -142,8 +144,12 @@ func (p *Pool) UpdateBalance(params pool.UpdateBalanceParams) {
 		return
 	}
 
+	startingTick := int32(p.state.ActiveTick.Int64())
+
 	p.state.Bins = newState.bins
 	p.state.ActiveTick = newState.activeTick
+
+	moveBins(p.state, startingTick, int32(p.state.ActiveTick.Int64()))
 }
 
 func (p *Pool) GetMetaInfo(tokenIn string, tokenOut string) interface{} {
@@ -159,8 +165,15 @@ func (p *Pool) deepcopyState(state *MaverickPoolState) (*MaverickPoolState, erro
 		BinCounter:       new(big.Int).Set(state.BinCounter),
 	}
 
+	if state.LastTwaD8 != nil {
+		newState.LastTwaD8 = new(big.Int).Set(state.LastTwaD8)
+	}
+	if state.TwaD8 != nil {
+		newState.TwaD8 = new(big.Int).Set(state.TwaD8)
+	}
+
 	// Clone state.Bins
-	newState.Bins = make(map[string]Bin, len(state.Bins))
+	newState.Bins = make(map[uint32]Bin, len(state.Bins))
 	for k, v := range state.Bins {
 		newState.Bins[k] = Bin{
 			ReserveA:  new(big.Int).Set(v.ReserveA),
@@ -172,16 +185,16 @@ func (p *Pool) deepcopyState(state *MaverickPoolState) (*MaverickPoolState, erro
 	}
 
 	// Clone state.BinPositions
-	newState.BinPositions = make(map[string]map[string]*big.Int, len(state.BinPositions))
+	newState.BinPositions = make(map[int32]map[uint8]uint32, len(state.BinPositions))
 	for k, v := range state.BinPositions {
-		newState.BinPositions[k] = make(map[string]*big.Int, len(v))
+		newState.BinPositions[k] = make(map[uint8]uint32, len(v))
 		for k1, v1 := range v {
-			newState.BinPositions[k][k1] = new(big.Int).Set(v1)
+			newState.BinPositions[k][k1] = v1
 		}
 	}
 
 	// Clone state.BinMap
-	newState.BinMap = make(map[string]*big.Int, len(state.BinMap))
+	newState.BinMap = make(map[int32]*big.Int, len(state.BinMap))
 	for k, v := range state.BinMap {
 		newState.BinMap[k] = new(big.Int).Set(v)
 	}
@@ -1,12 +1,15 @@
 package maverickv1_test
 
 import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/maverickv1"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 	"github.com/stretchr/testify/assert"
-	"testing"
 )
 
 func TestPoolCalcAmountOut(t *testing.T) {
@@ -307,3 +310,83 @@ func TestPoolCalcAmountOut(t *testing.T) {
 	//	"5": bignumber.NewBig10("7721018714868875516017241010155757617493946277325927722722110067420054945792"),
 	//}
 }
+
+func TestPoolUpdateBalance_MoveBins(t *testing.T) {
+	const (
+		tokenA = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+		tokenB = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+	)
+
+	// a static bin at the active tick 0 and a bin of kind right at tick -1, the twa moves from tick -1 to tick 0
+	newPool := func(lastTwaD8, twaD8 *big.Int) *maverickv1.Pool {
+		extra := maverickv1.Extra{
+			Fee:              big.NewInt(400000000000000),
+			ProtocolFeeRatio: big.NewInt(0),
+			ActiveTick:       big.NewInt(0),
+			BinCounter:       big.NewInt(2),
+			Bins: map[uint32]maverickv1.Bin{
+				1: {
+					ReserveA:  bignumber.NewBig10("100000000000000000000"),
+					ReserveB:  bignumber.NewBig10("100000000000000000000"),
+					LowerTick: big.NewInt(0),
+					Kind:      big.NewInt(0),
+					MergeID:   big.NewInt(0),
+				},
+				2: {
+					ReserveA:  bignumber.NewBig10("50000000000000000000"),
+					ReserveB:  big.NewInt(0),
+					LowerTick: big.NewInt(-1),
+					Kind:      big.NewInt(1),
+					MergeID:   big.NewInt(0),
+				},
+			},
+			BinPositions: map[int32]map[uint8]uint32{
+				0:  {0: 1},
+				-1: {1: 2},
+			},
+			BinMap: map[int32]*big.Int{
+				0:  big.NewInt(1),
+				-1: new(big.Int).Lsh(big.NewInt(1), 253),
+			},
+			LastTwaD8: lastTwaD8,
+			TwaD8:     twaD8,
+		}
+		extraBytes, err := json.Marshal(extra)
+		assert.Nil(t, err)
+
+		p, err := maverickv1.NewPoolSimulator(entity.Pool{
+			Tokens: []*entity.PoolToken{
+				{Address: tokenA, Decimals: 18},
+				{Address: tokenB, Decimals: 18},
+			},
+			Extra:       string(extraBytes),
+			StaticExtra: "{\"tickSpacing\":198}",
+		})
+		assert.Nil(t, err)
+
+		return p
+	}
+
+	swap := func(p *maverickv1.Pool, amountIn string) *big.Int {
+		tokenAmountIn := pool.TokenAmount{Token: tokenB, Amount: bignumber.NewBig10(amountIn)}
+		result, err := p.CalcAmountOut(tokenAmountIn, tokenA)
+		assert.Nil(t, err)
+
+		p.UpdateBalance(pool.UpdateBalanceParams{
+			TokenAmountIn:  tokenAmountIn,
+			TokenAmountOut: *result.TokenAmountOut,
+			SwapInfo:       result.SwapInfo,
+		})
+
+		return result.TokenAmountOut.Amount
+	}
+
+	moving := newPool(big.NewInt(-5e7), big.NewInt(5e7))
+	static := newPool(nil, nil)
+
+	// bins only move after a swap
+	assert.Equal(t, swap(static, "1000000000000000"), swap(moving, "1000000000000000"))
+
+	// the right bin has moved to the active tick and deepened its liquidity
+	assert.Equal(t, 1, swap(moving, "10000000000000000000").Cmp(swap(static, "10000000000000000000")))
+}
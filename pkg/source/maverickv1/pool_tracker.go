@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"math/big"
-	"strconv"
 	"time"
 
 	"github.com/KyberNetwork/ethrpc"
@@ -40,6 +39,8 @@ func (d *PoolTracker) GetNewPoolState(
 	var (
 		fee, binBalanceA, binBalanceB, tokenAScale, tokenBScale *big.Int
 		getStateResult                                          GetStateResult
+		getTwaResult                                            GetTwaResult
+		currentTwa                                              *big.Int
 	)
 
 	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
@@ -58,6 +59,20 @@ func (d *PoolTracker) GetNewPoolState(
 		Params: nil,
 	}, []interface{}{&getStateResult})
 
+	calls.AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: p.Address,
+		Method: poolMethodGetTwa,
+		Params: nil,
+	}, []interface{}{&getTwaResult})
+
+	calls.AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: p.Address,
+		Method: poolMethodGetCurrentTwa,
+		Params: nil,
+	}, []interface{}{&currentTwa})
+
 	calls.AddCall(&ethrpc.Call{
 		ABI:    poolABI,
 		Target: p.Address,
@@ -120,16 +135,16 @@ func (d *PoolTracker) GetNewPoolState(
 	}
 
 	// Generate bins, binPosition, binMap from binRaws
-	bins := make(map[string]Bin)
-	binPositions := make(map[string]map[string]*big.Int)
-	binMap := make(map[string]*big.Int)
+	bins := make(map[uint32]Bin)
+	binPositions := make(map[int32]map[uint8]uint32)
+	binMap := make(map[int32]*big.Int)
 	for i, binRaw := range binRaws {
 		if binRaw.BinState.MergeID.Cmp(zeroBI) != 0 ||
 			(binRaw.BinState.ReserveA.Cmp(zeroBI) == 0 && binRaw.BinState.ReserveB.Cmp(zeroBI) == 0) {
 			continue
 		}
 
-		strI := strconv.Itoa(i)
+		binID := uint32(i)
 		bin := Bin{
 			ReserveA:  new(big.Int).Set(binRaw.BinState.ReserveA),
 			ReserveB:  new(big.Int).Set(binRaw.BinState.ReserveB),
@@ -137,14 +152,14 @@ func (d *PoolTracker) GetNewPoolState(
 			Kind:      big.NewInt(int64(binRaw.BinState.Kind)),
 			MergeID:   new(big.Int).Set(binRaw.BinState.MergeID),
 		}
-		bins[strI] = bin
+		bins[binID] = bin
 
 		if bin.MergeID.Int64() == 0 {
-			d.putTypeAtTick(binMap, bin.Kind, bin.LowerTick)
-			if binPositions[bin.LowerTick.String()] == nil {
-				binPositions[bin.LowerTick.String()] = make(map[string]*big.Int)
+			putTypeAtTick(binMap, binRaw.BinState.Kind, binRaw.BinState.LowerTick)
+			if binPositions[binRaw.BinState.LowerTick] == nil {
+				binPositions[binRaw.BinState.LowerTick] = make(map[uint8]uint32)
 			}
-			binPositions[bin.LowerTick.String()][bin.Kind.String()] = big.NewInt(int64(i))
+			binPositions[binRaw.BinState.LowerTick][binRaw.BinState.Kind] = binID
 		}
 	}
 
@@ -176,6 +191,8 @@ func (d *PoolTracker) GetNewPoolState(
 		Bins:             bins,
 		BinPositions:     binPositions,
 		BinMap:           binMap,
+		LastTwaD8:        getTwaResult.TwaState.Twa,
+		TwaD8:            currentTwa,
 
 		SqrtPriceX96: sqrtPrice,
 		Liquidity:    liquidity,
@@ -200,31 +217,3 @@ func (d *PoolTracker) GetNewPoolState(
 
 	return p, nil
 }
-
-func (d *PoolTracker) putTypeAtTick(
-	binMap map[string]*big.Int,
-	kind, tick *big.Int,
-) {
-	offset, mapIndex := d.getMapPointer(
-		new(big.Int).Add(
-			new(big.Int).Mul(tick, Kinds),
-			kind,
-		))
-	subMap := binMap[mapIndex.String()]
-	if subMap == nil {
-		subMap = big.NewInt(0)
-	}
-
-	value := new(big.Int).Or(
-		subMap,
-		new(big.Int).Lsh(big.NewInt(1), uint(offset.Int64())))
-
-	binMap[mapIndex.String()] = value
-}
-
-func (d *PoolTracker) getMapPointer(tick *big.Int) (*big.Int, *big.Int) {
-	offset := new(big.Int).And(tick, OffsetMask)
-	mapIndex := new(big.Int).Rsh(tick, 8)
-
-	return offset, mapIndex
-}
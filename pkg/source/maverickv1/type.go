@@ -25,13 +25,18 @@ type StaticExtra struct {
 }
 
 type Extra struct {
-	Fee              *big.Int                       `json:"fee"`
-	ProtocolFeeRatio *big.Int                       `json:"protocolFeeRatio"`
-	ActiveTick       *big.Int                       `json:"activeTick"`
-	BinCounter       *big.Int                       `json:"binCounter"`
-	Bins             map[string]Bin                 `json:"bins"`
-	BinPositions     map[string]map[string]*big.Int `json:"binPositions"`
-	BinMap           map[string]*big.Int            `json:"binMap"`
+	Fee              *big.Int                   `json:"fee"`
+	ProtocolFeeRatio *big.Int                   `json:"protocolFeeRatio"`
+	ActiveTick       *big.Int                   `json:"activeTick"`
+	BinCounter       *big.Int                   `json:"binCounter"`
+	Bins             map[uint32]Bin             `json:"bins"`
+	BinPositions     map[int32]map[uint8]uint32 `json:"binPositions"`
+	BinMap           map[int32]*big.Int         `json:"binMap"`
+
+	// State to move bins after a swap, the twa stored at the last swap and the twa at the time the state is fetched,
+	// both in 8 decimals of a tick
+	LastTwaD8 *big.Int `json:"lastTwaD8,omitempty"`
+	TwaD8     *big.Int `json:"twaD8,omitempty"`
 
 	// State to calculate TVL
 	Liquidity    *big.Int `json:"liquidity"`
@@ -39,19 +44,21 @@ type Extra struct {
 }
 
 type MaverickPoolState struct {
-	TickSpacing      *big.Int                       `json:"tickSpacing"`
-	Fee              *big.Int                       `json:"fee"`
-	ProtocolFeeRatio *big.Int                       `json:"protocolFeeRatio"`
-	ActiveTick       *big.Int                       `json:"activeTick"`
-	BinCounter       *big.Int                       `json:"binCounter"`
-	Bins             map[string]Bin                 `json:"bins"`
-	BinPositions     map[string]map[string]*big.Int `json:"binPositions"`
-	BinMap           map[string]*big.Int            `json:"binMap"`
+	TickSpacing      *big.Int                   `json:"tickSpacing"`
+	Fee              *big.Int                   `json:"fee"`
+	ProtocolFeeRatio *big.Int                   `json:"protocolFeeRatio"`
+	ActiveTick       *big.Int                   `json:"activeTick"`
+	BinCounter       *big.Int                   `json:"binCounter"`
+	Bins             map[uint32]Bin             `json:"bins"`
+	BinPositions     map[int32]map[uint8]uint32 `json:"binPositions"`
+	BinMap           map[int32]*big.Int         `json:"binMap"`
+	LastTwaD8        *big.Int                   `json:"lastTwaD8"`
+	TwaD8            *big.Int                   `json:"twaD8"`
 }
 
 // maverickSwapInfo present the after state of a swap
 type maverickSwapInfo struct {
-	bins       map[string]Bin
+	bins       map[uint32]Bin
 	activeTick *big.Int
 }
 
@@ -98,6 +105,14 @@ type GetStateResult struct {
 	}
 }
 
+type GetTwaResult struct {
+	TwaState struct {
+		Twa           *big.Int `json:"twa"`
+		Value         *big.Int `json:"value"`
+		LastTimestamp uint64   `json:"lastTimestamp"`
+	}
+}
+
 type GetBinResult struct {
 	BinState struct {
 		ReserveA        *big.Int `json:"reserveA"`
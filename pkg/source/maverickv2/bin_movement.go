@@ -0,0 +1,99 @@
+package maverickv2
+
+import (
+	"math/big"
+	"sort"
+)
+
+// moveBins moves the bins of the movement kinds when the tick of the twa changes, bins of kind right follow the twa
+// when it moves right, bins of kind left when it moves left and bins of kind both in either direction. The bins carry
+// their share of the reserves of their tick, and a bin that moves to a tick which already has a bin of its kind is
+// merged into that bin.
+func (p *PoolSimulator) moveBins(lastTwaTick, newTwaTick, startingTick int32) {
+	if lastTwaTick == newTwaTick {
+		return
+	}
+
+	moveRight := newTwaTick > lastTwaTick
+	for kind := KindRight; kind <= KindBoth; kind++ {
+		if (moveRight && kind == KindLeft) || (!moveRight && kind == KindRight) {
+			continue
+		}
+
+		// the pool searches the ticks between the last twa and the ticks the swap went through
+		var fromTick, toTick int32
+		if moveRight {
+			fromTick, toTick = min(lastTwaTick, startingTick, p.activeTick), newTwaTick-1
+		} else {
+			fromTick, toTick = newTwaTick+1, max(lastTwaTick, startingTick, p.activeTick)
+		}
+
+		// only the ticks of the pool can hold bins, moving a bin may insert its new tick so they are copied first
+		lo := sort.Search(len(p.sortedTicks), func(i int) bool { return p.sortedTicks[i] >= fromTick })
+		hi := sort.Search(len(p.sortedTicks), func(i int) bool { return p.sortedTicks[i] > toTick })
+		if lo >= hi {
+			continue
+		}
+		for _, tick := range append([]int32(nil), p.sortedTicks[lo:hi]...) {
+			if binID := p.ticks[tick].BinIDs[kind]; binID != 0 {
+				p.moveBin(binID, kind, tick, newTwaTick)
+			}
+		}
+	}
+}
+
+func (p *PoolSimulator) moveBin(binID uint32, kind uint8, fromTick, toTick int32) {
+	bin, ok := p.bins[binID]
+	from := p.ticks[fromTick]
+	if !ok || from.TotalSupply == nil || from.TotalSupply.Sign() == 0 {
+		return
+	}
+
+	amountA := mulDiv(from.ReserveA, bin.TickBalance, from.TotalSupply, false)
+	amountB := mulDiv(from.ReserveB, bin.TickBalance, from.TotalSupply, false)
+
+	from.ReserveA = clip(from.ReserveA, amountA)
+	from.ReserveB = clip(from.ReserveB, amountB)
+	from.TotalSupply = clip(from.TotalSupply, bin.TickBalance)
+	from.BinIDs[kind] = 0
+	p.ticks[fromTick] = from
+
+	to, ok := p.ticks[toTick]
+	if !ok {
+		to = Tick{ReserveA: new(big.Int), ReserveB: new(big.Int), TotalSupply: new(big.Int)}
+		p.insertSortedTick(toTick)
+	}
+
+	// the new tick balance keeps the share of the bin in the value of the tick it moves to
+	tickBalance := new(big.Int).Add(amountA, amountB)
+	if reserves := new(big.Int).Add(to.ReserveA, to.ReserveB); to.TotalSupply.Sign() > 0 && reserves.Sign() > 0 {
+		tickBalance = mulDiv(to.TotalSupply, tickBalance, reserves, false)
+	}
+
+	to.ReserveA = new(big.Int).Add(to.ReserveA, amountA)
+	to.ReserveB = new(big.Int).Add(to.ReserveB, amountB)
+	to.TotalSupply = new(big.Int).Add(to.TotalSupply, tickBalance)
+
+	if targetID := to.BinIDs[kind]; targetID != 0 {
+		target := p.bins[targetID]
+		target.TickBalance = new(big.Int).Add(target.TickBalance, tickBalance)
+		p.bins[targetID] = target
+		delete(p.bins, binID)
+	} else {
+		to.BinIDs[kind] = binID
+		p.bins[binID] = Bin{Kind: kind, Tick: toTick, TickBalance: tickBalance}
+	}
+
+	p.ticks[toTick] = to
+}
+
+func (p *PoolSimulator) insertSortedTick(tick int32) {
+	i := sort.Search(len(p.sortedTicks), func(i int) bool { return p.sortedTicks[i] >= tick })
+	if i < len(p.sortedTicks) && p.sortedTicks[i] == tick {
+		return
+	}
+
+	p.sortedTicks = append(p.sortedTicks, 0)
+	copy(p.sortedTicks[i+1:], p.sortedTicks[i:])
+	p.sortedTicks[i] = tick
+}
@@ -0,0 +1,7 @@
+package maverickv2
+
+type Config struct {
+	DexID          string `json:"dexID"`
+	FactoryAddress string `json:"factoryAddress"`
+	NewPoolLimit   int    `json:"newPoolLimit"`
+}
@@ -0,0 +1,50 @@
+package maverickv2
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	DexTypeMaverickV2 = "maverick-v2"
+
+	factoryMethodLookup = "lookup"
+
+	poolMethodFee         = "fee"
+	poolMethodGetState    = "getState"
+	poolMethodGetTick     = "getTick"
+	poolMethodGetBin      = "getBin"
+	poolMethodLookback    = "lookback"
+	poolMethodTickSpacing = "tickSpacing"
+	poolMethodTokenA      = "tokenA"
+	poolMethodTokenB      = "tokenB"
+)
+
+// Kinds of bin, a bin of a movement kind follows the twa of the pool after a swap
+const (
+	KindStatic uint8 = iota
+	KindRight
+	KindLeft
+	KindBoth
+
+	numberOfKinds = 4
+)
+
+const (
+	MaxTick = 322378
+
+	// maxSwapIterations bounds the ticks with liquidity a single swap can go through
+	maxSwapIterations = 100
+
+	defaultTokenWeight uint = 50
+)
+
+var (
+	DefaultGas = Gas{Swap: 125000}
+
+	One        = bignumber.TenPowInt(18)
+	TickD8     = bignumber.TenPowInt(8)
+	HalfTickD8 = big.NewInt(5e7)
+	FeeD3      = big.NewInt(1000)
+)
@@ -0,0 +1,9 @@
+package maverickv2
+
+import _ "embed"
+
+//go:embed abis/Pool.json
+var poolABIJson []byte
+
+//go:embed abis/Factory.json
+var factoryABIJson []byte
@@ -0,0 +1,10 @@
+package maverickv2
+
+import "errors"
+
+var (
+	ErrLargerThanMaxTick     = errors.New("tick is larger than max tick")
+	ErrInvalidToken          = errors.New("invalid token")
+	ErrInsufficientLiquidity = errors.New("insufficient liquidity")
+	ErrMaxSwapIterations     = errors.New("swap crosses too many ticks")
+)
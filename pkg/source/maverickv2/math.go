@@ -0,0 +1,278 @@
+package maverickv2
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// tickSqrtPriceMultipliers are the Q128 square roots of 1.0001^-(2^i), the same as in v1
+var tickSqrtPriceMultipliers = []*big.Int{
+	bignumber.NewBig("0xfff97272373d41fd789c8cb37ffcaa1c"),
+	bignumber.NewBig("0xfff2e50f5f656ac9229c67059486f389"),
+	bignumber.NewBig("0xffe5caca7e10e81259b3cddc7a064941"),
+	bignumber.NewBig("0xffcb9843d60f67b19e8887e0bd251eb7"),
+	bignumber.NewBig("0xff973b41fa98cd2e57b660be99eb2c4a"),
+	bignumber.NewBig("0xff2ea16466c9838804e327cb417cafcb"),
+	bignumber.NewBig("0xfe5dee046a99d51e2cc356c2f617dbe0"),
+	bignumber.NewBig("0xfcbe86c7900aecf64236ab31f1f9dcb5"),
+	bignumber.NewBig("0xf987a7253ac4d9194200696907cf2e37"),
+	bignumber.NewBig("0xf3392b0822b88206f8abe8a3b44dd9be"),
+	bignumber.NewBig("0xe7159475a2c578ef4f1d17b2b235d480"),
+	bignumber.NewBig("0xd097f3bdfd254ee83bdd3f248e7e785e"),
+	bignumber.NewBig("0xa9f746462d8f7dd10e744d913d033333"),
+	bignumber.NewBig("0x70d869a156ddd32a39e257bc3f50aa9b"),
+	bignumber.NewBig("0x31be135f97da6e09a19dc367e3b6da40"),
+	bignumber.NewBig("0x9aa508b5b7e5a9780b0cc4e25d61a56"),
+	bignumber.NewBig("0x5d6af8dedbcb3a6ccb7ce618d14225"),
+	bignumber.NewBig("0x2216e584f630389b2052b8db590e"),
+}
+
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(bignumber.One, 256), bignumber.One)
+
+// tickSqrtPrice is TickMath.tickSqrtPrice, the square root of the lower price of a tick in 18 decimals
+func tickSqrtPrice(tickSpacing *big.Int, tick int32) (*big.Int, error) {
+	absTick := new(big.Int).Abs(big.NewInt(int64(tick)))
+	absTick.Mul(absTick, tickSpacing)
+	if absTick.Cmp(big.NewInt(MaxTick)) > 0 {
+		return nil, ErrLargerThanMaxTick
+	}
+
+	t := absTick.Uint64()
+
+	ratio := bignumber.NewBig("0x100000000000000000000000000000000")
+	if t&1 != 0 {
+		ratio = bignumber.NewBig("0xfffcb933bd6fad9d3af5f0b9f25db4d6")
+	}
+	for i, multiplier := range tickSqrtPriceMultipliers {
+		if t&(2<<i) != 0 {
+			ratio.Mul(ratio, multiplier)
+			ratio.Rsh(ratio, 128)
+		}
+	}
+
+	if tick > 0 {
+		ratio.Div(maxUint256, ratio)
+	}
+
+	ratio.Mul(ratio, One)
+	return ratio.Rsh(ratio, 128), nil
+}
+
+// tickSqrtPrices returns the square roots of the lower and upper prices of a tick
+func tickSqrtPrices(tickSpacing *big.Int, tick int32) (*big.Int, *big.Int, error) {
+	sqrtLowerTickPrice, err := tickSqrtPrice(tickSpacing, tick)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sqrtUpperTickPrice, err := tickSqrtPrice(tickSpacing, tick+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sqrtLowerTickPrice, sqrtUpperTickPrice, nil
+}
+
+// getTickL is TickMath.getTickL, the liquidity of a tick from its reserves
+func getTickL(reserveA, reserveB, sqrtLowerTickPrice, sqrtUpperTickPrice *big.Int) *big.Int {
+	var precisionBump uint
+	if new(big.Int).Rsh(reserveA, 60).Sign() == 0 && new(big.Int).Rsh(reserveB, 60).Sign() == 0 {
+		precisionBump = 40
+		reserveA = new(big.Int).Lsh(reserveA, precisionBump)
+		reserveB = new(big.Int).Lsh(reserveB, precisionBump)
+	}
+
+	diff := new(big.Int).Sub(sqrtUpperTickPrice, sqrtLowerTickPrice)
+	b := new(big.Int).Add(divDown(reserveA, sqrtUpperTickPrice), mulDown(reserveB, sqrtLowerTickPrice))
+
+	var liquidity *big.Int
+	if reserveA.Sign() == 0 || reserveB.Sign() == 0 {
+		liquidity = mulDiv(b, sqrtUpperTickPrice, diff, false)
+	} else {
+		b.Rsh(b, 1)
+		c := mulDiv(new(big.Int).Mul(reserveA, reserveB), diff, sqrtUpperTickPrice, false)
+		liquidity = mulDiv(
+			new(big.Int).Add(b, new(big.Int).Sqrt(new(big.Int).Add(new(big.Int).Mul(b, b), c))),
+			sqrtUpperTickPrice,
+			diff,
+			false,
+		)
+	}
+
+	return liquidity.Rsh(liquidity, precisionBump)
+}
+
+// getSqrtPrice is TickMath.getSqrtPrice, the square root of the price of a tick from its reserves and liquidity
+func getSqrtPrice(reserveA, reserveB, sqrtLowerTickPrice, sqrtUpperTickPrice, liquidity *big.Int) *big.Int {
+	if reserveA.Sign() == 0 {
+		return sqrtLowerTickPrice
+	}
+	if reserveB.Sign() == 0 {
+		return sqrtUpperTickPrice
+	}
+
+	numerator := new(big.Int).Add(reserveA, mulDown(liquidity, sqrtLowerTickPrice))
+	denominator := new(big.Int).Add(reserveB, divDown(liquidity, sqrtUpperTickPrice))
+	sqrtPrice := new(big.Int).Sqrt(new(big.Int).Div(new(big.Int).Mul(numerator, new(big.Int).Mul(One, One)), denominator))
+
+	if sqrtPrice.Cmp(sqrtLowerTickPrice) < 0 {
+		return sqrtLowerTickPrice
+	}
+	if sqrtPrice.Cmp(sqrtUpperTickPrice) > 0 {
+		return sqrtUpperTickPrice
+	}
+
+	return sqrtPrice
+}
+
+// computeSwapExactIn swaps amountIn in a tick. When the tick can not take all of it, the tick is swapped to its edge
+// and the rest of the amount is returned as excess.
+func computeSwapExactIn(
+	sqrtLowerTickPrice, sqrtUpperTickPrice, sqrtPrice, liquidity, reserveA, reserveB, amountIn, fee *big.Int,
+	protocolFeeRatioD3 uint8,
+	tokenAIn bool,
+) *Delta {
+	delta := &Delta{
+		DeltaInBinInternal: new(big.Int),
+		DeltaInErc:         new(big.Int),
+		DeltaOutErc:        new(big.Int),
+		Excess:             new(big.Int),
+		EndSqrtPrice:       sqrtPrice,
+	}
+
+	var binAmountIn *big.Int
+	if tokenAIn {
+		binAmountIn = mulUp(liquidity, new(big.Int).Sub(sqrtUpperTickPrice, sqrtPrice))
+	} else {
+		binAmountIn = new(big.Int).Sub(divUp(liquidity, sqrtLowerTickPrice), divDown(liquidity, sqrtPrice))
+	}
+
+	oneMinusFee := new(big.Int).Sub(One, fee)
+
+	var feeBasis *big.Int
+	if mulDown(amountIn, oneMinusFee).Cmp(binAmountIn) >= 0 {
+		feeBasis = mulDiv(binAmountIn, fee, oneMinusFee, true)
+		delta.DeltaInErc = new(big.Int).Add(binAmountIn, feeBasis)
+		delta.Excess = clip(amountIn, delta.DeltaInErc)
+		if tokenAIn {
+			delta.EndSqrtPrice = sqrtUpperTickPrice
+			delta.DeltaOutErc = new(big.Int).Set(reserveB)
+		} else {
+			delta.EndSqrtPrice = sqrtLowerTickPrice
+			delta.DeltaOutErc = new(big.Int).Set(reserveA)
+		}
+	} else {
+		binAmountIn = mulDown(amountIn, oneMinusFee)
+		delta.DeltaInErc = new(big.Int).Set(amountIn)
+		feeBasis = new(big.Int).Sub(delta.DeltaInErc, binAmountIn)
+	}
+
+	delta.DeltaInBinInternal = amountToBin(delta.DeltaInErc, feeBasis, protocolFeeRatioD3)
+
+	if delta.Excess.Sign() != 0 || liquidity.Sign() == 0 {
+		return delta
+	}
+
+	if tokenAIn {
+		delta.EndSqrtPrice = new(big.Int).Add(sqrtPrice, divDown(binAmountIn, liquidity))
+		delta.DeltaOutErc = minBI(reserveB, mulDiv(binAmountIn, divDown(One, sqrtPrice), delta.EndSqrtPrice, false))
+	} else {
+		endInvSqrtPrice := new(big.Int).Add(divDown(One, sqrtPrice), divDown(binAmountIn, liquidity))
+		delta.EndSqrtPrice = divDown(One, endInvSqrtPrice)
+		delta.DeltaOutErc = minBI(reserveA, mulDiv(binAmountIn, sqrtPrice, endInvSqrtPrice, false))
+	}
+
+	return delta
+}
+
+// amountToBin takes the protocol share of the fee out of the amount that goes to the tick
+func amountToBin(deltaInErc, feeBasis *big.Int, protocolFeeRatioD3 uint8) *big.Int {
+	if protocolFeeRatioD3 == 0 {
+		return deltaInErc
+	}
+
+	protocolFee := mulDiv(feeBasis, big.NewInt(int64(protocolFeeRatioD3)), FeeD3, true)
+	return clip(deltaInErc, protocolFee)
+}
+
+// logPriceD8 is the position of a price in 8 decimals of a tick, as stored in the twa of the pool
+func logPriceD8(tick int32, sqrtPrice, sqrtLowerTickPrice, sqrtUpperTickPrice *big.Int) int64 {
+	position := new(big.Int).Sub(sqrtPrice, sqrtLowerTickPrice)
+	position.Mul(position, TickD8)
+	position.Div(position, new(big.Int).Sub(sqrtUpperTickPrice, sqrtLowerTickPrice))
+
+	return int64(tick)*TickD8.Int64() + position.Int64()
+}
+
+// twaTick is the tick of a twa in 8 decimals, rounding half a tick down as the pool does
+func twaTick(twaD8 int64) int32 {
+	tick := new(big.Int).Sub(big.NewInt(twaD8), HalfTickD8)
+
+	// Div is the euclidean division, which floors for a positive divisor
+	return int32(tick.Div(tick, TickD8).Int64())
+}
+
+// ------------- fixed point math in 18 decimals --------------------
+
+func mulDiv(a, b, c *big.Int, ceil bool) *big.Int {
+	product := new(big.Int).Mul(a, b)
+	if ceil {
+		return divCeil(product, c)
+	}
+	return product.Div(product, c)
+}
+
+func mulDown(a, b *big.Int) *big.Int {
+	return mulDiv(a, b, One, false)
+}
+
+func mulUp(a, b *big.Int) *big.Int {
+	return mulDiv(a, b, One, true)
+}
+
+func divDown(a, b *big.Int) *big.Int {
+	return mulDiv(a, One, b, false)
+}
+
+func divUp(a, b *big.Int) *big.Int {
+	return mulDiv(a, One, b, true)
+}
+
+func divCeil(a, b *big.Int) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(a, b, new(big.Int))
+	if remainder.Sign() > 0 {
+		quotient.Add(quotient, bignumber.One)
+	}
+	return quotient
+}
+
+func clip(x, y *big.Int) *big.Int {
+	if x.Cmp(y) < 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).Sub(x, y)
+}
+
+func minBI(a, b *big.Int) *big.Int {
+	if a.Cmp(b) < 0 {
+		return a
+	}
+	return b
+}
+
+// scaleFromAmount converts an amount of a token to 18 decimals, rounding up as the pool does for amounts in
+func scaleFromAmount(amount *big.Int, decimals uint8) *big.Int {
+	if decimals > 18 {
+		return divCeil(amount, bignumber.TenPowInt(decimals-18))
+	}
+	return new(big.Int).Mul(amount, bignumber.TenPowInt(18-decimals))
+}
+
+// scaleToAmount converts an amount in 18 decimals to a token amount, rounding down as the pool does for amounts out
+func scaleToAmount(amount *big.Int, decimals uint8) *big.Int {
+	if decimals > 18 {
+		return new(big.Int).Mul(amount, bignumber.TenPowInt(decimals-18))
+	}
+	return new(big.Int).Div(amount, bignumber.TenPowInt(18-decimals))
+}
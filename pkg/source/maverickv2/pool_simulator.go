@@ -0,0 +1,288 @@
+package maverickv2
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/KyberNetwork/logger"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+type PoolSimulator struct {
+	pool.Pool
+	decimals []uint8
+
+	tickSpacing        *big.Int
+	lookback           *big.Int
+	feeAIn             *big.Int
+	feeBIn             *big.Int
+	protocolFeeRatioD3 uint8
+
+	activeTick     int32
+	lastTwaD8      int64
+	lastLogPriceD8 int64
+	lastTimestamp  int64
+	// timestamp is the time the state was tracked at, the swaps are simulated at it
+	timestamp int64
+
+	ticks map[int32]Tick
+	// sortedTicks are the ticks of the pool in ascending order, to find the next tick with liquidity
+	sortedTicks []int32
+	bins        map[uint32]Bin
+
+	gas Gas
+}
+
+func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
+	var extra Extra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	var staticExtra StaticExtra
+	if err := json.Unmarshal([]byte(entityPool.StaticExtra), &staticExtra); err != nil {
+		return nil, err
+	}
+
+	tokens := []string{entityPool.Tokens[0].Address, entityPool.Tokens[1].Address}
+	decimals := []uint8{entityPool.Tokens[0].Decimals, entityPool.Tokens[1].Decimals}
+
+	ticks := extra.Ticks
+	if ticks == nil {
+		ticks = make(map[int32]Tick)
+	}
+	bins := extra.Bins
+	if bins == nil {
+		bins = make(map[uint32]Bin)
+	}
+
+	sortedTicks := make([]int32, 0, len(ticks))
+	for tick := range ticks {
+		sortedTicks = append(sortedTicks, tick)
+	}
+	sort.Slice(sortedTicks, func(i, j int) bool { return sortedTicks[i] < sortedTicks[j] })
+
+	return &PoolSimulator{
+		Pool: pool.Pool{
+			Info: pool.PoolInfo{
+				Address:  entityPool.Address,
+				SwapFee:  extra.FeeAIn,
+				Exchange: entityPool.Exchange,
+				Type:     entityPool.Type,
+				Tokens:   tokens,
+				Checked:  false,
+			},
+		},
+		decimals:           decimals,
+		tickSpacing:        staticExtra.TickSpacing,
+		lookback:           staticExtra.Lookback,
+		feeAIn:             extra.FeeAIn,
+		feeBIn:             extra.FeeBIn,
+		protocolFeeRatioD3: extra.ProtocolFeeRatioD3,
+		activeTick:         extra.ActiveTick,
+		lastTwaD8:          extra.LastTwaD8,
+		lastLogPriceD8:     extra.LastLogPriceD8,
+		lastTimestamp:      extra.LastTimestamp,
+		timestamp:          max(entityPool.Timestamp, extra.LastTimestamp),
+		ticks:              ticks,
+		sortedTicks:        sortedTicks,
+		bins:               bins,
+		gas:                DefaultGas,
+	}, nil
+}
+
+func (p *PoolSimulator) CalcAmountOut(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	tokenInIndex := p.GetTokenIndex(tokenAmountIn.Token)
+	tokenOutIndex := p.GetTokenIndex(tokenOut)
+	if tokenInIndex < 0 || tokenOutIndex < 0 || tokenInIndex == tokenOutIndex {
+		return &pool.CalcAmountOutResult{}, fmt.Errorf("tokenInIndex %v or tokenOutIndex %v is not correct", tokenInIndex, tokenOutIndex)
+	}
+
+	tokenAIn := strings.EqualFold(tokenAmountIn.Token, p.Info.Tokens[0])
+
+	amountIn := scaleFromAmount(tokenAmountIn.Amount, p.decimals[tokenInIndex])
+	amountOut, swapInfo, err := p.swap(amountIn, tokenAIn)
+	if err != nil {
+		return &pool.CalcAmountOutResult{}, err
+	}
+
+	fee := p.feeBIn
+	if tokenAIn {
+		fee = p.feeAIn
+	}
+
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{
+			Token:  tokenOut,
+			Amount: scaleToAmount(amountOut, p.decimals[tokenOutIndex]),
+		},
+		Fee: &pool.TokenAmount{
+			Token:  tokenAmountIn.Token,
+			Amount: mulDiv(tokenAmountIn.Amount, fee, One, true),
+		},
+		Gas:      p.gas.Swap,
+		SwapInfo: swapInfo,
+	}, nil
+}
+
+func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	swapInfo, ok := params.SwapInfo.(maverickSwapInfo)
+	if !ok {
+		logger.Warn("failed to UpdateBalance for Maverick V2 pool, wrong swapInfo type")
+		return
+	}
+
+	startingTick := p.activeTick
+	for tick, tickState := range swapInfo.ticks {
+		p.ticks[tick] = tickState
+	}
+	p.activeTick = swapInfo.activeTick
+
+	p.updateTwa(p.timestamp, startingTick, swapInfo.sqrtPrice)
+}
+
+func (p *PoolSimulator) GetMetaInfo(tokenIn string, _ string) interface{} {
+	return Meta{TokenAIn: strings.EqualFold(tokenIn, p.Info.Tokens[0])}
+}
+
+// swap swaps an amount in 18 decimals through the ticks of the pool, the changed ticks are kept in the swap info and
+// the pool is not modified.
+func (p *PoolSimulator) swap(amountIn *big.Int, tokenAIn bool) (*big.Int, maverickSwapInfo, error) {
+	fee := p.feeBIn
+	if tokenAIn {
+		fee = p.feeAIn
+	}
+
+	swapInfo := maverickSwapInfo{
+		ticks:      make(map[int32]Tick),
+		activeTick: p.activeTick,
+	}
+	getTick := func(tick int32) Tick {
+		if tickState, ok := swapInfo.ticks[tick]; ok {
+			return tickState
+		}
+		return p.ticks[tick]
+	}
+
+	amountOut := new(big.Int)
+	excess := new(big.Int).Set(amountIn)
+	for i := 0; excess.Sign() > 0; i++ {
+		if i >= maxSwapIterations {
+			return nil, maverickSwapInfo{}, ErrMaxSwapIterations
+		}
+
+		tickState := getTick(swapInfo.activeTick)
+		if isEmptyTick(tickState) {
+			nextTick, ok := p.nextTick(swapInfo.activeTick, tokenAIn, getTick)
+			if !ok {
+				return nil, maverickSwapInfo{}, ErrInsufficientLiquidity
+			}
+			swapInfo.activeTick = nextTick
+			continue
+		}
+
+		sqrtLowerTickPrice, sqrtUpperTickPrice, err := tickSqrtPrices(p.tickSpacing, swapInfo.activeTick)
+		if err != nil {
+			return nil, maverickSwapInfo{}, err
+		}
+		liquidity := getTickL(tickState.ReserveA, tickState.ReserveB, sqrtLowerTickPrice, sqrtUpperTickPrice)
+		sqrtPrice := getSqrtPrice(tickState.ReserveA, tickState.ReserveB, sqrtLowerTickPrice, sqrtUpperTickPrice, liquidity)
+
+		delta := computeSwapExactIn(
+			sqrtLowerTickPrice, sqrtUpperTickPrice, sqrtPrice, liquidity,
+			tickState.ReserveA, tickState.ReserveB, excess, fee, p.protocolFeeRatioD3, tokenAIn,
+		)
+
+		newTickState := Tick{
+			ReserveA:    new(big.Int).Set(tickState.ReserveA),
+			ReserveB:    new(big.Int).Set(tickState.ReserveB),
+			TotalSupply: tickState.TotalSupply,
+			BinIDs:      tickState.BinIDs,
+		}
+		if tokenAIn {
+			newTickState.ReserveA.Add(newTickState.ReserveA, delta.DeltaInBinInternal)
+			newTickState.ReserveB = clip(newTickState.ReserveB, delta.DeltaOutErc)
+		} else {
+			newTickState.ReserveB.Add(newTickState.ReserveB, delta.DeltaInBinInternal)
+			newTickState.ReserveA = clip(newTickState.ReserveA, delta.DeltaOutErc)
+		}
+		swapInfo.ticks[swapInfo.activeTick] = newTickState
+		swapInfo.sqrtPrice = delta.EndSqrtPrice
+
+		amountOut.Add(amountOut, delta.DeltaOutErc)
+		excess = delta.Excess
+
+		if excess.Sign() > 0 {
+			if tokenAIn {
+				swapInfo.activeTick++
+			} else {
+				swapInfo.activeTick--
+			}
+		}
+	}
+
+	return amountOut, swapInfo, nil
+}
+
+// nextTick returns the closest tick with reserves on the right of a tick when tokenA is swapped in, or on its left
+func (p *PoolSimulator) nextTick(tick int32, tokenAIn bool, getTick func(int32) Tick) (int32, bool) {
+	if tokenAIn {
+		for i := sort.Search(len(p.sortedTicks), func(i int) bool { return p.sortedTicks[i] > tick }); i < len(p.sortedTicks); i++ {
+			if !isEmptyTick(getTick(p.sortedTicks[i])) {
+				return p.sortedTicks[i], true
+			}
+		}
+		return 0, false
+	}
+
+	for i := sort.Search(len(p.sortedTicks), func(i int) bool { return p.sortedTicks[i] >= tick }) - 1; i >= 0; i-- {
+		if !isEmptyTick(getTick(p.sortedTicks[i])) {
+			return p.sortedTicks[i], true
+		}
+	}
+	return 0, false
+}
+
+// updateTwa updates the twa after a swap and moves the bins that follow it, the twa moves towards the last price
+// over the lookback period.
+func (p *PoolSimulator) updateTwa(timestamp int64, startingTick int32, sqrtPrice *big.Int) {
+	twaD8 := p.getTwaD8(timestamp)
+
+	p.moveBins(twaTick(p.lastTwaD8), twaTick(twaD8), startingTick)
+
+	p.lastTwaD8 = twaD8
+	p.lastTimestamp = timestamp
+	if sqrtPrice != nil {
+		if sqrtLowerTickPrice, sqrtUpperTickPrice, err := tickSqrtPrices(p.tickSpacing, p.activeTick); err == nil {
+			p.lastLogPriceD8 = logPriceD8(p.activeTick, sqrtPrice, sqrtLowerTickPrice, sqrtUpperTickPrice)
+		}
+	}
+}
+
+func (p *PoolSimulator) getTwaD8(timestamp int64) int64 {
+	timeDiff := big.NewInt(timestamp - p.lastTimestamp)
+	if p.lookback == nil || p.lookback.Sign() == 0 || timeDiff.Cmp(p.lookback) >= 0 {
+		return p.lastLogPriceD8
+	}
+	if timeDiff.Sign() <= 0 {
+		return p.lastTwaD8
+	}
+
+	twaD8 := big.NewInt(p.lastLogPriceD8 - p.lastTwaD8)
+	twaD8.Mul(twaD8, timeDiff)
+	twaD8.Quo(twaD8, p.lookback)
+
+	return p.lastTwaD8 + twaD8.Int64()
+}
+
+func isEmptyTick(tick Tick) bool {
+	return (tick.ReserveA == nil || tick.ReserveA.Sign() == 0) && (tick.ReserveB == nil || tick.ReserveB.Sign() == 0)
+}
@@ -0,0 +1,159 @@
+package maverickv2
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+const (
+	tokenA = "0x4200000000000000000000000000000000000006"
+	tokenB = "0x833589fcd6e7a6e4bf26fa1ea5eca2a0b8d5d5bb"
+)
+
+// newTestExtra returns a pool with a balanced active tick 0, 100 B in tick 1 and 100 A in a bin of kind right at tick
+// -1, with fees of 0.1% for tokenA in and 1% for tokenB in.
+func newTestExtra() Extra {
+	hundred := bignumber.NewBig10("100000000000000000000")
+
+	return Extra{
+		FeeAIn:         bignumber.NewBig10("1000000000000000"),
+		FeeBIn:         bignumber.NewBig10("10000000000000000"),
+		ActiveTick:     0,
+		LastTwaD8:      5e7,
+		LastLogPriceD8: 5e7,
+		Ticks: map[int32]Tick{
+			-1: {ReserveA: hundred, ReserveB: new(big.Int), TotalSupply: hundred, BinIDs: [4]uint32{0, 3, 0, 0}},
+			0:  {ReserveA: hundred, ReserveB: hundred, TotalSupply: hundred, BinIDs: [4]uint32{1, 0, 0, 0}},
+			1:  {ReserveA: new(big.Int), ReserveB: hundred, TotalSupply: hundred, BinIDs: [4]uint32{2, 0, 0, 0}},
+		},
+		Bins: map[uint32]Bin{
+			1: {Kind: KindStatic, Tick: 0, TickBalance: hundred},
+			2: {Kind: KindStatic, Tick: 1, TickBalance: hundred},
+			3: {Kind: KindRight, Tick: -1, TickBalance: hundred},
+		},
+	}
+}
+
+func newTestPoolSimulator(t *testing.T, extra Extra) *PoolSimulator {
+	extraBytes, err := json.Marshal(extra)
+	require.NoError(t, err)
+
+	simulator, err := NewPoolSimulator(entity.Pool{
+		Address:  "0x6e8a7c8a5ac3a6e8a5b6e4ec7a2b0bd2e6b1c3d1",
+		Exchange: DexTypeMaverickV2,
+		Type:     DexTypeMaverickV2,
+		Tokens: []*entity.PoolToken{
+			{Address: tokenA, Decimals: 18},
+			{Address: tokenB, Decimals: 18},
+		},
+		Extra:       string(extraBytes),
+		StaticExtra: "{\"tickSpacing\":10,\"lookback\":3600}",
+		Timestamp:   1700000000,
+	})
+	require.NoError(t, err)
+
+	return simulator
+}
+
+func swap(t *testing.T, simulator *PoolSimulator, tokenIn, tokenOut, amountIn string) *pool.CalcAmountOutResult {
+	tokenAmountIn := pool.TokenAmount{Token: tokenIn, Amount: bignumber.NewBig10(amountIn)}
+	result, err := simulator.CalcAmountOut(tokenAmountIn, tokenOut)
+	require.NoError(t, err)
+
+	simulator.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  tokenAmountIn,
+		TokenAmountOut: *result.TokenAmountOut,
+		Fee:            *result.Fee,
+		SwapInfo:       result.SwapInfo,
+	})
+
+	return result
+}
+
+func TestPoolSimulator_CalcAmountOut(t *testing.T) {
+	simulator := newTestPoolSimulator(t, newTestExtra())
+
+	resultAIn, err := simulator.CalcAmountOut(pool.TokenAmount{
+		Token:  tokenA,
+		Amount: bignumber.NewBig10("1000000000000000000"),
+	}, tokenB)
+	require.NoError(t, err)
+	assert.Equal(t, "998498281909573386", resultAIn.TokenAmountOut.Amount.String())
+	assert.Equal(t, "1000000000000000", resultAIn.Fee.Amount.String())
+
+	// the fee of tokenB in is ten times the fee of tokenA in
+	resultBIn, err := simulator.CalcAmountOut(pool.TokenAmount{
+		Token:  tokenB,
+		Amount: bignumber.NewBig10("1000000000000000000"),
+	}, tokenA)
+	require.NoError(t, err)
+	assert.Equal(t, "990492523573247003", resultBIn.TokenAmountOut.Amount.String())
+	assert.Equal(t, "10000000000000000", resultBIn.Fee.Amount.String())
+}
+
+func TestPoolSimulator_CalcAmountOut_CrossTicks(t *testing.T) {
+	simulator := newTestPoolSimulator(t, newTestExtra())
+
+	// the 100 B of tick 0 are not enough, the rest comes from tick 1
+	result := swap(t, simulator, tokenA, tokenB, "150000000000000000000")
+	assert.Equal(t, "149712878041812156542", result.TokenAmountOut.Amount.String())
+
+	assert.Equal(t, int32(1), simulator.activeTick)
+	assert.Equal(t, "0", simulator.ticks[0].ReserveB.String())
+
+	_, err := simulator.CalcAmountOut(pool.TokenAmount{
+		Token:  tokenA,
+		Amount: bignumber.NewBig10("1000000000000000000000"),
+	}, tokenB)
+	assert.ErrorIs(t, err, ErrInsufficientLiquidity)
+}
+
+func TestPoolSimulator_UpdateBalance_MoveBins(t *testing.T) {
+	// the twa was at tick -1 and the last price at tick 0, more than the lookback ago, so the twa is now at tick 0
+	extra := newTestExtra()
+	extra.LastTwaD8 = -5e7
+	simulator := newTestPoolSimulator(t, extra)
+
+	beforeMove, err := simulator.CalcAmountOut(pool.TokenAmount{
+		Token:  tokenB,
+		Amount: bignumber.NewBig10("10000000000000000000"),
+	}, tokenA)
+	require.NoError(t, err)
+
+	swap(t, simulator, tokenA, tokenB, "1000000000000000")
+
+	// the bin of kind right has moved to the twa tick with its 100 A
+	assert.Equal(t, int32(0), simulator.bins[3].Tick)
+	assert.Equal(t, uint32(3), simulator.ticks[0].BinIDs[KindRight])
+	assert.Equal(t, uint32(0), simulator.ticks[-1].BinIDs[KindRight])
+	assert.Equal(t, "0", simulator.ticks[-1].ReserveA.String())
+	assert.Equal(t, "200001000000000000000", simulator.ticks[0].ReserveA.String())
+	assert.Equal(t, "49999999625193029523", simulator.bins[3].TickBalance.String())
+
+	// the active tick is deeper, so the same swap gets more
+	afterMove, err := simulator.CalcAmountOut(pool.TokenAmount{
+		Token:  tokenB,
+		Amount: bignumber.NewBig10("10000000000000000000"),
+	}, tokenA)
+	require.NoError(t, err)
+	assert.Equal(t, 1, afterMove.TokenAmountOut.Amount.Cmp(beforeMove.TokenAmountOut.Amount))
+
+	// the twa is stored by the first swap, the next swaps do not move bins again
+	assert.Equal(t, int64(5e7), simulator.lastTwaD8)
+	assert.Equal(t, int64(1700000000), simulator.lastTimestamp)
+}
+
+func TestPoolSimulator_GetMetaInfo(t *testing.T) {
+	simulator := newTestPoolSimulator(t, newTestExtra())
+
+	assert.Equal(t, Meta{TokenAIn: true}, simulator.GetMetaInfo(tokenA, tokenB))
+	assert.Equal(t, Meta{TokenAIn: false}, simulator.GetMetaInfo(tokenB, tokenA))
+}
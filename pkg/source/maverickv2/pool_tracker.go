@@ -0,0 +1,204 @@
+package maverickv2
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+type PoolTracker struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+}
+
+func NewPoolTracker(
+	cfg *Config,
+	ethrpcClient *ethrpc.Client,
+) *PoolTracker {
+	return &PoolTracker{
+		config:       cfg,
+		ethrpcClient: ethrpcClient,
+	}
+}
+
+func (d *PoolTracker) GetNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	_ pool.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	logger.WithFields(logger.Fields{
+		"address": p.Address,
+	}).Infof("[%s] Start getting new state of pool", p.Type)
+
+	var (
+		feeAIn, feeBIn *big.Int
+		getStateResult GetStateResult
+	)
+
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+	calls.AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: p.Address,
+		Method: poolMethodFee,
+		Params: []interface{}{true},
+	}, []interface{}{&feeAIn})
+	calls.AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: p.Address,
+		Method: poolMethodFee,
+		Params: []interface{}{false},
+	}, []interface{}{&feeBIn})
+	calls.AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: p.Address,
+		Method: poolMethodGetState,
+		Params: nil,
+	}, []interface{}{&getStateResult})
+
+	if _, err := calls.Aggregate(); err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to aggregate to get pool data")
+
+		return entity.Pool{}, err
+	}
+
+	bins, err := d.getBins(ctx, p.Address, getStateResult.State.BinCounter)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to get bins")
+
+		return entity.Pool{}, err
+	}
+
+	ticks, err := d.getTicks(ctx, p.Address, bins)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to get ticks")
+
+		return entity.Pool{}, err
+	}
+
+	extra := Extra{
+		FeeAIn:             feeAIn,
+		FeeBIn:             feeBIn,
+		ProtocolFeeRatioD3: getStateResult.State.ProtocolFeeRatioD3,
+		ActiveTick:         getStateResult.State.ActiveTick,
+		LastTwaD8:          getStateResult.State.LastTwaD8,
+		LastLogPriceD8:     getStateResult.State.LastLogPriceD8,
+		LastTimestamp:      getStateResult.State.LastTimestamp.Int64(),
+		Ticks:              ticks,
+		Bins:               bins,
+	}
+
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to marshal extra")
+
+		return entity.Pool{}, err
+	}
+
+	p.Reserves = entity.PoolReserves{
+		scaleToAmount(getStateResult.State.ReserveA, p.Tokens[0].Decimals).String(),
+		scaleToAmount(getStateResult.State.ReserveB, p.Tokens[1].Decimals).String(),
+	}
+	p.Timestamp = time.Now().Unix()
+	p.Extra = string(extraBytes)
+
+	logger.WithFields(logger.Fields{
+		"address": p.Address,
+	}).Infof("[%s] Finish getting new state of pool", p.Type)
+
+	return p, nil
+}
+
+// getBins returns the bins which are not merged into another bin, bin ids start from 1
+func (d *PoolTracker) getBins(ctx context.Context, poolAddress string, binCounter uint32) (map[uint32]Bin, error) {
+	bins := make(map[uint32]Bin)
+	if binCounter == 0 {
+		return bins, nil
+	}
+
+	binResults := make([]GetBinResult, binCounter)
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+	for i := range binResults {
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: poolAddress,
+			Method: poolMethodGetBin,
+			Params: []interface{}{uint32(i + 1)},
+		}, []interface{}{&binResults[i]})
+	}
+	if _, err := calls.Aggregate(); err != nil {
+		return nil, err
+	}
+
+	for i, binResult := range binResults {
+		if binResult.BinState.MergeID != 0 || binResult.BinState.TickBalance.Sign() == 0 {
+			continue
+		}
+
+		bins[uint32(i+1)] = Bin{
+			Kind:        binResult.BinState.Kind,
+			Tick:        binResult.BinState.Tick,
+			TickBalance: binResult.BinState.TickBalance,
+		}
+	}
+
+	return bins, nil
+}
+
+// getTicks returns the ticks which have bins
+func (d *PoolTracker) getTicks(ctx context.Context, poolAddress string, bins map[uint32]Bin) (map[int32]Tick, error) {
+	tickResults := make(map[int32]*GetTickResult)
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+	for _, bin := range bins {
+		if _, ok := tickResults[bin.Tick]; ok {
+			continue
+		}
+
+		tickResult := &GetTickResult{}
+		tickResults[bin.Tick] = tickResult
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: poolAddress,
+			Method: poolMethodGetTick,
+			Params: []interface{}{bin.Tick},
+		}, []interface{}{tickResult})
+	}
+
+	ticks := make(map[int32]Tick, len(tickResults))
+	if len(tickResults) == 0 {
+		return ticks, nil
+	}
+
+	if _, err := calls.Aggregate(); err != nil {
+		return nil, err
+	}
+
+	for tick, tickResult := range tickResults {
+		ticks[tick] = Tick{
+			ReserveA:    tickResult.TickState.ReserveA,
+			ReserveB:    tickResult.TickState.ReserveB,
+			TotalSupply: tickResult.TickState.TotalSupply,
+			BinIDs:      tickResult.TickState.BinIdsByTick,
+		}
+	}
+
+	return ticks, nil
+}
@@ -0,0 +1,152 @@
+package maverickv2
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util"
+)
+
+type PoolsListUpdater struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+}
+
+func NewPoolsListUpdater(
+	cfg *Config,
+	ethrpcClient *ethrpc.Client,
+) *PoolsListUpdater {
+	return &PoolsListUpdater{
+		config:       cfg,
+		ethrpcClient: ethrpcClient,
+	}
+}
+
+func (d *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte) ([]entity.Pool, []byte, error) {
+	var metadata Metadata
+	if len(metadataBytes) != 0 {
+		if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
+			return nil, metadataBytes, err
+		}
+	}
+
+	// Add timestamp to the context so that each run iteration will have something different
+	ctx = util.NewContextWithTimestamp(ctx)
+
+	// the factory returns the pools in [startIndex, endIndex) that exist, so a short page means there are no more
+	var poolAddresses []common.Address
+	if _, err := d.ethrpcClient.NewRequest().SetContext(ctx).AddCall(&ethrpc.Call{
+		ABI:    factoryABI,
+		Target: d.config.FactoryAddress,
+		Method: factoryMethodLookup,
+		Params: []interface{}{
+			big.NewInt(int64(metadata.Offset)),
+			big.NewInt(int64(metadata.Offset + d.config.NewPoolLimit)),
+		},
+	}, []interface{}{&poolAddresses}).Call(); err != nil {
+		logger.WithFields(logger.Fields{
+			"type":  DexTypeMaverickV2,
+			"error": err,
+		}).Errorf("failed to lookup pools from factory")
+		return nil, metadataBytes, err
+	}
+
+	if len(poolAddresses) == 0 {
+		return nil, metadataBytes, nil
+	}
+
+	pools, err := d.processBatch(ctx, poolAddresses)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"type":  DexTypeMaverickV2,
+			"error": err,
+		}).Errorf("failed to process batch of pools")
+		return nil, metadataBytes, err
+	}
+
+	newMetadataBytes, err := json.Marshal(Metadata{Offset: metadata.Offset + len(poolAddresses)})
+	if err != nil {
+		return nil, metadataBytes, err
+	}
+
+	return pools, newMetadataBytes, nil
+}
+
+func (d *PoolsListUpdater) processBatch(ctx context.Context, poolAddresses []common.Address) ([]entity.Pool, error) {
+	var (
+		tokenAs      = make([]common.Address, len(poolAddresses))
+		tokenBs      = make([]common.Address, len(poolAddresses))
+		tickSpacings = make([]*big.Int, len(poolAddresses))
+		lookbacks    = make([]*big.Int, len(poolAddresses))
+	)
+
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+	for i, poolAddress := range poolAddresses {
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: poolAddress.Hex(),
+			Method: poolMethodTokenA,
+		}, []interface{}{&tokenAs[i]})
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: poolAddress.Hex(),
+			Method: poolMethodTokenB,
+		}, []interface{}{&tokenBs[i]})
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: poolAddress.Hex(),
+			Method: poolMethodTickSpacing,
+		}, []interface{}{&tickSpacings[i]})
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: poolAddress.Hex(),
+			Method: poolMethodLookback,
+		}, []interface{}{&lookbacks[i]})
+	}
+
+	if _, err := calls.Aggregate(); err != nil {
+		return nil, err
+	}
+
+	pools := make([]entity.Pool, 0, len(poolAddresses))
+	for i, poolAddress := range poolAddresses {
+		staticExtraBytes, err := json.Marshal(StaticExtra{
+			TickSpacing: tickSpacings[i],
+			Lookback:    lookbacks[i],
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		pools = append(pools, entity.Pool{
+			Address:   strings.ToLower(poolAddress.Hex()),
+			Exchange:  d.config.DexID,
+			Type:      DexTypeMaverickV2,
+			Timestamp: time.Now().Unix(),
+			Reserves:  entity.PoolReserves{"0", "0"},
+			Tokens: []*entity.PoolToken{
+				{
+					Address:   strings.ToLower(tokenAs[i].Hex()),
+					Weight:    defaultTokenWeight,
+					Swappable: true,
+				},
+				{
+					Address:   strings.ToLower(tokenBs[i].Hex()),
+					Weight:    defaultTokenWeight,
+					Swappable: true,
+				},
+			},
+			StaticExtra: string(staticExtraBytes),
+		})
+	}
+
+	return pools, nil
+}
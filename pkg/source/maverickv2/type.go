@@ -0,0 +1,100 @@
+package maverickv2
+
+import (
+	"math/big"
+)
+
+type Metadata struct {
+	Offset int `json:"offset"`
+}
+
+type StaticExtra struct {
+	TickSpacing *big.Int `json:"tickSpacing"`
+	Lookback    *big.Int `json:"lookback"`
+}
+
+// Extra is the state of a pool. Unlike v1, the reserves are held by the ticks and the bins of a tick own shares of them,
+// the bins are only needed to move their liquidity after a swap.
+type Extra struct {
+	FeeAIn             *big.Int       `json:"feeAIn"`
+	FeeBIn             *big.Int       `json:"feeBIn"`
+	ProtocolFeeRatioD3 uint8          `json:"protocolFeeRatioD3"`
+	ActiveTick         int32          `json:"activeTick"`
+	LastTwaD8          int64          `json:"lastTwaD8"`
+	LastLogPriceD8     int64          `json:"lastLogPriceD8"`
+	LastTimestamp      int64          `json:"lastTimestamp"`
+	Ticks              map[int32]Tick `json:"ticks"`
+	Bins               map[uint32]Bin `json:"bins"`
+}
+
+type Tick struct {
+	ReserveA    *big.Int  `json:"reserveA"`
+	ReserveB    *big.Int  `json:"reserveB"`
+	TotalSupply *big.Int  `json:"totalSupply"`
+	BinIDs      [4]uint32 `json:"binIds"`
+}
+
+type Bin struct {
+	Kind        uint8    `json:"kind"`
+	Tick        int32    `json:"tick"`
+	TickBalance *big.Int `json:"tickBalance"`
+}
+
+// Meta is the meta of the encoder, the pool swaps by the side of the token in
+type Meta struct {
+	TokenAIn bool `json:"tokenAIn"`
+}
+
+// maverickSwapInfo present the ticks changed by a swap and the price after it
+type maverickSwapInfo struct {
+	ticks      map[int32]Tick
+	activeTick int32
+	sqrtPrice  *big.Int
+}
+
+type Gas struct {
+	Swap int64
+}
+
+// Delta is the result of a swap in a tick, amounts are in 18 decimals
+type Delta struct {
+	DeltaInBinInternal *big.Int
+	DeltaInErc         *big.Int
+	DeltaOutErc        *big.Int
+	Excess             *big.Int
+	EndSqrtPrice       *big.Int
+}
+
+type GetStateResult struct {
+	State struct {
+		ReserveA           *big.Int `json:"reserveA"`
+		ReserveB           *big.Int `json:"reserveB"`
+		LastTwaD8          int64    `json:"lastTwaD8"`
+		LastLogPriceD8     int64    `json:"lastLogPriceD8"`
+		LastTimestamp      *big.Int `json:"lastTimestamp"`
+		ActiveTick         int32    `json:"activeTick"`
+		IsLocked           bool     `json:"isLocked"`
+		BinCounter         uint32   `json:"binCounter"`
+		ProtocolFeeRatioD3 uint8    `json:"protocolFeeRatioD3"`
+	}
+}
+
+type GetTickResult struct {
+	TickState struct {
+		ReserveA     *big.Int  `json:"reserveA"`
+		ReserveB     *big.Int  `json:"reserveB"`
+		TotalSupply  *big.Int  `json:"totalSupply"`
+		BinIdsByTick [4]uint32 `json:"binIdsByTick"`
+	}
+}
+
+type GetBinResult struct {
+	BinState struct {
+		MergeBinBalance *big.Int `json:"mergeBinBalance"`
+		TickBalance     *big.Int `json:"tickBalance"`
+		TotalSupply     *big.Int `json:"totalSupply"`
+		Kind            uint8    `json:"kind"`
+		Tick            int32    `json:"tick"`
+		MergeID         uint32   `json:"mergeId"`
+	}
+}
@@ -1,13 +1,17 @@
 package metavault
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx/vaultengine"
+)
 
 var (
-	ErrVaultSwapsNotEnabled                = errors.New("vault: swaps not enabled")
-	ErrVaultMaxUsdmExceeded                = errors.New("vault: max USDM exceeded") // code: 51
-	ErrVaultPoolAmountExceeded             = errors.New("vault: poolAmount exceeded")
-	ErrVaultReserveExceedsPool             = errors.New("vault: reserve exceeds pool") // code: 50
-	ErrVaultPoolAmountLessThanBufferAmount = errors.New("vault: poolAmount < buffer")
+	ErrVaultSwapsNotEnabled                = vaultengine.ErrVaultSwapsNotEnabled
+	ErrVaultMaxUsdmExceeded                = vaultengine.ErrVaultMaxUsdgExceeded // code: 51
+	ErrVaultPoolAmountExceeded             = vaultengine.ErrVaultPoolAmountExceeded
+	ErrVaultReserveExceedsPool             = vaultengine.ErrVaultReserveExceedsPool // code: 50
+	ErrVaultPoolAmountLessThanBufferAmount = vaultengine.ErrVaultPoolAmountLessThanBufferAmount
 
 	ErrVaultPriceFeedInvalidPriceFeed         = errors.New("vaultPriceFeed: invalid price feed")
 	ErrVaultPriceFeedInvalidPrice             = errors.New("vaultPriceFeed: invalid price")
@@ -5,9 +5,11 @@ import (
 	"math/big"
 	"strings"
 
+	"github.com/KyberNetwork/logger"
+
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx/vaultengine"
 	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
-	constant "github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
 )
 
 type Gas struct {
@@ -17,9 +19,9 @@ type Gas struct {
 type PoolSimulator struct {
 	pool.Pool
 
-	vault      *Vault
-	vaultUtils *VaultUtils
-	gas        Gas
+	vault  *Vault
+	engine *vaultengine.Engine
+	gas    Gas
 }
 
 func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
@@ -44,12 +46,34 @@ func NewPoolSimulator(entityPool entity.Pool) (*PoolSimulator, error) {
 		Pool: pool.Pool{
 			Info: info,
 		},
-		vault:      extra.Vault,
-		vaultUtils: NewVaultUtils(extra.Vault),
-		gas:        DefaultGas,
+		vault:  extra.Vault,
+		engine: newVaultEngine(extra.Vault),
+		gas:    DefaultGas,
 	}, nil
 }
 
+// newVaultEngine binds the vault engine to the state maps of the vault, so the dynamic fees of VaultUtils follow the
+// swaps simulated by the engine.
+func newVaultEngine(vault *Vault) *vaultengine.Engine {
+	vaultUtils := NewVaultUtils(vault)
+
+	return vaultengine.NewEngine(
+		&vaultengine.Vault{
+			IsSwapEnabled:   vault.IsSwapEnabled,
+			UsdgAddress:     vault.USDM.Address,
+			PoolAmounts:     vault.PoolAmounts,
+			BufferAmounts:   vault.BufferAmounts,
+			ReservedAmounts: vault.ReservedAmounts,
+			UsdgAmounts:     vault.USDMAmounts,
+			MaxUsdgAmounts:  vault.MaxUSDMAmounts,
+		},
+		vault,
+		vaultengine.FeeCalculatorFunc(func(tokenIn string, tokenOut string, usdmAmount *big.Int) (*big.Int, error) {
+			return vaultUtils.GetSwapFeeBasisPoints(tokenIn, tokenOut, usdmAmount), nil
+		}),
+	)
+}
+
 func (p *PoolSimulator) CalcAmountOut(
 	tokenAmountIn pool.TokenAmount,
 	tokenOut string,
@@ -75,22 +99,19 @@ func (p *PoolSimulator) CalcAmountOut(
 	}, nil
 }
 
-// UpdateBalance update UsdmAmount only
-// https://github.com/gmx-io/gmx-contracts/blob/787d767e033c411f6d083f2725fb54b7fa956f7e/contracts/core/Vault.sol#L547-L548
+// UpdateBalance replays the swap on the vault, updating its usdg amounts and pool amounts.
+// https://github.com/gmx-io/gmx-contracts/blob/787d767e033c411f6d083f2725fb54b7fa956f7e/contracts/core/Vault.sol#L521
 func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
-	input, output, fee := params.TokenAmountIn, params.TokenAmountOut, params.Fee
-	priceIn, err := p.vault.GetMinPrice(input.Token)
-	if err != nil {
+	input, output := params.TokenAmountIn, params.TokenAmountOut
+	if _, err := p.engine.Swap(input.Token, output.Token, input.Amount); err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Info.Address,
+			"tokenIn":     input.Token,
+			"tokenOut":    output.Token,
+			"error":       err,
+		}).Errorf("failed to update balance")
 		return
 	}
-
-	usdmAmount := new(big.Int).Div(new(big.Int).Mul(input.Amount, priceIn), PricePrecision)
-	usdmAmount = p.vault.AdjustForDecimals(usdmAmount, input.Token, p.vault.USDM.Address)
-
-	p.vault.IncreaseUSDMAmount(input.Token, usdmAmount)
-	p.vault.DecreaseUSDMAmount(output.Token, usdmAmount)
-	p.vault.IncreasePoolAmount(input.Token, input.Amount)
-	p.vault.DecreasePoolAmount(output.Token, new(big.Int).Add(output.Amount, fee.Amount))
 }
 
 func (p *PoolSimulator) CanSwapFrom(address string) []string {
@@ -129,97 +150,10 @@ func (p *PoolSimulator) GetMetaInfo(_ string, _ string) interface{} { return nil
 
 // getAmountOut returns amountOutAfterFees, feeAmount and error
 func (p *PoolSimulator) getAmountOut(tokenIn string, tokenOut string, amountIn *big.Int) (*big.Int, *big.Int, error) {
-	if !p.vault.IsSwapEnabled {
-		return nil, nil, ErrVaultSwapsNotEnabled
-	}
-
-	priceIn, err := p.vault.GetMinPrice(tokenIn)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	priceOut, err := p.vault.GetMaxPrice(tokenOut)
+	result, err := p.engine.GetAmountOut(tokenIn, tokenOut, amountIn)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	amountOut := new(big.Int).Div(new(big.Int).Mul(amountIn, priceIn), priceOut)
-	amountOut = p.vault.AdjustForDecimals(amountOut, tokenIn, tokenOut)
-
-	usdmAmount := new(big.Int).Div(new(big.Int).Mul(amountIn, priceIn), PricePrecision)
-	usdmAmount = p.vault.AdjustForDecimals(usdmAmount, tokenIn, p.vault.USDM.Address)
-
-	if err = p.validateMaxUsdmExceed(tokenIn, usdmAmount); err != nil {
-		return nil, nil, err
-	}
-
-	// in smart contract, this validation is implemented inside _decreasePoolAmount method
-	if err = p.validateMinPoolAmount(tokenOut, amountOut); err != nil {
-		return nil, nil, err
-	}
-
-	// in smart contract, this validation is implemented inside _validateBufferAmount method
-	if err = p.validateBufferAmount(tokenOut, amountOut); err != nil {
-		return nil, nil, err
-	}
-
-	feeBasisPoints := p.vaultUtils.GetSwapFeeBasisPoints(tokenIn, tokenOut, usdmAmount)
-	amountOutAfterFees := new(big.Int).Div(
-		new(big.Int).Mul(
-			amountOut,
-			new(big.Int).Sub(BasisPointsDivisor, feeBasisPoints),
-		),
-		BasisPointsDivisor,
-	)
-
-	feeAmount := new(big.Int).Sub(amountOut, amountOutAfterFees)
-
-	return amountOutAfterFees, feeAmount, nil
-}
-
-func (p *PoolSimulator) validateMaxUsdmExceed(token string, amount *big.Int) error {
-	currentUsdmAmount := p.vault.USDMAmounts[token]
-	newUsdmAmount := new(big.Int).Add(currentUsdmAmount, amount)
-
-	maxUsdmAmount := p.vault.MaxUSDMAmounts[token]
-
-	if maxUsdmAmount.Cmp(constant.ZeroBI) == 0 {
-		return nil
-	}
-
-	if newUsdmAmount.Cmp(maxUsdmAmount) < 0 {
-		return nil
-	}
-
-	return ErrVaultMaxUsdmExceeded
-}
-
-func (p *PoolSimulator) validateMinPoolAmount(token string, amount *big.Int) error {
-	currentPoolAmount := p.vault.PoolAmounts[token]
-
-	if currentPoolAmount.Cmp(amount) < 0 {
-		return ErrVaultPoolAmountExceeded
-	}
-
-	newPoolAmount := new(big.Int).Sub(currentPoolAmount, amount)
-	reservedAmount := p.vault.ReservedAmounts[token]
-
-	if reservedAmount.Cmp(newPoolAmount) > 0 {
-		return ErrVaultReserveExceedsPool
-	}
-
-	return nil
-}
-
-func (p *PoolSimulator) validateBufferAmount(token string, amount *big.Int) error {
-	currentPoolAmount := p.vault.PoolAmounts[token]
-	newPoolAmount := new(big.Int).Sub(currentPoolAmount, amount)
-
-	bufferAmount := p.vault.BufferAmounts[token]
-
-	if newPoolAmount.Cmp(bufferAmount) < 0 {
-		return ErrVaultPoolAmountLessThanBufferAmount
-	}
-
-	return nil
+	return result.AmountOutAfterFees, result.FeeAmount, nil
 }
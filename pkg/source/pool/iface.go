@@ -52,3 +52,17 @@ type RFQResult struct {
 type IPoolRFQ interface {
 	RFQ(ctx context.Context, recipient string, params any) (RFQResult, error)
 }
+
+// IPoolInventoryUpdater is implemented by the simulators drawing on the Inventory of the request. UpdateInventory
+// applies a swap like UpdateBalance, but returns an error without applying the swap when the inventory does not
+// cover it. Callers should use it instead of UpdateBalance when it is available.
+type IPoolInventoryUpdater interface {
+	UpdateInventory(params UpdateBalanceParams) error
+}
+
+// IPoolInventoryCalculator is implemented by the simulators whose makers can be drawn on by other pools of the
+// request. CalcAmountOutWithInventory quotes a swap like CalcAmountOut, but with the balances of the makers left in
+// the Inventory. Callers should use it instead of CalcAmountOut when it is available.
+type IPoolInventoryCalculator interface {
+	CalcAmountOutWithInventory(tokenAmountIn TokenAmount, tokenOut string, inventory *Inventory) (*CalcAmountOutResult, error)
+}
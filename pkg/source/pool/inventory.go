@@ -0,0 +1,227 @@
+package pool
+
+import (
+	"math/big"
+	"strings"
+	"sync"
+)
+
+type inventoryKey struct {
+	maker string
+	token string
+}
+
+func newInventoryKey(maker, token string) inventoryKey {
+	return inventoryKey{maker: strings.ToLower(maker), token: strings.ToLower(token)}
+}
+
+// Inventory is the per-request balances of the makers of RFQ and limit order pools.
+// Balance is a map of tokenAddress - balance, used by the token-only methods (GetBalance, UpdateBalance, ...). The
+// maker methods (GetMakerBalance, UpdateMakerBalance, ...) scope the balances by maker and token instead, so the pools
+// of different makers never share a balance and the pairs of one maker always do.
+// The balances are stored WITHOUT decimals, the big.Int values are never modified in place.
+// DONOT directly modify them
+type Inventory struct {
+	lock         *sync.RWMutex
+	Balance      map[string]*big.Int
+	makerBalance map[inventoryKey]*big.Int
+}
+
+// InventorySnapshot is a copy of the balances of an Inventory at some point of the request
+type InventorySnapshot struct {
+	balance      map[string]*big.Int
+	makerBalance map[inventoryKey]*big.Int
+}
+
+func NewInventory(balance map[string]*big.Int) *Inventory {
+	if balance == nil {
+		balance = make(map[string]*big.Int)
+	}
+
+	return &Inventory{
+		lock:         &sync.RWMutex{},
+		Balance:      balance,
+		makerBalance: make(map[inventoryKey]*big.Int),
+	}
+}
+
+// NewInventoryWithBalances returns an Inventory holding the balances of a maker, keyed by token address
+func NewInventoryWithBalances(maker string, balances map[string]*big.Int) *Inventory {
+	i := NewInventory(nil)
+	for token, balance := range balances {
+		i.makerBalance[newInventoryKey(maker, token)] = new(big.Int).Set(balance)
+	}
+
+	return i
+}
+
+// GetBalance returns a copy of balance for the Inventory
+func (i *Inventory) GetBalance(tokenAddress string) *big.Int {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return getBalance(i.Balance, tokenAddress)
+}
+
+// UpdateBalance will reduce the Balance to reflect the change in inventory
+// note this delta is amount with Decimal
+func (i *Inventory) UpdateBalance(
+	decreaseTokenAddress, increaseTokenAddress string,
+	decreaseDelta, increaseDelta *big.Int,
+) (*big.Int, *big.Int, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return updateBalance(i.Balance, decreaseTokenAddress, increaseTokenAddress, decreaseDelta, increaseDelta)
+}
+
+// InitBalance sets the balance of tokenAddress if the Inventory does not have it yet, and returns a copy of the
+// balance in the Inventory
+func (i *Inventory) InitBalance(tokenAddress string, balance *big.Int) *big.Int {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return initBalance(i.Balance, tokenAddress, balance)
+}
+
+// DecreaseBalance reduces the balance of tokenAddress by delta and returns a copy of the new balance
+func (i *Inventory) DecreaseBalance(tokenAddress string, delta *big.Int) (*big.Int, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return decreaseBalance(i.Balance, tokenAddress, delta)
+}
+
+// HasMakerBalance returns whether the Inventory tracks the balance of the maker for the token
+func (i *Inventory) HasMakerBalance(maker, token string) bool {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	_, avail := i.makerBalance[newInventoryKey(maker, token)]
+	return avail
+}
+
+// GetMakerBalance returns a copy of the balance of the maker for the token, zero when it is not tracked
+func (i *Inventory) GetMakerBalance(maker, token string) *big.Int {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return getBalance(i.makerBalance, newInventoryKey(maker, token))
+}
+
+// MakerBalances returns a copy of the balances of the maker, keyed by lowercase token address
+func (i *Inventory) MakerBalances(maker string) map[string]*big.Int {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	maker = strings.ToLower(maker)
+	balances := make(map[string]*big.Int)
+	for key, balance := range i.makerBalance {
+		if key.maker == maker {
+			balances[key.token] = new(big.Int).Set(balance)
+		}
+	}
+	return balances
+}
+
+// SetMakerBalance replaces the balance of the maker for the token
+func (i *Inventory) SetMakerBalance(maker, token string, balance *big.Int) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.makerBalance[newInventoryKey(maker, token)] = new(big.Int).Set(balance)
+}
+
+// InitMakerBalance sets the balance of the maker for the token if the Inventory does not have it yet, and returns a
+// copy of the balance in the Inventory
+func (i *Inventory) InitMakerBalance(maker, token string, balance *big.Int) *big.Int {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return initBalance(i.makerBalance, newInventoryKey(maker, token), balance)
+}
+
+// DecreaseMakerBalance reduces the balance of the maker for the token by delta and returns a copy of the new balance.
+// The balance is left untouched when it does not cover delta.
+func (i *Inventory) DecreaseMakerBalance(maker, token string, delta *big.Int) (*big.Int, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return decreaseBalance(i.makerBalance, newInventoryKey(maker, token), delta)
+}
+
+// UpdateMakerBalance moves the balances of the maker for a swap, the maker pays decreaseDelta of decreaseToken and
+// receives increaseDelta of increaseToken. Both balances must be tracked, and nothing is changed when the swap is not
+// covered. It returns copies of the new balances.
+func (i *Inventory) UpdateMakerBalance(
+	maker, decreaseToken, increaseToken string,
+	decreaseDelta, increaseDelta *big.Int,
+) (*big.Int, *big.Int, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return updateBalance(i.makerBalance, newInventoryKey(maker, decreaseToken), newInventoryKey(maker, increaseToken),
+		decreaseDelta, increaseDelta)
+}
+
+// Snapshot returns the current balances, a route explorer restores them after trying a path it does not keep
+func (i *Inventory) Snapshot() InventorySnapshot {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return InventorySnapshot{balance: copyBalances(i.Balance), makerBalance: copyBalances(i.makerBalance)}
+}
+
+// Restore replaces the balances with the ones of the snapshot, the snapshot can be restored several times
+func (i *Inventory) Restore(snapshot InventorySnapshot) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.Balance = copyBalances(snapshot.balance)
+	i.makerBalance = copyBalances(snapshot.makerBalance)
+}
+
+func getBalance[K comparable](balances map[K]*big.Int, key K) *big.Int {
+	balance, avail := balances[key]
+	if !avail {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Set(balance)
+}
+
+func initBalance[K comparable](balances map[K]*big.Int, key K, balance *big.Int) *big.Int {
+	if _, avail := balances[key]; !avail {
+		balances[key] = new(big.Int).Set(balance)
+	}
+	return new(big.Int).Set(balances[key])
+}
+
+func decreaseBalance[K comparable](balances map[K]*big.Int, key K, delta *big.Int) (*big.Int, error) {
+	balance, avail := balances[key]
+	if !avail {
+		return big.NewInt(0), ErrTokenNotAvailable
+	}
+	if balance.Cmp(delta) < 0 {
+		return new(big.Int).Set(balance), ErrNotEnoughInventory
+	}
+	balances[key] = new(big.Int).Sub(balance, delta)
+	return new(big.Int).Set(balances[key]), nil
+}
+
+func updateBalance[K comparable](
+	balances map[K]*big.Int,
+	decreaseKey, increaseKey K,
+	decreaseDelta, increaseDelta *big.Int,
+) (*big.Int, *big.Int, error) {
+	decreasedBalance, avail := balances[decreaseKey]
+	if !avail {
+		return big.NewInt(0), big.NewInt(0), ErrTokenNotAvailable
+	}
+	increasedBalance, avail := balances[increaseKey]
+	if !avail {
+		return big.NewInt(0), big.NewInt(0), ErrTokenNotAvailable
+	}
+	if decreasedBalance.Cmp(decreaseDelta) < 0 {
+		return new(big.Int).Set(decreasedBalance), new(big.Int).Set(increasedBalance), ErrNotEnoughInventory
+	}
+
+	balances[decreaseKey] = new(big.Int).Sub(decreasedBalance, decreaseDelta)
+	balances[increaseKey] = new(big.Int).Add(balances[increaseKey], increaseDelta)
+	return new(big.Int).Set(balances[decreaseKey]), new(big.Int).Set(balances[increaseKey]), nil
+}
+
+// copyBalances copies the map only, the big.Int values are never modified in place
+func copyBalances[K comparable](balances map[K]*big.Int) map[K]*big.Int {
+	copied := make(map[K]*big.Int, len(balances))
+	for key, value := range balances {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,104 @@
+package pool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testMaker1 = "0x00000000000000000000000000000000000000a1"
+	testMaker2 = "0x00000000000000000000000000000000000000a2"
+	testUSDC   = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	testWETH   = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+)
+
+func TestInventory_ScopedByMakerAndToken(t *testing.T) {
+	inventory := NewInventoryWithBalances(testMaker1, map[string]*big.Int{testUSDC: big.NewInt(100)})
+	inventory.SetMakerBalance(testMaker2, testUSDC, big.NewInt(50))
+
+	balance, err := inventory.DecreaseMakerBalance(testMaker1, "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", big.NewInt(30))
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(70), balance)
+	assert.Equal(t, big.NewInt(50), inventory.GetMakerBalance(testMaker2, testUSDC))
+
+	assert.False(t, inventory.HasMakerBalance(testMaker1, testWETH))
+	assert.Equal(t, big.NewInt(0), inventory.GetMakerBalance(testMaker1, testWETH))
+	_, err = inventory.DecreaseMakerBalance(testMaker1, testWETH, big.NewInt(1))
+	assert.ErrorIs(t, err, ErrTokenNotAvailable)
+
+	// InitBalance keeps the balance already drawn on
+	assert.Equal(t, big.NewInt(70), inventory.InitMakerBalance(testMaker1, testUSDC, big.NewInt(100)))
+	assert.Equal(t, big.NewInt(1), inventory.InitMakerBalance(testMaker1, testWETH, big.NewInt(1)))
+}
+
+func TestInventory_UpdateBalance(t *testing.T) {
+	inventory := NewInventoryWithBalances(testMaker1, map[string]*big.Int{
+		testUSDC: big.NewInt(100),
+		testWETH: big.NewInt(10),
+	})
+
+	usdcBalance, wethBalance, err := inventory.UpdateMakerBalance(testMaker1, testUSDC, testWETH, big.NewInt(60), big.NewInt(5))
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(40), usdcBalance)
+	assert.Equal(t, big.NewInt(15), wethBalance)
+
+	// Nothing moves when the swap is not covered
+	_, _, err = inventory.UpdateMakerBalance(testMaker1, testUSDC, testWETH, big.NewInt(60), big.NewInt(5))
+	assert.ErrorIs(t, err, ErrNotEnoughInventory)
+	assert.Equal(t, big.NewInt(40), inventory.GetMakerBalance(testMaker1, testUSDC))
+	assert.Equal(t, big.NewInt(15), inventory.GetMakerBalance(testMaker1, testWETH))
+
+	_, _, err = inventory.UpdateMakerBalance(testMaker2, testUSDC, testWETH, big.NewInt(1), big.NewInt(1))
+	assert.ErrorIs(t, err, ErrTokenNotAvailable)
+}
+
+func TestInventory_SnapshotRestore(t *testing.T) {
+	inventory := NewInventoryWithBalances(testMaker1, map[string]*big.Int{testUSDC: big.NewInt(100)})
+	snapshot := inventory.Snapshot()
+
+	_, err := inventory.DecreaseMakerBalance(testMaker1, testUSDC, big.NewInt(40))
+	require.NoError(t, err)
+	inventory.InitMakerBalance(testMaker2, testUSDC, big.NewInt(10))
+
+	inventory.Restore(snapshot)
+	assert.Equal(t, big.NewInt(100), inventory.GetMakerBalance(testMaker1, testUSDC))
+	assert.False(t, inventory.HasMakerBalance(testMaker2, testUSDC))
+
+	// The snapshot is not affected by the balances drawn after restoring it
+	_, err = inventory.DecreaseMakerBalance(testMaker1, testUSDC, big.NewInt(100))
+	require.NoError(t, err)
+	inventory.Restore(snapshot)
+	assert.Equal(t, big.NewInt(100), inventory.GetMakerBalance(testMaker1, testUSDC))
+}
+
+func TestInventory_TokenBalances(t *testing.T) {
+	inventory := NewInventory(map[string]*big.Int{testUSDC: big.NewInt(100), testWETH: big.NewInt(10)})
+	inventory.SetMakerBalance(testMaker1, testUSDC, big.NewInt(50))
+	snapshot := inventory.Snapshot()
+
+	// the token balances and the maker balances are kept apart
+	usdcBalance, wethBalance, err := inventory.UpdateBalance(testUSDC, testWETH, big.NewInt(60), big.NewInt(5))
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(40), usdcBalance)
+	assert.Equal(t, big.NewInt(15), wethBalance)
+	assert.Equal(t, big.NewInt(40), inventory.Balance[testUSDC])
+	assert.Equal(t, big.NewInt(50), inventory.GetMakerBalance(testMaker1, testUSDC))
+	assert.Equal(t, map[string]*big.Int{testUSDC: big.NewInt(50)}, inventory.MakerBalances(testMaker1))
+
+	_, err = inventory.DecreaseBalance(testUSDC, big.NewInt(41))
+	assert.ErrorIs(t, err, ErrNotEnoughInventory)
+	assert.Equal(t, big.NewInt(1), inventory.InitBalance(testMaker2, big.NewInt(1)))
+
+	inventory.Restore(snapshot)
+	assert.Equal(t, big.NewInt(100), inventory.GetBalance(testUSDC))
+	assert.Equal(t, big.NewInt(0), inventory.GetBalance(testMaker2))
+
+	// the balances returned are copies
+	inventory.MakerBalances(testMaker1)[testUSDC].SetInt64(0)
+	inventory.GetBalance(testWETH).SetInt64(0)
+	assert.Equal(t, big.NewInt(50), inventory.GetMakerBalance(testMaker1, testUSDC))
+	assert.Equal(t, big.NewInt(10), inventory.GetBalance(testWETH))
+}
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"math/big"
 	"runtime"
-	"sync"
+
 	"github.com/KyberNetwork/logger"
 )
 
@@ -97,8 +97,7 @@ type UpdateBalanceParams struct {
 	Fee            TokenAmount
 	SwapInfo       interface{}
 
-	//Inventory is a reference to a per-request inventory balances.
-	// key is tokenAddress, balance is big.Float
+	// Inventory is a reference to the per-request balances of the makers, shared by every pool of the request.
 	// Must use reference (not copy)
 	Inventory *Inventory
 }
@@ -189,52 +188,3 @@ func CalcAmountIn(pool IPoolSimulator, tokenAmountOut TokenAmount, tokenIn strin
 		Gas: amountOutTokenOut.Gas,
 	}, nil
 }
-
-// Inventory is a map of tokenAddress- balance.
-// The balance is stored WITHOUT decimals
-// DONOT directly modify it
-type Inventory struct {
-	lock    *sync.RWMutex
-	Balance map[string]*big.Int
-}
-
-func NewInventory(balance map[string]*big.Int) *Inventory {
-	return &Inventory{
-		lock:    &sync.RWMutex{},
-		Balance: balance,
-	}
-}
-
-// GetBalance returns a copy of balance for the Inventory
-func (i *Inventory) GetBalance(tokenAddress string) *big.Int {
-	i.lock.RLock()
-	defer i.lock.RUnlock()
-	balance, avail := i.Balance[tokenAddress]
-	if !avail {
-		return big.NewInt(0)
-	}
-	return big.NewInt(0).Set(balance)
-}
-
-// UpdateBalance will reduce the Balance to reflect the change in inventory
-// note this delta is amount with Decimal
-func (i *Inventory) UpdateBalance(decreaseTokenAddress, increaseTokenAddress string, decreaseDelta, increaseDelta *big.Int) (*big.Int, *big.Int, error) {
-	i.lock.Lock()
-	defer i.lock.Unlock()
-	decreasedTokenBalance, avail := i.Balance[decreaseTokenAddress]
-	if !avail {
-		return big.NewInt(0), big.NewInt(0), ErrTokenNotAvailable
-	}
-	if decreasedTokenBalance.Cmp(decreaseDelta) < 0 {
-		return big.NewInt(0), big.NewInt(0), ErrNotEnoughInventory
-	}
-	i.Balance[decreaseTokenAddress] = decreasedTokenBalance.Sub(decreasedTokenBalance, decreaseDelta)
-
-	increasedTokenBalance, avail := i.Balance[increaseTokenAddress]
-	if !avail {
-		return big.NewInt(0), big.NewInt(0), ErrTokenNotAvailable
-	}
-	i.Balance[increaseTokenAddress] = increasedTokenBalance.Add(decreasedTokenBalance, increaseDelta)
-	return big.NewInt(0).Set(i.Balance[decreaseTokenAddress]), big.NewInt(0).Set(i.Balance[increaseTokenAddress]), nil
-}
-
@@ -0,0 +1,39 @@
+package rfq
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/blockchain-toolkit/time/durationjson"
+)
+
+const (
+	defaultTimeout = 5 * time.Second
+	bpsDenominator = 10000
+)
+
+type Config struct {
+	// Timeout is shared by the firm quote requests of a swap, retries included
+	Timeout durationjson.Duration `mapstructure:"timeout" json:"timeout,omitempty"`
+	// RetryCount is the number of extra firm quote requests sent to a maker that failed
+	RetryCount    int                   `mapstructure:"retry_count" json:"retry_count,omitempty"`
+	RetryInterval durationjson.Duration `mapstructure:"retry_interval" json:"retry_interval,omitempty"`
+	// ExpiryBuffer is the minimum remaining lifetime of an accepted quote, it covers building and sending the
+	// transaction
+	ExpiryBuffer durationjson.Duration `mapstructure:"expiry_buffer" json:"expiry_buffer,omitempty"`
+	// SlippageTolerance is how much lower than the indicative amount out a firm quote may be, in bps. A firm quote
+	// below the indicative amount out is rejected when it is not set.
+	SlippageTolerance int64 `mapstructure:"slippage_tolerance" json:"slippage_tolerance,omitempty"`
+}
+
+// MinAmountOut returns the lowest firm amount out accepted for the request, the tolerance of the request overrides the
+// configured one when positive and is an allowance below the indicative amount out
+func (c Config) MinAmountOut(req Request) *big.Int {
+	slippage := req.SlippageTolerance
+	if slippage <= 0 {
+		slippage = max(c.SlippageTolerance, 0)
+	}
+
+	minAmountOut := new(big.Int).Mul(req.AmountOut, big.NewInt(bpsDenominator-slippage))
+	return minAmountOut.Quo(minAmountOut, big.NewInt(bpsDenominator))
+}
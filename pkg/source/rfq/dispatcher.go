@@ -0,0 +1,124 @@
+package rfq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/logger"
+)
+
+// Dispatcher asks the maker of a source for a firm quote and checks it is still close enough to the indicative amount
+// out
+type Dispatcher struct {
+	config Config
+	maker  Maker
+	now    func() time.Time
+}
+
+func NewDispatcher(config Config, maker Maker) *Dispatcher {
+	return &Dispatcher{
+		config: config,
+		maker:  maker,
+		now:    time.Now,
+	}
+}
+
+func (d *Dispatcher) Quote(ctx context.Context, req Request) (Quote, error) {
+	if d.maker == nil {
+		return Quote{}, ErrNoMakers
+	}
+	if err := validateRequest(req); err != nil {
+		return Quote{}, err
+	}
+
+	timeout := d.config.Timeout.Duration
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	quote, err := d.firm(ctx, d.maker, req)
+	if err == nil {
+		err = d.validateQuote(req, quote)
+	}
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"maker":    quote.Maker,
+			"tokenIn":  req.TokenIn,
+			"tokenOut": req.TokenOut,
+			"error":    err,
+		}).Errorf("rfq quote rejected")
+		return Quote{}, fmt.Errorf("%w: %w", ErrNoValidQuote, err)
+	}
+
+	return quote, nil
+}
+
+// firm asks a maker for a firm quote, retrying failed requests until the retry count or the timeout is reached
+func (d *Dispatcher) firm(ctx context.Context, maker Maker, req Request) (Quote, error) {
+	var (
+		quote Quote
+		err   error
+	)
+	for attempt := 0; attempt <= d.config.RetryCount; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return Quote{Maker: maker.Name()}, errors.Join(err, ctx.Err())
+			case <-time.After(d.config.RetryInterval.Duration):
+			}
+		}
+
+		quote, err = maker.Firm(ctx, req)
+		if err == nil {
+			// the maker may name the account which signed the quote, e.g. the market maker of a hashflow pool
+			if quote.Maker == "" {
+				quote.Maker = maker.Name()
+			}
+			return quote, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return Quote{Maker: maker.Name()}, err
+}
+
+func (d *Dispatcher) validateQuote(req Request, quote Quote) error {
+	if !quote.Expiry.IsZero() && quote.Expiry.Before(d.now().Add(d.config.ExpiryBuffer.Duration)) {
+		return ErrQuoteExpired
+	}
+
+	if quote.AmountOut == nil {
+		return nil
+	}
+
+	minAmountOut := d.config.MinAmountOut(req)
+	if quote.AmountOut.Cmp(minAmountOut) < 0 {
+		return ErrQuoteBelowSlippage
+	}
+
+	return nil
+}
+
+func validateRequest(req Request) error {
+	if req.TokenIn == "" || req.TokenOut == "" || req.AmountIn == nil || req.AmountIn.Sign() <= 0 ||
+		req.AmountOut == nil || req.AmountOut.Sign() < 0 {
+		return ErrInvalidRequest
+	}
+
+	return nil
+}
+
+func quoteAmountOut(req Request, quote Quote) *big.Int {
+	if quote.AmountOut == nil {
+		return req.AmountOut
+	}
+
+	return quote.AmountOut
+}
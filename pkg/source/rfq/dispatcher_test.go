@@ -0,0 +1,174 @@
+package rfq
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KyberNetwork/blockchain-toolkit/time/durationjson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errMakerDown = errors.New("maker down")
+
+type mockMaker struct {
+	name     string
+	quote    Quote
+	failures int32
+	delay    time.Duration
+	calls    atomic.Int32
+}
+
+func (m *mockMaker) Name() string {
+	return m.name
+}
+
+func (m *mockMaker) Firm(ctx context.Context, _ Request) (Quote, error) {
+	if m.calls.Add(1) <= m.failures {
+		return Quote{}, errMakerDown
+	}
+
+	select {
+	case <-ctx.Done():
+		return Quote{}, ctx.Err()
+	case <-time.After(m.delay):
+	}
+
+	return m.quote, nil
+}
+
+func newTestRequest() Request {
+	return Request{
+		Taker:     "0x0000000000000000000000000000000000000001",
+		TokenIn:   "0x6b175474e89094c44da98b954eedeac495271d0f",
+		TokenOut:  "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+		AmountIn:  big.NewInt(1000000),
+		AmountOut: big.NewInt(1000000),
+	}
+}
+
+func TestDispatcher_Quote_WithinSlippage(t *testing.T) {
+	dispatcher := NewDispatcher(
+		Config{SlippageTolerance: 50},
+		&mockMaker{name: "a", quote: Quote{AmountOut: big.NewInt(995000), Expiry: time.Now().Add(time.Minute)}},
+	)
+
+	quote, err := dispatcher.Quote(context.Background(), newTestRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "a", quote.Maker)
+	assert.Equal(t, big.NewInt(995000), quote.AmountOut)
+}
+
+func TestDispatcher_Quote_Rejected(t *testing.T) {
+	testCases := []struct {
+		name  string
+		quote Quote
+		err   error
+	}{
+		{
+			name:  "below slippage",
+			quote: Quote{AmountOut: big.NewInt(994999)},
+			err:   ErrQuoteBelowSlippage,
+		},
+		{
+			name:  "expired",
+			quote: Quote{AmountOut: big.NewInt(1000000), Expiry: time.Now().Add(5 * time.Second)},
+			err:   ErrQuoteExpired,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dispatcher := NewDispatcher(
+				Config{ExpiryBuffer: durationjson.Duration{Duration: 10 * time.Second}, SlippageTolerance: 50},
+				&mockMaker{name: "a", quote: tc.quote},
+			)
+
+			_, err := dispatcher.Quote(context.Background(), newTestRequest())
+			assert.ErrorIs(t, err, ErrNoValidQuote)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestDispatcher_Quote_IndicativeAmount(t *testing.T) {
+	// a maker without an amount out fills the indicative amount
+	dispatcher := NewDispatcher(Config{}, &mockMaker{name: "a", quote: Quote{Extra: "signature"}})
+
+	quote, err := dispatcher.Quote(context.Background(), newTestRequest())
+	require.NoError(t, err)
+	assert.Nil(t, quote.AmountOut)
+	assert.Equal(t, "signature", quote.Extra)
+}
+
+func TestDispatcher_Quote_WithoutSlippageTolerance(t *testing.T) {
+	// without a tolerance the firm quote may not be below the indicative amount out
+	_, err := NewDispatcher(Config{}, &mockMaker{name: "a", quote: Quote{AmountOut: big.NewInt(999999)}}).
+		Quote(context.Background(), newTestRequest())
+	assert.ErrorIs(t, err, ErrQuoteBelowSlippage)
+
+	quote, err := NewDispatcher(Config{}, &mockMaker{name: "a", quote: Quote{AmountOut: big.NewInt(1000001)}}).
+		Quote(context.Background(), newTestRequest())
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000001), quote.AmountOut)
+}
+
+func TestDispatcher_Quote_MakerOfQuote(t *testing.T) {
+	// the maker of the quote is kept when the handler sets it
+	dispatcher := NewDispatcher(Config{}, &mockMaker{name: "a", quote: Quote{Maker: "mm1", Extra: "signature"}})
+
+	quote, err := dispatcher.Quote(context.Background(), newTestRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "mm1", quote.Maker)
+}
+
+func TestDispatcher_Quote_Retry(t *testing.T) {
+	maker := &mockMaker{name: "a", quote: Quote{AmountOut: big.NewInt(1000000)}, failures: 2}
+
+	_, err := NewDispatcher(Config{RetryCount: 1}, maker).Quote(context.Background(), newTestRequest())
+	assert.ErrorIs(t, err, errMakerDown)
+
+	maker.calls.Store(0)
+	quote, err := NewDispatcher(Config{RetryCount: 2}, maker).Quote(context.Background(), newTestRequest())
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), maker.calls.Load())
+	assert.Equal(t, big.NewInt(1000000), quote.AmountOut)
+}
+
+func TestDispatcher_Quote_Timeout(t *testing.T) {
+	dispatcher := NewDispatcher(
+		Config{Timeout: durationjson.Duration{Duration: 50 * time.Millisecond}},
+		&mockMaker{name: "slow", quote: Quote{AmountOut: big.NewInt(1100000)}, delay: time.Second},
+	)
+
+	start := time.Now()
+	_, err := dispatcher.Quote(context.Background(), newTestRequest())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestDispatcher_Quote_NoMaker(t *testing.T) {
+	_, err := NewDispatcher(Config{}, nil).Quote(context.Background(), newTestRequest())
+	assert.ErrorIs(t, err, ErrNoMakers)
+}
+
+func TestDecodeParams(t *testing.T) {
+	type params struct {
+		Amount string `json:"amount"`
+	}
+
+	decoded, err := DecodeParams[params](params{Amount: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, "1", decoded.Amount)
+
+	decoded, err = DecodeParams[params](map[string]any{"amount": "2"})
+	require.NoError(t, err)
+	assert.Equal(t, "2", decoded.Amount)
+
+	_, err = DecodeParams[params](map[string]any{"amount": 3})
+	assert.ErrorIs(t, err, ErrInvalidParams)
+}
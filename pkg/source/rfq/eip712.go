@@ -0,0 +1,127 @@
+package rfq
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+const (
+	signatureLength = 65
+	// signatureVOffset is added to the recovery id by the wallets, ecrecover expects 27 or 28
+	signatureVOffset = 27
+)
+
+var eip712DomainType = []apitypes.Type{
+	{Name: "name", Type: "string"},
+	{Name: "version", Type: "string"},
+	{Name: "chainId", Type: "uint256"},
+	{Name: "verifyingContract", Type: "address"},
+}
+
+// Domain is the name and version of the EIP-712 domain of a contract, a source uses the ones of its deployed contract
+// for the fields left empty
+type Domain struct {
+	Name    string `mapstructure:"name" json:"name,omitempty"`
+	Version string `mapstructure:"version" json:"version,omitempty"`
+}
+
+// Apply replaces the name and version of the domain of the typed data with the fields of d which are set
+func (d Domain) Apply(typedData apitypes.TypedData) apitypes.TypedData {
+	if d.Name != "" {
+		typedData.Domain.Name = d.Name
+	}
+	if d.Version != "" {
+		typedData.Domain.Version = d.Version
+	}
+
+	return typedData
+}
+
+// NewTypedData builds the EIP-712 typed data of a struct signed for a contract, the domain has the name, version,
+// chainId and verifyingContract fields
+func NewTypedData(
+	name, version string,
+	chainID uint,
+	verifyingContract string,
+	primaryType string,
+	fields []apitypes.Type,
+	message apitypes.TypedDataMessage,
+) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": eip712DomainType,
+			primaryType:    fields,
+		},
+		PrimaryType: primaryType,
+		Domain: apitypes.TypedDataDomain{
+			Name:              name,
+			Version:           version,
+			ChainId:           math.NewHexOrDecimal256(int64(chainID)),
+			VerifyingContract: verifyingContract,
+		},
+		Message: message,
+	}
+}
+
+// HashTypedData returns keccak256("\x19\x01" ‖ domainSeparator ‖ hashStruct(message)), the digest signed by the
+// maker
+func HashTypedData(typedData apitypes.TypedData) (common.Hash, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return common.BytesToHash(hash), nil
+}
+
+// RecoverSigner returns the address that signed the digest, the signature is r ‖ s ‖ v with v being 27 or 28
+func RecoverSigner(digest common.Hash, signature string) (common.Address, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != signatureLength {
+		return common.Address{}, ErrInvalidSignature
+	}
+
+	if sig[64] >= signatureVOffset {
+		sig[64] -= signatureVOffset
+	}
+
+	pubKey, err := crypto.SigToPub(digest.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, ErrInvalidSignature
+	}
+
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
+
+// VerifySigner checks that the typed data is signed by the expected maker or operator
+func VerifySigner(typedData apitypes.TypedData, signature string, expected string) error {
+	digest, err := HashTypedData(typedData)
+	if err != nil {
+		return err
+	}
+
+	signer, err := RecoverSigner(digest, signature)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(signer.Hex(), expected) {
+		return ErrUnexpectedSigner
+	}
+
+	return nil
+}
+
+// HexBytes normalizes the empty bytes fields of an order, EIP-712 encodes them as 0x
+func HexBytes(data string) string {
+	if data == "" {
+		return "0x"
+	}
+
+	return data
+}
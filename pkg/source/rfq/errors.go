@@ -0,0 +1,19 @@
+package rfq
+
+import "errors"
+
+var (
+	ErrNoMakers           = errors.New("no rfq makers")
+	ErrNoValidQuote       = errors.New("no valid rfq quote")
+	ErrInvalidRequest     = errors.New("invalid rfq request")
+	ErrInvalidParams      = errors.New("invalid rfq params")
+	ErrQuoteExpired       = errors.New("rfq quote expires too soon")
+	ErrQuoteBelowSlippage = errors.New("rfq quote amount out is below the slippage tolerance")
+	ErrQuoteMismatch      = errors.New("rfq quote does not match the request")
+	ErrInvalidSignature   = errors.New("invalid rfq signature")
+	ErrUnexpectedSigner   = errors.New("rfq quote is not signed by the expected signer")
+
+	ErrEmptyPriceLevels      = errors.New("empty price levels")
+	ErrInsufficientLiquidity = errors.New("insufficient liquidity")
+	ErrInvalidPriceLevel     = errors.New("invalid price level")
+)
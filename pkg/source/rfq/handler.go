@@ -0,0 +1,61 @@
+package rfq
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+)
+
+// Handler implements pool.IPoolRFQ on top of a dispatcher, the source only has to parse its params
+type Handler struct {
+	dispatcher *Dispatcher
+	parse      RequestParser
+}
+
+func NewHandler(config Config, parse RequestParser, maker Maker) *Handler {
+	return &Handler{
+		dispatcher: NewDispatcher(config, maker),
+		parse:      parse,
+	}
+}
+
+func (h *Handler) RFQ(ctx context.Context, recipient string, params any) (pool.RFQResult, error) {
+	req, err := h.parse(recipient, params)
+	if err != nil {
+		return pool.RFQResult{}, err
+	}
+
+	quote, err := h.dispatcher.Quote(ctx, req)
+	if err != nil {
+		return pool.RFQResult{}, err
+	}
+
+	return pool.RFQResult{
+		NewAmountOut: quote.AmountOut,
+		Extra:        quote.Extra,
+	}, nil
+}
+
+// DecodeParams returns the params as T, the swap info is used as is when it was not serialized on the way
+func DecodeParams[T any](params any) (T, error) {
+	switch p := params.(type) {
+	case T:
+		return p, nil
+	case *T:
+		if p != nil {
+			return *p, nil
+		}
+	}
+
+	var decoded T
+	paramsByteData, err := json.Marshal(params)
+	if err != nil {
+		return decoded, err
+	}
+	if err = json.Unmarshal(paramsByteData, &decoded); err != nil {
+		return decoded, ErrInvalidParams
+	}
+
+	return decoded, nil
+}
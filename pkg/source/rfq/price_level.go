@@ -0,0 +1,133 @@
+package rfq
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// PriceLevel is an exact level of the order book of a maker, Amount of token in is sold at Price. The amounts are in
+// tokens, not in wei.
+type PriceLevel struct {
+	Price  *big.Rat `json:"price"`
+	Amount *big.Rat `json:"amount"`
+}
+
+// UnmarshalJSON reads the price and the amount from strings, as big.Rat marshals itself, and from numbers, as the
+// extras written before the levels were exact
+func (l *PriceLevel) UnmarshalJSON(data []byte) error {
+	var level struct {
+		Price  json.RawMessage `json:"price"`
+		Amount json.RawMessage `json:"amount"`
+	}
+	if err := json.Unmarshal(data, &level); err != nil {
+		return err
+	}
+
+	price, err := unmarshalRat(level.Price)
+	if err != nil {
+		return err
+	}
+	amount, err := unmarshalRat(level.Amount)
+	if err != nil {
+		return err
+	}
+	l.Price, l.Amount = price, amount
+
+	return nil
+}
+
+func unmarshalRat(data json.RawMessage) (*big.Rat, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	text := string(data)
+	if data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return nil, err
+		}
+	}
+
+	rat, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPriceLevel, data)
+	}
+
+	return rat, nil
+}
+
+// ToDecimalAmount converts an amount in wei into an exact amount of tokens
+func ToDecimalAmount(amount *big.Int, decimals uint8) *big.Rat {
+	return new(big.Rat).SetFrac(amount, bignumber.TenPowInt(decimals))
+}
+
+// ToWeiAmount converts an exact amount of tokens into wei, rounding down in favour of the maker so the quote never
+// exceeds the amount the maker signs
+func ToWeiAmount(amount *big.Rat, decimals uint8) *big.Int {
+	wei := new(big.Int).Mul(amount.Num(), bignumber.TenPowInt(decimals))
+	return wei.Quo(wei, amount.Denom())
+}
+
+// GetAmountOut fills amountIn from the front of the order book, every level is filled at its price
+func GetAmountOut(amountIn *big.Rat, priceLevels []PriceLevel) (*big.Rat, error) {
+	if len(priceLevels) == 0 {
+		return nil, ErrEmptyPriceLevels
+	}
+
+	amountOut := new(big.Rat)
+	amountInLeft := new(big.Rat).Set(amountIn)
+	for _, level := range priceLevels {
+		if amountInLeft.Sign() == 0 {
+			break
+		}
+
+		swappableAmount := level.Amount
+		if swappableAmount.Cmp(amountInLeft) > 0 {
+			swappableAmount = amountInLeft
+		}
+		amountOut.Add(amountOut, new(big.Rat).Mul(swappableAmount, level.Price))
+		amountInLeft.Sub(amountInLeft, swappableAmount)
+	}
+
+	if amountInLeft.Sign() > 0 {
+		return nil, ErrInsufficientLiquidity
+	}
+
+	return amountOut, nil
+}
+
+// GetTotalAmountOut returns the amount out of the whole order book
+func GetTotalAmountOut(priceLevels []PriceLevel) *big.Rat {
+	amountOut := new(big.Rat)
+	for _, level := range priceLevels {
+		amountOut.Add(amountOut, new(big.Rat).Mul(level.Amount, level.Price))
+	}
+
+	return amountOut
+}
+
+// FillPriceLevels removes amountIn from the front of the order book. The levels are copied instead of modified
+// because they can be shared with other simulators.
+func FillPriceLevels(amountIn *big.Rat, priceLevels []PriceLevel) []PriceLevel {
+	levels := make([]PriceLevel, 0, len(priceLevels))
+	amountInLeft := new(big.Rat).Set(amountIn)
+	for _, level := range priceLevels {
+		switch {
+		case amountInLeft.Sign() == 0:
+			levels = append(levels, level)
+		case level.Amount.Cmp(amountInLeft) > 0:
+			levels = append(levels, PriceLevel{
+				Price:  level.Price,
+				Amount: new(big.Rat).Sub(level.Amount, amountInLeft),
+			})
+			amountInLeft.SetInt64(0)
+		default:
+			amountInLeft.Sub(amountInLeft, level.Amount)
+		}
+	}
+
+	return levels
+}
@@ -0,0 +1,208 @@
+package rfq
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAmountOut(t *testing.T) {
+	type args struct {
+		amountIn    *big.Rat
+		priceLevels []PriceLevel
+	}
+	tests := []struct {
+		name              string
+		args              args
+		expectedAmountOut *big.Rat
+		expectedErr       error
+	}{
+		{
+			name: "it should return error when price levels is empty",
+			args: args{
+				amountIn:    big.NewRat(1, 1),
+				priceLevels: []PriceLevel{},
+			},
+			expectedAmountOut: nil,
+			expectedErr:       ErrEmptyPriceLevels,
+		},
+		{
+			name: "it should return insufficient liquidity error when the requested amount is greater than available amount in price levels",
+			args: args{
+				amountIn: big.NewRat(4, 1),
+				priceLevels: []PriceLevel{
+					{
+						Price:  big.NewRat(100, 1),
+						Amount: big.NewRat(1, 1),
+					},
+					{
+						Price:  big.NewRat(99, 1),
+						Amount: big.NewRat(2, 1),
+					},
+				},
+			},
+			expectedAmountOut: nil,
+			expectedErr:       ErrInsufficientLiquidity,
+		},
+		{
+			name: "it should return correct amount out when fully filled",
+			args: args{
+				amountIn: big.NewRat(1, 1),
+				priceLevels: []PriceLevel{
+					{
+						Price:  big.NewRat(100, 1),
+						Amount: big.NewRat(1, 1),
+					},
+				},
+			},
+			expectedAmountOut: big.NewRat(100, 1),
+			expectedErr:       nil,
+		},
+		{
+			name: "it should return correct amount out when partially filled",
+			args: args{
+				amountIn: big.NewRat(2, 1),
+				priceLevels: []PriceLevel{
+					{
+						Price:  big.NewRat(100, 1),
+						Amount: big.NewRat(1, 1),
+					},
+					{
+						Price:  big.NewRat(99, 1),
+						Amount: big.NewRat(2, 1),
+					},
+				},
+			},
+			expectedAmountOut: big.NewRat(199, 1),
+			expectedErr:       nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amountOut, err := GetAmountOut(tt.args.amountIn, tt.args.priceLevels)
+			assert.Equal(t, tt.expectedErr, err)
+
+			if amountOut != nil {
+				assert.Equal(t, tt.expectedAmountOut.Cmp(amountOut), 0)
+			}
+		})
+	}
+}
+
+func TestFillPriceLevels(t *testing.T) {
+	type args struct {
+		amountIn    *big.Rat
+		priceLevels []PriceLevel
+	}
+	tests := []struct {
+		name                string
+		args                args
+		expectedPriceLevels []PriceLevel
+	}{
+		{
+			name: "it should do nothing when price levels is empty",
+			args: args{
+				amountIn:    big.NewRat(1, 1),
+				priceLevels: []PriceLevel{},
+			},
+			expectedPriceLevels: []PriceLevel{},
+		},
+		{
+			name: "it should return correct new price levels when fully filled",
+			args: args{
+				amountIn: big.NewRat(1, 1),
+				priceLevels: []PriceLevel{
+					{
+						Price:  big.NewRat(100, 1),
+						Amount: big.NewRat(1, 1),
+					},
+				},
+			},
+			expectedPriceLevels: []PriceLevel{},
+		},
+		{
+			name: "it should return correct new price levels when the amountIn is greater than the amount available in the single price level",
+			args: args{
+				amountIn: big.NewRat(2, 1),
+				priceLevels: []PriceLevel{
+					{
+						Price:  big.NewRat(100, 1),
+						Amount: big.NewRat(1, 1),
+					},
+				},
+			},
+			expectedPriceLevels: []PriceLevel{},
+		},
+		{
+			name: "it should return correct new price levels when the amountIn is greater than the amount available in the all price levels",
+			args: args{
+				amountIn: big.NewRat(5, 1),
+				priceLevels: []PriceLevel{
+					{
+						Price:  big.NewRat(100, 1),
+						Amount: big.NewRat(1, 1),
+					},
+					{
+						Price:  big.NewRat(99, 1),
+						Amount: big.NewRat(2, 1),
+					},
+				},
+			},
+			expectedPriceLevels: []PriceLevel{},
+		},
+		{
+			name: "it should return correct new price levels when partially filled",
+			args: args{
+				amountIn: big.NewRat(2, 1),
+				priceLevels: []PriceLevel{
+					{
+						Price:  big.NewRat(100, 1),
+						Amount: big.NewRat(1, 1),
+					},
+					{
+						Price:  big.NewRat(99, 1),
+						Amount: big.NewRat(2, 1),
+					},
+				},
+			},
+			expectedPriceLevels: []PriceLevel{
+				{
+					Price:  big.NewRat(99, 1),
+					Amount: big.NewRat(1, 1),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			priceLevels := make([]PriceLevel, len(tt.args.priceLevels))
+			for i, level := range tt.args.priceLevels {
+				priceLevels[i] = PriceLevel{Price: new(big.Rat).Set(level.Price), Amount: new(big.Rat).Set(level.Amount)}
+			}
+
+			newPriceLevels := FillPriceLevels(tt.args.amountIn, tt.args.priceLevels)
+
+			assert.ElementsMatch(t, tt.expectedPriceLevels, newPriceLevels)
+			// the levels can be shared with other simulators
+			assert.Equal(t, priceLevels, tt.args.priceLevels)
+		})
+	}
+}
+
+func TestPriceLevel_UnmarshalJSON(t *testing.T) {
+	var levels []PriceLevel
+	require.NoError(t, json.Unmarshal([]byte(`[{"price":1850.25,"amount":1e-3},{"price":"1/3","amount":"2"}]`), &levels))
+
+	assert.Equal(t, []PriceLevel{
+		{Price: big.NewRat(7401, 4), Amount: big.NewRat(1, 1000)},
+		{Price: big.NewRat(1, 3), Amount: big.NewRat(2, 1)},
+	}, levels)
+
+	var level PriceLevel
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"price":"abc","amount":1}`), &level), ErrInvalidPriceLevel)
+}
@@ -0,0 +1,41 @@
+package rfq
+
+import (
+	"context"
+	"math/big"
+	"time"
+)
+
+// Request is a firm quote request for a swap already quoted by CalcAmountOut
+type Request struct {
+	// Taker receives the maker asset
+	Taker    string
+	TokenIn  string
+	TokenOut string
+	AmountIn *big.Int
+	// AmountOut is the indicative amount returned by CalcAmountOut, firm quotes are checked against it
+	AmountOut *big.Int
+	// SlippageTolerance overrides the configured tolerance when positive, in bps
+	SlippageTolerance int64
+	// Params is the swap info of the source, the maker decodes it with DecodeParams
+	Params any
+}
+
+// Quote is a firm quote of a maker
+type Quote struct {
+	Maker string
+	// AmountOut is the amount the maker commits to, nil when the maker fills the indicative amount
+	AmountOut *big.Int
+	// Expiry is when the quote stops being fillable, zero when it does not expire
+	Expiry time.Time
+	Extra  any
+}
+
+// Maker is the source the dispatcher asks for firm quotes
+type Maker interface {
+	Name() string
+	Firm(ctx context.Context, req Request) (Quote, error)
+}
+
+// RequestParser builds the typed request of a source from the params given to pool.IPoolRFQ
+type RequestParser func(recipient string, params any) (Request, error)
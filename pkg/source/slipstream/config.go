@@ -0,0 +1,9 @@
+package slipstream
+
+type Config struct {
+	DexID              string `json:"dexID"`
+	FactoryAddress     string `json:"factoryAddress"`
+	NewPoolLimit       int    `json:"newPoolLimit"`
+	SubgraphAPI        string `json:"subgraphAPI"`
+	AllowSubgraphError bool   `json:"allowSubgraphError"`
+}
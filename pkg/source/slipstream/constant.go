@@ -0,0 +1,37 @@
+package slipstream
+
+import "math/big"
+
+const (
+	DexTypeSlipstream = "slipstream"
+)
+
+const (
+	factoryMethodAllPoolsLength = "allPoolsLength"
+	factoryMethodAllPools       = "allPools"
+	factoryMethodGetSwapFee     = "getSwapFee"
+	factoryMethodGetUnstakedFee = "getUnstakedFee"
+
+	poolMethodLiquidity       = "liquidity"
+	poolMethodSlot0           = "slot0"
+	poolMethodStakedLiquidity = "stakedLiquidity"
+	poolMethodTickSpacing     = "tickSpacing"
+	poolMethodTicks           = "ticks"
+	poolMethodToken0          = "token0"
+	poolMethodToken1          = "token1"
+
+	erc20MethodBalanceOf = "balanceOf"
+)
+
+const (
+	defaultTokenWeight = 50
+	zeroString         = "0"
+
+	// tickBatchSize is the number of ticks to get the staked liquidity net of in a multicall
+	tickBatchSize = 500
+)
+
+var (
+	// feeDenominator is the denominator of the swap fee and the unstaked fee, in pips
+	feeDenominator = big.NewInt(1_000_000)
+)
@@ -0,0 +1,12 @@
+package slipstream
+
+import _ "embed"
+
+//go:embed abis/CLPool.json
+var poolABIJson []byte
+
+//go:embed abis/CLFactory.json
+var factoryABIJson []byte
+
+//go:embed abis/ERC20.json
+var erc20ABIJson []byte
@@ -0,0 +1,7 @@
+package slipstream
+
+import "errors"
+
+var (
+	ErrInvalidSwapInfo = errors.New("invalid swap info")
+)
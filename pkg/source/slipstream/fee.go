@@ -0,0 +1,134 @@
+package slipstream
+
+import (
+	"math/big"
+
+	v3Utils "github.com/daoleno/uniswapv3-sdk/utils"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+)
+
+// getSwapFees walks the steps of an exact input swap like the pool does and returns the fee charged by the swap, split
+// between the unstaked liquidity providers and the gauge by the staked liquidity in range at every step.
+// https://github.com/velodrome-finance/slipstream/blob/main/contracts/core/CLPool.sol#L640
+func (p *PoolSimulator) getSwapFees(
+	zeroForOne bool,
+	amountIn *big.Int,
+	sqrtPriceLimitX96 *big.Int,
+) (feeAmount, unstakedFeeAmount, stakedFeeAmount *big.Int, err error) {
+	feeAmount, unstakedFeeAmount, stakedFeeAmount = new(big.Int), new(big.Int), new(big.Int)
+
+	var (
+		amountRemaining = new(big.Int).Set(amountIn)
+		sqrtPriceX96    = p.V3Pool.SqrtRatioX96
+		tick            = p.V3Pool.TickCurrent
+		liquidity       = p.V3Pool.Liquidity
+		stakedLiquidity = p.stakedLiquidity
+	)
+	for amountRemaining.Sign() != 0 && sqrtPriceX96.Cmp(sqrtPriceLimitX96) != 0 {
+		sqrtPriceStartX96 := sqrtPriceX96
+
+		tickNext, initialized, err := p.V3Pool.TickDataProvider.NextInitializedTickIndex(tick, zeroForOne)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		tickNext = max(min(tickNext, v3Utils.MaxTick), v3Utils.MinTick)
+
+		sqrtPriceNextX96, err := v3Utils.GetSqrtRatioAtTick(tickNext)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		sqrtPriceTargetX96 := sqrtPriceNextX96
+		if zeroForOne && sqrtPriceNextX96.Cmp(sqrtPriceLimitX96) < 0 ||
+			!zeroForOne && sqrtPriceNextX96.Cmp(sqrtPriceLimitX96) > 0 {
+			sqrtPriceTargetX96 = sqrtPriceLimitX96
+		}
+
+		var stepAmountIn, stepFeeAmount *big.Int
+		sqrtPriceX96, stepAmountIn, _, stepFeeAmount, err = v3Utils.ComputeSwapStep(
+			sqrtPriceX96, sqrtPriceTargetX96, liquidity, amountRemaining, p.V3Pool.Fee,
+		)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		amountRemaining.Sub(amountRemaining, stepAmountIn).Sub(amountRemaining, stepFeeAmount)
+
+		stepUnstakedFeeAmount, stepStakedFeeAmount := calculateFees(stepFeeAmount, liquidity, stakedLiquidity, p.unstakedFee)
+		feeAmount.Add(feeAmount, stepFeeAmount)
+		unstakedFeeAmount.Add(unstakedFeeAmount, stepUnstakedFeeAmount)
+		stakedFeeAmount.Add(stakedFeeAmount, stepStakedFeeAmount)
+
+		if sqrtPriceX96.Cmp(sqrtPriceNextX96) == 0 {
+			if initialized {
+				tickInfo, err := p.V3Pool.TickDataProvider.GetTick(tickNext)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+				liquidityNet, stakedLiquidityNet := tickInfo.LiquidityNet, p.stakedLiquidityNets[tickNext]
+				if stakedLiquidityNet == nil {
+					stakedLiquidityNet = new(big.Int)
+				}
+				if zeroForOne {
+					liquidityNet, stakedLiquidityNet = new(big.Int).Neg(liquidityNet), new(big.Int).Neg(stakedLiquidityNet)
+				}
+				liquidity = v3Utils.AddDelta(liquidity, liquidityNet)
+				stakedLiquidity = boundStakedLiquidity(new(big.Int).Add(stakedLiquidity, stakedLiquidityNet), liquidity)
+			}
+			if zeroForOne {
+				tick = tickNext - 1
+			} else {
+				tick = tickNext
+			}
+		} else if sqrtPriceX96.Cmp(sqrtPriceStartX96) != 0 {
+			if tick, err = v3Utils.GetTickAtSqrtRatio(sqrtPriceX96); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
+
+	return feeAmount, unstakedFeeAmount, stakedFeeAmount, nil
+}
+
+// calculateFees splits the fee of a swap step between the unstaked liquidity providers and the gauge, the gauge gets
+// the share of the staked liquidity, rounded up, and the unstaked fee of the share of the unstaked liquidity.
+// https://github.com/velodrome-finance/slipstream/blob/main/contracts/core/CLPool.sol#L866
+func calculateFees(feeAmount, liquidity, stakedLiquidity *big.Int, unstakedFee uint32) (*big.Int, *big.Int) {
+	if stakedLiquidity.Sign() == 0 {
+		return applyUnstakedFees(feeAmount, unstakedFee)
+	}
+
+	if liquidity.Cmp(stakedLiquidity) == 0 {
+		return new(big.Int), new(big.Int).Set(feeAmount)
+	}
+
+	stakedFeeAmount := mulDivRoundingUp(feeAmount, stakedLiquidity, liquidity)
+
+	unstakedFeeAmount, unstakedStakedFeeAmount := applyUnstakedFees(new(big.Int).Sub(feeAmount, stakedFeeAmount), unstakedFee)
+
+	return unstakedFeeAmount, stakedFeeAmount.Add(stakedFeeAmount, unstakedStakedFeeAmount)
+}
+
+// boundStakedLiquidity keeps the staked liquidity within [0, liquidity], the tracked staked liquidity nets may lag the
+// liquidity nets
+func boundStakedLiquidity(stakedLiquidity, liquidity *big.Int) *big.Int {
+	if stakedLiquidity.Sign() < 0 {
+		return stakedLiquidity.SetInt64(0)
+	}
+	if stakedLiquidity.Cmp(liquidity) > 0 {
+		return stakedLiquidity.Set(liquidity)
+	}
+	return stakedLiquidity
+}
+
+func applyUnstakedFees(feeAmount *big.Int, unstakedFee uint32) (*big.Int, *big.Int) {
+	stakedFeeAmount := mulDivRoundingUp(feeAmount, big.NewInt(int64(unstakedFee)), feeDenominator)
+	return new(big.Int).Sub(feeAmount, stakedFeeAmount), stakedFeeAmount
+}
+
+func mulDivRoundingUp(a, b, denominator *big.Int) *big.Int {
+	result, remainder := new(big.Int).QuoRem(new(big.Int).Mul(a, b), denominator, new(big.Int))
+	if remainder.Sign() > 0 {
+		result.Add(result, bignumber.One)
+	}
+	return result
+}
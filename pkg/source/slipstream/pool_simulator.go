@@ -0,0 +1,122 @@
+package slipstream
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/daoleno/uniswapv3-sdk/constants"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/uniswapv3"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+// PoolSimulator swaps through the ticks of the pool with the Uniswap V3 simulator, with the tick spacing of the pool
+// and the fee of the swap fee module. The fee of every step of a swap is split between the unstaked liquidity
+// providers and the gauge by the staked liquidity in range.
+type PoolSimulator struct {
+	*uniswapv3.PoolSimulator
+
+	unstakedFee         uint32
+	stakedLiquidity     *big.Int
+	stakedLiquidityNets map[int]*big.Int
+}
+
+func NewPoolSimulator(entityPool entity.Pool, chainID valueobject.ChainID) (*PoolSimulator, error) {
+	var extra Extra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	var staticExtra StaticExtra
+	if err := json.Unmarshal([]byte(entityPool.StaticExtra), &staticExtra); err != nil {
+		return nil, err
+	}
+
+	uniswapV3PoolSimulator, err := uniswapv3.NewPoolSimulatorWithTickSpacing(
+		entityPool, chainID, constants.FeeAmount(extra.Fee), staticExtra.TickSpacing,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stakedLiquidity := extra.StakedLiquidity
+	if stakedLiquidity == nil {
+		stakedLiquidity = new(big.Int)
+	}
+
+	return &PoolSimulator{
+		PoolSimulator:       uniswapV3PoolSimulator,
+		unstakedFee:         extra.UnstakedFee,
+		stakedLiquidity:     stakedLiquidity,
+		stakedLiquidityNets: extra.StakedLiquidityNets,
+	}, nil
+}
+
+func (p *PoolSimulator) CalcAmountOut(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	result, err := p.PoolSimulator.CalcAmountOut(tokenAmountIn, tokenOut)
+	if err != nil {
+		return result, err
+	}
+
+	uniV3SwapInfo, ok := result.SwapInfo.(uniswapv3.UniV3SwapInfo)
+	if !ok {
+		return &pool.CalcAmountOutResult{}, ErrInvalidSwapInfo
+	}
+
+	zeroForOne := !strings.EqualFold(tokenOut, p.V3Pool.Token0.Address.String())
+	feeAmount, unstakedFeeAmount, stakedFeeAmount, err := p.getSwapFees(
+		zeroForOne, tokenAmountIn.Amount, p.GetSqrtPriceLimit(zeroForOne),
+	)
+	if err != nil {
+		return &pool.CalcAmountOutResult{}, err
+	}
+
+	result.Fee = &pool.TokenAmount{
+		Token:  tokenAmountIn.Token,
+		Amount: feeAmount,
+	}
+	result.SwapInfo = SwapInfo{
+		UniV3SwapInfo:     uniV3SwapInfo,
+		UnstakedFeeAmount: unstakedFeeAmount,
+		StakedFeeAmount:   stakedFeeAmount,
+	}
+
+	return result, nil
+}
+
+func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	swapInfo, ok := params.SwapInfo.(SwapInfo)
+	if !ok {
+		logger.Warn("failed to UpdateBalance for Slipstream pool, wrong swapInfo type")
+		return
+	}
+
+	tickBefore := p.V3Pool.TickCurrent
+
+	params.SwapInfo = swapInfo.UniV3SwapInfo
+	p.PoolSimulator.UpdateBalance(params)
+
+	p.crossStakedLiquidity(tickBefore, p.V3Pool.TickCurrent)
+}
+
+// crossStakedLiquidity updates the staked liquidity in range by the ticks crossed by a swap, the ticks in
+// (tickBefore, tickAfter] are crossed from left to right and the ticks in (tickAfter, tickBefore] from right to left.
+func (p *PoolSimulator) crossStakedLiquidity(tickBefore, tickAfter int) {
+	stakedLiquidity := new(big.Int).Set(p.stakedLiquidity)
+	for tick, stakedLiquidityNet := range p.stakedLiquidityNets {
+		if tickBefore < tick && tick <= tickAfter {
+			stakedLiquidity.Add(stakedLiquidity, stakedLiquidityNet)
+		} else if tickAfter < tick && tick <= tickBefore {
+			stakedLiquidity.Sub(stakedLiquidity, stakedLiquidityNet)
+		}
+	}
+
+	p.stakedLiquidity = boundStakedLiquidity(stakedLiquidity, p.V3Pool.Liquidity)
+}
@@ -0,0 +1,140 @@
+package slipstream
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/uniswapv3"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+const (
+	token0 = "0x4200000000000000000000000000000000000006"
+	token1 = "0x833589fcd6e7a6e4bf26fa1ea5eca2a0b8d5d5bb"
+)
+
+// newTestEntityPool returns a pool at tick 0 with 1e24 liquidity in [-600, 600], half of it staked, a fee of 0.05% and
+// an unstaked fee of 10%.
+func newTestEntityPool(t *testing.T) entity.Pool {
+	liquidity := bignumber.NewBig10("1000000000000000000000000")
+	halfLiquidity := bignumber.NewBig10("500000000000000000000000")
+
+	extraBytes, err := json.Marshal(Extra{
+		Extra: uniswapv3.Extra{
+			Liquidity:    liquidity,
+			SqrtPriceX96: bignumber.NewBig10("79228162514264337593543950336"),
+			Tick:         big.NewInt(0),
+			Ticks: []uniswapv3.Tick{
+				{Index: -600, LiquidityGross: liquidity, LiquidityNet: liquidity},
+				{Index: 600, LiquidityGross: liquidity, LiquidityNet: new(big.Int).Neg(liquidity)},
+			},
+		},
+		Fee:             500,
+		UnstakedFee:     100000,
+		StakedLiquidity: halfLiquidity,
+		StakedLiquidityNets: map[int]*big.Int{
+			-600: halfLiquidity,
+			600:  new(big.Int).Neg(halfLiquidity),
+		},
+	})
+	require.NoError(t, err)
+
+	return entity.Pool{
+		Address:  "0xb2cc224c1c9fee385f8ad6a55b4d94e92359dc59",
+		Exchange: string(valueobject.ExchangeVelodromeSlipstream),
+		Type:     DexTypeSlipstream,
+		SwapFee:  500,
+		Reserves: entity.PoolReserves{"100000000000000000000000", "100000000000000000000000"},
+		Tokens: []*entity.PoolToken{
+			{Address: token0, Decimals: 18, Swappable: true},
+			{Address: token1, Decimals: 18, Swappable: true},
+		},
+		Extra:       string(extraBytes),
+		StaticExtra: "{\"tickSpacing\":200}",
+	}
+}
+
+func TestPoolSimulator_CalcAmountOut(t *testing.T) {
+	entityPool := newTestEntityPool(t)
+	simulator, err := NewPoolSimulator(entityPool, valueobject.ChainIDOptimism)
+	require.NoError(t, err)
+
+	tokenAmountIn := pool.TokenAmount{Token: token0, Amount: bignumber.NewBig10("1000000000000000000")}
+	result, err := simulator.CalcAmountOut(tokenAmountIn, token1)
+	require.NoError(t, err)
+
+	// the amount out is the one of a Uniswap V3 pool with the same fee and ticks
+	uniswapV3Simulator, err := uniswapv3.NewPoolSimulator(entityPool, valueobject.ChainIDOptimism)
+	require.NoError(t, err)
+	expected, err := uniswapV3Simulator.CalcAmountOut(tokenAmountIn, token1)
+	require.NoError(t, err)
+	assert.Equal(t, expected.TokenAmountOut.Amount, result.TokenAmountOut.Amount)
+
+	// the gauge gets the fee of the staked half and 10% of the fee of the unstaked half
+	assert.Equal(t, "500000000000000", result.Fee.Amount.String())
+	swapInfo, ok := result.SwapInfo.(SwapInfo)
+	require.True(t, ok)
+	assert.Equal(t, "225000000000000", swapInfo.UnstakedFeeAmount.String())
+	assert.Equal(t, "275000000000000", swapInfo.StakedFeeAmount.String())
+}
+
+func TestPoolSimulator_UpdateBalance(t *testing.T) {
+	simulator, err := NewPoolSimulator(newTestEntityPool(t), valueobject.ChainIDOptimism)
+	require.NoError(t, err)
+
+	// swap token1 in up to the last tick, which removes all the liquidity and the staked liquidity
+	tokenAmountIn := pool.TokenAmount{Token: token1, Amount: bignumber.NewBig10("1000000000000000000000000")}
+	result, err := simulator.CalcAmountOut(tokenAmountIn, token0)
+	require.NoError(t, err)
+
+	// the swap stops at the last tick, so it is charged less than 0.05% of the amount in
+	fullFeeAmount := bignumber.NewBig10("500000000000000000000")
+	assert.Equal(t, -1, result.Fee.Amount.Cmp(fullFeeAmount))
+	swapInfo, ok := result.SwapInfo.(SwapInfo)
+	require.True(t, ok)
+	assert.Equal(t, result.Fee.Amount, new(big.Int).Add(swapInfo.UnstakedFeeAmount, swapInfo.StakedFeeAmount))
+
+	simulator.UpdateBalance(pool.UpdateBalanceParams{
+		TokenAmountIn:  tokenAmountIn,
+		TokenAmountOut: *result.TokenAmountOut,
+		Fee:            *result.Fee,
+		SwapInfo:       result.SwapInfo,
+	})
+
+	assert.Equal(t, 600, simulator.V3Pool.TickCurrent)
+	assert.Equal(t, "0", simulator.V3Pool.Liquidity.String())
+	assert.Equal(t, "0", simulator.stakedLiquidity.String())
+}
+
+func TestCalculateFees(t *testing.T) {
+	feeAmount := big.NewInt(1000)
+
+	testCases := []struct {
+		name                       string
+		liquidity, stakedLiquidity int64
+		expectedUnstakedFeeAmount  int64
+		expectedStakedFeeAmount    int64
+	}{
+		{name: "only staked liquidity", liquidity: 100, stakedLiquidity: 100, expectedStakedFeeAmount: 1000},
+		{name: "only unstaked liquidity", liquidity: 100, expectedUnstakedFeeAmount: 900, expectedStakedFeeAmount: 100},
+		{name: "a quarter staked", liquidity: 100, stakedLiquidity: 25, expectedUnstakedFeeAmount: 675, expectedStakedFeeAmount: 325},
+		// the staked share of 333.3 is rounded up to 334, then 66.6 of the unstaked 666 up to 67
+		{name: "a third staked", liquidity: 3, stakedLiquidity: 1, expectedUnstakedFeeAmount: 599, expectedStakedFeeAmount: 401},
+		{name: "no liquidity", expectedUnstakedFeeAmount: 900, expectedStakedFeeAmount: 100},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			unstakedFeeAmount, stakedFeeAmount := calculateFees(feeAmount, big.NewInt(tc.liquidity), big.NewInt(tc.stakedLiquidity), 100000)
+			assert.Equal(t, tc.expectedUnstakedFeeAmount, unstakedFeeAmount.Int64())
+			assert.Equal(t, tc.expectedStakedFeeAmount, stakedFeeAmount.Int64())
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package slipstream
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/uniswapv3"
+)
+
+type PoolTracker struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+
+	// uniswapV3PoolTracker gets the ticks of the pools from the subgraph, which has the schema of Uniswap V3
+	uniswapV3PoolTracker *uniswapv3.PoolTracker
+}
+
+func NewPoolTracker(
+	cfg *Config,
+	ethrpcClient *ethrpc.Client,
+) (*PoolTracker, error) {
+	uniswapV3PoolTracker, err := uniswapv3.NewPoolTracker(&uniswapv3.Config{
+		DexID:              cfg.DexID,
+		SubgraphAPI:        cfg.SubgraphAPI,
+		AllowSubgraphError: cfg.AllowSubgraphError,
+	}, ethrpcClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PoolTracker{
+		config:               cfg,
+		ethrpcClient:         ethrpcClient,
+		uniswapV3PoolTracker: uniswapV3PoolTracker,
+	}, nil
+}
+
+func (d *PoolTracker) GetNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	_ pool.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	logger.WithFields(logger.Fields{
+		"address": p.Address,
+	}).Infof("[%s] Start getting new state of pool", p.Type)
+
+	var (
+		liquidity, stakedLiquidity *big.Int
+		slot0                      Slot0
+		fee, unstakedFee           *big.Int
+		reserve0, reserve1         *big.Int
+	)
+
+	// the swap fee and the unstaked fee are given by the fee modules of the factory on each swap
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+	calls.AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: p.Address,
+		Method: poolMethodLiquidity,
+	}, []interface{}{&liquidity})
+	calls.AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: p.Address,
+		Method: poolMethodStakedLiquidity,
+	}, []interface{}{&stakedLiquidity})
+	calls.AddCall(&ethrpc.Call{
+		ABI:    poolABI,
+		Target: p.Address,
+		Method: poolMethodSlot0,
+	}, []interface{}{&slot0})
+	calls.AddCall(&ethrpc.Call{
+		ABI:    factoryABI,
+		Target: d.config.FactoryAddress,
+		Method: factoryMethodGetSwapFee,
+		Params: []interface{}{common.HexToAddress(p.Address)},
+	}, []interface{}{&fee})
+	calls.AddCall(&ethrpc.Call{
+		ABI:    factoryABI,
+		Target: d.config.FactoryAddress,
+		Method: factoryMethodGetUnstakedFee,
+		Params: []interface{}{common.HexToAddress(p.Address)},
+	}, []interface{}{&unstakedFee})
+	calls.AddCall(&ethrpc.Call{
+		ABI:    erc20ABI,
+		Target: p.Tokens[0].Address,
+		Method: erc20MethodBalanceOf,
+		Params: []interface{}{common.HexToAddress(p.Address)},
+	}, []interface{}{&reserve0})
+	calls.AddCall(&ethrpc.Call{
+		ABI:    erc20ABI,
+		Target: p.Tokens[1].Address,
+		Method: erc20MethodBalanceOf,
+		Params: []interface{}{common.HexToAddress(p.Address)},
+	}, []interface{}{&reserve1})
+
+	if _, err := calls.Aggregate(); err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to aggregate to get pool data")
+		return entity.Pool{}, err
+	}
+
+	ticks, err := d.uniswapV3PoolTracker.GetPoolTicks(ctx, p.Address)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to query subgraph for pool ticks")
+		return entity.Pool{}, err
+	}
+
+	stakedLiquidityNets, err := d.getStakedLiquidityNets(ctx, p.Address, ticks)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to get staked liquidity of ticks")
+		return entity.Pool{}, err
+	}
+
+	extraBytes, err := json.Marshal(Extra{
+		Extra: uniswapv3.Extra{
+			Liquidity:    liquidity,
+			SqrtPriceX96: slot0.SqrtPriceX96,
+			Tick:         slot0.Tick,
+			Ticks:        ticks,
+		},
+		Fee:                 uint32(fee.Uint64()),
+		UnstakedFee:         uint32(unstakedFee.Uint64()),
+		StakedLiquidity:     stakedLiquidity,
+		StakedLiquidityNets: stakedLiquidityNets,
+	})
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to marshal extra data")
+		return entity.Pool{}, err
+	}
+
+	p.SwapFee = float64(fee.Uint64())
+	p.Extra = string(extraBytes)
+	p.Timestamp = time.Now().Unix()
+	p.Reserves = entity.PoolReserves{reserve0.String(), reserve1.String()}
+
+	logger.WithFields(logger.Fields{
+		"address": p.Address,
+	}).Infof("[%s] Finish getting new state of pool", p.Type)
+
+	return p, nil
+}
+
+// getStakedLiquidityNets gets the staked liquidity which is added to the staked liquidity of the pool when the price
+// crosses each tick from left to right, the subgraph only has the total liquidity of the ticks.
+func (d *PoolTracker) getStakedLiquidityNets(
+	ctx context.Context,
+	poolAddress string,
+	ticks []uniswapv3.Tick,
+) (map[int]*big.Int, error) {
+	stakedLiquidityNets := make(map[int]*big.Int)
+
+	for _, chunk := range lo.Chunk(ticks, tickBatchSize) {
+		tickInfos := make([]TickInfo, len(chunk))
+		calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+		for i, tick := range chunk {
+			calls.AddCall(&ethrpc.Call{
+				ABI:    poolABI,
+				Target: poolAddress,
+				Method: poolMethodTicks,
+				Params: []interface{}{big.NewInt(int64(tick.Index))},
+			}, []interface{}{&tickInfos[i]})
+		}
+
+		if _, err := calls.Aggregate(); err != nil {
+			return nil, err
+		}
+
+		for i, tickInfo := range tickInfos {
+			if tickInfo.StakedLiquidityNet != nil && tickInfo.StakedLiquidityNet.Sign() != 0 {
+				stakedLiquidityNets[chunk[i].Index] = tickInfo.StakedLiquidityNet
+			}
+		}
+	}
+
+	return stakedLiquidityNets, nil
+}
@@ -0,0 +1,162 @@
+package slipstream
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util"
+)
+
+// PoolsListUpdater lists the pools of the factory, which are keyed by their tokens and tick spacing instead of their fee
+type PoolsListUpdater struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+}
+
+func NewPoolsListUpdater(
+	cfg *Config,
+	ethrpcClient *ethrpc.Client,
+) *PoolsListUpdater {
+	return &PoolsListUpdater{
+		config:       cfg,
+		ethrpcClient: ethrpcClient,
+	}
+}
+
+func (d *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte) ([]entity.Pool, []byte, error) {
+	var metadata Metadata
+	if len(metadataBytes) != 0 {
+		if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
+			return nil, metadataBytes, err
+		}
+	}
+
+	// Add timestamp to the context so that each run iteration will have something different
+	ctx = util.NewContextWithTimestamp(ctx)
+
+	var lengthBI *big.Int
+	if _, err := d.ethrpcClient.NewRequest().SetContext(ctx).AddCall(&ethrpc.Call{
+		ABI:    factoryABI,
+		Target: d.config.FactoryAddress,
+		Method: factoryMethodAllPoolsLength,
+	}, []interface{}{&lengthBI}).Call(); err != nil {
+		logger.WithFields(logger.Fields{
+			"dexID": d.config.DexID,
+			"error": err,
+		}).Errorf("failed to get number of pools from factory")
+		return nil, metadataBytes, err
+	}
+
+	batchSize := min(d.config.NewPoolLimit, int(lengthBI.Int64())-metadata.Offset)
+	if batchSize <= 0 {
+		return nil, metadataBytes, nil
+	}
+
+	poolAddresses := make([]common.Address, batchSize)
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+	for i := range poolAddresses {
+		calls.AddCall(&ethrpc.Call{
+			ABI:    factoryABI,
+			Target: d.config.FactoryAddress,
+			Method: factoryMethodAllPools,
+			Params: []interface{}{big.NewInt(int64(metadata.Offset + i))},
+		}, []interface{}{&poolAddresses[i]})
+	}
+	if _, err := calls.Aggregate(); err != nil {
+		logger.WithFields(logger.Fields{
+			"dexID": d.config.DexID,
+			"error": err,
+		}).Errorf("failed to get pools from factory")
+		return nil, metadataBytes, err
+	}
+
+	pools, err := d.processBatch(ctx, poolAddresses)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"dexID": d.config.DexID,
+			"error": err,
+		}).Errorf("failed to process batch of pools")
+		return nil, metadataBytes, err
+	}
+
+	newMetadataBytes, err := json.Marshal(Metadata{Offset: metadata.Offset + batchSize})
+	if err != nil {
+		return nil, metadataBytes, err
+	}
+
+	logger.Infof("got %v %s pools, progress: %d/%d", len(pools), d.config.DexID, metadata.Offset+batchSize, lengthBI.Int64())
+
+	return pools, newMetadataBytes, nil
+}
+
+func (d *PoolsListUpdater) processBatch(ctx context.Context, poolAddresses []common.Address) ([]entity.Pool, error) {
+	var (
+		token0s      = make([]common.Address, len(poolAddresses))
+		token1s      = make([]common.Address, len(poolAddresses))
+		tickSpacings = make([]*big.Int, len(poolAddresses))
+	)
+
+	calls := d.ethrpcClient.NewRequest().SetContext(ctx)
+	for i, poolAddress := range poolAddresses {
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: poolAddress.Hex(),
+			Method: poolMethodToken0,
+		}, []interface{}{&token0s[i]})
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: poolAddress.Hex(),
+			Method: poolMethodToken1,
+		}, []interface{}{&token1s[i]})
+		calls.AddCall(&ethrpc.Call{
+			ABI:    poolABI,
+			Target: poolAddress.Hex(),
+			Method: poolMethodTickSpacing,
+		}, []interface{}{&tickSpacings[i]})
+	}
+
+	if _, err := calls.Aggregate(); err != nil {
+		return nil, err
+	}
+
+	pools := make([]entity.Pool, 0, len(poolAddresses))
+	for i, poolAddress := range poolAddresses {
+		staticExtraBytes, err := json.Marshal(StaticExtra{
+			TickSpacing: int(tickSpacings[i].Int64()),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		pools = append(pools, entity.Pool{
+			Address:   strings.ToLower(poolAddress.Hex()),
+			Exchange:  d.config.DexID,
+			Type:      DexTypeSlipstream,
+			Timestamp: time.Now().Unix(),
+			Reserves:  entity.PoolReserves{zeroString, zeroString},
+			Tokens: []*entity.PoolToken{
+				{
+					Address:   strings.ToLower(token0s[i].Hex()),
+					Weight:    defaultTokenWeight,
+					Swappable: true,
+				},
+				{
+					Address:   strings.ToLower(token1s[i].Hex()),
+					Weight:    defaultTokenWeight,
+					Swappable: true,
+				},
+			},
+			StaticExtra: string(staticExtraBytes),
+		})
+	}
+
+	return pools, nil
+}
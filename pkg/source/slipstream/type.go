@@ -0,0 +1,58 @@
+package slipstream
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/uniswapv3"
+)
+
+type Metadata struct {
+	Offset int `json:"offset"`
+}
+
+type StaticExtra struct {
+	TickSpacing int `json:"tickSpacing"`
+}
+
+// Extra is the extra of a Uniswap V3 pool with the fees of the fee modules and the liquidity staked in the gauge,
+// which earns the unstaked fee of the swaps but no swap fee.
+type Extra struct {
+	uniswapv3.Extra
+
+	Fee                 uint32           `json:"fee"`
+	UnstakedFee         uint32           `json:"unstakedFee"`
+	StakedLiquidity     *big.Int         `json:"stakedLiquidity"`
+	StakedLiquidityNets map[int]*big.Int `json:"stakedLiquidityNets,omitempty"`
+}
+
+// SwapInfo is the swap info of the Uniswap V3 simulator with the fees of the swap going to the unstaked liquidity
+// providers and to the gauge.
+type SwapInfo struct {
+	uniswapv3.UniV3SwapInfo
+
+	UnstakedFeeAmount *big.Int `json:"unstakedFeeAmount"`
+	StakedFeeAmount   *big.Int `json:"stakedFeeAmount"`
+}
+
+// Slot0 https://github.com/velodrome-finance/slipstream/blob/main/contracts/core/CLPool.sol#L47
+type Slot0 struct {
+	SqrtPriceX96               *big.Int `json:"sqrtPriceX96"`
+	Tick                       *big.Int `json:"tick"`
+	ObservationIndex           uint16   `json:"observationIndex"`
+	ObservationCardinality     uint16   `json:"observationCardinality"`
+	ObservationCardinalityNext uint16   `json:"observationCardinalityNext"`
+	Unlocked                   bool     `json:"unlocked"`
+}
+
+type TickInfo struct {
+	LiquidityGross                 *big.Int
+	LiquidityNet                   *big.Int
+	StakedLiquidityNet             *big.Int
+	FeeGrowthOutside0X128          *big.Int
+	FeeGrowthOutside1X128          *big.Int
+	RewardGrowthOutsideX128        *big.Int
+	TickCumulativeOutside          *big.Int
+	SecondsPerLiquidityOutsideX128 *big.Int
+	SecondsOutside                 uint32
+	Initialized                    bool
+}
@@ -3,7 +3,7 @@ package swapbasedperp
 import (
 	"math/big"
 
-	constant "github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/gmx/vaultengine"
 )
 
 // VaultUtils
@@ -50,54 +50,12 @@ func (u *VaultUtils) GetFeeBasisPoints(token string, usdbDelta *big.Int, feeBasi
 		return feeBasisPoints
 	}
 
-	initialAmount := u.vault.USDBAmounts[token]
-	nextAmount := new(big.Int).Add(initialAmount, usdbDelta)
-
-	if !increment {
-		if usdbDelta.Cmp(initialAmount) > 0 {
-			nextAmount = constant.ZeroBI
-		} else {
-			nextAmount = new(big.Int).Sub(initialAmount, usdbDelta)
-		}
-	}
-
-	targetAmount := u.vault.GetTargetUSDBAmount(token)
-
-	if targetAmount.Cmp(constant.ZeroBI) == 0 {
-		return feeBasisPoints
-	}
-
-	var initialDiff *big.Int
-	if initialAmount.Cmp(targetAmount) > 0 {
-		initialDiff = new(big.Int).Sub(initialAmount, targetAmount)
-	} else {
-		initialDiff = new(big.Int).Sub(targetAmount, initialAmount)
-	}
-
-	var nextDiff *big.Int
-	if nextAmount.Cmp(targetAmount) > 0 {
-		nextDiff = new(big.Int).Sub(nextAmount, targetAmount)
-	} else {
-		nextDiff = new(big.Int).Sub(targetAmount, nextAmount)
-	}
-
-	if nextDiff.Cmp(initialDiff) < 0 {
-		rebateBps := new(big.Int).Div(new(big.Int).Mul(taxBasisPoints, initialDiff), targetAmount)
-
-		if rebateBps.Cmp(feeBasisPoints) > 0 {
-			return constant.ZeroBI
-		} else {
-			return new(big.Int).Sub(feeBasisPoints, rebateBps)
-		}
-	}
-
-	averageDiff := new(big.Int).Div(new(big.Int).Add(initialDiff, nextDiff), constant.Two)
-
-	if averageDiff.Cmp(targetAmount) > 0 {
-		averageDiff = targetAmount
-	}
-
-	taxBps := new(big.Int).Div(new(big.Int).Mul(taxBasisPoints, averageDiff), targetAmount)
-
-	return new(big.Int).Add(feeBasisPoints, taxBps)
+	return vaultengine.GetFeeBasisPoints(
+		u.vault.USDBAmounts[token],
+		u.vault.GetTargetUSDBAmount(token),
+		usdbDelta,
+		feeBasisPoints,
+		taxBasisPoints,
+		increment,
+	)
 }
@@ -6,7 +6,16 @@ type Config struct {
 	TickLensAddress    string `json:"tickLensAddress"`
 	PreGenesisPoolPath string `json:"preGenesisPoolPath"`
 	AllowSubgraphError bool   `json:"allowSubgraphError"`
-	preGenesisPoolIDs  []string
+
+	// TickLensDepthUSD makes TickLens fetch only the words around the word of the current tick, the words on each
+	// side grow from TickLensWordRange until their liquidity is worth TickLensDepthUSD of the token a swap towards
+	// them buys. All the words are fetched when it is not positive or the pool has no reserveUsd yet.
+	TickLensDepthUSD float64 `json:"tickLensDepthUSD"`
+	// TickLensWordRange is the number of words first fetched on each side of the word of the current tick,
+	// a non-positive value will be set to 2 by default
+	TickLensWordRange int `json:"tickLensWordRange"`
+
+	preGenesisPoolIDs []string
 }
 
 func (c *Config) IsAllowSubgraphError() bool {
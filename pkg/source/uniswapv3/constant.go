@@ -14,6 +14,8 @@ const (
 	zeroString            = "0"
 	emptyString           = ""
 	graphQLRequestTimeout = 20 * time.Second
+
+	defaultTickLensWordRange = 2
 )
 
 const (
@@ -22,6 +22,9 @@ import (
 var (
 	ErrTickNil      = errors.New("tick is nil")
 	ErrV3TicksEmpty = errors.New("v3Ticks empty")
+	// ErrWindowExhausted is returned when a swap reaches the end of the ticks fetched around the current tick, the
+	// liquidity past them is unknown so the amount out would be under-quoted
+	ErrWindowExhausted = errors.New("swap reaches the end of the known ticks")
 )
 
 type PoolSimulator struct {
@@ -30,9 +33,24 @@ type PoolSimulator struct {
 	gas     Gas
 	tickMin int
 	tickMax int
+	// tickLower and tickUpper bound the ticks known to the simulator, see Extra
+	tickLower *int
+	tickUpper *int
 }
 
 func NewPoolSimulator(entityPool entity.Pool, chainID valueobject.ChainID) (*PoolSimulator, error) {
+	fee := constants.FeeAmount(entityPool.SwapFee)
+	return NewPoolSimulatorWithTickSpacing(entityPool, chainID, fee, constants.TickSpacings[fee])
+}
+
+// NewPoolSimulatorWithTickSpacing creates a simulator of a pool whose tick spacing is not derived from its fee, like the
+// pools of the forks which key their pools by tick spacing.
+func NewPoolSimulatorWithTickSpacing(
+	entityPool entity.Pool,
+	chainID valueobject.ChainID,
+	fee constants.FeeAmount,
+	tickSpacing int,
+) (*PoolSimulator, error) {
 	var extra Extra
 	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
 		return nil, err
@@ -78,7 +96,7 @@ func NewPoolSimulator(entityPool entity.Pool, chainID valueobject.ChainID) (*Poo
 		return nil, ErrV3TicksEmpty
 	}
 
-	ticks, err := v3Entities.NewTickListDataProvider(v3Ticks, constants.TickSpacings[constants.FeeAmount(entityPool.SwapFee)])
+	ticks, err := v3Entities.NewTickListDataProvider(v3Ticks, tickSpacing)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +104,7 @@ func NewPoolSimulator(entityPool entity.Pool, chainID valueobject.ChainID) (*Poo
 	v3Pool, err := v3Entities.NewPool(
 		token0,
 		token1,
-		constants.FeeAmount(entityPool.SwapFee),
+		fee,
 		extra.SqrtPriceX96,
 		extra.Liquidity,
 		int(extra.Tick.Int64()),
@@ -111,18 +129,20 @@ func NewPoolSimulator(entityPool entity.Pool, chainID valueobject.ChainID) (*Poo
 	}
 
 	return &PoolSimulator{
-		Pool:    pool.Pool{Info: info},
-		V3Pool:  v3Pool,
-		gas:     defaultGas,
-		tickMin: tickMin,
-		tickMax: tickMax,
+		Pool:      pool.Pool{Info: info},
+		V3Pool:    v3Pool,
+		gas:       defaultGas,
+		tickMin:   tickMin,
+		tickMax:   tickMax,
+		tickLower: extra.TickLower,
+		tickUpper: extra.TickUpper,
 	}, nil
 }
 
 /**
- * getSqrtPriceLimit get the price limit of pool based on the initialized ticks that this pool has
+ * GetSqrtPriceLimit get the price limit of pool based on the initialized ticks that this pool has
  */
-func (p *PoolSimulator) getSqrtPriceLimit(zeroForOne bool) *big.Int {
+func (p *PoolSimulator) GetSqrtPriceLimit(zeroForOne bool) *big.Int {
 	var tickLimit int
 	if zeroForOne {
 		tickLimit = p.tickMin
@@ -139,6 +159,25 @@ func (p *PoolSimulator) getSqrtPriceLimit(zeroForOne bool) *big.Int {
 	return sqrtPriceX96Limit
 }
 
+// isWindowExhausted tells if a swap stopped at the end of the ticks fetched around the current tick, the swap may not
+// have spent all of its amount in
+func (p *PoolSimulator) isWindowExhausted(zeroForOne bool, sqrtPriceX96 *big.Int) bool {
+	tickLimit := p.tickUpper
+	if zeroForOne {
+		tickLimit = p.tickLower
+	}
+	if tickLimit == nil {
+		return false
+	}
+
+	sqrtPriceX96Limit, err := v3Utils.GetSqrtRatioAtTick(*tickLimit)
+	if err != nil {
+		return false
+	}
+
+	return sqrtPriceX96.Cmp(sqrtPriceX96Limit) == 0
+}
+
 func (p *PoolSimulator) CalcAmountOut(
 	tokenAmountIn pool.TokenAmount,
 	tokenOut string,
@@ -157,12 +196,16 @@ func (p *PoolSimulator) CalcAmountOut(
 			zeroForOne = true
 		}
 		amountIn := coreEntities.FromRawAmount(tokenIn, tokenAmountIn.Amount)
-		amountOut, newPoolState, err := p.V3Pool.GetOutputAmount(amountIn, p.getSqrtPriceLimit(zeroForOne))
+		amountOut, newPoolState, err := p.V3Pool.GetOutputAmount(amountIn, p.GetSqrtPriceLimit(zeroForOne))
 
 		if err != nil {
 			return &pool.CalcAmountOutResult{}, fmt.Errorf("can not GetOutputAmount, err: %+v", err)
 		}
 
+		if p.isWindowExhausted(zeroForOne, newPoolState.SqrtRatioX96) {
+			return &pool.CalcAmountOutResult{}, ErrWindowExhausted
+		}
+
 		var totalGas = p.gas.Swap
 
 		//p.nextState.SqrtRatioX96 = newPoolState.SqrtRatioX96
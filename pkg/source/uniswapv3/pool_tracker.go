@@ -35,6 +35,10 @@ func NewPoolTracker(
 		return nil, err
 	}
 
+	if cfg.TickLensWordRange <= 0 {
+		cfg.TickLensWordRange = defaultTickLensWordRange
+	}
+
 	graphqlClient := graphqlPkg.NewWithTimeout(cfg.SubgraphAPI, graphQLRequestTimeout)
 
 	return &PoolTracker{
@@ -82,8 +86,13 @@ func (d *PoolTracker) GetNewPoolState(
 	var (
 		rpcData   FetchRPCResult
 		poolTicks []TickResp
+		window    tickWindow
 	)
 
+	isPreGenesisPool := lo.Contains[string](d.config.preGenesisPoolIDs, p.Address)
+	// the window of ticks around the current tick is fetched once the current tick is known
+	useTickLensWindow := isPreGenesisPool && d.config.TickLensDepthUSD > 0
+
 	g := pool.New().WithContext(ctx)
 	g.Go(func(context.Context) error {
 		var err error
@@ -104,7 +113,10 @@ func (d *PoolTracker) GetNewPoolState(
 		// Link to issue: https://www.notion.so/kybernetwork/Aggregator-1-20-defect-1caec6062f9d4da0918fc3443e6e1963#0810d1462cc14f0a9465f935c9e641fe
 		// TLDR: Optimism has some pre-genesis Uniswap V3 pool. Subgraph does not have data for these pools
 		// So we have to fetch ticks data from the TickLens smart contract (which is slower).
-		if lo.Contains[string](d.config.preGenesisPoolIDs, p.Address) {
+		if useTickLensWindow {
+			return nil
+		}
+		if isPreGenesisPool {
 			poolTicks, err = d.getPoolTicksFromSC(ctx, p)
 			if err != nil {
 				logger.WithFields(logger.Fields{
@@ -133,25 +145,27 @@ func (d *PoolTracker) GetNewPoolState(
 		return entity.Pool{}, err
 	}
 
-	var ticks []Tick
-	for _, tickResp := range poolTicks {
-		tick, err := transformTickRespToTick(tickResp)
+	if useTickLensWindow {
+		var err error
+		poolTicks, window, err = d.getPoolTicksInWindowFromSC(ctx, p, rpcData)
 		if err != nil {
 			logger.WithFields(logger.Fields{
 				"poolAddress": p.Address,
 				"error":       err,
-			}).Errorf("failed to transform tickResp to tick")
-			continue
+			}).Errorf("failed to call SC for pool ticks")
+			return entity.Pool{}, err
 		}
-
-		ticks = append(ticks, tick)
 	}
 
+	ticks := transformTickResps(p.Address, poolTicks)
+
 	extraBytes, err := json.Marshal(Extra{
 		Liquidity:    rpcData.liquidity,
 		SqrtPriceX96: rpcData.slot0.SqrtPriceX96,
 		Tick:         rpcData.slot0.Tick,
 		Ticks:        ticks,
+		TickLower:    window.lower,
+		TickUpper:    window.upper,
 	})
 	if err != nil {
 		logger.WithFields(logger.Fields{
@@ -288,3 +302,32 @@ func (d *PoolTracker) getPoolTicks(ctx context.Context, poolAddress string) ([]T
 
 	return ticks, nil
 }
+
+// GetPoolTicks gets the initialized ticks of a pool from the subgraph, for the forks whose subgraphs keep the ticks like
+// Uniswap V3.
+func (d *PoolTracker) GetPoolTicks(ctx context.Context, poolAddress string) ([]Tick, error) {
+	poolTicks, err := d.getPoolTicks(ctx, poolAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return transformTickResps(poolAddress, poolTicks), nil
+}
+
+func transformTickResps(poolAddress string, poolTicks []TickResp) []Tick {
+	var ticks []Tick
+	for _, tickResp := range poolTicks {
+		tick, err := transformTickRespToTick(tickResp)
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"poolAddress": poolAddress,
+				"error":       err,
+			}).Errorf("failed to transform tickResp to tick")
+			continue
+		}
+
+		ticks = append(ticks, tick)
+	}
+
+	return ticks
+}
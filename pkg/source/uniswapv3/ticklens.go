@@ -30,16 +30,102 @@ var (
 
 // getPoolTicksFromSC get all ticks of a pool from TickLens smart-contract
 func (d *PoolTracker) getPoolTicksFromSC(ctx context.Context, pool entity.Pool) ([]TickResp, error) {
+	poolMinWordIdx, poolMaxWordIdx := getPoolWordRange(getTickSpacing(pool.SwapFee))
+
+	ticks, err := d.getPopulatedTicksInWords(ctx, pool, poolMinWordIdx, poolMaxWordIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	sortTickResps(ticks)
+
+	return ticks, nil
+}
+
+// getPoolTicksInWindowFromSC gets the ticks of the words around the word of the current tick from TickLens smart-contract.
+// The words on each side start at TickLensWordRange and double until their liquidity is worth TickLensDepthUSD or
+// they reach the last word of the pool. A side not reaching it is closed by a tick out of the window, see
+// closeTickWindow.
+func (d *PoolTracker) getPoolTicksInWindowFromSC(
+	ctx context.Context,
+	pool entity.Pool,
+	rpcData FetchRPCResult,
+) ([]TickResp, tickWindow, error) {
 	tickSpace := getTickSpacing(pool.SwapFee)
-	poolMinWordIdx := int16(minWordIndex/tickSpace - 1)
-	poolMaxWordIdx := -poolMinWordIdx
+	poolMinWordIdx, poolMaxWordIdx := getPoolWordRange(tickSpace)
+	currentTick := int(rpcData.slot0.Tick.Int64())
+	currentWordIdx := getWordIndex(currentTick, tickSpace)
+
+	lowWordIdx := max(currentWordIdx-d.config.TickLensWordRange, poolMinWordIdx)
+	highWordIdx := min(currentWordIdx+d.config.TickLensWordRange, poolMaxWordIdx)
+	ticks, err := d.getPopulatedTicksInWords(ctx, pool, lowWordIdx, highWordIdx)
+	if err != nil {
+		return nil, tickWindow{}, err
+	}
 
-	// Prepare the list of wordIndexes, the total number of indexes is poolMaxWordIdx-poolMinWordIdx+1
-	wordIndexes := make([]int16, 0, poolMaxWordIdx-poolMinWordIdx+1)
-	for idx := poolMinWordIdx; idx <= poolMaxWordIdx; idx++ {
-		wordIndexes = append(wordIndexes, idx)
+	for {
+		sortTickResps(ticks)
+
+		lowerTick, upperTick := lowWordIdx*maxWordSize*tickSpace, (highWordIdx+1)*maxWordSize*tickSpace
+		zeroForOneDepth, oneForZeroDepth, ok := getDepthUSD(
+			pool.ReserveUsd, rpcData, transformTickResps(pool.Address, ticks), lowerTick, upperTick,
+		)
+		if !ok {
+			break
+		}
+
+		newLowWordIdx, newHighWordIdx := lowWordIdx, highWordIdx
+		if zeroForOneDepth < d.config.TickLensDepthUSD && lowWordIdx > poolMinWordIdx {
+			newLowWordIdx = max(currentWordIdx-2*(currentWordIdx-lowWordIdx), poolMinWordIdx)
+		}
+		if oneForZeroDepth < d.config.TickLensDepthUSD && highWordIdx < poolMaxWordIdx {
+			newHighWordIdx = min(currentWordIdx+2*(highWordIdx-currentWordIdx), poolMaxWordIdx)
+		}
+		if newLowWordIdx == lowWordIdx && newHighWordIdx == highWordIdx {
+			break
+		}
+
+		if newLowWordIdx < lowWordIdx {
+			lowTicks, err := d.getPopulatedTicksInWords(ctx, pool, newLowWordIdx, lowWordIdx-1)
+			if err != nil {
+				return nil, tickWindow{}, err
+			}
+			ticks = append(ticks, lowTicks...)
+		}
+		if newHighWordIdx > highWordIdx {
+			highTicks, err := d.getPopulatedTicksInWords(ctx, pool, highWordIdx+1, newHighWordIdx)
+			if err != nil {
+				return nil, tickWindow{}, err
+			}
+			ticks = append(ticks, highTicks...)
+		}
+		lowWordIdx, highWordIdx = newLowWordIdx, newHighWordIdx
 	}
 
+	ticks, window := closeTickWindow(
+		pool.Address,
+		ticks,
+		rpcData.liquidity,
+		currentTick,
+		lowWordIdx*maxWordSize*tickSpace-tickSpace,
+		(highWordIdx+1)*maxWordSize*tickSpace,
+	)
+
+	return ticks, window, nil
+}
+
+// getPopulatedTicksInWords gets the ticks of the words [fromWordIdx, toWordIdx] of a pool from TickLens smart-contract
+func (d *PoolTracker) getPopulatedTicksInWords(
+	ctx context.Context,
+	pool entity.Pool,
+	fromWordIdx int,
+	toWordIdx int,
+) ([]TickResp, error) {
+	// Prepare the list of wordIndexes, the total number of indexes is toWordIdx-fromWordIdx+1
+	wordIndexes := make([]int16, 0, toWordIdx-fromWordIdx+1)
+	for idx := fromWordIdx; idx <= toWordIdx; idx++ {
+		wordIndexes = append(wordIndexes, int16(idx))
+	}
 	// We will process 500 word indexes at a time
 	chunkedWordIndexes := lo.Chunk[int16](wordIndexes, multicallBatchSize)
 
@@ -86,15 +172,32 @@ func (d *PoolTracker) getPoolTicksFromSC(ctx context.Context, pool entity.Pool)
 		}
 	}
 
-	// Sort the ticks because function NewTickListDataProvider needs
+	return ticks, nil
+}
+
+// sortTickResps sorts the ticks because function NewTickListDataProvider needs
+func sortTickResps(ticks []TickResp) {
 	sort.SliceStable(ticks, func(i, j int) bool {
 		iTick, _ := strconv.Atoi(ticks[i].TickIdx)
 		jTick, _ := strconv.Atoi(ticks[j].TickIdx)
 
 		return iTick < jTick
 	})
+}
 
-	return ticks, nil
+// getPoolWordRange returns the first and the last word index of the ticks of a pool
+func getPoolWordRange(tickSpace int) (int, int) {
+	poolMinWordIdx := minWordIndex/tickSpace - 1
+	return poolMinWordIdx, -poolMinWordIdx
+}
+
+// getWordIndex returns the index of the word of the tick bitmap holding tick
+func getWordIndex(tick int, tickSpace int) int {
+	compressed := tick / tickSpace
+	if tick < 0 && tick%tickSpace != 0 {
+		compressed--
+	}
+	return compressed >> 8
 }
 
 func getTickSpacing(swapFee float64) int {
@@ -63,6 +63,10 @@ type Extra struct {
 	SqrtPriceX96 *big.Int `json:"sqrtPriceX96"`
 	Tick         *big.Int `json:"tick"`
 	Ticks        []Tick   `json:"ticks"`
+	// TickLower and TickUpper are set when the ticks were fetched in a window around the current tick, they are the
+	// ticks closing the window below and above it and a swap reaching them can not be quoted
+	TickLower *int `json:"tickLower,omitempty"`
+	TickUpper *int `json:"tickUpper,omitempty"`
 }
 
 type Slot0 struct {
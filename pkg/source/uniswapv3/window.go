@@ -0,0 +1,140 @@
+package uniswapv3
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/daoleno/uniswapv3-sdk/utils"
+)
+
+var q96Float = math.Pow(2, 96)
+
+// tickWindow holds the ticks bounding the ticks fetched around the current tick, a side is nil when the ticks reach
+// the last word of the pool
+type tickWindow struct {
+	lower *int
+	upper *int
+}
+
+// getDepthUSD estimates the value in USD of the token1 sold by the ticks in [lowerTick, currentTick], to swaps from
+// token0 to token1, and of the token0 sold by the ticks in (currentTick, upperTick), to swaps from token1 to token0.
+// The tokens are priced from reserveUsd split between the reserves at the current price, ok is false when they can
+// not be priced. ticks must be sorted.
+func getDepthUSD(
+	reserveUsd float64,
+	rpcData FetchRPCResult,
+	ticks []Tick,
+	lowerTick int,
+	upperTick int,
+) (zeroForOneDepth float64, oneForZeroDepth float64, ok bool) {
+	if rpcData.slot0.SqrtPriceX96 == nil || rpcData.slot0.Tick == nil {
+		return 0, 0, false
+	}
+	sqrtPrice := toFloat(rpcData.slot0.SqrtPriceX96) / q96Float
+	price := sqrtPrice * sqrtPrice
+	valueIn0 := toFloat(rpcData.reserve0) + toFloat(rpcData.reserve1)/price
+	if reserveUsd <= 0 || valueIn0 <= 0 {
+		return 0, 0, false
+	}
+	usdPer0 := reserveUsd / valueIn0
+	usdPer1 := usdPer0 / price
+
+	currentTick := int(rpcData.slot0.Tick.Int64())
+	firstAbove := len(ticks)
+	for i, tick := range ticks {
+		if tick.Index > currentTick {
+			firstAbove = i
+			break
+		}
+	}
+
+	// token0 to token1, the price goes down crossing the ticks at or below the current tick
+	amount1 := 0.0
+	liquidity, prevSqrtPrice := toFloat(rpcData.liquidity), sqrtPrice
+	for i := firstAbove - 1; i >= 0 && ticks[i].Index >= lowerTick; i-- {
+		tickSqrtPrice := getSqrtPriceAtTick(ticks[i].Index)
+		amount1 += liquidity * (prevSqrtPrice - tickSqrtPrice)
+		liquidity -= toFloat(ticks[i].LiquidityNet)
+		prevSqrtPrice = tickSqrtPrice
+	}
+	amount1 += liquidity * math.Max(prevSqrtPrice-getSqrtPriceAtTick(lowerTick), 0)
+
+	// token1 to token0, the price goes up crossing the ticks above the current tick
+	amount0 := 0.0
+	liquidity, prevSqrtPrice = toFloat(rpcData.liquidity), sqrtPrice
+	for i := firstAbove; i < len(ticks) && ticks[i].Index < upperTick; i++ {
+		tickSqrtPrice := getSqrtPriceAtTick(ticks[i].Index)
+		amount0 += liquidity * (1/prevSqrtPrice - 1/tickSqrtPrice)
+		liquidity += toFloat(ticks[i].LiquidityNet)
+		prevSqrtPrice = tickSqrtPrice
+	}
+	amount0 += liquidity * math.Max(1/prevSqrtPrice-1/getSqrtPriceAtTick(upperTick), 0)
+
+	return amount1 * usdPer1, amount0 * usdPer0, true
+}
+
+// closeTickWindow closes the sides of the ticks fetched around the current tick which do not reach the last word of
+// the pool. lowerTick, the last tick below the window, adds the liquidity left after crossing down all the ticks of the
+// window and upperTick, the first tick above it, removes the liquidity after crossing them up, so the liquidity net of
+// the ticks still sums to zero and the simulator stops at the end of the window. A side without liquidity at its end
+// is bounded by its last fetched tick instead. ticks must be sorted.
+func closeTickWindow(
+	poolAddress string,
+	ticks []TickResp,
+	liquidity *big.Int,
+	currentTick int,
+	lowerTick int,
+	upperTick int,
+) ([]TickResp, tickWindow) {
+	parsedTicks := transformTickResps(poolAddress, ticks)
+	lowerLiquidity := new(big.Int).Set(liquidity)
+	upperLiquidity := new(big.Int).Set(liquidity)
+	for _, tick := range parsedTicks {
+		if tick.Index <= currentTick {
+			lowerLiquidity.Sub(lowerLiquidity, tick.LiquidityNet)
+		} else {
+			upperLiquidity.Add(upperLiquidity, tick.LiquidityNet)
+		}
+	}
+
+	var window tickWindow
+	if lowerTick >= utils.MinTick {
+		if lowerLiquidity.Sign() != 0 {
+			ticks = append([]TickResp{newTickResp(lowerTick, lowerLiquidity)}, ticks...)
+			window.lower = &lowerTick
+		} else if len(parsedTicks) > 0 {
+			window.lower = &parsedTicks[0].Index
+		}
+	}
+	if upperTick <= utils.MaxTick {
+		if upperLiquidity.Sign() != 0 {
+			ticks = append(ticks, newTickResp(upperTick, new(big.Int).Neg(upperLiquidity)))
+			window.upper = &upperTick
+		} else if len(parsedTicks) > 0 {
+			window.upper = &parsedTicks[len(parsedTicks)-1].Index
+		}
+	}
+
+	return ticks, window
+}
+
+func newTickResp(tick int, liquidityNet *big.Int) TickResp {
+	return TickResp{
+		TickIdx:        strconv.Itoa(tick),
+		LiquidityGross: new(big.Int).Abs(liquidityNet).String(),
+		LiquidityNet:   liquidityNet.String(),
+	}
+}
+
+func getSqrtPriceAtTick(tick int) float64 {
+	return math.Pow(1.0001, float64(tick)/2)
+}
+
+func toFloat(v *big.Int) float64 {
+	if v == nil {
+		return 0
+	}
+	f, _ := new(big.Float).SetInt(v).Float64()
+	return f
+}
@@ -0,0 +1,119 @@
+package uniswapv3
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	coreEntities "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/daoleno/uniswapv3-sdk/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util/bignumber"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+func TestTickWindow(t *testing.T) {
+	// at tick 0, 1e18 of liquidity is in [-600, 600) and 5e17 in a wider range whose ticks are out of the window
+	rpcData := FetchRPCResult{
+		liquidity: bignumber.NewBig10("1500000000000000000"),
+		slot0:     Slot0{SqrtPriceX96: constants.Q96, Tick: big.NewInt(0)},
+		reserve0:  bignumber.NewBig10("1000000000000000000"),
+		reserve1:  bignumber.NewBig10("1000000000000000000"),
+	}
+	fetchedTicks := []TickResp{
+		{TickIdx: "-600", LiquidityGross: "1000000000000000000", LiquidityNet: "1000000000000000000"},
+		{TickIdx: "600", LiquidityGross: "1000000000000000000", LiquidityNet: "-1000000000000000000"},
+	}
+
+	ticks, window := closeTickWindow("pool", fetchedTicks, rpcData.liquidity, 0, -900, 900)
+	require.NotNil(t, window.lower)
+	require.NotNil(t, window.upper)
+	assert.Equal(t, -900, *window.lower)
+	assert.Equal(t, 900, *window.upper)
+	assert.Equal(t, []TickResp{
+		{TickIdx: "-900", LiquidityGross: "500000000000000000", LiquidityNet: "500000000000000000"},
+		fetchedTicks[0],
+		fetchedTicks[1],
+		{TickIdx: "900", LiquidityGross: "500000000000000000", LiquidityNet: "-500000000000000000"},
+	}, ticks)
+
+	extraBytes, err := json.Marshal(Extra{
+		Liquidity:    rpcData.liquidity,
+		SqrtPriceX96: rpcData.slot0.SqrtPriceX96,
+		Tick:         rpcData.slot0.Tick,
+		Ticks:        transformTickResps("pool", ticks),
+		TickLower:    window.lower,
+		TickUpper:    window.upper,
+	})
+	require.NoError(t, err)
+	p, err := NewPoolSimulator(entity.Pool{
+		Address:  "pool",
+		Exchange: "uniswapv3",
+		Type:     DexTypeUniswapV3,
+		SwapFee:  3000,
+		Reserves: entity.PoolReserves{"1000000000000000000", "1000000000000000000"},
+		Tokens: []*entity.PoolToken{
+			{Address: "0x000000000000000000000000000000000000000a", Decimals: 18},
+			{Address: "0x000000000000000000000000000000000000000b", Decimals: 18},
+		},
+		Extra: string(extraBytes),
+	}, valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name        string
+		in          string
+		inAmount    string
+		out         string
+		expectedErr error
+	}{
+		{"0 to 1 within the window", p.Info.Tokens[0], "1000000000000000", p.Info.Tokens[1], nil},
+		{"0 to 1 past the window", p.Info.Tokens[0], "1000000000000000000", p.Info.Tokens[1], ErrWindowExhausted},
+		{"1 to 0 within the window", p.Info.Tokens[1], "1000000000000000", p.Info.Tokens[0], nil},
+		{"1 to 0 past the window", p.Info.Tokens[1], "1000000000000000000", p.Info.Tokens[0], ErrWindowExhausted},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			amountIn := pool.TokenAmount{Token: tc.in, Amount: bignumber.NewBig10(tc.inAmount)}
+			result, err := p.CalcAmountOut(amountIn, tc.out)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 1, result.TokenAmountOut.Amount.Sign())
+		})
+	}
+
+	// the depth is what a swap reaching the end of the window gets, both tokens are worth 1 USD at tick 0
+	zeroForOneDepth, oneForZeroDepth, ok := getDepthUSD(2e18, rpcData, transformTickResps("pool", fetchedTicks), -900, 900)
+	require.True(t, ok)
+
+	for _, zeroForOne := range []bool{true, false} {
+		tokenIn, depth := p.V3Pool.Token0, zeroForOneDepth
+		if !zeroForOne {
+			tokenIn, depth = p.V3Pool.Token1, oneForZeroDepth
+		}
+		amountOut, _, err := p.V3Pool.GetOutputAmount(
+			coreEntities.FromRawAmount(tokenIn, bignumber.NewBig10("10000000000000000000")), p.GetSqrtPriceLimit(zeroForOne),
+		)
+		require.NoError(t, err)
+		assert.InEpsilon(t, toFloat(amountOut.Quotient()), depth, 1e-6)
+	}
+
+	_, _, ok = getDepthUSD(0, rpcData, transformTickResps("pool", fetchedTicks), -900, 900)
+	assert.False(t, ok)
+}
+
+func TestGetWordIndex(t *testing.T) {
+	assert.Equal(t, 0, getWordIndex(0, 60))
+	assert.Equal(t, 0, getWordIndex(15359, 60))
+	assert.Equal(t, 1, getWordIndex(15360, 60))
+	assert.Equal(t, -1, getWordIndex(-1, 60))
+	assert.Equal(t, -1, getWordIndex(-15360, 60))
+	assert.Equal(t, -2, getWordIndex(-15361, 60))
+}
@@ -0,0 +1,18 @@
+package uniswapv4
+
+import "github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+
+type Config struct {
+	DexID              string              `json:"-"`
+	ChainID            valueobject.ChainID `json:"chainID"`
+	PoolManagerAddress string              `json:"poolManagerAddress"`
+	StateViewAddress   string              `json:"stateViewAddress"`
+
+	// StartBlock is the PoolManager deployment block, discovery starts from here on the first run
+	StartBlock uint64 `json:"startBlock"`
+	// BlockBatchSize is the block range of a single eth_getLogs request
+	BlockBatchSize uint64 `json:"blockBatchSize"`
+	// TickWordRange is the number of words of the tick bitmap scanned on each side of the word of the current tick,
+	// a non-positive value will be set to 8 by default
+	TickWordRange int `json:"tickWordRange"`
+}
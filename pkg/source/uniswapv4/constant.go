@@ -0,0 +1,36 @@
+package uniswapv4
+
+import (
+	"math/big"
+)
+
+const (
+	DexTypeUniswapV4      = "uniswapv4"
+	defaultTokenDecimals  = 18
+	defaultTokenWeight    = 50
+	defaultBlockBatchSize = 5000
+	defaultTickWordRange  = 8
+	zeroString            = "0"
+)
+
+const (
+	poolManagerEventInitialize = "Initialize"
+
+	stateViewMethodGetSlot0         = "getSlot0"
+	stateViewMethodGetLiquidity     = "getLiquidity"
+	stateViewMethodGetTickBitmap    = "getTickBitmap"
+	stateViewMethodGetTickLiquidity = "getTickLiquidity"
+	erc20MethodDecimals             = "decimals"
+)
+
+const (
+	// DynamicFeeFlag marks a pool whose LP fee is set by its hook, the actual fee lives in slot0.lpFee
+	DynamicFeeFlag uint32 = 0x800000
+	// MaxLpFee is 100% in hundredths of a bip
+	MaxLpFee uint32 = 1000000
+)
+
+var (
+	zeroBI     = big.NewInt(0)
+	defaultGas = Gas{Swap: 130000, Hook: 40000}
+)
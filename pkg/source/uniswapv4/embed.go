@@ -0,0 +1,14 @@
+package uniswapv4
+
+import (
+	_ "embed"
+)
+
+//go:embed abis/PoolManager.json
+var poolManagerJson []byte
+
+//go:embed abis/StateView.json
+var stateViewJson []byte
+
+//go:embed abis/ERC20.json
+var erc20Json []byte
@@ -0,0 +1,15 @@
+package uniswapv4
+
+import "errors"
+
+var (
+	ErrTickNil           = errors.New("tick is nil")
+	ErrV4TicksEmpty      = errors.New("v4Ticks empty")
+	ErrInvalidToken      = errors.New("invalid token")
+	ErrZeroAmountOut     = errors.New("amountOut is 0")
+	ErrUnsupportedHook   = errors.New("hook modifies swap deltas and has no registered adapter")
+	ErrInvalidFee        = errors.New("invalid lp fee")
+	ErrInvalidPoolId     = errors.New("pool id does not match pool key")
+	ErrHookDeltaExceeded = errors.New("hook delta exceeds swap amount")
+	ErrWindowExhausted   = errors.New("swap reaches the end of the scanned ticks")
+)
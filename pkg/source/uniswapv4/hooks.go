@@ -0,0 +1,110 @@
+package uniswapv4
+
+import (
+	"encoding/binary"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// HookPermissions are encoded in the lowest 14 bits of the hook address
+// https://github.com/Uniswap/v4-core/blob/main/src/libraries/Hooks.sol
+type HookPermissions uint16
+
+const (
+	AfterRemoveLiquidityReturnsDelta HookPermissions = 1 << iota
+	AfterAddLiquidityReturnsDelta
+	AfterSwapReturnsDelta
+	BeforeSwapReturnsDelta
+	AfterDonate
+	BeforeDonate
+	AfterSwap
+	BeforeSwap
+	AfterRemoveLiquidity
+	BeforeRemoveLiquidity
+	AfterAddLiquidity
+	BeforeAddLiquidity
+	AfterInitialize
+	BeforeInitialize
+
+	allHookPermissions HookPermissions = 1<<14 - 1
+)
+
+func GetHookPermissions(hooks string) HookPermissions {
+	address := common.HexToAddress(hooks)
+
+	return HookPermissions(binary.BigEndian.Uint16(address[18:])) & allHookPermissions
+}
+
+func (p HookPermissions) Has(flag HookPermissions) bool {
+	return p&flag != 0
+}
+
+// HasSwapHooks reports whether the hook is called during a swap
+func (p HookPermissions) HasSwapHooks() bool {
+	return p.Has(BeforeSwap | AfterSwap)
+}
+
+// ModifiesSwapDeltas reports whether the hook can take or give tokens during a swap, such pools can not be
+// quoted from the pool state alone
+func (p HookPermissions) ModifiesSwapDeltas() bool {
+	return p.Has(BeforeSwapReturnsDelta | AfterSwapReturnsDelta)
+}
+
+type BeforeSwapParams struct {
+	PoolKey    PoolKey
+	ZeroForOne bool
+	AmountIn   *big.Int
+	LpFee      uint32
+}
+
+type BeforeSwapResult struct {
+	// DeltaSpecified is taken by the hook from the amount in before the swap is executed
+	DeltaSpecified *big.Int
+	// DeltaUnspecified is taken by the hook from the amount out
+	DeltaUnspecified *big.Int
+	// LpFee replaces the pool LP fee for this swap when OverrideFee is set
+	LpFee       uint32
+	OverrideFee bool
+}
+
+type AfterSwapParams struct {
+	PoolKey    PoolKey
+	ZeroForOne bool
+	AmountIn   *big.Int
+	AmountOut  *big.Int
+}
+
+// Hook simulates a hook contract. Pools whose hooks modify swap deltas are only quoted when an adapter has been
+// registered for their hook address with RegisterHook.
+type Hook interface {
+	BeforeSwap(params BeforeSwapParams) (BeforeSwapResult, error)
+	// AfterSwap returns the amount the hook takes from the amount out
+	AfterSwap(params AfterSwapParams) (*big.Int, error)
+}
+
+var (
+	hookRegistry     = map[common.Address]Hook{}
+	hookRegistryLock sync.RWMutex
+)
+
+// RegisterHook registers an adapter for the given hook addresses, replacing any previous one
+func RegisterHook(hook Hook, addresses ...common.Address) {
+	hookRegistryLock.Lock()
+	defer hookRegistryLock.Unlock()
+
+	for _, address := range addresses {
+		hookRegistry[address] = hook
+	}
+}
+
+// GetHook returns the adapter registered for the hook address
+func GetHook(address common.Address) (Hook, bool) {
+	hookRegistryLock.RLock()
+	defer hookRegistryLock.RUnlock()
+
+	hook, ok := hookRegistry[address]
+
+	return hook, ok
+}
@@ -0,0 +1,62 @@
+package uniswapv4
+
+import (
+	"math/big"
+
+	v3Utils "github.com/daoleno/uniswapv3-sdk/utils"
+)
+
+func NewBig10(s string) (res *big.Int) {
+	res, _ = new(big.Int).SetString(s, 10)
+	return res
+}
+
+// getSwapFee mirrors ProtocolFeeLibrary.calculateSwapFee, the protocol fee is stored as 12 bits per direction
+func getSwapFee(protocolFee, lpFee uint32, zeroForOne bool) uint32 {
+	if zeroForOne {
+		protocolFee = protocolFee & 0xfff
+	} else {
+		protocolFee = protocolFee >> 12
+	}
+
+	if protocolFee == 0 {
+		return lpFee
+	}
+
+	return protocolFee + lpFee - uint32(uint64(protocolFee)*uint64(lpFee)/uint64(MaxLpFee))
+}
+
+// calcReserves sums the token amounts held by every initialized range of the pool. The PoolManager holds the
+// balances of all pools, so this is the only way to get per-pool reserves.
+func calcReserves(ticks []Tick, sqrtPriceX96 *big.Int, tickCurrent int) (*big.Int, *big.Int, error) {
+	reserve0, reserve1 := big.NewInt(0), big.NewInt(0)
+	liquidity := big.NewInt(0)
+
+	for i := 0; i < len(ticks)-1; i++ {
+		liquidity = new(big.Int).Add(liquidity, ticks[i].LiquidityNet)
+		if liquidity.Sign() <= 0 {
+			continue
+		}
+
+		sqrtLower, err := v3Utils.GetSqrtRatioAtTick(ticks[i].Index)
+		if err != nil {
+			return nil, nil, err
+		}
+		sqrtUpper, err := v3Utils.GetSqrtRatioAtTick(ticks[i+1].Index)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch {
+		case tickCurrent < ticks[i].Index:
+			reserve0.Add(reserve0, v3Utils.GetAmount0Delta(sqrtLower, sqrtUpper, liquidity, false))
+		case tickCurrent >= ticks[i+1].Index:
+			reserve1.Add(reserve1, v3Utils.GetAmount1Delta(sqrtLower, sqrtUpper, liquidity, false))
+		default:
+			reserve0.Add(reserve0, v3Utils.GetAmount0Delta(sqrtPriceX96, sqrtUpper, liquidity, false))
+			reserve1.Add(reserve1, v3Utils.GetAmount1Delta(sqrtLower, sqrtPriceX96, liquidity, false))
+		}
+	}
+
+	return reserve0, reserve1, nil
+}
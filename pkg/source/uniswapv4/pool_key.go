@@ -0,0 +1,26 @@
+package uniswapv4
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ID returns keccak256(abi.encode(key)), the same value as PoolIdLibrary.toId
+func (k PoolKey) ID() common.Hash {
+	var buf [160]byte
+	copy(buf[12:32], common.HexToAddress(k.Currency0).Bytes())
+	copy(buf[44:64], common.HexToAddress(k.Currency1).Bytes())
+	new(big.Int).SetUint64(uint64(k.Fee)).FillBytes(buf[64:96])
+	copy(buf[96:128], math.U256Bytes(big.NewInt(int64(k.TickSpacing))))
+	copy(buf[140:160], common.HexToAddress(k.Hooks).Bytes())
+
+	return crypto.Keccak256Hash(buf[:])
+}
+
+// IsDynamicFee reports whether the LP fee of the pool is managed by its hook
+func (k PoolKey) IsDynamicFee() bool {
+	return k.Fee == DynamicFeeFlag
+}
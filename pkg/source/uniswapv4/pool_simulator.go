@@ -0,0 +1,306 @@
+package uniswapv4
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/KyberNetwork/logger"
+	coreEntities "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/daoleno/uniswapv3-sdk/constants"
+	v3Entities "github.com/daoleno/uniswapv3-sdk/entities"
+	v3Utils "github.com/daoleno/uniswapv3-sdk/utils"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+// PoolSimulator reuses the uniswapv3 swap math: the v4 concentrated liquidity core is unchanged, only the fee
+// (dynamic LP fee plus protocol fee) and the hooks around the swap differ.
+type PoolSimulator struct {
+	V4Pool *v3Entities.Pool
+	pool.Pool
+	poolKey     PoolKey
+	poolManager string
+	permissions HookPermissions
+	hook        Hook
+	lpFee       uint32
+	protocolFee uint32
+	gas         Gas
+	tickMin     int
+	tickMax     int
+	// tickLower and tickUpper bound the ticks scanned by the tracker, see Extra
+	tickLower *int
+	tickUpper *int
+}
+
+func NewPoolSimulator(entityPool entity.Pool, chainID valueobject.ChainID) (*PoolSimulator, error) {
+	var extra Extra
+	if err := json.Unmarshal([]byte(entityPool.Extra), &extra); err != nil {
+		return nil, err
+	}
+
+	var staticExtra StaticExtra
+	if err := json.Unmarshal([]byte(entityPool.StaticExtra), &staticExtra); err != nil {
+		return nil, err
+	}
+
+	if extra.Tick == nil {
+		return nil, ErrTickNil
+	}
+
+	if extra.LpFee > MaxLpFee {
+		return nil, ErrInvalidFee
+	}
+
+	// Use the raw currencies so the sdk keeps the PoolManager ordering, the native currency is address zero
+	token0 := coreEntities.NewToken(uint(chainID), common.HexToAddress(staticExtra.Currency0), uint(entityPool.Tokens[0].Decimals), entityPool.Tokens[0].Symbol, entityPool.Tokens[0].Name)
+	token1 := coreEntities.NewToken(uint(chainID), common.HexToAddress(staticExtra.Currency1), uint(entityPool.Tokens[1].Decimals), entityPool.Tokens[1].Symbol, entityPool.Tokens[1].Name)
+
+	tokens := make([]string, 2)
+	reserves := make([]*big.Int, 2)
+	if len(entityPool.Reserves) == 2 && len(entityPool.Tokens) == 2 {
+		tokens[0] = entityPool.Tokens[0].Address
+		reserves[0] = NewBig10(entityPool.Reserves[0])
+		tokens[1] = entityPool.Tokens[1].Address
+		reserves[1] = NewBig10(entityPool.Reserves[1])
+	}
+
+	var v4Ticks []v3Entities.Tick
+	for _, t := range extra.Ticks {
+		// LiquidityGross = 0 means that the tick is uninitialized
+		if t.LiquidityGross.Cmp(zeroBI) == 0 {
+			continue
+		}
+
+		v4Ticks = append(v4Ticks, v3Entities.Tick{
+			Index:          t.Index,
+			LiquidityGross: t.LiquidityGross,
+			LiquidityNet:   t.LiquidityNet,
+		})
+	}
+
+	// if the tick list is empty, the pool should be ignored
+	if len(v4Ticks) == 0 {
+		return nil, ErrV4TicksEmpty
+	}
+
+	ticks, err := v3Entities.NewTickListDataProvider(v4Ticks, staticExtra.TickSpacing)
+	if err != nil {
+		return nil, err
+	}
+
+	v4Pool, err := v3Entities.NewPool(
+		token0,
+		token1,
+		constants.FeeAmount(extra.LpFee),
+		extra.SqrtPriceX96,
+		extra.Liquidity,
+		int(extra.Tick.Int64()),
+		ticks,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	hook, _ := GetHook(common.HexToAddress(staticExtra.Hooks))
+
+	var info = pool.PoolInfo{
+		Address:    strings.ToLower(entityPool.Address),
+		ReserveUsd: entityPool.ReserveUsd,
+		SwapFee:    big.NewInt(int64(extra.LpFee)),
+		Exchange:   entityPool.Exchange,
+		Type:       entityPool.Type,
+		Tokens:     tokens,
+		Reserves:   reserves,
+		Checked:    false,
+	}
+
+	return &PoolSimulator{
+		Pool:        pool.Pool{Info: info},
+		V4Pool:      v4Pool,
+		poolKey:     staticExtra.PoolKey,
+		poolManager: staticExtra.PoolManager,
+		permissions: GetHookPermissions(staticExtra.Hooks),
+		hook:        hook,
+		lpFee:       extra.LpFee,
+		protocolFee: extra.ProtocolFee,
+		gas:         defaultGas,
+		tickMin:     v4Ticks[0].Index,
+		tickMax:     v4Ticks[len(v4Ticks)-1].Index,
+		tickLower:   extra.TickLower,
+		tickUpper:   extra.TickUpper,
+	}, nil
+}
+
+// isWindowExhausted tells if a swap stopped at the end of the ticks scanned by the tracker, the swap may not have spent
+// all of its amount in
+func (p *PoolSimulator) isWindowExhausted(zeroForOne bool, sqrtPriceX96 *big.Int) bool {
+	tickLimit := p.tickUpper
+	if zeroForOne {
+		tickLimit = p.tickLower
+	}
+	if tickLimit == nil {
+		return false
+	}
+
+	sqrtPriceX96Limit, err := v3Utils.GetSqrtRatioAtTick(*tickLimit)
+	if err != nil {
+		return false
+	}
+
+	return sqrtPriceX96.Cmp(sqrtPriceX96Limit) == 0
+}
+
+/**
+ * getSqrtPriceLimit get the price limit of pool based on the initialized ticks that this pool has
+ */
+func (p *PoolSimulator) getSqrtPriceLimit(zeroForOne bool) *big.Int {
+	var tickLimit int
+	if zeroForOne {
+		tickLimit = p.tickMin
+	} else {
+		tickLimit = p.tickMax
+	}
+
+	sqrtPriceX96Limit, err := v3Utils.GetSqrtRatioAtTick(tickLimit)
+
+	if err != nil {
+		return nil
+	}
+
+	return sqrtPriceX96Limit
+}
+
+func (p *PoolSimulator) CalcAmountOut(
+	tokenAmountIn pool.TokenAmount,
+	tokenOut string,
+) (*pool.CalcAmountOutResult, error) {
+	var tokenInIndex = p.GetTokenIndex(tokenAmountIn.Token)
+	var tokenOutIndex = p.GetTokenIndex(tokenOut)
+	if tokenInIndex < 0 || tokenOutIndex < 0 || tokenInIndex == tokenOutIndex {
+		return &pool.CalcAmountOutResult{}, fmt.Errorf("tokenInIndex %v or tokenOutIndex %v is not correct", tokenInIndex, tokenOutIndex)
+	}
+
+	if p.permissions.ModifiesSwapDeltas() && p.hook == nil {
+		return &pool.CalcAmountOutResult{}, ErrUnsupportedHook
+	}
+
+	var (
+		zeroForOne   = tokenInIndex == 0
+		tokenIn      = p.V4Pool.Token1
+		amountToSwap = tokenAmountIn.Amount
+		lpFee        = p.lpFee
+		totalGas     = p.gas.Swap
+		beforeSwap   BeforeSwapResult
+	)
+	if zeroForOne {
+		tokenIn = p.V4Pool.Token0
+	}
+
+	if p.hook != nil && p.permissions.Has(BeforeSwap) {
+		var err error
+		beforeSwap, err = p.hook.BeforeSwap(BeforeSwapParams{
+			PoolKey:    p.poolKey,
+			ZeroForOne: zeroForOne,
+			AmountIn:   tokenAmountIn.Amount,
+			LpFee:      lpFee,
+		})
+		if err != nil {
+			return &pool.CalcAmountOutResult{}, err
+		}
+
+		if beforeSwap.OverrideFee {
+			lpFee = beforeSwap.LpFee
+		}
+		if beforeSwap.DeltaSpecified != nil && p.permissions.Has(BeforeSwapReturnsDelta) {
+			amountToSwap = new(big.Int).Sub(amountToSwap, beforeSwap.DeltaSpecified)
+			if amountToSwap.Sign() < 0 {
+				return &pool.CalcAmountOutResult{}, ErrHookDeltaExceeded
+			}
+		}
+		totalGas += p.gas.Hook
+	}
+
+	if lpFee > MaxLpFee {
+		return &pool.CalcAmountOutResult{}, ErrInvalidFee
+	}
+
+	// the sdk pool is shared between quotes, swap on a copy with the fee of this swap
+	v4Pool := *p.V4Pool
+	v4Pool.Fee = constants.FeeAmount(getSwapFee(p.protocolFee, lpFee, zeroForOne))
+
+	amountIn := coreEntities.FromRawAmount(tokenIn, amountToSwap)
+	swapAmountOut, newPoolState, err := v4Pool.GetOutputAmount(amountIn, p.getSqrtPriceLimit(zeroForOne))
+	if err != nil {
+		return &pool.CalcAmountOutResult{}, fmt.Errorf("can not GetOutputAmount, err: %+v", err)
+	}
+	if p.isWindowExhausted(zeroForOne, newPoolState.SqrtRatioX96) {
+		return &pool.CalcAmountOutResult{}, ErrWindowExhausted
+	}
+
+	amountOut := swapAmountOut.Quotient()
+	if beforeSwap.DeltaUnspecified != nil && p.permissions.Has(BeforeSwapReturnsDelta) {
+		amountOut = new(big.Int).Sub(amountOut, beforeSwap.DeltaUnspecified)
+	}
+
+	if p.hook != nil && p.permissions.Has(AfterSwap) {
+		afterSwapDelta, err := p.hook.AfterSwap(AfterSwapParams{
+			PoolKey:    p.poolKey,
+			ZeroForOne: zeroForOne,
+			AmountIn:   tokenAmountIn.Amount,
+			AmountOut:  amountOut,
+		})
+		if err != nil {
+			return &pool.CalcAmountOutResult{}, err
+		}
+
+		if afterSwapDelta != nil && p.permissions.Has(AfterSwapReturnsDelta) {
+			amountOut = new(big.Int).Sub(amountOut, afterSwapDelta)
+		}
+		totalGas += p.gas.Hook
+	}
+
+	if amountOut.Cmp(zeroBI) <= 0 {
+		return &pool.CalcAmountOutResult{}, ErrZeroAmountOut
+	}
+
+	return &pool.CalcAmountOutResult{
+		TokenAmountOut: &pool.TokenAmount{
+			Token:  tokenOut,
+			Amount: amountOut,
+		},
+		Fee: &pool.TokenAmount{
+			Token:  tokenAmountIn.Token,
+			Amount: nil,
+		},
+		Gas: totalGas,
+		SwapInfo: SwapInfo{
+			nextStateSqrtRatioX96: new(big.Int).Set(newPoolState.SqrtRatioX96),
+			nextStateLiquidity:    new(big.Int).Set(newPoolState.Liquidity),
+			nextStateTickCurrent:  newPoolState.TickCurrent,
+		},
+	}, nil
+}
+
+func (p *PoolSimulator) UpdateBalance(params pool.UpdateBalanceParams) {
+	si, ok := params.SwapInfo.(SwapInfo)
+	if !ok {
+		logger.Warn("failed to UpdateBalance for UniV4 pool, wrong swapInfo type")
+		return
+	}
+	p.V4Pool.SqrtRatioX96 = si.nextStateSqrtRatioX96
+	p.V4Pool.Liquidity = si.nextStateLiquidity
+	p.V4Pool.TickCurrent = si.nextStateTickCurrent
+}
+
+func (p *PoolSimulator) GetMetaInfo(_ string, _ string) interface{} {
+	return MetaInfo{
+		PoolManager: p.poolManager,
+		PoolKey:     p.poolKey,
+		Approximate: p.permissions.HasSwapHooks() && p.hook == nil,
+	}
+}
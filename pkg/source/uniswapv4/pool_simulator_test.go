@@ -0,0 +1,214 @@
+package uniswapv4
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/uniswapv3"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+const (
+	testToken0 = "0x6b175474e89094c44da98b954eedeac495271d0f"
+	testToken1 = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	testExtra  = "{\"liquidity\":1000000000000000000000,\"sqrtPriceX96\":79228162514264337593543950336,\"tick\":0,\"protocolFee\":%d,\"lpFee\":3000,\"ticks\":[{\"index\":-887220,\"liquidityGross\":1000000000000000000000,\"liquidityNet\":1000000000000000000000},{\"index\":887220,\"liquidityGross\":1000000000000000000000,\"liquidityNet\":-1000000000000000000000}]}"
+)
+
+func newTestEntityPool(t *testing.T, hooks string, protocolFee uint32) entity.Pool {
+	poolKey := PoolKey{
+		Currency0:   testToken0,
+		Currency1:   testToken1,
+		Fee:         3000,
+		TickSpacing: 60,
+		Hooks:       hooks,
+	}
+	staticExtraBytes, err := json.Marshal(StaticExtra{PoolKey: poolKey})
+	require.NoError(t, err)
+
+	return entity.Pool{
+		Address:  poolKey.ID().Hex(),
+		SwapFee:  3000,
+		Reserves: entity.PoolReserves{"1000000000000000000000", "1000000000000000000000"},
+		Tokens: []*entity.PoolToken{
+			{Address: testToken0, Decimals: 18},
+			{Address: testToken1, Decimals: 18},
+		},
+		Extra:       fmt.Sprintf(testExtra, protocolFee),
+		StaticExtra: string(staticExtraBytes),
+	}
+}
+
+func TestPoolSimulator_CalcAmountOut_MatchesV3(t *testing.T) {
+	entityPool := newTestEntityPool(t, valueobject.ZeroAddress, 0)
+	v4Pool, err := NewPoolSimulator(entityPool, valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+
+	entityPool.Extra = "{\"liquidity\":1000000000000000000000,\"sqrtPriceX96\":79228162514264337593543950336,\"tick\":0,\"ticks\":[{\"index\":-887220,\"liquidityGross\":1000000000000000000000,\"liquidityNet\":1000000000000000000000},{\"index\":887220,\"liquidityGross\":1000000000000000000000,\"liquidityNet\":-1000000000000000000000}]}"
+	v3Pool, err := uniswapv3.NewPoolSimulator(entityPool, valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+
+	for _, tokenIn := range []string{testToken0, testToken1} {
+		tokenOut := v4Pool.CanSwapTo(tokenIn)[0]
+		amountIn := pool.TokenAmount{Token: tokenIn, Amount: big.NewInt(1e18)}
+
+		v4Result, err := v4Pool.CalcAmountOut(amountIn, tokenOut)
+		require.NoError(t, err)
+		v3Result, err := v3Pool.CalcAmountOut(amountIn, tokenOut)
+		require.NoError(t, err)
+
+		assert.Equal(t, v3Result.TokenAmountOut.Amount, v4Result.TokenAmountOut.Amount)
+	}
+}
+
+func TestPoolSimulator_ProtocolFee(t *testing.T) {
+	// 0.1% protocol fee on zeroForOne only
+	v4Pool, err := NewPoolSimulator(newTestEntityPool(t, valueobject.ZeroAddress, 1000), valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+	noFeePool, err := NewPoolSimulator(newTestEntityPool(t, valueobject.ZeroAddress, 0), valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+
+	amountIn := pool.TokenAmount{Token: testToken0, Amount: big.NewInt(1e18)}
+	withFee, err := v4Pool.CalcAmountOut(amountIn, testToken1)
+	require.NoError(t, err)
+	withoutFee, err := noFeePool.CalcAmountOut(amountIn, testToken1)
+	require.NoError(t, err)
+	assert.Equal(t, -1, withFee.TokenAmountOut.Amount.Cmp(withoutFee.TokenAmountOut.Amount))
+
+	amountIn = pool.TokenAmount{Token: testToken1, Amount: big.NewInt(1e18)}
+	withFee, err = v4Pool.CalcAmountOut(amountIn, testToken0)
+	require.NoError(t, err)
+	withoutFee, err = noFeePool.CalcAmountOut(amountIn, testToken0)
+	require.NoError(t, err)
+	assert.Equal(t, withoutFee.TokenAmountOut.Amount, withFee.TokenAmountOut.Amount)
+
+	assert.Equal(t, uint32(3000), getSwapFee(1000, 3000, false))
+	assert.Equal(t, uint32(3997), getSwapFee(1000, 3000, true))
+}
+
+type testHook struct {
+	fee *big.Int
+}
+
+func (h testHook) BeforeSwap(params BeforeSwapParams) (BeforeSwapResult, error) {
+	return BeforeSwapResult{
+		DeltaSpecified: new(big.Int).Div(new(big.Int).Mul(params.AmountIn, h.fee), big.NewInt(100)),
+		LpFee:          500,
+		OverrideFee:    true,
+	}, nil
+}
+
+func (h testHook) AfterSwap(_ AfterSwapParams) (*big.Int, error) {
+	return nil, nil
+}
+
+func TestPoolSimulator_Hooks(t *testing.T) {
+	amountIn := pool.TokenAmount{Token: testToken0, Amount: big.NewInt(1e18)}
+
+	// beforeSwap | beforeSwapReturnsDelta
+	deltaHook := "0x0000000000000000000000000000000000000088"
+	v4Pool, err := NewPoolSimulator(newTestEntityPool(t, deltaHook, 0), valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+	_, err = v4Pool.CalcAmountOut(amountIn, testToken1)
+	assert.ErrorIs(t, err, ErrUnsupportedHook)
+
+	// a hook that only runs on liquidity changes does not affect quoting
+	liquidityHook := "0x0000000000000000000000000000000000000a00"
+	v4Pool, err = NewPoolSimulator(newTestEntityPool(t, liquidityHook, 0), valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+	_, err = v4Pool.CalcAmountOut(amountIn, testToken1)
+	require.NoError(t, err)
+	assert.False(t, v4Pool.GetMetaInfo(testToken0, testToken1).(MetaInfo).Approximate)
+
+	RegisterHook(testHook{fee: big.NewInt(1)}, common.HexToAddress(deltaHook))
+	v4Pool, err = NewPoolSimulator(newTestEntityPool(t, deltaHook, 0), valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+	hooked, err := v4Pool.CalcAmountOut(amountIn, testToken1)
+	require.NoError(t, err)
+
+	// the hook takes 1% of the amount in and sets the LP fee to 0.05%
+	v4Pool, err = NewPoolSimulator(newTestEntityPool(t, valueobject.ZeroAddress, 0), valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+	v4Pool.lpFee = 500
+	expected, err := v4Pool.CalcAmountOut(pool.TokenAmount{Token: testToken0, Amount: big.NewInt(99e16)}, testToken1)
+	require.NoError(t, err)
+	assert.Equal(t, expected.TokenAmountOut.Amount, hooked.TokenAmountOut.Amount)
+	assert.Equal(t, defaultGas.Swap+defaultGas.Hook, hooked.Gas)
+}
+
+func TestParseInitializeLog(t *testing.T) {
+	poolKey := PoolKey{
+		Currency0:   "0x0000000000000000000000000000000000000000",
+		Currency1:   testToken1,
+		Fee:         DynamicFeeFlag,
+		TickSpacing: 10,
+		Hooks:       "0x0000000000000000000000000000000000000080",
+	}
+
+	event := poolManagerABI.Events[poolManagerEventInitialize]
+	data, err := event.Inputs.NonIndexed().Pack(big.NewInt(int64(poolKey.Fee)), big.NewInt(int64(poolKey.TickSpacing)),
+		common.HexToAddress(poolKey.Hooks), big.NewInt(1), big.NewInt(0))
+	require.NoError(t, err)
+
+	parsed, err := parseInitializeLog(types.Log{
+		Topics: []common.Hash{
+			event.ID,
+			poolKey.ID(),
+			common.BytesToHash(common.HexToAddress(poolKey.Currency0).Bytes()),
+			common.BytesToHash(common.HexToAddress(poolKey.Currency1).Bytes()),
+		},
+		Data: data,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, poolKey, parsed)
+	assert.True(t, parsed.IsDynamicFee())
+	assert.True(t, GetHookPermissions(parsed.Hooks).HasSwapHooks())
+	assert.False(t, GetHookPermissions(parsed.Hooks).ModifiesSwapDeltas())
+
+	_, err = parseInitializeLog(types.Log{
+		Topics: []common.Hash{event.ID, {}, {}, {}},
+		Data:   data,
+	})
+	assert.ErrorIs(t, err, ErrInvalidPoolId)
+}
+
+func TestPoolSimulator_WindowExhausted(t *testing.T) {
+	liquidity := big.NewInt(1e18)
+	ticks, tickLower, tickUpper := closeTickWindow(nil, liquidity, 0, -600, 600)
+	require.Len(t, ticks, 2)
+	assert.Equal(t, -600, *tickLower)
+	assert.Equal(t, 600, *tickUpper)
+	assert.Equal(t, liquidity, ticks[0].LiquidityNet)
+	assert.Equal(t, new(big.Int).Neg(liquidity), ticks[1].LiquidityNet)
+
+	entityPool := newTestEntityPool(t, valueobject.ZeroAddress, 0)
+	extraBytes, err := json.Marshal(Extra{
+		Liquidity:    liquidity,
+		SqrtPriceX96: new(big.Int).Lsh(big.NewInt(1), 96),
+		Tick:         big.NewInt(0),
+		LpFee:        3000,
+		Ticks:        ticks,
+		TickLower:    tickLower,
+		TickUpper:    tickUpper,
+	})
+	require.NoError(t, err)
+	entityPool.Extra = string(extraBytes)
+	v4Pool, err := NewPoolSimulator(entityPool, valueobject.ChainIDEthereum)
+	require.NoError(t, err)
+
+	_, err = v4Pool.CalcAmountOut(pool.TokenAmount{Token: testToken0, Amount: big.NewInt(1e16)}, testToken1)
+	require.NoError(t, err)
+
+	_, err = v4Pool.CalcAmountOut(pool.TokenAmount{Token: testToken0, Amount: big.NewInt(1e18)}, testToken1)
+	assert.ErrorIs(t, err, ErrWindowExhausted)
+	_, err = v4Pool.CalcAmountOut(pool.TokenAmount{Token: testToken1, Amount: big.NewInt(1e18)}, testToken0)
+	assert.ErrorIs(t, err, ErrWindowExhausted)
+}
@@ -0,0 +1,289 @@
+package uniswapv4
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	v3Utils "github.com/daoleno/uniswapv3-sdk/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/samber/lo"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	sourcePool "github.com/KyberNetwork/kyberswap-dex-lib/pkg/source/pool"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/util"
+)
+
+const (
+	multicallBatchSize = 500
+	maxWordSize        = 256
+)
+
+type PoolTracker struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+}
+
+func NewPoolTracker(
+	cfg *Config,
+	ethrpcClient *ethrpc.Client,
+) *PoolTracker {
+	if cfg.TickWordRange <= 0 {
+		cfg.TickWordRange = defaultTickWordRange
+	}
+	return &PoolTracker{
+		config:       cfg,
+		ethrpcClient: ethrpcClient,
+	}
+}
+
+func (d *PoolTracker) GetNewPoolState(
+	ctx context.Context,
+	p entity.Pool,
+	_ sourcePool.GetNewPoolStateParams,
+) (entity.Pool, error) {
+	logger.Infof("[%s] Start getting new state of pool: %v", d.config.DexID, p.Address)
+
+	var staticExtra StaticExtra
+	if err := json.Unmarshal([]byte(p.StaticExtra), &staticExtra); err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to unmarshal static extra")
+		return entity.Pool{}, err
+	}
+
+	poolId := common.HexToHash(p.Address)
+
+	var (
+		liquidity *big.Int
+		slot0     Slot0
+	)
+
+	rpcRequest := d.ethrpcClient.NewRequest()
+	rpcRequest.SetContext(ctx)
+	rpcRequest.AddCall(&ethrpc.Call{
+		ABI:    stateViewABI,
+		Target: d.config.StateViewAddress,
+		Method: stateViewMethodGetLiquidity,
+		Params: []interface{}{poolId},
+	}, []interface{}{&liquidity})
+	rpcRequest.AddCall(&ethrpc.Call{
+		ABI:    stateViewABI,
+		Target: d.config.StateViewAddress,
+		Method: stateViewMethodGetSlot0,
+		Params: []interface{}{poolId},
+	}, []interface{}{&slot0})
+
+	if _, err := rpcRequest.Aggregate(); err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to fetch data from RPC")
+		return entity.Pool{}, err
+	}
+
+	ticks, tickLower, tickUpper, err := d.getPoolTicks(ctx, poolId, staticExtra.TickSpacing, slot0, liquidity)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to fetch pool ticks from state view")
+		return entity.Pool{}, err
+	}
+
+	reserve0, reserve1, err := calcReserves(ticks, slot0.SqrtPriceX96, int(slot0.Tick.Int64()))
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to calculate reserves")
+		return entity.Pool{}, err
+	}
+
+	extraBytes, err := json.Marshal(Extra{
+		Liquidity:    liquidity,
+		SqrtPriceX96: slot0.SqrtPriceX96,
+		Tick:         slot0.Tick,
+		ProtocolFee:  uint32(slot0.ProtocolFee.Uint64()),
+		LpFee:        uint32(slot0.LpFee.Uint64()),
+		Ticks:        ticks,
+		TickLower:    tickLower,
+		TickUpper:    tickUpper,
+	})
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"poolAddress": p.Address,
+			"error":       err,
+		}).Errorf("failed to marshal extra data")
+		return entity.Pool{}, err
+	}
+
+	p.Extra = string(extraBytes)
+	p.Timestamp = time.Now().Unix()
+	p.Reserves = entity.PoolReserves{
+		reserve0.String(),
+		reserve1.String(),
+	}
+
+	logger.Infof("[%s] Finish updating state of pool: %v", d.config.DexID, p.Address)
+
+	return p, nil
+}
+
+// getPoolTicks reads the words of the tick bitmap within TickWordRange of the word of the current tick from the
+// StateView contract, then the liquidity of every initialized tick. A side of the scan not reaching the end of the
+// ticks is closed by a tick out of the scanned words, see closeTickWindow, which is returned as its bound.
+func (d *PoolTracker) getPoolTicks(
+	ctx context.Context,
+	poolId common.Hash,
+	tickSpacing int,
+	slot0 Slot0,
+	liquidity *big.Int,
+) ([]Tick, *int, *int, error) {
+	poolMinWordIdx := (v3Utils.MinTick/tickSpacing - 1) >> 8
+	poolMaxWordIdx := (v3Utils.MaxTick / tickSpacing) >> 8
+	currentTick := int(slot0.Tick.Int64())
+	currentWordIdx := getWordIndex(currentTick, tickSpacing)
+	minWordIdx := max(currentWordIdx-d.config.TickWordRange, poolMinWordIdx)
+	maxWordIdx := min(currentWordIdx+d.config.TickWordRange, poolMaxWordIdx)
+
+	wordIndexes := make([]int16, 0, maxWordIdx-minWordIdx+1)
+	for idx := minWordIdx; idx <= maxWordIdx; idx++ {
+		wordIndexes = append(wordIndexes, int16(idx))
+	}
+
+	var tickIndexes []int
+	for _, chunk := range lo.Chunk[int16](wordIndexes, multicallBatchSize) {
+		rpcRequest := d.ethrpcClient.NewRequest()
+		rpcRequest.SetContext(util.NewContextWithTimestamp(ctx))
+
+		bitmaps := make([]*big.Int, len(chunk))
+		for i, wordIndex := range chunk {
+			rpcRequest.AddCall(&ethrpc.Call{
+				ABI:    stateViewABI,
+				Target: d.config.StateViewAddress,
+				Method: stateViewMethodGetTickBitmap,
+				Params: []interface{}{poolId, wordIndex},
+			}, []interface{}{&bitmaps[i]})
+		}
+
+		if _, err := rpcRequest.Aggregate(); err != nil {
+			return nil, nil, nil, err
+		}
+
+		for i, bitmap := range bitmaps {
+			if bitmap == nil || bitmap.Sign() == 0 {
+				continue
+			}
+
+			for bit := 0; bit < maxWordSize; bit++ {
+				if bitmap.Bit(bit) == 1 {
+					tickIndexes = append(tickIndexes, ((int(chunk[i])<<8)+bit)*tickSpacing)
+				}
+			}
+		}
+	}
+
+	ticks := make([]Tick, 0, len(tickIndexes))
+	for _, chunk := range lo.Chunk[int](tickIndexes, multicallBatchSize) {
+		rpcRequest := d.ethrpcClient.NewRequest()
+		rpcRequest.SetContext(util.NewContextWithTimestamp(ctx))
+
+		liquidities := make([]tickLiquidity, len(chunk))
+		for i, tickIndex := range chunk {
+			rpcRequest.AddCall(&ethrpc.Call{
+				ABI:    stateViewABI,
+				Target: d.config.StateViewAddress,
+				Method: stateViewMethodGetTickLiquidity,
+				Params: []interface{}{poolId, big.NewInt(int64(tickIndex))},
+			}, []interface{}{&liquidities[i]})
+		}
+
+		if _, err := rpcRequest.Aggregate(); err != nil {
+			return nil, nil, nil, err
+		}
+
+		for i, tickIndex := range chunk {
+			ticks = append(ticks, Tick{
+				Index:          tickIndex,
+				LiquidityGross: liquidities[i].LiquidityGross,
+				LiquidityNet:   liquidities[i].LiquidityNet,
+			})
+		}
+	}
+
+	// Sort the ticks because function NewTickListDataProvider needs
+	sort.SliceStable(ticks, func(i, j int) bool {
+		return ticks[i].Index < ticks[j].Index
+	})
+
+	lowerTick, upperTick := minWordIdx*maxWordSize*tickSpacing-tickSpacing, (maxWordIdx+1)*maxWordSize*tickSpacing
+	if minWordIdx == poolMinWordIdx {
+		lowerTick = v3Utils.MinTick - 1
+	}
+	if maxWordIdx == poolMaxWordIdx {
+		upperTick = v3Utils.MaxTick + 1
+	}
+	ticks, tickLower, tickUpper := closeTickWindow(ticks, liquidity, currentTick, lowerTick, upperTick)
+
+	return ticks, tickLower, tickUpper, nil
+}
+
+// closeTickWindow closes the sides of the ticks scanned around the current tick. lowerTick, the last tick below the
+// scanned words, adds the liquidity left after crossing down all the scanned ticks and upperTick, the first tick above
+// them, removes the liquidity left after crossing them up, so the liquidity net of the ticks still sums to zero and
+// the simulator stops at the end of the scan. A side without liquidity at its end is bounded by its last scanned tick
+// instead, and a side whose bound is out of [MinTick, MaxTick] is complete. ticks must be sorted.
+func closeTickWindow(ticks []Tick, liquidity *big.Int, currentTick, lowerTick, upperTick int) ([]Tick, *int, *int) {
+	lowerLiquidity := new(big.Int).Set(liquidity)
+	upperLiquidity := new(big.Int).Set(liquidity)
+	for _, tick := range ticks {
+		if tick.Index <= currentTick {
+			lowerLiquidity.Sub(lowerLiquidity, tick.LiquidityNet)
+		} else {
+			upperLiquidity.Add(upperLiquidity, tick.LiquidityNet)
+		}
+	}
+
+	var tickLower, tickUpper *int
+	if lowerTick >= v3Utils.MinTick {
+		if lowerLiquidity.Sign() != 0 {
+			ticks = append([]Tick{{
+				Index:          lowerTick,
+				LiquidityGross: new(big.Int).Abs(lowerLiquidity),
+				LiquidityNet:   lowerLiquidity,
+			}}, ticks...)
+			tickLower = &lowerTick
+		} else if len(ticks) > 0 {
+			tickLower = &ticks[0].Index
+		}
+	}
+	if upperTick <= v3Utils.MaxTick {
+		if upperLiquidity.Sign() != 0 {
+			ticks = append(ticks, Tick{
+				Index:          upperTick,
+				LiquidityGross: new(big.Int).Abs(upperLiquidity),
+				LiquidityNet:   new(big.Int).Neg(upperLiquidity),
+			})
+			tickUpper = &upperTick
+		} else if len(ticks) > 0 {
+			tickUpper = &ticks[len(ticks)-1].Index
+		}
+	}
+
+	return ticks, tickLower, tickUpper
+}
+
+// getWordIndex returns the index of the word of the tick bitmap holding tick
+func getWordIndex(tick int, tickSpacing int) int {
+	compressed := tick / tickSpacing
+	if tick < 0 && tick%tickSpacing != 0 {
+		compressed--
+	}
+	return compressed >> 8
+}
@@ -0,0 +1,249 @@
+package uniswapv4
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/entity"
+	"github.com/KyberNetwork/kyberswap-dex-lib/pkg/valueobject"
+)
+
+// LogsClient is the part of ethclient.Client used to scan PoolManager events
+type LogsClient interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+}
+
+type PoolsListUpdater struct {
+	config       *Config
+	ethrpcClient *ethrpc.Client
+	logsClient   LogsClient
+}
+
+func NewPoolsListUpdater(
+	cfg *Config,
+	ethrpcClient *ethrpc.Client,
+	logsClient LogsClient,
+) *PoolsListUpdater {
+	return &PoolsListUpdater{
+		config:       cfg,
+		ethrpcClient: ethrpcClient,
+		logsClient:   logsClient,
+	}
+}
+
+func (d *PoolsListUpdater) GetNewPools(ctx context.Context, metadataBytes []byte) ([]entity.Pool, []byte, error) {
+	var metadata Metadata
+	if len(metadataBytes) != 0 {
+		if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
+			return nil, metadataBytes, err
+		}
+	}
+
+	fromBlock := d.config.StartBlock
+	if metadata.LastBlock >= fromBlock {
+		fromBlock = metadata.LastBlock + 1
+	}
+
+	latestBlock, err := d.logsClient.BlockNumber(ctx)
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"error": err,
+		}).Errorf("failed to get latest block")
+		return nil, metadataBytes, err
+	}
+	if fromBlock > latestBlock {
+		return nil, metadataBytes, nil
+	}
+
+	batchSize := d.config.BlockBatchSize
+	if batchSize == 0 {
+		batchSize = defaultBlockBatchSize
+	}
+	toBlock := fromBlock + batchSize - 1
+	if toBlock > latestBlock {
+		toBlock = latestBlock
+	}
+
+	logs, err := d.logsClient.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{common.HexToAddress(d.config.PoolManagerAddress)},
+		Topics:    [][]common.Hash{{poolManagerABI.Events[poolManagerEventInitialize].ID}},
+	})
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"fromBlock": fromBlock,
+			"toBlock":   toBlock,
+			"error":     err,
+		}).Errorf("failed to filter Initialize logs")
+		return nil, metadataBytes, err
+	}
+
+	pools, err := d.newPools(ctx, logs)
+	if err != nil {
+		return nil, metadataBytes, err
+	}
+
+	newMetadataBytes, err := json.Marshal(Metadata{
+		LastBlock: toBlock,
+	})
+	if err != nil {
+		return nil, metadataBytes, err
+	}
+
+	logger.Infof("got %v %s pools until block %v", len(pools), d.config.DexID, toBlock)
+
+	return pools, newMetadataBytes, nil
+}
+
+func (d *PoolsListUpdater) newPools(ctx context.Context, logs []types.Log) ([]entity.Pool, error) {
+	poolKeys := make([]PoolKey, 0, len(logs))
+	for _, log := range logs {
+		poolKey, err := parseInitializeLog(log)
+		if err != nil {
+			logger.WithFields(logger.Fields{
+				"txHash": log.TxHash.Hex(),
+				"error":  err,
+			}).Errorf("failed to parse Initialize log")
+			continue
+		}
+
+		poolKeys = append(poolKeys, poolKey)
+	}
+
+	decimals, err := d.getDecimals(ctx, poolKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	pools := make([]entity.Pool, 0, len(poolKeys))
+	for _, poolKey := range poolKeys {
+		staticExtraBytes, err := json.Marshal(StaticExtra{
+			PoolKey:     poolKey,
+			PoolManager: strings.ToLower(d.config.PoolManagerAddress),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var swapFee float64
+		if !poolKey.IsDynamicFee() {
+			swapFee = float64(poolKey.Fee)
+		}
+
+		pools = append(pools, entity.Pool{
+			Address:   strings.ToLower(poolKey.ID().Hex()),
+			SwapFee:   swapFee,
+			Exchange:  d.config.DexID,
+			Type:      DexTypeUniswapV4,
+			Timestamp: time.Now().Unix(),
+			Reserves:  entity.PoolReserves{zeroString, zeroString},
+			Tokens: []*entity.PoolToken{
+				{
+					Address:   d.tokenAddress(poolKey.Currency0),
+					Decimals:  decimals[poolKey.Currency0],
+					Weight:    defaultTokenWeight,
+					Swappable: true,
+				},
+				{
+					Address:   d.tokenAddress(poolKey.Currency1),
+					Decimals:  decimals[poolKey.Currency1],
+					Weight:    defaultTokenWeight,
+					Swappable: true,
+				},
+			},
+			StaticExtra: string(staticExtraBytes),
+		})
+	}
+
+	return pools, nil
+}
+
+// tokenAddress maps the native currency (address zero) to the wrapped native token used in routing
+func (d *PoolsListUpdater) tokenAddress(currency string) string {
+	if currency == strings.ToLower(valueobject.ZeroAddress) {
+		return strings.ToLower(valueobject.WETHByChainID[d.config.ChainID])
+	}
+
+	return currency
+}
+
+func (d *PoolsListUpdater) getDecimals(ctx context.Context, poolKeys []PoolKey) (map[string]uint8, error) {
+	decimals := map[string]uint8{strings.ToLower(valueobject.ZeroAddress): defaultTokenDecimals}
+
+	var currencies []string
+	for _, poolKey := range poolKeys {
+		for _, currency := range []string{poolKey.Currency0, poolKey.Currency1} {
+			if _, ok := decimals[currency]; !ok {
+				decimals[currency] = defaultTokenDecimals
+				currencies = append(currencies, currency)
+			}
+		}
+	}
+	if len(currencies) == 0 {
+		return decimals, nil
+	}
+
+	results := make([]uint8, len(currencies))
+	rpcRequest := d.ethrpcClient.NewRequest()
+	rpcRequest.SetContext(ctx)
+	for i, currency := range currencies {
+		rpcRequest.AddCall(&ethrpc.Call{
+			ABI:    erc20ABI,
+			Target: currency,
+			Method: erc20MethodDecimals,
+			Params: nil,
+		}, []interface{}{&results[i]})
+	}
+
+	resp, err := rpcRequest.TryAggregate()
+	if err != nil {
+		logger.WithFields(logger.Fields{
+			"error": err,
+		}).Errorf("failed to fetch token decimals")
+		return nil, err
+	}
+
+	for i, currency := range currencies {
+		if resp.Result[i] {
+			decimals[currency] = results[i]
+		}
+	}
+
+	return decimals, nil
+}
+
+func parseInitializeLog(log types.Log) (PoolKey, error) {
+	if len(log.Topics) < 4 {
+		return PoolKey{}, ErrInvalidPoolId
+	}
+
+	var event initializeEvent
+	if err := poolManagerABI.UnpackIntoInterface(&event, poolManagerEventInitialize, log.Data); err != nil {
+		return PoolKey{}, err
+	}
+
+	poolKey := PoolKey{
+		Currency0:   strings.ToLower(common.BytesToAddress(log.Topics[2].Bytes()).Hex()),
+		Currency1:   strings.ToLower(common.BytesToAddress(log.Topics[3].Bytes()).Hex()),
+		Fee:         uint32(event.Fee.Uint64()),
+		TickSpacing: int(event.TickSpacing.Int64()),
+		Hooks:       strings.ToLower(event.Hooks.Hex()),
+	}
+
+	if poolKey.ID() != log.Topics[1] {
+		return PoolKey{}, ErrInvalidPoolId
+	}
+
+	return poolKey, nil
+}
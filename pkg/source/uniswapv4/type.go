@@ -0,0 +1,84 @@
+package uniswapv4
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Gas struct {
+	Swap int64
+	Hook int64
+}
+
+// SwapInfo present the after state of a swap
+type SwapInfo struct {
+	nextStateSqrtRatioX96 *big.Int
+	nextStateLiquidity    *big.Int
+	nextStateTickCurrent  int
+}
+
+type Metadata struct {
+	LastBlock uint64 `json:"lastBlock"`
+}
+
+// PoolKey identifies a pool inside the PoolManager singleton, its keccak256 is the poolId
+type PoolKey struct {
+	Currency0   string `json:"currency0"`
+	Currency1   string `json:"currency1"`
+	Fee         uint32 `json:"fee"`
+	TickSpacing int    `json:"tickSpacing"`
+	Hooks       string `json:"hooks"`
+}
+
+type StaticExtra struct {
+	PoolKey
+	PoolManager string `json:"poolManager"`
+}
+
+type Tick struct {
+	Index          int      `json:"index"`
+	LiquidityGross *big.Int `json:"liquidityGross"`
+	LiquidityNet   *big.Int `json:"liquidityNet"`
+}
+
+type Extra struct {
+	Liquidity    *big.Int `json:"liquidity"`
+	SqrtPriceX96 *big.Int `json:"sqrtPriceX96"`
+	Tick         *big.Int `json:"tick"`
+	ProtocolFee  uint32   `json:"protocolFee"`
+	LpFee        uint32   `json:"lpFee"`
+	Ticks        []Tick   `json:"ticks"`
+	// TickLower and TickUpper are the ticks closing the scanned words below and above the current tick, a swap
+	// reaching them can not be quoted. They are nil when the scan reaches the end of the ticks on that side.
+	TickLower *int `json:"tickLower,omitempty"`
+	TickUpper *int `json:"tickUpper,omitempty"`
+}
+
+type MetaInfo struct {
+	PoolManager string  `json:"poolManager"`
+	PoolKey     PoolKey `json:"poolKey"`
+	// Approximate is set when the pool has swap hooks without a registered adapter, the quote uses the last
+	// fetched LP fee and may differ from what the hook applies on-chain
+	Approximate bool `json:"approximate"`
+}
+
+type Slot0 struct {
+	SqrtPriceX96 *big.Int `json:"sqrtPriceX96"`
+	Tick         *big.Int `json:"tick"`
+	ProtocolFee  *big.Int `json:"protocolFee"`
+	LpFee        *big.Int `json:"lpFee"`
+}
+
+type tickLiquidity struct {
+	LiquidityGross *big.Int
+	LiquidityNet   *big.Int
+}
+
+type initializeEvent struct {
+	Fee          *big.Int
+	TickSpacing  *big.Int
+	Hooks        common.Address
+	SqrtPriceX96 *big.Int
+	Tick         *big.Int
+}
@@ -0,0 +1,78 @@
+package ethrpctest
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RecordEnv is the environment variable which makes Dial and NewClient record the fixtures from the node instead of
+// replaying them, e.g. ETHRPC_RECORD=1 go test ./pkg/source/...
+const RecordEnv = "ETHRPC_RECORD"
+
+// Dial returns an ethclient.Client replaying the fixture at path. When RecordEnv is set, the client sends the requests
+// to the node at rpcURL instead and the traffic is written to the fixture when the test ends. The test fails when the
+// fixture was not recorded, the fixtures are committed with the tests using them.
+func Dial(t testing.TB, path string, rpcURL string) *ethclient.Client {
+	t.Helper()
+
+	if os.Getenv(RecordEnv) != "" {
+		return dialRecording(t, path, rpcURL)
+	}
+
+	fixture, err := LoadFixture(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("no fixture at %s, record it with %s=1 against %s", path, RecordEnv, rpcURL)
+	}
+	if err != nil {
+		t.Fatalf("failed to load fixture: %v", err)
+	}
+
+	server := NewServer(fixture)
+	t.Cleanup(server.Close)
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatalf("failed to dial replay server: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+// NewClient returns an ethrpc.Client over Dial, the multicall contract still has to be set
+func NewClient(t testing.TB, path string, rpcURL string) *ethrpc.Client {
+	t.Helper()
+
+	return ethrpc.NewWithClient(Dial(t, path, rpcURL))
+}
+
+func dialRecording(t testing.TB, path string, rpcURL string) *ethclient.Client {
+	recorder := NewRecorder(nil)
+	rpcClient, err := rpc.DialOptions(context.Background(), rpcURL, rpc.WithHTTPClient(&http.Client{Transport: recorder}))
+	if err != nil {
+		t.Fatalf("failed to dial %s: %v", rpcURL, err)
+	}
+	client := ethclient.NewClient(rpcClient)
+
+	t.Cleanup(func() {
+		client.Close()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("failed to create fixture directory: %v", err)
+			return
+		}
+		if err := recorder.Fixture().Save(path); err != nil {
+			t.Errorf("failed to save fixture: %v", err)
+		}
+	})
+
+	return client
+}
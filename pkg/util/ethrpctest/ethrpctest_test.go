@@ -0,0 +1,166 @@
+package ethrpctest
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/KyberNetwork/ethrpc"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const balanceOfABI = `[{"inputs":[{"name":"account","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+var (
+	token   = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	account = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+)
+
+// fakeEth is the eth namespace of a node, its block number moves on every call so the order of replayed responses
+// is checked
+type fakeEth struct {
+	blockNumber atomic.Uint64
+}
+
+func (e *fakeEth) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(e.blockNumber.Add(1))
+}
+
+func (e *fakeEth) Call(_ map[string]interface{}, _ string) hexutil.Bytes {
+	return common.LeftPadBytes(big.NewInt(1000).Bytes(), 32)
+}
+
+func (e *fakeEth) GetLogs(_ map[string]interface{}) []types.Log {
+	return []types.Log{{
+		Address:     token,
+		Topics:      []common.Hash{common.HexToHash("0x01")},
+		Data:        []byte{1},
+		BlockNumber: 10,
+	}}
+}
+
+func newNode(t *testing.T) *httptest.Server {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeEth{}))
+	node := httptest.NewServer(server)
+	t.Cleanup(node.Close)
+
+	return node
+}
+
+// exercise sends the requests of a tracker, a multicall-like eth_call, eth_getLogs and a batch, and returns what it
+// got
+func exercise(t *testing.T, client *ethclient.Client) (*big.Int, []types.Log, []uint64) {
+	erc20ABI, err := abi.JSON(strings.NewReader(balanceOfABI))
+	require.NoError(t, err)
+
+	var balance *big.Int
+	_, err = ethrpc.NewWithClient(client).NewRequest().AddCall(&ethrpc.Call{
+		ABI:    erc20ABI,
+		Target: token.Hex(),
+		Method: "balanceOf",
+		Params: []interface{}{account},
+	}, []interface{}{&balance}).Call()
+	require.NoError(t, err)
+
+	logs, err := client.FilterLogs(context.Background(), ethereum.FilterQuery{
+		FromBlock: big.NewInt(1),
+		ToBlock:   big.NewInt(10),
+		Addresses: []common.Address{token},
+	})
+	require.NoError(t, err)
+
+	var first, second, third hexutil.Uint64
+	require.NoError(t, client.Client().BatchCall([]rpc.BatchElem{
+		{Method: "eth_blockNumber", Result: &first},
+		{Method: "eth_blockNumber", Result: &second},
+	}))
+	require.NoError(t, client.Client().Call(&third, "eth_blockNumber"))
+
+	return balance, logs, []uint64{uint64(first), uint64(second), uint64(third)}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	node := newNode(t)
+	path := filepath.Join(t.TempDir(), "testdata", "fixture.json")
+
+	var (
+		recordedBalance      *big.Int
+		recordedLogs         []types.Log
+		recordedBlockNumbers []uint64
+	)
+	t.Run("record", func(t *testing.T) {
+		t.Setenv(RecordEnv, "1")
+		recordedBalance, recordedLogs, recordedBlockNumbers = exercise(t, Dial(t, path, node.URL))
+	})
+	assert.Equal(t, big.NewInt(1000), recordedBalance)
+	assert.Len(t, recordedLogs, 1)
+	assert.Equal(t, []uint64{1, 2, 3}, recordedBlockNumbers)
+
+	fixture, err := LoadFixture(path)
+	require.NoError(t, err)
+	assert.Len(t, fixture.Interactions, 5)
+
+	// the node is not needed anymore
+	node.Close()
+
+	t.Run("replay", func(t *testing.T) {
+		client := Dial(t, path, node.URL)
+		balance, logs, blockNumbers := exercise(t, client)
+		assert.Equal(t, recordedBalance, balance)
+		assert.Equal(t, recordedLogs, logs)
+		assert.Equal(t, recordedBlockNumbers, blockNumbers)
+
+		// the last response is repeated once all are served
+		var blockNumber hexutil.Uint64
+		require.NoError(t, client.Client().Call(&blockNumber, "eth_blockNumber"))
+		assert.EqualValues(t, 3, blockNumber)
+
+		// a request which was not recorded fails
+		_, err := client.ChainID(context.Background())
+		assert.ErrorContains(t, err, "no fixture for eth_chainId")
+	})
+}
+
+// fatalTB records the failure of a test instead of failing it, Fatalf stops the goroutine like testing.T does
+type fatalTB struct {
+	testing.TB
+	failure string
+}
+
+func (tb *fatalTB) Helper() {}
+
+func (tb *fatalTB) Fatalf(format string, args ...any) {
+	tb.failure = fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+func TestDial_FailsWithoutFixture(t *testing.T) {
+	tb := &fatalTB{TB: t}
+	reached := false
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Dial(tb, filepath.Join(t.TempDir(), "missing.json"), "http://localhost:8545")
+		reached = true
+	}()
+	<-done
+
+	assert.False(t, reached)
+	assert.Contains(t, tb.failure, "no fixture at")
+	assert.Contains(t, tb.failure, RecordEnv)
+}
@@ -0,0 +1,94 @@
+// Package ethrpctest records the JSON-RPC traffic of pool trackers and list updaters with a node into fixture files
+// and replays them, so their tests run offline and deterministically.
+package ethrpctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Fixture is the JSON-RPC traffic recorded with a node, the responses to the same request are replayed in the order
+// they were recorded
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the response of the node, eth_call of the multicall contract, eth_getLogs or any other
+// method alike
+type Interaction struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is the error object of a JSON-RPC response
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// LoadFixture reads the fixture at path
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+
+	return &fixture, nil
+}
+
+// Save writes the fixture at path
+func (f *Fixture) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// requestKey identifies the requests of the same method with the same params
+func requestKey(method string, params json.RawMessage) string {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, params); err != nil {
+		return method + string(params)
+	}
+
+	return method + compacted.String()
+}
+
+// jsonrpcMessage is a JSON-RPC request or response
+type jsonrpcMessage struct {
+	Version string          `json:"jsonrpc,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// parseMessages parses a JSON-RPC body holding a message or a batch of them
+func parseMessages(body []byte) ([]jsonrpcMessage, bool, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var messages []jsonrpcMessage
+		err := json.Unmarshal(body, &messages)
+		return messages, true, err
+	}
+
+	var message jsonrpcMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return nil, false, err
+	}
+
+	return []jsonrpcMessage{message}, false, nil
+}
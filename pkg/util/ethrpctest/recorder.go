@@ -0,0 +1,99 @@
+package ethrpctest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Recorder is an http.RoundTripper which forwards the JSON-RPC requests to the node and records them with their
+// responses
+type Recorder struct {
+	transport http.RoundTripper
+
+	mu      sync.Mutex
+	fixture Fixture
+}
+
+// NewRecorder returns a Recorder forwarding the requests with transport, http.DefaultTransport when it is nil
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &Recorder{transport: transport}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	if resp.StatusCode == http.StatusOK {
+		r.record(reqBody, respBody)
+	}
+
+	return resp, nil
+}
+
+// Fixture returns the traffic recorded so far
+func (r *Recorder) Fixture() *Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &Fixture{Interactions: append([]Interaction(nil), r.fixture.Interactions...)}
+}
+
+// record matches the responses to the requests by id, the bodies which are not JSON-RPC are not recorded
+func (r *Recorder) record(reqBody, respBody []byte) {
+	requests, _, err := parseMessages(reqBody)
+	if err != nil {
+		return
+	}
+	responses, _, err := parseMessages(respBody)
+	if err != nil {
+		return
+	}
+
+	responseByID := make(map[string]jsonrpcMessage, len(responses))
+	for _, response := range responses {
+		responseByID[string(response.ID)] = response
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, request := range requests {
+		response, ok := responseByID[string(request.ID)]
+		if !ok {
+			continue
+		}
+		r.fixture.Interactions = append(r.fixture.Interactions, Interaction{
+			Method: request.Method,
+			Params: request.Params,
+			Result: response.Result,
+			Error:  response.Error,
+		})
+	}
+}
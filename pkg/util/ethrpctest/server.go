@@ -0,0 +1,87 @@
+package ethrpctest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// errCodeNoFixture is the JSON-RPC error code of the requests which were not recorded
+const errCodeNoFixture = -32000
+
+// Server is a JSON-RPC server replaying a fixture, a request which was not recorded gets an error response
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses map[string][]Interaction
+	served    map[string]int
+}
+
+// NewServer starts a Server replaying fixture, the caller should Close it
+func NewServer(fixture *Fixture) *Server {
+	s := &Server{
+		responses: make(map[string][]Interaction, len(fixture.Interactions)),
+		served:    make(map[string]int, len(fixture.Interactions)),
+	}
+	for _, interaction := range fixture.Interactions {
+		key := requestKey(interaction.Method, interaction.Params)
+		s.responses[key] = append(s.responses[key], interaction)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	requests, isBatch, err := parseMessages(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	responses := make([]jsonrpcMessage, 0, len(requests))
+	for _, request := range requests {
+		responses = append(responses, s.reply(request))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if isBatch {
+		_ = json.NewEncoder(w).Encode(responses)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(responses[0])
+}
+
+// reply returns the next recorded response to the request, the last one is repeated once they are all served
+func (s *Server) reply(request jsonrpcMessage) jsonrpcMessage {
+	response := jsonrpcMessage{Version: "2.0", ID: request.ID}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := requestKey(request.Method, request.Params)
+	interactions := s.responses[key]
+	if len(interactions) == 0 {
+		response.Error = &RPCError{
+			Code:    errCodeNoFixture,
+			Message: fmt.Sprintf("ethrpctest: no fixture for %s %s", request.Method, request.Params),
+		}
+		return response
+	}
+
+	idx := min(s.served[key], len(interactions)-1)
+	s.served[key]++
+	response.Result, response.Error = interactions[idx].Result, interactions[idx].Error
+
+	return response
+}
@@ -65,14 +65,21 @@ var (
 	ExchangePancakeStable Exchange = "pancake-stable"
 
 	ExchangeUniSwapV3        Exchange = "uniswapv3"
+	ExchangeUniSwapV4        Exchange = "uniswapv4"
 	ExchangeKyberswapElastic Exchange = "kyberswap-elastic"
 
+	ExchangeMaverickV2 Exchange = "maverick-v2"
+
+	ExchangeAmbient Exchange = "ambient"
+
 	ExchangeBalancer   Exchange = "balancer"
 	ExchangeBeethovenX Exchange = "beethovenx"
 
-	ExchangeDodo Exchange = "dodo"
+	ExchangeDodo   Exchange = "dodo"
+	ExchangeDodoV3 Exchange = "dodo-v3"
 
 	ExchangeGMX       Exchange = "gmx"
+	ExchangeGMXV2     Exchange = "gmx-v2"
 	ExchangeMadMex    Exchange = "madmex"
 	ExchangeMetavault Exchange = "metavault"
 
@@ -82,6 +89,8 @@ var (
 
 	ExchangeMakerLido Exchange = "lido"
 
+	ExchangeERC4626 Exchange = "erc4626"
+
 	ExchangeDMM             Exchange = "dmm"
 	ExchangeKyberSwap       Exchange = "kyberswap"
 	ExchangeKyberSwapStatic Exchange = "kyberswap-static"
@@ -93,9 +102,16 @@ var (
 	ExchangeVelocore  Exchange = "velocore"
 	ExchangePearl     Exchange = "pearl"
 
+	ExchangeVelodromeSlipstream Exchange = "velodrome-slipstream"
+	ExchangeAerodromeSlipstream Exchange = "aerodrome-slipstream"
+	ExchangeQuickSwapV4         Exchange = "quickswap-v4"
+
 	ExchangePlatypus Exchange = "platypus"
 
 	ExchangeKyberSwapLimitOrder Exchange = "kyberswap-limit-order"
+
+	ExchangeKyberPMM Exchange = "kyber-pmm"
+	ExchangeHashflow Exchange = "hashflow"
 )
 
 var AMMSourceSet = map[Exchange]struct{}{
@@ -158,16 +174,22 @@ var AMMSourceSet = map[Exchange]struct{}{
 	ExchangeEllipsis:            {},
 	ExchangePancakeStable:       {},
 	ExchangeUniSwapV3:           {},
+	ExchangeUniSwapV4:           {},
 	ExchangeKyberswapElastic:    {},
+	ExchangeMaverickV2:          {},
+	ExchangeAmbient:             {},
 	ExchangeBalancer:            {},
 	ExchangeBeethovenX:          {},
 	ExchangeDodo:                {},
+	ExchangeDodoV3:              {},
 	ExchangeGMX:                 {},
+	ExchangeGMXV2:               {},
 	ExchangeMadMex:              {},
 	ExchangeMetavault:           {},
 	ExchangeSynthetix:           {},
 	ExchangeMakerPSM:            {},
 	ExchangeMakerLido:           {},
+	ExchangeERC4626:             {},
 	ExchangeDMM:                 {},
 	ExchangeKyberSwap:           {},
 	ExchangeKyberSwapStatic:     {},
@@ -177,6 +199,9 @@ var AMMSourceSet = map[Exchange]struct{}{
 	ExchangeChronos:             {},
 	ExchangeRamses:              {},
 	ExchangeVelocore:            {},
+	ExchangeVelodromeSlipstream: {},
+	ExchangeAerodromeSlipstream: {},
+	ExchangeQuickSwapV4:         {},
 	ExchangePlatypus:            {},
 	ExchangeKyberSwapLimitOrder: {},
 }
@@ -186,3 +211,16 @@ func IsAMMSource(exchange Exchange) bool {
 
 	return contained
 }
+
+// RFQSourceSet contains the sources whose swaps need a firm quote from the maker before they are built
+var RFQSourceSet = map[Exchange]struct{}{
+	ExchangeKyberSwapLimitOrder: {},
+	ExchangeKyberPMM:            {},
+	ExchangeHashflow:            {},
+}
+
+func IsRFQSource(exchange Exchange) bool {
+	_, contained := RFQSourceSet[exchange]
+
+	return contained
+}